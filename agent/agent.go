@@ -44,6 +44,10 @@ const (
 	// Time to wait for the entity config from agentd before sending the first
 	// keepalive
 	entityConfigGracePeriod = 10 * time.Second
+
+	// Maximum size of the payloads of the messages packed in a batch message,
+	// beyond which no more messages are added to the batch
+	maxBatchPayloadSize = 1 << 20
 )
 
 const (
@@ -140,6 +144,7 @@ type Agent struct {
 	api                *http.Server
 	assetGetter        asset.Getter
 	backendSelector    BackendSelector
+	capabilities       transport.Capabilities
 	config             *Config
 	connected          bool
 	connectedMu        sync.RWMutex
@@ -153,12 +158,14 @@ type Agent struct {
 	inProgress         map[string]*corev2.CheckConfig
 	inProgressMu       *sync.Mutex
 	localEntityConfig  *corev3.EntityConfig
+	localScheduler     *localScheduler
 	statsdServer       StatsdServer
 	sendq              chan *transport.Message
 	systemInfo         *corev2.System
 	systemInfoMu       sync.RWMutex
 	wg                 sync.WaitGroup
 	apiQueue           queue
+	outbox             *outbox
 	marshal            MarshalFunc
 	unmarshal          UnmarshalFunc
 	sequencesMu        sync.Mutex
@@ -185,8 +192,24 @@ func NewAgentContext(ctx context.Context, config *Config) (*Agent, error) {
 	if to := config.KeepaliveWarningTimeout; to > 0 && to <= config.KeepaliveInterval {
 		return nil, errors.New("keepalive warning timeout must be greater than keepalive interval")
 	}
+	switch config.BackendCompression {
+	case "", BackendCompressionNone, BackendCompressionDeflate, BackendCompressionZstd:
+	default:
+		return nil, fmt.Errorf(
+			"invalid backend compression %q, must be one of %s, %s or %s",
+			config.BackendCompression, BackendCompressionNone, BackendCompressionDeflate, BackendCompressionZstd,
+		)
+	}
+	sendqSize := 10
+	if config.BackendBatchSize > sendqSize {
+		sendqSize = config.BackendBatchSize
+	}
+	backendSelector, err := NewBackendSelector(config.BackendSelection, config.BackendURLs, config.AgentName, config.BackendProbeInterval, config.TLS)
+	if err != nil {
+		return nil, err
+	}
 	agent := &Agent{
-		backendSelector:  &RandomBackendSelector{Backends: config.BackendURLs},
+		backendSelector:  backendSelector,
 		connected:        false,
 		config:           config,
 		executor:         command.NewExecutor(),
@@ -194,7 +217,7 @@ func NewAgentContext(ctx context.Context, config *Config) (*Agent, error) {
 		entityConfigCh:   make(chan struct{}),
 		inProgress:       make(map[string]*corev2.CheckConfig),
 		inProgressMu:     &sync.Mutex{},
-		sendq:            make(chan *transport.Message, 10),
+		sendq:            make(chan *transport.Message, sendqSize),
 		systemInfo:       &corev2.System{},
 		unmarshal:        UnmarshalJSON,
 		marshal:          MarshalJSON,
@@ -203,6 +226,14 @@ func NewAgentContext(ctx context.Context, config *Config) (*Agent, error) {
 		maxSessionLength: config.MaxSessionLength,
 	}
 
+	if config.Processes.Enable {
+		filter := process.Filter{Allow: config.Processes.Allow, Deny: config.Processes.Deny}
+		if err := filter.Validate(); err != nil {
+			return nil, err
+		}
+		agent.ProcessGetter = process.NewGetter(filter, config.Processes.Cmdline)
+	}
+
 	agent.statsdServer = NewStatsdServer(agent)
 	agent.handler.AddHandler(transport.MessageTypeEntityConfig, agent.handleEntityConfig)
 
@@ -214,11 +245,25 @@ func NewAgentContext(ctx context.Context, config *Config) (*Agent, error) {
 	if err := systemInfoCtx.Err(); err != nil {
 		logger.WithError(err).Error("couldn't refresh all system information within deadline")
 	}
-	var err error
 	agent.apiQueue, err = newQueue(config.CacheDir)
 	if err != nil {
 		return nil, fmt.Errorf("error creating agent: %s", err)
 	}
+	if config.Outbox != nil && !config.Outbox.Disable && config.CacheDir != os.DevNull {
+		agent.outbox, err = newOutbox(config.CacheDir, config.Outbox)
+		if err != nil {
+			return nil, fmt.Errorf("error creating agent: %s", err)
+		}
+	}
+
+	// The events of the checks executed while disconnected are kept in the
+	// outbox, so the local scheduler requires it
+	if config.LocalScheduler != nil && !config.LocalScheduler.Disable && agent.outbox != nil {
+		agent.localScheduler, err = newLocalScheduler(config.CacheDir, config.LocalScheduler)
+		if err != nil {
+			return nil, fmt.Errorf("error creating agent: %s", err)
+		}
+	}
 
 	allowList, err := readAllowList(config.AllowList, ioutil.ReadFile)
 	if err != nil {
@@ -236,6 +281,9 @@ func NewAgentContext(ctx context.Context, config *Config) (*Agent, error) {
 }
 
 func (a *Agent) sendMessage(msg *transport.Message) {
+	if a.queueMessage(msg) {
+		return
+	}
 	logger.WithFields(logrus.Fields{
 		"type":         msg.Type,
 		"content_type": a.contentType,
@@ -306,6 +354,22 @@ func (a *Agent) buildTransportHeaderMap() http.Header {
 	}
 	header.Set(transport.HeaderKeySubscriptions, strings.Join(a.config.Subscriptions, ","))
 
+	// Request the optional capabilities of the protocol, which are only used
+	// if the backend supports them
+	capabilities := transport.Capabilities{}
+	if a.config.BackendBatchSize > 1 {
+		capabilities[transport.CapabilityBatch] = true
+	}
+	switch a.config.BackendCompression {
+	case BackendCompressionDeflate:
+		capabilities[transport.CapabilityDeflate] = true
+	case BackendCompressionZstd:
+		capabilities[transport.CapabilityZstd] = true
+	}
+	if len(capabilities) > 0 {
+		header.Set(transport.HeaderKeyCapabilities, capabilities.String())
+	}
+
 	return header
 }
 
@@ -326,6 +390,16 @@ func (a *Agent) Run(ctx context.Context) error {
 		if err := a.apiQueue.Close(); err != nil {
 			logger.WithError(err).Error("error closing API queue")
 		}
+		if a.outbox != nil {
+			if err := a.outbox.Close(); err != nil {
+				logger.WithError(err).Error("error closing outbox")
+			}
+		}
+		if a.localScheduler != nil {
+			if err := a.localScheduler.Close(); err != nil {
+				logger.WithError(err).Error("error closing local scheduler")
+			}
+		}
 	}()
 	defer cancel()
 	a.header = a.buildTransportHeaderMap()
@@ -383,6 +457,10 @@ func (a *Agent) Run(ctx context.Context) error {
 			limit = rate.Limit(asset.DefaultAssetsRateLimit)
 		}
 		var err error
+		assetManager.SignatureVerifier, err = asset.NewSignatureVerifier(a.config.AssetTrustedKeys, a.config.AssetSignaturePolicy)
+		if err != nil {
+			return err
+		}
 		a.assetGetter, err = assetManager.StartAssetManager(ctx, rate.NewLimiter(limit, a.config.AssetsBurstLimit))
 		if err != nil {
 			return err
@@ -398,12 +476,20 @@ func (a *Agent) Run(ctx context.Context) error {
 		a.StartAPI(ctx)
 	}
 
+	// Start the OTLP/gRPC receiver only if a port is configured for it
+	if !a.config.OTLP.Disable && a.config.OTLP.GRPCPort != 0 {
+		a.StartOTLPGRPC(ctx)
+	}
+
 	// Increment the waitgroup counter here too in case none of the components
 	// above were started, and rely on the system info collector to decrement it
 	// once it exits
 	go a.connectionManager(ctx, cancel)
 	go a.refreshSystemInfoPeriodically(ctx)
 	go a.handleAPIQueue(ctx)
+	if a.localScheduler != nil {
+		go a.runLocalScheduler(ctx)
+	}
 
 	// Wait for context to complete
 	<-ctx.Done()
@@ -454,7 +540,7 @@ func (a *Agent) connectionManager(ctx context.Context, cancel context.CancelFunc
 
 		a.clearAgentEntity()
 
-		conn, err := a.connectWithBackoff(ctx)
+		conn, backendURL, err := a.connectWithBackoff(ctx)
 		if err != nil {
 			if err == ctx.Err() {
 				return
@@ -476,6 +562,7 @@ func (a *Agent) connectionManager(ctx context.Context, cancel context.CancelFunc
 		newConnections.WithLabelValues().Inc()
 
 		go a.enforceMaxSessionLength(connCancel)
+		go a.rebalance(connCtx, connCancel, backendURL)
 		go a.receiveLoop(connCtx, connCancel, conn)
 
 		// Block until we receive an entity config, or the grace period expires,
@@ -530,6 +617,19 @@ func (a *Agent) enforceMaxSessionLength(connCancel context.CancelFunc) {
 	}
 }
 
+// rebalance cancels the connection's context when the backend selector
+// prefers another backend to the current one, forcing the agent to reconnect.
+func (a *Agent) rebalance(ctx context.Context, connCancel context.CancelFunc, backendURL string) {
+	rebalancer, ok := a.backendSelector.(BackendRebalancer)
+	if !ok {
+		return
+	}
+	if rebalancer.Rebalance(ctx, backendURL) {
+		logger.WithField("backend", backendURL).Info("reconnecting to a preferred backend")
+		connCancel()
+	}
+}
+
 func (a *Agent) receiveLoop(ctx context.Context, cancel context.CancelFunc, conn transport.Transport) {
 	defer cancel()
 	for {
@@ -582,6 +682,13 @@ func (a *Agent) sendLoop(ctx context.Context, cancel context.CancelFunc, conn tr
 		logger.WithError(err).Error("error sending message over websocket")
 		return err
 	}
+	// Replay the events of the outbox, if enabled. The replay channel is nil
+	// otherwise, and never ready.
+	var replay chan *outboxEntry
+	if a.outbox != nil {
+		replay = make(chan *outboxEntry)
+		go a.replayOutbox(ctx, replay)
+	}
 	for {
 		select {
 		case <-ctx.Done():
@@ -591,12 +698,25 @@ func (a *Agent) sendLoop(ctx context.Context, cancel context.CancelFunc, conn tr
 			}
 			return nil
 		case msg := <-a.sendq:
+			msgs := a.nextBatch(msg)
+			if len(msgs) > 1 {
+				msg = transport.NewBatchMessage(msgs)
+			}
 			if err := conn.Send(msg); err != nil {
-				messagesDropped.WithLabelValues().Inc()
+				for _, msg := range msgs {
+					if !a.requeueMessage(msg) {
+						messagesDropped.WithLabelValues().Inc()
+					}
+				}
+				logger.WithError(err).Error("error sending message over websocket")
+				return err
+			}
+			messagesSent.WithLabelValues().Add(float64(len(msgs)))
+		case entry := <-replay:
+			if err := a.sendOutboxEntry(conn, entry); err != nil {
 				logger.WithError(err).Error("error sending message over websocket")
 				return err
 			}
-			messagesSent.WithLabelValues().Inc()
 		case <-keepalive.C:
 			if err := conn.Send(a.newKeepalive()); err != nil {
 				messagesDropped.WithLabelValues().Inc()
@@ -608,6 +728,28 @@ func (a *Agent) sendLoop(ctx context.Context, cancel context.CancelFunc, conn tr
 	}
 }
 
+// nextBatch returns the message along with the messages queued after it, up
+// to the batch size, if the backend supports batch messages. It doesn't wait
+// for more messages to be queued, so the batches only grow when the agent
+// queues messages faster than it sends them.
+func (a *Agent) nextBatch(msg *transport.Message) []*transport.Message {
+	msgs := []*transport.Message{msg}
+	if !a.capabilities.Has(transport.CapabilityBatch) {
+		return msgs
+	}
+	size := len(msg.Payload)
+	for len(msgs) < a.config.BackendBatchSize && size < maxBatchPayloadSize {
+		select {
+		case msg := <-a.sendq:
+			msgs = append(msgs, msg)
+			size += len(msg.Payload)
+		default:
+			return msgs
+		}
+	}
+	return msgs
+}
+
 func (a *Agent) nextSequence(check string) int64 {
 	a.sequencesMu.Lock()
 	defer a.sequencesMu.Unlock()
@@ -722,8 +864,11 @@ func (a *Agent) StartStatsd(ctx context.Context) {
 	}()
 }
 
-func (a *Agent) connectWithBackoff(ctx context.Context) (transport.Transport, error) {
-	var conn transport.Transport
+func (a *Agent) connectWithBackoff(ctx context.Context) (transport.Transport, string, error) {
+	var (
+		conn       transport.Transport
+		connectURL string
+	)
 
 	backoff := retry.ExponentialBackoff{
 		InitialDelayInterval: a.config.RetryMin,
@@ -759,6 +904,10 @@ func (a *Agent) connectWithBackoff(ctx context.Context) (transport.Transport, er
 		logger.Info("successfully connected")
 
 		conn = c
+		connectURL = backendURL
+		if observer, ok := a.backendSelector.(BackendObserver); ok {
+			observer.Connected(backendURL)
+		}
 
 		logger.WithField("header", fmt.Sprintf("Accept: %s", respHeader["Accept"])).Debug("received header")
 		if utilstrings.InArray(ProtobufSerializationHeader, respHeader["Accept"]) {
@@ -775,10 +924,13 @@ func (a *Agent) connectWithBackoff(ctx context.Context) (transport.Transport, er
 		a.header.Set("Content-Type", a.contentType)
 		logger.WithField("header", fmt.Sprintf("Content-Type: %s", a.contentType)).Debug("setting header")
 
+		a.capabilities = transport.ParseCapabilities(a.header).Intersect(transport.ParseCapabilities(respHeader))
+		logger.WithField("capabilities", a.capabilities.String()).Debug("negotiated capabilities")
+
 		return true, nil
 	})
 
-	return conn, err
+	return conn, connectURL, err
 }
 
 // GracefulShutdown listens for the SIGINT & SIGTERM signals and cancel the
@@ -299,6 +299,57 @@ func TestInvalidKeepaliveTimeout(t *testing.T) {
 	}
 }
 
+func TestInvalidBackendCompression(t *testing.T) {
+	cfg, cleanup := FixtureConfig()
+	defer cleanup()
+	cfg.BackendCompression = "gzip"
+	if _, err := NewAgent(cfg); err == nil {
+		t.Error("expected non-nil error")
+	}
+	cfg.BackendCompression = BackendCompressionZstd
+	if _, err := NewAgent(cfg); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNextBatch(t *testing.T) {
+	cfg, cleanup := FixtureConfig()
+	defer cleanup()
+	cfg.BackendBatchSize = 3
+	ta, err := NewAgent(cfg)
+	require.NoError(t, err)
+
+	for i := 0; i < 5; i++ {
+		ta.sendq <- transport.NewMessage(transport.MessageTypeEvent, []byte(fmt.Sprint(i)))
+	}
+
+	// the messages are sent one by one if the backend doesn't support batches
+	assert.Len(t, ta.nextBatch(<-ta.sendq), 1)
+
+	ta.capabilities = transport.NewCapabilities(transport.CapabilityBatch)
+	batch := ta.nextBatch(<-ta.sendq)
+	require.Len(t, batch, 3)
+	assert.Equal(t, "1", string(batch[0].Payload))
+	assert.Equal(t, "3", string(batch[2].Payload))
+
+	// the batch doesn't wait for more messages
+	assert.Len(t, ta.nextBatch(<-ta.sendq), 1)
+}
+
+func TestInvalidProcessesFilter(t *testing.T) {
+	cfg, cleanup := FixtureConfig()
+	defer cleanup()
+	cfg.Processes.Enable = true
+	cfg.Processes.Deny = []string{"[a"}
+	if _, err := NewAgent(cfg); err == nil {
+		t.Error("expected non-nil error")
+	}
+	cfg.Processes.Enable = false
+	if _, err := NewAgent(cfg); err != nil {
+		t.Fatal(err)
+	}
+}
+
 // TestConnectionManager validates the connection manager reconnects after a
 // connection is closed. It also validates that it doesn't try to reconnect after
 // the shutdown process is started.
@@ -13,6 +13,8 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	v2 "github.com/sensu/core/v2"
 	"github.com/sensu/lasr"
+	"github.com/sensu/sensu-go/agent/otlp"
+	"github.com/sensu/sensu-go/process"
 	"github.com/sensu/sensu-go/transport"
 	"github.com/sensu/sensu-go/version"
 	"golang.org/x/time/rate"
@@ -47,8 +49,15 @@ func newServer(a *Agent) *http.Server {
 func registerRoutes(a *Agent, r *mux.Router) {
 	r.HandleFunc("/events", addEvent(a)).Methods(http.MethodPost)
 	r.HandleFunc("/healthz", healthz(a.Connected)).Methods(http.MethodGet)
+	r.HandleFunc("/outbox", outboxShow(a)).Methods(http.MethodGet)
 	r.HandleFunc("/version", versionShow()).Methods(http.MethodGet)
 	r.Handle("/metrics", promhttp.Handler())
+	if a.config.Processes.Enable {
+		r.HandleFunc("/processes", processesShow(a)).Methods(http.MethodGet)
+	}
+	if !a.config.OTLP.Disable {
+		r.Handle(otlp.MetricsPath, otlp.NewHTTPHandler(a.exportOTLPMetrics)).Methods(http.MethodPost)
+	}
 }
 
 // healthz returns an OK status if the agent is up and connected to a backend.
@@ -64,6 +73,21 @@ func healthz(connected func() bool) http.HandlerFunc {
 	}
 }
 
+// processesShow returns the details of the local processes found by the last
+// refresh of the system information.
+func processesShow(a *Agent) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		processes := []*process.Info{}
+		if lister, ok := a.ProcessGetter.(process.Lister); ok && lister.Processes() != nil {
+			processes = lister.Processes()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(processes); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
 // sensuVersion returns the version of Sensu
 func versionShow() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
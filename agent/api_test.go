@@ -2,6 +2,7 @@ package agent
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/gorilla/mux"
 	v2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/process"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -111,6 +113,58 @@ func TestHealthz(t *testing.T) {
 	}
 }
 
+type mockProcessLister struct {
+	processes []*process.Info
+}
+
+func (m mockProcessLister) Get(context.Context) ([]*v2.Process, error) {
+	return nil, nil
+}
+
+func (m mockProcessLister) Processes() []*process.Info {
+	return m.processes
+}
+
+func TestProcesses(t *testing.T) {
+	config, cleanup := FixtureConfig()
+	defer cleanup()
+	agent, err := NewAgent(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The endpoint only exists when the discovery is enabled
+	router := mux.NewRouter()
+	registerRoutes(agent, router)
+	r, err := http.NewRequest(http.MethodGet, "/processes", nil)
+	assert.NoError(t, err)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	config.Processes.Enable = true
+	router = mux.NewRouter()
+	registerRoutes(agent, router)
+	agent.ProcessGetter = process.NoopProcessGetter{}
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, "[]", w.Body.String())
+
+	agent.ProcessGetter = mockProcessLister{processes: []*process.Info{
+		{PID: 42, PPID: 1, Name: "nginx", State: "sleeping"},
+	}}
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var processes []*process.Info
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &processes))
+	if assert.Len(t, processes, 1) {
+		assert.Equal(t, int32(42), processes[0].PID)
+		assert.Equal(t, "nginx", processes[0].Name)
+	}
+}
+
 func TestVersion(t *testing.T) {
 	var (
 		versionResponse = `{"version":""}`
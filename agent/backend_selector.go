@@ -1,8 +1,22 @@
 package agent
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"io/ioutil"
 	"math/rand"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
 	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sirupsen/logrus"
 )
 
 // A BackendSelector is repsonsible for selecting an appropriate backend from
@@ -44,3 +58,392 @@ func (b *RandomBackendSelector) Select() string {
 
 	return b.Backends[next]
 }
+
+const (
+	// BackendSelectionRandom selects the backends in a random order.
+	BackendSelectionRandom = "random"
+
+	// BackendSelectionPriority selects the backends in the order of the list
+	// of backends, and fails back to the preferred backends once they recover.
+	BackendSelectionPriority = "priority"
+
+	// BackendSelectionLatency selects the healthy backend with the lowest
+	// round-trip time.
+	BackendSelectionLatency = "latency"
+
+	// BackendSelectionSticky selects the backends in an order specific to the
+	// agent, which spreads the agents evenly across the backends, and fails
+	// back to the first backend of the order once it recovers.
+	BackendSelectionSticky = "sticky"
+
+	// DefaultBackendProbeInterval is the default interval at which the
+	// backends are probed, to fail back or to rebalance.
+	DefaultBackendProbeInterval = time.Minute
+
+	// backendProbeTimeout is the maximum duration of a probe of a backend.
+	backendProbeTimeout = 5 * time.Second
+
+	// backendFailbackProbes is the number of consecutive successful probes
+	// of a preferred backend after which the agent fails back to it.
+	backendFailbackProbes = 3
+
+	// latencyRebalanceRatio is the ratio of the round-trip time of the
+	// current backend under which another backend is preferred.
+	latencyRebalanceRatio = 0.5
+
+	// latencyWeight is the weight of the last probe in the moving average of
+	// the round-trip time to a backend.
+	latencyWeight = 0.3
+)
+
+// A BackendObserver is a BackendSelector that is notified when the agent
+// connects to the backends it selects.
+type BackendObserver interface {
+	// Connected is called when the agent connects to the backend.
+	Connected(backend string)
+}
+
+// A BackendRebalancer is a BackendSelector that can ask the agent to
+// reconnect, to a backend that it prefers to the current one.
+type BackendRebalancer interface {
+	// Rebalance blocks until the agent should reconnect, in which case it
+	// returns true, or until ctx is done.
+	Rebalance(ctx context.Context, current string) bool
+}
+
+// A BackendProber probes a backend, and returns the round-trip time to it or
+// an error if the backend is unreachable.
+type BackendProber func(ctx context.Context, backend string) (time.Duration, error)
+
+// NewBackendSelector returns the backend selector of the given selection
+// strategy, that probes the health of the backends every interval, with the
+// given TLS options.
+func NewBackendSelector(selection string, backends []string, agentName string, interval time.Duration, tlsOpts *corev2.TLSOptions) (BackendSelector, error) {
+	if interval <= 0 {
+		interval = DefaultBackendProbeInterval
+	}
+	switch selection {
+	case "", BackendSelectionRandom:
+		return &RandomBackendSelector{Backends: backends}, nil
+	case BackendSelectionPriority, BackendSelectionLatency, BackendSelectionSticky:
+	default:
+		return nil, fmt.Errorf(
+			"invalid backend selection %q, must be one of %s, %s, %s or %s",
+			selection, BackendSelectionRandom, BackendSelectionPriority, BackendSelectionLatency, BackendSelectionSticky,
+		)
+	}
+	probe, err := NewBackendHealthProber(tlsOpts)
+	if err != nil {
+		return nil, err
+	}
+	switch selection {
+	case BackendSelectionPriority:
+		return &PriorityBackendSelector{Backends: backends, Probe: probe, Interval: interval}, nil
+	case BackendSelectionLatency:
+		return &LatencyBackendSelector{Backends: backends, Probe: probe, Interval: interval}, nil
+	default:
+		return NewStickyBackendSelector(agentName, backends, probe, interval), nil
+	}
+}
+
+// NewBackendHealthProber returns a prober that checks the health endpoint of
+// the backends, with the given TLS options. A backend is healthy if its
+// health endpoint responds, and the postgres store it uses, if any, is
+// healthy.
+func NewBackendHealthProber(tlsOpts *corev2.TLSOptions) (BackendProber, error) {
+	transport := &http.Transport{Proxy: http.ProxyFromEnvironment}
+	if tlsOpts != nil {
+		tlsConfig, err := tlsOpts.ToClientTLSConfig()
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+	client := &http.Client{Transport: transport, Timeout: backendProbeTimeout}
+	return func(ctx context.Context, backend string) (time.Duration, error) {
+		return checkBackendHealth(ctx, client, backend)
+	}, nil
+}
+
+func checkBackendHealth(ctx context.Context, client *http.Client, backend string) (time.Duration, error) {
+	u, err := url.Parse(backend)
+	if err != nil {
+		return 0, err
+	}
+	switch u.Scheme {
+	case "ws":
+		u.Scheme = "http"
+	case "wss":
+		u.Scheme = "https"
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/health"
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return 0, err
+	}
+	start := time.Now()
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	rtt := time.Since(start)
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("health check failed with status %d", resp.StatusCode)
+	}
+	var health corev2.HealthResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1024*1024)).Decode(&health); err != nil {
+		return 0, fmt.Errorf("invalid health check response: %s", err)
+	}
+	// drain the body so that the connection can be reused
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	for _, pg := range health.PostgresHealth {
+		if pg.Active && !pg.Healthy {
+			return 0, fmt.Errorf("postgres store %s is unhealthy", pg.Name)
+		}
+	}
+	return rtt, nil
+}
+
+// A PriorityBackendSelector selects the backends in the order of the list of
+// backends, starting over from the first one after a successful connection.
+// While connected to another backend than the first one, it probes the
+// backends that it prefers, and asks the agent to reconnect once one of them
+// has recovered, i.e. it was healthy at several consecutive probes.
+type PriorityBackendSelector struct {
+	// Backends is the list of backend URLs, by order of preference.
+	Backends []string
+
+	// Probe probes the preferred backends.
+	Probe BackendProber
+
+	// Interval is the interval at which the preferred backends are probed.
+	Interval time.Duration
+
+	mu   sync.Mutex
+	next int
+}
+
+// Select returns the next backend by order of preference.
+func (b *PriorityBackendSelector) Select() string {
+	if len(b.Backends) == 0 {
+		return ""
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	backend := b.Backends[b.next%len(b.Backends)]
+	b.next = (b.next + 1) % len(b.Backends)
+	return backend
+}
+
+// Connected starts the selection over from the preferred backend.
+func (b *PriorityBackendSelector) Connected(backend string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.next = 0
+}
+
+// Rebalance blocks until a backend preferred to the current one is healthy
+// at backendFailbackProbes consecutive probes.
+func (b *PriorityBackendSelector) Rebalance(ctx context.Context, current string) bool {
+	var preferred []string
+	for _, backend := range b.Backends {
+		if backend == current {
+			break
+		}
+		preferred = append(preferred, backend)
+	}
+	if len(preferred) == 0 || len(preferred) == len(b.Backends) {
+		<-ctx.Done()
+		return false
+	}
+
+	successes := make(map[string]int, len(preferred))
+	ticker := time.NewTicker(b.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+			for _, backend := range preferred {
+				if _, err := b.Probe(ctx, backend); err != nil {
+					successes[backend] = 0
+					continue
+				}
+				successes[backend]++
+				if successes[backend] >= backendFailbackProbes {
+					logger.WithField("backend", backend).Info("preferred backend has recovered")
+					return true
+				}
+			}
+		}
+	}
+}
+
+// NewStickyBackendSelector returns a selector of the backends in an order
+// specific to the agent. The order is that of rendezvous hashing, so the
+// agents are spread evenly across the backends, and only the agents that
+// prefer a new backend move to it when backends are added.
+func NewStickyBackendSelector(agentName string, backends []string, probe BackendProber, interval time.Duration) *PriorityBackendSelector {
+	scores := make(map[string]uint64, len(backends))
+	for _, backend := range backends {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(agentName))
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(backend))
+		scores[backend] = h.Sum64()
+	}
+	ordered := append([]string{}, backends...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return scores[ordered[i]] > scores[ordered[j]]
+	})
+	return &PriorityBackendSelector{
+		Backends: ordered,
+		Probe:    probe,
+		Interval: interval,
+	}
+}
+
+// A LatencyBackendSelector selects the reachable backends by order of their
+// round-trip time, and the unreachable backends last. While connected, it
+// probes the backends and asks the agent to reconnect if another backend
+// is much closer than the current one. The round-trip times are the
+// exponentially weighted moving averages of the probes, so that a single
+// slow or fast probe doesn't reorder the backends.
+type LatencyBackendSelector struct {
+	// Backends is the list of backend URLs.
+	Backends []string
+
+	// Probe measures the round-trip time to the backends.
+	Probe BackendProber
+
+	// Interval is the interval at which the backends are probed while
+	// connected.
+	Interval time.Duration
+
+	mu    sync.Mutex
+	queue []string
+
+	rttMu sync.Mutex
+	rtts  map[string]time.Duration
+}
+
+// Select returns the next backend by order of round-trip time. The backends
+// are probed again once they were all selected, or after a connection.
+func (b *LatencyBackendSelector) Select() string {
+	if len(b.Backends) == 0 {
+		return ""
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.queue) == 0 {
+		b.queue = b.rank(context.Background())
+	}
+	backend := b.queue[0]
+	b.queue = b.queue[1:]
+	return backend
+}
+
+// Connected probes the backends again at the next selection.
+func (b *LatencyBackendSelector) Connected(backend string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.queue = nil
+}
+
+// Rebalance blocks until another backend has a round-trip time under half of
+// the round-trip time of the current backend.
+func (b *LatencyBackendSelector) Rebalance(ctx context.Context, current string) bool {
+	if len(b.Backends) < 2 {
+		<-ctx.Done()
+		return false
+	}
+	ticker := time.NewTicker(b.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+			rtts := b.probe(ctx)
+			currentRTT, ok := rtts[current]
+			if !ok {
+				// the connection to the current backend is still up
+				continue
+			}
+			for backend, rtt := range rtts {
+				if backend != current && float64(rtt) < float64(currentRTT)*latencyRebalanceRatio {
+					logger.WithFields(logrus.Fields{
+						"backend":         backend,
+						"rtt":             rtt,
+						"current_backend": current,
+						"current_rtt":     currentRTT,
+					}).Info("closer backend is reachable")
+					return true
+				}
+			}
+		}
+	}
+}
+
+// rank returns the backends by order of round-trip time, the unreachable
+// backends last.
+func (b *LatencyBackendSelector) rank(ctx context.Context) []string {
+	rtts := b.probe(ctx)
+	ranked := append([]string{}, b.Backends...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		rtti, oki := rtts[ranked[i]]
+		rttj, okj := rtts[ranked[j]]
+		if oki != okj {
+			return oki
+		}
+		return rtti < rttj
+	})
+	return ranked
+}
+
+// probe probes the backends concurrently, and returns the average round-trip
+// times of the reachable backends. The average of a backend starts over once
+// it is unreachable.
+func (b *LatencyBackendSelector) probe(ctx context.Context) map[string]time.Duration {
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		rtts = make(map[string]time.Duration, len(b.Backends))
+	)
+	for _, backend := range b.Backends {
+		wg.Add(1)
+		go func(backend string) {
+			defer wg.Done()
+			rtt, err := b.Probe(ctx, backend)
+			if err != nil {
+				logger.WithError(err).WithField("backend", backend).Debug("backend is unreachable")
+				return
+			}
+			mu.Lock()
+			rtts[backend] = rtt
+			mu.Unlock()
+		}(backend)
+	}
+	wg.Wait()
+
+	b.rttMu.Lock()
+	defer b.rttMu.Unlock()
+	if b.rtts == nil {
+		b.rtts = make(map[string]time.Duration, len(b.Backends))
+	}
+	for _, backend := range b.Backends {
+		rtt, ok := rtts[backend]
+		if !ok {
+			delete(b.rtts, backend)
+			continue
+		}
+		if average, ok := b.rtts[backend]; ok {
+			rtt = average + time.Duration(latencyWeight*float64(rtt-average))
+		}
+		b.rtts[backend] = rtt
+		rtts[backend] = rtt
+	}
+	return rtts
+}
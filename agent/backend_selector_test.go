@@ -1,10 +1,21 @@
 package agent
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"sort"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	corev2 "github.com/sensu/core/v2"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestBackendSelector(t *testing.T) {
@@ -27,3 +38,241 @@ func TestEmptyBackendSelector(t *testing.T) {
 	assert.Equal(t, "", selector.Select())
 	assert.Equal(t, "", selector.Select())
 }
+
+// fakeProbe probes backends with fixed round-trip times, the backends without
+// a round-trip time being unreachable.
+type fakeProbe struct {
+	mu   sync.Mutex
+	rtts map[string]time.Duration
+}
+
+func (p *fakeProbe) set(backend string, rtt time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.rtts[backend] = rtt
+}
+
+func (p *fakeProbe) Probe(ctx context.Context, backend string) (time.Duration, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	rtt, ok := p.rtts[backend]
+	if !ok {
+		return 0, errors.New("unreachable")
+	}
+	return rtt, nil
+}
+
+func TestNewBackendSelector(t *testing.T) {
+	backends := []string{"ws://a:8081", "ws://b:8081"}
+	tests := []struct {
+		selection string
+		want      BackendSelector
+		wantErr   bool
+	}{
+		{selection: "", want: &RandomBackendSelector{}},
+		{selection: BackendSelectionRandom, want: &RandomBackendSelector{}},
+		{selection: BackendSelectionPriority, want: &PriorityBackendSelector{}},
+		{selection: BackendSelectionLatency, want: &LatencyBackendSelector{}},
+		{selection: BackendSelectionSticky, want: &PriorityBackendSelector{}},
+		{selection: "nearest", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.selection, func(t *testing.T) {
+			selector, err := NewBackendSelector(tt.selection, backends, "agent", 0, nil)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.IsType(t, tt.want, selector)
+		})
+	}
+}
+
+func TestPriorityBackendSelector(t *testing.T) {
+	selector := &PriorityBackendSelector{Backends: []string{"a", "b", "c"}}
+
+	assert.Equal(t, "a", selector.Select())
+	assert.Equal(t, "b", selector.Select())
+	assert.Equal(t, "c", selector.Select())
+	assert.Equal(t, "a", selector.Select())
+	assert.Equal(t, "b", selector.Select())
+
+	// the selection starts over from the preferred backend once connected
+	selector.Connected("b")
+	assert.Equal(t, "a", selector.Select())
+}
+
+func TestEmptyPriorityBackendSelector(t *testing.T) {
+	selector := &PriorityBackendSelector{}
+	assert.Equal(t, "", selector.Select())
+}
+
+func TestPriorityBackendSelectorRebalance(t *testing.T) {
+	probe := &fakeProbe{rtts: map[string]time.Duration{}}
+	selector := &PriorityBackendSelector{
+		Backends: []string{"a", "b", "c"},
+		Probe:    probe.Probe,
+		Interval: time.Millisecond,
+	}
+
+	// no failback from the preferred backend
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.False(t, selector.Rebalance(ctx, "a"))
+
+	// no failback while the preferred backends are unreachable
+	probe.set("c", time.Millisecond)
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.False(t, selector.Rebalance(ctx, "b"))
+
+	// no failback to a preferred backend that is flapping
+	var probes int
+	selector.Probe = func(ctx context.Context, backend string) (time.Duration, error) {
+		if probes++; probes%backendFailbackProbes == 0 {
+			return 0, errors.New("unreachable")
+		}
+		return time.Millisecond, nil
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.False(t, selector.Rebalance(ctx, "b"))
+
+	// failback once a preferred backend recovers
+	selector.Probe = probe.Probe
+	probe.set("a", time.Millisecond)
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.True(t, selector.Rebalance(ctx, "c"))
+}
+
+func TestStickyBackendSelector(t *testing.T) {
+	backends := []string{"ws://a:8081", "ws://b:8081", "ws://c:8081"}
+
+	// the order is specific to the agent, and doesn't depend on the order of
+	// the backends
+	selector := NewStickyBackendSelector("agent", backends, nil, time.Minute)
+	reversed := NewStickyBackendSelector("agent", []string{backends[2], backends[1], backends[0]}, nil, time.Minute)
+	assert.Equal(t, selector.Backends, reversed.Backends)
+	assert.ElementsMatch(t, backends, selector.Backends)
+
+	// the agents are spread across the backends
+	counts := map[string]int{}
+	for i := 0; i < 300; i++ {
+		selector := NewStickyBackendSelector(fmt.Sprintf("agent-%d", i), backends, nil, time.Minute)
+		counts[selector.Select()]++
+	}
+	for _, backend := range backends {
+		assert.InDelta(t, 100, counts[backend], 40, backend)
+	}
+
+	// only the agents that prefer a new backend move to it
+	added := append(backends, "ws://d:8081")
+	for i := 0; i < 300; i++ {
+		name := fmt.Sprintf("agent-%d", i)
+		before := NewStickyBackendSelector(name, backends, nil, time.Minute).Select()
+		after := NewStickyBackendSelector(name, added, nil, time.Minute).Select()
+		if after != "ws://d:8081" {
+			assert.Equal(t, before, after, name)
+		}
+	}
+}
+
+func TestLatencyBackendSelector(t *testing.T) {
+	probe := &fakeProbe{rtts: map[string]time.Duration{
+		"a": 30 * time.Millisecond,
+		"b": 10 * time.Millisecond,
+		"c": 20 * time.Millisecond,
+	}}
+	selector := &LatencyBackendSelector{
+		Backends: []string{"a", "b", "c", "d"},
+		Probe:    probe.Probe,
+	}
+
+	assert.Equal(t, "b", selector.Select())
+	assert.Equal(t, "c", selector.Select())
+
+	// the backends are probed again once connected, and a single fast probe
+	// doesn't make a backend closer than the others
+	probe.set("a", time.Millisecond)
+	selector.Connected("c")
+	assert.Equal(t, "b", selector.Select())
+
+	// it does once the round-trip time stays low
+	for i := 0; i < 2; i++ {
+		selector.Connected("b")
+		assert.Equal(t, "b", selector.Select())
+	}
+	selector.Connected("b")
+	assert.Equal(t, "a", selector.Select())
+	assert.Equal(t, "b", selector.Select())
+	assert.Equal(t, "c", selector.Select())
+
+	// the unreachable backends are selected last
+	assert.Equal(t, "d", selector.Select())
+}
+
+func TestLatencyBackendSelectorRebalance(t *testing.T) {
+	probe := &fakeProbe{rtts: map[string]time.Duration{
+		"a": 10 * time.Millisecond,
+		"b": 15 * time.Millisecond,
+	}}
+	selector := &LatencyBackendSelector{
+		Backends: []string{"a", "b"},
+		Probe:    probe.Probe,
+		Interval: time.Millisecond,
+	}
+
+	// no rebalancing to a backend that is not much closer
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.False(t, selector.Rebalance(ctx, "b"))
+
+	probe.set("a", 5*time.Millisecond)
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.True(t, selector.Rebalance(ctx, "b"))
+}
+
+func TestBackendHealthProber(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		status = http.StatusOK
+		health = corev2.HealthResponse{}
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.URL.Path != "/health" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(health)
+	}))
+	backend := strings.Replace(server.URL, "http://", "ws://", 1)
+
+	probe, err := NewBackendHealthProber(nil)
+	require.NoError(t, err)
+	_, err = probe(context.Background(), backend)
+	assert.NoError(t, err)
+
+	// the backends whose postgres store is unhealthy are not healthy
+	mu.Lock()
+	health.PostgresHealth = []*corev2.PostgresHealth{{Name: "pg", Active: true}}
+	mu.Unlock()
+	_, err = probe(context.Background(), backend)
+	assert.Error(t, err)
+
+	mu.Lock()
+	health.PostgresHealth = nil
+	status = http.StatusServiceUnavailable
+	mu.Unlock()
+	_, err = probe(context.Background(), backend)
+	assert.Error(t, err)
+
+	server.Close()
+	_, err = probe(context.Background(), backend)
+	assert.Error(t, err)
+}
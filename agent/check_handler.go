@@ -66,6 +66,18 @@ func (a *Agent) handleCheck(ctx context.Context, payload []byte) error {
 		return nil
 	}
 
+	// Keep the request for the local scheduler, and skip it if it was already
+	// executed locally while the agent was disconnected
+	if a.localScheduler != nil {
+		execute, err := a.localScheduler.received(request)
+		if err != nil {
+			logger.WithError(err).Error("error scheduling check locally")
+		} else if !execute {
+			logger.Info("skipping check execution already scheduled locally: ", checkConfig.Name)
+			return nil
+		}
+	}
+
 	logger.Info("scheduling check execution: ", checkConfig.Name)
 
 	entity := a.getAgentEntity()
@@ -185,6 +197,18 @@ func (a *Agent) executeCheck(ctx context.Context, request *corev2.CheckRequest,
 		}
 	}
 
+	// Report the assets installed despite the failure of their signature
+	// verification, which would fail the check with enforced signatures
+	if warnings := assets.SignatureWarnings(); len(warnings) > 0 {
+		logger.WithFields(fields).Warn(strings.Join(warnings, "; "))
+		annotations := make(map[string]string, len(check.Annotations)+1)
+		for k, v := range check.Annotations {
+			annotations[k] = v
+		}
+		annotations[asset.SignatureWarningAnnotation] = strings.Join(warnings, "; ")
+		check.Annotations = annotations
+	}
+
 	// Prepare environment variables
 	var env []string
 	if match && !matchedEntry.EnableEnv {
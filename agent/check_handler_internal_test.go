@@ -8,6 +8,7 @@ import (
 	"time"
 
 	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/asset"
 	"github.com/sensu/sensu-go/command"
 	"github.com/sensu/sensu-go/testing/mockexecutor"
 	"github.com/sensu/sensu-go/token"
@@ -670,3 +671,40 @@ func TestEvaluateOutputMetricThresholds(t *testing.T) {
 		})
 	}
 }
+
+// warningAssetGetter gets the assets installed despite the failure of their
+// signature verification.
+type warningAssetGetter struct{}
+
+func (warningAssetGetter) Get(ctx context.Context, a *corev2.Asset) (*asset.RuntimeAsset, error) {
+	return &asset.RuntimeAsset{Name: a.Name, SHA512: a.Sha512, SignatureWarning: "signature mismatch"}, nil
+}
+
+func TestExecuteCheckAssetSignatureWarning(t *testing.T) {
+	checkConfig := corev2.FixtureCheckConfig("check")
+	request := &corev2.CheckRequest{
+		Assets: []corev2.Asset{*corev2.FixtureAsset("asset")},
+		Config: checkConfig,
+		Issued: time.Now().Unix(),
+	}
+
+	config, cleanup := FixtureConfig()
+	defer cleanup()
+	agent, err := NewAgent(config)
+	require.NoError(t, err)
+	ch := make(chan *transport.Message, 1)
+	agent.sendq = ch
+	agent.assetGetter = warningAssetGetter{}
+	ex := &mockexecutor.MockExecutor{}
+	agent.executor = ex
+	ex.Return(command.FixtureExecutionResponse(0, "ok"), nil)
+
+	// The check is executed, and its event reports the warning
+	agent.executeCheck(context.TODO(), request, agent.getAgentEntity())
+	msg := <-ch
+	event := &corev2.Event{}
+	require.NoError(t, json.Unmarshal(msg.Payload, event))
+	assert.Equal(t, uint32(0), event.Check.Status)
+	assert.Equal(t, "signature mismatch", event.Check.Annotations[asset.SignatureWarningAnnotation])
+	assert.NotContains(t, checkConfig.Annotations, asset.SignatureWarningAnnotation)
+}
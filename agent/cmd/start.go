@@ -41,7 +41,11 @@ const (
 	flagAPIPort                   = "api-port"
 	flagAssetsRateLimit           = "assets-rate-limit"
 	flagAssetsBurstLimit          = "assets-burst-limit"
+	flagAssetSignaturePolicy      = "asset-signature-policy"
+	flagAssetTrustedKeys          = "asset-trusted-keys"
 	flagBackendURL                = "backend-url"
+	flagBackendSelection          = "backend-selection"
+	flagBackendProbeInterval      = "backend-probe-interval"
 	flagCacheDir                  = "cache-dir"
 	flagConfigFile                = "config-file"
 	flagDeregister                = "deregister"
@@ -57,6 +61,21 @@ const (
 	flagKeepaliveCheckAnnotations = "keepalive-check-annotations"
 	flagKeepalivePipelines        = "keepalive-pipelines"
 	flagNamespace                 = "namespace"
+	flagOTLPDisable               = "otlp-disable"
+	flagOTLPEventHandlers         = "otlp-event-handlers"
+	flagOTLPGRPCHost              = "otlp-grpc-host"
+	flagOTLPGRPCPort              = "otlp-grpc-port"
+	flagLocalSchedulerDisable     = "local-scheduler-disable"
+	flagLocalSchedulerMaxAge      = "local-scheduler-max-age"
+	flagOutboxDisable             = "outbox-disable"
+	flagOutboxMaxBytes            = "outbox-max-bytes"
+	flagOutboxMaxAge              = "outbox-max-age"
+	flagOutboxReplayRateLimit     = "outbox-replay-rate-limit"
+	flagOutboxReplayBurstLimit    = "outbox-replay-burst-limit"
+	flagProcessesEnable           = "processes-enable"
+	flagProcessesAllow            = "processes-allow"
+	flagProcessesDeny             = "processes-deny"
+	flagProcessesCmdline          = "processes-cmdline"
 	flagPassword                  = "password"
 	flagRedact                    = "redact"
 	flagStatsdDisable             = "statsd-disable"
@@ -75,6 +94,8 @@ const (
 	flagBackendHandshakeTimeout   = "backend-handshake-timeout"
 	flagBackendHeartbeatInterval  = "backend-heartbeat-interval"
 	flagBackendHeartbeatTimeout   = "backend-heartbeat-timeout"
+	flagBackendBatchSize          = "backend-batch-size"
+	flagBackendCompression        = "backend-compression"
 	flagAgentManagedEntity        = "agent-managed-entity"
 	flagRetryMin                  = "retry-min"
 	flagRetryMax                  = "retry-max"
@@ -114,6 +135,10 @@ func NewAgentConfig(cmd *cobra.Command) (*agent.Config, error) {
 	cfg.API.Port = viper.GetInt(flagAPIPort)
 	cfg.AssetsRateLimit = rate.Limit(viper.GetFloat64(flagAssetsRateLimit))
 	cfg.AssetsBurstLimit = viper.GetInt(flagAssetsBurstLimit)
+	cfg.AssetSignaturePolicy = asset.SignaturePolicy(viper.GetString(flagAssetSignaturePolicy))
+	cfg.AssetTrustedKeys = viper.GetStringSlice(flagAssetTrustedKeys)
+	cfg.BackendSelection = viper.GetString(flagBackendSelection)
+	cfg.BackendProbeInterval = viper.GetDuration(flagBackendProbeInterval)
 	cfg.CacheDir = viper.GetString(flagCacheDir)
 	cfg.Deregister = viper.GetBool(flagDeregister)
 	cfg.DeregistrationHandler = viper.GetString(flagDeregistrationHandler)
@@ -128,7 +153,22 @@ func NewAgentConfig(cmd *cobra.Command) (*agent.Config, error) {
 	cfg.KeepaliveCheckLabels = viper.GetStringMapString(flagKeepaliveCheckLabels)
 	cfg.KeepaliveCheckAnnotations = viper.GetStringMapString(flagKeepaliveCheckAnnotations)
 	cfg.KeepalivePipelines = viper.GetStringSlice(flagKeepalivePipelines)
+	cfg.LocalScheduler.Disable = viper.GetBool(flagLocalSchedulerDisable)
+	cfg.LocalScheduler.MaxAge = viper.GetDuration(flagLocalSchedulerMaxAge)
 	cfg.Namespace = viper.GetString(flagNamespace)
+	cfg.OTLP.Disable = viper.GetBool(flagOTLPDisable)
+	cfg.OTLP.Handlers = viper.GetStringSlice(flagOTLPEventHandlers)
+	cfg.OTLP.GRPCHost = viper.GetString(flagOTLPGRPCHost)
+	cfg.OTLP.GRPCPort = viper.GetInt(flagOTLPGRPCPort)
+	cfg.Outbox.Disable = viper.GetBool(flagOutboxDisable)
+	cfg.Outbox.MaxBytes = viper.GetInt64(flagOutboxMaxBytes)
+	cfg.Outbox.MaxAge = viper.GetDuration(flagOutboxMaxAge)
+	cfg.Outbox.ReplayRateLimit = rate.Limit(viper.GetFloat64(flagOutboxReplayRateLimit))
+	cfg.Outbox.ReplayBurstLimit = viper.GetInt(flagOutboxReplayBurstLimit)
+	cfg.Processes.Enable = viper.GetBool(flagProcessesEnable)
+	cfg.Processes.Allow = viper.GetStringSlice(flagProcessesAllow)
+	cfg.Processes.Deny = viper.GetStringSlice(flagProcessesDeny)
+	cfg.Processes.Cmdline = viper.GetBool(flagProcessesCmdline)
 	cfg.Password = viper.GetString(flagPassword)
 	cfg.StatsdServer.Disable = viper.GetBool(flagStatsdDisable)
 	cfg.StatsdServer.FlushInterval = viper.GetInt(flagStatsdFlushInterval)
@@ -140,6 +180,8 @@ func NewAgentConfig(cmd *cobra.Command) (*agent.Config, error) {
 	cfg.BackendHandshakeTimeout = viper.GetInt(flagBackendHandshakeTimeout)
 	cfg.BackendHeartbeatInterval = viper.GetInt(flagBackendHeartbeatInterval)
 	cfg.BackendHeartbeatTimeout = viper.GetInt(flagBackendHeartbeatTimeout)
+	cfg.BackendBatchSize = viper.GetInt(flagBackendBatchSize)
+	cfg.BackendCompression = viper.GetString(flagBackendCompression)
 	cfg.RetryMin = viper.GetDuration(flagRetryMin)
 	cfg.RetryMax = viper.GetDuration(flagRetryMax)
 	cfg.RetryMultiplier = viper.GetFloat64(flagRetryMultiplier)
@@ -291,6 +333,8 @@ func handleConfig(cmd *cobra.Command, arguments []string) error {
 	viper.SetDefault(flagAPIHost, agent.DefaultAPIHost)
 	viper.SetDefault(flagAPIPort, agent.DefaultAPIPort)
 	viper.SetDefault(flagBackendURL, []string{agent.DefaultBackendURL})
+	viper.SetDefault(flagBackendSelection, agent.BackendSelectionRandom)
+	viper.SetDefault(flagBackendProbeInterval, agent.DefaultBackendProbeInterval)
 	viper.SetDefault(flagCacheDir, path.SystemCacheDir("sensu-agent"))
 	viper.SetDefault(flagDeregister, false)
 	viper.SetDefault(flagDeregistrationHandler, "")
@@ -299,12 +343,29 @@ func handleConfig(cmd *cobra.Command, arguments []string) error {
 	viper.SetDefault(flagDisableAssets, false)
 	viper.SetDefault(flagAssetsRateLimit, asset.DefaultAssetsRateLimit)
 	viper.SetDefault(flagAssetsBurstLimit, asset.DefaultAssetsBurstLimit)
+	viper.SetDefault(flagAssetSignaturePolicy, string(asset.SignaturePolicyEnforce))
+	viper.SetDefault(flagAssetTrustedKeys, []string{})
 	viper.SetDefault(flagEventsRateLimit, agent.DefaultEventsAPIRateLimit)
 	viper.SetDefault(flagEventsBurstLimit, agent.DefaultEventsAPIBurstLimit)
 	viper.SetDefault(flagKeepaliveInterval, agent.DefaultKeepaliveInterval)
 	viper.SetDefault(flagKeepaliveWarningTimeout, corev2.DefaultKeepaliveTimeout)
 	viper.SetDefault(flagKeepaliveCriticalTimeout, 0)
 	viper.SetDefault(flagNamespace, agent.DefaultNamespace)
+	viper.SetDefault(flagOTLPDisable, agent.DefaultOTLPDisable)
+	viper.SetDefault(flagOTLPEventHandlers, []string{})
+	viper.SetDefault(flagOTLPGRPCHost, agent.DefaultOTLPGRPCHost)
+	viper.SetDefault(flagOTLPGRPCPort, agent.DefaultOTLPGRPCPort)
+	viper.SetDefault(flagLocalSchedulerDisable, agent.DefaultLocalSchedulerDisable)
+	viper.SetDefault(flagLocalSchedulerMaxAge, agent.DefaultLocalSchedulerMaxAge)
+	viper.SetDefault(flagOutboxDisable, agent.DefaultOutboxDisable)
+	viper.SetDefault(flagOutboxMaxBytes, agent.DefaultOutboxMaxBytes)
+	viper.SetDefault(flagOutboxMaxAge, agent.DefaultOutboxMaxAge)
+	viper.SetDefault(flagOutboxReplayRateLimit, agent.DefaultOutboxReplayRateLimit)
+	viper.SetDefault(flagOutboxReplayBurstLimit, agent.DefaultOutboxReplayBurstLimit)
+	viper.SetDefault(flagProcessesEnable, agent.DefaultProcessesEnable)
+	viper.SetDefault(flagProcessesAllow, []string{})
+	viper.SetDefault(flagProcessesDeny, []string{})
+	viper.SetDefault(flagProcessesCmdline, agent.DefaultProcessesCmdline)
 	viper.SetDefault(flagPassword, agent.DefaultPassword)
 	viper.SetDefault(flagRedact, corev2.DefaultRedactFields)
 	viper.SetDefault(flagStatsdDisable, agent.DefaultStatsdDisable)
@@ -320,6 +381,8 @@ func handleConfig(cmd *cobra.Command, arguments []string) error {
 	viper.SetDefault(flagBackendHandshakeTimeout, 15)
 	viper.SetDefault(flagBackendHeartbeatInterval, 30)
 	viper.SetDefault(flagBackendHeartbeatTimeout, 45)
+	viper.SetDefault(flagBackendBatchSize, agent.DefaultBackendBatchSize)
+	viper.SetDefault(flagBackendCompression, agent.BackendCompressionNone)
 	viper.SetDefault(flagRetryMin, time.Second)
 	viper.SetDefault(flagRetryMax, 120*time.Second)
 	viper.SetDefault(flagRetryMultiplier, 2.0)
@@ -409,9 +472,26 @@ func flagSet() *pflag.FlagSet {
 	flagSet.Bool(flagDetectCloudProvider, viper.GetBool(flagDetectCloudProvider), "enable cloud provider detection")
 	flagSet.Float64(flagAssetsRateLimit, viper.GetFloat64(flagAssetsRateLimit), "maximum number of assets fetched per second")
 	flagSet.Int(flagAssetsBurstLimit, viper.GetInt(flagAssetsBurstLimit), "asset fetch burst limit")
+	flagSet.String(flagAssetSignaturePolicy, viper.GetString(flagAssetSignaturePolicy), "policy of the assets whose signature can't be verified with the trusted keys, warn or enforce")
+	flagSet.StringSlice(flagAssetTrustedKeys, viper.GetStringSlice(flagAssetTrustedKeys), "comma-delimited list of the files of the public keys trusted to sign the assets, minisign or PEM encoded ECDSA keys. The signatures of the assets are verified when set. This flag can also be invoked multiple times")
 	flagSet.Float64(flagEventsRateLimit, viper.GetFloat64(flagEventsRateLimit), "maximum number of events transmitted to the backend through the /events api")
 	flagSet.Int(flagEventsBurstLimit, viper.GetInt(flagEventsBurstLimit), "/events api burst limit")
 	flagSet.String(flagNamespace, viper.GetString(flagNamespace), "agent namespace")
+	flagSet.Bool(flagOTLPDisable, viper.GetBool(flagOTLPDisable), "disables the OpenTelemetry (OTLP) metrics receiver")
+	flagSet.StringSlice(flagOTLPEventHandlers, viper.GetStringSlice(flagOTLPEventHandlers), "comma-delimited list of event handlers for OpenTelemetry metrics. This flag can also be invoked multiple times")
+	flagSet.String(flagOTLPGRPCHost, viper.GetString(flagOTLPGRPCHost), "address used for the OpenTelemetry (OTLP) gRPC metrics receiver")
+	flagSet.Int(flagOTLPGRPCPort, viper.GetInt(flagOTLPGRPCPort), "port used for the OpenTelemetry (OTLP) gRPC metrics receiver, 0 to disable it")
+	flagSet.Bool(flagLocalSchedulerDisable, viper.GetBool(flagLocalSchedulerDisable), "disables the local scheduling of the last known checks while disconnected from the backend")
+	flagSet.Duration(flagLocalSchedulerMaxAge, viper.GetDuration(flagLocalSchedulerMaxAge), "maximum age of the check requests scheduled locally while disconnected (0 for no limit)")
+	flagSet.Bool(flagOutboxDisable, viper.GetBool(flagOutboxDisable), "disables the outbox, which keeps the events produced while disconnected from the backend")
+	flagSet.Int64(flagOutboxMaxBytes, viper.GetInt64(flagOutboxMaxBytes), "maximum size, in bytes, of the compressed events kept in the outbox (0 for no limit)")
+	flagSet.Duration(flagOutboxMaxAge, viper.GetDuration(flagOutboxMaxAge), "maximum age of the events kept in the outbox (0 for no limit)")
+	flagSet.Float64(flagOutboxReplayRateLimit, viper.GetFloat64(flagOutboxReplayRateLimit), "maximum number of events per second replayed from the outbox once reconnected (0 for no limit)")
+	flagSet.Int(flagOutboxReplayBurstLimit, viper.GetInt(flagOutboxReplayBurstLimit), "outbox replay burst limit")
+	flagSet.Bool(flagProcessesEnable, viper.GetBool(flagProcessesEnable), "enables the discovery of the local processes (Linux only)")
+	flagSet.StringSlice(flagProcessesAllow, viper.GetStringSlice(flagProcessesAllow), "comma-delimited list of glob patterns of the names of the processes to report, all of them when empty. This flag can also be invoked multiple times")
+	flagSet.StringSlice(flagProcessesDeny, viper.GetStringSlice(flagProcessesDeny), "comma-delimited list of glob patterns of the names of the processes to ignore. This flag can also be invoked multiple times")
+	flagSet.Bool(flagProcessesCmdline, viper.GetBool(flagProcessesCmdline), "reports the command lines of the local processes at /processes on the agent API. Command lines often contain credentials")
 	flagSet.String(flagPassword, viper.GetString(flagPassword), "agent password")
 	flagSet.StringSlice(flagRedact, viper.GetStringSlice(flagRedact), "comma-delimited list of fields to redact, overwrites the default fields. This flag can also be invoked multiple times")
 	flagSet.Bool(flagStatsdDisable, viper.GetBool(flagStatsdDisable), "disables the statsd listener and metrics server")
@@ -422,6 +502,8 @@ func flagSet() *pflag.FlagSet {
 	flagSet.StringSlice(flagSubscriptions, viper.GetStringSlice(flagSubscriptions), "comma-delimited list of agent subscriptions. This flag can also be invoked multiple times")
 	flagSet.String(flagUser, viper.GetString(flagUser), "agent user")
 	flagSet.StringSlice(flagBackendURL, viper.GetStringSlice(flagBackendURL), "comma-delimited list of ws/wss URLs of Sensu backend servers. This flag can also be invoked multiple times")
+	flagSet.String(flagBackendSelection, viper.GetString(flagBackendSelection), "strategy used to select the backend to connect to: random, priority (in the order of the backend URLs, failing back to the first ones once they recover), latency (lowest round-trip time first) or sticky (evenly spread across the backends, failing back like priority)")
+	flagSet.Duration(flagBackendProbeInterval, viper.GetDuration(flagBackendProbeInterval), "interval at which the health of the backends is probed to fail back or rebalance, with the priority, latency and sticky backend selections")
 	flagSet.StringSlice(flagKeepaliveHandlers, viper.GetStringSlice(flagKeepaliveHandlers), "comma-delimited list of keepalive handlers for this entity. This flag can also be invoked multiple times")
 	flagSet.Int(flagKeepaliveInterval, viper.GetInt(flagKeepaliveInterval), "number of seconds to send between keepalive events")
 	flagSet.Uint32(flagKeepaliveWarningTimeout, uint32(viper.GetInt(flagKeepaliveWarningTimeout)), "number of seconds until agent is considered dead by backend to create a warning event")
@@ -442,6 +524,8 @@ func flagSet() *pflag.FlagSet {
 	flagSet.Int(flagBackendHandshakeTimeout, viper.GetInt(flagBackendHandshakeTimeout), "number of seconds the agent should wait when negotiating a new WebSocket connection")
 	flagSet.Int(flagBackendHeartbeatInterval, viper.GetInt(flagBackendHeartbeatInterval), "interval at which the agent should send heartbeats to the backend")
 	flagSet.Int(flagBackendHeartbeatTimeout, viper.GetInt(flagBackendHeartbeatTimeout), "number of seconds the agent should wait for a response to a hearbeat")
+	flagSet.Int(flagBackendBatchSize, viper.GetInt(flagBackendBatchSize), "maximum number of queued messages the agent packs in a single message to the backend, if the backend supports it (batching is disabled when lower than 2)")
+	flagSet.String(flagBackendCompression, viper.GetString(flagBackendCompression), "compression of the messages exchanged with the backend, if the backend supports it: none, deflate or zstd")
 	flagSet.Bool(flagAgentManagedEntity, viper.GetBool(flagAgentManagedEntity), "manage this entity via the agent")
 	flagSet.Duration(flagRetryMin, viper.GetDuration(flagRetryMin), "minimum amount of time to wait before retrying an agent connection to the backend")
 	flagSet.Duration(flagRetryMax, viper.GetDuration(flagRetryMax), "maximum amount of time to wait before retrying an agent connection to the backend")
@@ -24,6 +24,22 @@ const (
 	// DefaultBackendURL specifies the default backend URL
 	DefaultBackendURL = "ws://127.0.0.1:8081"
 
+	// DefaultBackendBatchSize specifies the default maximum number of messages
+	// sent to the backend in a single batch message
+	DefaultBackendBatchSize = 100
+
+	// BackendCompressionNone disables the compression of the messages
+	// exchanged with the backend
+	BackendCompressionNone = "none"
+
+	// BackendCompressionDeflate compresses the messages exchanged with the
+	// backend with the permessage-deflate websocket extension
+	BackendCompressionDeflate = "deflate"
+
+	// BackendCompressionZstd compresses the messages exchanged with the
+	// backend with zstd
+	BackendCompressionZstd = "zstd"
+
 	// DefaultEventsAPIRateLimit defines the rate limit, in events per second,
 	// for outgoing events.
 	DefaultEventsAPIRateLimit rate.Limit = 10.0
@@ -36,9 +52,56 @@ const (
 	// DefaultKeepaliveInterval specifies the default keepalive interval
 	DefaultKeepaliveInterval = 20
 
+	// DefaultLocalSchedulerDisable specifies if the local scheduling of the
+	// checks is disabled
+	DefaultLocalSchedulerDisable = false
+
+	// DefaultLocalSchedulerMaxAge specifies the default maximum age of the
+	// check requests scheduled locally
+	DefaultLocalSchedulerMaxAge = 24 * time.Hour
+
 	// DefaultNamespace specifies the default namespace
 	DefaultNamespace = "default"
 
+	// DefaultOTLPDisable specifies if the OpenTelemetry metrics receiver is
+	// disabled
+	DefaultOTLPDisable = false
+
+	// DefaultOTLPGRPCHost specifies the default host of the OpenTelemetry gRPC
+	// metrics receiver
+	DefaultOTLPGRPCHost = "127.0.0.1"
+
+	// DefaultOTLPGRPCPort specifies the default port of the OpenTelemetry gRPC
+	// metrics receiver. The gRPC receiver is disabled when the port is 0.
+	DefaultOTLPGRPCPort = 0
+
+	// DefaultOutboxDisable specifies if the outbox is disabled
+	DefaultOutboxDisable = false
+
+	// DefaultOutboxMaxBytes specifies the default maximum size, in bytes, of
+	// the compressed events kept in the outbox
+	DefaultOutboxMaxBytes int64 = 100 * 1024 * 1024
+
+	// DefaultOutboxMaxAge specifies the default maximum age of the events kept
+	// in the outbox
+	DefaultOutboxMaxAge = 24 * time.Hour
+
+	// DefaultOutboxReplayRateLimit defines the rate limit, in events per
+	// second, of the events replayed from the outbox
+	DefaultOutboxReplayRateLimit rate.Limit = 100.0
+
+	// DefaultOutboxReplayBurstLimit defines the burst ceiling of the events
+	// replayed from the outbox
+	DefaultOutboxReplayBurstLimit int = 100
+
+	// DefaultProcessesEnable specifies if the discovery of the local
+	// processes is enabled
+	DefaultProcessesEnable = false
+
+	// DefaultProcessesCmdline specifies if the command lines of the local
+	// processes are reported
+	DefaultProcessesCmdline = false
+
 	// DefaultPassword specifies the default password
 	DefaultPassword = "P@ssw0rd!"
 
@@ -79,10 +142,26 @@ type Config struct {
 	// AssetsBurstLimit is the maximum amount of burst allowed in a rate interval.
 	AssetsBurstLimit int
 
+	// AssetSignaturePolicy is the policy of the assets whose signature can't
+	// be verified with the AssetTrustedKeys.
+	AssetSignaturePolicy asset.SignaturePolicy
+
+	// AssetTrustedKeys are the files of the public keys trusted to sign the
+	// assets. The signatures of the assets are verified when set.
+	AssetTrustedKeys []string
+
 	// BackendURLs is a list of URLs for the Sensu Backend. Default:
 	// ws://127.0.0.1:8081
 	BackendURLs []string
 
+	// BackendSelection is the strategy used to select the backend to connect
+	// to among the BackendURLs: random, priority, latency or sticky.
+	BackendSelection string
+
+	// BackendProbeInterval is the interval at which the backends are probed,
+	// to fail back to a preferred backend or to rebalance the agents.
+	BackendProbeInterval time.Duration
+
 	// CacheDir path where cached data is stored
 	CacheDir string
 
@@ -144,12 +223,27 @@ type Config struct {
 	// Annotations are key-value pairs that users can provide to agent entities
 	Annotations map[string]string
 
+	// LocalScheduler contains the configuration of the local scheduling of
+	// the checks while disconnected from the backend
+	LocalScheduler *LocalSchedulerConfig
+
 	// Namespace sets the Agent's RBAC namespace identifier
 	Namespace string
 
 	// Password sets Agent's password
 	Password string
 
+	// OTLP contains the OpenTelemetry metrics receiver configuration
+	OTLP *OTLPConfig
+
+	// Outbox contains the configuration of the outbox, which keeps the events
+	// that could not be sent to the backend
+	Outbox *OutboxConfig
+
+	// Processes contains the configuration of the discovery of the local
+	// processes
+	Processes *ProcessesConfig
+
 	// Redact contains the fields to redact when marshalling the agent's entity
 	Redact []string
 
@@ -181,6 +275,15 @@ type Config struct {
 	// reconnect with exponential backoff
 	BackendHeartbeatTimeout int
 
+	// BackendBatchSize specifies the maximum number of messages ready to be
+	// sent that the agent packs in a single message, if the backend supports
+	// batch messages. Batching is disabled when lower than 2.
+	BackendBatchSize int
+
+	// BackendCompression specifies the compression of the messages exchanged
+	// with the backend, if the backend supports it: none, deflate or zstd
+	BackendCompression string
+
 	// MockSystemInfo determines whether the system info collection should return
 	// mocked system information. This should only be used for testing.
 	MockSystemInfo bool
@@ -222,6 +325,77 @@ type StatsdServerConfig struct {
 	Disable       bool
 }
 
+// OTLPConfig contains the OpenTelemetry metrics receiver configuration. The
+// OTLP/HTTP receiver is served by the agent API, and the OTLP/gRPC receiver
+// has its own listener.
+type OTLPConfig struct {
+	Disable  bool
+	Handlers []string
+	GRPCHost string
+	GRPCPort int
+}
+
+// OutboxConfig contains the configuration of the outbox. The outbox is a
+// bounded, disk-backed queue in the cache directory, which keeps the events
+// produced while the agent is disconnected from the backend, and replays them
+// once the agent reconnects.
+type OutboxConfig struct {
+	// Disable disables the outbox
+	Disable bool
+
+	// MaxBytes is the maximum size, in bytes, of the compressed events kept
+	// in the outbox. The oldest events are dropped to make room for new ones.
+	MaxBytes int64
+
+	// MaxAge is the maximum age of the events kept in the outbox. Older events
+	// are dropped.
+	MaxAge time.Duration
+
+	// ReplayRateLimit is the maximum number of events per second replayed
+	// from the outbox once the agent reconnects. 0 means no limit.
+	ReplayRateLimit rate.Limit
+
+	// ReplayBurstLimit is the maximum amount of burst allowed in a rate
+	// interval when replaying events.
+	ReplayBurstLimit int
+}
+
+// LocalSchedulerConfig contains the configuration of the local scheduler. The
+// local scheduler keeps the last check requests received from the backend in
+// the cache directory, and keeps executing them on their schedule while the
+// agent is disconnected from the backend. Their events are kept in the outbox,
+// which is required, until the agent reconnects.
+type LocalSchedulerConfig struct {
+	// Disable disables the local scheduler
+	Disable bool
+
+	// MaxAge is the maximum age of the check requests scheduled locally. The
+	// requests that were not received from the backend for longer are not
+	// executed anymore.
+	MaxAge time.Duration
+}
+
+// ProcessesConfig contains the configuration of the discovery of the local
+// processes, which are refreshed along with the system information of the
+// entity. The entity only contains the names of the processes, which can be
+// narrowed down with glob patterns to keep the keepalives small. The discovery
+// is opt-in, since the process list reveals what runs on the host.
+type ProcessesConfig struct {
+	// Enable enables the discovery of the local processes
+	Enable bool
+
+	// Allow contains the patterns of the names of the processes to report.
+	// All the processes are reported when empty.
+	Allow []string
+
+	// Deny contains the patterns of the names of the processes to ignore
+	Deny []string
+
+	// Cmdline reports the command lines of the processes, which often
+	// contain credentials, at /processes on the agent API
+	Cmdline bool
+}
+
 // FixtureConfig provides a new Config object initialized with defaults for use
 // in tests, as well as a cleanup function to call at the end of the test.
 func FixtureConfig() (*Config, func()) {
@@ -238,14 +412,42 @@ func FixtureConfig() (*Config, func()) {
 		},
 		AssetsRateLimit:         asset.DefaultAssetsRateLimit,
 		AssetsBurstLimit:        asset.DefaultAssetsBurstLimit,
+		AssetSignaturePolicy:    asset.SignaturePolicyEnforce,
 		BackendURLs:             []string{},
+		BackendSelection:        BackendSelectionRandom,
+		BackendProbeInterval:    DefaultBackendProbeInterval,
+		BackendBatchSize:        DefaultBackendBatchSize,
+		BackendCompression:      BackendCompressionNone,
 		CacheDir:                cacheDir,
 		EventsAPIRateLimit:      DefaultEventsAPIRateLimit,
 		EventsAPIBurstLimit:     DefaultEventsAPIBurstLimit,
 		KeepaliveInterval:       DefaultKeepaliveInterval,
 		KeepaliveWarningTimeout: corev2.DefaultKeepaliveTimeout,
 		Namespace:               DefaultNamespace,
-		Password:                DefaultPassword,
+		OTLP: &OTLPConfig{
+			Disable:  DefaultOTLPDisable,
+			Handlers: []string{},
+			GRPCHost: DefaultOTLPGRPCHost,
+			GRPCPort: DefaultOTLPGRPCPort,
+		},
+		// The outbox is disabled so that the events sent by the agent, which is
+		// not connected to a backend, can be read from its send queue
+		Outbox: &OutboxConfig{
+			Disable:          true,
+			MaxBytes:         DefaultOutboxMaxBytes,
+			MaxAge:           DefaultOutboxMaxAge,
+			ReplayRateLimit:  DefaultOutboxReplayRateLimit,
+			ReplayBurstLimit: DefaultOutboxReplayBurstLimit,
+		},
+		// The local scheduler requires the outbox
+		LocalScheduler: &LocalSchedulerConfig{
+			Disable: true,
+			MaxAge:  DefaultLocalSchedulerMaxAge,
+		},
+		Password: DefaultPassword,
+		Processes: &ProcessesConfig{
+			Enable: DefaultProcessesEnable,
+		},
 		StatsdServer: &StatsdServerConfig{
 			Host:          DefaultStatsdMetricsHost,
 			Port:          DefaultStatsdMetricsPort,
@@ -265,8 +467,12 @@ func FixtureConfig() (*Config, func()) {
 // NewConfig provides a new empty Config object
 func NewConfig() *Config {
 	c := &Config{
-		API:          &APIConfig{},
-		StatsdServer: &StatsdServerConfig{},
+		API:            &APIConfig{},
+		LocalScheduler: &LocalSchedulerConfig{},
+		OTLP:           &OTLPConfig{},
+		Outbox:         &OutboxConfig{},
+		Processes:      &ProcessesConfig{},
+		StatsdServer:   &StatsdServerConfig{},
 	}
 	return c
 }
@@ -0,0 +1,301 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	cron "github.com/robfig/cron/v3"
+	corev2 "github.com/sensu/core/v2"
+	utilstrings "github.com/sensu/sensu-go/util/strings"
+	bolt "go.etcd.io/bbolt"
+)
+
+// LocalScheduleAnnotation is the annotation of the checks of the events
+// produced by the local scheduler, while the agent was disconnected from the
+// backend.
+const LocalScheduleAnnotation = "sensu.io/local_schedule"
+
+// localSchedulerInterval is the interval at which the local scheduler looks
+// for the checks to execute.
+const localSchedulerInterval = time.Second
+
+var localChecksBucket = []byte("checks")
+
+// localCheck is a check request kept by the local scheduler.
+type localCheck struct {
+	// Request is the last request of the check received from the backend.
+	Request *corev2.CheckRequest `json:"request"`
+
+	// Received is the time at which the request was received.
+	Received time.Time `json:"received"`
+
+	// Executed is the time, in seconds since the epoch, of the last local
+	// execution of the check since the request was received, if any.
+	Executed int64 `json:"executed,omitempty"`
+}
+
+// localScheduler keeps the last check requests received from the backend in a
+// bolt database, so that the agent keeps executing them on their schedule
+// while it is disconnected from the backend, including across restarts. The
+// requests not received for longer than maxAge are dropped, unless maxAge is
+// 0.
+type localScheduler struct {
+	db     *bolt.DB
+	maxAge time.Duration
+	now    func() time.Time
+}
+
+func newLocalScheduler(path string, config *LocalSchedulerConfig) (*localScheduler, error) {
+	if err := os.MkdirAll(path, 0744|os.ModeDir); err != nil {
+		return nil, fmt.Errorf("could not create directory for local scheduler (%s): %s", path, err)
+	}
+	dbPath := filepath.Join(path, "local_checks.db")
+	db, err := bolt.Open(dbPath, 0600, &bolt.Options{Timeout: 60 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("could not open local scheduler (%s): %s (is sensu-agent already running?)", dbPath, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(localChecksBucket)
+		return err
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("could not load local scheduler (%s): %s", dbPath, err)
+	}
+	return &localScheduler{
+		db:     db,
+		maxAge: config.MaxAge,
+		now:    time.Now,
+	}, nil
+}
+
+// Close closes the local scheduler database.
+func (s *localScheduler) Close() error {
+	return s.db.Close()
+}
+
+// schedulable returns whether a check request can be scheduled locally. Only
+// the published checks that run on the agent itself on a schedule are
+// scheduled, since the round robin and proxy checks are distributed by the
+// backend among several agents.
+func schedulable(request *corev2.CheckRequest) bool {
+	config := request.Config
+	if !config.Publish || config.RoundRobin || config.ProxyEntityName != "" || config.ProxyRequests != nil {
+		return false
+	}
+	return config.Interval > 0 || config.Cron != ""
+}
+
+// nextExecution returns the time, in seconds since the epoch, of the next
+// execution of a check after the given time.
+func nextExecution(config *corev2.CheckConfig, after int64) (int64, error) {
+	if config.Cron == "" {
+		return after + int64(config.Interval), nil
+	}
+	schedule, err := cron.ParseStandard(config.Cron)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cron %q: %s", config.Cron, err)
+	}
+	return schedule.Next(time.Unix(after, 0)).Unix(), nil
+}
+
+func (s *localScheduler) get(bucket *bolt.Bucket, key string) (*localCheck, error) {
+	value := bucket.Get([]byte(key))
+	if value == nil {
+		return nil, nil
+	}
+	var check localCheck
+	if err := json.Unmarshal(value, &check); err != nil {
+		return nil, err
+	}
+	return &check, nil
+}
+
+func (s *localScheduler) put(bucket *bolt.Bucket, key string, check *localCheck) error {
+	value, err := json.Marshal(check)
+	if err != nil {
+		return err
+	}
+	return bucket.Put([]byte(key), value)
+}
+
+// received keeps a check request received from the backend. It returns false
+// if the request must not be executed, because it was already executed
+// locally while the agent was disconnected: the first request issued by the
+// backend after a local execution is skipped when it is closer to that
+// execution than to the next one. The requests that carry secrets are not kept,
+// since the database is not encrypted, and their checks are not executed
+// locally.
+func (s *localScheduler) received(request *corev2.CheckRequest) (bool, error) {
+	if !schedulable(request) {
+		return true, nil
+	}
+	execute := true
+	key := checkKey(request)
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(localChecksBucket)
+		previous, err := s.get(bucket, key)
+		if err != nil {
+			logger.WithError(err).WithField("check", key).Error("dropping invalid check request from the local scheduler")
+		} else if previous != nil && previous.Executed > 0 {
+			next, err := nextExecution(request.Config, previous.Executed)
+			if err == nil && request.Issued < previous.Executed+(next-previous.Executed)/2 {
+				execute = false
+			}
+		}
+		if len(request.Secrets) > 0 {
+			return bucket.Delete([]byte(key))
+		}
+		return s.put(bucket, key, &localCheck{Request: request, Received: s.now()})
+	})
+	if err != nil {
+		return true, fmt.Errorf("could not keep check request in the local scheduler: %s", err)
+	}
+	return execute, nil
+}
+
+// due returns the check requests that are due for execution, for the checks
+// of the given subscriptions. The returned requests are issued at the current
+// time and annotated with LocalScheduleAnnotation.
+func (s *localScheduler) due(subscriptions []string) ([]*corev2.CheckRequest, error) {
+	now := s.now()
+	var requests []*corev2.CheckRequest
+	var expired [][]byte
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(localChecksBucket).ForEach(func(k, v []byte) error {
+			var check localCheck
+			if err := json.Unmarshal(v, &check); err != nil || check.Request == nil || check.Request.Config == nil {
+				logger.WithField("check", string(k)).Error("dropping invalid check request from the local scheduler")
+				expired = append(expired, append([]byte(nil), k...))
+				return nil
+			}
+			if len(check.Request.Secrets) > 0 {
+				logger.WithField("check", string(k)).Info("dropping check request with secrets from the local scheduler")
+				expired = append(expired, append([]byte(nil), k...))
+				return nil
+			}
+			if s.maxAge > 0 && now.Sub(check.Received) > s.maxAge {
+				logger.WithField("check", string(k)).Info("dropping expired check request from the local scheduler")
+				expired = append(expired, append([]byte(nil), k...))
+				return nil
+			}
+			request := check.Request
+			if !subscribed(request.Config, subscriptions) {
+				return nil
+			}
+			last := request.Issued
+			if check.Executed > last {
+				last = check.Executed
+			}
+			next, err := nextExecution(request.Config, last)
+			if err != nil {
+				logger.WithError(err).WithField("check", string(k)).Error("can't schedule check locally")
+				return nil
+			}
+			if now.Unix() < next {
+				return nil
+			}
+			request.Issued = now.Unix()
+			if request.Config.Annotations == nil {
+				request.Config.Annotations = make(map[string]string)
+			}
+			request.Config.Annotations[LocalScheduleAnnotation] = "true"
+			requests = append(requests, request)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not read the local scheduler: %s", err)
+	}
+	if len(expired) == 0 {
+		return requests, nil
+	}
+	// Only open a write transaction when there are requests to drop, since
+	// due is called every second while the agent is disconnected
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(localChecksBucket)
+		for _, k := range expired {
+			if err := bucket.Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not update the local scheduler: %s", err)
+	}
+	return requests, nil
+}
+
+// executed records the local execution of a check request returned by due.
+func (s *localScheduler) executed(request *corev2.CheckRequest) error {
+	key := checkKey(request)
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(localChecksBucket)
+		check, err := s.get(bucket, key)
+		if err != nil || check == nil {
+			return err
+		}
+		check.Executed = request.Issued
+		return s.put(bucket, key, check)
+	})
+	if err != nil {
+		return fmt.Errorf("could not update the local scheduler: %s", err)
+	}
+	return nil
+}
+
+// subscribed returns whether a check targets one of the subscriptions.
+func subscribed(config *corev2.CheckConfig, subscriptions []string) bool {
+	for _, subscription := range config.Subscriptions {
+		if utilstrings.InArray(subscription, subscriptions) {
+			return true
+		}
+	}
+	return false
+}
+
+// runLocalScheduler executes the check requests kept by the local scheduler
+// on their schedule while the agent is disconnected from the backend, until
+// ctx is done. Their events are kept in the outbox until the agent reconnects.
+func (a *Agent) runLocalScheduler(ctx context.Context) {
+	defer logger.Info("shutting down local scheduler")
+	ticker := time.NewTicker(localSchedulerInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !a.Connected() {
+				a.executeLocalChecks(ctx)
+			}
+		}
+	}
+}
+
+// executeLocalChecks executes the check requests that are due for execution
+// according to the local scheduler.
+func (a *Agent) executeLocalChecks(ctx context.Context) {
+	entity := a.getAgentEntity()
+	requests, err := a.localScheduler.due(entity.Subscriptions)
+	if err != nil {
+		logger.WithError(err).Error("error scheduling checks locally")
+		return
+	}
+	for _, request := range requests {
+		if a.checkInProgress(request) {
+			continue
+		}
+		if err := a.localScheduler.executed(request); err != nil {
+			logger.WithError(err).Error("error scheduling checks locally")
+			continue
+		}
+		logger.WithField("check", request.Config.Name).Info("scheduling check execution locally")
+		go a.executeCheck(ctx, request, entity)
+	}
+}
@@ -0,0 +1,261 @@
+package agent
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/command"
+	"github.com/sensu/sensu-go/testing/mockexecutor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLocalScheduler(t *testing.T, dir string, config *LocalSchedulerConfig) *localScheduler {
+	t.Helper()
+	s, err := newLocalScheduler(dir, config)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+func fixtureLocalCheckRequest(name string, issued time.Time) *corev2.CheckRequest {
+	config := corev2.FixtureCheckConfig(name)
+	config.Publish = true
+	config.Interval = 60
+	config.Subscriptions = []string{"linux"}
+	return &corev2.CheckRequest{Config: config, Issued: issued.Unix()}
+}
+
+func TestLocalSchedulerDue(t *testing.T) {
+	dir := t.TempDir()
+	s, err := newLocalScheduler(dir, &LocalSchedulerConfig{})
+	require.NoError(t, err)
+
+	issued := time.Unix(1700000000, 0)
+	s.now = func() time.Time { return issued }
+	execute, err := s.received(fixtureLocalCheckRequest("check", issued))
+	require.NoError(t, err)
+	assert.True(t, execute)
+
+	s.now = func() time.Time { return issued.Add(30 * time.Second) }
+	requests, err := s.due([]string{"linux"})
+	require.NoError(t, err)
+	assert.Empty(t, requests)
+
+	s.now = func() time.Time { return issued.Add(time.Minute) }
+	requests, err = s.due([]string{"linux"})
+	require.NoError(t, err)
+	require.Len(t, requests, 1)
+	assert.Equal(t, "check", requests[0].Config.Name)
+	assert.Equal(t, issued.Add(time.Minute).Unix(), requests[0].Issued)
+	assert.Equal(t, "true", requests[0].Config.Annotations[LocalScheduleAnnotation])
+	require.NoError(t, s.executed(requests[0]))
+
+	// The checks are not scheduled for other subscriptions
+	requests, err = s.due([]string{"windows"})
+	require.NoError(t, err)
+	assert.Empty(t, requests)
+	require.NoError(t, s.Close())
+
+	// The requests and their local executions are kept across restarts
+	s = newTestLocalScheduler(t, dir, &LocalSchedulerConfig{})
+	s.now = func() time.Time { return issued.Add(90 * time.Second) }
+	requests, err = s.due([]string{"linux"})
+	require.NoError(t, err)
+	assert.Empty(t, requests)
+
+	s.now = func() time.Time { return issued.Add(2 * time.Minute) }
+	requests, err = s.due([]string{"linux"})
+	require.NoError(t, err)
+	assert.Len(t, requests, 1)
+}
+
+func TestLocalSchedulerCron(t *testing.T) {
+	s := newTestLocalScheduler(t, t.TempDir(), &LocalSchedulerConfig{})
+
+	issued := time.Date(2024, 3, 17, 2, 0, 0, 0, time.Local)
+	request := fixtureLocalCheckRequest("check", issued)
+	request.Config.Interval = 0
+	request.Config.Cron = "0 * * * *"
+	s.now = func() time.Time { return issued }
+	_, err := s.received(request)
+	require.NoError(t, err)
+
+	s.now = func() time.Time { return issued.Add(59 * time.Minute) }
+	requests, err := s.due([]string{"linux"})
+	require.NoError(t, err)
+	assert.Empty(t, requests)
+
+	s.now = func() time.Time { return issued.Add(time.Hour) }
+	requests, err = s.due([]string{"linux"})
+	require.NoError(t, err)
+	assert.Len(t, requests, 1)
+}
+
+func TestLocalSchedulerReceived(t *testing.T) {
+	tests := []struct {
+		name    string
+		issued  time.Duration
+		execute bool
+	}{
+		{
+			name:    "backend request in the slot of the local execution",
+			issued:  10 * time.Second,
+			execute: false,
+		},
+		{
+			name:    "backend request in the next slot",
+			issued:  50 * time.Second,
+			execute: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestLocalScheduler(t, t.TempDir(), &LocalSchedulerConfig{})
+			start := time.Unix(1700000000, 0)
+			s.now = func() time.Time { return start }
+			_, err := s.received(fixtureLocalCheckRequest("check", start))
+			require.NoError(t, err)
+
+			local := start.Add(time.Minute)
+			s.now = func() time.Time { return local }
+			requests, err := s.due([]string{"linux"})
+			require.NoError(t, err)
+			require.Len(t, requests, 1)
+			require.NoError(t, s.executed(requests[0]))
+
+			execute, err := s.received(fixtureLocalCheckRequest("check", local.Add(tt.issued)))
+			require.NoError(t, err)
+			assert.Equal(t, tt.execute, execute)
+
+			// Only the first request after a local execution may be skipped
+			execute, err = s.received(fixtureLocalCheckRequest("check", local.Add(tt.issued+time.Minute)))
+			require.NoError(t, err)
+			assert.True(t, execute)
+		})
+	}
+}
+
+func TestLocalSchedulerMaxAge(t *testing.T) {
+	s := newTestLocalScheduler(t, t.TempDir(), &LocalSchedulerConfig{MaxAge: time.Hour})
+
+	issued := time.Unix(1700000000, 0)
+	s.now = func() time.Time { return issued }
+	_, err := s.received(fixtureLocalCheckRequest("check", issued))
+	require.NoError(t, err)
+
+	s.now = func() time.Time { return issued.Add(2 * time.Hour) }
+	requests, err := s.due([]string{"linux"})
+	require.NoError(t, err)
+	assert.Empty(t, requests)
+}
+
+func TestSchedulable(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(*corev2.CheckConfig)
+		want   bool
+	}{
+		{
+			name:   "interval",
+			mutate: func(*corev2.CheckConfig) {},
+			want:   true,
+		},
+		{
+			name:   "cron",
+			mutate: func(c *corev2.CheckConfig) { c.Interval, c.Cron = 0, "* * * * *" },
+			want:   true,
+		},
+		{
+			name:   "unpublished",
+			mutate: func(c *corev2.CheckConfig) { c.Publish = false },
+		},
+		{
+			name:   "round robin",
+			mutate: func(c *corev2.CheckConfig) { c.RoundRobin = true },
+		},
+		{
+			name:   "proxy entity",
+			mutate: func(c *corev2.CheckConfig) { c.ProxyEntityName = "router" },
+		},
+		{
+			name:   "proxy requests",
+			mutate: func(c *corev2.CheckConfig) { c.ProxyRequests = &corev2.ProxyRequests{} },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := fixtureLocalCheckRequest("check", time.Now())
+			tt.mutate(request.Config)
+			assert.Equal(t, tt.want, schedulable(request))
+		})
+	}
+}
+
+func TestExecuteLocalChecks(t *testing.T) {
+	config, cleanup := FixtureConfig()
+	defer cleanup()
+	config.Outbox.Disable = false
+	config.LocalScheduler.Disable = false
+	config.Subscriptions = []string{"linux"}
+	agent, err := NewAgent(config)
+	require.NoError(t, err)
+	defer agent.outbox.Close()
+	defer agent.localScheduler.Close()
+	require.NotNil(t, agent.localScheduler)
+
+	ex := &mockexecutor.MockExecutor{}
+	agent.executor = ex
+	ex.Return(command.FixtureExecutionResponse(0, "ok"), nil)
+
+	request := fixtureLocalCheckRequest("check", time.Now().Add(-time.Minute))
+	_, err = agent.localScheduler.received(request)
+	require.NoError(t, err)
+
+	// The events of the checks executed while disconnected are kept in the
+	// outbox
+	agent.executeLocalChecks(context.Background())
+	require.Eventually(t, func() bool {
+		return agent.outbox.len() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	entry, err := agent.outbox.next(0)
+	require.NoError(t, err)
+	var event corev2.Event
+	require.NoError(t, UnmarshalJSON(decompressMessage(entry.Payload), &event))
+	assert.Equal(t, "check", event.Check.Name)
+	assert.Equal(t, "true", event.Check.Annotations[LocalScheduleAnnotation])
+}
+
+func TestLocalSchedulerSecrets(t *testing.T) {
+	dir := t.TempDir()
+	s := newTestLocalScheduler(t, dir, &LocalSchedulerConfig{})
+
+	issued := time.Unix(1700000000, 0)
+	s.now = func() time.Time { return issued }
+	_, err := s.received(fixtureLocalCheckRequest("check", issued))
+	require.NoError(t, err)
+
+	// A request with secrets replaces the request kept for its check, and is
+	// neither written to the database nor executed locally
+	request := fixtureLocalCheckRequest("check", issued)
+	request.Secrets = []string{"TOKEN=hunter2"}
+	execute, err := s.received(request)
+	require.NoError(t, err)
+	assert.True(t, execute)
+	assert.Equal(t, []string{"TOKEN=hunter2"}, request.Secrets)
+
+	s.now = func() time.Time { return issued.Add(time.Minute) }
+	requests, err := s.due([]string{"linux"})
+	require.NoError(t, err)
+	assert.Empty(t, requests)
+	require.NoError(t, s.Close())
+
+	content, err := os.ReadFile(filepath.Join(dir, "local_checks.db"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "hunter2")
+}
@@ -0,0 +1,94 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/agent/otlp"
+	"github.com/sensu/sensu-go/transport"
+	"github.com/sirupsen/logrus"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+)
+
+// exportOTLPMetrics converts the metrics exported by an OpenTelemetry client to
+// a metrics event, which is sent to the backend.
+func (a *Agent) exportOTLPMetrics(ctx context.Context, req *colmetricspb.ExportMetricsServiceRequest) (*colmetricspb.ExportMetricsServiceResponse, error) {
+	points, rejected := otlp.MetricPoints(req, time.Now())
+
+	resp := new(colmetricspb.ExportMetricsServiceResponse)
+	if rejected > 0 {
+		resp.PartialSuccess = &colmetricspb.ExportMetricsPartialSuccess{
+			RejectedDataPoints: rejected,
+			ErrorMessage:       "unsupported or invalid data points were rejected",
+		}
+	}
+	if len(points) == 0 {
+		return resp, nil
+	}
+
+	event := &corev2.Event{
+		Entity:    a.getAgentEntity(),
+		Timestamp: time.Now().Unix(),
+		Metrics: &corev2.Metrics{
+			Points:   points,
+			Handlers: a.config.OTLP.Handlers,
+		},
+	}
+	msg, err := a.marshal(event)
+	if err != nil {
+		logger.WithError(err).Error("error marshaling metric event")
+		return nil, fmt.Errorf("could not marshal the metrics event: %s", err)
+	}
+
+	logger.WithFields(logrus.Fields{
+		"points": len(points),
+		"entity": event.Entity.Name,
+	}).Debug("sending otlp metrics")
+	a.sendMessage(&transport.Message{
+		Type:    transport.MessageTypeEvent,
+		Payload: msg,
+	})
+	return resp, nil
+}
+
+// StartOTLPGRPC starts the OTLP/gRPC metrics receiver, and logs an error for
+// any failures. The OTLP/HTTP metrics receiver is served by the agent API.
+func (a *Agent) StartOTLPGRPC(ctx context.Context) {
+	addr := net.JoinHostPort(a.config.OTLP.GRPCHost, strconv.Itoa(a.config.OTLP.GRPCPort))
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		logger.WithError(err).Errorf("otlp grpc listener failed on %s", addr)
+		return
+	}
+	server := otlp.NewGRPCServer(a.exportOTLPMetrics)
+	logger.Info("starting otlp grpc receiver on address: ", listener.Addr())
+
+	a.wg.Add(2)
+	go func() {
+		defer a.wg.Done()
+		if err := server.Serve(listener); err != nil {
+			logger.WithError(err).Errorf("otlp grpc receiver failed on %s", addr)
+		}
+	}()
+
+	go func() {
+		defer a.wg.Done()
+		<-ctx.Done()
+		logger.Info("otlp grpc receiver shutting down")
+
+		stopped := make(chan struct{})
+		go func() {
+			server.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(time.Second):
+			server.Stop()
+		}
+	}()
+}
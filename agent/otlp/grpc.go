@@ -0,0 +1,37 @@
+package otlp
+
+import (
+	"context"
+
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ExportFunc handles the metrics exported by an OTLP client.
+type ExportFunc func(ctx context.Context, req *colmetricspb.ExportMetricsServiceRequest) (*colmetricspb.ExportMetricsServiceResponse, error)
+
+// metricsService is the OTLP metrics gRPC service.
+type metricsService struct {
+	colmetricspb.UnimplementedMetricsServiceServer
+
+	export ExportFunc
+}
+
+// Export handles the metrics exported by a client.
+func (s *metricsService) Export(ctx context.Context, req *colmetricspb.ExportMetricsServiceRequest) (*colmetricspb.ExportMetricsServiceResponse, error) {
+	resp, err := s.export(ctx, req)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	return resp, nil
+}
+
+// NewGRPCServer returns a gRPC server serving the OTLP metrics service, whose
+// exports are handled by export.
+func NewGRPCServer(export ExportFunc, opts ...grpc.ServerOption) *grpc.Server {
+	server := grpc.NewServer(opts...)
+	colmetricspb.RegisterMetricsServiceServer(server, &metricsService{export: export})
+	return server
+}
@@ -0,0 +1,50 @@
+package otlp
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestGRPCServer(t *testing.T) {
+	var exported *colmetricspb.ExportMetricsServiceRequest
+	server := NewGRPCServer(func(ctx context.Context, req *colmetricspb.ExportMetricsServiceRequest) (*colmetricspb.ExportMetricsServiceResponse, error) {
+		if len(req.ResourceMetrics) == 0 {
+			return nil, errors.New("no metrics")
+		}
+		exported = req
+		return &colmetricspb.ExportMetricsServiceResponse{
+			PartialSuccess: &colmetricspb.ExportMetricsPartialSuccess{RejectedDataPoints: 2},
+		}, nil
+	})
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer server.Stop()
+
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, listener.Addr().String(), grpc.WithInsecure())
+	require.NoError(t, err)
+	defer conn.Close()
+	client := colmetricspb.NewMetricsServiceClient(conn)
+
+	resp, err := client.Export(ctx, testExportRequest())
+	require.NoError(t, err)
+	require.NotNil(t, exported)
+	assert.True(t, proto.Equal(testExportRequest(), exported))
+	assert.Equal(t, int64(2), resp.GetPartialSuccess().GetRejectedDataPoints())
+
+	_, err = client.Export(ctx, &colmetricspb.ExportMetricsServiceRequest{})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
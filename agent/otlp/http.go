@@ -0,0 +1,115 @@
+package otlp
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	// MetricsPath is the default path of the OTLP/HTTP metrics endpoint.
+	MetricsPath = "/v1/metrics"
+
+	// MaxRequestSize is the maximum size of the body of an OTLP/HTTP request,
+	// once decompressed.
+	MaxRequestSize = 16 * 1024 * 1024
+
+	contentTypeProtobuf = "application/x-protobuf"
+	contentTypeJSON     = "application/json"
+)
+
+var errRequestTooLarge = fmt.Errorf("request body larger than %d bytes", MaxRequestSize)
+
+// NewHTTPHandler returns an OTLP/HTTP handler for the metrics endpoint, whose
+// exports are handled by export. Requests can be encoded with protobuf or
+// JSON, and compressed with gzip.
+func NewHTTPHandler(export ExportFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if contentType != contentTypeProtobuf && contentType != contentTypeJSON {
+			writeStatus(w, contentTypeJSON, http.StatusUnsupportedMediaType, codes.InvalidArgument,
+				fmt.Sprintf("unsupported content type %q", contentType))
+			return
+		}
+
+		body, err := readBody(r)
+		if err != nil {
+			status := http.StatusBadRequest
+			if errors.Is(err, errRequestTooLarge) {
+				status = http.StatusRequestEntityTooLarge
+			}
+			writeStatus(w, contentType, status, codes.InvalidArgument, err.Error())
+			return
+		}
+
+		var req *colmetricspb.ExportMetricsServiceRequest
+		if contentType == contentTypeJSON {
+			req, err = UnmarshalRequestJSON(body)
+		} else {
+			req, err = UnmarshalRequest(body)
+		}
+		if err != nil {
+			writeStatus(w, contentType, http.StatusBadRequest, codes.InvalidArgument, err.Error())
+			return
+		}
+
+		resp, err := export(r.Context(), req)
+		if err != nil {
+			writeStatus(w, contentType, http.StatusServiceUnavailable, codes.Unavailable, err.Error())
+			return
+		}
+		var b []byte
+		if contentType == contentTypeJSON {
+			b = MarshalResponseJSON(resp)
+		} else {
+			b = MarshalResponse(resp)
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(b)
+	})
+}
+
+// readBody reads the body of a request, decompressing it if needed.
+func readBody(r *http.Request) ([]byte, error) {
+	var reader io.Reader = r.Body
+	switch encoding := r.Header.Get("Content-Encoding"); encoding {
+	case "", "identity":
+	case "gzip":
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzip body: %s", err)
+		}
+		defer gz.Close()
+		reader = gz
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q", encoding)
+	}
+	body, err := io.ReadAll(io.LimitReader(reader, MaxRequestSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > MaxRequestSize {
+		return nil, errRequestTooLarge
+	}
+	return body, nil
+}
+
+func writeStatus(w http.ResponseWriter, contentType string, status int, code codes.Code, message string) {
+	var b []byte
+	if contentType == contentTypeProtobuf {
+		b = MarshalStatus(code, message)
+	} else {
+		contentType = contentTypeJSON
+		b = MarshalStatusJSON(code, message)
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	_, _ = w.Write(b)
+}
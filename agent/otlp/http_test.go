@@ -0,0 +1,134 @@
+package otlp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/grpc/codes"
+)
+
+func TestHTTPHandler(t *testing.T) {
+	var gzipped bytes.Buffer
+	gz := gzip.NewWriter(&gzipped)
+	_, _ = gz.Write(testRequest())
+	_ = gz.Close()
+
+	tests := []struct {
+		name            string
+		contentType     string
+		contentEncoding string
+		body            []byte
+		exportErr       error
+		wantStatus      int
+		wantContentType string
+		wantBody        []byte
+		wantExported    bool
+	}{
+		{
+			name:            "protobuf",
+			contentType:     "application/x-protobuf",
+			body:            testRequest(),
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/x-protobuf",
+			wantBody:        MarshalResponse(&colmetricspb.ExportMetricsServiceResponse{}),
+			wantExported:    true,
+		},
+		{
+			name:            "gzipped protobuf",
+			contentType:     "application/x-protobuf",
+			contentEncoding: "gzip",
+			body:            gzipped.Bytes(),
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/x-protobuf",
+			wantBody:        MarshalResponse(&colmetricspb.ExportMetricsServiceResponse{}),
+			wantExported:    true,
+		},
+		{
+			name:            "json",
+			contentType:     "application/json; charset=utf-8",
+			body:            []byte(`{"resourceMetrics":[]}`),
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/json",
+			wantBody:        []byte(`{}`),
+			wantExported:    true,
+		},
+		{
+			name:            "unsupported content type",
+			contentType:     "text/plain",
+			body:            []byte("foo"),
+			wantStatus:      http.StatusUnsupportedMediaType,
+			wantContentType: "application/json",
+			wantBody:        MarshalStatusJSON(codes.InvalidArgument, `unsupported content type "text/plain"`),
+		},
+		{
+			name:            "unsupported content encoding",
+			contentType:     "application/x-protobuf",
+			contentEncoding: "br",
+			body:            testRequest(),
+			wantStatus:      http.StatusBadRequest,
+			wantContentType: "application/x-protobuf",
+			wantBody:        MarshalStatus(codes.InvalidArgument, `unsupported content encoding "br"`),
+		},
+		{
+			name:            "invalid json",
+			contentType:     "application/json",
+			body:            []byte(`{`),
+			wantStatus:      http.StatusBadRequest,
+			wantContentType: "application/json",
+		},
+		{
+			name:            "too large",
+			contentType:     "application/x-protobuf",
+			body:            make([]byte, MaxRequestSize+1),
+			wantStatus:      http.StatusRequestEntityTooLarge,
+			wantContentType: "application/x-protobuf",
+			wantBody:        MarshalStatus(codes.InvalidArgument, errRequestTooLarge.Error()),
+		},
+		{
+			name:            "export error",
+			contentType:     "application/json",
+			body:            []byte(`{}`),
+			exportErr:       errors.New("queue full"),
+			wantStatus:      http.StatusServiceUnavailable,
+			wantContentType: "application/json",
+			wantBody:        MarshalStatusJSON(codes.Unavailable, "queue full"),
+			wantExported:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exported := false
+			handler := NewHTTPHandler(func(ctx context.Context, req *colmetricspb.ExportMetricsServiceRequest) (*colmetricspb.ExportMetricsServiceResponse, error) {
+				exported = true
+				if tt.exportErr != nil {
+					return nil, tt.exportErr
+				}
+				return &colmetricspb.ExportMetricsServiceResponse{}, nil
+			})
+
+			r, err := http.NewRequest(http.MethodPost, MetricsPath, bytes.NewReader(tt.body))
+			require.NoError(t, err)
+			r.Header.Set("Content-Type", tt.contentType)
+			if tt.contentEncoding != "" {
+				r.Header.Set("Content-Encoding", tt.contentEncoding)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, tt.wantContentType, w.Header().Get("Content-Type"))
+			if tt.wantBody != nil {
+				assert.Equal(t, string(tt.wantBody), w.Body.String())
+			}
+			assert.Equal(t, tt.wantExported, exported)
+		})
+	}
+}
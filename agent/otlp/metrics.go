@@ -0,0 +1,220 @@
+package otlp
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"strconv"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+)
+
+// MetricPoints converts the data points of a request to Sensu metric points.
+// The attributes of the resource and of each data point become the tags of the
+// metric points, the latter taking precedence. Data points without a timestamp
+// are given now.
+//
+// Gauges and sums are converted to one metric point per data point. Histograms
+// are converted to the .count, .sum, .min and .max metric points, and to one
+// cumulative .bucket metric point per bucket, tagged with its upper bound (le).
+//
+// It also returns the number of data points that were rejected, either
+// because their type is not supported or because they are not valid.
+func MetricPoints(req *colmetricspb.ExportMetricsServiceRequest, now time.Time) ([]*corev2.MetricPoint, int64) {
+	var points []*corev2.MetricPoint
+	var rejected int64
+	for _, rm := range req.GetResourceMetrics() {
+		resourceAttributes := rm.GetResource().GetAttributes()
+		for _, sm := range rm.GetScopeMetrics() {
+			for _, m := range sm.GetMetrics() {
+				p, r := metricPoints(m, resourceAttributes, now)
+				points = append(points, p...)
+				rejected += r
+			}
+		}
+	}
+	return points, rejected
+}
+
+func metricPoints(m *metricspb.Metric, resourceAttributes []*commonpb.KeyValue, now time.Time) ([]*corev2.MetricPoint, int64) {
+	var numberPoints []*metricspb.NumberDataPoint
+	switch data := m.Data.(type) {
+	case *metricspb.Metric_Gauge:
+		numberPoints = data.Gauge.GetDataPoints()
+	case *metricspb.Metric_Sum:
+		numberPoints = data.Sum.GetDataPoints()
+	case *metricspb.Metric_Histogram:
+		return histogramPoints(m.Name, data.Histogram, resourceAttributes, now)
+	case *metricspb.Metric_ExponentialHistogram:
+		// Exponential histograms and summaries are not supported
+		return nil, int64(len(data.ExponentialHistogram.GetDataPoints()))
+	case *metricspb.Metric_Summary:
+		return nil, int64(len(data.Summary.GetDataPoints()))
+	}
+	if m.Name == "" {
+		return nil, int64(len(numberPoints))
+	}
+
+	points := make([]*corev2.MetricPoint, 0, len(numberPoints))
+	for _, dp := range numberPoints {
+		points = append(points, &corev2.MetricPoint{
+			Name:      m.Name,
+			Value:     numberValue(dp),
+			Timestamp: timestamp(dp.TimeUnixNano, now),
+			Tags:      tags(resourceAttributes, dp.Attributes),
+		})
+	}
+	return points, 0
+}
+
+// numberValue returns the value of a data point, which is an integer or a
+// double, as a float64.
+func numberValue(dp *metricspb.NumberDataPoint) float64 {
+	switch value := dp.Value.(type) {
+	case *metricspb.NumberDataPoint_AsInt:
+		return float64(value.AsInt)
+	case *metricspb.NumberDataPoint_AsDouble:
+		return value.AsDouble
+	}
+	return 0
+}
+
+func histogramPoints(name string, histogram *metricspb.Histogram, resourceAttributes []*commonpb.KeyValue, now time.Time) ([]*corev2.MetricPoint, int64) {
+	if name == "" {
+		return nil, int64(len(histogram.GetDataPoints()))
+	}
+
+	var points []*corev2.MetricPoint
+	var rejected int64
+	for _, dp := range histogram.GetDataPoints() {
+		if len(dp.BucketCounts) > 0 && len(dp.BucketCounts) != len(dp.ExplicitBounds)+1 {
+			rejected++
+			continue
+		}
+		ts := timestamp(dp.TimeUnixNano, now)
+		dpTags := tags(resourceAttributes, dp.Attributes)
+		point := func(suffix string, value float64, extra ...*corev2.MetricTag) *corev2.MetricPoint {
+			return &corev2.MetricPoint{
+				Name:      name + suffix,
+				Value:     value,
+				Timestamp: ts,
+				Tags:      append(append([]*corev2.MetricTag{}, dpTags...), extra...),
+			}
+		}
+
+		points = append(points, point(".count", float64(dp.Count)))
+		if dp.Sum != nil {
+			points = append(points, point(".sum", *dp.Sum))
+		}
+		if dp.Min != nil {
+			points = append(points, point(".min", *dp.Min))
+		}
+		if dp.Max != nil {
+			points = append(points, point(".max", *dp.Max))
+		}
+		var cumulative uint64
+		for i, count := range dp.BucketCounts {
+			cumulative += count
+			bound := math.Inf(1)
+			if i < len(dp.ExplicitBounds) {
+				bound = dp.ExplicitBounds[i]
+			}
+			le := &corev2.MetricTag{Name: "le", Value: formatFloat(bound)}
+			points = append(points, point(".bucket", float64(cumulative), le))
+		}
+	}
+	return points, rejected
+}
+
+func timestamp(unixNano uint64, now time.Time) int64 {
+	if unixNano == 0 {
+		return now.UnixNano()
+	}
+	return int64(unixNano)
+}
+
+// tags returns the tags of a data point, from the attributes of its resource
+// and its own attributes.
+func tags(resourceAttributes, attributes []*commonpb.KeyValue) []*corev2.MetricTag {
+	tags := make([]*corev2.MetricTag, 0, len(resourceAttributes)+len(attributes))
+	index := make(map[string]int, cap(tags))
+	for _, attrs := range [][]*commonpb.KeyValue{resourceAttributes, attributes} {
+		for _, kv := range attrs {
+			if kv == nil || kv.Key == "" {
+				continue
+			}
+			tag := &corev2.MetricTag{Name: kv.Key, Value: anyValueString(kv.Value)}
+			if i, ok := index[kv.Key]; ok {
+				tags[i] = tag
+				continue
+			}
+			index[kv.Key] = len(tags)
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// anyValueString formats an attribute value as a tag value. Arrays and lists
+// of attributes are formatted as JSON, and bytes with base64.
+func anyValueString(v *commonpb.AnyValue) string {
+	switch value := anyValueInterface(v).(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case bool:
+		return strconv.FormatBool(value)
+	case int64:
+		return strconv.FormatInt(value, 10)
+	case float64:
+		return formatFloat(value)
+	default:
+		b, err := json.Marshal(value)
+		if err != nil {
+			return ""
+		}
+		return string(b)
+	}
+}
+
+func anyValueInterface(v *commonpb.AnyValue) interface{} {
+	switch value := v.GetValue().(type) {
+	case *commonpb.AnyValue_StringValue:
+		return value.StringValue
+	case *commonpb.AnyValue_BoolValue:
+		return value.BoolValue
+	case *commonpb.AnyValue_IntValue:
+		return value.IntValue
+	case *commonpb.AnyValue_DoubleValue:
+		if math.IsNaN(value.DoubleValue) || math.IsInf(value.DoubleValue, 0) {
+			return formatFloat(value.DoubleValue)
+		}
+		return value.DoubleValue
+	case *commonpb.AnyValue_ArrayValue:
+		values := make([]interface{}, 0, len(value.ArrayValue.GetValues()))
+		for _, v := range value.ArrayValue.GetValues() {
+			values = append(values, anyValueInterface(v))
+		}
+		return values
+	case *commonpb.AnyValue_KvlistValue:
+		values := make(map[string]interface{}, len(value.KvlistValue.GetValues()))
+		for _, kv := range value.KvlistValue.GetValues() {
+			if kv != nil {
+				values[kv.Key] = anyValueInterface(kv.Value)
+			}
+		}
+		return values
+	case *commonpb.AnyValue_BytesValue:
+		return base64.StdEncoding.EncodeToString(value.BytesValue)
+	}
+	return nil
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
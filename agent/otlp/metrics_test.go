@@ -0,0 +1,95 @@
+package otlp
+
+import (
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+)
+
+func TestMetricPoints(t *testing.T) {
+	req, err := UnmarshalRequestJSON([]byte(`{
+		"resourceMetrics": [{
+			"resource": {"attributes": [
+				{"key": "service.name", "value": {"stringValue": "web"}},
+				{"key": "host", "value": {"stringValue": "a"}}
+			]},
+			"scopeMetrics": [{
+				"metrics": [
+					{"name": "cpu", "gauge": {"dataPoints": [
+						{"asDouble": 0.5, "timeUnixNano": "1000", "attributes": [{"key": "host", "value": {"stringValue": "b"}}]}
+					]}},
+					{"name": "requests", "sum": {"dataPoints": [{"asInt": "42"}]}},
+					{"name": "latency", "histogram": {"dataPoints": [
+						{"count": "6", "sum": 4.2, "bucketCounts": ["1", "2", "3"], "explicitBounds": [0.1, 1], "timeUnixNano": "3000"},
+						{"count": "1", "bucketCounts": ["1"], "explicitBounds": [0.1, 1]}
+					]}},
+					{"name": "summary", "summary": {"dataPoints": [{}]}},
+					{"gauge": {"dataPoints": [{"asInt": "1"}]}}
+				]
+			}]
+		}]
+	}`))
+	require.NoError(t, err)
+
+	now := time.Unix(10, 0)
+	points, rejected := MetricPoints(req, now)
+	assert.Equal(t, int64(3), rejected)
+
+	resourceTags := []*corev2.MetricTag{{Name: "service.name", Value: "web"}, {Name: "host", Value: "a"}}
+	bucketTags := func(le string) []*corev2.MetricTag {
+		return append(append([]*corev2.MetricTag{}, resourceTags...), &corev2.MetricTag{Name: "le", Value: le})
+	}
+	want := []*corev2.MetricPoint{
+		{Name: "cpu", Value: 0.5, Timestamp: 1000, Tags: []*corev2.MetricTag{{Name: "service.name", Value: "web"}, {Name: "host", Value: "b"}}},
+		{Name: "requests", Value: 42, Timestamp: now.UnixNano(), Tags: resourceTags},
+		{Name: "latency.count", Value: 6, Timestamp: 3000, Tags: resourceTags},
+		{Name: "latency.sum", Value: 4.2, Timestamp: 3000, Tags: resourceTags},
+		{Name: "latency.bucket", Value: 1, Timestamp: 3000, Tags: bucketTags("0.1")},
+		{Name: "latency.bucket", Value: 3, Timestamp: 3000, Tags: bucketTags("1")},
+		{Name: "latency.bucket", Value: 6, Timestamp: 3000, Tags: bucketTags("+Inf")},
+	}
+	assert.Equal(t, want, points)
+}
+
+func TestAnyValueString(t *testing.T) {
+	str := func(s string) *commonpb.AnyValue {
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: s}}
+	}
+	boolean := &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: true}}
+	integer := &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: -3}}
+	tests := []struct {
+		name  string
+		value *commonpb.AnyValue
+		want  string
+	}{
+		{"nil", nil, ""},
+		{"string", str("foo"), "foo"},
+		{"bool", boolean, "true"},
+		{"int", integer, "-3"},
+		{"double", &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: 1.5}}, "1.5"},
+		{"bytes", &commonpb.AnyValue{Value: &commonpb.AnyValue_BytesValue{BytesValue: []byte("foo")}}, "Zm9v"},
+		{
+			"array",
+			&commonpb.AnyValue{Value: &commonpb.AnyValue_ArrayValue{ArrayValue: &commonpb.ArrayValue{
+				Values: []*commonpb.AnyValue{str("foo"), integer},
+			}}},
+			`["foo",-3]`,
+		},
+		{
+			"kvlist",
+			&commonpb.AnyValue{Value: &commonpb.AnyValue_KvlistValue{KvlistValue: &commonpb.KeyValueList{
+				Values: []*commonpb.KeyValue{{Key: "a", Value: boolean}},
+			}}},
+			`{"a":true}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, anyValueString(tt.value))
+		})
+	}
+}
@@ -0,0 +1,58 @@
+// Package otlp implements the receivers of the metrics exported with the
+// OpenTelemetry protocol (OTLP), over gRPC and HTTP, and the conversion of the
+// metrics to Sensu metric points. The messages are the ones generated by
+// go.opentelemetry.io/proto/otlp.
+package otlp
+
+import (
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// UnmarshalRequest decodes a protobuf encoded export request.
+func UnmarshalRequest(b []byte) (*colmetricspb.ExportMetricsServiceRequest, error) {
+	req := new(colmetricspb.ExportMetricsServiceRequest)
+	if err := proto.Unmarshal(b, req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// UnmarshalRequestJSON decodes a JSON encoded export request. Unknown fields
+// are skipped, as required by the OTLP/JSON specification.
+func UnmarshalRequestJSON(b []byte) (*colmetricspb.ExportMetricsServiceRequest, error) {
+	req := new(colmetricspb.ExportMetricsServiceRequest)
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(b, req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// MarshalResponse encodes an export response with protobuf.
+func MarshalResponse(resp *colmetricspb.ExportMetricsServiceResponse) []byte {
+	b, _ := proto.Marshal(resp)
+	return b
+}
+
+// MarshalResponseJSON encodes an export response with JSON.
+func MarshalResponseJSON(resp *colmetricspb.ExportMetricsServiceResponse) []byte {
+	b, _ := protojson.Marshal(resp)
+	return b
+}
+
+// MarshalStatus encodes the google.rpc.Status message of an error response
+// with protobuf.
+func MarshalStatus(code codes.Code, message string) []byte {
+	b, _ := proto.Marshal(status.New(code, message).Proto())
+	return b
+}
+
+// MarshalStatusJSON encodes the google.rpc.Status message of an error
+// response with JSON.
+func MarshalStatusJSON(code codes.Code, message string) []byte {
+	b, _ := protojson.Marshal(status.New(code, message).Proto())
+	return b
+}
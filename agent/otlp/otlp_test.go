@@ -0,0 +1,122 @@
+package otlp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+)
+
+func stringAttribute(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{
+		Key:   key,
+		Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}},
+	}
+}
+
+func float64Ptr(f float64) *float64 {
+	return &f
+}
+
+// testExportRequest returns a request with a gauge, a sum, a histogram and a
+// summary.
+func testExportRequest() *colmetricspb.ExportMetricsServiceRequest {
+	return &colmetricspb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*metricspb.ResourceMetrics{{
+			Resource: &resourcepb.Resource{Attributes: []*commonpb.KeyValue{stringAttribute("service.name", "web")}},
+			ScopeMetrics: []*metricspb.ScopeMetrics{{
+				Scope: &commonpb.InstrumentationScope{Name: "scope"},
+				Metrics: []*metricspb.Metric{
+					{Name: "cpu", Data: &metricspb.Metric_Gauge{Gauge: &metricspb.Gauge{
+						DataPoints: []*metricspb.NumberDataPoint{{
+							Attributes:   []*commonpb.KeyValue{stringAttribute("core", "0")},
+							TimeUnixNano: 1000,
+							Value:        &metricspb.NumberDataPoint_AsDouble{AsDouble: 0.5},
+						}},
+					}}},
+					{Name: "requests", Data: &metricspb.Metric_Sum{Sum: &metricspb.Sum{
+						DataPoints: []*metricspb.NumberDataPoint{{
+							TimeUnixNano: 2000,
+							Value:        &metricspb.NumberDataPoint_AsInt{AsInt: 42},
+						}},
+						IsMonotonic: true,
+					}}},
+					{Name: "latency", Unit: "s", Data: &metricspb.Metric_Histogram{Histogram: &metricspb.Histogram{
+						DataPoints: []*metricspb.HistogramDataPoint{{
+							TimeUnixNano:   3000,
+							Count:          6,
+							Sum:            float64Ptr(4.2),
+							BucketCounts:   []uint64{1, 2, 3},
+							ExplicitBounds: []float64{0.1, 1},
+							Min:            float64Ptr(0.01),
+							Max:            float64Ptr(2),
+						}},
+					}}},
+					{Name: "summary", Data: &metricspb.Metric_Summary{Summary: &metricspb.Summary{
+						DataPoints: []*metricspb.SummaryDataPoint{{}, {}},
+					}}},
+				},
+			}},
+		}},
+	}
+}
+
+// testRequest returns the protobuf encoding of testExportRequest.
+func testRequest() []byte {
+	b, err := proto.Marshal(testExportRequest())
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestUnmarshalRequest(t *testing.T) {
+	req, err := UnmarshalRequest(testRequest())
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(testExportRequest(), req))
+
+	_, err = UnmarshalRequest([]byte{0x0a, 0xff})
+	assert.Error(t, err)
+}
+
+func TestUnmarshalRequestJSON(t *testing.T) {
+	// Unknown fields are skipped, and 64 bits integers can be strings
+	req, err := UnmarshalRequestJSON([]byte(`{
+		"resourceMetrics": [{
+			"unknown": true,
+			"scopeMetrics": [{"metrics": [
+				{"name": "requests", "sum": {"dataPoints": [{"asInt": "42", "timeUnixNano": "2000"}]}}
+			]}]
+		}]
+	}`))
+	require.NoError(t, err)
+	metric := req.ResourceMetrics[0].ScopeMetrics[0].Metrics[0]
+	assert.Equal(t, "requests", metric.Name)
+	assert.Equal(t, int64(42), metric.GetSum().DataPoints[0].GetAsInt())
+	assert.Equal(t, uint64(2000), metric.GetSum().DataPoints[0].TimeUnixNano)
+
+	_, err = UnmarshalRequestJSON([]byte(`{"resourceMetrics": {}}`))
+	assert.Error(t, err)
+}
+
+func TestMarshalResponse(t *testing.T) {
+	assert.Empty(t, MarshalResponse(&colmetricspb.ExportMetricsServiceResponse{}))
+
+	resp := &colmetricspb.ExportMetricsServiceResponse{
+		PartialSuccess: &colmetricspb.ExportMetricsPartialSuccess{RejectedDataPoints: 2, ErrorMessage: "rejected"},
+	}
+	decoded := new(colmetricspb.ExportMetricsServiceResponse)
+	require.NoError(t, proto.Unmarshal(MarshalResponse(resp), decoded))
+	assert.True(t, proto.Equal(resp, decoded))
+}
+
+func TestMarshalStatus(t *testing.T) {
+	assert.NotEmpty(t, MarshalStatus(codes.InvalidArgument, "invalid"))
+	assert.Contains(t, string(MarshalStatusJSON(codes.InvalidArgument, "invalid")), `"invalid"`)
+}
@@ -0,0 +1,81 @@
+package agent
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/agent/otlp"
+	"github.com/sensu/sensu-go/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const otlpTestRequest = `{
+	"resourceMetrics": [{
+		"resource": {"attributes": [{"key": "service.name", "value": {"stringValue": "web"}}]},
+		"scopeMetrics": [{
+			"metrics": [
+				{"name": "requests", "sum": {"dataPoints": [{"asInt": "42", "timeUnixNano": "1000000000"}]}},
+				{"name": "latency", "summary": {"dataPoints": [{}]}}
+			]
+		}]
+	}]
+}`
+
+func TestOTLPMetrics(t *testing.T) {
+	config, cleanup := FixtureConfig()
+	defer cleanup()
+	config.OTLP.Handlers = []string{"influxdb"}
+	agent, err := NewAgent(config)
+	require.NoError(t, err)
+	agent.sendq = make(chan *transport.Message, 1)
+
+	router := mux.NewRouter()
+	registerRoutes(agent, router)
+
+	r, err := http.NewRequest(http.MethodPost, otlp.MetricsPath, bytes.NewBufferString(otlpTestRequest))
+	require.NoError(t, err)
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"partialSuccess":{"rejectedDataPoints":"1","errorMessage":"unsupported or invalid data points were rejected"}}`, w.Body.String())
+
+	msg := <-agent.sendq
+	assert.Equal(t, transport.MessageTypeEvent, msg.Type)
+	var event corev2.Event
+	require.NoError(t, agent.unmarshal(msg.Payload, &event))
+	assert.Equal(t, agent.config.AgentName, event.Entity.Name)
+	require.NotNil(t, event.Metrics)
+	assert.Equal(t, []string{"influxdb"}, event.Metrics.Handlers)
+	require.Len(t, event.Metrics.Points, 1)
+	point := event.Metrics.Points[0]
+	assert.Equal(t, "requests", point.Name)
+	assert.Equal(t, float64(42), point.Value)
+	assert.Equal(t, int64(1000000000), point.Timestamp)
+	assert.Equal(t, []*corev2.MetricTag{{Name: "service.name", Value: "web"}}, point.Tags)
+}
+
+func TestOTLPMetricsDisabled(t *testing.T) {
+	config, cleanup := FixtureConfig()
+	defer cleanup()
+	config.OTLP.Disable = true
+	agent, err := NewAgent(config)
+	require.NoError(t, err)
+
+	router := mux.NewRouter()
+	registerRoutes(agent, router)
+
+	r, err := http.NewRequest(http.MethodPost, otlp.MetricsPath, bytes.NewBufferString(otlpTestRequest))
+	require.NoError(t, err)
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,605 @@
+package agent
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/prometheus/client_golang/prometheus"
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/transport"
+	"github.com/sirupsen/logrus"
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/time/rate"
+)
+
+const (
+	OutboxEvents         = "sensu_go_agent_outbox_events"
+	OutboxBytes          = "sensu_go_agent_outbox_bytes"
+	OutboxEventsDropped  = "sensu_go_agent_outbox_events_dropped"
+	OutboxEventsReplayed = "sensu_go_agent_outbox_events_replayed"
+)
+
+// The reasons why events are dropped from the outbox
+const (
+	outboxDropMaxBytes = "max_bytes"
+	outboxDropMaxAge   = "max_age"
+	outboxDropInvalid  = "invalid"
+)
+
+// defaultOutboxEntriesLimit is the default number of events returned by the
+// outbox API
+const defaultOutboxEntriesLimit = 100
+
+var (
+	outboxEvents = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: OutboxEvents,
+			Help: "The number of events in the outbox",
+		},
+	)
+
+	outboxBytes = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: OutboxBytes,
+			Help: "The size, in bytes, of the compressed events in the outbox",
+		},
+	)
+
+	outboxEventsDropped = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: OutboxEventsDropped,
+			Help: "The total number of events dropped from the outbox",
+		},
+		[]string{"reason"},
+	)
+
+	outboxEventsReplayed = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: OutboxEventsReplayed,
+			Help: "The total number of events replayed from the outbox",
+		},
+	)
+)
+
+func init() {
+	_ = prometheus.Register(outboxEvents)
+	_ = prometheus.Register(outboxBytes)
+	_ = prometheus.Register(outboxEventsDropped)
+	_ = prometheus.Register(outboxEventsReplayed)
+}
+
+var outboxBucket = []byte("outbox")
+
+// outboxEntry is an event kept in the outbox.
+type outboxEntry struct {
+	ID uint64 `json:"id"`
+
+	// Check is the name of the check of the event, if any. The events of a
+	// check are sent in order.
+	Check string `json:"check"`
+
+	// ContentType is the serialization of the event.
+	ContentType string `json:"content_type"`
+
+	// Queued is the time at which the event was added to the outbox.
+	Queued time.Time `json:"queued"`
+
+	// Size is the size of the compressed event, in bytes.
+	Size int `json:"size"`
+
+	// Payload is the event, compressed with gzip.
+	Payload []byte `json:"payload,omitempty"`
+}
+
+// outbox is a bounded queue of events, stored in a bolt database. It keeps the
+// events produced while the agent is disconnected from the backend, or that
+// could not be sent to it, until they can be replayed. Events older than
+// maxAge are dropped, and the oldest events are dropped when the compressed
+// events grow larger than maxBytes. Either bound is disabled when 0.
+type outbox struct {
+	db       *bolt.DB
+	maxBytes int64
+	maxAge   time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	count   int
+	bytes   int64
+	checks  map[string]int
+	changed chan struct{}
+}
+
+func newOutbox(path string, config *OutboxConfig) (*outbox, error) {
+	if err := os.MkdirAll(path, 0744|os.ModeDir); err != nil {
+		return nil, fmt.Errorf("could not create directory for outbox (%s): %s", path, err)
+	}
+	outboxPath := filepath.Join(path, "outbox.db")
+	db, err := bolt.Open(outboxPath, 0600, &bolt.Options{Timeout: 60 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("could not open outbox (%s): %s (is sensu-agent already running?)", outboxPath, err)
+	}
+	o := &outbox{
+		db:       db,
+		maxBytes: config.MaxBytes,
+		maxAge:   config.MaxAge,
+		now:      time.Now,
+		checks:   make(map[string]int),
+		changed:  make(chan struct{}),
+	}
+
+	// Load the events kept from a previous run of the agent
+	err = db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(outboxBucket)
+		if err != nil {
+			return err
+		}
+		var invalid [][]byte
+		err = bucket.ForEach(func(k, v []byte) error {
+			var entry outboxEntry
+			if err := json.Unmarshal(v, &entry); err != nil {
+				logger.WithError(err).Error("dropping invalid event from the outbox")
+				outboxEventsDropped.WithLabelValues(outboxDropInvalid).Inc()
+				invalid = append(invalid, k)
+				return nil
+			}
+			o.add(&entry)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, k := range invalid {
+			if err := bucket.Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("could not load outbox (%s): %s", outboxPath, err)
+	}
+	if o.count > 0 {
+		logger.WithField("events", o.count).Info("loaded events from the outbox")
+	}
+	return o, nil
+}
+
+func outboxKey(id uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, id)
+	return key
+}
+
+// add and sub account for an entry being added to or removed from the outbox.
+// They must be called with o.mu held, except while loading the outbox.
+func (o *outbox) add(entry *outboxEntry) {
+	o.count++
+	o.bytes += int64(entry.Size)
+	o.checks[entry.Check]++
+	outboxEvents.Set(float64(o.count))
+	outboxBytes.Set(float64(o.bytes))
+}
+
+func (o *outbox) sub(entry *outboxEntry) {
+	o.count--
+	o.bytes -= int64(entry.Size)
+	if o.checks[entry.Check]--; o.checks[entry.Check] <= 0 {
+		delete(o.checks, entry.Check)
+	}
+	outboxEvents.Set(float64(o.count))
+	outboxBytes.Set(float64(o.bytes))
+}
+
+// Close closes the outbox database.
+func (o *outbox) Close() error {
+	return o.db.Close()
+}
+
+// len returns the number of events in the outbox.
+func (o *outbox) len() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.count
+}
+
+// size returns the size of the compressed events in the outbox, in bytes.
+func (o *outbox) size() int64 {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.bytes
+}
+
+// pending returns the number of events of a check in the outbox.
+func (o *outbox) pending(check string) int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.checks[check]
+}
+
+// changes returns a channel that is closed once an event is added to the
+// outbox.
+func (o *outbox) changes() <-chan struct{} {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.changed
+}
+
+// push adds an event to the outbox, dropping the oldest events if needed to
+// stay within its bounds.
+func (o *outbox) push(check, contentType string, payload []byte) error {
+	entry := &outboxEntry{
+		Check:       check,
+		ContentType: contentType,
+		Queued:      o.now(),
+		Payload:     compressMessage(payload),
+	}
+	entry.Size = len(entry.Payload)
+	if o.maxBytes > 0 && int64(entry.Size) > o.maxBytes {
+		outboxEventsDropped.WithLabelValues(outboxDropMaxBytes).Inc()
+		return fmt.Errorf("event is larger than the outbox (%d bytes)", o.maxBytes)
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var dropped map[string][]*outboxEntry
+	err := o.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(outboxBucket)
+		var err error
+		if dropped, err = o.trim(bucket, int64(entry.Size)); err != nil {
+			return err
+		}
+		if entry.ID, err = bucket.NextSequence(); err != nil {
+			return err
+		}
+		value, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		return bucket.Put(outboxKey(entry.ID), value)
+	})
+	if err != nil {
+		return fmt.Errorf("could not add event to the outbox: %s", err)
+	}
+	o.dropped(dropped)
+	o.add(entry)
+	close(o.changed)
+	o.changed = make(chan struct{})
+	return nil
+}
+
+// trim deletes the expired events from the outbox, and the oldest events until
+// there is room for incoming bytes. It returns the deleted events by reason,
+// which must be accounted for with dropped once the transaction is committed.
+func (o *outbox) trim(bucket *bolt.Bucket, incoming int64) (map[string][]*outboxEntry, error) {
+	dropped := make(map[string][]*outboxEntry)
+	var keys [][]byte
+	bytes := o.bytes
+	cursor := bucket.Cursor()
+	for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+		entry := new(outboxEntry)
+		reason := outboxDropInvalid
+		if err := json.Unmarshal(v, entry); err == nil {
+			if o.maxAge > 0 && o.now().Sub(entry.Queued) > o.maxAge {
+				reason = outboxDropMaxAge
+			} else if o.maxBytes > 0 && bytes+incoming > o.maxBytes {
+				reason = outboxDropMaxBytes
+			} else {
+				break
+			}
+		}
+		keys = append(keys, k)
+		bytes -= int64(entry.Size)
+		dropped[reason] = append(dropped[reason], entry)
+	}
+	for _, k := range keys {
+		if err := bucket.Delete(k); err != nil {
+			return nil, err
+		}
+	}
+	return dropped, nil
+}
+
+// dropped accounts for the events deleted by trim. It must be called with o.mu
+// held.
+func (o *outbox) dropped(dropped map[string][]*outboxEntry) {
+	for reason, entries := range dropped {
+		logger.WithFields(logrus.Fields{
+			"events": len(entries),
+			"reason": reason,
+		}).Warning("dropped events from the outbox")
+		outboxEventsDropped.WithLabelValues(reason).Add(float64(len(entries)))
+		for _, entry := range entries {
+			if reason != outboxDropInvalid {
+				o.sub(entry)
+			}
+		}
+	}
+}
+
+// next returns the oldest event of the outbox following the event identified
+// by after, or nil if there is none. Expired events are dropped.
+func (o *outbox) next(after uint64) (*outboxEntry, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var next *outboxEntry
+	var dropped map[string][]*outboxEntry
+	err := o.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(outboxBucket)
+		var err error
+		if dropped, err = o.trim(bucket, 0); err != nil {
+			return err
+		}
+		k, v := bucket.Cursor().Seek(outboxKey(after + 1))
+		if k == nil {
+			return nil
+		}
+		next = new(outboxEntry)
+		return json.Unmarshal(v, next)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not read the outbox: %s", err)
+	}
+	o.dropped(dropped)
+	return next, nil
+}
+
+// remove deletes an event from the outbox, once it has been sent, or dropped
+// for reason if reason is not empty.
+func (o *outbox) remove(entry *outboxEntry, reason string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var deleted bool
+	err := o.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(outboxBucket)
+		key := outboxKey(entry.ID)
+		if bucket.Get(key) == nil {
+			// The event was already dropped by trim
+			return nil
+		}
+		deleted = true
+		return bucket.Delete(key)
+	})
+	if err != nil {
+		return fmt.Errorf("could not remove event from the outbox: %s", err)
+	}
+	if !deleted {
+		return nil
+	}
+	o.sub(entry)
+	if reason == "" {
+		outboxEventsReplayed.Inc()
+	} else {
+		outboxEventsDropped.WithLabelValues(reason).Inc()
+	}
+	return nil
+}
+
+// entries returns up to limit of the oldest events of the outbox, without
+// their payload.
+func (o *outbox) entries(limit int) ([]*outboxEntry, error) {
+	entries := []*outboxEntry{}
+	err := o.db.View(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(outboxBucket).Cursor()
+		for k, v := cursor.First(); k != nil && len(entries) < limit; k, v = cursor.Next() {
+			entry := new(outboxEntry)
+			if err := json.Unmarshal(v, entry); err != nil {
+				continue
+			}
+			entry.Payload = nil
+			entries = append(entries, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not read the outbox: %s", err)
+	}
+	return entries, nil
+}
+
+// serialization returns the content type of the messages currently sent to
+// the backend.
+func (a *Agent) serialization() string {
+	if a.contentType == "" {
+		return JSONSerializationHeader
+	}
+	return a.contentType
+}
+
+// queueMessage adds an event to the outbox instead of sending it, while the
+// agent is disconnected from the backend, or while older events of the same
+// check are still in the outbox so that the events of a check are sent in
+// order. It returns false if the message must be sent.
+func (a *Agent) queueMessage(msg *transport.Message) bool {
+	if a.outbox == nil || msg.Type != transport.MessageTypeEvent || msg.SendCallback != nil {
+		return false
+	}
+	connected := a.Connected()
+	if connected && a.outbox.len() == 0 {
+		return false
+	}
+	check := a.eventCheckName(msg.Payload)
+	if connected && a.outbox.pending(check) == 0 {
+		return false
+	}
+	return a.pushOutbox(check, msg)
+}
+
+// requeueMessage adds an event that could not be sent to the backend to the
+// outbox. It returns false if the message can't be kept in the outbox.
+func (a *Agent) requeueMessage(msg *transport.Message) bool {
+	if a.outbox == nil || msg.Type != transport.MessageTypeEvent || msg.SendCallback != nil {
+		return false
+	}
+	return a.pushOutbox(a.eventCheckName(msg.Payload), msg)
+}
+
+func (a *Agent) pushOutbox(check string, msg *transport.Message) bool {
+	if err := a.outbox.push(check, a.serialization(), msg.Payload); err != nil {
+		logger.WithError(err).Error("error adding event to the outbox")
+		return false
+	}
+	logger.WithField("check", check).Debug("event added to the outbox")
+	return true
+}
+
+// eventCheckName returns the name of the check of a serialized event, or an
+// empty string if the event has no check.
+func (a *Agent) eventCheckName(payload []byte) string {
+	var event corev2.Event
+	if err := a.unmarshal(payload, &event); err != nil || event.Check == nil {
+		return ""
+	}
+	return event.Check.Name
+}
+
+// replayOutbox sends the events of the outbox to ch, oldest first and rate
+// limited, until ctx is done. The events are removed from the outbox by
+// sendLoop once they are sent, and are replayed again on the next connection
+// otherwise.
+func (a *Agent) replayOutbox(ctx context.Context, ch chan<- *outboxEntry) {
+	limit := a.config.Outbox.ReplayRateLimit
+	if limit == 0 {
+		limit = rate.Inf
+	}
+	burst := a.config.Outbox.ReplayBurstLimit
+	if burst < 1 {
+		burst = 1
+	}
+	limiter := rate.NewLimiter(limit, burst)
+
+	var after uint64
+	for {
+		changed := a.outbox.changes()
+		entry, err := a.outbox.next(after)
+		if err != nil {
+			logger.WithError(err).Error("error replaying the outbox")
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+				continue
+			}
+		}
+		if entry == nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-changed:
+				continue
+			}
+		}
+		if err := limiter.Wait(ctx); err != nil {
+			// context canceled
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case ch <- entry:
+			after = entry.ID
+		}
+	}
+}
+
+// outboxMessage returns the message of an event of the outbox, serialized
+// like the messages currently sent to the backend.
+func (a *Agent) outboxMessage(entry *outboxEntry) (*transport.Message, error) {
+	payload := decompressMessage(entry.Payload)
+	if payload == nil {
+		return nil, errors.New("could not decompress event")
+	}
+	if contentType := a.serialization(); entry.ContentType != contentType {
+		unmarshal := UnmarshalJSON
+		if entry.ContentType == ProtobufSerializationHeader {
+			unmarshal = proto.Unmarshal
+		}
+		var event corev2.Event
+		if err := unmarshal(payload, &event); err != nil {
+			return nil, fmt.Errorf("could not decode event: %s", err)
+		}
+		var err error
+		if payload, err = a.marshal(&event); err != nil {
+			return nil, fmt.Errorf("could not encode event: %s", err)
+		}
+	}
+	return &transport.Message{
+		Type:    transport.MessageTypeEvent,
+		Payload: payload,
+	}, nil
+}
+
+// sendOutboxEntry sends an event of the outbox to the backend, and removes it
+// from the outbox once sent.
+func (a *Agent) sendOutboxEntry(conn transport.Transport, entry *outboxEntry) error {
+	msg, err := a.outboxMessage(entry)
+	if err != nil {
+		logger.WithError(err).Error("dropping invalid event from the outbox")
+		if err := a.outbox.remove(entry, outboxDropInvalid); err != nil {
+			logger.WithError(err).Error("error dropping event from the outbox")
+		}
+		return nil
+	}
+	if err := conn.Send(msg); err != nil {
+		return err
+	}
+	messagesSent.WithLabelValues().Inc()
+	if err := a.outbox.remove(entry, ""); err != nil {
+		logger.WithError(err).Error("error removing replayed event from the outbox")
+	}
+	return nil
+}
+
+// outboxStatus is the response of the outbox API.
+type outboxStatus struct {
+	Events   int            `json:"events"`
+	Bytes    int64          `json:"bytes"`
+	MaxBytes int64          `json:"max_bytes"`
+	MaxAge   string         `json:"max_age"`
+	Entries  []*outboxEntry `json:"entries"`
+}
+
+// outboxShow returns the state of the outbox and its oldest events, up to the
+// limit query parameter.
+func outboxShow(a *Agent) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if a.outbox == nil {
+			http.Error(w, "the outbox is disabled", http.StatusNotFound)
+			return
+		}
+		limit := defaultOutboxEntriesLimit
+		if value := r.URL.Query().Get("limit"); value != "" {
+			var err error
+			if limit, err = strconv.Atoi(value); err != nil || limit < 0 {
+				http.Error(w, fmt.Sprintf("invalid limit %q", value), http.StatusBadRequest)
+				return
+			}
+		}
+		entries, err := a.outbox.entries(limit)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		status := outboxStatus{
+			Events:   a.outbox.len(),
+			Bytes:    a.outbox.size(),
+			MaxBytes: a.outbox.maxBytes,
+			MaxAge:   a.outbox.maxAge.String(),
+			Entries:  entries,
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(status); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/testing/mocktransport"
+	"github.com/sensu/sensu-go/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestOutbox(t *testing.T, config *OutboxConfig) *outbox {
+	t.Helper()
+	o, err := newOutbox(t.TempDir(), config)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = o.Close() })
+	return o
+}
+
+func TestOutbox(t *testing.T) {
+	dir := t.TempDir()
+	o, err := newOutbox(dir, &OutboxConfig{})
+	require.NoError(t, err)
+
+	require.NoError(t, o.push("check1", JSONSerializationHeader, []byte("a")))
+	require.NoError(t, o.push("check2", JSONSerializationHeader, []byte("b")))
+	require.NoError(t, o.push("check1", JSONSerializationHeader, []byte("c")))
+	assert.Equal(t, 3, o.len())
+	assert.Equal(t, 2, o.pending("check1"))
+	assert.Equal(t, 1, o.pending("check2"))
+
+	first, err := o.next(0)
+	require.NoError(t, err)
+	require.NotNil(t, first)
+	assert.Equal(t, "check1", first.Check)
+	assert.Equal(t, []byte("a"), decompressMessage(first.Payload))
+
+	second, err := o.next(first.ID)
+	require.NoError(t, err)
+	require.NotNil(t, second)
+	assert.Equal(t, []byte("b"), decompressMessage(second.Payload))
+
+	require.NoError(t, o.remove(first, ""))
+	assert.Equal(t, 2, o.len())
+	assert.Equal(t, 1, o.pending("check1"))
+	require.NoError(t, o.Close())
+
+	// The events are kept across restarts
+	o, err = newOutbox(dir, &OutboxConfig{})
+	require.NoError(t, err)
+	defer o.Close()
+	assert.Equal(t, 2, o.len())
+	assert.Equal(t, 1, o.pending("check1"))
+	next, err := o.next(0)
+	require.NoError(t, err)
+	require.NotNil(t, next)
+	assert.Equal(t, second.ID, next.ID)
+
+	entries, err := o.entries(1)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, second.ID, entries[0].ID)
+	assert.Nil(t, entries[0].Payload)
+}
+
+func TestOutboxMaxBytes(t *testing.T) {
+	size := int64(len(compressMessage([]byte("a"))))
+	o := newTestOutbox(t, &OutboxConfig{MaxBytes: 2 * size})
+
+	require.NoError(t, o.push("check", JSONSerializationHeader, []byte("a")))
+	require.NoError(t, o.push("check", JSONSerializationHeader, []byte("b")))
+	require.NoError(t, o.push("check", JSONSerializationHeader, []byte("c")))
+	assert.Equal(t, 2, o.len())
+	assert.Equal(t, 2*size, o.size())
+
+	next, err := o.next(0)
+	require.NoError(t, err)
+	require.NotNil(t, next)
+	assert.Equal(t, []byte("b"), decompressMessage(next.Payload))
+
+	assert.Error(t, o.push("check", JSONSerializationHeader, make([]byte, 1024*1024)))
+}
+
+func TestOutboxMaxAge(t *testing.T) {
+	o := newTestOutbox(t, &OutboxConfig{MaxAge: time.Minute})
+	now := time.Now()
+	o.now = func() time.Time { return now }
+
+	require.NoError(t, o.push("check", JSONSerializationHeader, []byte("a")))
+	now = now.Add(30 * time.Second)
+	require.NoError(t, o.push("check", JSONSerializationHeader, []byte("b")))
+	now = now.Add(45 * time.Second)
+
+	next, err := o.next(0)
+	require.NoError(t, err)
+	require.NotNil(t, next)
+	assert.Equal(t, []byte("b"), decompressMessage(next.Payload))
+	assert.Equal(t, 1, o.len())
+
+	now = now.Add(time.Minute)
+	next, err = o.next(0)
+	require.NoError(t, err)
+	assert.Nil(t, next)
+	assert.Equal(t, 0, o.len())
+}
+
+func newOutboxTestAgent(t *testing.T) *Agent {
+	t.Helper()
+	config, cleanup := FixtureConfig()
+	t.Cleanup(cleanup)
+	config.Outbox.Disable = false
+	agent, err := NewAgent(config)
+	require.NoError(t, err)
+	require.NotNil(t, agent.outbox)
+	t.Cleanup(func() { _ = agent.outbox.Close() })
+	agent.sendq = make(chan *transport.Message, 10)
+	return agent
+}
+
+func eventMessage(t *testing.T, agent *Agent, check string, status uint32) *transport.Message {
+	t.Helper()
+	event := corev2.FixtureEvent(agent.config.AgentName, check)
+	event.Check.Status = status
+	payload, err := agent.marshal(event)
+	require.NoError(t, err)
+	return &transport.Message{Type: transport.MessageTypeEvent, Payload: payload}
+}
+
+func TestAgentQueueMessage(t *testing.T) {
+	agent := newOutboxTestAgent(t)
+
+	// Events are queued in the outbox while the agent is disconnected
+	agent.sendMessage(eventMessage(t, agent, "check1", 0))
+	agent.sendMessage(&transport.Message{Type: transport.MessageTypeKeepalive})
+	assert.Equal(t, 1, agent.outbox.len())
+	assert.Equal(t, 1, agent.outbox.pending("check1"))
+	require.Len(t, agent.sendq, 1)
+	assert.Equal(t, transport.MessageTypeKeepalive, (<-agent.sendq).Type)
+
+	// Once connected, the events of checks with events in the outbox are still
+	// queued, to preserve their order
+	agent.connected = true
+	agent.sendMessage(eventMessage(t, agent, "check1", 1))
+	agent.sendMessage(eventMessage(t, agent, "check2", 0))
+	assert.Equal(t, 2, agent.outbox.len())
+	assert.Equal(t, 2, agent.outbox.pending("check1"))
+	require.Len(t, agent.sendq, 1)
+	assert.Equal(t, "check2", agent.eventCheckName((<-agent.sendq).Payload))
+}
+
+func TestSendLoopReplaysOutbox(t *testing.T) {
+	agent := newOutboxTestAgent(t)
+	agent.config.KeepaliveInterval = 3600
+	for status := uint32(0); status < 3; status++ {
+		require.NoError(t, agent.outbox.push("check", JSONSerializationHeader, eventMessage(t, agent, "check", status).Payload))
+	}
+
+	sent := make(chan *transport.Message, 10)
+	conn := new(mocktransport.MockTransport)
+	conn.On("Send", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		sent <- args.Get(0).(*transport.Message)
+	})
+	conn.On("Close").Return(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error)
+	go func() {
+		done <- agent.sendLoop(ctx, cancel, conn)
+	}()
+
+	assert.Equal(t, transport.MessageTypeKeepalive, (<-sent).Type)
+	for status := uint32(0); status < 3; status++ {
+		msg := <-sent
+		var event corev2.Event
+		require.NoError(t, json.Unmarshal(msg.Payload, &event))
+		assert.Equal(t, status, event.Check.Status)
+	}
+	cancel()
+	require.NoError(t, <-done)
+	assert.Equal(t, 0, agent.outbox.len())
+}
+
+func TestSendLoopRequeuesFailedEvents(t *testing.T) {
+	agent := newOutboxTestAgent(t)
+	agent.config.KeepaliveInterval = 3600
+
+	conn := new(mocktransport.MockTransport)
+	conn.On("Send", mock.MatchedBy(func(msg *transport.Message) bool {
+		return msg.Type == transport.MessageTypeKeepalive
+	})).Return(nil)
+	conn.On("Send", mock.Anything).Return(transport.ClosedError{Message: "closed"})
+
+	agent.sendq <- eventMessage(t, agent, "check", 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	assert.Error(t, agent.sendLoop(ctx, cancel, conn))
+	assert.Equal(t, 1, agent.outbox.pending("check"))
+}
+
+func TestOutboxShow(t *testing.T) {
+	agent := newOutboxTestAgent(t)
+	agent.sendMessage(eventMessage(t, agent, "check1", 0))
+	agent.sendMessage(eventMessage(t, agent, "check2", 0))
+
+	router := mux.NewRouter()
+	registerRoutes(agent, router)
+
+	r, err := http.NewRequest(http.MethodGet, "/outbox?limit=1", nil)
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var status outboxStatus
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+	assert.Equal(t, 2, status.Events)
+	assert.Equal(t, agent.outbox.size(), status.Bytes)
+	require.Len(t, status.Entries, 1)
+	assert.Equal(t, "check1", status.Entries[0].Check)
+
+	r, err = http.NewRequest(http.MethodGet, "/outbox?limit=foo", nil)
+	require.NoError(t, err)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,354 @@
+package v2
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+)
+
+const (
+	// LDAPType is the type name of the LDAP resource.
+	LDAPType = "LDAP"
+
+	// LDAPSecurityTLS connects to the servers over TLS (LDAPS).
+	LDAPSecurityTLS = "tls"
+
+	// LDAPSecurityStartTLS connects to the servers in plain text, and upgrades
+	// the connection to TLS with StartTLS before binding.
+	LDAPSecurityStartTLS = "starttls"
+
+	// LDAPSecurityInsecure connects to the servers in plain text. Credentials
+	// are sent unencrypted.
+	LDAPSecurityInsecure = "insecure"
+
+	// DefaultLDAPUserAttribute is the attribute matched against usernames when
+	// none is configured.
+	DefaultLDAPUserAttribute = "uid"
+
+	// DefaultLDAPUserObjectClass is the object class of users when none is
+	// configured.
+	DefaultLDAPUserObjectClass = "person"
+
+	// DefaultLDAPGroupAttribute is the attribute of groups listing the DNs of
+	// their members when none is configured.
+	DefaultLDAPGroupAttribute = "member"
+
+	// DefaultLDAPGroupNameAttribute is the attribute holding the name of groups
+	// when none is configured.
+	DefaultLDAPGroupNameAttribute = "cn"
+
+	// DefaultLDAPGroupObjectClass is the object class of groups when none is
+	// configured.
+	DefaultLDAPGroupObjectClass = "groupOfNames"
+)
+
+var (
+	_ corev3.Resource       = new(LDAP)
+	_ corev3.GlobalResource = new(LDAP)
+)
+
+// LDAP configures an LDAP or Active Directory authentication provider. Users
+// are looked up in the directory, authenticated by binding with their DN and
+// password, and the groups they are members of are used as RBAC groups.
+type LDAP struct {
+	// Metadata contains the name, labels and annotations of the provider.
+	// Authentication providers are global resources and have no namespace.
+	Metadata *corev2.ObjectMeta `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+
+	// Servers are the directory servers, tried in order. The next server is
+	// only used when the previous one can't be reached.
+	Servers []*LDAPServer `json:"servers" yaml:"servers"`
+
+	// UsernamePrefix is prepended to usernames, e.g. "ldap:", so they can't
+	// conflict with the users of other providers.
+	UsernamePrefix string `json:"username_prefix,omitempty" yaml:"username_prefix,omitempty"`
+
+	// GroupsPrefix is prepended to group names, e.g. "ldap:", so they can't
+	// conflict with the groups of other providers.
+	GroupsPrefix string `json:"groups_prefix,omitempty" yaml:"groups_prefix,omitempty"`
+}
+
+// LDAPServer is a directory server of an LDAP provider.
+type LDAPServer struct {
+	// Host is the hostname or IP address of the server.
+	Host string `json:"host" yaml:"host"`
+
+	// Port is the port of the server. Defaults to 636 with the tls security,
+	// and 389 otherwise.
+	Port int `json:"port,omitempty" yaml:"port,omitempty"`
+
+	// Security is one of tls (the default), starttls or insecure.
+	Security string `json:"security,omitempty" yaml:"security,omitempty"`
+
+	// InsecureSkipVerify disables the verification of the server certificate.
+	InsecureSkipVerify bool `json:"insecure_skip_verify,omitempty" yaml:"insecure_skip_verify,omitempty"`
+
+	// TrustedCAFile is the path to the CA certificates used to verify the
+	// server certificate. Defaults to the system certificates.
+	TrustedCAFile string `json:"trusted_ca_file,omitempty" yaml:"trusted_ca_file,omitempty"`
+
+	// ClientCertFile and ClientKeyFile are the paths to a client certificate
+	// and its key, presented to servers requiring TLS client authentication.
+	ClientCertFile string `json:"client_cert_file,omitempty" yaml:"client_cert_file,omitempty"`
+	ClientKeyFile  string `json:"client_key_file,omitempty" yaml:"client_key_file,omitempty"`
+
+	// Binding are the credentials used to search the directory. Users and
+	// groups are searched anonymously when empty.
+	Binding *LDAPBinding `json:"binding,omitempty" yaml:"binding,omitempty"`
+
+	// UserSearch configures how users are found.
+	UserSearch LDAPUserSearch `json:"user_search" yaml:"user_search"`
+
+	// GroupSearch configures how the groups of users are found.
+	GroupSearch LDAPGroupSearch `json:"group_search" yaml:"group_search"`
+}
+
+// LDAPBinding are the credentials of the account used to search a directory.
+type LDAPBinding struct {
+	UserDN   string `json:"user_dn" yaml:"user_dn"`
+	Password string `json:"password" yaml:"password" sensu:"redact"`
+}
+
+// LDAPUserSearch configures how users are found. Users are searched under
+// BaseDN with the filter (&(objectClass=<ObjectClass>)(<Attribute>=<username>)<Filter>).
+type LDAPUserSearch struct {
+	// BaseDN is the DN under which users are searched.
+	BaseDN string `json:"base_dn" yaml:"base_dn"`
+
+	// Attribute is matched against usernames, e.g. sAMAccountName for
+	// Active Directory. Defaults to uid.
+	Attribute string `json:"attribute,omitempty" yaml:"attribute,omitempty"`
+
+	// ObjectClass is the object class of users. Defaults to person.
+	ObjectClass string `json:"object_class,omitempty" yaml:"object_class,omitempty"`
+
+	// Filter is an optional search filter that users must also match, e.g.
+	// (memberOf=cn=sensu,ou=groups,dc=acme,dc=org).
+	Filter string `json:"filter,omitempty" yaml:"filter,omitempty"`
+}
+
+// LDAPGroupSearch configures how the groups of users are found. Groups are
+// searched under BaseDN with the filter
+// (&(objectClass=<ObjectClass>)(<Attribute>=<user DN>)<Filter>).
+type LDAPGroupSearch struct {
+	// BaseDN is the DN under which groups are searched.
+	BaseDN string `json:"base_dn" yaml:"base_dn"`
+
+	// Attribute lists the DNs of the members of groups. Defaults to member.
+	// With Active Directory, member:1.2.840.113556.1.4.1941: also matches the
+	// members of nested groups.
+	Attribute string `json:"attribute,omitempty" yaml:"attribute,omitempty"`
+
+	// NameAttribute holds the name of groups. Defaults to cn.
+	NameAttribute string `json:"name_attribute,omitempty" yaml:"name_attribute,omitempty"`
+
+	// ObjectClass is the object class of groups. Defaults to groupOfNames.
+	ObjectClass string `json:"object_class,omitempty" yaml:"object_class,omitempty"`
+
+	// Filter is an optional search filter that groups must also match.
+	Filter string `json:"filter,omitempty" yaml:"filter,omitempty"`
+}
+
+// FixtureLDAP returns an LDAP fixture for testing.
+func FixtureLDAP(name string) *LDAP {
+	return &LDAP{
+		Metadata: corev2.NewObjectMetaP(name, ""),
+		Servers: []*LDAPServer{
+			{
+				Host: "ldap.example.com",
+				Binding: &LDAPBinding{
+					UserDN:   "cn=binder,dc=acme,dc=org",
+					Password: "P@ssw0rd!",
+				},
+				UserSearch: LDAPUserSearch{
+					BaseDN: "dc=acme,dc=org",
+				},
+				GroupSearch: LDAPGroupSearch{
+					BaseDN: "dc=acme,dc=org",
+				},
+			},
+		},
+	}
+}
+
+// GetMetadata returns the metadata of the provider.
+func (l *LDAP) GetMetadata() *corev2.ObjectMeta {
+	return l.Metadata
+}
+
+// SetMetadata sets the metadata of the provider.
+func (l *LDAP) SetMetadata(meta *corev2.ObjectMeta) {
+	l.Metadata = meta
+}
+
+// StoreName returns the store name of the provider.
+func (l *LDAP) StoreName() string {
+	return AuthProvidersResource
+}
+
+// RBACName returns the RBAC name of the provider.
+func (l *LDAP) RBACName() string {
+	return AuthProvidersResource
+}
+
+// URIPath returns the API path of the provider.
+func (l *LDAP) URIPath() string {
+	if l.Metadata == nil {
+		return uriPath(ldapPath, "")
+	}
+	return uriPath(ldapPath, l.Metadata.Name)
+}
+
+// IsGlobalResource returns true, authentication providers are not namespaced.
+func (l *LDAP) IsGlobalResource() bool {
+	return true
+}
+
+// GetTypeMeta returns the type metadata of the provider.
+func (l *LDAP) GetTypeMeta() corev2.TypeMeta {
+	return corev2.TypeMeta{
+		APIVersion: APIVersion,
+		Type:       LDAPType,
+	}
+}
+
+// Validate checks that the provider is valid.
+func (l *LDAP) Validate() error {
+	if l == nil {
+		return errors.New("nil LDAP")
+	}
+	if err := corev3.ValidateGlobalMetadata(l.Metadata); err != nil {
+		return fmt.Errorf("invalid LDAP: %s", err)
+	}
+	if l.Metadata.Name == "basic" {
+		return errors.New("the name basic is reserved for the basic provider")
+	}
+	if len(l.Servers) == 0 {
+		return errors.New("at least one server must be configured")
+	}
+	for i, server := range l.Servers {
+		if err := server.Validate(); err != nil {
+			return fmt.Errorf("invalid server %d: %s", i, err)
+		}
+	}
+	return nil
+}
+
+// Validate checks that the server is valid.
+func (s *LDAPServer) Validate() error {
+	if s == nil {
+		return errors.New("nil server")
+	}
+	if s.Host == "" {
+		return errors.New("host must be set")
+	}
+	if s.Port < 0 || s.Port > 65535 {
+		return fmt.Errorf("invalid port %d", s.Port)
+	}
+	switch s.Security {
+	case "", LDAPSecurityTLS, LDAPSecurityStartTLS, LDAPSecurityInsecure:
+	default:
+		return fmt.Errorf("invalid security %q: must be %s, %s or %s",
+			s.Security, LDAPSecurityTLS, LDAPSecurityStartTLS, LDAPSecurityInsecure)
+	}
+	if (s.ClientCertFile == "") != (s.ClientKeyFile == "") {
+		return errors.New("client_cert_file and client_key_file must be set together")
+	}
+	if s.Binding != nil && (s.Binding.UserDN == "" || s.Binding.Password == "") {
+		return errors.New("binding requires a user_dn and a password")
+	}
+	for search, filter := range map[string]string{"user_search": s.UserSearch.Filter, "group_search": s.GroupSearch.Filter} {
+		if filter != "" && (!strings.HasPrefix(filter, "(") || !strings.HasSuffix(filter, ")")) {
+			return fmt.Errorf("%s filter %q must be enclosed in parentheses", search, filter)
+		}
+	}
+	if s.UserSearch.BaseDN == "" {
+		return errors.New("user_search requires a base_dn")
+	}
+	if s.GroupSearch.BaseDN == "" {
+		return errors.New("group_search requires a base_dn")
+	}
+	return nil
+}
+
+// GetPort returns the port of the server, or its default.
+func (s *LDAPServer) GetPort() int {
+	if s.Port != 0 {
+		return s.Port
+	}
+	if s.GetSecurity() == LDAPSecurityTLS {
+		return 636
+	}
+	return 389
+}
+
+// GetSecurity returns the security of the server, or its default.
+func (s *LDAPServer) GetSecurity() string {
+	if s.Security == "" {
+		return LDAPSecurityTLS
+	}
+	return s.Security
+}
+
+// GetAttribute returns the attribute matched against usernames, or its
+// default.
+func (s LDAPUserSearch) GetAttribute() string {
+	if s.Attribute == "" {
+		return DefaultLDAPUserAttribute
+	}
+	return s.Attribute
+}
+
+// GetObjectClass returns the object class of users, or its default.
+func (s LDAPUserSearch) GetObjectClass() string {
+	if s.ObjectClass == "" {
+		return DefaultLDAPUserObjectClass
+	}
+	return s.ObjectClass
+}
+
+// GetAttribute returns the attribute listing the members of groups, or its
+// default.
+func (s LDAPGroupSearch) GetAttribute() string {
+	if s.Attribute == "" {
+		return DefaultLDAPGroupAttribute
+	}
+	return s.Attribute
+}
+
+// GetNameAttribute returns the attribute holding the name of groups, or its
+// default.
+func (s LDAPGroupSearch) GetNameAttribute() string {
+	if s.NameAttribute == "" {
+		return DefaultLDAPGroupNameAttribute
+	}
+	return s.NameAttribute
+}
+
+// GetObjectClass returns the object class of groups, or its default.
+func (s LDAPGroupSearch) GetObjectClass() string {
+	if s.ObjectClass == "" {
+		return DefaultLDAPGroupObjectClass
+	}
+	return s.ObjectClass
+}
+
+// LDAPFields returns a set of fields that represent the resource.
+func LDAPFields(r corev3.Resource) map[string]string {
+	resource := r.(*LDAP)
+	fields := map[string]string{
+		"ldap.name": resource.Metadata.Name,
+	}
+	for k, v := range resource.Metadata.Labels {
+		fields["ldap.labels."+k] = v
+	}
+	return fields
+}
+
+// Fields returns a set of fields that represent the resource.
+func (l *LDAP) Fields() map[string]string {
+	return LDAPFields(l)
+}
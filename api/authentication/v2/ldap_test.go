@@ -0,0 +1,160 @@
+package v2
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sensu/core/v3/types"
+	apitools "github.com/sensu/sensu-api-tools"
+)
+
+func TestLDAPValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*LDAP)
+		wantErr bool
+	}{
+		{
+			name:   "valid fixture",
+			mutate: func(*LDAP) {},
+		},
+		{
+			name:    "no servers",
+			mutate:  func(l *LDAP) { l.Servers = nil },
+			wantErr: true,
+		},
+		{
+			name:    "nil server",
+			mutate:  func(l *LDAP) { l.Servers = append(l.Servers, nil) },
+			wantErr: true,
+		},
+		{
+			name:    "missing host",
+			mutate:  func(l *LDAP) { l.Servers[0].Host = "" },
+			wantErr: true,
+		},
+		{
+			name:    "invalid port",
+			mutate:  func(l *LDAP) { l.Servers[0].Port = 70000 },
+			wantErr: true,
+		},
+		{
+			name:   "starttls",
+			mutate: func(l *LDAP) { l.Servers[0].Security = LDAPSecurityStartTLS },
+		},
+		{
+			name:    "invalid security",
+			mutate:  func(l *LDAP) { l.Servers[0].Security = "ssl" },
+			wantErr: true,
+		},
+		{
+			name:    "client cert without key",
+			mutate:  func(l *LDAP) { l.Servers[0].ClientCertFile = "/etc/sensu/ldap.pem" },
+			wantErr: true,
+		},
+		{
+			name:   "anonymous search",
+			mutate: func(l *LDAP) { l.Servers[0].Binding = nil },
+		},
+		{
+			name:    "binding without password",
+			mutate:  func(l *LDAP) { l.Servers[0].Binding.Password = "" },
+			wantErr: true,
+		},
+		{
+			name:    "missing user search base dn",
+			mutate:  func(l *LDAP) { l.Servers[0].UserSearch.BaseDN = "" },
+			wantErr: true,
+		},
+		{
+			name:    "missing group search base dn",
+			mutate:  func(l *LDAP) { l.Servers[0].GroupSearch.BaseDN = "" },
+			wantErr: true,
+		},
+		{
+			name:   "user search filter",
+			mutate: func(l *LDAP) { l.Servers[0].UserSearch.Filter = "(!(disabled=TRUE))" },
+		},
+		{
+			name:    "invalid group search filter",
+			mutate:  func(l *LDAP) { l.Servers[0].GroupSearch.Filter = "cn=sensu" },
+			wantErr: true,
+		},
+		{
+			name:    "namespaced",
+			mutate:  func(l *LDAP) { l.Metadata.Namespace = "default" },
+			wantErr: true,
+		},
+		{
+			name:    "reserved name",
+			mutate:  func(l *LDAP) { l.Metadata.Name = "basic" },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := FixtureLDAP("openldap")
+			tt.mutate(l)
+			if err := l.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("LDAP.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLDAPServerDefaults(t *testing.T) {
+	server := FixtureLDAP("openldap").Servers[0]
+	if got, want := server.GetSecurity(), LDAPSecurityTLS; got != want {
+		t.Errorf("default security = %q, want %q", got, want)
+	}
+	if got, want := server.GetPort(), 636; got != want {
+		t.Errorf("default tls port = %d, want %d", got, want)
+	}
+	server.Security = LDAPSecurityStartTLS
+	if got, want := server.GetPort(), 389; got != want {
+		t.Errorf("default starttls port = %d, want %d", got, want)
+	}
+	if got, want := server.UserSearch.GetAttribute(), DefaultLDAPUserAttribute; got != want {
+		t.Errorf("default user attribute = %q, want %q", got, want)
+	}
+	if got, want := server.GroupSearch.GetNameAttribute(), DefaultLDAPGroupNameAttribute; got != want {
+		t.Errorf("default group name attribute = %q, want %q", got, want)
+	}
+	server.GroupSearch.Attribute = "uniqueMember"
+	if got, want := server.GroupSearch.GetAttribute(), "uniqueMember"; got != want {
+		t.Errorf("group attribute = %q, want %q", got, want)
+	}
+}
+
+func TestLDAPURIPath(t *testing.T) {
+	l := FixtureLDAP("openldap")
+	if got, want := l.URIPath(), "/api/authentication/v2/authproviders/ldap/openldap"; got != want {
+		t.Errorf("LDAP.URIPath() = %q, want %q", got, want)
+	}
+}
+
+func TestLDAPResolve(t *testing.T) {
+	v, err := apitools.Resolve(APIVersion, LDAPType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := v.(*LDAP); !ok {
+		t.Fatalf("unexpected type %T", v)
+	}
+
+	b, err := json.Marshal(types.WrapResource(FixtureLDAP("openldap")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var w types.Wrapper
+	if err := json.Unmarshal(b, &w); err != nil {
+		t.Fatal(err)
+	}
+	provider, ok := w.Value.(*LDAP)
+	if !ok {
+		t.Fatalf("unexpected type %T", w.Value)
+	}
+	if err := provider.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
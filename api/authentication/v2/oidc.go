@@ -0,0 +1,203 @@
+package v2
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+)
+
+const (
+	// OIDCType is the type name of the OIDC resource.
+	OIDCType = "OIDC"
+
+	// AuthProvidersResource is the name of the authentication provider
+	// resources, as used for storage, RBAC and API paths.
+	AuthProvidersResource = "authproviders"
+
+	// DefaultOIDCUsernameClaim is the claim of the ID token used as the
+	// username when none is configured.
+	DefaultOIDCUsernameClaim = "sub"
+
+	// DefaultOIDCGroupsClaim is the claim of the ID token used as the list of
+	// groups when none is configured.
+	DefaultOIDCGroupsClaim = "groups"
+)
+
+var (
+	_ corev3.Resource       = new(OIDC)
+	_ corev3.GlobalResource = new(OIDC)
+)
+
+// OIDC configures an OpenID Connect authentication provider. Users are
+// authenticated with the authorization code flow against the provider's
+// issuer, and the claims of their ID token are mapped to a Sensu username
+// and RBAC groups.
+type OIDC struct {
+	// Metadata contains the name, labels and annotations of the provider.
+	// Authentication providers are global resources and have no namespace.
+	Metadata *corev2.ObjectMeta `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+
+	// Server is the URL of the OpenID Connect issuer. The provider
+	// configuration is discovered from its /.well-known/openid-configuration
+	// document.
+	Server string `json:"server" yaml:"server"`
+
+	// ClientID is the client identifier registered with the issuer.
+	ClientID string `json:"client_id" yaml:"client_id"`
+
+	// ClientSecret is the client secret registered with the issuer.
+	ClientSecret string `json:"client_secret" yaml:"client_secret" sensu:"redact"`
+
+	// RedirectURI is the URI the issuer redirects users to once they are
+	// authenticated. It must point to the /auth/oidc/callback endpoint of
+	// the backend API. When empty, it is derived from the URL of the
+	// authorization request.
+	RedirectURI string `json:"redirect_uri,omitempty" yaml:"redirect_uri,omitempty"`
+
+	// AdditionalScopes are requested in addition to the openid scope.
+	AdditionalScopes []string `json:"additional_scopes,omitempty" yaml:"additional_scopes,omitempty"`
+
+	// DisableOfflineAccess prevents the offline_access scope from being
+	// requested. Without it, most issuers don't return a refresh token, and
+	// users must log in again once their session can no longer be refreshed.
+	DisableOfflineAccess bool `json:"disable_offline_access" yaml:"disable_offline_access"`
+
+	// UsernameClaim is the claim of the ID token used as the username.
+	// Defaults to "sub".
+	UsernameClaim string `json:"username_claim,omitempty" yaml:"username_claim,omitempty"`
+
+	// UsernamePrefix is prepended to usernames, e.g. "oidc:", so they can't
+	// conflict with the users of other providers.
+	UsernamePrefix string `json:"username_prefix,omitempty" yaml:"username_prefix,omitempty"`
+
+	// GroupsClaim is the claim of the ID token containing the groups of the
+	// user, which are used as RBAC subjects. Defaults to "groups".
+	GroupsClaim string `json:"groups_claim,omitempty" yaml:"groups_claim,omitempty"`
+
+	// GroupsPrefix is prepended to group names, e.g. "oidc:", so they can't
+	// conflict with the groups of other providers.
+	GroupsPrefix string `json:"groups_prefix,omitempty" yaml:"groups_prefix,omitempty"`
+}
+
+// FixtureOIDC returns an OIDC fixture for testing.
+func FixtureOIDC(name string) *OIDC {
+	return &OIDC{
+		Metadata:     corev2.NewObjectMetaP(name, ""),
+		Server:       "https://idp.example.com",
+		ClientID:     "sensu",
+		ClientSecret: "P@ssw0rd!",
+	}
+}
+
+// GetMetadata returns the metadata of the provider.
+func (o *OIDC) GetMetadata() *corev2.ObjectMeta {
+	return o.Metadata
+}
+
+// SetMetadata sets the metadata of the provider.
+func (o *OIDC) SetMetadata(meta *corev2.ObjectMeta) {
+	o.Metadata = meta
+}
+
+// StoreName returns the store name of the provider.
+func (o *OIDC) StoreName() string {
+	return AuthProvidersResource
+}
+
+// RBACName returns the RBAC name of the provider.
+func (o *OIDC) RBACName() string {
+	return AuthProvidersResource
+}
+
+// URIPath returns the API path of the provider.
+func (o *OIDC) URIPath() string {
+	if o.Metadata == nil {
+		return uriPath(oidcPath, "")
+	}
+	return uriPath(oidcPath, o.Metadata.Name)
+}
+
+// IsGlobalResource returns true, authentication providers are not namespaced.
+func (o *OIDC) IsGlobalResource() bool {
+	return true
+}
+
+// GetTypeMeta returns the type metadata of the provider.
+func (o *OIDC) GetTypeMeta() corev2.TypeMeta {
+	return corev2.TypeMeta{
+		APIVersion: APIVersion,
+		Type:       OIDCType,
+	}
+}
+
+// GetUsernameClaim returns the username claim, or its default.
+func (o *OIDC) GetUsernameClaim() string {
+	if o.UsernameClaim == "" {
+		return DefaultOIDCUsernameClaim
+	}
+	return o.UsernameClaim
+}
+
+// GetGroupsClaim returns the groups claim, or its default.
+func (o *OIDC) GetGroupsClaim() string {
+	if o.GroupsClaim == "" {
+		return DefaultOIDCGroupsClaim
+	}
+	return o.GroupsClaim
+}
+
+// Validate checks that the provider is valid.
+func (o *OIDC) Validate() error {
+	if o == nil {
+		return errors.New("nil OIDC")
+	}
+	if err := corev3.ValidateGlobalMetadata(o.Metadata); err != nil {
+		return fmt.Errorf("invalid OIDC: %s", err)
+	}
+	if o.Metadata.Name == "basic" {
+		return errors.New("the name basic is reserved for the basic provider")
+	}
+	if o.Server == "" {
+		return errors.New("server must be set")
+	}
+	if o.ClientID == "" {
+		return errors.New("client_id must be set")
+	}
+	if o.ClientSecret == "" {
+		return errors.New("client_secret must be set")
+	}
+	for field, value := range map[string]string{"server": o.Server, "redirect_uri": o.RedirectURI} {
+		if value == "" {
+			continue
+		}
+		u, err := url.Parse(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %s", field, err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return fmt.Errorf("invalid %s scheme %q: must be http or https", field, u.Scheme)
+		}
+	}
+	return nil
+}
+
+// OIDCFields returns a set of fields that represent the resource.
+func OIDCFields(r corev3.Resource) map[string]string {
+	resource := r.(*OIDC)
+	fields := map[string]string{
+		"oidc.name":   resource.Metadata.Name,
+		"oidc.server": resource.Server,
+	}
+	for k, v := range resource.Metadata.Labels {
+		fields["oidc.labels."+k] = v
+	}
+	return fields
+}
+
+// Fields returns a set of fields that represent the resource.
+func (o *OIDC) Fields() map[string]string {
+	return OIDCFields(o)
+}
@@ -0,0 +1,126 @@
+package v2
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sensu/core/v3/types"
+	apitools "github.com/sensu/sensu-api-tools"
+)
+
+func TestOIDCValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*OIDC)
+		wantErr bool
+	}{
+		{
+			name:   "valid fixture",
+			mutate: func(*OIDC) {},
+		},
+		{
+			name:    "missing server",
+			mutate:  func(o *OIDC) { o.Server = "" },
+			wantErr: true,
+		},
+		{
+			name:    "unsupported server scheme",
+			mutate:  func(o *OIDC) { o.Server = "ftp://idp.example.com" },
+			wantErr: true,
+		},
+		{
+			name:    "missing client id",
+			mutate:  func(o *OIDC) { o.ClientID = "" },
+			wantErr: true,
+		},
+		{
+			name:    "missing client secret",
+			mutate:  func(o *OIDC) { o.ClientSecret = "" },
+			wantErr: true,
+		},
+		{
+			name:   "redirect uri",
+			mutate: func(o *OIDC) { o.RedirectURI = "https://sensu.example.com:8080/auth/oidc/callback" },
+		},
+		{
+			name:    "invalid redirect uri",
+			mutate:  func(o *OIDC) { o.RedirectURI = "sensu.example.com" },
+			wantErr: true,
+		},
+		{
+			name:    "namespaced",
+			mutate:  func(o *OIDC) { o.Metadata.Namespace = "default" },
+			wantErr: true,
+		},
+		{
+			name:    "reserved name",
+			mutate:  func(o *OIDC) { o.Metadata.Name = "basic" },
+			wantErr: true,
+		},
+		{
+			name:    "nil metadata",
+			mutate:  func(o *OIDC) { o.Metadata = nil },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := FixtureOIDC("okta")
+			tt.mutate(o)
+			if err := o.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("OIDC.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOIDCClaimDefaults(t *testing.T) {
+	o := FixtureOIDC("okta")
+	if got, want := o.GetUsernameClaim(), DefaultOIDCUsernameClaim; got != want {
+		t.Errorf("default username claim = %q, want %q", got, want)
+	}
+	if got, want := o.GetGroupsClaim(), DefaultOIDCGroupsClaim; got != want {
+		t.Errorf("default groups claim = %q, want %q", got, want)
+	}
+	o.UsernameClaim = "email"
+	o.GroupsClaim = "roles"
+	if got, want := o.GetUsernameClaim(), "email"; got != want {
+		t.Errorf("username claim = %q, want %q", got, want)
+	}
+	if got, want := o.GetGroupsClaim(), "roles"; got != want {
+		t.Errorf("groups claim = %q, want %q", got, want)
+	}
+}
+
+func TestOIDCURIPath(t *testing.T) {
+	o := FixtureOIDC("okta")
+	if got, want := o.URIPath(), "/api/authentication/v2/authproviders/oidc/okta"; got != want {
+		t.Errorf("OIDC.URIPath() = %q, want %q", got, want)
+	}
+}
+
+func TestOIDCResolve(t *testing.T) {
+	v, err := apitools.Resolve(APIVersion, OIDCType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := v.(*OIDC); !ok {
+		t.Fatalf("unexpected type %T", v)
+	}
+
+	b, err := json.Marshal(types.WrapResource(FixtureOIDC("okta")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var w types.Wrapper
+	if err := json.Unmarshal(b, &w); err != nil {
+		t.Fatal(err)
+	}
+	provider, ok := w.Value.(*OIDC)
+	if !ok {
+		t.Fatalf("unexpected type %T", w.Value)
+	}
+	if err := provider.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,30 @@
+// Package v2 contains the authentication/v2 API group. Resources in this group
+// configure the external authentication providers used by the backend, in
+// addition to the built-in basic provider.
+package v2
+
+import (
+	"net/url"
+	"path"
+
+	apitools "github.com/sensu/sensu-api-tools"
+)
+
+// APIVersion is the API version of the resources in this package.
+const APIVersion = "authentication/v2"
+
+func init() {
+	apitools.RegisterType(APIVersion, new(LDAP), apitools.WithAlias("ldap"))
+	apitools.RegisterType(APIVersion, new(OIDC), apitools.WithAlias("oidc"))
+}
+
+// Authentication providers share the authproviders resource, and each type of
+// provider has its own path under it.
+var (
+	ldapPath = path.Join(AuthProvidersResource, "ldap")
+	oidcPath = path.Join(AuthProvidersResource, "oidc")
+)
+
+func uriPath(resource, name string) string {
+	return path.Join("/api", "authentication", "v2", resource, url.PathEscape(name))
+}
@@ -0,0 +1,312 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	cron "github.com/robfig/cron/v3"
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+)
+
+const (
+	// MaintenanceWindowType is the type name of the MaintenanceWindow
+	// resource.
+	MaintenanceWindowType = "MaintenanceWindow"
+
+	// MaintenanceWindowsResource is the name of the MaintenanceWindow
+	// resource, as used for storage, RBAC and API paths.
+	MaintenanceWindowsResource = "maintenance_windows"
+
+	// MaintenanceWindowLabel is the label of the silences created for a
+	// maintenance window, whose value is the name of the window.
+	MaintenanceWindowLabel = "sensu.io/maintenance_window"
+
+	// MaintenanceWindowManager is the value of the corev2.ManagedByLabel label
+	// of the silences created for the maintenance windows.
+	MaintenanceWindowManager = "maintenance-window"
+
+	// maintenanceWindowStartLayout is the layout of the start of a window,
+	// when it doesn't specify a time zone offset.
+	maintenanceWindowStartLayout = "2006-01-02T15:04:05"
+)
+
+var _ corev3.Resource = new(MaintenanceWindow)
+
+// MaintenanceWindow is a recurring period of time during which the events of
+// the targeted entities and checks are silenced. The occurrences of the
+// window are scheduled by either a cron expression or a recurrence rule, and
+// last for its duration.
+//
+// The backend creates a silence for each target of the window ahead of its
+// next occurrence, which expires at the end of the occurrence.
+type MaintenanceWindow struct {
+	// Metadata contains the name, namespace, labels and annotations of the
+	// window.
+	Metadata *corev2.ObjectMeta `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+
+	// Cron is a cron expression that schedules the start of the occurrences
+	// of the window, e.g. "0 2 * * SUN".
+	Cron string `json:"cron,omitempty" yaml:"cron,omitempty"`
+
+	// RRule is an RFC 5545 recurrence rule that schedules the start of the
+	// occurrences of the window, from its start, e.g.
+	// "FREQ=WEEKLY;BYDAY=SU;BYHOUR=2;BYMINUTE=0".
+	RRule string `json:"rrule,omitempty" yaml:"rrule,omitempty"`
+
+	// Start is the time of the first possible occurrence of the window, as
+	// an RFC 3339 time, or as a local time in the time zone of the window,
+	// e.g. "2024-01-07T02:00:00". It is required by recurrence rules.
+	Start string `json:"start,omitempty" yaml:"start,omitempty"`
+
+	// Duration is the duration of each occurrence of the window, in seconds.
+	Duration uint32 `json:"duration" yaml:"duration"`
+
+	// Timezone is the IANA time zone that the occurrences of the window are
+	// scheduled in. Defaults to UTC.
+	Timezone string `json:"timezone,omitempty" yaml:"timezone,omitempty"`
+
+	// Subscriptions are the subscriptions silenced by the window.
+	Subscriptions []string `json:"subscriptions,omitempty" yaml:"subscriptions,omitempty"`
+
+	// Checks are the names of the checks silenced by the window. All the
+	// checks of the targeted subscriptions and entities are silenced when
+	// neither checks nor a check selector are specified.
+	Checks []string `json:"checks,omitempty" yaml:"checks,omitempty"`
+
+	// EntitySelector is a field selector of the entities silenced by the
+	// window, e.g. "entity.labels.role == db".
+	EntitySelector string `json:"entity_selector,omitempty" yaml:"entity_selector,omitempty"`
+
+	// CheckSelector is a field selector of the checks silenced by the
+	// window, e.g. "check.labels.tier == web".
+	CheckSelector string `json:"check_selector,omitempty" yaml:"check_selector,omitempty"`
+
+	// Reason is the reason of the silences created for the window.
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+}
+
+// Occurrence is an occurrence of a maintenance window.
+type Occurrence struct {
+	// Start is the start of the occurrence.
+	Start time.Time `json:"start" yaml:"start"`
+
+	// End is the end of the occurrence.
+	End time.Time `json:"end" yaml:"end"`
+}
+
+// Active returns whether the occurrence is active at the given time.
+func (o Occurrence) Active(now time.Time) bool {
+	return !now.Before(o.Start) && now.Before(o.End)
+}
+
+// FixtureMaintenanceWindow returns a MaintenanceWindow fixture for testing.
+func FixtureMaintenanceWindow(name string) *MaintenanceWindow {
+	return &MaintenanceWindow{
+		Metadata:      corev2.NewObjectMetaP(name, "default"),
+		Cron:          "0 2 * * SUN",
+		Duration:      7200,
+		Subscriptions: []string{"linux"},
+		Reason:        "weekly maintenance",
+	}
+}
+
+// GetMetadata returns the metadata of the window.
+func (w *MaintenanceWindow) GetMetadata() *corev2.ObjectMeta {
+	return w.Metadata
+}
+
+// SetMetadata sets the metadata of the window.
+func (w *MaintenanceWindow) SetMetadata(meta *corev2.ObjectMeta) {
+	w.Metadata = meta
+}
+
+// StoreName returns the store name of the window.
+func (w *MaintenanceWindow) StoreName() string {
+	return MaintenanceWindowsResource
+}
+
+// RBACName returns the RBAC name of the window.
+func (w *MaintenanceWindow) RBACName() string {
+	return MaintenanceWindowsResource
+}
+
+// URIPath returns the API path of the window.
+func (w *MaintenanceWindow) URIPath() string {
+	if w.Metadata == nil {
+		return uriPath(MaintenanceWindowsResource, "", "")
+	}
+	return uriPath(MaintenanceWindowsResource, w.Metadata.Namespace, w.Metadata.Name)
+}
+
+// GetTypeMeta returns the type metadata of the window.
+func (w *MaintenanceWindow) GetTypeMeta() corev2.TypeMeta {
+	return corev2.TypeMeta{
+		APIVersion: APIVersion,
+		Type:       MaintenanceWindowType,
+	}
+}
+
+// Validate checks that the window is valid.
+func (w *MaintenanceWindow) Validate() error {
+	if w == nil {
+		return errors.New("nil MaintenanceWindow")
+	}
+	if err := corev3.ValidateMetadata(w.Metadata); err != nil {
+		return fmt.Errorf("invalid MaintenanceWindow: %s", err)
+	}
+	if (w.Cron == "") == (w.RRule == "") {
+		return errors.New("exactly one of cron or rrule must be set")
+	}
+	if w.Duration == 0 {
+		return errors.New("duration must be greater than zero")
+	}
+	if len(w.Subscriptions) == 0 && len(w.Checks) == 0 && w.EntitySelector == "" && w.CheckSelector == "" {
+		return errors.New("at least one of subscriptions, checks, entity_selector or check_selector must be set")
+	}
+	if _, err := w.schedule(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// DurationTime returns the duration of the occurrences of the window.
+func (w *MaintenanceWindow) DurationTime() time.Duration {
+	return time.Duration(w.Duration) * time.Second
+}
+
+// Location returns the time zone of the window.
+func (w *MaintenanceWindow) Location() (*time.Location, error) {
+	if w.Timezone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(w.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %s", w.Timezone, err)
+	}
+	return loc, nil
+}
+
+// StartTime returns the start of the window, or the zero time when it is
+// not set.
+func (w *MaintenanceWindow) StartTime() (time.Time, error) {
+	if w.Start == "" {
+		return time.Time{}, nil
+	}
+	loc, err := w.Location()
+	if err != nil {
+		return time.Time{}, err
+	}
+	if t, err := time.Parse(time.RFC3339, w.Start); err == nil {
+		return t.In(loc), nil
+	}
+	t, err := time.ParseInLocation(maintenanceWindowStartLayout, w.Start, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid start %q: must be an RFC 3339 or a local time", w.Start)
+	}
+	return t, nil
+}
+
+// schedule returns a function that returns the start of the first
+// occurrence of the window that starts after the given time.
+func (w *MaintenanceWindow) schedule() (func(time.Time) (time.Time, bool), error) {
+	loc, err := w.Location()
+	if err != nil {
+		return nil, err
+	}
+	start, err := w.StartTime()
+	if err != nil {
+		return nil, err
+	}
+	if w.RRule != "" {
+		if start.IsZero() {
+			return nil, errors.New("start must be set with rrule")
+		}
+		rule, err := parseRRule(w.RRule)
+		if err != nil {
+			return nil, err
+		}
+		if rule.until != "" {
+			if _, err := rule.untilTime(loc); err != nil {
+				return nil, err
+			}
+		}
+		return func(after time.Time) (time.Time, bool) {
+			return rule.next(start, after)
+		}, nil
+	}
+
+	// The time zone of the window is used instead
+	if strings.HasPrefix(w.Cron, "CRON_TZ=") || strings.HasPrefix(w.Cron, "TZ=") {
+		return nil, errors.New("cron must not specify a time zone, use timezone instead")
+	}
+	sched, err := cron.ParseStandard(w.Cron)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron %q: %s", w.Cron, err)
+	}
+	return func(after time.Time) (time.Time, bool) {
+		if !start.IsZero() && after.Before(start) {
+			// The occurrence may start at start
+			after = start.Add(-time.Second)
+		}
+		next := sched.Next(after.In(loc))
+		return next, !next.IsZero()
+	}, nil
+}
+
+// NextOccurrence returns the occurrence of the window that is active at the
+// given time, or the next one. It returns nil when the window doesn't occur
+// anymore.
+func (w *MaintenanceWindow) NextOccurrence(now time.Time) (*Occurrence, error) {
+	next, err := w.schedule()
+	if err != nil {
+		return nil, err
+	}
+	start, ok := next(now.Add(-w.DurationTime()))
+	if !ok {
+		return nil, nil
+	}
+	return &Occurrence{Start: start, End: start.Add(w.DurationTime())}, nil
+}
+
+// Occurrences returns the occurrences of the window that are active at the
+// given time, or that start before until.
+func (w *MaintenanceWindow) Occurrences(now, until time.Time) ([]Occurrence, error) {
+	next, err := w.schedule()
+	if err != nil {
+		return nil, err
+	}
+	var occurrences []Occurrence
+	after := now.Add(-w.DurationTime())
+	for {
+		start, ok := next(after)
+		if !ok || !start.Before(until) {
+			return occurrences, nil
+		}
+		occurrences = append(occurrences, Occurrence{Start: start, End: start.Add(w.DurationTime())})
+		after = start
+	}
+}
+
+// MaintenanceWindowFields returns a set of fields that represent the
+// resource.
+func MaintenanceWindowFields(r corev3.Resource) map[string]string {
+	resource := r.(*MaintenanceWindow)
+	fields := map[string]string{
+		"maintenance_window.name":          resource.Metadata.Name,
+		"maintenance_window.namespace":     resource.Metadata.Namespace,
+		"maintenance_window.subscriptions": strings.Join(resource.Subscriptions, ","),
+		"maintenance_window.checks":        strings.Join(resource.Checks, ","),
+	}
+	for k, v := range resource.Metadata.Labels {
+		fields["maintenance_window.labels."+k] = v
+	}
+	return fields
+}
+
+// Fields returns a set of fields that represent the resource.
+func (w *MaintenanceWindow) Fields() map[string]string {
+	return MaintenanceWindowFields(w)
+}
@@ -0,0 +1,165 @@
+package v1
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMaintenanceWindowValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*MaintenanceWindow)
+		wantErr bool
+	}{
+		{
+			name:   "valid fixture",
+			mutate: func(*MaintenanceWindow) {},
+		},
+		{
+			name: "rrule",
+			mutate: func(w *MaintenanceWindow) {
+				w.Cron = ""
+				w.RRule = "FREQ=WEEKLY;BYDAY=SU"
+				w.Start = "2024-01-07T02:00:00"
+			},
+		},
+		{
+			name: "rrule without start",
+			mutate: func(w *MaintenanceWindow) {
+				w.Cron = ""
+				w.RRule = "FREQ=WEEKLY;BYDAY=SU"
+			},
+			wantErr: true,
+		},
+		{
+			name:    "cron and rrule",
+			mutate:  func(w *MaintenanceWindow) { w.RRule = "FREQ=DAILY" },
+			wantErr: true,
+		},
+		{
+			name:    "no schedule",
+			mutate:  func(w *MaintenanceWindow) { w.Cron = "" },
+			wantErr: true,
+		},
+		{
+			name:    "invalid cron",
+			mutate:  func(w *MaintenanceWindow) { w.Cron = "0 2 * *" },
+			wantErr: true,
+		},
+		{
+			name:    "cron time zone",
+			mutate:  func(w *MaintenanceWindow) { w.Cron = "CRON_TZ=Europe/Paris 0 2 * * SUN" },
+			wantErr: true,
+		},
+		{
+			name:    "invalid timezone",
+			mutate:  func(w *MaintenanceWindow) { w.Timezone = "Mars/Olympus" },
+			wantErr: true,
+		},
+		{
+			name:    "invalid start",
+			mutate:  func(w *MaintenanceWindow) { w.Start = "tomorrow" },
+			wantErr: true,
+		},
+		{
+			name:    "no duration",
+			mutate:  func(w *MaintenanceWindow) { w.Duration = 0 },
+			wantErr: true,
+		},
+		{
+			name:    "no target",
+			mutate:  func(w *MaintenanceWindow) { w.Subscriptions = nil },
+			wantErr: true,
+		},
+		{
+			name: "selector target",
+			mutate: func(w *MaintenanceWindow) {
+				w.Subscriptions = nil
+				w.EntitySelector = "entity.labels.role == db"
+			},
+		},
+		{
+			name:    "nil metadata",
+			mutate:  func(w *MaintenanceWindow) { w.Metadata = nil },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := FixtureMaintenanceWindow("window")
+			tt.mutate(w)
+			if err := w.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("MaintenanceWindow.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMaintenanceWindowNextOccurrence(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	w := FixtureMaintenanceWindow("window")
+	w.Timezone = "Europe/Paris"
+
+	// Saturday
+	now := time.Date(2024, 3, 16, 12, 0, 0, 0, time.UTC)
+	got, err := w.NextOccurrence(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2024, 3, 17, 2, 0, 0, 0, paris)
+	if !got.Start.Equal(want) || !got.End.Equal(want.Add(2*time.Hour)) {
+		t.Errorf("NextOccurrence() = %v, want start %v", got, want)
+	}
+
+	// The active occurrence is returned
+	now = want.Add(time.Hour)
+	got, err = w.NextOccurrence(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Start.Equal(want) || !got.Active(now) {
+		t.Errorf("NextOccurrence() = %v, want active occurrence at %v", got, want)
+	}
+
+	// The window doesn't occur before its start
+	w.Start = "2024-04-01T00:00:00"
+	got, err = w.NextOccurrence(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2024, 4, 7, 2, 0, 0, 0, paris); !got.Start.Equal(want) {
+		t.Errorf("NextOccurrence() = %v, want start %v", got.Start, want)
+	}
+}
+
+func TestMaintenanceWindowOccurrences(t *testing.T) {
+	w := FixtureMaintenanceWindow("window")
+	w.Cron = ""
+	w.RRule = "FREQ=WEEKLY;INTERVAL=2;BYDAY=SA,SU;COUNT=5"
+	w.Start = "2024-01-06T22:00:00Z"
+
+	now := time.Date(2024, 1, 6, 23, 0, 0, 0, time.UTC)
+	got, err := w.Occurrences(now, now.AddDate(1, 0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []time.Time{
+		time.Date(2024, 1, 6, 22, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 7, 22, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 20, 22, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 21, 22, 0, 0, 0, time.UTC),
+		time.Date(2024, 2, 3, 22, 0, 0, 0, time.UTC),
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Occurrences() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if !got[i].Start.Equal(want[i]) {
+			t.Errorf("Occurrences()[%d] = %v, want %v", i, got[i].Start, want[i])
+		}
+	}
+}
@@ -0,0 +1,24 @@
+// Package v1 contains the maintenance/v1 API group. Resources in this group
+// schedule the maintenance of entities and checks.
+package v1
+
+import (
+	"net/url"
+	"path"
+
+	apitools "github.com/sensu/sensu-api-tools"
+)
+
+// APIVersion is the API version of the resources in this package.
+const APIVersion = "maintenance/v1"
+
+func init() {
+	apitools.RegisterType(APIVersion, new(MaintenanceWindow), apitools.WithAlias("maintenance_window", "maintenance_windows"))
+}
+
+func uriPath(typename, namespace, name string) string {
+	if namespace == "" {
+		return path.Join("/api", "maintenance", "v1", typename, url.PathEscape(name))
+	}
+	return path.Join("/api", "maintenance", "v1", "namespaces", url.PathEscape(namespace), typename, url.PathEscape(name))
+}
@@ -0,0 +1,309 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxRRuleDays bounds the number of days searched for the next occurrence of
+// a recurrence rule.
+const maxRRuleDays = 5 * 366
+
+var rruleWeekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// rrule is a recurrence rule, as defined by RFC 5545. The DAILY, WEEKLY,
+// MONTHLY and YEARLY frequencies are supported, along with the INTERVAL,
+// COUNT, UNTIL, BYMONTH, BYMONTHDAY, BYDAY, BYHOUR and BYMINUTE parts. BYDAY
+// only supports plain weekdays, e.g. MO, and not the ordinal ones, e.g. 1MO.
+type rrule struct {
+	freq       string
+	interval   int
+	count      int
+	until      string
+	byMonth    []int
+	byMonthDay []int
+	byDay      []time.Weekday
+	byHour     []int
+	byMinute   []int
+}
+
+func parseRRule(s string) (*rrule, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	rule := &rrule{interval: 1}
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("invalid rrule part %q", part)
+		}
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			rule.freq = strings.ToUpper(value)
+			switch rule.freq {
+			case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+			default:
+				return nil, fmt.Errorf("unsupported rrule frequency %q", value)
+			}
+		case "INTERVAL":
+			rule.interval, err = strconv.Atoi(value)
+			if err == nil && rule.interval < 1 {
+				err = errors.New("must be at least 1")
+			}
+		case "COUNT":
+			rule.count, err = strconv.Atoi(value)
+			if err == nil && rule.count < 1 {
+				err = errors.New("must be at least 1")
+			}
+		case "UNTIL":
+			rule.until = value
+		case "BYMONTH":
+			rule.byMonth, err = parseRRuleInts(value, 1, 12, false)
+		case "BYMONTHDAY":
+			rule.byMonthDay, err = parseRRuleInts(value, 1, 31, true)
+		case "BYHOUR":
+			rule.byHour, err = parseRRuleInts(value, 0, 23, false)
+		case "BYMINUTE":
+			rule.byMinute, err = parseRRuleInts(value, 0, 59, false)
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				weekday, ok := rruleWeekdays[strings.ToUpper(day)]
+				if !ok {
+					return nil, fmt.Errorf("unsupported rrule weekday %q", day)
+				}
+				rule.byDay = append(rule.byDay, weekday)
+			}
+		default:
+			return nil, fmt.Errorf("unsupported rrule part %q", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid rrule %s: %s", key, err)
+		}
+	}
+	if rule.freq == "" {
+		return nil, errors.New("rrule must specify FREQ")
+	}
+	if rule.count > 0 && rule.until != "" {
+		return nil, errors.New("rrule can't specify both COUNT and UNTIL")
+	}
+	return rule, nil
+}
+
+func parseRRuleInts(value string, min, max int, negative bool) ([]int, error) {
+	var values []int
+	for _, s := range strings.Split(value, ",") {
+		i, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, err
+		}
+		abs := i
+		if negative && i < 0 {
+			abs = -i
+		}
+		if abs < min || abs > max {
+			return nil, fmt.Errorf("%d is out of range", i)
+		}
+		values = append(values, i)
+	}
+	return values, nil
+}
+
+// untilTime returns the UNTIL time of the rule, in loc when it is not in UTC.
+func (r *rrule) untilTime(loc *time.Location) (time.Time, error) {
+	if strings.HasSuffix(r.until, "Z") {
+		t, err := time.ParseInLocation("20060102T150405Z", r.until, time.UTC)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid rrule UNTIL %q", r.until)
+		}
+		return t, nil
+	}
+	for _, layout := range []string{"20060102T150405", "20060102"} {
+		if t, err := time.ParseInLocation(layout, r.until, loc); err == nil {
+			if layout == "20060102" {
+				// A date includes the whole day
+				t = t.AddDate(0, 0, 1).Add(-time.Second)
+			}
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid rrule UNTIL %q", r.until)
+}
+
+// next returns the first occurrence of the rule that is strictly after the
+// given time. The occurrences start at dtstart, and are computed in the
+// location of dtstart.
+func (r *rrule) next(dtstart, after time.Time) (time.Time, bool) {
+	loc := dtstart.Location()
+	after = after.In(loc)
+	var until time.Time
+	if r.until != "" {
+		var err error
+		if until, err = r.untilTime(loc); err != nil {
+			return time.Time{}, false
+		}
+	}
+
+	// The days before the one preceding after can be skipped, unless the
+	// occurrences must be counted from the start
+	day := dateOf(dtstart)
+	if r.count == 0 {
+		if previous := dateOf(after).AddDate(0, 0, -1); previous.After(day) {
+			day = previous
+		}
+	}
+
+	hours := r.byHour
+	if len(hours) == 0 {
+		hours = []int{dtstart.Hour()}
+	}
+	minutes := r.byMinute
+	if len(minutes) == 0 {
+		minutes = []int{dtstart.Minute()}
+	}
+	// The occurrences are at wall clock times, which are kept across the
+	// daylight saving time transitions
+	times := make([][2]int, 0, len(hours)*len(minutes))
+	for _, hour := range hours {
+		for _, minute := range minutes {
+			times = append(times, [2]int{hour, minute})
+		}
+	}
+	sort.Slice(times, func(i, j int) bool {
+		return times[i][0] < times[j][0] || times[i][0] == times[j][0] && times[i][1] < times[j][1]
+	})
+
+	last := dateOf(after).AddDate(0, 0, maxRRuleDays)
+	count := 0
+	for ; !day.After(last); day = day.AddDate(0, 0, 1) {
+		if !until.IsZero() && day.After(until) {
+			return time.Time{}, false
+		}
+		if !r.matches(dateOf(dtstart), day) {
+			continue
+		}
+		for _, clock := range times {
+			t := wallClock(day, clock[0], clock[1], dtstart.Second(), loc)
+			if t.Before(dtstart) {
+				continue
+			}
+			if !until.IsZero() && t.After(until) {
+				return time.Time{}, false
+			}
+			count++
+			if r.count > 0 && count > r.count {
+				return time.Time{}, false
+			}
+			if t.After(after) {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// wallClock returns the time of the given day at the given wall clock time in
+// loc. As in RFC 5545, a time skipped by a daylight saving time transition is
+// interpreted with the offset before the transition, e.g. 02:00 becomes 03:00
+// when the clocks jump from 02:00 to 03:00.
+func wallClock(day time.Time, hour, minute, second int, loc *time.Location) time.Time {
+	t := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, second, 0, loc)
+	if t.Hour() == hour && t.Minute() == minute {
+		return t
+	}
+	_, offset := t.Add(-12 * time.Hour).Zone()
+	utc := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, second, 0, time.UTC)
+	return utc.Add(-time.Duration(offset) * time.Second).In(loc)
+}
+
+// matches returns whether the rule occurs on the given day.
+func (r *rrule) matches(start, day time.Time) bool {
+	switch r.freq {
+	case "DAILY":
+		if daysBetween(start, day)%r.interval != 0 {
+			return false
+		}
+	case "WEEKLY":
+		if daysBetween(weekStart(start), weekStart(day))/7%r.interval != 0 {
+			return false
+		}
+		if len(r.byDay) == 0 && day.Weekday() != start.Weekday() {
+			return false
+		}
+	case "MONTHLY":
+		months := (day.Year()-start.Year())*12 + int(day.Month()) - int(start.Month())
+		if months%r.interval != 0 {
+			return false
+		}
+		if len(r.byDay) == 0 && len(r.byMonthDay) == 0 && day.Day() != start.Day() {
+			return false
+		}
+	case "YEARLY":
+		if (day.Year()-start.Year())%r.interval != 0 {
+			return false
+		}
+		if len(r.byMonth) == 0 && day.Month() != start.Month() {
+			return false
+		}
+		if len(r.byDay) == 0 && len(r.byMonthDay) == 0 && day.Day() != start.Day() {
+			return false
+		}
+	}
+	if len(r.byMonth) > 0 && !containsInt(r.byMonth, int(day.Month())) {
+		return false
+	}
+	if len(r.byMonthDay) > 0 {
+		daysInMonth := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+		if !containsInt(r.byMonthDay, day.Day()) && !containsInt(r.byMonthDay, day.Day()-daysInMonth-1) {
+			return false
+		}
+	}
+	if len(r.byDay) > 0 {
+		found := false
+		for _, weekday := range r.byDay {
+			if day.Weekday() == weekday {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// dateOf returns the date of t, at midnight UTC, so that the days can be
+// counted regardless of the daylight saving time transitions.
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func daysBetween(a, b time.Time) int {
+	return int(b.Sub(a).Hours() / 24)
+}
+
+// weekStart returns the monday of the week of the given date.
+func weekStart(date time.Time) time.Time {
+	offset := (int(date.Weekday()) + 6) % 7
+	return date.AddDate(0, 0, -offset)
+}
+
+func containsInt(values []int, i int) bool {
+	for _, value := range values {
+		if value == i {
+			return true
+		}
+	}
+	return false
+}
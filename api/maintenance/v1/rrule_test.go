@@ -0,0 +1,141 @@
+package v1
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRRule(t *testing.T) {
+	tests := []struct {
+		rule    string
+		wantErr bool
+	}{
+		{rule: "FREQ=DAILY"},
+		{rule: "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE"},
+		{rule: "FREQ=MONTHLY;BYMONTHDAY=-1;BYHOUR=3;BYMINUTE=30"},
+		{rule: "FREQ=YEARLY;BYMONTH=12;BYMONTHDAY=25;UNTIL=20301225"},
+		{rule: "INTERVAL=2", wantErr: true},
+		{rule: "FREQ=HOURLY", wantErr: true},
+		{rule: "FREQ=DAILY;INTERVAL=0", wantErr: true},
+		{rule: "FREQ=MONTHLY;BYDAY=1MO", wantErr: true},
+		{rule: "FREQ=MONTHLY;BYMONTHDAY=32", wantErr: true},
+		{rule: "FREQ=DAILY;COUNT=2;UNTIL=20301225", wantErr: true},
+		{rule: "FREQ=DAILY;BYSETPOS=1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			if _, err := parseRRule(tt.rule); (err != nil) != tt.wantErr {
+				t.Errorf("parseRRule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRRuleNext(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	tests := []struct {
+		name    string
+		rule    string
+		dtstart time.Time
+		after   time.Time
+		want    time.Time
+		wantOK  bool
+	}{
+		{
+			name:    "first occurrence",
+			rule:    "FREQ=DAILY",
+			dtstart: time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC),
+			after:   time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+			want:    time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC),
+			wantOK:  true,
+		},
+		{
+			name:    "daily interval",
+			rule:    "FREQ=DAILY;INTERVAL=3",
+			dtstart: time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC),
+			after:   time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC),
+			want:    time.Date(2024, 1, 7, 2, 0, 0, 0, time.UTC),
+			wantOK:  true,
+		},
+		{
+			name:    "last day of the month",
+			rule:    "FREQ=MONTHLY;BYMONTHDAY=-1",
+			dtstart: time.Date(2024, 1, 31, 2, 0, 0, 0, time.UTC),
+			after:   time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+			want:    time.Date(2024, 2, 29, 2, 0, 0, 0, time.UTC),
+			wantOK:  true,
+		},
+		{
+			name:    "hours and minutes",
+			rule:    "FREQ=DAILY;BYHOUR=6,18;BYMINUTE=15",
+			dtstart: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			after:   time.Date(2024, 1, 1, 6, 15, 0, 0, time.UTC),
+			want:    time.Date(2024, 1, 1, 18, 15, 0, 0, time.UTC),
+			wantOK:  true,
+		},
+		{
+			name:    "yearly",
+			rule:    "FREQ=YEARLY",
+			dtstart: time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC),
+			after:   time.Date(2024, 12, 26, 0, 0, 0, 0, time.UTC),
+			want:    time.Date(2025, 12, 25, 0, 0, 0, 0, time.UTC),
+			wantOK:  true,
+		},
+		{
+			name:    "daylight saving time",
+			rule:    "FREQ=WEEKLY;BYDAY=SU",
+			dtstart: time.Date(2024, 3, 3, 1, 0, 0, 0, newYork),
+			after:   time.Date(2024, 3, 4, 0, 0, 0, 0, newYork),
+			want:    time.Date(2024, 3, 10, 1, 0, 0, 0, newYork),
+			wantOK:  true,
+		},
+		{
+			name:    "02:00 when daylight saving time starts",
+			rule:    "FREQ=WEEKLY;BYDAY=SU;BYHOUR=2",
+			dtstart: time.Date(2026, 1, 4, 2, 0, 0, 0, newYork),
+			after:   time.Date(2026, 3, 2, 0, 0, 0, 0, newYork),
+			// 02:00 doesn't exist that day, the clocks jump to 03:00 EDT
+			want:   time.Date(2026, 3, 8, 7, 0, 0, 0, time.UTC),
+			wantOK: true,
+		},
+		{
+			name:    "02:00 when daylight saving time ends",
+			rule:    "FREQ=WEEKLY;BYDAY=SU;BYHOUR=2",
+			dtstart: time.Date(2026, 1, 4, 2, 0, 0, 0, newYork),
+			after:   time.Date(2026, 10, 26, 0, 0, 0, 0, newYork),
+			// 02:00 EST, not 01:00 EST
+			want:   time.Date(2026, 11, 1, 7, 0, 0, 0, time.UTC),
+			wantOK: true,
+		},
+		{
+			name:    "until",
+			rule:    "FREQ=DAILY;UNTIL=20240102T000000Z",
+			dtstart: time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC),
+			after:   time.Date(2024, 1, 1, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "count",
+			rule:    "FREQ=DAILY;COUNT=2",
+			dtstart: time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC),
+			after:   time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := parseRRule(tt.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, ok := rule.next(tt.dtstart, tt.after)
+			if ok != tt.wantOK {
+				t.Fatalf("next() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+)
+
+const (
+	// EscalationPolicyType is the type name of the EscalationPolicy resource.
+	EscalationPolicyType = "EscalationPolicy"
+
+	// EscalationPoliciesResource is the name of the EscalationPolicy
+	// resource, as used for storage, RBAC and API paths.
+	EscalationPoliciesResource = "escalation_policies"
+)
+
+var _ corev3.Resource = new(EscalationPolicy)
+
+// EscalationPolicy is a pipeline handler that notifies a sequence of handlers
+// for as long as an incident stays open. It can be referenced from a pipeline
+// workflow with the pipeline/v1 API version and the EscalationPolicy type.
+//
+// An incident is opened when a failing event is handled by the policy, and is
+// closed when the event resolves or is deleted. The steps of the policy are
+// run in order, each after its delay, and are postponed while the event is
+// silenced.
+type EscalationPolicy struct {
+	// Metadata contains the name, namespace, labels and annotations of the
+	// policy.
+	Metadata *corev2.ObjectMeta `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+
+	// Steps are the steps of the policy, in the order they are run.
+	Steps []*EscalationStep `json:"steps" yaml:"steps"`
+
+	// Repeat configures whether the steps are run again once the last step
+	// has run. When nil, the steps are run once.
+	Repeat *EscalationRepeat `json:"repeat,omitempty" yaml:"repeat,omitempty"`
+}
+
+// EscalationStep is a step of an EscalationPolicy.
+type EscalationStep struct {
+	// Delay is the number of seconds to wait before running the step, from
+	// the opening of the incident for the first step, and from the previous
+	// step for the other steps.
+	Delay uint32 `json:"delay" yaml:"delay"`
+
+	// Handlers are the handlers notified by the step, e.g. core/v2 Handler or
+	// pipeline/v1 HTTPHandler references.
+	Handlers []*corev2.ResourceReference `json:"handlers" yaml:"handlers"`
+}
+
+// EscalationRepeat configures how the steps of an EscalationPolicy are
+// repeated.
+type EscalationRepeat struct {
+	// Count is the number of times the steps are repeated. Zero repeats the
+	// steps until the incident is closed.
+	Count uint32 `json:"count" yaml:"count"`
+
+	// Interval is the number of seconds to wait after the last step before
+	// repeating the first one.
+	Interval uint32 `json:"interval" yaml:"interval"`
+}
+
+// FixtureEscalationPolicy returns an EscalationPolicy fixture for testing.
+func FixtureEscalationPolicy(name string) *EscalationPolicy {
+	return &EscalationPolicy{
+		Metadata: corev2.NewObjectMetaP(name, "default"),
+		Steps: []*EscalationStep{
+			{
+				Handlers: []*corev2.ResourceReference{
+					{APIVersion: "core/v2", Type: "Handler", Name: "primary"},
+				},
+			},
+			{
+				Delay: 900,
+				Handlers: []*corev2.ResourceReference{
+					{APIVersion: "core/v2", Type: "Handler", Name: "secondary"},
+				},
+			},
+		},
+	}
+}
+
+// GetMetadata returns the metadata of the policy.
+func (p *EscalationPolicy) GetMetadata() *corev2.ObjectMeta {
+	return p.Metadata
+}
+
+// SetMetadata sets the metadata of the policy.
+func (p *EscalationPolicy) SetMetadata(meta *corev2.ObjectMeta) {
+	p.Metadata = meta
+}
+
+// StoreName returns the store name of the policy.
+func (p *EscalationPolicy) StoreName() string {
+	return EscalationPoliciesResource
+}
+
+// RBACName returns the RBAC name of the policy.
+func (p *EscalationPolicy) RBACName() string {
+	return EscalationPoliciesResource
+}
+
+// URIPath returns the API path of the policy.
+func (p *EscalationPolicy) URIPath() string {
+	if p.Metadata == nil {
+		return uriPath(EscalationPoliciesResource, "", "")
+	}
+	return uriPath(EscalationPoliciesResource, p.Metadata.Namespace, p.Metadata.Name)
+}
+
+// GetTypeMeta returns the type metadata of the policy.
+func (p *EscalationPolicy) GetTypeMeta() corev2.TypeMeta {
+	return corev2.TypeMeta{
+		APIVersion: APIVersion,
+		Type:       EscalationPolicyType,
+	}
+}
+
+// Validate checks that the policy is valid.
+func (p *EscalationPolicy) Validate() error {
+	if p == nil {
+		return errors.New("nil EscalationPolicy")
+	}
+	if err := corev3.ValidateMetadata(p.Metadata); err != nil {
+		return fmt.Errorf("invalid EscalationPolicy: %s", err)
+	}
+	if len(p.Steps) == 0 {
+		return errors.New("at least one step must be set")
+	}
+	for i, step := range p.Steps {
+		if step == nil || len(step.Handlers) == 0 {
+			return fmt.Errorf("step %d must have at least one handler", i)
+		}
+		for _, ref := range step.Handlers {
+			if ref == nil {
+				return fmt.Errorf("step %d has a nil handler", i)
+			}
+			if err := ref.Validate(); err != nil {
+				return fmt.Errorf("step %d has an invalid handler: %s", i, err)
+			}
+			if ref.APIVersion == APIVersion && ref.Type == EscalationPolicyType {
+				return fmt.Errorf("step %d can't reference an escalation policy", i)
+			}
+		}
+	}
+	if p.Repeat != nil && p.Repeat.Interval == 0 {
+		return errors.New("repeat interval must be greater than 0")
+	}
+	return nil
+}
+
+// StepDelay returns the delay of the step at index i.
+func (p *EscalationPolicy) StepDelay(i int) time.Duration {
+	if i < 0 || i >= len(p.Steps) {
+		return 0
+	}
+	return time.Duration(p.Steps[i].Delay) * time.Second
+}
+
+// EscalationPolicyFields returns a set of fields that represent the resource.
+func EscalationPolicyFields(r corev3.Resource) map[string]string {
+	resource := r.(*EscalationPolicy)
+	fields := map[string]string{
+		"escalation_policy.name":      resource.Metadata.Name,
+		"escalation_policy.namespace": resource.Metadata.Namespace,
+		"escalation_policy.steps":     strconv.Itoa(len(resource.Steps)),
+	}
+	for k, v := range resource.Metadata.Labels {
+		fields["escalation_policy.labels."+k] = v
+	}
+	return fields
+}
+
+// Fields returns a set of fields that represent the resource.
+func (p *EscalationPolicy) Fields() map[string]string {
+	return EscalationPolicyFields(p)
+}
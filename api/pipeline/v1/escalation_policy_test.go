@@ -0,0 +1,95 @@
+package v1
+
+import (
+	"testing"
+
+	corev2 "github.com/sensu/core/v2"
+	apitools "github.com/sensu/sensu-api-tools"
+)
+
+func TestEscalationPolicyValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*EscalationPolicy)
+		wantErr bool
+	}{
+		{
+			name:   "valid fixture",
+			mutate: func(*EscalationPolicy) {},
+		},
+		{
+			name:    "no steps",
+			mutate:  func(p *EscalationPolicy) { p.Steps = nil },
+			wantErr: true,
+		},
+		{
+			name:    "step without handlers",
+			mutate:  func(p *EscalationPolicy) { p.Steps[1].Handlers = nil },
+			wantErr: true,
+		},
+		{
+			name: "invalid handler reference",
+			mutate: func(p *EscalationPolicy) {
+				p.Steps[0].Handlers = []*corev2.ResourceReference{{Name: "primary"}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "escalation policy reference",
+			mutate: func(p *EscalationPolicy) {
+				p.Steps[0].Handlers = []*corev2.ResourceReference{{APIVersion: APIVersion, Type: EscalationPolicyType, Name: "other"}}
+			},
+			wantErr: true,
+		},
+		{
+			name:   "repeat",
+			mutate: func(p *EscalationPolicy) { p.Repeat = &EscalationRepeat{Count: 2, Interval: 3600} },
+		},
+		{
+			name:    "repeat without interval",
+			mutate:  func(p *EscalationPolicy) { p.Repeat = &EscalationRepeat{Count: 2} },
+			wantErr: true,
+		},
+		{
+			name:    "nil metadata",
+			mutate:  func(p *EscalationPolicy) { p.Metadata = nil },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := FixtureEscalationPolicy("on-call")
+			tt.mutate(p)
+			if err := p.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("EscalationPolicy.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEscalationPolicyURIPath(t *testing.T) {
+	p := FixtureEscalationPolicy("on-call")
+	if got, want := p.URIPath(), "/api/pipeline/v1/namespaces/default/escalation_policies/on-call"; got != want {
+		t.Errorf("EscalationPolicy.URIPath() = %q, want %q", got, want)
+	}
+}
+
+func TestEscalationPolicyResolve(t *testing.T) {
+	v, err := apitools.Resolve(APIVersion, EscalationPolicyType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := v.(*EscalationPolicy); !ok {
+		t.Fatalf("unexpected type %T", v)
+	}
+
+	workflow := &corev2.PipelineWorkflow{Name: "workflow"}
+	workflow.Handler = &corev2.ResourceReference{
+		APIVersion: APIVersion,
+		Type:       EscalationPolicyType,
+		Name:       "on-call",
+	}
+	if err := workflow.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
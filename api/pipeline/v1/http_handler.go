@@ -0,0 +1,260 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+)
+
+const (
+	// HTTPHandlerType is the type name of the HTTPHandler resource.
+	HTTPHandlerType = "HTTPHandler"
+
+	// HTTPHandlersResource is the name of the HTTPHandler resource, as used
+	// for storage, RBAC and API paths.
+	HTTPHandlersResource = "http_handlers"
+
+	// DefaultHTTPHandlerTimeout is the default timeout, in seconds, of a
+	// single HTTP request made by an HTTPHandler.
+	DefaultHTTPHandlerTimeout uint32 = 10
+
+	// DefaultHTTPHandlerMaxAttempts is the default number of attempts that
+	// are made to deliver an event when a retry policy is configured without
+	// an explicit number of attempts.
+	DefaultHTTPHandlerMaxAttempts uint32 = 3
+)
+
+var _ corev3.Resource = new(HTTPHandler)
+
+// HTTPHandler is a pipeline handler that sends events, or the output of a
+// mutator, to an HTTP endpoint. It can be referenced from a pipeline workflow
+// with the pipeline/v1 API version and the HTTPHandler type.
+type HTTPHandler struct {
+	// Metadata contains the name, namespace, labels and annotations of the
+	// handler.
+	Metadata *corev2.ObjectMeta `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+
+	// URL is the URL of the endpoint to send events to. It can contain
+	// template actions, which are evaluated the same way as Body.
+	URL string `json:"url" yaml:"url"`
+
+	// Method is the HTTP method to use. Defaults to POST.
+	Method string `json:"method,omitempty" yaml:"method,omitempty"`
+
+	// Headers are the HTTP headers to set on each request. Header values can
+	// contain template actions, which are evaluated the same way as Body.
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+
+	// Body is an optional text/template used to render the request body.
+	// The template has access to .Event, the event being handled, .Data,
+	// the mutated event data as a string, and .Secrets, a map of the
+	// resolved secrets of the handler. When empty, the mutated event data is
+	// sent as-is.
+	Body string `json:"body,omitempty" yaml:"body,omitempty"`
+
+	// Timeout is the timeout of a single request, in seconds.
+	Timeout uint32 `json:"timeout" yaml:"timeout"`
+
+	// TLS contains the TLS configuration used when connecting to the
+	// endpoint. Only the client fields of TLSOptions are used.
+	TLS *corev2.TLSOptions `json:"tls,omitempty" yaml:"tls,omitempty"`
+
+	// Retry configures how failed requests are retried. When nil, requests
+	// are attempted once.
+	Retry *HTTPRetryPolicy `json:"retry,omitempty" yaml:"retry,omitempty"`
+
+	// Secrets is the list of Sensu secrets available to the URL, Headers and
+	// Body templates.
+	Secrets []*corev2.Secret `json:"secrets" yaml:"secrets"`
+}
+
+// HTTPRetryPolicy configures retries with exponential backoff. Requests are
+// retried when they fail at the transport level, or when the endpoint returns
+// a 429 or a 5xx status code.
+type HTTPRetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first
+	// one.
+	MaxAttempts uint32 `json:"max_attempts" yaml:"max_attempts"`
+
+	// InitialBackoff is the delay before the first retry, e.g. "1s".
+	InitialBackoff string `json:"initial_backoff,omitempty" yaml:"initial_backoff,omitempty"`
+
+	// MaxBackoff is the maximum delay between two attempts, e.g. "30s".
+	MaxBackoff string `json:"max_backoff,omitempty" yaml:"max_backoff,omitempty"`
+}
+
+// InitialBackoffDuration returns the parsed InitialBackoff, or one second if
+// it is not set.
+func (p *HTTPRetryPolicy) InitialBackoffDuration() time.Duration {
+	if p.InitialBackoff == "" {
+		return time.Second
+	}
+	d, _ := time.ParseDuration(p.InitialBackoff)
+	return d
+}
+
+// MaxBackoffDuration returns the parsed MaxBackoff, or zero if it is not set.
+func (p *HTTPRetryPolicy) MaxBackoffDuration() time.Duration {
+	if p.MaxBackoff == "" {
+		return 0
+	}
+	d, _ := time.ParseDuration(p.MaxBackoff)
+	return d
+}
+
+// Attempts returns the maximum number of attempts for the policy.
+func (p *HTTPRetryPolicy) Attempts() uint32 {
+	if p == nil {
+		return 1
+	}
+	if p.MaxAttempts == 0 {
+		return DefaultHTTPHandlerMaxAttempts
+	}
+	return p.MaxAttempts
+}
+
+func (p *HTTPRetryPolicy) validate() error {
+	for field, value := range map[string]string{
+		"initial_backoff": p.InitialBackoff,
+		"max_backoff":     p.MaxBackoff,
+	} {
+		if value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid retry %s: %s", field, err)
+		}
+		if d < 0 {
+			return fmt.Errorf("invalid retry %s: must not be negative", field)
+		}
+	}
+	return nil
+}
+
+// FixtureHTTPHandler returns an HTTPHandler fixture for testing.
+func FixtureHTTPHandler(name string) *HTTPHandler {
+	return &HTTPHandler{
+		Metadata: corev2.NewObjectMetaP(name, "default"),
+		URL:      "http://127.0.0.1:8080/events",
+		Method:   http.MethodPost,
+		Timeout:  DefaultHTTPHandlerTimeout,
+	}
+}
+
+// GetMetadata returns the metadata of the handler.
+func (h *HTTPHandler) GetMetadata() *corev2.ObjectMeta {
+	return h.Metadata
+}
+
+// SetMetadata sets the metadata of the handler.
+func (h *HTTPHandler) SetMetadata(meta *corev2.ObjectMeta) {
+	h.Metadata = meta
+}
+
+// StoreName returns the store name of the handler.
+func (h *HTTPHandler) StoreName() string {
+	return HTTPHandlersResource
+}
+
+// RBACName returns the RBAC name of the handler.
+func (h *HTTPHandler) RBACName() string {
+	return HTTPHandlersResource
+}
+
+// URIPath returns the API path of the handler.
+func (h *HTTPHandler) URIPath() string {
+	if h.Metadata == nil {
+		return uriPath(HTTPHandlersResource, "", "")
+	}
+	return uriPath(HTTPHandlersResource, h.Metadata.Namespace, h.Metadata.Name)
+}
+
+// GetTypeMeta returns the type metadata of the handler.
+func (h *HTTPHandler) GetTypeMeta() corev2.TypeMeta {
+	return corev2.TypeMeta{
+		APIVersion: APIVersion,
+		Type:       HTTPHandlerType,
+	}
+}
+
+// HTTPMethod returns the HTTP method of the handler, defaulting to POST.
+func (h *HTTPHandler) HTTPMethod() string {
+	if h.Method == "" {
+		return http.MethodPost
+	}
+	return strings.ToUpper(h.Method)
+}
+
+// Validate checks that the handler is valid.
+func (h *HTTPHandler) Validate() error {
+	if h == nil {
+		return errors.New("nil HTTPHandler")
+	}
+	if err := corev3.ValidateMetadata(h.Metadata); err != nil {
+		return fmt.Errorf("invalid HTTPHandler: %s", err)
+	}
+	if h.URL == "" {
+		return errors.New("url must be set")
+	}
+	if !strings.Contains(h.URL, "{{") {
+		u, err := url.Parse(h.URL)
+		if err != nil {
+			return fmt.Errorf("invalid url: %s", err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return fmt.Errorf("invalid url scheme %q: must be http or https", u.Scheme)
+		}
+	}
+	switch h.HTTPMethod() {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodGet, http.MethodDelete:
+	default:
+		return fmt.Errorf("unsupported method: %s", h.Method)
+	}
+	templates := map[string]string{"url": h.URL, "body": h.Body}
+	for name, value := range h.Headers {
+		templates["header "+name] = value
+	}
+	for name, text := range templates {
+		if _, err := template.New(name).Parse(text); err != nil {
+			return fmt.Errorf("invalid %s template: %s", name, err)
+		}
+	}
+	if h.Retry != nil {
+		if err := h.Retry.validate(); err != nil {
+			return err
+		}
+	}
+	for _, secret := range h.Secrets {
+		if secret == nil || secret.Name == "" || secret.Secret == "" {
+			return errors.New("secrets must have a name and a secret")
+		}
+	}
+	return nil
+}
+
+// HTTPHandlerFields returns a set of fields that represent the resource.
+func HTTPHandlerFields(r corev3.Resource) map[string]string {
+	resource := r.(*HTTPHandler)
+	fields := map[string]string{
+		"http_handler.name":      resource.Metadata.Name,
+		"http_handler.namespace": resource.Metadata.Namespace,
+		"http_handler.method":    resource.HTTPMethod(),
+	}
+	for k, v := range resource.Metadata.Labels {
+		fields["http_handler.labels."+k] = v
+	}
+	return fields
+}
+
+// Fields returns a set of fields that represent the resource.
+func (h *HTTPHandler) Fields() map[string]string {
+	return HTTPHandlerFields(h)
+}
@@ -0,0 +1,132 @@
+package v1
+
+import (
+	"encoding/json"
+	"testing"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/core/v3/types"
+	apitools "github.com/sensu/sensu-api-tools"
+)
+
+func TestHTTPHandlerValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*HTTPHandler)
+		wantErr bool
+	}{
+		{
+			name:   "valid fixture",
+			mutate: func(*HTTPHandler) {},
+		},
+		{
+			name:    "missing url",
+			mutate:  func(h *HTTPHandler) { h.URL = "" },
+			wantErr: true,
+		},
+		{
+			name:    "unsupported scheme",
+			mutate:  func(h *HTTPHandler) { h.URL = "ftp://example.com" },
+			wantErr: true,
+		},
+		{
+			name:   "templated url",
+			mutate: func(h *HTTPHandler) { h.URL = "{{ .Secrets.WEBHOOK_URL }}" },
+		},
+		{
+			name:    "unsupported method",
+			mutate:  func(h *HTTPHandler) { h.Method = "CONNECT" },
+			wantErr: true,
+		},
+		{
+			name:    "invalid body template",
+			mutate:  func(h *HTTPHandler) { h.Body = "{{ .Event" },
+			wantErr: true,
+		},
+		{
+			name:    "invalid header template",
+			mutate:  func(h *HTTPHandler) { h.Headers = map[string]string{"X-Foo": "{{ end }}"} },
+			wantErr: true,
+		},
+		{
+			name:    "invalid retry backoff",
+			mutate:  func(h *HTTPHandler) { h.Retry = &HTTPRetryPolicy{InitialBackoff: "soon"} },
+			wantErr: true,
+		},
+		{
+			name:    "incomplete secret",
+			mutate:  func(h *HTTPHandler) { h.Secrets = []*corev2.Secret{{Name: "TOKEN"}} },
+			wantErr: true,
+		},
+		{
+			name:    "nil metadata",
+			mutate:  func(h *HTTPHandler) { h.Metadata = nil },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := FixtureHTTPHandler("webhook")
+			tt.mutate(h)
+			if err := h.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("HTTPHandler.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHTTPRetryPolicyAttempts(t *testing.T) {
+	var policy *HTTPRetryPolicy
+	if got, want := policy.Attempts(), uint32(1); got != want {
+		t.Errorf("nil policy attempts = %d, want %d", got, want)
+	}
+	policy = &HTTPRetryPolicy{}
+	if got, want := policy.Attempts(), DefaultHTTPHandlerMaxAttempts; got != want {
+		t.Errorf("default policy attempts = %d, want %d", got, want)
+	}
+}
+
+func TestHTTPHandlerURIPath(t *testing.T) {
+	h := FixtureHTTPHandler("web hook")
+	if got, want := h.URIPath(), "/api/pipeline/v1/namespaces/default/http_handlers/web%20hook"; got != want {
+		t.Errorf("HTTPHandler.URIPath() = %q, want %q", got, want)
+	}
+}
+
+func TestHTTPHandlerResolve(t *testing.T) {
+	v, err := apitools.Resolve(APIVersion, HTTPHandlerType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := v.(*HTTPHandler); !ok {
+		t.Fatalf("unexpected type %T", v)
+	}
+
+	b, err := json.Marshal(types.WrapResource(FixtureHTTPHandler("webhook")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var w types.Wrapper
+	if err := json.Unmarshal(b, &w); err != nil {
+		t.Fatal(err)
+	}
+	handler, ok := w.Value.(*HTTPHandler)
+	if !ok {
+		t.Fatalf("unexpected type %T", w.Value)
+	}
+	if err := handler.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPipelineWorkflowHandlerReference(t *testing.T) {
+	workflow := &corev2.PipelineWorkflow{Name: "workflow"}
+	workflow.Handler = &corev2.ResourceReference{
+		APIVersion: APIVersion,
+		Type:       HTTPHandlerType,
+		Name:       "webhook",
+	}
+	if err := workflow.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,41 @@
+// Package v1 contains the pipeline/v1 API group. Resources in this group
+// extend the core/v2 pipeline resources (Pipeline, Handler, EventFilter and
+// Mutator) with resource types that are provided natively by the backend.
+package v1
+
+import (
+	"net/url"
+	"path"
+
+	corev2 "github.com/sensu/core/v2"
+	apitools "github.com/sensu/sensu-api-tools"
+)
+
+// APIVersion is the API version of the resources in this package.
+const APIVersion = "pipeline/v1"
+
+func init() {
+	apitools.RegisterType(APIVersion, new(HTTPHandler), apitools.WithAlias("http_handler", "http_handlers"))
+	apitools.RegisterType(APIVersion, new(EscalationPolicy), apitools.WithAlias("escalation_policy", "escalation_policies"))
+	apitools.RegisterType(APIVersion, new(ThrottleFilter), apitools.WithAlias("throttle_filter", "throttle_filters"))
+
+	corev2.AddValidPipelineWorkflowHandlerReference(corev2.ResourceReference{
+		APIVersion: APIVersion,
+		Type:       HTTPHandlerType,
+	})
+	corev2.AddValidPipelineWorkflowHandlerReference(corev2.ResourceReference{
+		APIVersion: APIVersion,
+		Type:       EscalationPolicyType,
+	})
+	corev2.AddValidPipelineWorkflowFilterReference(corev2.ResourceReference{
+		APIVersion: APIVersion,
+		Type:       ThrottleFilterType,
+	})
+}
+
+func uriPath(typename, namespace, name string) string {
+	if namespace == "" {
+		return path.Join("/api", "pipeline", "v1", typename, url.PathEscape(name))
+	}
+	return path.Join("/api", "pipeline", "v1", "namespaces", url.PathEscape(namespace), typename, url.PathEscape(name))
+}
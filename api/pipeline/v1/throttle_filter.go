@@ -0,0 +1,201 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+)
+
+const (
+	// ThrottleFilterType is the type name of the ThrottleFilter resource.
+	ThrottleFilterType = "ThrottleFilter"
+
+	// ThrottleFiltersResource is the name of the ThrottleFilter resource, as
+	// used for storage, RBAC and API paths.
+	ThrottleFiltersResource = "throttle_filters"
+
+	// ThrottleKeyEntity and ThrottleKeyCheck are the key elements of a
+	// ThrottleFilter that select the entity name and the check name of the
+	// event.
+	ThrottleKeyEntity = "entity"
+	ThrottleKeyCheck  = "check"
+
+	// ThrottleKeyLabelPrefix prefixes the key elements of a ThrottleFilter
+	// that select the value of a label of the event, e.g. "labels.service".
+	ThrottleKeyLabelPrefix = "labels."
+)
+
+var _ corev3.Resource = new(ThrottleFilter)
+
+// ThrottleFilter is a pipeline filter that throttles the notifications of
+// repeated events. It can be referenced from a pipeline workflow with the
+// pipeline/v1 API version and the ThrottleFilter type.
+//
+// The failing events are counted by key. The first occurrences of a key are
+// allowed, then at most one event is allowed per interval. The count of a key
+// is reset when a passing event of the key is filtered, which is always
+// allowed.
+type ThrottleFilter struct {
+	// Metadata contains the name, namespace, labels and annotations of the
+	// filter.
+	Metadata *corev2.ObjectMeta `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+
+	// Occurrences is the number of first occurrences of a key that are
+	// allowed.
+	Occurrences uint32 `json:"occurrences" yaml:"occurrences"`
+
+	// Interval is the minimum number of seconds between two allowed events
+	// of a key, once its first occurrences were allowed. Zero denies the
+	// events of the key until it is reset.
+	Interval uint32 `json:"interval" yaml:"interval"`
+
+	// Key are the elements of the event that the occurrences are counted
+	// by: "entity", "check", or "labels.<name>" for the value of the label of
+	// the event, check or entity, in this order. Defaults to the entity and
+	// the check. The events of every entity and check with the same key
+	// share their occurrences, e.g. with only a label in the key, a passing
+	// event of one entity resets the count of all the entities with the same
+	// label value. Add "entity" to the key to count them separately.
+	Key []string `json:"key,omitempty" yaml:"key,omitempty"`
+}
+
+// FixtureThrottleFilter returns a ThrottleFilter fixture for testing.
+func FixtureThrottleFilter(name string) *ThrottleFilter {
+	return &ThrottleFilter{
+		Metadata:    corev2.NewObjectMetaP(name, "default"),
+		Occurrences: 3,
+		Interval:    1800,
+	}
+}
+
+// GetMetadata returns the metadata of the filter.
+func (f *ThrottleFilter) GetMetadata() *corev2.ObjectMeta {
+	return f.Metadata
+}
+
+// SetMetadata sets the metadata of the filter.
+func (f *ThrottleFilter) SetMetadata(meta *corev2.ObjectMeta) {
+	f.Metadata = meta
+}
+
+// StoreName returns the store name of the filter.
+func (f *ThrottleFilter) StoreName() string {
+	return ThrottleFiltersResource
+}
+
+// RBACName returns the RBAC name of the filter.
+func (f *ThrottleFilter) RBACName() string {
+	return ThrottleFiltersResource
+}
+
+// URIPath returns the API path of the filter.
+func (f *ThrottleFilter) URIPath() string {
+	if f.Metadata == nil {
+		return uriPath(ThrottleFiltersResource, "", "")
+	}
+	return uriPath(ThrottleFiltersResource, f.Metadata.Namespace, f.Metadata.Name)
+}
+
+// GetTypeMeta returns the type metadata of the filter.
+func (f *ThrottleFilter) GetTypeMeta() corev2.TypeMeta {
+	return corev2.TypeMeta{
+		APIVersion: APIVersion,
+		Type:       ThrottleFilterType,
+	}
+}
+
+// Validate checks that the filter is valid.
+func (f *ThrottleFilter) Validate() error {
+	if f == nil {
+		return errors.New("nil ThrottleFilter")
+	}
+	if err := corev3.ValidateMetadata(f.Metadata); err != nil {
+		return fmt.Errorf("invalid ThrottleFilter: %s", err)
+	}
+	if f.Occurrences == 0 && f.Interval == 0 {
+		return errors.New("occurrences or interval must be set")
+	}
+	for _, element := range f.Key {
+		switch {
+		case element == ThrottleKeyEntity, element == ThrottleKeyCheck:
+		case strings.HasPrefix(element, ThrottleKeyLabelPrefix) && len(element) > len(ThrottleKeyLabelPrefix):
+		default:
+			return fmt.Errorf("invalid key element %q: must be entity, check or labels.<name>", element)
+		}
+	}
+	return nil
+}
+
+// IntervalDuration returns the interval of the filter.
+func (f *ThrottleFilter) IntervalDuration() time.Duration {
+	return time.Duration(f.Interval) * time.Second
+}
+
+// EventKey returns the key of the event, which its occurrences are counted
+// by.
+func (f *ThrottleFilter) EventKey(event *corev2.Event) string {
+	key := f.Key
+	if len(key) == 0 {
+		key = []string{ThrottleKeyEntity, ThrottleKeyCheck}
+	}
+	values := make([]string, 0, len(key))
+	for _, element := range key {
+		var value string
+		switch {
+		case element == ThrottleKeyEntity:
+			if event.Entity != nil {
+				value = event.Entity.Name
+			}
+		case element == ThrottleKeyCheck:
+			if event.Check != nil {
+				value = event.Check.Name
+			}
+		default:
+			value = eventLabel(event, strings.TrimPrefix(element, ThrottleKeyLabelPrefix))
+		}
+		values = append(values, strconv.Quote(value))
+	}
+	return strings.Join(values, ",")
+}
+
+// eventLabel returns the value of a label of the event, or of its check or
+// entity.
+func eventLabel(event *corev2.Event, name string) string {
+	if value, ok := event.ObjectMeta.Labels[name]; ok {
+		return value
+	}
+	if event.Check != nil {
+		if value, ok := event.Check.ObjectMeta.Labels[name]; ok {
+			return value
+		}
+	}
+	if event.Entity != nil {
+		if value, ok := event.Entity.ObjectMeta.Labels[name]; ok {
+			return value
+		}
+	}
+	return ""
+}
+
+// ThrottleFilterFields returns a set of fields that represent the resource.
+func ThrottleFilterFields(r corev3.Resource) map[string]string {
+	resource := r.(*ThrottleFilter)
+	fields := map[string]string{
+		"throttle_filter.name":      resource.Metadata.Name,
+		"throttle_filter.namespace": resource.Metadata.Namespace,
+	}
+	for k, v := range resource.Metadata.Labels {
+		fields["throttle_filter.labels."+k] = v
+	}
+	return fields
+}
+
+// Fields returns a set of fields that represent the resource.
+func (f *ThrottleFilter) Fields() map[string]string {
+	return ThrottleFilterFields(f)
+}
@@ -0,0 +1,84 @@
+package v1
+
+import (
+	"testing"
+
+	corev2 "github.com/sensu/core/v2"
+)
+
+func TestThrottleFilterValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*ThrottleFilter)
+		wantErr bool
+	}{
+		{
+			name:   "valid fixture",
+			mutate: func(*ThrottleFilter) {},
+		},
+		{
+			name:   "occurrences only",
+			mutate: func(f *ThrottleFilter) { f.Interval = 0 },
+		},
+		{
+			name:    "no occurrences nor interval",
+			mutate:  func(f *ThrottleFilter) { f.Occurrences, f.Interval = 0, 0 },
+			wantErr: true,
+		},
+		{
+			name:   "label key",
+			mutate: func(f *ThrottleFilter) { f.Key = []string{"check", "labels.service"} },
+		},
+		{
+			name:    "invalid key",
+			mutate:  func(f *ThrottleFilter) { f.Key = []string{"labels."} },
+			wantErr: true,
+		},
+		{
+			name:    "nil metadata",
+			mutate:  func(f *ThrottleFilter) { f.Metadata = nil },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := FixtureThrottleFilter("throttle")
+			tt.mutate(f)
+			if err := f.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("ThrottleFilter.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestThrottleFilterEventKey(t *testing.T) {
+	event := corev2.FixtureEvent("entity1", "check1")
+	event.Entity.Labels = map[string]string{"service": "web", "region": "us"}
+	event.Check.Labels = map[string]string{"service": "api"}
+
+	f := FixtureThrottleFilter("throttle")
+	if got, want := f.EventKey(event), `"entity1","check1"`; got != want {
+		t.Errorf("EventKey() = %s, want %s", got, want)
+	}
+
+	// The labels of the check take precedence over those of the entity
+	f.Key = []string{"labels.service", "labels.region", "labels.missing"}
+	if got, want := f.EventKey(event), `"api","us",""`; got != want {
+		t.Errorf("EventKey() = %s, want %s", got, want)
+	}
+}
+
+func TestThrottleFilterWorkflowReference(t *testing.T) {
+	workflow := &corev2.PipelineWorkflow{
+		Name: "workflow",
+		Filters: []*corev2.ResourceReference{{
+			APIVersion: APIVersion,
+			Type:       ThrottleFilterType,
+			Name:       "throttle",
+		}},
+		Handler: &corev2.ResourceReference{APIVersion: "core/v2", Type: "Handler", Name: "slack"},
+	}
+	if err := workflow.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
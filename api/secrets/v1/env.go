@@ -0,0 +1,108 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+)
+
+const (
+	// EnvType is the type name of the Env resource.
+	EnvType = "Env"
+
+	// ProvidersResource is the name of the secrets provider resources, as used
+	// for storage, RBAC and API paths.
+	ProvidersResource = "providers"
+)
+
+var (
+	_ corev3.Resource       = new(Env)
+	_ corev3.GlobalResource = new(Env)
+)
+
+// Env configures a secrets provider that reads the secrets from the
+// environment variables of the backend. The ID of a secret is the name of an
+// environment variable.
+type Env struct {
+	// Metadata contains the name, labels and annotations of the provider.
+	// Secrets providers are global resources and have no namespace.
+	Metadata *corev2.ObjectMeta `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+}
+
+// FixtureEnv returns an Env fixture for testing.
+func FixtureEnv(name string) *Env {
+	return &Env{
+		Metadata: corev2.NewObjectMetaP(name, ""),
+	}
+}
+
+// GetMetadata returns the metadata of the provider.
+func (e *Env) GetMetadata() *corev2.ObjectMeta {
+	return e.Metadata
+}
+
+// SetMetadata sets the metadata of the provider.
+func (e *Env) SetMetadata(meta *corev2.ObjectMeta) {
+	e.Metadata = meta
+}
+
+// StoreName returns the store name of the provider.
+func (e *Env) StoreName() string {
+	return ProvidersResource
+}
+
+// RBACName returns the RBAC name of the provider.
+func (e *Env) RBACName() string {
+	return ProvidersResource
+}
+
+// URIPath returns the API path of the provider.
+func (e *Env) URIPath() string {
+	if e.Metadata == nil {
+		return uriPath(envPath, "", "")
+	}
+	return uriPath(envPath, "", e.Metadata.Name)
+}
+
+// IsGlobalResource returns true, secrets providers are not namespaced.
+func (e *Env) IsGlobalResource() bool {
+	return true
+}
+
+// GetTypeMeta returns the type metadata of the provider.
+func (e *Env) GetTypeMeta() corev2.TypeMeta {
+	return corev2.TypeMeta{
+		APIVersion: APIVersion,
+		Type:       EnvType,
+	}
+}
+
+// Validate checks that the provider is valid.
+func (e *Env) Validate() error {
+	if e == nil {
+		return errors.New("nil Env")
+	}
+	if err := corev3.ValidateGlobalMetadata(e.Metadata); err != nil {
+		return fmt.Errorf("invalid Env: %s", err)
+	}
+	return nil
+}
+
+// EnvFields returns a set of fields that represent the resource.
+func EnvFields(r corev3.Resource) map[string]string {
+	resource := r.(*Env)
+	fields := map[string]string{
+		"env.name": resource.Metadata.Name,
+	}
+	for k, v := range resource.Metadata.Labels {
+		fields["env.labels."+k] = v
+	}
+	return fields
+}
+
+// Fields returns a set of fields that represent the resource.
+func (e *Env) Fields() map[string]string {
+	return EnvFields(e)
+}
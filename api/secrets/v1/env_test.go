@@ -0,0 +1,39 @@
+package v1
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sensu/core/v3/types"
+)
+
+func TestEnvValidate(t *testing.T) {
+	if err := FixtureEnv("env").Validate(); err != nil {
+		t.Fatal(err)
+	}
+	e := FixtureEnv("env")
+	e.Metadata.Namespace = "default"
+	if err := e.Validate(); err == nil {
+		t.Fatal("expected an error for a namespaced provider")
+	}
+}
+
+func TestEnvURIPath(t *testing.T) {
+	if got, want := FixtureEnv("env").URIPath(), "/api/secrets/v1/providers/env/env"; got != want {
+		t.Errorf("Env.URIPath() = %q, want %q", got, want)
+	}
+}
+
+func TestEnvResolve(t *testing.T) {
+	b, err := json.Marshal(types.WrapResource(FixtureEnv("env")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var w types.Wrapper
+	if err := json.Unmarshal(b, &w); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := w.Value.(*Env); !ok {
+		t.Fatalf("unexpected type %T", w.Value)
+	}
+}
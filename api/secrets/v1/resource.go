@@ -0,0 +1,34 @@
+// Package v1 contains the secrets/v1 API group. Resources in this group
+// configure the secrets providers of the backend, and the secrets that checks,
+// handlers and mutators can reference.
+package v1
+
+import (
+	"net/url"
+	"path"
+
+	apitools "github.com/sensu/sensu-api-tools"
+)
+
+// APIVersion is the API version of the resources in this package.
+const APIVersion = "secrets/v1"
+
+func init() {
+	apitools.RegisterType(APIVersion, new(Env), apitools.WithAlias("env"))
+	apitools.RegisterType(APIVersion, new(VaultProvider), apitools.WithAlias("vault_provider", "vault_providers"))
+	apitools.RegisterType(APIVersion, new(Secret), apitools.WithAlias("secret", "secrets"))
+}
+
+// Secrets providers share the providers resource, and each type of provider
+// has its own path under it.
+var (
+	envPath   = path.Join(ProvidersResource, "env")
+	vaultPath = path.Join(ProvidersResource, "vault")
+)
+
+func uriPath(resource, namespace, name string) string {
+	if namespace == "" {
+		return path.Join("/api", "secrets", "v1", resource, url.PathEscape(name))
+	}
+	return path.Join("/api", "secrets", "v1", "namespaces", url.PathEscape(namespace), resource, url.PathEscape(name))
+}
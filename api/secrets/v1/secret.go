@@ -0,0 +1,116 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+)
+
+const (
+	// SecretType is the type name of the Secret resource.
+	SecretType = "Secret"
+
+	// SecretsResource is the name of the Secret resource, as used for
+	// storage, RBAC and API paths.
+	SecretsResource = "secrets"
+)
+
+var _ corev3.Resource = new(Secret)
+
+// Secret maps the name of a Sensu secret, as referenced by the secrets of
+// checks, handlers and mutators, to a secret of a secrets provider.
+type Secret struct {
+	// Metadata contains the name, namespace, labels and annotations of the
+	// secret.
+	Metadata *corev2.ObjectMeta `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+
+	// ID identifies the secret in the provider. Its format depends on the
+	// type of the provider.
+	ID string `json:"id" yaml:"id"`
+
+	// Provider is the name of the secrets provider.
+	Provider string `json:"provider" yaml:"provider"`
+}
+
+// FixtureSecret returns a Secret fixture for testing.
+func FixtureSecret(name, namespace string) *Secret {
+	return &Secret{
+		Metadata: corev2.NewObjectMetaP(name, namespace),
+		ID:       "SENSU_SECRET",
+		Provider: "env",
+	}
+}
+
+// GetMetadata returns the metadata of the secret.
+func (s *Secret) GetMetadata() *corev2.ObjectMeta {
+	return s.Metadata
+}
+
+// SetMetadata sets the metadata of the secret.
+func (s *Secret) SetMetadata(meta *corev2.ObjectMeta) {
+	s.Metadata = meta
+}
+
+// StoreName returns the store name of the secret.
+func (s *Secret) StoreName() string {
+	return SecretsResource
+}
+
+// RBACName returns the RBAC name of the secret.
+func (s *Secret) RBACName() string {
+	return SecretsResource
+}
+
+// URIPath returns the API path of the secret.
+func (s *Secret) URIPath() string {
+	if s.Metadata == nil {
+		return uriPath(SecretsResource, "", "")
+	}
+	return uriPath(SecretsResource, s.Metadata.Namespace, s.Metadata.Name)
+}
+
+// GetTypeMeta returns the type metadata of the secret.
+func (s *Secret) GetTypeMeta() corev2.TypeMeta {
+	return corev2.TypeMeta{
+		APIVersion: APIVersion,
+		Type:       SecretType,
+	}
+}
+
+// Validate checks that the secret is valid.
+func (s *Secret) Validate() error {
+	if s == nil {
+		return errors.New("nil Secret")
+	}
+	if err := corev3.ValidateMetadata(s.Metadata); err != nil {
+		return fmt.Errorf("invalid Secret: %s", err)
+	}
+	if s.ID == "" {
+		return errors.New("id must be set")
+	}
+	if s.Provider == "" {
+		return errors.New("provider must be set")
+	}
+	return nil
+}
+
+// SecretFields returns a set of fields that represent the resource.
+func SecretFields(r corev3.Resource) map[string]string {
+	resource := r.(*Secret)
+	fields := map[string]string{
+		"secret.name":      resource.Metadata.Name,
+		"secret.namespace": resource.Metadata.Namespace,
+		"secret.provider":  resource.Provider,
+	}
+	for k, v := range resource.Metadata.Labels {
+		fields["secret.labels."+k] = v
+	}
+	return fields
+}
+
+// Fields returns a set of fields that represent the resource.
+func (s *Secret) Fields() map[string]string {
+	return SecretFields(s)
+}
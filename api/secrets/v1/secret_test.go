@@ -0,0 +1,73 @@
+package v1
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sensu/core/v3/types"
+	apitools "github.com/sensu/sensu-api-tools"
+)
+
+func TestSecretValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*Secret)
+		wantErr bool
+	}{
+		{
+			name:   "valid fixture",
+			mutate: func(*Secret) {},
+		},
+		{
+			name:    "missing id",
+			mutate:  func(s *Secret) { s.ID = "" },
+			wantErr: true,
+		},
+		{
+			name:    "missing provider",
+			mutate:  func(s *Secret) { s.Provider = "" },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := FixtureSecret("db-password", "default")
+			tt.mutate(s)
+			if err := s.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Secret.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSecretURIPath(t *testing.T) {
+	if got, want := FixtureSecret("db-password", "default").URIPath(), "/api/secrets/v1/namespaces/default/secrets/db-password"; got != want {
+		t.Errorf("Secret.URIPath() = %q, want %q", got, want)
+	}
+}
+
+func TestSecretResolve(t *testing.T) {
+	v, err := apitools.Resolve(APIVersion, SecretType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := v.(*Secret); !ok {
+		t.Fatalf("unexpected type %T", v)
+	}
+
+	b, err := json.Marshal(types.WrapResource(FixtureSecret("db-password", "default")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var w types.Wrapper
+	if err := json.Unmarshal(b, &w); err != nil {
+		t.Fatal(err)
+	}
+	secret, ok := w.Value.(*Secret)
+	if !ok {
+		t.Fatalf("unexpected type %T", w.Value)
+	}
+	if err := secret.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
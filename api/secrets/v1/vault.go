@@ -0,0 +1,235 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+)
+
+const (
+	// VaultProviderType is the type name of the VaultProvider resource.
+	VaultProviderType = "VaultProvider"
+
+	// VaultKVv1 is the version 1 of the Vault KV secrets engine.
+	VaultKVv1 = "v1"
+
+	// VaultKVv2 is the version 2 of the Vault KV secrets engine, which
+	// versions secrets.
+	VaultKVv2 = "v2"
+
+	// DefaultVaultTimeout is the default timeout, in seconds, of the requests
+	// made to Vault.
+	DefaultVaultTimeout uint32 = 60
+
+	// DefaultVaultCacheTTL is the default time, in seconds, secrets are
+	// cached for.
+	DefaultVaultCacheTTL uint32 = 300
+
+	// DefaultVaultAppRoleMountPath is the default mount path of the AppRole
+	// auth method.
+	DefaultVaultAppRoleMountPath = "approle"
+)
+
+var (
+	_ corev3.Resource       = new(VaultProvider)
+	_ corev3.GlobalResource = new(VaultProvider)
+)
+
+// VaultProvider configures a secrets provider that reads the secrets from the
+// KV secrets engine of HashiCorp Vault. The ID of a secret is the path of the
+// secret, including the mount of the secrets engine, and the key of the value
+// to read, separated by a #, e.g. secret/database#password.
+type VaultProvider struct {
+	// Metadata contains the name, labels and annotations of the provider.
+	// Secrets providers are global resources and have no namespace.
+	Metadata *corev2.ObjectMeta `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+
+	// Client configures how Vault is reached.
+	Client *VaultClient `json:"client" yaml:"client"`
+}
+
+// VaultClient configures the client of a Vault provider.
+type VaultClient struct {
+	// Address is the URL of the Vault server.
+	Address string `json:"address" yaml:"address"`
+
+	// Token authenticates the requests made to Vault. Either Token or AppRole
+	// must be set.
+	Token string `json:"token,omitempty" yaml:"token,omitempty" sensu:"redact"`
+
+	// AppRole authenticates with the AppRole auth method. Tokens are renewed
+	// by logging in again once they expire.
+	AppRole *VaultAppRole `json:"approle,omitempty" yaml:"approle,omitempty"`
+
+	// Version is the version of the KV secrets engine, v1 or v2. Defaults to
+	// v2.
+	Version string `json:"version,omitempty" yaml:"version,omitempty"`
+
+	// TLS contains the TLS configuration used when connecting to Vault. Only
+	// the client fields of TLSOptions are used.
+	TLS *corev2.TLSOptions `json:"tls,omitempty" yaml:"tls,omitempty"`
+
+	// Timeout is the timeout of the requests made to Vault, in seconds.
+	// Defaults to 60.
+	Timeout uint32 `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+
+	// CacheTTL is the maximum time secrets are cached for, in seconds.
+	// Secrets with a lease are never cached beyond their lease duration.
+	// Defaults to 300.
+	CacheTTL uint32 `json:"cache_ttl,omitempty" yaml:"cache_ttl,omitempty"`
+}
+
+// VaultAppRole are the credentials of the AppRole auth method.
+type VaultAppRole struct {
+	RoleID   string `json:"role_id" yaml:"role_id"`
+	SecretID string `json:"secret_id" yaml:"secret_id" sensu:"redact"`
+
+	// MountPath is the path the auth method is mounted at. Defaults to
+	// approle.
+	MountPath string `json:"mount_path,omitempty" yaml:"mount_path,omitempty"`
+}
+
+// FixtureVaultProvider returns a VaultProvider fixture for testing.
+func FixtureVaultProvider(name string) *VaultProvider {
+	return &VaultProvider{
+		Metadata: corev2.NewObjectMetaP(name, ""),
+		Client: &VaultClient{
+			Address: "https://vault.example.com:8200",
+			Token:   "VAULT_TOKEN",
+		},
+	}
+}
+
+// GetMetadata returns the metadata of the provider.
+func (v *VaultProvider) GetMetadata() *corev2.ObjectMeta {
+	return v.Metadata
+}
+
+// SetMetadata sets the metadata of the provider.
+func (v *VaultProvider) SetMetadata(meta *corev2.ObjectMeta) {
+	v.Metadata = meta
+}
+
+// StoreName returns the store name of the provider.
+func (v *VaultProvider) StoreName() string {
+	return ProvidersResource
+}
+
+// RBACName returns the RBAC name of the provider.
+func (v *VaultProvider) RBACName() string {
+	return ProvidersResource
+}
+
+// URIPath returns the API path of the provider.
+func (v *VaultProvider) URIPath() string {
+	if v.Metadata == nil {
+		return uriPath(vaultPath, "", "")
+	}
+	return uriPath(vaultPath, "", v.Metadata.Name)
+}
+
+// IsGlobalResource returns true, secrets providers are not namespaced.
+func (v *VaultProvider) IsGlobalResource() bool {
+	return true
+}
+
+// GetTypeMeta returns the type metadata of the provider.
+func (v *VaultProvider) GetTypeMeta() corev2.TypeMeta {
+	return corev2.TypeMeta{
+		APIVersion: APIVersion,
+		Type:       VaultProviderType,
+	}
+}
+
+// Validate checks that the provider is valid.
+func (v *VaultProvider) Validate() error {
+	if v == nil {
+		return errors.New("nil VaultProvider")
+	}
+	if err := corev3.ValidateGlobalMetadata(v.Metadata); err != nil {
+		return fmt.Errorf("invalid VaultProvider: %s", err)
+	}
+	if v.Client == nil {
+		return errors.New("client must be set")
+	}
+	return v.Client.Validate()
+}
+
+// Validate checks that the client is valid.
+func (c *VaultClient) Validate() error {
+	if c.Address == "" {
+		return errors.New("address must be set")
+	}
+	u, err := url.Parse(c.Address)
+	if err != nil {
+		return fmt.Errorf("invalid address: %s", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid address scheme %q: must be http or https", u.Scheme)
+	}
+	if (c.Token == "") == (c.AppRole == nil) {
+		return errors.New("exactly one of token and approle must be set")
+	}
+	if c.AppRole != nil && (c.AppRole.RoleID == "" || c.AppRole.SecretID == "") {
+		return errors.New("approle requires a role_id and a secret_id")
+	}
+	switch c.Version {
+	case "", VaultKVv1, VaultKVv2:
+	default:
+		return fmt.Errorf("invalid version %q: must be %s or %s", c.Version, VaultKVv1, VaultKVv2)
+	}
+	return nil
+}
+
+// GetVersion returns the version of the KV secrets engine, or its default.
+func (c *VaultClient) GetVersion() string {
+	if c.Version == "" {
+		return VaultKVv2
+	}
+	return c.Version
+}
+
+// GetTimeout returns the timeout of the requests, or its default.
+func (c *VaultClient) GetTimeout() uint32 {
+	if c.Timeout == 0 {
+		return DefaultVaultTimeout
+	}
+	return c.Timeout
+}
+
+// GetCacheTTL returns the maximum time secrets are cached for, or its
+// default.
+func (c *VaultClient) GetCacheTTL() uint32 {
+	if c.CacheTTL == 0 {
+		return DefaultVaultCacheTTL
+	}
+	return c.CacheTTL
+}
+
+// GetMountPath returns the mount path of the auth method, or its default.
+func (a *VaultAppRole) GetMountPath() string {
+	if a.MountPath == "" {
+		return DefaultVaultAppRoleMountPath
+	}
+	return a.MountPath
+}
+
+// VaultProviderFields returns a set of fields that represent the resource.
+func VaultProviderFields(r corev3.Resource) map[string]string {
+	resource := r.(*VaultProvider)
+	fields := map[string]string{
+		"vault_provider.name": resource.Metadata.Name,
+	}
+	for k, v := range resource.Metadata.Labels {
+		fields["vault_provider.labels."+k] = v
+	}
+	return fields
+}
+
+// Fields returns a set of fields that represent the resource.
+func (v *VaultProvider) Fields() map[string]string {
+	return VaultProviderFields(v)
+}
@@ -0,0 +1,126 @@
+package v1
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sensu/core/v3/types"
+)
+
+func TestVaultProviderValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*VaultProvider)
+		wantErr bool
+	}{
+		{
+			name:   "valid fixture",
+			mutate: func(*VaultProvider) {},
+		},
+		{
+			name:    "missing client",
+			mutate:  func(v *VaultProvider) { v.Client = nil },
+			wantErr: true,
+		},
+		{
+			name:    "missing address",
+			mutate:  func(v *VaultProvider) { v.Client.Address = "" },
+			wantErr: true,
+		},
+		{
+			name:    "unsupported address scheme",
+			mutate:  func(v *VaultProvider) { v.Client.Address = "tcp://vault.example.com:8200" },
+			wantErr: true,
+		},
+		{
+			name:    "missing credentials",
+			mutate:  func(v *VaultProvider) { v.Client.Token = "" },
+			wantErr: true,
+		},
+		{
+			name: "approle",
+			mutate: func(v *VaultProvider) {
+				v.Client.Token = ""
+				v.Client.AppRole = &VaultAppRole{RoleID: "role", SecretID: "secret"}
+			},
+		},
+		{
+			name: "token and approle",
+			mutate: func(v *VaultProvider) {
+				v.Client.AppRole = &VaultAppRole{RoleID: "role", SecretID: "secret"}
+			},
+			wantErr: true,
+		},
+		{
+			name: "approle without secret id",
+			mutate: func(v *VaultProvider) {
+				v.Client.Token = ""
+				v.Client.AppRole = &VaultAppRole{RoleID: "role"}
+			},
+			wantErr: true,
+		},
+		{
+			name:   "kv v1",
+			mutate: func(v *VaultProvider) { v.Client.Version = VaultKVv1 },
+		},
+		{
+			name:    "invalid version",
+			mutate:  func(v *VaultProvider) { v.Client.Version = "v3" },
+			wantErr: true,
+		},
+		{
+			name:    "namespaced",
+			mutate:  func(v *VaultProvider) { v.Metadata.Namespace = "default" },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := FixtureVaultProvider("vault")
+			tt.mutate(v)
+			if err := v.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("VaultProvider.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVaultClientDefaults(t *testing.T) {
+	c := FixtureVaultProvider("vault").Client
+	if got, want := c.GetVersion(), VaultKVv2; got != want {
+		t.Errorf("default version = %q, want %q", got, want)
+	}
+	if got, want := c.GetTimeout(), DefaultVaultTimeout; got != want {
+		t.Errorf("default timeout = %d, want %d", got, want)
+	}
+	if got, want := c.GetCacheTTL(), DefaultVaultCacheTTL; got != want {
+		t.Errorf("default cache ttl = %d, want %d", got, want)
+	}
+	if got, want := (&VaultAppRole{}).GetMountPath(), DefaultVaultAppRoleMountPath; got != want {
+		t.Errorf("default approle mount path = %q, want %q", got, want)
+	}
+}
+
+func TestVaultProviderURIPath(t *testing.T) {
+	if got, want := FixtureVaultProvider("vault").URIPath(), "/api/secrets/v1/providers/vault/vault"; got != want {
+		t.Errorf("VaultProvider.URIPath() = %q, want %q", got, want)
+	}
+}
+
+func TestVaultProviderResolve(t *testing.T) {
+	b, err := json.Marshal(types.WrapResource(FixtureVaultProvider("vault")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var w types.Wrapper
+	if err := json.Unmarshal(b, &w); err != nil {
+		t.Fatal(err)
+	}
+	provider, ok := w.Value.(*VaultProvider)
+	if !ok {
+		t.Fatalf("unexpected type %T", w.Value)
+	}
+	if err := provider.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
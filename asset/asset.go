@@ -48,6 +48,9 @@ type RuntimeAsset struct {
 	Path string
 	// SHA512 is the hash of the asset tarball.
 	SHA512 string
+	// SignatureWarning is the failure of the signature verification of an
+	// asset installed with the warn signature policy.
+	SignatureWarning string
 }
 
 // BinDir returns the full path to the asset's bin directory.
@@ -11,6 +11,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	corev2 "github.com/sensu/core/v2"
 	metricspkg "github.com/sensu/sensu-go/metrics"
+	"github.com/sirupsen/logrus"
 	bolt "go.etcd.io/bbolt"
 	"golang.org/x/time/rate"
 )
@@ -28,6 +29,10 @@ const (
 var (
 	assetBucketName = []byte("assets")
 
+	// assetSignatureBucketName is the bucket of the keys that verified the
+	// signatures of the installed assets.
+	assetSignatureBucketName = []byte("asset_signatures")
+
 	fetchDuration = prometheus.NewSummaryVec(
 		prometheus.SummaryOpts{
 			Name:       FetchDuration,
@@ -57,14 +62,16 @@ func init() {
 }
 
 // NewBoltDBGetter returns a new default asset Getter. If fetcher, verifier, or
-// expander are nil, the getter will use the built-in components.
+// expander are nil, the getter will use the built-in components. The
+// signatures of the assets are verified by signatures, if not nil.
 func NewBoltDBGetter(db *bolt.DB,
 	localStorage string,
 	trustedCAFile string,
 	fetcher Fetcher,
 	verifier Verifier,
 	expander Expander,
-	limiter *rate.Limiter) Getter {
+	limiter *rate.Limiter,
+	signatures *SignatureVerifier) Getter {
 
 	if fetcher == nil {
 		fetcher = &httpFetcher{
@@ -87,6 +94,7 @@ func NewBoltDBGetter(db *bolt.DB,
 		fetcher:      fetcher,
 		expander:     expander,
 		verifier:     verifier,
+		signatures:   signatures,
 	}
 }
 
@@ -101,6 +109,7 @@ type boltDBAssetManager struct {
 	fetcher      Fetcher
 	expander     Expander
 	verifier     Verifier
+	signatures   *SignatureVerifier
 }
 
 // Get opens a transaction to BoltDB, causing subsequent calls to
@@ -127,7 +136,7 @@ func (b *boltDBAssetManager) Get(ctx context.Context, asset *corev2.Asset) (*Run
 		}
 
 		value := bucket.Get(key)
-		if value != nil {
+		if value != nil && b.isTrusted(tx, key) {
 			// deserialize asset
 			if err := json.Unmarshal(value, &localAsset); err == nil {
 				return nil
@@ -157,7 +166,7 @@ func (b *boltDBAssetManager) Get(ctx context.Context, asset *corev2.Asset) (*Run
 		// was blocked on serialization. Re-attempt to get the key in case that is
 		// what happened.
 		value := bucket.Get(key)
-		if value != nil {
+		if value != nil && b.isTrusted(tx, key) {
 			// deserialize asset
 			if err := json.Unmarshal(value, &localAsset); err == nil {
 				return nil
@@ -186,6 +195,22 @@ func (b *boltDBAssetManager) Get(ctx context.Context, asset *corev2.Asset) (*Run
 			)
 		}
 
+		// verify the signature
+		var signatureWarning string
+		if b.signatures != nil {
+			signatureWarning, err = b.verifySignature(ctx, tx, tmpFile, asset)
+			if err != nil {
+				return err
+			}
+		}
+
+		// replace the previous installation of the asset, that can't be used
+		if value != nil {
+			if err := os.RemoveAll(filepath.Join(b.localStorage, asset.Sha512)); err != nil {
+				return err
+			}
+		}
+
 		// expand
 		assetPath, err := b.expandWithDuration(tmpFile, asset)
 		if err != nil {
@@ -193,7 +218,8 @@ func (b *boltDBAssetManager) Get(ctx context.Context, asset *corev2.Asset) (*Run
 		}
 
 		localAsset = &RuntimeAsset{
-			Path: assetPath,
+			Path:             assetPath,
+			SignatureWarning: signatureWarning,
 		}
 
 		assetJSON, err := json.Marshal(localAsset)
@@ -214,6 +240,50 @@ func (b *boltDBAssetManager) Get(ctx context.Context, asset *corev2.Asset) (*Run
 	return localAsset, nil
 }
 
+// isTrusted returns whether the installed asset of the given key can be
+// used. The assets installed without the verification of their signature
+// cannot be used if the signatures are enforced, they are installed again.
+func (b *boltDBAssetManager) isTrusted(tx *bolt.Tx, key []byte) bool {
+	if b.signatures == nil || b.signatures.Policy != SignaturePolicyEnforce {
+		return true
+	}
+	bucket := tx.Bucket(assetSignatureBucketName)
+	return bucket != nil && bucket.Get(key) != nil
+}
+
+// verifySignature verifies the signature of the downloaded asset, and
+// records the key that verified it. The asset is only installed with a
+// warning if the verification fails and the signatures are not enforced,
+// which is returned.
+func (b *boltDBAssetManager) verifySignature(ctx context.Context, tx *bolt.Tx, file *os.File, asset *corev2.Asset) (string, error) {
+	keyID, err := b.signatureKeyID(ctx, file, asset)
+	if err != nil {
+		err = fmt.Errorf("could not verify the signature of asset %q: %s", asset.Name, err)
+		if b.signatures.Policy == SignaturePolicyEnforce {
+			return "", err
+		}
+		logger.WithFields(logrus.Fields{
+			"asset":     asset.Name,
+			"namespace": asset.Namespace,
+		}).WithError(err).Warn("installing asset that failed signature verification")
+		return err.Error(), nil
+	}
+
+	bucket, err := tx.CreateBucketIfNotExists(assetSignatureBucketName)
+	if err != nil {
+		return "", err
+	}
+	return "", bucket.Put([]byte(asset.GetSha512()), []byte(keyID))
+}
+
+func (b *boltDBAssetManager) signatureKeyID(ctx context.Context, file *os.File, asset *corev2.Asset) (string, error) {
+	signature, err := b.signatures.signature(ctx, b.fetcher, asset)
+	if err != nil {
+		return "", err
+	}
+	return b.signatures.Verify(file, signature)
+}
+
 func (b *boltDBAssetManager) fetchWithDuration(ctx context.Context, asset *corev2.Asset) (file *os.File, err error) {
 	timer := prometheus.NewTimer(prometheus.ObserverFunc(func(v float64) {
 		status := metricspkg.StatusLabelSuccess
@@ -7,6 +7,8 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	v2 "github.com/sensu/core/v2"
@@ -287,3 +289,146 @@ func TestSuccessfulGetAsset(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestGetAssetSignature(t *testing.T) {
+	t.Parallel()
+
+	key := newMinisignKey(t, 1)
+	signature := string(key.sign(nil, minisignHashedAlgorithm, "file:asset.tar.gz"))
+	otherSignature := string(newMinisignKey(t, 2).sign(nil, minisignHashedAlgorithm, "file:asset.tar.gz"))
+
+	tests := []struct {
+		name      string
+		policy    SignaturePolicy
+		signature string
+		wantErr   bool
+		warning   bool
+	}{
+		{
+			name:      "enforced valid signature",
+			policy:    SignaturePolicyEnforce,
+			signature: signature,
+		},
+		{
+			name:      "enforced invalid signature",
+			policy:    SignaturePolicyEnforce,
+			signature: otherSignature,
+			wantErr:   true,
+		},
+		{
+			name:      "invalid signature with a warning",
+			policy:    SignaturePolicyWarn,
+			signature: otherSignature,
+			warning:   true,
+		},
+		{
+			name:      "valid signature without a warning",
+			policy:    SignaturePolicyWarn,
+			signature: signature,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, err := bolt.Open(filepath.Join(t.TempDir(), "assets.db"), 0600, &bolt.Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer db.Close()
+
+			manager := &boltDBAssetManager{
+				localStorage: t.TempDir(),
+				db:           db,
+				fetcher:      &mockFetcher{true},
+				verifier:     &mockVerifier{true},
+				expander:     &mockExpander{true},
+				signatures:   newTestSignatureVerifier(t, tt.policy, key.publicKeyFile()),
+			}
+			a := &v2.Asset{
+				ObjectMeta: v2.ObjectMeta{
+					Name:        "asset",
+					Namespace:   "default",
+					Annotations: map[string]string{SignatureAnnotation: tt.signature},
+				},
+				Sha512: "sha",
+				URL:    "path",
+			}
+
+			runtimeAsset, err := manager.Get(context.TODO(), a)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "could not verify the signature") {
+					t.Fatalf("expected signature error, got %v", err)
+				}
+				if runtimeAsset != nil {
+					t.Fatalf("expected nil runtime asset, got %v", runtimeAsset)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if runtimeAsset == nil {
+				t.Fatal("expected runtime asset, got nil")
+			}
+
+			// the warning is reported every time the asset is used
+			for i := 0; i < 2; i++ {
+				if got := runtimeAsset.SignatureWarning != ""; got != tt.warning {
+					t.Fatalf("expected signature warning %v, got %q", tt.warning, runtimeAsset.SignatureWarning)
+				}
+				if runtimeAsset, err = manager.Get(context.TODO(), a); err != nil {
+					t.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestGetUnverifiedAssetEnforced(t *testing.T) {
+	t.Parallel()
+
+	db, err := bolt.Open(filepath.Join(t.TempDir(), "assets.db"), 0600, &bolt.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	key := newMinisignKey(t, 1)
+	a := &v2.Asset{
+		ObjectMeta: v2.ObjectMeta{
+			Name:      "asset",
+			Namespace: "default",
+		},
+		Sha512: "sha",
+		URL:    "path",
+	}
+
+	// the asset is installed without the verification of its signature
+	manager := &boltDBAssetManager{
+		localStorage: t.TempDir(),
+		db:           db,
+		fetcher:      &mockFetcher{true},
+		verifier:     &mockVerifier{true},
+		expander:     &mockExpander{true},
+	}
+	if _, err := manager.Get(context.TODO(), a); err != nil {
+		t.Fatal(err)
+	}
+
+	// it can't be used once the signatures are enforced
+	manager.signatures = newTestSignatureVerifier(t, SignaturePolicyEnforce, key.publicKeyFile())
+	if _, err := manager.Get(context.TODO(), a); err == nil {
+		t.Fatal("expected signature error, got nil")
+	}
+
+	// until it is installed again with a valid signature
+	a.Annotations = map[string]string{
+		SignatureAnnotation: string(key.sign(nil, minisignHashedAlgorithm, "file:asset.tar.gz")),
+	}
+	if _, err := manager.Get(context.TODO(), a); err != nil {
+		t.Fatal(err)
+	}
+	a.Annotations = nil
+	if _, err := manager.Get(context.TODO(), a); err != nil {
+		t.Fatalf("expected the verified asset to be used, got %v", err)
+	}
+}
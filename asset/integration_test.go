@@ -86,6 +86,7 @@ func TestBoltDBManager(t *testing.T) {
 		nil,
 		nil,
 		nil,
+		nil,
 	)
 
 	if getter == nil {
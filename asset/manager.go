@@ -22,6 +22,9 @@ type Manager struct {
 	entity		*v2.Entity
 	wg		*sync.WaitGroup
 	trustedCAFile	string
+
+	// SignatureVerifier verifies the signatures of the assets, if not nil.
+	SignatureVerifier	*SignatureVerifier
 }
 
 // NewManager ...
@@ -57,7 +60,7 @@ func (m *Manager) StartAssetManager(ctx context.Context, limiter *rate.Limiter)
 		}
 	}()
 	boltDBGetter := NewBoltDBGetter(
-		db, m.cacheDir, m.trustedCAFile, nil, nil, nil, limiter)
+		db, m.cacheDir, m.trustedCAFile, nil, nil, nil, limiter, m.SignatureVerifier)
 
 	return NewFilteredManager(boltDBGetter, m.entity), nil
 }
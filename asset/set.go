@@ -96,3 +96,15 @@ func (r *RuntimeAssetSet) joinPaths(pathFunc func(*RuntimeAsset) string) string
 	}
 	return sb.String()
 }
+
+// SignatureWarnings returns the failures of the signature verifications of
+// the assets installed with the warn signature policy.
+func (r RuntimeAssetSet) SignatureWarnings() []string {
+	var warnings []string
+	for _, asset := range r {
+		if asset.SignatureWarning != "" {
+			warnings = append(warnings, asset.SignatureWarning)
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,354 @@
+package asset
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev2 "github.com/sensu/core/v2"
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	// SignatureAnnotation is the annotation of an asset that contains its
+	// detached signature.
+	SignatureAnnotation = "sensu.io/asset/signature"
+
+	// SignatureURLAnnotation is the annotation of an asset that contains the
+	// URL of its detached signature. The signature of an asset that has
+	// neither annotation is fetched from the URL of the asset, with the
+	// DefaultSignatureSuffix.
+	SignatureURLAnnotation = "sensu.io/asset/signature_url"
+
+	// DefaultSignatureSuffix is the suffix of the URL of an asset that is the
+	// URL of its signature, by default.
+	DefaultSignatureSuffix = ".sig"
+
+	// SignatureWarningAnnotation is the annotation of the checks of the
+	// events that the agent reports with assets installed despite the
+	// failure of their signature verification.
+	SignatureWarningAnnotation = "sensu.io/asset/signature_warning"
+
+	// SignatureVerifications is the name of the prometheus counter vec of the
+	// signature verifications of the assets.
+	SignatureVerifications = "sensu_go_asset_signature_verifications"
+
+	// maxSignatureSize is the maximum size of a detached signature.
+	maxSignatureSize = 64 * 1024
+)
+
+// SignaturePolicy is the policy of the assets that fail the verification of
+// their signature.
+type SignaturePolicy string
+
+const (
+	// SignaturePolicyWarn installs the assets that fail the verification of
+	// their signature, with a warning. The failure is kept as the
+	// SignatureWarning of the runtime asset, so that it is reported every time
+	// the asset is used.
+	SignaturePolicyWarn SignaturePolicy = "warn"
+
+	// SignaturePolicyEnforce refuses to install the assets that fail the
+	// verification of their signature.
+	SignaturePolicyEnforce SignaturePolicy = "enforce"
+)
+
+var signatureVerifications = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: SignatureVerifications,
+		Help: "The total number of signature verifications of the assets",
+	},
+	[]string{"result"},
+)
+
+func init() {
+	_ = prometheus.Register(signatureVerifications)
+}
+
+// ParseSignaturePolicy parses a signature policy, either warn or enforce.
+func ParseSignaturePolicy(s string) (SignaturePolicy, error) {
+	switch policy := SignaturePolicy(strings.ToLower(s)); policy {
+	case SignaturePolicyWarn, SignaturePolicyEnforce:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("invalid asset signature policy %q, must be %s or %s", s, SignaturePolicyWarn, SignaturePolicyEnforce)
+	}
+}
+
+// A publicKey verifies the signatures made with its private key.
+type publicKey interface {
+	// ID identifies the key.
+	ID() string
+
+	// Verify verifies the signature of the file. It returns
+	// errSignatureFormat if the signature is not of the format of the key.
+	Verify(file io.ReadSeeker, signature []byte) error
+}
+
+var errSignatureFormat = errors.New("the format of the signature doesn't match the format of the key")
+
+// SignatureVerifier verifies the detached signatures of the assets against
+// trusted public keys. The keys are either minisign public keys, which verify
+// minisign signatures, or ECDSA public keys in the PEM format, which verify
+// base64 encoded signatures of the SHA-256 digest of the assets, like the
+// signatures of cosign sign-blob.
+type SignatureVerifier struct {
+	// Policy is the policy of the assets that fail the verification.
+	Policy SignaturePolicy
+
+	keys []publicKey
+}
+
+// NewSignatureVerifier returns a verifier of the signatures made with the
+// private keys of the public keys found in the given files. It returns nil if
+// there are no files, in which case the signatures are not verified.
+func NewSignatureVerifier(keyFiles []string, policy SignaturePolicy) (*SignatureVerifier, error) {
+	if len(keyFiles) == 0 {
+		return nil, nil
+	}
+	policy, err := ParseSignaturePolicy(string(policy))
+	if err != nil {
+		return nil, err
+	}
+	verifier := &SignatureVerifier{Policy: policy}
+	for _, path := range keyFiles {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't read trusted asset key: %s", err)
+		}
+		keys, err := parsePublicKeys(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted asset key %s: %s", path, err)
+		}
+		verifier.keys = append(verifier.keys, keys...)
+	}
+	return verifier, nil
+}
+
+// Verify verifies the detached signature of the file against the trusted
+// keys, and returns the ID of the key that verified it.
+func (v *SignatureVerifier) Verify(file io.ReadSeeker, signature []byte) (string, error) {
+	var err error
+	for _, key := range v.keys {
+		verr := key.Verify(file, signature)
+		if _, serr := file.Seek(0, io.SeekStart); serr != nil {
+			return "", serr
+		}
+		if verr == nil {
+			signatureVerifications.WithLabelValues("verified").Inc()
+			return key.ID(), nil
+		}
+		// report the error of a key of the format of the signature, rather
+		// than a format mismatch
+		if err == nil || verr != errSignatureFormat {
+			err = verr
+		}
+	}
+	if err == nil {
+		err = errors.New("no trusted key")
+	}
+	signatureVerifications.WithLabelValues("failed").Inc()
+	return "", fmt.Errorf("signature verification failed: %s", err)
+}
+
+// signature returns the detached signature of the asset, either from its
+// annotation or fetched from its URL.
+func (v *SignatureVerifier) signature(ctx context.Context, fetcher Fetcher, asset *corev2.Asset) ([]byte, error) {
+	if signature, ok := asset.Annotations[SignatureAnnotation]; ok {
+		return []byte(signature), nil
+	}
+	url, ok := asset.Annotations[SignatureURLAnnotation]
+	if !ok {
+		url = asset.URL + DefaultSignatureSuffix
+	}
+	file, err := fetcher.Fetch(ctx, url, asset.Headers)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't fetch signature: %s", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+	signature, err := ioutil.ReadAll(io.LimitReader(file, maxSignatureSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read signature: %s", err)
+	}
+	if len(signature) > maxSignatureSize {
+		return nil, errors.New("signature too large")
+	}
+	return signature, nil
+}
+
+// parsePublicKeys parses the public keys in the PEM format, or a minisign
+// public key.
+func parsePublicKeys(data []byte) ([]publicKey, error) {
+	var keys []publicKey
+	for rest := data; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "PUBLIC KEY" {
+			continue
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		ecdsaKey, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("unsupported public key type %T", key)
+		}
+		fingerprint := sha256.Sum256(block.Bytes)
+		keys = append(keys, &ecdsaPublicKey{
+			id:  hex.EncodeToString(fingerprint[:8]),
+			key: ecdsaKey,
+		})
+	}
+	if len(keys) > 0 {
+		return keys, nil
+	}
+	key, err := parseMinisignPublicKey(data)
+	if err != nil {
+		return nil, err
+	}
+	return []publicKey{key}, nil
+}
+
+// ecdsaPublicKey verifies the signatures of the SHA-256 digest of the files.
+type ecdsaPublicKey struct {
+	id  string
+	key *ecdsa.PublicKey
+}
+
+func (k *ecdsaPublicKey) ID() string {
+	return k.id
+}
+
+func (k *ecdsaPublicKey) Verify(file io.ReadSeeker, signature []byte) error {
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		return errSignatureFormat
+	}
+	digest, err := digestOf(file, sha256.New())
+	if err != nil {
+		return err
+	}
+	if !ecdsa.VerifyASN1(k.key, digest, sig) {
+		return fmt.Errorf("invalid signature for key %s", k.id)
+	}
+	return nil
+}
+
+const (
+	minisignAlgorithm       = "Ed"
+	minisignHashedAlgorithm = "ED"
+)
+
+// minisignPublicKey verifies the minisign signatures of the files.
+type minisignPublicKey struct {
+	id  uint64
+	key ed25519.PublicKey
+}
+
+// parseMinisignPublicKey parses a minisign public key, with or without its
+// untrusted comment line.
+func parseMinisignPublicKey(data []byte) (*minisignPublicKey, error) {
+	lines := minisignLines(data)
+	if len(lines) > 0 && strings.HasPrefix(lines[0], "untrusted comment:") {
+		lines = lines[1:]
+	}
+	if len(lines) == 0 {
+		return nil, errors.New("no public key found")
+	}
+	raw, err := base64.StdEncoding.DecodeString(lines[0])
+	if err != nil || len(raw) != 2+8+ed25519.PublicKeySize || string(raw[:2]) != minisignAlgorithm {
+		return nil, errors.New("not a PEM or minisign public key")
+	}
+	return &minisignPublicKey{
+		id:  binary.LittleEndian.Uint64(raw[2:10]),
+		key: ed25519.PublicKey(raw[10:]),
+	}, nil
+}
+
+func (k *minisignPublicKey) ID() string {
+	return fmt.Sprintf("%016X", k.id)
+}
+
+// Verify verifies a minisign signature, made of an untrusted comment, the
+// signature of the file, a trusted comment and the signature of both.
+func (k *minisignPublicKey) Verify(file io.ReadSeeker, signature []byte) error {
+	lines := minisignLines(signature)
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "untrusted comment:") || !strings.HasPrefix(lines[2], "trusted comment: ") {
+		return errSignatureFormat
+	}
+	raw, err := base64.StdEncoding.DecodeString(lines[1])
+	if err != nil || len(raw) != 2+8+ed25519.SignatureSize {
+		return errSignatureFormat
+	}
+	if id := binary.LittleEndian.Uint64(raw[2:10]); id != k.id {
+		return fmt.Errorf("signed by the untrusted key %016X", id)
+	}
+	sig := raw[10:]
+
+	var message []byte
+	switch string(raw[:2]) {
+	case minisignAlgorithm:
+		if message, err = ioutil.ReadAll(file); err != nil {
+			return err
+		}
+	case minisignHashedAlgorithm:
+		h, _ := blake2b.New512(nil)
+		if message, err = digestOf(file, h); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported minisign signature algorithm %q", raw[:2])
+	}
+	if !ed25519.Verify(k.key, message, sig) {
+		return fmt.Errorf("invalid signature for key %s", k.ID())
+	}
+
+	// the trusted comment is signed along with the signature of the file
+	globalSig, err := base64.StdEncoding.DecodeString(lines[3])
+	if err != nil || len(globalSig) != ed25519.SignatureSize {
+		return errSignatureFormat
+	}
+	trustedComment := strings.TrimPrefix(lines[2], "trusted comment: ")
+	if !ed25519.Verify(k.key, append(append([]byte{}, sig...), trustedComment...), globalSig) {
+		return fmt.Errorf("invalid trusted comment signature for key %s", k.ID())
+	}
+	return nil
+}
+
+// minisignLines returns the non-empty lines of a minisign key or signature.
+func minisignLines(data []byte) []string {
+	var lines []string
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if line := strings.TrimSpace(string(line)); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func digestOf(file io.Reader, h hash.Hash) ([]byte, error) {
+	if _, err := io.Copy(h, file); err != nil {
+		return nil, fmt.Errorf("couldn't compute the digest of the asset: %s", err)
+	}
+	return h.Sum(nil), nil
+}
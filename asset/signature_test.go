@@ -0,0 +1,229 @@
+package asset
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// minisignKey is a minisign key pair for the tests.
+type minisignKey struct {
+	id      uint64
+	public  ed25519.PublicKey
+	private ed25519.PrivateKey
+}
+
+func newMinisignKey(t *testing.T, id uint64) *minisignKey {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &minisignKey{id: id, public: public, private: private}
+}
+
+func (k *minisignKey) publicKeyFile() []byte {
+	raw := append([]byte(minisignAlgorithm), make([]byte, 8)...)
+	binary.LittleEndian.PutUint64(raw[2:], k.id)
+	raw = append(raw, k.public...)
+	return []byte(fmt.Sprintf("untrusted comment: minisign public key %016X\n%s\n", k.id, base64.StdEncoding.EncodeToString(raw)))
+}
+
+func (k *minisignKey) sign(data []byte, algorithm, trustedComment string) []byte {
+	message := data
+	if algorithm == minisignHashedAlgorithm {
+		digest := blake2b.Sum512(data)
+		message = digest[:]
+	}
+	sig := ed25519.Sign(k.private, message)
+	raw := append([]byte(algorithm), make([]byte, 8)...)
+	binary.LittleEndian.PutUint64(raw[2:], k.id)
+	raw = append(raw, sig...)
+	globalSig := ed25519.Sign(k.private, append(append([]byte{}, sig...), trustedComment...))
+	return []byte(fmt.Sprintf("untrusted comment: signature from minisign secret key\n%s\ntrusted comment: %s\n%s\n",
+		base64.StdEncoding.EncodeToString(raw), trustedComment, base64.StdEncoding.EncodeToString(globalSig)))
+}
+
+func newECDSAKey(t *testing.T) (*ecdsa.PrivateKey, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func signECDSA(t *testing.T, key *ecdsa.PrivateKey, data []byte) []byte {
+	t.Helper()
+	digest := sha256.Sum256(data)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return []byte(base64.StdEncoding.EncodeToString(sig) + "\n")
+}
+
+func newTestSignatureVerifier(t *testing.T, policy SignaturePolicy, keys ...[]byte) *SignatureVerifier {
+	t.Helper()
+	dir := t.TempDir()
+	var files []string
+	for i, key := range keys {
+		path := filepath.Join(dir, fmt.Sprintf("key%d.pub", i))
+		if err := ioutil.WriteFile(path, key, 0600); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, path)
+	}
+	verifier, err := NewSignatureVerifier(files, policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return verifier
+}
+
+func TestSignatureVerifier(t *testing.T) {
+	asset := []byte("a tarball of the asset")
+	trusted := newMinisignKey(t, 1)
+	untrusted := newMinisignKey(t, 2)
+	ecdsaKey, ecdsaPublicKey := newECDSAKey(t)
+	otherECDSAKey, _ := newECDSAKey(t)
+
+	tamperedComment := bytes.Replace(trusted.sign(asset, minisignHashedAlgorithm, "file:asset.tar.gz"), []byte("file:asset"), []byte("file:other"), 1)
+
+	verifier := newTestSignatureVerifier(t, SignaturePolicyEnforce, trusted.publicKeyFile(), ecdsaPublicKey)
+
+	tests := []struct {
+		name      string
+		file      []byte
+		signature []byte
+		wantKey   string
+		wantErr   string
+	}{
+		{
+			name:      "minisign prehashed signature",
+			file:      asset,
+			signature: trusted.sign(asset, minisignHashedAlgorithm, "file:asset.tar.gz"),
+			wantKey:   "0000000000000001",
+		},
+		{
+			name:      "minisign legacy signature",
+			file:      asset,
+			signature: trusted.sign(asset, minisignAlgorithm, "file:asset.tar.gz"),
+			wantKey:   "0000000000000001",
+		},
+		{
+			name:      "minisign signature of another file",
+			file:      []byte("a tampered tarball"),
+			signature: trusted.sign(asset, minisignHashedAlgorithm, "file:asset.tar.gz"),
+			wantErr:   "invalid signature for key 0000000000000001",
+		},
+		{
+			name:      "minisign signature with a tampered trusted comment",
+			file:      asset,
+			signature: tamperedComment,
+			wantErr:   "invalid trusted comment signature",
+		},
+		{
+			name:      "minisign signature of an untrusted key",
+			file:      asset,
+			signature: untrusted.sign(asset, minisignHashedAlgorithm, "file:asset.tar.gz"),
+			wantErr:   "untrusted key 0000000000000002",
+		},
+		{
+			name:      "ecdsa signature",
+			file:      asset,
+			signature: signECDSA(t, ecdsaKey, asset),
+		},
+		{
+			name:      "ecdsa signature of an untrusted key",
+			file:      asset,
+			signature: signECDSA(t, otherECDSAKey, asset),
+			wantErr:   "invalid signature for key",
+		},
+		{
+			name:      "no signature",
+			file:      asset,
+			signature: []byte("not a signature"),
+			wantErr:   "signature verification failed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyID, err := verifier.Verify(bytes.NewReader(tt.file), tt.signature)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantKey != "" && keyID != tt.wantKey {
+				t.Errorf("expected key %s, got %s", tt.wantKey, keyID)
+			}
+		})
+	}
+}
+
+func TestNewSignatureVerifier(t *testing.T) {
+	verifier, err := NewSignatureVerifier(nil, SignaturePolicyEnforce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verifier != nil {
+		t.Error("expected no verifier without trusted keys")
+	}
+
+	path := filepath.Join(t.TempDir(), "key.pub")
+	if err := ioutil.WriteFile(path, []byte("not a key"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewSignatureVerifier([]string{path}, SignaturePolicyEnforce); err == nil {
+		t.Error("expected error with an invalid key")
+	}
+	if _, err := NewSignatureVerifier([]string{path}, "ignore"); err == nil {
+		t.Error("expected error with an invalid policy")
+	}
+
+	// the policy is stored as parsed, so that it is enforced
+	if err := ioutil.WriteFile(path, newMinisignKey(t, 1).publicKeyFile(), 0600); err != nil {
+		t.Fatal(err)
+	}
+	verifier, err = NewSignatureVerifier([]string{path}, "Enforce")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verifier.Policy != SignaturePolicyEnforce {
+		t.Errorf("expected policy %s, got %s", SignaturePolicyEnforce, verifier.Policy)
+	}
+}
+
+func TestParseSignaturePolicy(t *testing.T) {
+	for _, s := range []string{"warn", "Enforce"} {
+		if _, err := ParseSignaturePolicy(s); err != nil {
+			t.Errorf("expected no error for %q, got %v", s, err)
+		}
+	}
+	if _, err := ParseSignaturePolicy("ignore"); err == nil {
+		t.Error("expected error")
+	}
+}
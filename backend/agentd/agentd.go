@@ -35,8 +35,9 @@ import (
 
 var (
 	// upgrader is safe for concurrent use, and we don't need any particularly
-	// specialized configurations for different uses.
-	upgrader = &websocket.Upgrader{}
+	// specialized configurations for different uses. The permessage-deflate
+	// compression is only used with the agents that request it.
+	upgrader = &websocket.Upgrader{EnableCompression: true}
 
 	// used for registering prometheus session counter
 	sessionCounterOnce sync.Once
@@ -67,6 +68,9 @@ func init() {
 	if err := prometheus.Register(sessionErrorCounter); err != nil {
 		metrics.LogError(logger, sessionErrorCounterName, err)
 	}
+	if err := prometheus.Register(droppedBatchCounter); err != nil {
+		metrics.LogError(logger, droppedBatchCounterName, err)
+	}
 	if err := prometheus.Register(eventBytesSummary); err != nil {
 		metrics.LogError(logger, EventBytesSummaryName, err)
 	}
@@ -327,6 +331,8 @@ func (a *Agentd) webSocketHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	responseHeader.Set("Content-Type", contentType)
 	lager.WithField("header", fmt.Sprintf("Content-Type: %s", contentType)).Debug("setting header")
+	capabilities := transport.NegotiateCapabilities(r.Header, responseHeader)
+	lager.WithField("header", fmt.Sprintf("%s: %s", transport.HeaderKeyCapabilities, capabilities)).Debug("setting header")
 
 	// Validate the agent namespace
 	namespace := r.Header.Get(transport.HeaderKeyNamespace)
@@ -362,7 +368,7 @@ func (a *Agentd) webSocketHandler(w http.ResponseWriter, r *http.Request) {
 		ContentType:   contentType,
 		WriteTimeout:  a.writeTimeout,
 		Bus:           a.bus,
-		Conn:          transport.NewTransport(conn),
+		Conn:          transport.NewTransportWithCapabilities(conn, capabilities),
 		Storev2:       a.store,
 		Marshal:       marshal,
 		Unmarshal:     unmarshal,
@@ -41,6 +41,9 @@ const (
 	// Name of the websocket errors metric
 	websocketErrorCounterName = "sensu_go_websocket_errors"
 
+	// Name of the dropped batch messages counter metric
+	droppedBatchCounterName = "sensu_go_agent_dropped_batches"
+
 	// EventBytesSummaryName is the name of the prometheus summary vec used to
 	// track event sizes (in bytes).
 	EventBytesSummaryName = "sensu_go_agentd_event_bytes"
@@ -74,6 +77,12 @@ var (
 		},
 		[]string{"error"},
 	)
+	droppedBatchCounter = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: droppedBatchCounterName,
+			Help: "The total number of batch messages of the agents dropped because they couldn't be decoded",
+		},
+	)
 )
 
 // A Session is a server-side connection between a Sensu backend server and
@@ -221,20 +230,44 @@ func (s *Session) receiver() {
 			}
 			return
 		}
-		ctx, cancel := context.WithTimeout(s.ctx, time.Duration(s.cfg.WriteTimeout)*time.Second)
-		if err := s.handler.Handle(ctx, msg.Type, msg.Payload); err != nil {
-			logger.WithError(err).WithFields(logrus.Fields{
-				"type":    msg.Type,
-				"payload": string(msg.Payload)}).Error("error handling message")
-			if _, ok := err.(*store.ErrInternal); ok {
-				// Fatal error - boot the agent out of the session
-				sessionErrorCounter.WithLabelValues("store.ErrInternal").Inc()
-				logger.Error("internal error - stopping session")
-				go s.Stop()
+		msgs := []*transport.Message{msg}
+		if msg.Type == transport.MessageTypeBatch {
+			// Unpack the messages batched by the agent
+			if msgs, err = transport.DecodeBatch(msg.Payload); err != nil {
+				droppedBatchCounter.Inc()
+				logger.WithError(err).WithFields(logrus.Fields{
+					"addr":  s.cfg.AgentAddr,
+					"agent": s.cfg.AgentName,
+				}).Error("error decoding batch message, dropping its messages")
+				continue
 			}
 		}
-		cancel()
+		for _, msg := range msgs {
+			if !s.handle(msg) {
+				break
+			}
+		}
+	}
+}
+
+// handle handles a message received from the agent, and returns false if the
+// session is stopping.
+func (s *Session) handle(msg *transport.Message) bool {
+	ctx, cancel := context.WithTimeout(s.ctx, time.Duration(s.cfg.WriteTimeout)*time.Second)
+	defer cancel()
+	if err := s.handler.Handle(ctx, msg.Type, msg.Payload); err != nil {
+		logger.WithError(err).WithFields(logrus.Fields{
+			"type":    msg.Type,
+			"payload": string(msg.Payload)}).Error("error handling message")
+		if _, ok := err.(*store.ErrInternal); ok {
+			// Fatal error - boot the agent out of the session
+			sessionErrorCounter.WithLabelValues("store.ErrInternal").Inc()
+			logger.Error("internal error - stopping session")
+			go s.Stop()
+			return false
+		}
 	}
+	return true
 }
 
 func (s *Session) sender() {
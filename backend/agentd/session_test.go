@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/gogo/protobuf/proto"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	corev2 "github.com/sensu/core/v2"
 	corev3 "github.com/sensu/core/v3"
 	"github.com/sensu/sensu-go/agent"
@@ -769,3 +770,41 @@ func TestSession_receiver(t *testing.T) {
 		})
 	}
 }
+
+func TestSession_receiverBatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	conn := new(mocktransport.MockTransport)
+	batch := transport.NewBatchMessage([]*transport.Message{
+		transport.NewMessage("test", []byte("1")),
+		transport.NewMessage("test", []byte("2")),
+		transport.NewMessage("test", []byte("3")),
+	})
+	conn.On("Receive").Once().Return(batch, nil)
+	conn.On("Receive").Once().Return(&transport.Message{Type: transport.MessageTypeBatch, Payload: []byte{0xff}}, nil)
+	conn.On("Receive").Once().Run(func(args mock.Arguments) {
+		cancel()
+	}).Return(&transport.Message{}, nil)
+
+	s := &Session{
+		cfg: SessionConfig{
+			WriteTimeout: 5,
+		},
+		conn:   conn,
+		ctx:    ctx,
+		cancel: cancel,
+		wg:     &sync.WaitGroup{},
+	}
+	s.wg.Add(1)
+	s.handler = handler.NewMessageHandler()
+	var received []string
+	s.handler.AddHandler("test", func(ctx context.Context, payload []byte) error {
+		received = append(received, string(payload))
+		return nil
+	})
+	dropped := testutil.ToFloat64(droppedBatchCounter)
+	go s.receiver()
+
+	s.wg.Wait()
+	assert.Equal(t, []string{"1", "2", "3"}, received)
+	assert.Equal(t, dropped+1, testutil.ToFloat64(droppedBatchCounter))
+}
@@ -32,6 +32,12 @@ func (a *AuthenticationClient) CreateAccessToken(ctx context.Context, username,
 		return nil, corev2.ErrUnauthorized
 	}
 
+	return issueTokens(ctx, claims)
+}
+
+// issueTokens issues a new access token and refresh token for the user
+// identified by the given claims.
+func issueTokens(ctx context.Context, claims *corev2.Claims) (*corev2.Tokens, error) {
 	// Add the 'system:users' group to this user
 	claims.Groups = append(claims.Groups, "system:users")
 
@@ -60,7 +66,6 @@ func (a *AuthenticationClient) CreateAccessToken(ctx context.Context, username,
 	}
 
 	return result, nil
-
 }
 
 // TestCreds detects if the username and password are valid.
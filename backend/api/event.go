@@ -2,7 +2,9 @@ package api
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	corev2 "github.com/sensu/core/v2"
 	"github.com/sensu/sensu-go/backend/authorization"
@@ -69,6 +71,24 @@ func (e *EventClient) FetchEvent(ctx context.Context, entity, check string) (*co
 	return e.store.GetEventByEntityCheck(ctx, entity, check)
 }
 
+// FetchEventHistory gets the status changes of an event that occurred
+// between start and end, if authorized.
+func (e *EventClient) FetchEventHistory(ctx context.Context, entity, check string, start, end time.Time) ([]*store.EventHistoryEntry, error) {
+	attrs := eventGetAttributes(ctx, fmt.Sprintf("%s:%s", entity, check))
+	if err := authorize(ctx, e.auth, attrs); err != nil {
+		return nil, err
+	}
+	history, ok := e.store.(store.EventHistoryStore)
+	if !ok {
+		return nil, errors.New("event history is not supported by the event store")
+	}
+	entries, err := history.GetEventHistory(ctx, entity, check, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get event history: %s", err)
+	}
+	return entries, nil
+}
+
 // DeleteEvent deletes an event, if authorized.
 func (e *EventClient) DeleteEvent(ctx context.Context, entity, check string) error {
 	attrs := eventDeleteAttributes(ctx, entity, check)
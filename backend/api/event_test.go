@@ -4,6 +4,7 @@ import (
 	"context"
 	"reflect"
 	"testing"
+	"time"
 
 	corev2 "github.com/sensu/core/v2"
 	"github.com/sensu/sensu-go/backend/authorization"
@@ -290,6 +291,91 @@ func TestGetEvent(t *testing.T) {
 	}
 }
 
+func TestGetEventHistory(t *testing.T) {
+	history := []*store.EventHistoryEntry{
+		{Entity: "default", Check: "default", Status: 2, Timestamp: 150},
+	}
+	start, end := time.Unix(100, 0), time.Unix(200, 0)
+	getAttrs := func(verb string) authorization.Authorizer {
+		return &mockAuth{
+			attrs: map[authorization.AttributesKey]bool{
+				authorization.AttributesKey{
+					APIGroup:     "core",
+					APIVersion:   "v2",
+					Namespace:    "default",
+					Resource:     "events",
+					ResourceName: "default:default",
+					UserName:     "legit",
+					Verb:         verb,
+				}: true,
+			},
+		}
+	}
+	tests := []struct {
+		Name       string
+		Ctx        func() context.Context
+		EventStore func() store.EventStore
+		Auth       func() authorization.Authorizer
+		Exp        []*store.EventHistoryEntry
+		ExpErr     bool
+	}{
+		{
+			Name: "no auth",
+			Ctx:  defaultContext,
+			EventStore: func() store.EventStore {
+				return new(mockstore.MockStore)
+			},
+			Auth: func() authorization.Authorizer {
+				return &rbac.Authorizer{}
+			},
+			ExpErr: true,
+		},
+		{
+			Name: "right user, wrong perms",
+			Ctx: func() context.Context {
+				return contextWithUser(defaultContext(), "legit", nil)
+			},
+			EventStore: func() store.EventStore {
+				return new(mockstore.MockStore)
+			},
+			Auth: func() authorization.Authorizer {
+				return getAttrs("create")
+			},
+			ExpErr: true,
+		},
+		{
+			Name: "good auth",
+			Ctx: func() context.Context {
+				return contextWithUser(defaultContext(), "legit", nil)
+			},
+			EventStore: func() store.EventStore {
+				store := new(mockstore.MockStore)
+				store.On("GetEventHistory", mock.Anything, "default", "default", start, end).Return(history, nil)
+				return store
+			},
+			Auth: func() authorization.Authorizer {
+				return getAttrs("get")
+			},
+			Exp: history,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			client := NewEventClient(test.EventStore(), test.Auth(), new(mockbus.MockBus))
+			entries, err := client.FetchEventHistory(test.Ctx(), "default", "default", start, end)
+			if err != nil && !test.ExpErr {
+				t.Fatal(err)
+			}
+			if err == nil && test.ExpErr {
+				t.Fatal("expected non-nil error")
+			}
+			if got, want := entries, test.Exp; !reflect.DeepEqual(got, want) {
+				t.Fatalf("bad history: got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
 func TestUpdateEvent(t *testing.T) {
 	tests := []struct {
 		Name       string
@@ -0,0 +1,232 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/authentication"
+	"github.com/sensu/sensu-go/backend/authentication/jwt"
+	"github.com/sensu/sensu-go/backend/authentication/providers/oidc"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/sensu/sensu-go/backend/store/memory"
+	"github.com/sirupsen/logrus"
+)
+
+// oidcLoginTimeout is the time given to users to complete a login, and to
+// clients to redeem the login code they are given.
+var oidcLoginTimeout = 5 * time.Minute
+
+var (
+	// ErrOIDCProviderNotFound is returned when no matching OIDC provider is
+	// configured.
+	ErrOIDCProviderNotFound = errors.New("oidc provider not found")
+
+	// ErrInvalidClientRedirect is returned when a login is started with a
+	// client redirect that isn't a loopback http URL.
+	ErrInvalidClientRedirect = errors.New("the redirect must be a loopback http URL")
+)
+
+// oidcLogin is a login in progress, identified by its state.
+type oidcLogin struct {
+	Provider       string `json:"provider"`
+	Nonce          string `json:"nonce"`
+	RedirectURI    string `json:"redirect_uri,omitempty"`
+	ClientRedirect string `json:"client_redirect,omitempty"`
+}
+
+// OIDCLogin is the outcome of a successful OIDC login.
+type OIDCLogin struct {
+	// Tokens are the tokens issued to the user. They are only set when the
+	// login was not started by a client expecting a redirect.
+	Tokens *corev2.Tokens
+
+	// RedirectURL is the URL the user must be redirected to. It contains the
+	// single-use code the client can exchange for the tokens of the user.
+	RedirectURL string
+}
+
+// OIDCClient is an API client for logging in with the authorization code flow
+// of the OIDC authentication providers. Logins in progress are kept in the
+// OIDC state store, so each step of a login can be handled by any backend.
+type OIDCClient struct {
+	auth   *authentication.Authenticator
+	states store.OIDCStateStore
+}
+
+// NewOIDCClient creates a new OIDCClient, given an authenticator and the
+// store of the logins in progress. The logins are kept in memory if the store
+// is nil.
+func NewOIDCClient(auth *authentication.Authenticator, states store.OIDCStateStore) *OIDCClient {
+	if states == nil {
+		states = memory.NewOIDCStateStore()
+	}
+	return &OIDCClient{
+		auth:   auth,
+		states: states,
+	}
+}
+
+// Authorize starts a login with the named OIDC provider, or with the only one
+// configured if name is empty, and returns the URL of the issuer where the
+// user must authenticate. The redirect URI is the URL of the callback
+// endpoint, unless the provider configures one. The optional client redirect
+// is where the user is sent once logged in, and must be a loopback URL, such
+// as the one sensuctl listens on.
+func (c *OIDCClient) Authorize(ctx context.Context, name, redirectURI, clientRedirect string) (string, error) {
+	provider, err := c.provider(name)
+	if err != nil {
+		return "", err
+	}
+	if clientRedirect != "" {
+		if err := validateClientRedirect(clientRedirect); err != nil {
+			return "", err
+		}
+	}
+
+	state, err := jwt.GenJTI()
+	if err != nil {
+		return "", err
+	}
+	nonce, err := jwt.GenJTI()
+	if err != nil {
+		return "", err
+	}
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, redirectURI)
+	if err != nil {
+		return "", err
+	}
+
+	login, err := json.Marshal(&oidcLogin{
+		Provider:       provider.Name(),
+		Nonce:          nonce,
+		RedirectURI:    redirectURI,
+		ClientRedirect: clientRedirect,
+	})
+	if err != nil {
+		return "", err
+	}
+	if err := c.states.PutOIDCState(ctx, store.OIDCLogin, state, login, time.Now().Add(oidcLoginTimeout)); err != nil {
+		return "", err
+	}
+	return authURL, nil
+}
+
+// Callback completes the login identified by state, with the authorization
+// code given by the issuer.
+func (c *OIDCClient) Callback(ctx context.Context, state, code string) (*OIDCLogin, error) {
+	var login oidcLogin
+	if err := c.take(ctx, store.OIDCLogin, state, &login); err != nil {
+		return nil, err
+	}
+
+	provider, err := c.provider(login.Provider)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := provider.Exchange(ctx, code, login.Nonce, login.RedirectURI)
+	if err != nil {
+		logger.WithError(err).WithField("provider", login.Provider).Error("oidc login failed")
+		return nil, corev2.ErrUnauthorized
+	}
+	logger.WithFields(logrus.Fields{
+		"subject":         claims.Subject,
+		"groups":          claims.Groups,
+		"provider_id":     claims.Provider.ProviderID,
+		"provider_type":   claims.Provider.ProviderType,
+		"provider_userid": claims.Provider.UserID,
+	}).Info("login successful")
+
+	tokens, err := issueTokens(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+	if login.ClientRedirect == "" {
+		return &OIDCLogin{Tokens: tokens}, nil
+	}
+
+	loginCode, err := jwt.GenJTI()
+	if err != nil {
+		return nil, err
+	}
+	value, err := json.Marshal(tokens)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.states.PutOIDCState(ctx, store.OIDCCode, loginCode, value, time.Now().Add(oidcLoginTimeout)); err != nil {
+		return nil, err
+	}
+
+	redirect, _ := url.Parse(login.ClientRedirect)
+	query := redirect.Query()
+	query.Set("code", loginCode)
+	redirect.RawQuery = query.Encode()
+	return &OIDCLogin{RedirectURL: redirect.String()}, nil
+}
+
+// Token redeems the single-use code given to a client at the end of a login
+// for the tokens of the user.
+func (c *OIDCClient) Token(ctx context.Context, code string) (*corev2.Tokens, error) {
+	var tokens corev2.Tokens
+	if err := c.take(ctx, store.OIDCCode, code, &tokens); err != nil {
+		return nil, err
+	}
+	return &tokens, nil
+}
+
+// take takes the single-use state id of the given kind from the store, and
+// decodes it in v. Unknown or expired states are unauthorized.
+func (c *OIDCClient) take(ctx context.Context, kind, id string, v interface{}) error {
+	value, err := c.states.TakeOIDCState(ctx, kind, id)
+	if err != nil {
+		if _, ok := err.(*store.ErrNotFound); ok {
+			return corev2.ErrUnauthorized
+		}
+		return err
+	}
+	return json.Unmarshal(value, v)
+}
+
+// provider returns the named OIDC provider, or the only one configured if
+// name is empty.
+func (c *OIDCClient) provider(name string) (*oidc.Provider, error) {
+	var found *oidc.Provider
+	for _, p := range c.auth.Providers() {
+		provider, ok := p.(*oidc.Provider)
+		if !ok {
+			continue
+		}
+		if provider.Name() == name {
+			return provider, nil
+		}
+		if name == "" {
+			if found != nil {
+				return nil, errors.New("several oidc providers are configured, one must be specified")
+			}
+			found = provider
+		}
+	}
+	if found == nil {
+		return nil, ErrOIDCProviderNotFound
+	}
+	return found, nil
+}
+
+// validateClientRedirect ensures that users are only redirected, along with
+// their login code, to a local client.
+func validateClientRedirect(clientRedirect string) error {
+	u, err := url.Parse(clientRedirect)
+	if err != nil || u.Scheme != "http" {
+		return fmt.Errorf("%w: %q", ErrInvalidClientRedirect, clientRedirect)
+	}
+	host := u.Hostname()
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return fmt.Errorf("%w: %q", ErrInvalidClientRedirect, clientRedirect)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	corev2 "github.com/sensu/core/v2"
+	authv2 "github.com/sensu/sensu-go/api/authentication/v2"
+	"github.com/sensu/sensu-go/backend/authentication/jwt"
+	"github.com/sensu/sensu-go/backend/authentication/providers/oidc"
+	"github.com/sensu/sensu-go/testing/mockoidc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testOIDCCallback = "http://127.0.0.1:8080/auth/oidc/callback"
+
+func newTestOIDCClient(t *testing.T) *OIDCClient {
+	t.Helper()
+	issuer, err := mockoidc.NewIssuer("sensu", "secret")
+	require.NoError(t, err)
+	t.Cleanup(issuer.Close)
+	issuer.SetClaims(map[string]interface{}{
+		"email":  "jane@example.com",
+		"groups": []string{"ops"},
+	})
+
+	config := authv2.FixtureOIDC("mock")
+	config.Server = issuer.URL
+	config.ClientID = "sensu"
+	config.ClientSecret = "secret"
+	config.UsernameClaim = "email"
+
+	auth := defaultAuth(defaultStore())
+	auth.AddProvider(oidc.New(config))
+	return NewOIDCClient(auth, nil)
+}
+
+// followOIDCLogin sends the user to the issuer and returns the query of the
+// callback request.
+func followOIDCLogin(t *testing.T, authURL string) url.Values {
+	t.Helper()
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := client.Get(authURL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	location, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	return location.Query()
+}
+
+func TestOIDCLogin(t *testing.T) {
+	client := newTestOIDCClient(t)
+	ctx := context.WithValue(context.Background(), jwt.IssuerURLKey, "http://127.0.0.1:8080")
+
+	authURL, err := client.Authorize(ctx, "", testOIDCCallback, "")
+	require.NoError(t, err)
+	callback := followOIDCLogin(t, authURL)
+
+	login, err := client.Callback(ctx, callback.Get("state"), callback.Get("code"))
+	require.NoError(t, err)
+	require.NotNil(t, login.Tokens)
+	assert.Empty(t, login.RedirectURL)
+
+	token, err := jwt.ValidateToken(login.Tokens.Access)
+	require.NoError(t, err)
+	claims := token.Claims.(*corev2.Claims)
+	assert.Equal(t, "jane@example.com", claims.Subject)
+	assert.Equal(t, []string{"ops", "system:users"}, claims.Groups)
+	assert.Equal(t, "http://127.0.0.1:8080", claims.Issuer)
+	assert.Equal(t, oidc.Type, claims.Provider.ProviderType)
+
+	// The state can only be used once
+	_, err = client.Callback(ctx, callback.Get("state"), callback.Get("code"))
+	assert.Equal(t, corev2.ErrUnauthorized, err)
+}
+
+func TestOIDCLoginClientRedirect(t *testing.T) {
+	client := newTestOIDCClient(t)
+	ctx := context.Background()
+
+	authURL, err := client.Authorize(ctx, "mock", testOIDCCallback, "http://127.0.0.1:9999/callback")
+	require.NoError(t, err)
+	callback := followOIDCLogin(t, authURL)
+
+	login, err := client.Callback(ctx, callback.Get("state"), callback.Get("code"))
+	require.NoError(t, err)
+	assert.Nil(t, login.Tokens)
+	redirect, err := url.Parse(login.RedirectURL)
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1:9999", redirect.Host)
+	code := redirect.Query().Get("code")
+	require.NotEmpty(t, code)
+
+	tokens, err := client.Token(ctx, code)
+	require.NoError(t, err)
+	assert.NotEmpty(t, tokens.Access)
+	assert.NotEmpty(t, tokens.Refresh)
+
+	// The code can only be redeemed once
+	_, err = client.Token(ctx, code)
+	assert.Equal(t, corev2.ErrUnauthorized, err)
+}
+
+func TestOIDCAuthorizeErrors(t *testing.T) {
+	client := newTestOIDCClient(t)
+	ctx := context.Background()
+
+	_, err := client.Authorize(ctx, "missing", testOIDCCallback, "")
+	assert.True(t, errors.Is(err, ErrOIDCProviderNotFound), "unexpected error: %v", err)
+
+	for _, redirect := range []string{
+		"https://127.0.0.1:9999/callback",
+		"http://evil.example.com/callback",
+		"http://10.0.0.1/callback",
+	} {
+		_, err = client.Authorize(ctx, "mock", testOIDCCallback, redirect)
+		assert.True(t, errors.Is(err, ErrInvalidClientRedirect), "unexpected error for %s: %v", redirect, err)
+	}
+}
+
+func TestOIDCRefreshAccessToken(t *testing.T) {
+	client := newTestOIDCClient(t)
+	ctx := context.Background()
+
+	authURL, err := client.Authorize(ctx, "", testOIDCCallback, "")
+	require.NoError(t, err)
+	callback := followOIDCLogin(t, authURL)
+	login, err := client.Callback(ctx, callback.Get("state"), callback.Get("code"))
+	require.NoError(t, err)
+
+	token, err := jwt.ValidateToken(login.Tokens.Access)
+	require.NoError(t, err)
+	ctx = contextWithClaims(token.Claims.(*corev2.Claims))
+	ctx = context.WithValue(ctx, corev2.RefreshTokenString, login.Tokens.Refresh)
+
+	tokens, err := NewAuthenticationClient(client.auth).RefreshAccessToken(ctx)
+	require.NoError(t, err)
+	token, err = jwt.ValidateToken(tokens.Access)
+	require.NoError(t, err)
+	assert.Equal(t, "jane@example.com", token.Claims.(*corev2.Claims).Subject)
+}
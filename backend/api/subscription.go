@@ -0,0 +1,220 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+	"github.com/sensu/sensu-go/backend/authorization"
+	"github.com/sensu/sensu-go/backend/messaging"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+// subscriptionBufferSize is the number of updates buffered for each
+// subscription. Updates are dropped when a subscriber falls further behind, so
+// that slow subscribers never hold up the message bus.
+const subscriptionBufferSize = 100
+
+// EntityUpdate is a change made to an entity, as delivered by
+// SubscriptionClient.
+type EntityUpdate struct {
+	// Action is the kind of change made to the entity.
+	Action storev2.WatchActionType
+
+	// Name is the name of the entity.
+	Name string
+
+	// Entity is the entity, or nil if it was deleted.
+	Entity *corev2.Entity
+}
+
+// SubscriptionClient is an API client for live updates of events and
+// entities. Updates are only delivered if the user is authorized to get the
+// resource they concern.
+type SubscriptionClient struct {
+	store storev2.Interface
+	bus   messaging.MessageBus
+	auth  authorization.Authorizer
+}
+
+// NewSubscriptionClient creates a new SubscriptionClient, given a store, a
+// message bus and an authorizer.
+func NewSubscriptionClient(store storev2.Interface, bus messaging.MessageBus, auth authorization.Authorizer) *SubscriptionClient {
+	return &SubscriptionClient{
+		store: store,
+		bus:   bus,
+		auth:  auth,
+	}
+}
+
+// SubscribeEvents returns a channel receiving the events of the namespace
+// stored in ctx each time they are updated, optionally limited to the given
+// entity and check. The channel is closed once ctx is cancelled.
+func (c *SubscriptionClient) SubscribeEvents(ctx context.Context, entity, check string) (<-chan *corev2.Event, error) {
+	return c.subscribeEvents(ctx, func(event *corev2.Event) bool {
+		if entity != "" && event.Entity.Name != entity {
+			return false
+		}
+		if check != "" && event.Check.Name != check {
+			return false
+		}
+		return true
+	})
+}
+
+// SubscribeKeepalives returns a channel receiving the keepalive events of the
+// namespace stored in ctx each time their status changes. The channel is
+// closed once ctx is cancelled.
+func (c *SubscriptionClient) SubscribeKeepalives(ctx context.Context) (<-chan *corev2.Event, error) {
+	return c.subscribeEvents(ctx, func(event *corev2.Event) bool {
+		return event.Check.Name == corev2.KeepaliveCheckName && statusChanged(event)
+	})
+}
+
+func (c *SubscriptionClient) subscribeEvents(ctx context.Context, match func(*corev2.Event) bool) (<-chan *corev2.Event, error) {
+	namespace := corev2.ContextNamespace(ctx)
+	if namespace == "" {
+		return nil, errors.New("must specify a namespace")
+	}
+	// Fail early when the user can't get any event of the namespace at all
+	if err := authorize(ctx, c.auth, eventListAttributes(ctx)); err != nil {
+		return nil, err
+	}
+	in := make(messaging.ChanSubscriber, subscriptionBufferSize)
+	subscription, err := c.bus.Subscribe(messaging.TopicEvent, "graphql-"+uuid.New().String(), in)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't subscribe to events: %s", err)
+	}
+	// The message bus blocks until the subscriber receives the events, so
+	// they are received without delay and handed over to a separate goroutine
+	// that authorizes them. Events are dropped when that goroutine falls
+	// behind.
+	pending := make(chan *corev2.Event, subscriptionBufferSize)
+	go func() {
+		defer close(pending)
+		defer func() {
+			if err := subscription.Cancel(); err != nil {
+				logger.WithError(err).Error("couldn't cancel event subscription")
+			}
+		}()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg := <-in:
+				event, ok := msg.(*corev2.Event)
+				if !ok || !event.HasCheck() || event.Entity == nil {
+					continue
+				}
+				if event.Entity.Namespace != namespace || !match(event) {
+					continue
+				}
+				select {
+				case pending <- event:
+				default:
+					logger.WithField("event", eventName(event)).Warn("subscriber is too slow, dropping event")
+				}
+			}
+		}
+	}()
+	out := make(chan *corev2.Event, subscriptionBufferSize)
+	go func() {
+		defer close(out)
+		for event := range pending {
+			name := eventName(event)
+			if err := authorize(ctx, c.auth, eventGetAttributes(ctx, name)); err != nil {
+				continue
+			}
+			select {
+			case out <- event:
+			default:
+				logger.WithField("event", name).Warn("subscriber is too slow, dropping event")
+			}
+		}
+	}()
+	return out, nil
+}
+
+func eventName(event *corev2.Event) string {
+	return fmt.Sprintf("%s:%s", event.Entity.Name, event.Check.Name)
+}
+
+// SubscribeEntities returns a channel receiving the changes made to the
+// entities of the namespace stored in ctx. The channel is closed once ctx is
+// cancelled. The entities are watched for each subscription, so a slow
+// subscriber only delays its own updates.
+func (c *SubscriptionClient) SubscribeEntities(ctx context.Context) (<-chan EntityUpdate, error) {
+	namespace := corev2.ContextNamespace(ctx)
+	if namespace == "" {
+		return nil, errors.New("must specify a namespace")
+	}
+	// Fail early when the user can't get any entity of the namespace at all
+	if err := authorize(ctx, c.auth, entityAuthAttributes(ctx, "list", "")); err != nil {
+		return nil, err
+	}
+	watch := c.store.GetEntityConfigStore().Watch(ctx, namespace, "")
+	entityStore := c.store.GetEntityStore()
+	out := make(chan EntityUpdate, subscriptionBufferSize)
+	go func() {
+		defer close(out)
+		for {
+			var events []storev2.WatchEvent
+			var ok bool
+			select {
+			case <-ctx.Done():
+				return
+			case events, ok = <-watch:
+				if !ok {
+					return
+				}
+			}
+			for _, e := range events {
+				if e.Err != nil {
+					logger.WithError(e.Err).Error("error watching entities")
+					continue
+				}
+				switch e.Type {
+				case storev2.WatchCreate, storev2.WatchUpdate, storev2.WatchDelete:
+				default:
+					continue
+				}
+				update := EntityUpdate{Action: e.Type, Name: e.Key.Name}
+				if update.Name == "" && e.Value != nil {
+					var config corev3.EntityConfig
+					if err := e.Value.UnwrapInto(&config); err == nil && config.Metadata != nil {
+						update.Name = config.Metadata.Name
+					}
+				}
+				if err := authorize(ctx, c.auth, entityAuthAttributes(ctx, "get", update.Name)); err != nil {
+					continue
+				}
+				if e.Type != storev2.WatchDelete {
+					entity, err := entityStore.GetEntityByName(ctx, update.Name)
+					if err != nil || entity == nil {
+						continue
+					}
+					update.Entity = entity
+				}
+				select {
+				case out <- update:
+				default:
+					logger.WithField("entity", update.Name).Warn("subscriber is too slow, dropping entity update")
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+// statusChanged returns true if the check status of the event differs from
+// its previous status, or if the event has no previous status.
+func statusChanged(event *corev2.Event) bool {
+	history := event.Check.History
+	if len(history) < 2 {
+		return true
+	}
+	return history[len(history)-1].Status != history[len(history)-2].Status
+}
@@ -0,0 +1,214 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+	"github.com/sensu/sensu-go/backend/authorization"
+	"github.com/sensu/sensu-go/backend/messaging"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func subscriptionAuth(resource string, names ...string) authorization.Authorizer {
+	attrs := map[authorization.AttributesKey]bool{
+		{
+			APIGroup:   "core",
+			APIVersion: "v2",
+			Namespace:  "default",
+			Resource:   resource,
+			UserName:   "legit",
+			Verb:       "list",
+		}: true,
+	}
+	for _, name := range names {
+		attrs[authorization.AttributesKey{
+			APIGroup:     "core",
+			APIVersion:   "v2",
+			Namespace:    "default",
+			Resource:     resource,
+			ResourceName: name,
+			UserName:     "legit",
+			Verb:         "get",
+		}] = true
+	}
+	return &mockAuth{attrs: attrs}
+}
+
+func newTestBus(t *testing.T) messaging.MessageBus {
+	t.Helper()
+	bus, err := messaging.NewWizardBus(messaging.WizardBusConfig{})
+	require.NoError(t, err)
+	require.NoError(t, bus.Start())
+	t.Cleanup(func() { _ = bus.Stop() })
+	return bus
+}
+
+func receiveEvent(t *testing.T, ch <-chan *corev2.Event) *corev2.Event {
+	t.Helper()
+	select {
+	case event := <-ch:
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for event")
+	}
+	return nil
+}
+
+// slowAuth authorizes the list requests, and blocks the other requests until
+// ctx is done.
+type slowAuth struct{}
+
+func (slowAuth) Authorize(ctx context.Context, attrs *authorization.Attributes) (bool, error) {
+	if attrs.Verb == "list" {
+		return true, nil
+	}
+	<-ctx.Done()
+	return false, ctx.Err()
+}
+
+func TestSubscribeEventsSlowSubscriber(t *testing.T) {
+	bus := newTestBus(t)
+	client := NewSubscriptionClient(new(mockstore.V2MockStore), bus, slowAuth{})
+
+	ctx, cancel := context.WithCancel(contextWithUser(defaultContext(), "legit", nil))
+	defer cancel()
+	_, err := client.SubscribeEvents(ctx, "", "")
+	require.NoError(t, err)
+
+	// the events are dropped rather than holding up the bus while the
+	// subscriber is neither authorizing nor receiving them
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 10*subscriptionBufferSize; i++ {
+			_ = bus.Publish(messaging.TopicEvent, corev2.FixtureEvent("foo", "check"))
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("slow subscriber held up the message bus")
+	}
+}
+
+func TestSubscribeEvents(t *testing.T) {
+	bus := newTestBus(t)
+	client := NewSubscriptionClient(new(mockstore.V2MockStore), bus, subscriptionAuth("events", "foo:check", "bar:check"))
+
+	ctx, cancel := context.WithCancel(contextWithUser(defaultContext(), "legit", nil))
+	defer cancel()
+	events, err := client.SubscribeEvents(ctx, "", "check")
+	require.NoError(t, err)
+
+	otherNamespace := corev2.FixtureEvent("foo", "check")
+	otherNamespace.Entity.Namespace = "other"
+	unauthorized := corev2.FixtureEvent("baz", "check")
+	otherCheck := corev2.FixtureEvent("foo", "other")
+	for _, event := range []*corev2.Event{
+		otherNamespace,
+		unauthorized,
+		otherCheck,
+		corev2.FixtureEvent("foo", "check"),
+		corev2.FixtureEvent("bar", "check"),
+	} {
+		require.NoError(t, bus.Publish(messaging.TopicEvent, event))
+	}
+
+	if got, want := receiveEvent(t, events).Entity.Name, "foo"; got != want {
+		t.Errorf("bad entity: got %q, want %q", got, want)
+	}
+	if got, want := receiveEvent(t, events).Entity.Name, "bar"; got != want {
+		t.Errorf("bad entity: got %q, want %q", got, want)
+	}
+
+	cancel()
+	for range events {
+	}
+}
+
+func TestSubscribeEventsUnauthorized(t *testing.T) {
+	client := NewSubscriptionClient(new(mockstore.V2MockStore), newTestBus(t), badAuth())
+	ctx := contextWithUser(defaultContext(), "legit", nil)
+	if _, err := client.SubscribeEvents(ctx, "", ""); err == nil {
+		t.Fatal("expected non-nil error")
+	}
+}
+
+func TestSubscribeKeepalives(t *testing.T) {
+	bus := newTestBus(t)
+	client := NewSubscriptionClient(new(mockstore.V2MockStore), bus, subscriptionAuth("events", "foo:keepalive", "foo:check"))
+
+	ctx, cancel := context.WithCancel(contextWithUser(defaultContext(), "legit", nil))
+	defer cancel()
+	events, err := client.SubscribeKeepalives(ctx)
+	require.NoError(t, err)
+
+	unchanged := corev2.FixtureEvent("foo", corev2.KeepaliveCheckName)
+	unchanged.Check.History = []corev2.CheckHistory{{Status: 0}, {Status: 0}}
+	changed := corev2.FixtureEvent("foo", corev2.KeepaliveCheckName)
+	changed.Check.Status = 2
+	changed.Check.History = []corev2.CheckHistory{{Status: 0}, {Status: 2}}
+	notKeepalive := corev2.FixtureEvent("foo", "check")
+	for _, event := range []*corev2.Event{unchanged, notKeepalive, changed} {
+		require.NoError(t, bus.Publish(messaging.TopicEvent, event))
+	}
+
+	if got, want := receiveEvent(t, events).Check.Status, uint32(2); got != want {
+		t.Errorf("bad status: got %d, want %d", got, want)
+	}
+
+	cancel()
+	for range events {
+	}
+}
+
+func TestSubscribeEntities(t *testing.T) {
+	watch := make(chan []storev2.WatchEvent, 1)
+	var watchCh <-chan []storev2.WatchEvent = watch
+	ecstore := new(mockstore.EntityConfigStore)
+	ecstore.On("Watch", mock.Anything, "default", "").Return(watchCh)
+	estore := new(mockstore.MockStore)
+	estore.On("GetEntityByName", mock.Anything, "foo").Return(corev2.FixtureEntity("foo"), nil)
+	s := new(mockstore.V2MockStore)
+	s.On("GetEntityConfigStore").Return(ecstore)
+	s.On("GetEntityStore").Return(estore)
+
+	client := NewSubscriptionClient(s, newTestBus(t), subscriptionAuth("entities", "foo", "bar"))
+	ctx, cancel := context.WithCancel(contextWithUser(defaultContext(), "legit", nil))
+	defer cancel()
+	updates, err := client.SubscribeEntities(ctx)
+	require.NoError(t, err)
+
+	wrap := func(name string) storev2.Wrapper {
+		return mockstore.Wrapper[*corev3.EntityConfig]{Value: corev3.FixtureEntityConfig(name)}
+	}
+	watch <- []storev2.WatchEvent{
+		{Type: storev2.WatchUpdate, Key: storev2.ResourceRequest{Namespace: "default", Name: "baz"}, Value: wrap("baz")},
+		{Type: storev2.WatchUpdate, Key: storev2.ResourceRequest{Namespace: "default", Name: "foo"}, Value: wrap("foo")},
+		{Type: storev2.WatchDelete, Key: storev2.ResourceRequest{Namespace: "default", Name: "bar"}},
+	}
+
+	receive := func() EntityUpdate {
+		select {
+		case update := <-updates:
+			return update
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for entity update")
+		}
+		return EntityUpdate{}
+	}
+	update := receive()
+	if update.Action != storev2.WatchUpdate || update.Name != "foo" || update.Entity == nil {
+		t.Errorf("bad update: %#v", update)
+	}
+	update = receive()
+	if update.Action != storev2.WatchDelete || update.Name != "bar" || update.Entity != nil {
+		t.Errorf("bad update: %#v", update)
+	}
+}
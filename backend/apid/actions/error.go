@@ -55,6 +55,9 @@ const (
 
 	// Gone indicates that an API that was once supported but no longer is.
 	Gone
+
+	// ResourceExhausted indicates that the client exceeded its rate limit.
+	ResourceExhausted
 )
 
 // Default error messages if not message is provided.
@@ -69,6 +72,7 @@ var standardErrorMessages = map[ErrCode]string{
 	PreconditionFailed: "precondition failed",
 	DeadlineExceeded:   "deadline exceeded",
 	Gone:               "this action is no longer supported",
+	ResourceExhausted:  "too many requests",
 }
 
 // Error describes an issue that ocurred while performing the action.
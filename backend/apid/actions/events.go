@@ -2,6 +2,7 @@ package actions
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/sensu/sensu-go/backend/apid/request"
@@ -78,6 +79,30 @@ func (a EventController) Get(ctx context.Context, entity, check string) (*corev2
 	return result, nil
 }
 
+// History returns the status changes of the event indicated by the supplied
+// entity and check, that occurred between start and end.
+func (a EventController) History(ctx context.Context, entity, check string, start, end time.Time) ([]*store.EventHistoryEntry, error) {
+	// History (for events) requires both an entity and check
+	if entity == "" || check == "" {
+		return nil, NewErrorf(InvalidArgument, "History() requires both an entity and a check")
+	}
+	if !start.IsZero() && !end.IsZero() && end.Before(start) {
+		return nil, NewErrorf(InvalidArgument, "end must not be before start")
+	}
+
+	history, ok := a.store.(store.EventHistoryStore)
+	if !ok {
+		return nil, NewErrorf(InternalErr, "event history is not supported by the event store")
+	}
+
+	results, err := history.GetEventHistory(ctx, entity, check, start, end)
+	if err != nil {
+		return nil, NewError(InternalErr, err)
+	}
+
+	return results, nil
+}
+
 // Delete destroys the event indicated by the supplied entity and check.
 func (a EventController) Delete(ctx context.Context, entity, check string) error {
 	// Destroy (for events) requires both an entity and check
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	corev2 "github.com/sensu/core/v2"
 	"github.com/sensu/sensu-go/backend/authentication/jwt"
@@ -176,6 +177,96 @@ func TestEventFind(t *testing.T) {
 	}
 }
 
+func TestEventHistory(t *testing.T) {
+	defaultCtx := context.Background()
+	start := time.Unix(100, 0)
+	end := time.Unix(200, 0)
+
+	testCases := []struct {
+		name            string
+		entity          string
+		check           string
+		start           time.Time
+		end             time.Time
+		entries         []*store.EventHistoryEntry
+		storeErr        error
+		expectedLen     int
+		expectedErrCode ErrCode
+		expectedErr     bool
+	}{
+		{
+			name:            "No Params",
+			expectedErrCode: InvalidArgument,
+			expectedErr:     true,
+		},
+		{
+			name:            "Only Entity Param",
+			entity:          "entity1",
+			expectedErrCode: InvalidArgument,
+			expectedErr:     true,
+		},
+		{
+			name:            "End Before Start",
+			entity:          "entity1",
+			check:           "check1",
+			start:           end,
+			end:             start,
+			expectedErrCode: InvalidArgument,
+			expectedErr:     true,
+		},
+		{
+			name:   "Found",
+			entity: "entity1",
+			check:  "check1",
+			start:  start,
+			end:    end,
+			entries: []*store.EventHistoryEntry{
+				{Entity: "entity1", Check: "check1", Status: 0, Timestamp: 110},
+				{Entity: "entity1", Check: "check1", Status: 2, Timestamp: 150},
+			},
+			expectedLen: 2,
+		},
+		{
+			name:            "Store Error",
+			entity:          "entity1",
+			check:           "check1",
+			storeErr:        errors.New("error"),
+			expectedErrCode: InternalErr,
+			expectedErr:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		store := &mockstore.MockStore{}
+		sv2 := new(mockstore.V2MockStore)
+		sv2.On("GetEventStore").Return(store)
+		bus := &mockbus.MockBus{}
+		eventController := NewEventController(sv2, bus)
+
+		t.Run(tc.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			// Mock store methods
+			store.
+				On("GetEventHistory", defaultCtx, tc.entity, tc.check, tc.start, tc.end).
+				Return(tc.entries, tc.storeErr)
+
+			// Exec Query
+			result, err := eventController.History(defaultCtx, tc.entity, tc.check, tc.start, tc.end)
+
+			if tc.expectedErr {
+				inferErr, ok := err.(Error)
+				if assert.True(ok, "expected an action error") {
+					assert.Equal(tc.expectedErrCode, inferErr.Code)
+				}
+				return
+			}
+			assert.NoError(err)
+			assert.Len(result, tc.expectedLen)
+		})
+	}
+}
+
 func TestEventDestroy(t *testing.T) {
 	defaultCtx := context.Background()
 
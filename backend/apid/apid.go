@@ -26,17 +26,33 @@ import (
 	"github.com/sensu/sensu-go/backend/authorization/rbac"
 	"github.com/sensu/sensu-go/backend/messaging"
 	"github.com/sensu/sensu-go/backend/queue"
+	"github.com/sensu/sensu-go/backend/ratelimit"
+	"github.com/sensu/sensu-go/backend/store"
 	storev2 "github.com/sensu/sensu-go/backend/store/v2"
 )
 
+// GraphQLRateLimitGroup is the route group of the rate limit of the GraphQL
+// requests. The route group of the other requests is their API group and
+// version, e.g. core/v2.
+const GraphQLRateLimitGroup = "graphql"
+
+// AuthenticationRateLimitGroup is the route group of the rate limit of the
+// authentication requests, which are only limited by source IP.
+const AuthenticationRateLimitGroup = "auth"
+
 // APId is the backend HTTP API.
 type APId struct {
 	Authenticator              *authentication.Authenticator
 	HTTPServer                 *http.Server
 	CoreSubrouter              *mux.Router
 	CoreV3Subrouter            *mux.Router
+	PipelineSubrouter          *mux.Router
+	AuthenticationV2Subrouter  *mux.Router
+	SecretsSubrouter           *mux.Router
+	MaintenanceSubrouter       *mux.Router
 	EntityLimitedCoreSubrouter *mux.Router
 	GraphQLSubrouter           *mux.Router
+	AuditSubrouter             *mux.Router
 	RequestLimit               int64
 
 	stopping chan struct{}
@@ -64,6 +80,27 @@ type Config struct {
 	ClusterVersion string
 	GraphQLService *graphql.Service
 	Queue          queue.Client
+	AuditStore     store.AuditStore
+
+	// APIKeyUsageStore records the last time the API keys are used.
+	APIKeyUsageStore store.APIKeyUsageStore
+
+	// OIDCStateStore keeps the OIDC logins in progress.
+	OIDCStateStore store.OIDCStateStore
+
+	// RateLimiter keeps the token buckets of the RateLimits, which are the
+	// limits of the requests of each client by route group, e.g. core/v2 or
+	// graphql.
+	RateLimiter ratelimit.Limiter
+	RateLimits  map[string]ratelimit.Limit
+
+	// IPRateLimits are the limits of the requests of each source IP by route
+	// group, applied before the requests are authenticated.
+	IPRateLimits map[string]ratelimit.Limit
+
+	// AllowedOrigins are the origins, besides the origin of the API, from
+	// which browsers can open GraphQL subscriptions.
+	AllowedOrigins []string
 }
 
 // New creates a new APId.
@@ -96,7 +133,12 @@ func New(c Config, opts ...Option) (*APId, error) {
 	_ = AuthenticationSubrouter(router, c)
 	a.CoreSubrouter = CoreSubrouter(router, c)
 	a.CoreV3Subrouter = CoreV3Subrouter(router, c)
+	a.PipelineSubrouter = PipelineSubrouter(router, c)
+	a.AuthenticationV2Subrouter = AuthenticationV2Subrouter(router, c)
+	a.SecretsSubrouter = SecretsSubrouter(router, c)
+	a.MaintenanceSubrouter = MaintenanceSubrouter(router, c)
 	a.EntityLimitedCoreSubrouter = EntityLimitedCoreSubrouter(router, c)
+	a.AuditSubrouter = AuditSubrouter(router, c)
 
 	a.HTTPServer = &http.Server{
 		Addr:         c.ListenAddress,
@@ -129,8 +171,23 @@ func NewRouter() *mux.Router {
 // AuthenticationSubrouter initializes a subrouter that handles all
 // authentication requests
 func AuthenticationSubrouter(router *mux.Router, cfg Config) *mux.Router {
+	// The authorization code flow of the OIDC providers is driven by
+	// redirections of the user's browser, which carry no tokens.
+	oidcSubrouter := NewSubrouter(
+		router.NewRoute(),
+		middlewares.SourceIP{},
+		middlewares.RateLimit{Limiter: cfg.RateLimiter, Limits: cfg.IPRateLimits, Group: AuthenticationRateLimitGroup, ByIP: true},
+		middlewares.SimpleLogger{},
+		middlewares.LimitRequest{Limit: cfg.RequestLimit},
+	)
+	mountRouters(oidcSubrouter,
+		routers.NewOIDCRouter(api.NewOIDCClient(cfg.Authenticator, cfg.OIDCStateStore)),
+	)
+
 	subrouter := NewSubrouter(
 		router.NewRoute(),
+		middlewares.SourceIP{},
+		middlewares.RateLimit{Limiter: cfg.RateLimiter, Limits: cfg.IPRateLimits, Group: AuthenticationRateLimitGroup, ByIP: true},
 		middlewares.SimpleLogger{},
 		middlewares.RefreshToken{},
 		middlewares.LimitRequest{Limit: cfg.RequestLimit},
@@ -149,7 +206,10 @@ func CoreSubrouter(router *mux.Router, cfg Config) *mux.Router {
 	subrouter := NewSubrouter(
 		router.PathPrefix("/api/{group:core}/{version:v2}/"),
 		middlewares.Namespace{},
-		middlewares.Authentication{Store: cfg.Store},
+		middlewares.SourceIP{},
+		middlewares.RateLimit{Limiter: cfg.RateLimiter, Limits: cfg.IPRateLimits, ByIP: true},
+		middlewares.Authentication{Store: cfg.Store, APIKeyUsage: cfg.APIKeyUsageStore},
+		middlewares.RateLimit{Limiter: cfg.RateLimiter, Limits: cfg.RateLimits},
 		middlewares.SimpleLogger{},
 		middlewares.AuthorizationAttributes{},
 		middlewares.Authorization{Authorizer: &rbac.Authorizer{Store: cfg.Store}},
@@ -160,7 +220,7 @@ func CoreSubrouter(router *mux.Router, cfg Config) *mux.Router {
 	mountRouters(
 		subrouter,
 		routers.NewAssetRouter(cfg.Store),
-		routers.NewAPIKeysRouter(cfg.Store),
+		routers.NewAPIKeysRouter(cfg.Store, cfg.APIKeyUsageStore),
 		routers.NewChecksRouter(cfg.Store, cfg.Queue),
 		routers.NewClusterRolesRouter(cfg.Store),
 		routers.NewClusterRoleBindingsRouter(cfg.Store),
@@ -183,7 +243,10 @@ func CoreV3Subrouter(router *mux.Router, cfg Config) *mux.Router {
 	subrouter := NewSubrouter(
 		router.PathPrefix("/api/{group:core}/{version:v3}/"),
 		middlewares.Namespace{},
-		middlewares.Authentication{Store: cfg.Store},
+		middlewares.SourceIP{},
+		middlewares.RateLimit{Limiter: cfg.RateLimiter, Limits: cfg.IPRateLimits, ByIP: true},
+		middlewares.Authentication{Store: cfg.Store, APIKeyUsage: cfg.APIKeyUsageStore},
+		middlewares.RateLimit{Limiter: cfg.RateLimiter, Limits: cfg.RateLimits},
 		middlewares.SimpleLogger{},
 		middlewares.AuthorizationAttributes{},
 		middlewares.Authorization{Authorizer: &rbac.Authorizer{Store: cfg.Store}},
@@ -198,13 +261,139 @@ func CoreV3Subrouter(router *mux.Router, cfg Config) *mux.Router {
 	return subrouter
 }
 
+// PipelineSubrouter initializes a subrouter that handles all requests coming
+// to /api/pipeline/v1
+func PipelineSubrouter(router *mux.Router, cfg Config) *mux.Router {
+	subrouter := NewSubrouter(
+		router.PathPrefix("/api/{group:pipeline}/{version:v1}/"),
+		middlewares.Namespace{},
+		middlewares.SourceIP{},
+		middlewares.RateLimit{Limiter: cfg.RateLimiter, Limits: cfg.IPRateLimits, ByIP: true},
+		middlewares.Authentication{Store: cfg.Store, APIKeyUsage: cfg.APIKeyUsageStore},
+		middlewares.RateLimit{Limiter: cfg.RateLimiter, Limits: cfg.RateLimits},
+		middlewares.SimpleLogger{},
+		middlewares.AuthorizationAttributes{},
+		middlewares.Authorization{Authorizer: &rbac.Authorizer{Store: cfg.Store}},
+		middlewares.LimitRequest{Limit: cfg.RequestLimit},
+		middlewares.Pagination{},
+		middlewares.Selectors{},
+	)
+	mountRouters(
+		subrouter,
+		routers.NewHTTPHandlersRouter(cfg.Store),
+		routers.NewEscalationPoliciesRouter(cfg.Store),
+		routers.NewThrottleFiltersRouter(cfg.Store),
+	)
+	return subrouter
+}
+
+// AuthenticationV2Subrouter initializes a subrouter that handles all requests
+// coming to /api/authentication/v2
+func AuthenticationV2Subrouter(router *mux.Router, cfg Config) *mux.Router {
+	subrouter := NewSubrouter(
+		router.PathPrefix("/api/{group:authentication}/{version:v2}/"),
+		middlewares.Namespace{},
+		middlewares.SourceIP{},
+		middlewares.RateLimit{Limiter: cfg.RateLimiter, Limits: cfg.IPRateLimits, ByIP: true},
+		middlewares.Authentication{Store: cfg.Store, APIKeyUsage: cfg.APIKeyUsageStore},
+		middlewares.RateLimit{Limiter: cfg.RateLimiter, Limits: cfg.RateLimits},
+		middlewares.SimpleLogger{},
+		middlewares.AuthorizationAttributes{},
+		middlewares.Authorization{Authorizer: &rbac.Authorizer{Store: cfg.Store}},
+		middlewares.LimitRequest{Limit: cfg.RequestLimit},
+		middlewares.Pagination{},
+		middlewares.Selectors{},
+	)
+	mountRouters(
+		subrouter,
+		routers.NewAuthProvidersRouter(cfg.Store),
+	)
+	return subrouter
+}
+
+// SecretsSubrouter initializes a subrouter that handles all requests coming to
+// /api/secrets/v1
+func SecretsSubrouter(router *mux.Router, cfg Config) *mux.Router {
+	subrouter := NewSubrouter(
+		router.PathPrefix("/api/{group:secrets}/{version:v1}/"),
+		middlewares.Namespace{},
+		middlewares.SourceIP{},
+		middlewares.RateLimit{Limiter: cfg.RateLimiter, Limits: cfg.IPRateLimits, ByIP: true},
+		middlewares.Authentication{Store: cfg.Store, APIKeyUsage: cfg.APIKeyUsageStore},
+		middlewares.RateLimit{Limiter: cfg.RateLimiter, Limits: cfg.RateLimits},
+		middlewares.SimpleLogger{},
+		middlewares.AuthorizationAttributes{},
+		middlewares.Authorization{Authorizer: &rbac.Authorizer{Store: cfg.Store}},
+		middlewares.LimitRequest{Limit: cfg.RequestLimit},
+		middlewares.Pagination{},
+		middlewares.Selectors{},
+	)
+	mountRouters(
+		subrouter,
+		routers.NewSecretsProvidersRouter(cfg.Store),
+		routers.NewSecretsRouter(cfg.Store),
+	)
+	return subrouter
+}
+
+// MaintenanceSubrouter initializes a subrouter that handles all requests
+// coming to /api/maintenance/v1
+func MaintenanceSubrouter(router *mux.Router, cfg Config) *mux.Router {
+	subrouter := NewSubrouter(
+		router.PathPrefix("/api/{group:maintenance}/{version:v1}/"),
+		middlewares.Namespace{},
+		middlewares.SourceIP{},
+		middlewares.RateLimit{Limiter: cfg.RateLimiter, Limits: cfg.IPRateLimits, ByIP: true},
+		middlewares.Authentication{Store: cfg.Store, APIKeyUsage: cfg.APIKeyUsageStore},
+		middlewares.RateLimit{Limiter: cfg.RateLimiter, Limits: cfg.RateLimits},
+		middlewares.SimpleLogger{},
+		middlewares.AuthorizationAttributes{},
+		middlewares.Authorization{Authorizer: &rbac.Authorizer{Store: cfg.Store}},
+		middlewares.LimitRequest{Limit: cfg.RequestLimit},
+		middlewares.Pagination{},
+		middlewares.Selectors{},
+	)
+	mountRouters(
+		subrouter,
+		routers.NewMaintenanceWindowsRouter(cfg.Store),
+	)
+	return subrouter
+}
+
+// AuditSubrouter initializes a subrouter that handles all requests coming to
+// /api/audit/v1
+func AuditSubrouter(router *mux.Router, cfg Config) *mux.Router {
+	subrouter := NewSubrouter(
+		router.PathPrefix("/api/{group:audit}/{version:v1}/"),
+		middlewares.Namespace{},
+		middlewares.SourceIP{},
+		middlewares.RateLimit{Limiter: cfg.RateLimiter, Limits: cfg.IPRateLimits, ByIP: true},
+		middlewares.Authentication{Store: cfg.Store, APIKeyUsage: cfg.APIKeyUsageStore},
+		middlewares.RateLimit{Limiter: cfg.RateLimiter, Limits: cfg.RateLimits},
+		middlewares.SimpleLogger{},
+		middlewares.AuthorizationAttributes{},
+		middlewares.Authorization{Authorizer: &rbac.Authorizer{Store: cfg.Store}},
+		middlewares.LimitRequest{Limit: cfg.RequestLimit},
+	)
+	if cfg.AuditStore != nil {
+		mountRouters(
+			subrouter,
+			routers.NewAuditRouter(cfg.AuditStore),
+		)
+	}
+	return subrouter
+}
+
 // EntityLimitedCoreSubrouter initializes a subrouter that handles all requests
 // coming to /api/core/v2 that must be gated by entity limits.
 func EntityLimitedCoreSubrouter(router *mux.Router, cfg Config) *mux.Router {
 	subrouter := NewSubrouter(
 		router.PathPrefix("/api/{group:core}/{version:v2}/"),
 		middlewares.Namespace{},
-		middlewares.Authentication{Store: cfg.Store},
+		middlewares.SourceIP{},
+		middlewares.RateLimit{Limiter: cfg.RateLimiter, Limits: cfg.IPRateLimits, ByIP: true},
+		middlewares.Authentication{Store: cfg.Store, APIKeyUsage: cfg.APIKeyUsageStore},
+		middlewares.RateLimit{Limiter: cfg.RateLimiter, Limits: cfg.RateLimits},
 		middlewares.SimpleLogger{},
 		middlewares.AuthorizationAttributes{},
 		middlewares.Authorization{Authorizer: &rbac.Authorizer{Store: cfg.Store}},
@@ -234,7 +423,10 @@ func GraphQLSubrouter(router *mux.Router, cfg Config) *mux.Router {
 		//
 		// https://github.com/graphql/graphiql
 		// https://graphql.org/learn/introspection/
-		middlewares.Authentication{IgnoreUnauthorized: true, Store: cfg.Store},
+		middlewares.SourceIP{},
+		middlewares.RateLimit{Limiter: cfg.RateLimiter, Limits: cfg.IPRateLimits, Group: GraphQLRateLimitGroup, ByIP: true},
+		middlewares.Authentication{IgnoreUnauthorized: true, Store: cfg.Store, APIKeyUsage: cfg.APIKeyUsageStore},
+		middlewares.RateLimit{Limiter: cfg.RateLimiter, Limits: cfg.RateLimits, Group: GraphQLRateLimitGroup},
 		middlewares.SimpleLogger{},
 	)
 
@@ -249,8 +441,10 @@ func GraphQLSubrouter(router *mux.Router, cfg Config) *mux.Router {
 	mountRouters(
 		subrouter,
 		&routers.GraphQLRouter{
-			Service: cfg.GraphQLService,
-			Timeout: timeout,
+			Service:        cfg.GraphQLService,
+			Timeout:        timeout,
+			Authenticate:   middlewares.Authentication{Store: cfg.Store, APIKeyUsage: cfg.APIKeyUsageStore}.Authenticate,
+			AllowedOrigins: cfg.AllowedOrigins,
 		},
 	)
 
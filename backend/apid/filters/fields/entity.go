@@ -0,0 +1,25 @@
+package fields
+
+import (
+	"strings"
+
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+)
+
+// EntityFields returns the fields of an entity used by field selectors. It
+// extends the fields of corev3.EntityFields with the names of the processes
+// of the entity, as entity.system.processes, so that the entities running a
+// given process can be selected.
+func EntityFields(r corev3.Resource) map[string]string {
+	fields := corev3.EntityFields(r)
+	processes := r.(*corev2.Entity).System.Processes
+	names := make([]string, 0, len(processes))
+	for _, process := range processes {
+		if process != nil {
+			names = append(names, process.Name)
+		}
+	}
+	fields["entity.system.processes"] = strings.Join(names, ",")
+	return fields
+}
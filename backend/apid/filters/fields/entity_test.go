@@ -0,0 +1,27 @@
+package fields
+
+import (
+	"testing"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/selector"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntityFields(t *testing.T) {
+	entity := corev2.FixtureEntity("foo")
+	entity.System.Processes = []*corev2.Process{{Name: "nginx"}, {Name: "postgres"}}
+
+	fields := EntityFields(entity)
+	assert.Equal(t, "foo", fields["entity.name"])
+	assert.Equal(t, "nginx,postgres", fields["entity.system.processes"])
+
+	sel, err := selector.ParseFieldSelector("nginx in entity.system.processes")
+	require.NoError(t, err)
+	assert.True(t, sel.Matches(fields))
+
+	sel, err = selector.ParseFieldSelector("redis in entity.system.processes")
+	require.NoError(t, err)
+	assert.False(t, sel.Matches(fields))
+}
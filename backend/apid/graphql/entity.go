@@ -7,6 +7,7 @@ import (
 
 	corev2 "github.com/sensu/core/v2"
 	corev3 "github.com/sensu/core/v3"
+	maintenancev1 "github.com/sensu/sensu-go/api/maintenance/v1"
 	"github.com/sensu/sensu-go/backend/apid/graphql/filter"
 	"github.com/sensu/sensu-go/backend/apid/graphql/globalid"
 	"github.com/sensu/sensu-go/backend/apid/graphql/schema"
@@ -153,6 +154,31 @@ func (r *entityImpl) Silences(p graphql.ResolveParams) (interface{}, error) {
 	return records, err
 }
 
+// InMaintenance implements response to request for 'inMaintenance' field.
+func (r *entityImpl) InMaintenance(p graphql.ResolveParams) (bool, error) {
+	src := p.Source.(*corev2.Entity)
+	results, err := loadSilenceds(p.Context, src.Namespace)
+	records := filterSilenceds(results, filterMaintenanceByEntity(src))
+	return len(records) > 0, err
+}
+
+func filterMaintenanceByEntity(src *corev2.Entity) silencePredicate {
+	now := time.Now().Unix()
+	return func(obj *corev2.Silenced) bool {
+		if obj.Labels[corev2.ManagedByLabel] != maintenancev1.MaintenanceWindowManager {
+			return false
+		}
+		if !(obj.Check == "" || obj.Check == "*") || obj.Begin > now {
+			return false
+		}
+		if obj.ExpireAt > 0 && obj.ExpireAt <= now {
+			return false
+		}
+		return obj.Subscription == corev2.GetEntitySubscription(src.Name) ||
+			strings.InArray(obj.Subscription, src.Subscriptions)
+	}
+}
+
 func filterSilenceByEntity(src *corev2.Entity) silencePredicate {
 	now := time.Now().Unix()
 	return func(obj *corev2.Silenced) bool {
@@ -18,6 +18,15 @@ func EntityFilters() map[string]filter.Filter {
 		"subscription": filter.String(func(res corev3.Resource, v string) bool {
 			return strings.InArray(v, res.(*v2.Entity).Subscriptions)
 		}),
+		// process:nginx | process:postgres
+		"process": filter.String(func(res corev3.Resource, v string) bool {
+			for _, process := range res.(*v2.Entity).System.Processes {
+				if process != nil && process.Name == v {
+					return true
+				}
+			}
+			return false
+		}),
 	}
 
 	// merge global filters
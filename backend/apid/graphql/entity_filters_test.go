@@ -54,6 +54,24 @@ func TestEntityFilters(t *testing.T) {
 				return entity
 			},
 		},
+		{
+			statement: "process:nginx",
+			expect:    true,
+			setupRecord: func() *v2.Entity {
+				entity := v2.FixtureEntity("a")
+				entity.System.Processes = []*v2.Process{{Name: "sshd"}, {Name: "nginx"}}
+				return entity
+			},
+		},
+		{
+			statement: "process:nginx",
+			expect:    false,
+			setupRecord: func() *v2.Entity {
+				entity := v2.FixtureEntity("a")
+				entity.System.Processes = []*v2.Process{{Name: "nginx-exporter"}}
+				return entity
+			},
+		},
 	}
 
 	for _, tc := range testCases {
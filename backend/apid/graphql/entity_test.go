@@ -7,6 +7,7 @@ import (
 	"time"
 
 	corev2 "github.com/sensu/core/v2"
+	maintenancev1 "github.com/sensu/sensu-go/api/maintenance/v1"
 	"github.com/sensu/sensu-go/backend/apid/graphql/schema"
 	"github.com/sensu/sensu-go/graphql"
 	"github.com/stretchr/testify/assert"
@@ -253,3 +254,71 @@ func Test_entityImpl_IsSilenced(t *testing.T) {
 		})
 	}
 }
+
+func Test_entityImpl_InMaintenance(t *testing.T) {
+	managed := map[string]string{corev2.ManagedByLabel: maintenancev1.MaintenanceWindowManager}
+	testCases := []struct {
+		name     string
+		entity   *corev2.Entity
+		silence  *corev2.Silenced
+		expected bool
+	}{
+		{
+			name:     "active maintenance window silence",
+			entity:   &corev2.Entity{Subscriptions: []string{"unix"}},
+			silence:  &corev2.Silenced{ObjectMeta: corev2.ObjectMeta{Labels: managed}, Subscription: "unix"},
+			expected: true,
+		},
+		{
+			name:     "entity subscription",
+			entity:   &corev2.Entity{ObjectMeta: corev2.ObjectMeta{Name: "venice"}},
+			silence:  &corev2.Silenced{ObjectMeta: corev2.ObjectMeta{Labels: managed}, Subscription: "entity:venice"},
+			expected: true,
+		},
+		{
+			name:     "silence not managed by a maintenance window",
+			entity:   &corev2.Entity{Subscriptions: []string{"unix"}},
+			silence:  &corev2.Silenced{Subscription: "unix"},
+			expected: false,
+		},
+		{
+			name:     "upcoming maintenance window silence",
+			entity:   &corev2.Entity{Subscriptions: []string{"unix"}},
+			silence:  &corev2.Silenced{ObjectMeta: corev2.ObjectMeta{Labels: managed}, Subscription: "unix", Begin: 999_999_999_999},
+			expected: false,
+		},
+		{
+			name:     "expired maintenance window silence",
+			entity:   &corev2.Entity{Subscriptions: []string{"unix"}},
+			silence:  &corev2.Silenced{ObjectMeta: corev2.ObjectMeta{Labels: managed}, Subscription: "unix", ExpireAt: 1},
+			expected: false,
+		},
+		{
+			name:     "maintenance window silence of a single check",
+			entity:   &corev2.Entity{Subscriptions: []string{"unix"}},
+			silence:  &corev2.Silenced{ObjectMeta: corev2.ObjectMeta{Labels: managed}, Subscription: "unix", Check: "disk-check"},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := new(MockSilencedClient)
+			client.On("ListSilenced", mock.Anything).Return([]*corev2.Silenced{
+				tc.silence,
+			}, nil).Once()
+
+			impl := &entityImpl{}
+			params := graphql.ResolveParams{}
+			cfg := ServiceConfig{SilencedClient: client}
+			params.Context = contextWithLoadersNoCache(context.Background(), cfg)
+			params.Source = tc.entity
+
+			r, err := impl.InMaintenance(params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tc.expected, r)
+		})
+	}
+}
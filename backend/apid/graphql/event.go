@@ -7,11 +7,13 @@ import (
 	corev3 "github.com/sensu/core/v3"
 	"github.com/sensu/sensu-go/backend/apid/graphql/globalid"
 	"github.com/sensu/sensu-go/backend/apid/graphql/schema"
+	"github.com/sensu/sensu-go/backend/store"
 	"github.com/sensu/sensu-go/graphql"
 	"github.com/sensu/core/v3/types"
 )
 
 var _ schema.EventFieldResolvers = (*eventImpl)(nil)
+var _ schema.EventHistoryEntryFieldResolvers = (*eventHistoryEntryImpl)(nil)
 
 //
 // Implement CheckConfigFieldResolvers
@@ -19,6 +21,7 @@ var _ schema.EventFieldResolvers = (*eventImpl)(nil)
 
 type eventImpl struct {
 	schema.EventAliases
+	client EventClient
 }
 
 // ID implements response to request for 'id' field.
@@ -44,6 +47,20 @@ func (r *eventImpl) Timestamp(p graphql.ResolveParams) (time.Time, error) {
 	return time.Unix(event.Timestamp, 0), nil
 }
 
+// History implements response to request for 'history' field.
+func (r *eventImpl) History(p schema.EventHistoryFieldResolverParams) (interface{}, error) {
+	event := p.Source.(*corev2.Event)
+	if event.Entity == nil || event.Check == nil {
+		return []*store.EventHistoryEntry{}, nil
+	}
+	ctx := contextWithNamespace(p.Context, event.Entity.Namespace)
+	entries, err := r.client.FetchEventHistory(ctx, event.Entity.Name, event.Check.Name, p.Args.From, p.Args.To)
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
 // IsIncident implements response to request for 'isIncident' field.
 func (r *eventImpl) IsIncident(p graphql.ResolveParams) (bool, error) {
 	event := p.Source.(*corev2.Event)
@@ -101,3 +118,29 @@ func (r *eventImpl) IsTypeOf(s interface{}, p graphql.IsTypeOfParams) bool {
 func (r *eventImpl) ToJSON(p graphql.ResolveParams) (interface{}, error) {
 	return types.WrapResource(p.Source.(corev3.Resource)), nil
 }
+
+//
+// Implement EventHistoryEntryFieldResolvers
+//
+
+type eventHistoryEntryImpl struct {
+	schema.EventHistoryEntryAliases
+}
+
+// Status implements response to request for 'status' field.
+func (r *eventHistoryEntryImpl) Status(p graphql.ResolveParams) (interface{}, error) {
+	entry := p.Source.(*store.EventHistoryEntry)
+	return entry.Status, nil
+}
+
+// Executed implements response to request for 'executed' field.
+func (r *eventHistoryEntryImpl) Executed(p graphql.ResolveParams) (time.Time, error) {
+	entry := p.Source.(*store.EventHistoryEntry)
+	return time.Unix(entry.Executed, 0), nil
+}
+
+// Timestamp implements response to request for 'timestamp' field.
+func (r *eventHistoryEntryImpl) Timestamp(p graphql.ResolveParams) (time.Time, error) {
+	entry := p.Source.(*store.EventHistoryEntry)
+	return time.Unix(entry.Timestamp, 0), nil
+}
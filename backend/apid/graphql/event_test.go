@@ -4,8 +4,11 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/apid/graphql/schema"
+	"github.com/sensu/sensu-go/backend/store"
 	"github.com/sensu/sensu-go/graphql"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -102,3 +105,29 @@ func TestEventTypeSilencesField(t *testing.T) {
 	require.NoError(t, err)
 	assert.Len(t, res, 4)
 }
+
+func TestEventTypeHistoryField(t *testing.T) {
+	event := corev2.FixtureEvent("my-entity", "my-check")
+	from := time.Unix(100, 0)
+	entries := []*store.EventHistoryEntry{
+		{Entity: "my-entity", Check: "my-check", Status: 2, Executed: 120, Timestamp: 121},
+	}
+
+	client := new(MockEventClient)
+	client.On("FetchEventHistory", mock.Anything, "my-entity", "my-check", from, time.Time{}).Return(entries, nil).Once()
+
+	params := schema.EventHistoryFieldResolverParams{}
+	params.Context = context.Background()
+	params.Source = event
+	params.Args.From = from
+
+	impl := &eventImpl{client: client}
+	res, err := impl.History(params)
+	require.NoError(t, err)
+	assert.Equal(t, entries, res)
+
+	entryImpl := &eventHistoryEntryImpl{}
+	ts, err := entryImpl.Timestamp(graphql.ResolveParams{Source: entries[0]})
+	require.NoError(t, err)
+	assert.Equal(t, time.Unix(121, 0), ts)
+}
@@ -2,6 +2,7 @@ package graphql
 
 import (
 	"context"
+	"time"
 
 	dto "github.com/prometheus/client_model/go"
 	corev2 "github.com/sensu/core/v2"
@@ -37,6 +38,7 @@ type EntityClient interface {
 type EventClient interface {
 	UpdateEvent(ctx context.Context, event *corev2.Event) error
 	FetchEvent(ctx context.Context, entity, check string) (*corev2.Event, error)
+	FetchEventHistory(ctx context.Context, entity, check string, start, end time.Time) ([]*store.EventHistoryEntry, error)
 	DeleteEvent(ctx context.Context, entity, check string) error
 	ListEvents(ctx context.Context, pred *store.SelectionPredicate) ([]*corev2.Event, error)
 	ListEventsByEntity(ctx context.Context, entity string, pred *store.SelectionPredicate) ([]*corev2.Event, error)
@@ -44,6 +46,12 @@ type EventClient interface {
 	EventStoreSupportsFiltering(context.Context) bool
 }
 
+type SubscriptionClient interface {
+	SubscribeEvents(ctx context.Context, entity, check string) (<-chan *corev2.Event, error)
+	SubscribeKeepalives(ctx context.Context) (<-chan *corev2.Event, error)
+	SubscribeEntities(ctx context.Context) (<-chan api.EntityUpdate, error)
+}
+
 type EventFilterClient interface {
 	ListEventFilters(ctx context.Context) ([]*corev2.EventFilter, error)
 	FetchEventFilter(ctx context.Context, name string) (*corev2.EventFilter, error)
@@ -2,6 +2,7 @@ package graphql
 
 import (
 	"context"
+	"time"
 
 	dto "github.com/prometheus/client_model/go"
 	corev2 "github.com/sensu/core/v2"
@@ -158,6 +159,11 @@ func (c *MockEventClient) FetchEvent(ctx context.Context, entity, check string)
 	return args.Get(0).(*corev2.Event), args.Error(1)
 }
 
+func (c *MockEventClient) FetchEventHistory(ctx context.Context, entity, check string, start, end time.Time) ([]*store.EventHistoryEntry, error) {
+	args := c.Called(ctx, entity, check, start, end)
+	return args.Get(0).([]*store.EventHistoryEntry), args.Error(1)
+}
+
 func (c *MockEventClient) DeleteEvent(ctx context.Context, entity, check string) error {
 	return c.Called(ctx, entity, check).Error(0)
 }
@@ -419,3 +425,22 @@ func (m *MockRBACClient) UpdateClusterRole(ctx context.Context, rb *corev2.Clust
 	args := m.Called(ctx, rb)
 	return args.Error(0)
 }
+
+type MockSubscriptionClient struct {
+	mock.Mock
+}
+
+func (c *MockSubscriptionClient) SubscribeEvents(ctx context.Context, entity, check string) (<-chan *corev2.Event, error) {
+	args := c.Called(ctx, entity, check)
+	return args.Get(0).(<-chan *corev2.Event), args.Error(1)
+}
+
+func (c *MockSubscriptionClient) SubscribeKeepalives(ctx context.Context) (<-chan *corev2.Event, error) {
+	args := c.Called(ctx)
+	return args.Get(0).(<-chan *corev2.Event), args.Error(1)
+}
+
+func (c *MockSubscriptionClient) SubscribeEntities(ctx context.Context) (<-chan api.EntityUpdate, error) {
+	args := c.Called(ctx)
+	return args.Get(0).(<-chan api.EntityUpdate), args.Error(1)
+}
@@ -5,6 +5,7 @@ import (
 
 	corev2 "github.com/sensu/core/v2"
 	corev3 "github.com/sensu/core/v3"
+	"github.com/sensu/sensu-go/backend/apid/filters/fields"
 	"github.com/sensu/sensu-go/backend/apid/graphql/filter"
 	"github.com/sensu/sensu-go/backend/apid/graphql/globalid"
 	"github.com/sensu/sensu-go/backend/apid/graphql/schema"
@@ -277,7 +278,7 @@ CONTINUE:
 	}
 
 	// filter
-	matchFn, err := filter.Compile(p.Args.Filters, EntityFilters(), corev3.EntityFields)
+	matchFn, err := filter.Compile(p.Args.Filters, EntityFilters(), fields.EntityFields)
 	if err != nil {
 		return res, err
 	}
@@ -100,6 +100,9 @@ type EntityFieldResolvers interface {
 	// IsSilenced implements response to request for 'isSilenced' field.
 	IsSilenced(p graphql.ResolveParams) (bool, error)
 
+	// InMaintenance implements response to request for 'inMaintenance' field.
+	InMaintenance(p graphql.ResolveParams) (bool, error)
+
 	// Silences implements response to request for 'silences' field.
 	Silences(p graphql.ResolveParams) (interface{}, error)
 
@@ -299,6 +302,19 @@ func (_ EntityAliases) IsSilenced(p graphql.ResolveParams) (bool, error) {
 	return ret, err
 }
 
+// InMaintenance implements response to request for 'inMaintenance' field.
+func (_ EntityAliases) InMaintenance(p graphql.ResolveParams) (bool, error) {
+	val, err := graphql.DefaultResolver(p.Source, p.Info.FieldName)
+	ret, ok := val.(bool)
+	if err != nil {
+		return ret, err
+	}
+	if !ok {
+		return ret, errors.New("unable to coerce value for field 'inMaintenance'")
+	}
+	return ret, err
+}
+
 // Silences implements response to request for 'silences' field.
 func (_ EntityAliases) Silences(p graphql.ResolveParams) (interface{}, error) {
 	val, err := graphql.DefaultResolver(p.Source, p.Info.FieldName)
@@ -486,6 +502,15 @@ func _ObjTypeEntityIsSilencedHandler(impl interface{}) graphql1.FieldResolveFn {
 	}
 }
 
+func _ObjTypeEntityInMaintenanceHandler(impl interface{}) graphql1.FieldResolveFn {
+	resolver := impl.(interface {
+		InMaintenance(p graphql.ResolveParams) (bool, error)
+	})
+	return func(frp graphql1.ResolveParams) (interface{}, error) {
+		return resolver.InMaintenance(frp)
+	}
+}
+
 func _ObjTypeEntitySilencesHandler(impl interface{}) graphql1.FieldResolveFn {
 	resolver := impl.(interface {
 		Silences(p graphql.ResolveParams) (interface{}, error)
@@ -554,6 +579,13 @@ func _ObjectTypeEntityConfigFn() graphql1.ObjectConfig {
 				Name:              "id",
 				Type:              graphql1.NewNonNull(graphql1.ID),
 			},
+			"inMaintenance": &graphql1.Field{
+				Args:              graphql1.FieldConfigArgument{},
+				DeprecationReason: "",
+				Description:       "inMaintenance returns true if an occurrence of a maintenance window is\ncurrently silencing all the checks of the entity.",
+				Name:              "inMaintenance",
+				Type:              graphql1.NewNonNull(graphql1.Boolean),
+			},
 			"isSilenced": &graphql1.Field{
 				Args:              graphql1.FieldConfigArgument{},
 				DeprecationReason: "",
@@ -683,6 +715,7 @@ var _ObjectTypeEntityDesc = graphql.ObjectDesc{
 		"entityClass":       _ObjTypeEntityEntityClassHandler,
 		"events":            _ObjTypeEntityEventsHandler,
 		"id":                _ObjTypeEntityIDHandler,
+		"inMaintenance":     _ObjTypeEntityInMaintenanceHandler,
 		"isSilenced":        _ObjTypeEntityIsSilencedHandler,
 		"lastSeen":          _ObjTypeEntityLastSeenHandler,
 		"metadata":          _ObjTypeEntityMetadataHandler,
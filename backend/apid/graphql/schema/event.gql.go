@@ -5,10 +5,23 @@ package schema
 import (
 	errors "errors"
 	graphql1 "github.com/graphql-go/graphql"
+	mapstructure "github.com/mitchellh/mapstructure"
 	graphql "github.com/sensu/sensu-go/graphql"
 	time "time"
 )
 
+// EventHistoryFieldResolverArgs contains arguments provided to history when selected
+type EventHistoryFieldResolverArgs struct {
+	From time.Time // From - self descriptive
+	To   time.Time // To - self descriptive
+}
+
+// EventHistoryFieldResolverParams contains contextual info to resolve history field
+type EventHistoryFieldResolverParams struct {
+	graphql.ResolveParams
+	Args EventHistoryFieldResolverArgs
+}
+
 // EventFieldResolvers represents a collection of methods whose products represent the
 // response values of the 'Event' type.
 type EventFieldResolvers interface {
@@ -54,6 +67,9 @@ type EventFieldResolvers interface {
 	// Silenced implements response to request for 'silenced' field.
 	Silenced(p graphql.ResolveParams) ([]string, error)
 
+	// History implements response to request for 'history' field.
+	History(p EventHistoryFieldResolverParams) (interface{}, error)
+
 	// ToJSON implements response to request for 'toJSON' field.
 	ToJSON(p graphql.ResolveParams) (interface{}, error)
 }
@@ -211,6 +227,12 @@ func (_ EventAliases) Silenced(p graphql.ResolveParams) ([]string, error) {
 	return ret, err
 }
 
+// History implements response to request for 'history' field.
+func (_ EventAliases) History(p EventHistoryFieldResolverParams) (interface{}, error) {
+	val, err := graphql.DefaultResolver(p.Source, p.Info.FieldName)
+	return val, err
+}
+
 // ToJSON implements response to request for 'toJSON' field.
 func (_ EventAliases) ToJSON(p graphql.ResolveParams) (interface{}, error) {
 	val, err := graphql.DefaultResolver(p.Source, p.Info.FieldName)
@@ -350,6 +372,21 @@ func _ObjTypeEventSilencedHandler(impl interface{}) graphql1.FieldResolveFn {
 	}
 }
 
+func _ObjTypeEventHistoryHandler(impl interface{}) graphql1.FieldResolveFn {
+	resolver := impl.(interface {
+		History(p EventHistoryFieldResolverParams) (interface{}, error)
+	})
+	return func(p graphql1.ResolveParams) (interface{}, error) {
+		frp := EventHistoryFieldResolverParams{ResolveParams: p}
+		err := mapstructure.Decode(p.Args, &frp.Args)
+		if err != nil {
+			return nil, err
+		}
+
+		return resolver.History(frp)
+	}
+}
+
 func _ObjTypeEventToJSONHandler(impl interface{}) graphql1.FieldResolveFn {
 	resolver := impl.(interface {
 		ToJSON(p graphql.ResolveParams) (interface{}, error)
@@ -377,6 +414,22 @@ func _ObjectTypeEventConfigFn() graphql1.ObjectConfig {
 				Name:              "entity",
 				Type:              graphql.OutputType("Entity"),
 			},
+			"history": &graphql1.Field{
+				Args: graphql1.FieldConfigArgument{
+					"from": &graphql1.ArgumentConfig{
+						Description: "self descriptive",
+						Type:        graphql1.DateTime,
+					},
+					"to": &graphql1.ArgumentConfig{
+						Description: "self descriptive",
+						Type:        graphql1.DateTime,
+					},
+				},
+				DeprecationReason: "",
+				Description:       "history returns the status changes of the event, from the oldest to the most\nrecent, that occurred within the given time range. The range is left open\nwhen from or to are omitted.",
+				Name:              "history",
+				Type:              graphql1.NewNonNull(graphql1.NewList(graphql1.NewNonNull(graphql.OutputType("EventHistoryEntry")))),
+			},
 			"hooks": &graphql1.Field{
 				Args:              graphql1.FieldConfigArgument{},
 				DeprecationReason: "",
@@ -492,6 +545,7 @@ var _ObjectTypeEventDesc = graphql.ObjectDesc{
 	FieldHandlers: map[string]graphql.FieldHandler{
 		"check":         _ObjTypeEventCheckHandler,
 		"entity":        _ObjTypeEventEntityHandler,
+		"history":       _ObjTypeEventHistoryHandler,
 		"hooks":         _ObjTypeEventHooksHandler,
 		"id":            _ObjTypeEventIDHandler,
 		"isIncident":    _ObjTypeEventIsIncidentHandler,
@@ -508,6 +562,206 @@ var _ObjectTypeEventDesc = graphql.ObjectDesc{
 	},
 }
 
+// EventHistoryEntryFieldResolvers represents a collection of methods whose products represent the
+// response values of the 'EventHistoryEntry' type.
+type EventHistoryEntryFieldResolvers interface {
+	// Status implements response to request for 'status' field.
+	Status(p graphql.ResolveParams) (interface{}, error)
+
+	// State implements response to request for 'state' field.
+	State(p graphql.ResolveParams) (string, error)
+
+	// Output implements response to request for 'output' field.
+	Output(p graphql.ResolveParams) (string, error)
+
+	// Executed implements response to request for 'executed' field.
+	Executed(p graphql.ResolveParams) (time.Time, error)
+
+	// Timestamp implements response to request for 'timestamp' field.
+	Timestamp(p graphql.ResolveParams) (time.Time, error)
+}
+
+// EventHistoryEntryAliases implements all methods on EventHistoryEntryFieldResolvers interface by using reflection to
+// match name of field to a field on the given value. Intent is reduce friction
+// of writing new resolvers by removing all the instances where you would simply
+// have the resolvers method return a field.
+type EventHistoryEntryAliases struct{}
+
+// Status implements response to request for 'status' field.
+func (_ EventHistoryEntryAliases) Status(p graphql.ResolveParams) (interface{}, error) {
+	val, err := graphql.DefaultResolver(p.Source, p.Info.FieldName)
+	return val, err
+}
+
+// State implements response to request for 'state' field.
+func (_ EventHistoryEntryAliases) State(p graphql.ResolveParams) (string, error) {
+	val, err := graphql.DefaultResolver(p.Source, p.Info.FieldName)
+	ret, ok := val.(string)
+	if err != nil {
+		return ret, err
+	}
+	if !ok {
+		return ret, errors.New("unable to coerce value for field 'state'")
+	}
+	return ret, err
+}
+
+// Output implements response to request for 'output' field.
+func (_ EventHistoryEntryAliases) Output(p graphql.ResolveParams) (string, error) {
+	val, err := graphql.DefaultResolver(p.Source, p.Info.FieldName)
+	ret, ok := val.(string)
+	if err != nil {
+		return ret, err
+	}
+	if !ok {
+		return ret, errors.New("unable to coerce value for field 'output'")
+	}
+	return ret, err
+}
+
+// Executed implements response to request for 'executed' field.
+func (_ EventHistoryEntryAliases) Executed(p graphql.ResolveParams) (time.Time, error) {
+	val, err := graphql.DefaultResolver(p.Source, p.Info.FieldName)
+	ret, ok := val.(time.Time)
+	if err != nil {
+		return ret, err
+	}
+	if !ok {
+		return ret, errors.New("unable to coerce value for field 'executed'")
+	}
+	return ret, err
+}
+
+// Timestamp implements response to request for 'timestamp' field.
+func (_ EventHistoryEntryAliases) Timestamp(p graphql.ResolveParams) (time.Time, error) {
+	val, err := graphql.DefaultResolver(p.Source, p.Info.FieldName)
+	ret, ok := val.(time.Time)
+	if err != nil {
+		return ret, err
+	}
+	if !ok {
+		return ret, errors.New("unable to coerce value for field 'timestamp'")
+	}
+	return ret, err
+}
+
+// EventHistoryEntryType EventHistoryEntry is a status change of an event.
+var EventHistoryEntryType = graphql.NewType("EventHistoryEntry", graphql.ObjectKind)
+
+// RegisterEventHistoryEntry registers EventHistoryEntry object type with given service.
+func RegisterEventHistoryEntry(svc *graphql.Service, impl EventHistoryEntryFieldResolvers) {
+	svc.RegisterObject(_ObjectTypeEventHistoryEntryDesc, impl)
+}
+func _ObjTypeEventHistoryEntryStatusHandler(impl interface{}) graphql1.FieldResolveFn {
+	resolver := impl.(interface {
+		Status(p graphql.ResolveParams) (interface{}, error)
+	})
+	return func(frp graphql1.ResolveParams) (interface{}, error) {
+		return resolver.Status(frp)
+	}
+}
+
+func _ObjTypeEventHistoryEntryStateHandler(impl interface{}) graphql1.FieldResolveFn {
+	resolver := impl.(interface {
+		State(p graphql.ResolveParams) (string, error)
+	})
+	return func(frp graphql1.ResolveParams) (interface{}, error) {
+		return resolver.State(frp)
+	}
+}
+
+func _ObjTypeEventHistoryEntryOutputHandler(impl interface{}) graphql1.FieldResolveFn {
+	resolver := impl.(interface {
+		Output(p graphql.ResolveParams) (string, error)
+	})
+	return func(frp graphql1.ResolveParams) (interface{}, error) {
+		return resolver.Output(frp)
+	}
+}
+
+func _ObjTypeEventHistoryEntryExecutedHandler(impl interface{}) graphql1.FieldResolveFn {
+	resolver := impl.(interface {
+		Executed(p graphql.ResolveParams) (time.Time, error)
+	})
+	return func(frp graphql1.ResolveParams) (interface{}, error) {
+		return resolver.Executed(frp)
+	}
+}
+
+func _ObjTypeEventHistoryEntryTimestampHandler(impl interface{}) graphql1.FieldResolveFn {
+	resolver := impl.(interface {
+		Timestamp(p graphql.ResolveParams) (time.Time, error)
+	})
+	return func(frp graphql1.ResolveParams) (interface{}, error) {
+		return resolver.Timestamp(frp)
+	}
+}
+
+func _ObjectTypeEventHistoryEntryConfigFn() graphql1.ObjectConfig {
+	return graphql1.ObjectConfig{
+		Description: "EventHistoryEntry is a status change of an event.",
+		Fields: graphql1.Fields{
+			"executed": &graphql1.Field{
+				Args:              graphql1.FieldConfigArgument{},
+				DeprecationReason: "",
+				Description:       "Executed describes the time in which the check request was executed.",
+				Name:              "executed",
+				Type:              graphql1.NewNonNull(graphql1.DateTime),
+			},
+			"output": &graphql1.Field{
+				Args:              graphql1.FieldConfigArgument{},
+				DeprecationReason: "",
+				Description:       "Output is the check output of the event.",
+				Name:              "output",
+				Type:              graphql1.NewNonNull(graphql1.String),
+			},
+			"state": &graphql1.Field{
+				Args:              graphql1.FieldConfigArgument{},
+				DeprecationReason: "",
+				Description:       "State is the check state of the event, e.g. passing or failing.",
+				Name:              "state",
+				Type:              graphql1.NewNonNull(graphql1.String),
+			},
+			"status": &graphql1.Field{
+				Args:              graphql1.FieldConfigArgument{},
+				DeprecationReason: "",
+				Description:       "Status is the check status the event changed to.",
+				Name:              "status",
+				Type:              graphql1.NewNonNull(graphql.OutputType("Uint")),
+			},
+			"timestamp": &graphql1.Field{
+				Args:              graphql1.FieldConfigArgument{},
+				DeprecationReason: "",
+				Description:       "Timestamp is the time of the event.",
+				Name:              "timestamp",
+				Type:              graphql1.NewNonNull(graphql1.DateTime),
+			},
+		},
+		Interfaces: []*graphql1.Interface{},
+		IsTypeOf: func(_ graphql1.IsTypeOfParams) bool {
+			// NOTE:
+			// Panic by default. Intent is that when Service is invoked, values of
+			// these fields are updated with instantiated resolvers. If these
+			// defaults are called it is most certainly programmer err.
+			// If you're see this comment then: 'Whoops! Sorry, my bad.'
+			panic("Unimplemented; see EventHistoryEntryFieldResolvers.")
+		},
+		Name: "EventHistoryEntry",
+	}
+}
+
+// describe EventHistoryEntry's configuration; kept private to avoid unintentional tampering of configuration at runtime.
+var _ObjectTypeEventHistoryEntryDesc = graphql.ObjectDesc{
+	Config: _ObjectTypeEventHistoryEntryConfigFn,
+	FieldHandlers: map[string]graphql.FieldHandler{
+		"executed":  _ObjTypeEventHistoryEntryExecutedHandler,
+		"output":    _ObjTypeEventHistoryEntryOutputHandler,
+		"state":     _ObjTypeEventHistoryEntryStateHandler,
+		"status":    _ObjTypeEventHistoryEntryStatusHandler,
+		"timestamp": _ObjTypeEventHistoryEntryTimestampHandler,
+	},
+}
+
 // EventConnectionFieldResolvers represents a collection of methods whose products represent the
 // response values of the 'EventConnection' type.
 type EventConnectionFieldResolvers interface {
@@ -73,13 +73,14 @@ type NamespaceEntitiesFieldResolverArgs struct {
 	Filter  string          // Filter - DEPRECATED: Please use the filters argument instead.
 	Filters []string        /*
 	Filters reduces the set using given arbitrary expression[s]; expressions
-	take on the form KEY: VALUE. The accepted key(s) are: subscription &
-	class.
+	take on the form KEY: VALUE. The accepted key(s) are: subscription,
+	class & process.
 
 	Eg.
 
 	subscription:unix
 	class:proxy
+	process:nginx
 	*/
 }
 
@@ -485,7 +486,7 @@ func _ObjectTypeNamespaceConfigFn() graphql1.ObjectConfig {
 					},
 					"filters": &graphql1.ArgumentConfig{
 						DefaultValue: []interface{}{},
-						Description:  "Filters reduces the set using given arbitrary expression[s]; expressions\ntake on the form KEY: VALUE. The accepted key(s) are: subscription &\nclass.\n\nEg.\n\nsubscription:unix\nclass:proxy",
+						Description:  "Filters reduces the set using given arbitrary expression[s]; expressions\ntake on the form KEY: VALUE. The accepted key(s) are: subscription,\nclass & process.\n\nEg.\n\nsubscription:unix\nclass:proxy\nprocess:nginx",
 						Type:         graphql1.NewList(graphql1.NewNonNull(graphql1.String)),
 					},
 					"limit": &graphql1.ArgumentConfig{
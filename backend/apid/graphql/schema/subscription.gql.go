@@ -0,0 +1,394 @@
+// Code generated by scripts/gengraphql.go. DO NOT EDIT.
+
+package schema
+
+import (
+	errors "errors"
+	graphql1 "github.com/graphql-go/graphql"
+	mapstructure "github.com/mitchellh/mapstructure"
+	graphql "github.com/sensu/sensu-go/graphql"
+)
+
+// SubscriptionEventUpdatedFieldResolverArgs contains arguments provided to eventUpdated when selected
+type SubscriptionEventUpdatedFieldResolverArgs struct {
+	Namespace string // Namespace - self descriptive
+	Entity    string // Entity - self descriptive
+	Check     string // Check - self descriptive
+}
+
+// SubscriptionEventUpdatedFieldResolverParams contains contextual info to resolve eventUpdated field
+type SubscriptionEventUpdatedFieldResolverParams struct {
+	graphql.ResolveParams
+	Args SubscriptionEventUpdatedFieldResolverArgs
+}
+
+// SubscriptionEntityUpdatedFieldResolverArgs contains arguments provided to entityUpdated when selected
+type SubscriptionEntityUpdatedFieldResolverArgs struct {
+	Namespace string // Namespace - self descriptive
+}
+
+// SubscriptionEntityUpdatedFieldResolverParams contains contextual info to resolve entityUpdated field
+type SubscriptionEntityUpdatedFieldResolverParams struct {
+	graphql.ResolveParams
+	Args SubscriptionEntityUpdatedFieldResolverArgs
+}
+
+// SubscriptionKeepaliveChangedFieldResolverArgs contains arguments provided to keepaliveChanged when selected
+type SubscriptionKeepaliveChangedFieldResolverArgs struct {
+	Namespace string // Namespace - self descriptive
+}
+
+// SubscriptionKeepaliveChangedFieldResolverParams contains contextual info to resolve keepaliveChanged field
+type SubscriptionKeepaliveChangedFieldResolverParams struct {
+	graphql.ResolveParams
+	Args SubscriptionKeepaliveChangedFieldResolverArgs
+}
+
+// SubscriptionFieldResolvers represents a collection of methods whose products represent the
+// response values of the 'Subscription' type.
+type SubscriptionFieldResolvers interface {
+	// EventUpdated implements response to request for 'eventUpdated' field.
+	EventUpdated(p SubscriptionEventUpdatedFieldResolverParams) (interface{}, error)
+
+	// EntityUpdated implements response to request for 'entityUpdated' field.
+	EntityUpdated(p SubscriptionEntityUpdatedFieldResolverParams) (interface{}, error)
+
+	// KeepaliveChanged implements response to request for 'keepaliveChanged' field.
+	KeepaliveChanged(p SubscriptionKeepaliveChangedFieldResolverParams) (interface{}, error)
+}
+
+// SubscriptionAliases implements all methods on SubscriptionFieldResolvers interface by using reflection to
+// match name of field to a field on the given value. Intent is reduce friction
+// of writing new resolvers by removing all the instances where you would simply
+// have the resolvers method return a field.
+type SubscriptionAliases struct{}
+
+// EventUpdated implements response to request for 'eventUpdated' field.
+func (_ SubscriptionAliases) EventUpdated(p SubscriptionEventUpdatedFieldResolverParams) (interface{}, error) {
+	val, err := graphql.DefaultResolver(p.Source, p.Info.FieldName)
+	return val, err
+}
+
+// EntityUpdated implements response to request for 'entityUpdated' field.
+func (_ SubscriptionAliases) EntityUpdated(p SubscriptionEntityUpdatedFieldResolverParams) (interface{}, error) {
+	val, err := graphql.DefaultResolver(p.Source, p.Info.FieldName)
+	return val, err
+}
+
+// KeepaliveChanged implements response to request for 'keepaliveChanged' field.
+func (_ SubscriptionAliases) KeepaliveChanged(p SubscriptionKeepaliveChangedFieldResolverParams) (interface{}, error) {
+	val, err := graphql.DefaultResolver(p.Source, p.Info.FieldName)
+	return val, err
+}
+
+/*
+SubscriptionType The root query for implementing GraphQL subscriptions. Subscriptions are served
+over WebSocket, using the graphql-transport-ws protocol.
+*/
+var SubscriptionType = graphql.NewType("Subscription", graphql.ObjectKind)
+
+// RegisterSubscription registers Subscription object type with given service.
+func RegisterSubscription(svc *graphql.Service, impl SubscriptionFieldResolvers) {
+	svc.RegisterObject(_ObjectTypeSubscriptionDesc, impl)
+}
+func _ObjTypeSubscriptionEventUpdatedHandler(impl interface{}) graphql1.FieldResolveFn {
+	resolver := impl.(interface {
+		EventUpdated(p SubscriptionEventUpdatedFieldResolverParams) (interface{}, error)
+	})
+	return func(p graphql1.ResolveParams) (interface{}, error) {
+		frp := SubscriptionEventUpdatedFieldResolverParams{ResolveParams: p}
+		err := mapstructure.Decode(p.Args, &frp.Args)
+		if err != nil {
+			return nil, err
+		}
+
+		return resolver.EventUpdated(frp)
+	}
+}
+
+func _ObjTypeSubscriptionEntityUpdatedHandler(impl interface{}) graphql1.FieldResolveFn {
+	resolver := impl.(interface {
+		EntityUpdated(p SubscriptionEntityUpdatedFieldResolverParams) (interface{}, error)
+	})
+	return func(p graphql1.ResolveParams) (interface{}, error) {
+		frp := SubscriptionEntityUpdatedFieldResolverParams{ResolveParams: p}
+		err := mapstructure.Decode(p.Args, &frp.Args)
+		if err != nil {
+			return nil, err
+		}
+
+		return resolver.EntityUpdated(frp)
+	}
+}
+
+func _ObjTypeSubscriptionKeepaliveChangedHandler(impl interface{}) graphql1.FieldResolveFn {
+	resolver := impl.(interface {
+		KeepaliveChanged(p SubscriptionKeepaliveChangedFieldResolverParams) (interface{}, error)
+	})
+	return func(p graphql1.ResolveParams) (interface{}, error) {
+		frp := SubscriptionKeepaliveChangedFieldResolverParams{ResolveParams: p}
+		err := mapstructure.Decode(p.Args, &frp.Args)
+		if err != nil {
+			return nil, err
+		}
+
+		return resolver.KeepaliveChanged(frp)
+	}
+}
+
+func _ObjectTypeSubscriptionConfigFn() graphql1.ObjectConfig {
+	return graphql1.ObjectConfig{
+		Description: "The root query for implementing GraphQL subscriptions. Subscriptions are served\nover WebSocket, using the graphql-transport-ws protocol.",
+		Fields: graphql1.Fields{
+			"entityUpdated": &graphql1.Field{
+				Args: graphql1.FieldConfigArgument{"namespace": &graphql1.ArgumentConfig{
+					Description: "self descriptive",
+					Type:        graphql1.NewNonNull(graphql1.String),
+				}},
+				DeprecationReason: "",
+				Description:       "Receives the entities of the given namespace each time they are created,\nupdated or deleted.",
+				Name:              "entityUpdated",
+				Type:              graphql1.NewNonNull(graphql.OutputType("EntityUpdate")),
+			},
+			"eventUpdated": &graphql1.Field{
+				Args: graphql1.FieldConfigArgument{
+					"check": &graphql1.ArgumentConfig{
+						Description: "self descriptive",
+						Type:        graphql1.String,
+					},
+					"entity": &graphql1.ArgumentConfig{
+						Description: "self descriptive",
+						Type:        graphql1.String,
+					},
+					"namespace": &graphql1.ArgumentConfig{
+						Description: "self descriptive",
+						Type:        graphql1.NewNonNull(graphql1.String),
+					},
+				},
+				DeprecationReason: "",
+				Description:       "Receives the events of the given namespace each time they are updated,\noptionally limited to the given entity and check.",
+				Name:              "eventUpdated",
+				Type:              graphql1.NewNonNull(graphql.OutputType("Event")),
+			},
+			"keepaliveChanged": &graphql1.Field{
+				Args: graphql1.FieldConfigArgument{"namespace": &graphql1.ArgumentConfig{
+					Description: "self descriptive",
+					Type:        graphql1.NewNonNull(graphql1.String),
+				}},
+				DeprecationReason: "",
+				Description:       "Receives the keepalive events of the given namespace each time their status\nchanges.",
+				Name:              "keepaliveChanged",
+				Type:              graphql1.NewNonNull(graphql.OutputType("Event")),
+			},
+		},
+		Interfaces: []*graphql1.Interface{},
+		IsTypeOf: func(_ graphql1.IsTypeOfParams) bool {
+			// NOTE:
+			// Panic by default. Intent is that when Service is invoked, values of
+			// these fields are updated with instantiated resolvers. If these
+			// defaults are called it is most certainly programmer err.
+			// If you're see this comment then: 'Whoops! Sorry, my bad.'
+			panic("Unimplemented; see SubscriptionFieldResolvers.")
+		},
+		Name: "Subscription",
+	}
+}
+
+// describe Subscription's configuration; kept private to avoid unintentional tampering of configuration at runtime.
+var _ObjectTypeSubscriptionDesc = graphql.ObjectDesc{
+	Config: _ObjectTypeSubscriptionConfigFn,
+	FieldHandlers: map[string]graphql.FieldHandler{
+		"entityUpdated":    _ObjTypeSubscriptionEntityUpdatedHandler,
+		"eventUpdated":     _ObjTypeSubscriptionEventUpdatedHandler,
+		"keepaliveChanged": _ObjTypeSubscriptionKeepaliveChangedHandler,
+	},
+}
+
+// EntityUpdateFieldResolvers represents a collection of methods whose products represent the
+// response values of the 'EntityUpdate' type.
+type EntityUpdateFieldResolvers interface {
+	// Action implements response to request for 'action' field.
+	Action(p graphql.ResolveParams) (WatchAction, error)
+
+	// Name implements response to request for 'name' field.
+	Name(p graphql.ResolveParams) (string, error)
+
+	// Entity implements response to request for 'entity' field.
+	Entity(p graphql.ResolveParams) (interface{}, error)
+}
+
+// EntityUpdateAliases implements all methods on EntityUpdateFieldResolvers interface by using reflection to
+// match name of field to a field on the given value. Intent is reduce friction
+// of writing new resolvers by removing all the instances where you would simply
+// have the resolvers method return a field.
+type EntityUpdateAliases struct{}
+
+// Action implements response to request for 'action' field.
+func (_ EntityUpdateAliases) Action(p graphql.ResolveParams) (WatchAction, error) {
+	val, err := graphql.DefaultResolver(p.Source, p.Info.FieldName)
+	ret, ok := WatchAction(val.(string)), true
+	if err != nil {
+		return ret, err
+	}
+	if !ok {
+		return ret, errors.New("unable to coerce value for field 'action'")
+	}
+	return ret, err
+}
+
+// Name implements response to request for 'name' field.
+func (_ EntityUpdateAliases) Name(p graphql.ResolveParams) (string, error) {
+	val, err := graphql.DefaultResolver(p.Source, p.Info.FieldName)
+	ret, ok := val.(string)
+	if err != nil {
+		return ret, err
+	}
+	if !ok {
+		return ret, errors.New("unable to coerce value for field 'name'")
+	}
+	return ret, err
+}
+
+// Entity implements response to request for 'entity' field.
+func (_ EntityUpdateAliases) Entity(p graphql.ResolveParams) (interface{}, error) {
+	val, err := graphql.DefaultResolver(p.Source, p.Info.FieldName)
+	return val, err
+}
+
+// EntityUpdateType EntityUpdate describes a change made to an entity.
+var EntityUpdateType = graphql.NewType("EntityUpdate", graphql.ObjectKind)
+
+// RegisterEntityUpdate registers EntityUpdate object type with given service.
+func RegisterEntityUpdate(svc *graphql.Service, impl EntityUpdateFieldResolvers) {
+	svc.RegisterObject(_ObjectTypeEntityUpdateDesc, impl)
+}
+func _ObjTypeEntityUpdateActionHandler(impl interface{}) graphql1.FieldResolveFn {
+	resolver := impl.(interface {
+		Action(p graphql.ResolveParams) (WatchAction, error)
+	})
+	return func(frp graphql1.ResolveParams) (interface{}, error) {
+
+		val, err := resolver.Action(frp)
+		return string(val), err
+	}
+}
+
+func _ObjTypeEntityUpdateNameHandler(impl interface{}) graphql1.FieldResolveFn {
+	resolver := impl.(interface {
+		Name(p graphql.ResolveParams) (string, error)
+	})
+	return func(frp graphql1.ResolveParams) (interface{}, error) {
+		return resolver.Name(frp)
+	}
+}
+
+func _ObjTypeEntityUpdateEntityHandler(impl interface{}) graphql1.FieldResolveFn {
+	resolver := impl.(interface {
+		Entity(p graphql.ResolveParams) (interface{}, error)
+	})
+	return func(frp graphql1.ResolveParams) (interface{}, error) {
+		return resolver.Entity(frp)
+	}
+}
+
+func _ObjectTypeEntityUpdateConfigFn() graphql1.ObjectConfig {
+	return graphql1.ObjectConfig{
+		Description: "EntityUpdate describes a change made to an entity.",
+		Fields: graphql1.Fields{
+			"action": &graphql1.Field{
+				Args:              graphql1.FieldConfigArgument{},
+				DeprecationReason: "",
+				Description:       "The kind of change made to the entity.",
+				Name:              "action",
+				Type:              graphql1.NewNonNull(graphql.OutputType("WatchAction")),
+			},
+			"entity": &graphql1.Field{
+				Args:              graphql1.FieldConfigArgument{},
+				DeprecationReason: "",
+				Description:       "The entity; null if the entity was deleted.",
+				Name:              "entity",
+				Type:              graphql.OutputType("Entity"),
+			},
+			"name": &graphql1.Field{
+				Args:              graphql1.FieldConfigArgument{},
+				DeprecationReason: "",
+				Description:       "The name of the entity.",
+				Name:              "name",
+				Type:              graphql1.NewNonNull(graphql1.String),
+			},
+		},
+		Interfaces: []*graphql1.Interface{},
+		IsTypeOf: func(_ graphql1.IsTypeOfParams) bool {
+			// NOTE:
+			// Panic by default. Intent is that when Service is invoked, values of
+			// these fields are updated with instantiated resolvers. If these
+			// defaults are called it is most certainly programmer err.
+			// If you're see this comment then: 'Whoops! Sorry, my bad.'
+			panic("Unimplemented; see EntityUpdateFieldResolvers.")
+		},
+		Name: "EntityUpdate",
+	}
+}
+
+// describe EntityUpdate's configuration; kept private to avoid unintentional tampering of configuration at runtime.
+var _ObjectTypeEntityUpdateDesc = graphql.ObjectDesc{
+	Config: _ObjectTypeEntityUpdateConfigFn,
+	FieldHandlers: map[string]graphql.FieldHandler{
+		"action": _ObjTypeEntityUpdateActionHandler,
+		"entity": _ObjTypeEntityUpdateEntityHandler,
+		"name":   _ObjTypeEntityUpdateNameHandler,
+	},
+}
+
+// WatchAction describes the kind of change made to a resource.
+type WatchAction string
+
+// WatchActions holds enum values
+var WatchActions = _EnumTypeWatchActionValues{
+	CREATED: "CREATED",
+	DELETED: "DELETED",
+	UPDATED: "UPDATED",
+}
+
+// WatchActionType WatchAction describes the kind of change made to a resource.
+var WatchActionType = graphql.NewType("WatchAction", graphql.EnumKind)
+
+// RegisterWatchAction registers WatchAction object type with given service.
+func RegisterWatchAction(svc *graphql.Service) {
+	svc.RegisterEnum(_EnumTypeWatchActionDesc)
+}
+func _EnumTypeWatchActionConfigFn() graphql1.EnumConfig {
+	return graphql1.EnumConfig{
+		Description: "WatchAction describes the kind of change made to a resource.",
+		Name:        "WatchAction",
+		Values: graphql1.EnumValueConfigMap{
+			"CREATED": &graphql1.EnumValueConfig{
+				DeprecationReason: "",
+				Description:       "self descriptive",
+				Value:             "CREATED",
+			},
+			"DELETED": &graphql1.EnumValueConfig{
+				DeprecationReason: "",
+				Description:       "self descriptive",
+				Value:             "DELETED",
+			},
+			"UPDATED": &graphql1.EnumValueConfig{
+				DeprecationReason: "",
+				Description:       "self descriptive",
+				Value:             "UPDATED",
+			},
+		},
+	}
+}
+
+// describe WatchAction's configuration; kept private to avoid unintentional tampering of configuration at runtime.
+var _EnumTypeWatchActionDesc = graphql.EnumDesc{Config: _EnumTypeWatchActionConfigFn}
+
+type _EnumTypeWatchActionValues struct {
+	// CREATED - self descriptive
+	CREATED WatchAction
+	// UPDATED - self descriptive
+	UPDATED WatchAction
+	// DELETED - self descriptive
+	DELETED WatchAction
+}
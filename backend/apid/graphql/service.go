@@ -32,6 +32,7 @@ type ServiceConfig struct {
 	HealthController   EtcdHealthController
 	MutatorClient      MutatorClient
 	SilencedClient     SilencedClient
+	SubscriptionClient SubscriptionClient
 	NamespaceClient    NamespaceClient
 	HookClient         HookClient
 	UserClient         UserClient
@@ -83,7 +84,7 @@ func NewService(cfg ServiceConfig) (*Service, error) {
 	schema.RegisterCoreV3EntityStateExtensionOverrides(svc, &corev3EntityStateExtImpl{client: cfg.GenericClient, entityClient: cfg.EntityClient})
 	schema.RegisterNamespace(svc, &namespaceImpl{client: cfg.NamespaceClient, entityClient: cfg.EntityClient, eventClient: cfg.EventClient, serviceConfig: &cfg})
 	schema.RegisterErrCode(svc)
-	schema.RegisterEvent(svc, &eventImpl{})
+	schema.RegisterEvent(svc, &eventImpl{client: cfg.EventClient})
 	schema.RegisterEventsListOrder(svc)
 	schema.RegisterJSON(svc, jsonImpl{})
 	schema.RegisterKVPairString(svc, &schema.KVPairStringAliases{})
@@ -103,6 +104,7 @@ func NewService(cfg ServiceConfig) (*Service, error) {
 	schema.RegisterSilenced(svc, &silencedImpl{client: cfg.CheckClient})
 	schema.RegisterSilencedConnection(svc, &schema.SilencedConnectionAliases{})
 	schema.RegisterSilencesListOrder(svc)
+	schema.RegisterSubscription(svc, &subscriptionImpl{client: cfg.SubscriptionClient})
 	schema.RegisterSuggestionOrder(svc)
 	schema.RegisterSuggestionResultSet(svc, &schema.SuggestionResultSetAliases{})
 	schema.RegisterUint(svc, unsignedIntegerImpl{})
@@ -126,8 +128,11 @@ func NewService(cfg ServiceConfig) (*Service, error) {
 	schema.RegisterSystem(svc, &systemImpl{})
 
 	// Register event types
-	schema.RegisterEvent(svc, &eventImpl{})
+	schema.RegisterEvent(svc, &eventImpl{client: cfg.EventClient})
 	schema.RegisterEventConnection(svc, &schema.EventConnectionAliases{})
+	schema.RegisterEventHistoryEntry(svc, &eventHistoryEntryImpl{})
+	schema.RegisterEntityUpdate(svc, &entityUpdateImpl{})
+	schema.RegisterWatchAction(svc)
 
 	// Register event filter types
 	schema.RegisterEventFilter(svc, &eventFilterImpl{})
@@ -239,3 +244,13 @@ func (svc *Service) Do(ctx context.Context, p graphql.QueryParams) *graphql.Resu
 	// Execute query inside context
 	return svc.Target.Do(qryCtx, p)
 }
+
+// Subscribe executes given subscription string and variables. A result is
+// sent on the returned channel for each update, until ctx is cancelled.
+func (svc *Service) Subscribe(ctx context.Context, p graphql.QueryParams) chan *graphql.Result {
+	// Instantiate loaders and lift them into the context
+	qryCtx := contextWithLoaders(ctx, *svc.Config)
+
+	// Execute subscription inside context
+	return svc.Target.Subscribe(qryCtx, p)
+}
@@ -0,0 +1,149 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sensu/sensu-go/backend/api"
+	"github.com/sensu/sensu-go/backend/apid/graphql/schema"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	"github.com/sensu/sensu-go/graphql"
+)
+
+var _ schema.SubscriptionFieldResolvers = (*subscriptionImpl)(nil)
+var _ schema.EntityUpdateFieldResolvers = (*entityUpdateImpl)(nil)
+var _ graphql.FieldSubscriber = (*subscriptionImpl)(nil)
+
+//
+// Implement SubscriptionFieldResolvers
+//
+
+type subscriptionImpl struct {
+	client SubscriptionClient
+}
+
+// SubscribeFields implements graphql.FieldSubscriber.
+func (r *subscriptionImpl) SubscribeFields() map[string]graphql.SubscribeFn {
+	return map[string]graphql.SubscribeFn{
+		"eventUpdated":     r.subscribeEventUpdated,
+		"entityUpdated":    r.subscribeEntityUpdated,
+		"keepaliveChanged": r.subscribeKeepaliveChanged,
+	}
+}
+
+func (r *subscriptionImpl) subscribeEventUpdated(p graphql.ResolveParams) (chan interface{}, error) {
+	if r.client == nil {
+		return nil, errors.New("subscriptions are not supported")
+	}
+	namespace, _ := p.Args["namespace"].(string)
+	entity, _ := p.Args["entity"].(string)
+	check, _ := p.Args["check"].(string)
+	ctx := contextWithNamespace(p.Context, namespace)
+	events, err := r.client.SubscribeEvents(ctx, entity, check)
+	if err != nil {
+		return nil, err
+	}
+	return forward(ctx, events), nil
+}
+
+func (r *subscriptionImpl) subscribeEntityUpdated(p graphql.ResolveParams) (chan interface{}, error) {
+	if r.client == nil {
+		return nil, errors.New("subscriptions are not supported")
+	}
+	namespace, _ := p.Args["namespace"].(string)
+	ctx := contextWithNamespace(p.Context, namespace)
+	updates, err := r.client.SubscribeEntities(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return forward(ctx, updates), nil
+}
+
+func (r *subscriptionImpl) subscribeKeepaliveChanged(p graphql.ResolveParams) (chan interface{}, error) {
+	if r.client == nil {
+		return nil, errors.New("subscriptions are not supported")
+	}
+	namespace, _ := p.Args["namespace"].(string)
+	ctx := contextWithNamespace(p.Context, namespace)
+	events, err := r.client.SubscribeKeepalives(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return forward(ctx, events), nil
+}
+
+// EventUpdated implements response to request for 'eventUpdated' field.
+func (r *subscriptionImpl) EventUpdated(p schema.SubscriptionEventUpdatedFieldResolverParams) (interface{}, error) {
+	return p.Source, nil
+}
+
+// EntityUpdated implements response to request for 'entityUpdated' field.
+func (r *subscriptionImpl) EntityUpdated(p schema.SubscriptionEntityUpdatedFieldResolverParams) (interface{}, error) {
+	return p.Source, nil
+}
+
+// KeepaliveChanged implements response to request for 'keepaliveChanged' field.
+func (r *subscriptionImpl) KeepaliveChanged(p schema.SubscriptionKeepaliveChangedFieldResolverParams) (interface{}, error) {
+	return p.Source, nil
+}
+
+// forward copies the values received from in to the channel of payloads
+// expected by graphql-go, until in is closed or ctx is cancelled.
+func forward[T any](ctx context.Context, in <-chan T) chan interface{} {
+	out := make(chan interface{})
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-in:
+				if !ok {
+					return
+				}
+				select {
+				case out <- v:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
+
+//
+// Implement EntityUpdateFieldResolvers
+//
+
+type entityUpdateImpl struct {
+	schema.EntityUpdateAliases
+}
+
+// Action implements response to request for 'action' field.
+func (*entityUpdateImpl) Action(p graphql.ResolveParams) (schema.WatchAction, error) {
+	update := p.Source.(api.EntityUpdate)
+	switch update.Action {
+	case storev2.WatchCreate:
+		return schema.WatchActions.CREATED, nil
+	case storev2.WatchDelete:
+		return schema.WatchActions.DELETED, nil
+	default:
+		return schema.WatchActions.UPDATED, nil
+	}
+}
+
+// Name implements response to request for 'name' field.
+func (*entityUpdateImpl) Name(p graphql.ResolveParams) (string, error) {
+	update := p.Source.(api.EntityUpdate)
+	return update.Name, nil
+}
+
+// Entity implements response to request for 'entity' field.
+func (*entityUpdateImpl) Entity(p graphql.ResolveParams) (interface{}, error) {
+	update := p.Source.(api.EntityUpdate)
+	if update.Entity == nil {
+		return nil, nil
+	}
+	return update.Entity, nil
+}
@@ -0,0 +1,111 @@
+package graphql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/api"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	"github.com/sensu/sensu-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func receiveResult(t *testing.T, results chan *graphql.Result) *graphql.Result {
+	t.Helper()
+	select {
+	case result := <-results:
+		require.NotNil(t, result)
+		return result
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for result")
+	}
+	return nil
+}
+
+func TestSubscriptionEventUpdated(t *testing.T) {
+	events := make(chan *corev2.Event, 1)
+	client := new(MockSubscriptionClient)
+	client.On("SubscribeEvents", mock.Anything, "", "check").Return((<-chan *corev2.Event)(events), nil)
+
+	svc, err := NewService(ServiceConfig{SubscriptionClient: client})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	results := svc.Subscribe(ctx, graphql.QueryParams{
+		Query: `subscription { eventUpdated(namespace: "default", check: "check") { check { name } entity { name } } }`,
+	})
+
+	events <- corev2.FixtureEvent("foo", "check")
+	result := receiveResult(t, results)
+	require.Empty(t, result.Errors)
+	assert.Equal(t, map[string]interface{}{
+		"eventUpdated": map[string]interface{}{
+			"check":  map[string]interface{}{"name": "check"},
+			"entity": map[string]interface{}{"name": "foo"},
+		},
+	}, result.Data)
+
+	cancel()
+	for range results {
+	}
+	subCtx := client.Calls[0].Arguments.Get(0).(context.Context)
+	assert.Equal(t, "default", corev2.ContextNamespace(subCtx))
+}
+
+func TestSubscriptionEntityUpdated(t *testing.T) {
+	updates := make(chan api.EntityUpdate, 2)
+	client := new(MockSubscriptionClient)
+	client.On("SubscribeEntities", mock.Anything).Return((<-chan api.EntityUpdate)(updates), nil)
+
+	svc, err := NewService(ServiceConfig{SubscriptionClient: client})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	results := svc.Subscribe(ctx, graphql.QueryParams{
+		Query: `subscription { entityUpdated(namespace: "default") { action name entity { name } } }`,
+	})
+
+	updates <- api.EntityUpdate{Action: storev2.WatchCreate, Name: "foo", Entity: corev2.FixtureEntity("foo")}
+	updates <- api.EntityUpdate{Action: storev2.WatchDelete, Name: "foo"}
+	result := receiveResult(t, results)
+	require.Empty(t, result.Errors)
+	assert.Equal(t, map[string]interface{}{
+		"entityUpdated": map[string]interface{}{
+			"action": "CREATED",
+			"name":   "foo",
+			"entity": map[string]interface{}{"name": "foo"},
+		},
+	}, result.Data)
+	result = receiveResult(t, results)
+	require.Empty(t, result.Errors)
+	assert.Equal(t, map[string]interface{}{
+		"entityUpdated": map[string]interface{}{
+			"action": "DELETED",
+			"name":   "foo",
+			"entity": nil,
+		},
+	}, result.Data)
+
+	cancel()
+	for range results {
+	}
+}
+
+func TestSubscriptionInvalid(t *testing.T) {
+	svc, err := NewService(ServiceConfig{SubscriptionClient: new(MockSubscriptionClient)})
+	require.NoError(t, err)
+
+	results := svc.Subscribe(context.Background(), graphql.QueryParams{
+		Query: `subscription { eventUpdated { check { name } } }`,
+	})
+	result := receiveResult(t, results)
+	assert.NotEmpty(t, result.Errors)
+	for range results {
+	}
+}
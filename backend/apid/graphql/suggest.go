@@ -5,6 +5,7 @@ import (
 
 	corev2 "github.com/sensu/core/v2"
 	corev3 "github.com/sensu/core/v3"
+	"github.com/sensu/sensu-go/backend/apid/filters/fields"
 	"github.com/sensu/sensu-go/backend/apid/graphql/suggest"
 )
 
@@ -28,6 +29,17 @@ func subscriptionsFn(res corev3.Resource) []string {
 	return res.(Subscribable).GetSubscriptions()
 }
 
+func processesFn(res corev3.Resource) []string {
+	processes := res.(*corev2.Entity).System.Processes
+	names := make([]string, 0, len(processes))
+	for _, process := range processes {
+		if process != nil {
+			names = append(names, process.Name)
+		}
+	}
+	return names
+}
+
 func commandFn(res corev3.Resource) []string {
 	return []string{res.(Commandable).GetCommand()}
 }
@@ -94,7 +106,7 @@ func DefaultSuggestSchema() suggest.Register {
 		&suggest.Resource{
 			Group:      "core/v2",
 			Name:       "entity",
-			FilterFunc: corev3.EntityFields,
+			FilterFunc: fields.EntityFields,
 			Fields: []suggest.Field{
 				&suggest.ObjectField{
 					Name: "metadata",
@@ -130,6 +142,10 @@ func DefaultSuggestSchema() suggest.Register {
 								return []string{res.(*corev2.Entity).System.Arch}
 							},
 						},
+						&suggest.CustomField{
+							Name:      "processes",
+							FieldFunc: processesFn,
+						},
 					},
 				},
 				&suggest.CustomField{
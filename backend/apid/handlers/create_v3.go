@@ -39,6 +39,10 @@ func (h Handlers[R, T]) CreateResource(r *http.Request) (HandlerResponse, error)
 		meta.CreatedBy = claims.StandardClaims.Subject
 	}
 
+	if IsDryRun(r) {
+		return h.dryRun(ctx, payload, true)
+	}
+
 	gstore := storev2.Of[R](h.Store)
 
 	if err := gstore.CreateIfNotExists(ctx, payload); err != nil {
@@ -0,0 +1,307 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+	"github.com/sensu/core/v3/types"
+	apitools "github.com/sensu/sensu-api-tools"
+	"github.com/sensu/sensu-go/backend/api"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/backend/authorization"
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	"github.com/sensu/sensu-go/js"
+	utilstrings "github.com/sensu/sensu-go/util/strings"
+)
+
+// DryRunParam is the query parameter that enables the dry-run mode of the
+// create and update handlers, e.g. POST /api/core/v2/namespaces/default/checks?dryRun=true
+const DryRunParam = "dryRun"
+
+const (
+	// DryRunCreate is the operation of a dry run for a resource that does
+	// not exist yet.
+	DryRunCreate = "create"
+
+	// DryRunUpdate is the operation of a dry run for an existing resource.
+	DryRunUpdate = "update"
+)
+
+var (
+	// builtInFilterNames are the names of the core/v2 event filters that are
+	// not stored.
+	builtInFilterNames = []string{"is_incident", "has_metrics", "not_silenced"}
+
+	// builtInMutatorNames are the names of the core/v2 mutators that are not
+	// stored.
+	builtInMutatorNames = []string{"json", "only_check_output"}
+)
+
+// DryRunResult is the response of a dry-run request. The resource was
+// validated but not persisted.
+type DryRunResult struct {
+	// DryRun is always true, to tell dry-run responses apart.
+	DryRun bool `json:"dry_run"`
+
+	// Operation is the operation the request would have performed, either
+	// DryRunCreate or DryRunUpdate, if known.
+	Operation string `json:"operation,omitempty"`
+
+	// References are the resources referenced by the resource.
+	References []DryRunReference `json:"references"`
+
+	// Warnings are the problems that would not prevent the resource from
+	// being persisted, but would likely prevent it from working as intended.
+	Warnings []DryRunWarning `json:"warnings"`
+
+	// Resource is the resource as it would have been persisted, e.g. with
+	// its default values, so that clients can diff it against the live
+	// resource.
+	Resource *types.Wrapper `json:"resource,omitempty"`
+}
+
+// DryRunReference is a resource referenced by the resource of a dry run.
+type DryRunReference struct {
+	Field      string `json:"field"`
+	APIVersion string `json:"api_version"`
+	Type       string `json:"type"`
+	Name       string `json:"name"`
+	Exists     bool   `json:"exists"`
+}
+
+// DryRunWarning is a problem found by a dry run, in the given field of the
+// resource.
+type DryRunWarning struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// IsDryRun returns whether the dry-run mode is requested.
+func IsDryRun(r *http.Request) bool {
+	dryRun, _ := strconv.ParseBool(r.URL.Query().Get(DryRunParam))
+	return dryRun
+}
+
+// UnsupportedDryRun returns the error of the handlers that don't support the
+// dry-run mode.
+func UnsupportedDryRun() error {
+	return actions.NewError(actions.InvalidArgument, fmt.Errorf("the %s parameter is not supported by this endpoint", DryRunParam))
+}
+
+// DryRun validates a resource and checks its references to other resources,
+// without persisting it. The references are read with the permissions of the
+// user of the request, so that a dry run doesn't tell whether the resources
+// the user can't read exist. The store and authorizer may be nil for the
+// resource types that have no references. A resource that is not valid,
+// including its cron schedule and the JavaScript expressions of filters and
+// proxy requests, returns an InvalidArgument error, while the missing
+// references and the syntax errors of the other JavaScript expressions are
+// returned as warnings.
+func DryRun(ctx context.Context, s storev2.Interface, auth authorization.Authorizer, resource corev3.Resource) (*DryRunResult, error) {
+	if err := resource.Validate(); err != nil {
+		return nil, actions.NewError(actions.InvalidArgument, err)
+	}
+	l := &linter{
+		ctx:    ctx,
+		store:  s,
+		auth:   auth,
+		result: &DryRunResult{DryRun: true, References: []DryRunReference{}, Warnings: []DryRunWarning{}},
+	}
+	if meta := resource.GetMetadata(); meta != nil {
+		l.ctx = context.WithValue(ctx, corev2.NamespaceKey, meta.Namespace)
+	}
+	if err := l.lint(resource); err != nil {
+		return nil, actions.NewError(actions.InternalErr, err)
+	}
+	wrapper := types.WrapResource(resource)
+	l.result.Resource = &wrapper
+	return l.result, nil
+}
+
+// dryRun performs the dry run of a create, or of an update when create is
+// false, for the generic handlers.
+func (h Handlers[R, T]) dryRun(ctx context.Context, payload R, create bool) (HandlerResponse, error) {
+	var response HandlerResponse
+	result, err := DryRun(ctx, h.Store, h.Auth, payload)
+	if err != nil {
+		return response, err
+	}
+	meta := payload.GetMetadata()
+	exists, err := storev2.Of[R](h.Store).Exists(ctx, storev2.ID{Namespace: meta.Namespace, Name: meta.Name})
+	if err != nil {
+		return response, actions.NewError(actions.InternalErr, err)
+	}
+	if exists && create {
+		return response, actions.NewErrorf(actions.AlreadyExistsErr)
+	}
+	result.Operation = DryRunCreate
+	if exists {
+		result.Operation = DryRunUpdate
+	}
+	response.DryRun = result
+	return response, nil
+}
+
+// linter finds the problems of a resource for a dry run. The references are
+// looked up in the namespace of the resource, which is set on ctx. err is the
+// first error of the store, after which the remaining references are ignored.
+type linter struct {
+	ctx    context.Context
+	store  storev2.Interface
+	auth   authorization.Authorizer
+	result *DryRunResult
+	err    error
+}
+
+func (l *linter) warn(field, format string, args ...interface{}) {
+	l.result.Warnings = append(l.result.Warnings, DryRunWarning{
+		Field:   field,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// reference records a reference to the resource of the given type and name,
+// and warns if it doesn't exist.
+func (l *linter) reference(field, apiVersion, typename, name string) {
+	if l.err != nil {
+		return
+	}
+	if name == "" {
+		l.warn(field, "missing %s name", typename)
+		return
+	}
+	resolved, err := apitools.Resolve(apiVersion, typename)
+	if err != nil {
+		l.warn(field, "unknown resource type %s.%s: %s", apiVersion, typename, err)
+		return
+	}
+	resource, ok := resolved.(corev3.Resource)
+	if !ok {
+		l.warn(field, "resource type %s.%s can't be referenced", apiVersion, typename)
+		return
+	}
+	client := &api.GenericClient{
+		Kind:       resource,
+		Store:      l.store,
+		Auth:       l.auth,
+		APIGroup:   path.Dir(apiVersion),
+		APIVersion: path.Base(apiVersion),
+	}
+	exists := true
+	if err := client.Get(l.ctx, name, resource); err != nil {
+		if err == authorization.ErrUnauthorized {
+			l.warn(field, "not authorized to get %s %s", typename, name)
+			return
+		}
+		if _, ok := err.(*store.ErrNotFound); !ok {
+			l.err = fmt.Errorf("could not check %s %s: %s", typename, name, err)
+			return
+		}
+		exists = false
+	}
+	l.result.References = append(l.result.References, DryRunReference{
+		Field:      field,
+		APIVersion: apiVersion,
+		Type:       typename,
+		Name:       name,
+		Exists:     exists,
+	})
+	if !exists {
+		l.warn(field, "%s %s not found", typename, name)
+	}
+}
+
+func (l *linter) references(field, apiVersion, typename string, names []string, builtIns []string) {
+	for i, name := range names {
+		if utilstrings.InArray(name, builtIns) {
+			continue
+		}
+		l.reference(fmt.Sprintf("%s[%d]", field, i), apiVersion, typename, name)
+	}
+}
+
+func (l *linter) resourceReferences(field string, refs []*corev2.ResourceReference) {
+	for i, ref := range refs {
+		l.resourceReference(fmt.Sprintf("%s[%d]", field, i), ref)
+	}
+}
+
+func (l *linter) resourceReference(field string, ref *corev2.ResourceReference) {
+	if ref == nil {
+		return
+	}
+	if ref.APIVersion == "core/v2" {
+		if ref.Type == "EventFilter" && utilstrings.InArray(ref.Name, builtInFilterNames) {
+			return
+		}
+		if ref.Type == "Mutator" && utilstrings.InArray(ref.Name, builtInMutatorNames) {
+			return
+		}
+	}
+	l.reference(field, ref.APIVersion, ref.Type, ref.Name)
+}
+
+func (l *linter) secrets(field string, secrets []*corev2.Secret) {
+	for i, secret := range secrets {
+		if secret != nil {
+			l.reference(fmt.Sprintf("%s[%d].secret", field, i), "secrets/v1", "Secret", secret.Secret)
+		}
+	}
+}
+
+func (l *linter) expressions(field string, expressions []string) {
+	if err := js.ParseExpressions(expressions); err != nil {
+		l.warn(field, "%s", err)
+	}
+}
+
+// lint checks the references of the resource types that have any, and the
+// JavaScript expressions that are not already parsed by their validation.
+func (l *linter) lint(resource corev3.Resource) error {
+	switch r := resource.(type) {
+	case *corev2.CheckConfig:
+		l.references("handlers", "core/v2", "Handler", r.Handlers, nil)
+		l.references("output_metric_handlers", "core/v2", "Handler", r.OutputMetricHandlers, nil)
+		l.references("runtime_assets", "core/v2", "Asset", r.RuntimeAssets, nil)
+		for i, hooks := range r.CheckHooks {
+			l.references(fmt.Sprintf("check_hooks[%d].hooks", i), "core/v2", "HookConfig", hooks.Hooks, nil)
+		}
+		l.secrets("secrets", r.Secrets)
+		l.resourceReferences("pipelines", r.Pipelines)
+	case *corev2.Handler:
+		l.references("filters", "core/v2", "EventFilter", r.Filters, builtInFilterNames)
+		if r.Mutator != "" && !utilstrings.InArray(r.Mutator, builtInMutatorNames) {
+			l.reference("mutator", "core/v2", "Mutator", r.Mutator)
+		}
+		l.references("handlers", "core/v2", "Handler", r.Handlers, nil)
+		l.references("runtime_assets", "core/v2", "Asset", r.RuntimeAssets, nil)
+		l.secrets("secrets", r.Secrets)
+	case *corev2.EventFilter:
+		l.references("runtime_assets", "core/v2", "Asset", r.RuntimeAssets, nil)
+	case *corev2.Mutator:
+		if r.Type == corev2.JavascriptMutator {
+			l.expressions("eval", []string{r.Eval})
+		}
+		l.references("runtime_assets", "core/v2", "Asset", r.RuntimeAssets, nil)
+		l.secrets("secrets", r.Secrets)
+	case *corev2.HookConfig:
+		l.references("runtime_assets", "core/v2", "Asset", r.RuntimeAssets, nil)
+	case *corev2.Pipeline:
+		for i, workflow := range r.Workflows {
+			if workflow == nil {
+				continue
+			}
+			field := fmt.Sprintf("workflows[%d]", i)
+			l.resourceReferences(field+".filters", workflow.Filters)
+			l.resourceReference(field+".mutator", workflow.Mutator)
+			l.resourceReference(field+".handler", workflow.Handler)
+		}
+	}
+	return l.err
+}
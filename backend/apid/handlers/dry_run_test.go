@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/backend/authorization"
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// dryRunWrapper is the wrapper of the resources that exist in the store of
+// the dry runs.
+type dryRunWrapper struct{}
+
+func (dryRunWrapper) Unwrap() (corev3.Resource, error) { return nil, nil }
+
+func (dryRunWrapper) UnwrapInto(interface{}) error { return nil }
+
+// dryRunAuth denies the requests for the resources of the given RBAC names.
+type dryRunAuth []string
+
+func (a dryRunAuth) Authorize(ctx context.Context, attrs *authorization.Attributes) (bool, error) {
+	for _, resource := range a {
+		if attrs.Resource == resource {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// newDryRunStore returns a store in which only the resources of the given
+// types exist.
+func newDryRunStore(existing ...string) *mockstore.V2MockStore {
+	s := &mockstore.V2MockStore{}
+	cs := new(mockstore.ConfigStore)
+	s.On("GetConfigStore").Return(cs)
+	for _, typename := range existing {
+		typename := typename
+		matchType := mock.MatchedBy(func(req storev2.ResourceRequest) bool {
+			return req.Type == typename
+		})
+		cs.On("Exists", mock.Anything, matchType).Return(true, nil)
+		cs.On("Get", mock.Anything, matchType).Return(dryRunWrapper{}, nil)
+	}
+	cs.On("Exists", mock.Anything, mock.Anything).Return(false, nil)
+	cs.On("Get", mock.Anything, mock.Anything).Return(nil, &store.ErrNotFound{})
+	return s
+}
+
+func newDryRunHandlers[R storev2.Resource[T], T any](s storev2.Interface) Handlers[R, T] {
+	h := NewHandlers[R](s)
+	h.Auth = dryRunAuth(nil)
+	return h
+}
+
+func newDryRunRequest(t *testing.T, method string, body []byte) *http.Request {
+	t.Helper()
+	r, err := http.NewRequest(method, "/?dryRun=true", bytes.NewReader(body))
+	require.NoError(t, err)
+	ctx := context.WithValue(r.Context(), corev2.ClaimsKey, corev2.FixtureClaims("developer", nil))
+	return r.WithContext(ctx)
+}
+
+func TestDryRunCreate(t *testing.T) {
+	handler := corev2.FixtureHandler("slack")
+	handler.Filters = []string{"is_incident", "missing"}
+	handler.Mutator = "mutator"
+	handler.RuntimeAssets = []string{"asset"}
+
+	// The store mock fails the test if the handler is created
+	h := newDryRunHandlers[*corev2.Handler](newDryRunStore("Mutator"))
+	response, err := h.CreateResource(newDryRunRequest(t, http.MethodPost, marshal(t, handler)))
+	require.NoError(t, err)
+	require.NotNil(t, response.DryRun)
+
+	result := response.DryRun
+	assert.True(t, result.DryRun)
+	assert.Equal(t, DryRunCreate, result.Operation)
+	assert.Equal(t, []DryRunReference{
+		{Field: "filters[1]", APIVersion: "core/v2", Type: "EventFilter", Name: "missing"},
+		{Field: "mutator", APIVersion: "core/v2", Type: "Mutator", Name: "mutator", Exists: true},
+		{Field: "runtime_assets[0]", APIVersion: "core/v2", Type: "Asset", Name: "asset"},
+	}, result.References)
+	assert.Equal(t, []DryRunWarning{
+		{Field: "filters[1]", Message: "EventFilter missing not found"},
+		{Field: "runtime_assets[0]", Message: "Asset asset not found"},
+	}, result.Warnings)
+
+	// The resource is returned as it would have been persisted
+	require.NotNil(t, result.Resource)
+	created := result.Resource.Value.(*corev2.Handler)
+	assert.Equal(t, handler.Filters, created.Filters)
+	assert.Equal(t, "developer", created.CreatedBy)
+}
+
+func TestDryRunCreateAlreadyExists(t *testing.T) {
+	h := newDryRunHandlers[*corev2.Handler](newDryRunStore("Handler"))
+	body := marshal(t, corev2.FixtureHandler("slack"))
+	_, err := h.CreateResource(newDryRunRequest(t, http.MethodPost, body))
+	require.Error(t, err)
+	assert.Equal(t, actions.AlreadyExistsErr, err.(actions.Error).Code)
+}
+
+func TestDryRunUpdate(t *testing.T) {
+	h := newDryRunHandlers[*corev2.Handler](newDryRunStore("Handler"))
+	body := marshal(t, corev2.FixtureHandler("slack"))
+	response, err := h.CreateOrUpdateResource(newDryRunRequest(t, http.MethodPut, body))
+	require.NoError(t, err)
+	require.NotNil(t, response.DryRun)
+	assert.Equal(t, DryRunUpdate, response.DryRun.Operation)
+	assert.Empty(t, response.DryRun.Warnings)
+}
+
+func TestDryRunInvalid(t *testing.T) {
+	t.Run("cron", func(t *testing.T) {
+		check := corev2.FixtureCheckConfig("check")
+		check.Cron = "not a cron"
+
+		h := newDryRunHandlers[*corev2.CheckConfig](newDryRunStore())
+		_, err := h.CreateOrUpdateResource(newDryRunRequest(t, http.MethodPut, marshal(t, check)))
+		require.Error(t, err)
+		assert.Equal(t, actions.InvalidArgument, err.(actions.Error).Code)
+	})
+
+	t.Run("filter expressions", func(t *testing.T) {
+		filter := corev2.FixtureEventFilter("filter")
+		filter.Expressions = []string{"event.check.status == 0", "event.check.status =="}
+
+		h := newDryRunHandlers[*corev2.EventFilter](newDryRunStore())
+		_, err := h.CreateOrUpdateResource(newDryRunRequest(t, http.MethodPut, marshal(t, filter)))
+		require.Error(t, err)
+		assert.Equal(t, actions.InvalidArgument, err.(actions.Error).Code)
+	})
+}
+
+func TestDryRunMutatorEval(t *testing.T) {
+	mutator := corev2.FixtureMutator("mutator")
+	mutator.Command = ""
+	mutator.Type = corev2.JavascriptMutator
+	mutator.Eval = "return event.check.status =="
+
+	h := newDryRunHandlers[*corev2.Mutator](newDryRunStore())
+	response, err := h.CreateOrUpdateResource(newDryRunRequest(t, http.MethodPut, marshal(t, mutator)))
+	require.NoError(t, err)
+	require.NotNil(t, response.DryRun)
+	require.Len(t, response.DryRun.Warnings, 1)
+	assert.Equal(t, "eval", response.DryRun.Warnings[0].Field)
+	assert.Contains(t, response.DryRun.Warnings[0].Message, "syntax error")
+}
+
+func TestDryRunPatchUnsupported(t *testing.T) {
+	h := newDryRunHandlers[*corev2.Handler](newDryRunStore())
+	_, err := h.PatchResource(newDryRunRequest(t, http.MethodPatch, []byte(`{}`)))
+	require.Error(t, err)
+	assert.Equal(t, actions.InvalidArgument, err.(actions.Error).Code)
+}
+
+func TestDryRunUnauthorizedReference(t *testing.T) {
+	handler := corev2.FixtureHandler("slack")
+	handler.Mutator = "mutator"
+
+	// The mutator exists, but the dry run must not tell users who can't read
+	// mutators
+	h := newDryRunHandlers[*corev2.Handler](newDryRunStore("Mutator"))
+	h.Auth = dryRunAuth{"mutators"}
+	response, err := h.CreateOrUpdateResource(newDryRunRequest(t, http.MethodPut, marshal(t, handler)))
+	require.NoError(t, err)
+	require.NotNil(t, response.DryRun)
+	assert.Empty(t, response.DryRun.References)
+	assert.Equal(t, []DryRunWarning{
+		{Field: "mutator", Message: "not authorized to get Mutator mutator"},
+	}, response.DryRun.Warnings)
+}
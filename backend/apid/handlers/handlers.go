@@ -6,17 +6,24 @@ import (
 	"net/url"
 
 	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/authorization"
+	"github.com/sensu/sensu-go/backend/authorization/rbac"
 	storev2 "github.com/sensu/sensu-go/backend/store/v2"
 )
 
 // Handlers represents the HTTP handlers for CRUD operations on resources
 type Handlers[R storev2.Resource[T], T any] struct {
 	Store storev2.Interface
+
+	// Auth authorizes the reads of the resources referenced by the resources
+	// of the dry runs.
+	Auth authorization.Authorizer
 }
 
 func NewHandlers[R storev2.Resource[T], T any](store storev2.Interface) Handlers[R, T] {
 	return Handlers[R, T]{
 		Store: store,
+		Auth:  &rbac.Authorizer{Store: store},
 	}
 }
 
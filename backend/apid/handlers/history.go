@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/backend/authentication/jwt"
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+const (
+	// RevisionParam is the query parameter of the revision to roll back to.
+	RevisionParam = "revision"
+
+	// DiffFromParam and DiffToParam are the query parameters of the
+	// revisions compared by a diff. The latest revision is used if
+	// DiffToParam is missing.
+	DiffFromParam = "from"
+	DiffToParam   = "to"
+)
+
+// RevisionDiff is the comparison of two revisions of a resource. Diff is the
+// JSON merge patch that turns the resource of From into the resource of To.
+type RevisionDiff struct {
+	From *storev2.Revision `json:"from"`
+	To   *storev2.Revision `json:"to"`
+	Diff json.RawMessage   `json:"diff"`
+}
+
+// revisionRequest returns the resource request of the resource named in the
+// request URL.
+func (h Handlers[R, T]) revisionRequest(r *http.Request) (storev2.ResourceRequest, error) {
+	name, err := url.PathUnescape(mux.Vars(r)["id"])
+	if err != nil {
+		return storev2.ResourceRequest{}, actions.NewError(actions.InvalidArgument, err)
+	}
+	var t T
+	resource := R(&t)
+	resource.SetMetadata(&corev2.ObjectMeta{
+		Namespace: store.NewNamespaceFromContext(r.Context()),
+		Name:      name,
+	})
+	return storev2.NewResourceRequestFromResource(resource), nil
+}
+
+// getRevision returns the revision given by the query parameter, or the
+// latest revision if the parameter is missing and latest is set.
+func (h Handlers[R, T]) getRevision(r *http.Request, req storev2.ResourceRequest, param string, latest bool) (*storev2.Revision, error) {
+	value := r.URL.Query().Get(param)
+	if value == "" && latest {
+		revisions, err := h.Store.GetConfigHistoryStore().ListRevisions(r.Context(), req)
+		if err != nil {
+			return nil, actions.NewError(actions.InternalErr, err)
+		}
+		if len(revisions) == 0 {
+			return nil, actions.NewErrorf(actions.NotFound)
+		}
+		return revisions[0], nil
+	}
+	number, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || number <= 0 {
+		return nil, actions.NewError(actions.InvalidArgument, fmt.Errorf("invalid %s: %q", param, value))
+	}
+	revision, err := h.Store.GetConfigHistoryStore().GetRevision(r.Context(), req, number)
+	if err != nil {
+		if _, ok := err.(*store.ErrNotFound); ok {
+			return nil, actions.NewError(actions.NotFound, fmt.Errorf("revision %d not found", number))
+		}
+		return nil, actions.NewError(actions.InternalErr, err)
+	}
+	return revision, nil
+}
+
+// ListRevisions lists the revisions of a resource, most recent first.
+func (h Handlers[R, T]) ListRevisions(r *http.Request) (HandlerResponse, error) {
+	var response HandlerResponse
+	req, err := h.revisionRequest(r)
+	if err != nil {
+		return response, err
+	}
+	revisions, err := h.Store.GetConfigHistoryStore().ListRevisions(r.Context(), req)
+	if err != nil {
+		return response, actions.NewError(actions.InternalErr, err)
+	}
+	if len(revisions) == 0 {
+		return response, actions.NewErrorf(actions.NotFound)
+	}
+	// Revisions are not resources, they are written as a plain list
+	response.Payload = revisions
+	return response, nil
+}
+
+// DiffRevisions compares two revisions of a resource.
+func (h Handlers[R, T]) DiffRevisions(r *http.Request) (HandlerResponse, error) {
+	var response HandlerResponse
+	req, err := h.revisionRequest(r)
+	if err != nil {
+		return response, err
+	}
+	from, err := h.getRevision(r, req, DiffFromParam, false)
+	if err != nil {
+		return response, err
+	}
+	to, err := h.getRevision(r, req, DiffToParam, true)
+	if err != nil {
+		return response, err
+	}
+	diff, err := jsonpatch.CreateMergePatch(from.Resource, to.Resource)
+	if err != nil {
+		return response, actions.NewError(actions.InternalErr, err)
+	}
+	response.Payload = &RevisionDiff{From: from, To: to, Diff: diff}
+	return response, nil
+}
+
+// RollbackResource restores a resource as it was at the given revision. The
+// rollback is a write of the resource, which records a new revision, so that
+// it can be rolled back too. The revision of a deletion can't be restored,
+// but the revision that precedes it can.
+func (h Handlers[R, T]) RollbackResource(r *http.Request) (HandlerResponse, error) {
+	var response HandlerResponse
+	req, err := h.revisionRequest(r)
+	if err != nil {
+		return response, err
+	}
+	revision, err := h.getRevision(r, req, RevisionParam, false)
+	if err != nil {
+		return response, err
+	}
+	if revision.Operation == storev2.RevisionDelete {
+		return response, actions.NewError(actions.InvalidArgument, fmt.Errorf(
+			"revision %d is a deletion, roll back to revision %d instead", revision.Revision, revision.Revision-1))
+	}
+
+	var t T
+	payload := R(&t)
+	if err := json.Unmarshal(revision.Resource, payload); err != nil {
+		return response, actions.NewError(actions.InternalErr, err)
+	}
+	meta := payload.GetMetadata()
+	if meta == nil {
+		return response, actions.NewError(actions.InternalErr, errors.New("nil metadata"))
+	}
+
+	ctx := storev2.ContextWithTxInfo(r.Context(), &response.TxInfo)
+	if claims := jwt.GetClaimsFromContext(ctx); claims != nil {
+		meta.CreatedBy = claims.StandardClaims.Subject
+	}
+
+	if IsDryRun(r) {
+		return h.dryRun(ctx, payload, false)
+	}
+
+	if err := storev2.Of[R](h.Store).CreateOrUpdate(ctx, payload); err != nil {
+		switch err := err.(type) {
+		case *store.ErrNotValid:
+			return response, actions.NewError(actions.InvalidArgument, err)
+		default:
+			return response, actions.NewError(actions.InternalErr, err)
+		}
+	}
+	response.Resource = payload
+	return response, nil
+}
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func fixtureRevision(t *testing.T, number int64, operation, command string) *storev2.Revision {
+	t.Helper()
+	check := corev2.FixtureCheckConfig("cpu")
+	check.Command = command
+	resource, err := json.Marshal(check)
+	require.NoError(t, err)
+	return &storev2.Revision{
+		Revision:  number,
+		Operation: operation,
+		Time:      time.Unix(1700000000+number, 0),
+		Resource:  resource,
+	}
+}
+
+func newHistoryStore(t *testing.T) (*mockstore.V2MockStore, *mockstore.ConfigStore, *mockstore.ConfigHistoryStore) {
+	t.Helper()
+	s := &mockstore.V2MockStore{}
+	cs := new(mockstore.ConfigStore)
+	hs := new(mockstore.ConfigHistoryStore)
+	s.On("GetConfigStore").Return(cs)
+	s.On("GetConfigHistoryStore").Return(hs)
+
+	req := storev2.NewResourceRequestFromResource(corev2.FixtureCheckConfig("cpu"))
+	revisions := []*storev2.Revision{
+		fixtureRevision(t, 3, storev2.RevisionDelete, "check-cpu --warning 90"),
+		fixtureRevision(t, 2, storev2.RevisionUpdate, "check-cpu --warning 90"),
+		fixtureRevision(t, 1, storev2.RevisionCreate, "check-cpu"),
+	}
+	hs.On("ListRevisions", mock.Anything, req).Return(revisions, nil)
+	for _, revision := range revisions {
+		hs.On("GetRevision", mock.Anything, req, revision.Revision).Return(revision, nil)
+	}
+	hs.On("GetRevision", mock.Anything, req, mock.Anything).Return(nil, &store.ErrNotFound{})
+	hs.On("ListRevisions", mock.Anything, mock.Anything).Return([]*storev2.Revision{}, nil)
+	return s, cs, hs
+}
+
+func newRevisionRequest(t *testing.T, method, target, name string) *http.Request {
+	t.Helper()
+	ctx := context.WithValue(context.Background(), corev2.NamespaceKey, "default")
+	r, err := http.NewRequestWithContext(ctx, method, target, nil)
+	require.NoError(t, err)
+	return mux.SetURLVars(r, map[string]string{"namespace": "default", "id": name})
+}
+
+func TestListRevisions(t *testing.T) {
+	s, _, _ := newHistoryStore(t)
+	h := NewHandlers[*corev2.CheckConfig](s)
+
+	response, err := h.ListRevisions(newRevisionRequest(t, http.MethodGet, "/", "cpu"))
+	require.NoError(t, err)
+	revisions, ok := response.Payload.([]*storev2.Revision)
+	require.True(t, ok)
+	assert.Len(t, revisions, 3)
+
+	_, err = h.ListRevisions(newRevisionRequest(t, http.MethodGet, "/", "missing"))
+	require.Error(t, err)
+	assert.Equal(t, actions.NotFound, err.(actions.Error).Code)
+}
+
+func TestDiffRevisions(t *testing.T) {
+	s, _, _ := newHistoryStore(t)
+	h := NewHandlers[*corev2.CheckConfig](s)
+
+	tests := []struct {
+		name     string
+		target   string
+		wantFrom int64
+		wantTo   int64
+		wantDiff string
+		wantCode actions.ErrCode
+	}{
+		{
+			name:     "two revisions",
+			target:   "/?from=1&to=2",
+			wantFrom: 1,
+			wantTo:   2,
+			wantDiff: `{"command": "check-cpu --warning 90"}`,
+		},
+		{
+			name:     "latest revision",
+			target:   "/?from=2",
+			wantFrom: 2,
+			wantTo:   3,
+			wantDiff: `{}`,
+		},
+		{
+			name:     "missing from",
+			target:   "/",
+			wantCode: actions.InvalidArgument,
+		},
+		{
+			name:     "unknown revision",
+			target:   "/?from=1&to=5",
+			wantCode: actions.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := h.DiffRevisions(newRevisionRequest(t, http.MethodGet, tt.target, "cpu"))
+			if tt.wantCode != 0 {
+				require.Error(t, err)
+				assert.Equal(t, tt.wantCode, err.(actions.Error).Code)
+				return
+			}
+			require.NoError(t, err)
+			diff, ok := response.Payload.(*RevisionDiff)
+			require.True(t, ok)
+			assert.Equal(t, tt.wantFrom, diff.From.Revision)
+			assert.Equal(t, tt.wantTo, diff.To.Revision)
+			assert.JSONEq(t, tt.wantDiff, string(diff.Diff))
+		})
+	}
+}
+
+func TestRollbackResource(t *testing.T) {
+	s, cs, _ := newHistoryStore(t)
+	h := NewHandlers[*corev2.CheckConfig](s)
+
+	var restored corev2.CheckConfig
+	cs.On("CreateOrUpdate", mock.Anything, mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		require.NoError(t, args.Get(2).(storev2.Wrapper).UnwrapInto(&restored))
+	})
+
+	response, err := h.RollbackResource(newRevisionRequest(t, http.MethodPut, "/?revision=1", "cpu"))
+	require.NoError(t, err)
+	assert.Equal(t, "check-cpu", restored.Command)
+	assert.Equal(t, "check-cpu", response.Resource.(*corev2.CheckConfig).Command)
+}
+
+func TestRollbackResourceErrors(t *testing.T) {
+	s, cs, _ := newHistoryStore(t)
+	h := NewHandlers[*corev2.CheckConfig](s)
+
+	tests := []struct {
+		name     string
+		target   string
+		wantCode actions.ErrCode
+	}{
+		{
+			name:     "missing revision",
+			target:   "/",
+			wantCode: actions.InvalidArgument,
+		},
+		{
+			name:     "unknown revision",
+			target:   "/?revision=5",
+			wantCode: actions.NotFound,
+		},
+		{
+			name:     "deletion",
+			target:   "/?revision=3",
+			wantCode: actions.InvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := h.RollbackResource(newRevisionRequest(t, http.MethodPut, tt.target, "cpu"))
+			require.Error(t, err)
+			assert.Equal(t, tt.wantCode, err.(actions.Error).Code)
+		})
+	}
+	cs.AssertNotCalled(t, "CreateOrUpdate", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRollbackResourceDryRun(t *testing.T) {
+	s, cs, _ := newHistoryStore(t)
+	cs.On("Exists", mock.Anything, mock.Anything).Return(false, nil)
+	cs.On("Get", mock.Anything, mock.Anything).Return(nil, &store.ErrNotFound{})
+	h := newDryRunHandlers[*corev2.CheckConfig](s)
+
+	r := newRevisionRequest(t, http.MethodPut, "/?revision=2&dryRun=true", "cpu")
+	r = r.WithContext(context.WithValue(r.Context(), corev2.ClaimsKey, corev2.FixtureClaims("developer", nil)))
+	response, err := h.RollbackResource(r)
+	require.NoError(t, err)
+	require.NotNil(t, response.DryRun)
+	assert.Equal(t, DryRunCreate, response.DryRun.Operation)
+	cs.AssertNotCalled(t, "CreateOrUpdate", mock.Anything, mock.Anything, mock.Anything)
+}
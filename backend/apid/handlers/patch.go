@@ -33,6 +33,10 @@ var acceptedContentTypes = []string{mergePatchContentType}
 func (h Handlers[R, T]) PatchResource(r *http.Request) (HandlerResponse, error) {
 	var response HandlerResponse
 
+	if IsDryRun(r) {
+		return response, UnsupportedDryRun()
+	}
+
 	// Read the request body
 	body, err := ioutil.ReadAll(r.Body)
 	if err != nil {
@@ -10,10 +10,17 @@ type HandlerResponse struct {
 	ResourceList []corev3.Resource
 	TxInfo       storev2.TxInfo
 	GraphQL      interface{} // unfortunate
+	DryRun       *DryRunResult
+
+	// Payload is a response that is not made of resources, e.g. a list of
+	// history entries. It is written as is.
+	Payload interface{}
 }
 
 func (h HandlerResponse) IsEmpty() bool {
 	return (h.Resource == nil &&
 		h.ResourceList == nil &&
-		h.GraphQL == nil)
+		h.GraphQL == nil &&
+		h.DryRun == nil &&
+		h.Payload == nil)
 }
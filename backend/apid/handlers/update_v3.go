@@ -40,6 +40,10 @@ func (h Handlers[R, T]) CreateOrUpdateResource(r *http.Request) (HandlerResponse
 		meta.CreatedBy = claims.StandardClaims.Subject
 	}
 
+	if IsDryRun(r) {
+		return h.dryRun(ctx, payload, false)
+	}
+
 	gstore := storev2.Of[R](h.Store)
 
 	if err := gstore.CreateOrUpdate(ctx, payload); err != nil {
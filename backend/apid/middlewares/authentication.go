@@ -5,11 +5,15 @@ import (
 	"errors"
 	"net/http"
 	"strings"
+	"time"
 
 	corev2 "github.com/sensu/core/v2"
 	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/backend/authentication/apikey"
 	"github.com/sensu/sensu-go/backend/authentication/bcrypt"
 	"github.com/sensu/sensu-go/backend/authentication/jwt"
+	"github.com/sensu/sensu-go/backend/authorization"
+	"github.com/sensu/sensu-go/backend/store"
 	storev2 "github.com/sensu/sensu-go/backend/store/v2"
 )
 
@@ -19,48 +23,29 @@ type Authentication struct {
 	// in the case where an access token was not present.
 	IgnoreUnauthorized bool
 	Store              storev2.Interface
+	// APIKeyUsage records the last time the API keys are used, if set.
+	APIKeyUsage store.APIKeyUsageStore
 }
 
+// errNoCredentials is returned by Authenticate when the Authorization header
+// contains neither an access token nor an API key.
+var errNoCredentials = errors.New("no credentials")
+
 // Then middleware
 func (a Authentication) Then(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 		authHeader, ok := r.Header["Authorization"]
 		if ok && len(authHeader) >= 1 {
-			headerString := authHeader[0]
-
-			// if the auth header contains Bearer, continue with token auth
-			if strings.HasPrefix(headerString, "Bearer ") {
-				headerString = strings.TrimPrefix(headerString, "Bearer ")
-				token, err := jwt.ValidateToken(headerString)
-				if err != nil {
-					logger.WithError(err).Warn("invalid token")
-					actionErr := actions.NewErrorf(actions.Unauthenticated, "invalid credentials")
-					SimpleLogger{}.Then(errorWriter{err: actionErr}.Then(next)).ServeHTTP(w, r.WithContext(ctx))
-					return
-				}
-				// Set the claims into the request context
-				ctx = jwt.SetClaimsIntoContext(r, token.Claims.(*corev2.Claims))
-				next.ServeHTTP(w, r.WithContext(ctx))
+			authCtx, err := a.Authenticate(ctx, authHeader[0])
+			if err == nil {
+				next.ServeHTTP(w, r.WithContext(authCtx))
 				return
 			}
-
-			// if the auth header contains Key, continue with api key auth
-			if strings.HasPrefix(headerString, "Key ") {
-				headerString = strings.TrimPrefix(headerString, "Key ")
-				claims, err := extractAPIKeyClaims(ctx, headerString, a.Store)
-				if err != nil {
-					logger.WithError(err).Warn("invalid api key")
-					actionErr := actions.NewErrorf(actions.Unauthenticated, "invalid credentials")
-					SimpleLogger{}.Then(errorWriter{err: actionErr}.Then(next)).ServeHTTP(w, r.WithContext(ctx))
-					return
-				}
-				if claims != nil {
-					// Set the claims into the request context
-					ctx = jwt.SetClaimsIntoContext(r, claims)
-					next.ServeHTTP(w, r.WithContext(ctx))
-					return
-				}
+			if err != errNoCredentials {
+				actionErr := actions.NewErrorf(actions.Unauthenticated, "invalid credentials")
+				SimpleLogger{}.Then(errorWriter{err: actionErr}.Then(next)).ServeHTTP(w, r.WithContext(ctx))
+				return
 			}
 		}
 
@@ -75,34 +60,94 @@ func (a Authentication) Then(next http.Handler) http.Handler {
 	})
 }
 
-func extractAPIKeyClaims(ctx context.Context, key string, store storev2.Interface) (*corev2.Claims, error) {
+// Authenticate authenticates the value of an Authorization header, either an
+// access token or an API key, and returns the context with the claims of the
+// user, along with the scope of the API key.
+func (a Authentication) Authenticate(ctx context.Context, header string) (context.Context, error) {
+	// if the auth header contains Bearer, continue with token auth
+	if strings.HasPrefix(header, "Bearer ") {
+		token, err := jwt.ValidateToken(strings.TrimPrefix(header, "Bearer "))
+		if err != nil {
+			logger.WithError(err).Warn("invalid token")
+			return ctx, err
+		}
+		claims, ok := token.Claims.(*corev2.Claims)
+		if !ok {
+			return ctx, errors.New("invalid token claims")
+		}
+		return context.WithValue(ctx, corev2.ClaimsKey, claims), nil
+	}
+
+	// if the auth header contains Key, continue with api key auth
+	if strings.HasPrefix(header, "Key ") {
+		claims, scope, err := extractAPIKeyClaims(ctx, strings.TrimPrefix(header, "Key "), a.Store)
+		if err != nil {
+			logger.WithError(err).Warn("invalid api key")
+			return ctx, err
+		}
+		a.recordAPIKeyUse(ctx, claims.Id)
+		// Set the claims into the context, along with the scope of the api
+		// key that restricts its requests
+		ctx = context.WithValue(ctx, corev2.ClaimsKey, claims)
+		if scope != nil {
+			ctx = authorization.ContextWithScope(ctx, scope)
+		}
+		return ctx, nil
+	}
+
+	return ctx, errNoCredentials
+}
+
+func (a Authentication) recordAPIKeyUse(ctx context.Context, name string) {
+	if a.APIKeyUsage == nil {
+		return
+	}
+	if err := a.APIKeyUsage.RecordAPIKeyUse(ctx, name, time.Now()); err != nil {
+		logger.WithError(err).Warn("couldn't record api key use")
+	}
+}
+
+func extractAPIKeyClaims(ctx context.Context, key string, store storev2.Interface) (*corev2.Claims, *authorization.Scope, error) {
 	var claims *corev2.Claims
 	keyStore := storev2.Of[*corev2.APIKey](store)
 	apiKeys, err := keyStore.List(ctx, storev2.ID{}, nil)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	for _, apiKey := range apiKeys {
 		if bcrypt.CheckPassword(string(apiKey.Hash), key) {
+			if apikey.Expired(apiKey, time.Now()) {
+				return nil, nil, apikey.ErrExpired
+			}
+			scope, err := apikey.Scope(apiKey)
+			if err != nil {
+				return nil, nil, err
+			}
+
 			userStore := storev2.Of[*corev2.User](store)
 			user, err := userStore.Get(ctx, storev2.ID{Name: apiKey.Username})
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 
-			// inject the username and groups into standard jwt claims
+			// inject the username and groups into standard jwt claims, and
+			// identify the api key with the token id
 			claims = &corev2.Claims{
 				StandardClaims: corev2.StandardClaims(user.Username),
 				Groups:         user.Groups,
 				APIKey:         true,
 			}
+			claims.Id = apiKey.Name
+			if expiresAt, _ := apikey.ExpiresAt(apiKey); !expiresAt.IsZero() {
+				claims.ExpiresAt = expiresAt.Unix()
+			}
 
-			return claims, nil
+			return claims, scope, nil
 		}
 	}
 
-	return nil, errors.New("API key rejected")
+	return nil, nil, errors.New("API key rejected")
 }
 
 type errorWriter struct {
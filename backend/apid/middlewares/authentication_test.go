@@ -1,15 +1,19 @@
 package middlewares
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/authentication/apikey"
 	"github.com/sensu/sensu-go/backend/authentication/bcrypt"
 	"github.com/sensu/sensu-go/backend/authentication/jwt"
+	"github.com/sensu/sensu-go/backend/authorization"
 	"github.com/sensu/sensu-go/backend/store"
 	storev2 "github.com/sensu/sensu-go/backend/store/v2"
 	"github.com/sensu/sensu-go/testing/mockstore"
@@ -85,7 +89,10 @@ func TestMiddlewareValidAPIKey(t *testing.T) {
 	mware := Authentication{
 		Store: store,
 	}
-	server := httptest.NewServer(mware.Then(testHandler()))
+	var claims *corev2.Claims
+	server := httptest.NewServer(mware.Then(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims = jwt.GetClaimsFromContext(r.Context())
+	})))
 	defer server.Close()
 
 	secret := "174373d0-4aff-41d8-aa5f-084dfcad7dc7"
@@ -113,6 +120,13 @@ func TestMiddlewareValidAPIKey(t *testing.T) {
 	res, err := client.Do(req)
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	// The api key is identified by the token id
+	if assert.NotNil(t, claims) {
+		assert.True(t, claims.APIKey)
+		assert.Equal(t, "admin", claims.Subject)
+		assert.Equal(t, "foobar", claims.Id)
+	}
 }
 
 func TestMiddlewareInvalidAPIKey(t *testing.T) {
@@ -188,3 +202,116 @@ func TestMiddlewareNoUserAPIKey(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
 }
+
+type apiKeyUsageStore struct {
+	uses map[string]time.Time
+}
+
+func (s *apiKeyUsageStore) RecordAPIKeyUse(ctx context.Context, name string, t time.Time) error {
+	s.uses[name] = t
+	return nil
+}
+
+func (s *apiKeyUsageStore) GetAPIKeyUses(ctx context.Context) (map[string]time.Time, error) {
+	return s.uses, nil
+}
+
+func (s *apiKeyUsageStore) PruneAPIKeyUses(ctx context.Context, keep []string) (int64, error) {
+	return 0, nil
+}
+
+func TestMiddlewareScopedAPIKey(t *testing.T) {
+	st := &mockstore.V2MockStore{}
+	cs := new(mockstore.ConfigStore)
+	st.On("GetConfigStore").Return(cs)
+	usage := &apiKeyUsageStore{uses: map[string]time.Time{}}
+	mware := Authentication{
+		Store:       st,
+		APIKeyUsage: usage,
+	}
+	var (
+		claims *corev2.Claims
+		scope  *authorization.Scope
+	)
+	server := httptest.NewServer(mware.Then(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims = jwt.GetClaimsFromContext(r.Context())
+		scope = authorization.ScopeFromContext(r.Context())
+	})))
+	defer server.Close()
+
+	secret := "174373d0-4aff-41d8-aa5f-084dfcad7dc7"
+	hash, err := bcrypt.HashPassword(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	key := &corev2.APIKey{
+		ObjectMeta: corev2.ObjectMeta{
+			Name: "ci",
+			Annotations: map[string]string{
+				apikey.ExpiresAtAnnotation:  expiresAt.Format(time.RFC3339),
+				apikey.NamespacesAnnotation: "ci",
+			},
+		},
+		Username: "admin",
+		Hash:     []byte(hash),
+	}
+	user := &corev2.User{Username: "admin"}
+	userReq := storev2.NewResourceRequestFromResource(user)
+	cs.On("Get", mock.Anything, userReq).Return(mockstore.Wrapper[*corev2.User]{Value: user}, nil)
+	cs.On("List", mock.Anything, mock.Anything, mock.Anything).Return(mockstore.WrapList[*corev2.APIKey]{key}, nil)
+
+	client := &http.Client{}
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	req.Header.Add("Authorization", fmt.Sprintf("Key %s", secret))
+	res, err := client.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	if assert.NotNil(t, claims) {
+		assert.Equal(t, expiresAt.Unix(), claims.ExpiresAt)
+	}
+	assert.Equal(t, &authorization.Scope{Namespaces: []string{"ci"}}, scope)
+	assert.Contains(t, usage.uses, "ci")
+}
+
+func TestMiddlewareExpiredAPIKey(t *testing.T) {
+	st := &mockstore.V2MockStore{}
+	cs := new(mockstore.ConfigStore)
+	st.On("GetConfigStore").Return(cs)
+	usage := &apiKeyUsageStore{uses: map[string]time.Time{}}
+	mware := Authentication{
+		Store:       st,
+		APIKeyUsage: usage,
+	}
+	server := httptest.NewServer(mware.Then(testHandler()))
+	defer server.Close()
+
+	secret := "174373d0-4aff-41d8-aa5f-084dfcad7dc7"
+	hash, err := bcrypt.HashPassword(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := &corev2.APIKey{
+		ObjectMeta: corev2.ObjectMeta{
+			Name: "ci",
+			Annotations: map[string]string{
+				apikey.ExpiresAtAnnotation: time.Now().Add(-time.Hour).Format(time.RFC3339),
+			},
+		},
+		Username: "admin",
+		Hash:     []byte(hash),
+	}
+	user := &corev2.User{Username: "admin"}
+	userReq := storev2.NewResourceRequestFromResource(user)
+	cs.On("Get", mock.Anything, userReq).Return(mockstore.Wrapper[*corev2.User]{Value: user}, nil)
+	cs.On("List", mock.Anything, mock.Anything, mock.Anything).Return(mockstore.WrapList[*corev2.APIKey]{key}, nil)
+
+	client := &http.Client{}
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	req.Header.Add("Authorization", fmt.Sprintf("Key %s", secret))
+	res, err := client.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	assert.Empty(t, usage.uses)
+}
@@ -0,0 +1,125 @@
+package middlewares
+
+import (
+	"math"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/backend/apid/request"
+	"github.com/sensu/sensu-go/backend/authentication/jwt"
+	"github.com/sensu/sensu-go/backend/ratelimit"
+)
+
+const (
+	// RateLimitRequests is the name of the prometheus counter vec of the
+	// requests subject to a rate limit, by route group and result.
+	RateLimitRequests = "sensu_go_api_rate_limit_requests"
+
+	// RateLimitGroupLabel is the label of the route group of a request.
+	RateLimitGroupLabel = "group"
+
+	// RateLimitResultLabel is the label of the result of a request, either
+	// RateLimitAllowed or RateLimitLimited.
+	RateLimitResultLabel = "result"
+
+	RateLimitAllowed = "allowed"
+	RateLimitLimited = "limited"
+)
+
+var rateLimitCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: RateLimitRequests,
+		Help: "The total number of API requests subject to a rate limit",
+	},
+	[]string{RateLimitGroupLabel, RateLimitResultLabel},
+)
+
+func init() {
+	_ = prometheus.Register(rateLimitCounter)
+}
+
+// RateLimit is a HTTP middleware that limits the rate of the requests of each
+// client, identified by its API key, its user or its source IP, with a token
+// bucket per client and route group. It must follow the Authentication and
+// SourceIP middlewares, unless ByIP is set.
+type RateLimit struct {
+	// Limiter keeps the token buckets, the requests are not limited if it's
+	// nil.
+	Limiter ratelimit.Limiter
+
+	// Limits are the limits of the route groups, e.g. core/v2. The requests
+	// of the groups without a limit are not limited.
+	Limits map[string]ratelimit.Limit
+
+	// Group is the route group of the requests. It defaults to the API group
+	// and version of the request path.
+	Group string
+
+	// ByIP limits the requests of each source IP, whether they are
+	// authenticated or not. It only needs to follow the SourceIP middleware,
+	// so that it can precede the Authentication middleware and bound the
+	// work done for the requests with invalid credentials.
+	ByIP bool
+}
+
+// Then middleware
+func (l RateLimit) Then(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		group := l.Group
+		if group == "" {
+			vars := mux.Vars(r)
+			group = path.Join(vars["group"], vars["version"])
+		}
+		limit, ok := l.Limits[group]
+		if l.Limiter == nil || !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		key := rateLimitKey(r)
+		if l.ByIP {
+			key = "source:" + request.SourceIPFromContext(r.Context())
+		}
+		delay, err := l.Limiter.Take(r.Context(), group+"/"+key, limit)
+		if err != nil {
+			// Don't turn an unavailable store into an unavailable API
+			logger.WithError(err).Warn("couldn't apply the rate limit, allowing the request")
+			next.ServeHTTP(w, r)
+			return
+		}
+		if delay > 0 {
+			rateLimitCounter.WithLabelValues(group, RateLimitLimited).Inc()
+			w.Header().Set("Retry-After", retryAfter(delay))
+			writeErr(w, actions.NewErrorf(actions.ResourceExhausted, "rate limit exceeded"))
+			return
+		}
+		rateLimitCounter.WithLabelValues(group, RateLimitAllowed).Inc()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// rateLimitKey identifies the client of a request by its API key, its user,
+// or its source IP if it's unauthenticated.
+func rateLimitKey(r *http.Request) string {
+	if claims := jwt.GetClaimsFromContext(r.Context()); claims != nil {
+		if claims.APIKey && claims.Id != "" {
+			return "apikey:" + claims.Id
+		}
+		return "user:" + claims.Subject
+	}
+	return "ip:" + request.SourceIPFromContext(r.Context())
+}
+
+// retryAfter returns the value of the Retry-After header, in whole seconds.
+func retryAfter(delay time.Duration) string {
+	seconds := int64(math.Ceil(delay.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	return strconv.FormatInt(seconds, 10)
+}
@@ -0,0 +1,137 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/apid/request"
+	"github.com/sensu/sensu-go/backend/ratelimit"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeLimiter struct {
+	keys  []string
+	delay time.Duration
+	err   error
+}
+
+func (l *fakeLimiter) Take(_ context.Context, key string, _ ratelimit.Limit) (time.Duration, error) {
+	l.keys = append(l.keys, key)
+	return l.delay, l.err
+}
+
+func TestRateLimit(t *testing.T) {
+	limits := map[string]ratelimit.Limit{
+		"core/v2": {Rate: 1, Burst: 1},
+		"graphql": {Rate: 1, Burst: 1},
+	}
+	apiKeyClaims := &corev2.Claims{StandardClaims: corev2.StandardClaims("ci"), APIKey: true}
+	apiKeyClaims.Id = "0c2b3f45-0b6d-4a9b-9d25-2b8f1a4c8f3e"
+
+	tests := []struct {
+		name           string
+		group          string
+		version        string
+		middleware     RateLimit
+		claims         *corev2.Claims
+		delay          time.Duration
+		err            error
+		wantKeys       []string
+		wantStatusCode int
+		wantRetryAfter string
+	}{
+		{
+			name:           "requests of groups without a limit are not limited",
+			group:          "core",
+			version:        "v3",
+			claims:         corev2.FixtureClaims("alice", nil),
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "requests of users are limited by user",
+			group:          "core",
+			version:        "v2",
+			claims:         corev2.FixtureClaims("alice", nil),
+			wantKeys:       []string{"core/v2/user:alice"},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "requests with api keys are limited by api key",
+			group:          "core",
+			version:        "v2",
+			claims:         apiKeyClaims,
+			wantKeys:       []string{"core/v2/apikey:0c2b3f45-0b6d-4a9b-9d25-2b8f1a4c8f3e"},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "unauthenticated requests are limited by source ip",
+			middleware:     RateLimit{Group: "graphql"},
+			wantKeys:       []string{"graphql/ip:10.0.0.1"},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "requests are limited by source ip before authentication",
+			group:          "core",
+			version:        "v2",
+			middleware:     RateLimit{ByIP: true},
+			claims:         corev2.FixtureClaims("alice", nil),
+			wantKeys:       []string{"core/v2/source:10.0.0.1"},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "requests over the limit are rejected",
+			group:          "core",
+			version:        "v2",
+			claims:         corev2.FixtureClaims("alice", nil),
+			delay:          1500 * time.Millisecond,
+			wantKeys:       []string{"core/v2/user:alice"},
+			wantStatusCode: http.StatusTooManyRequests,
+			wantRetryAfter: "2",
+		},
+		{
+			name:           "requests are allowed if the limiter fails",
+			group:          "core",
+			version:        "v2",
+			claims:         corev2.FixtureClaims("alice", nil),
+			err:            errors.New("connection refused"),
+			wantKeys:       []string{"core/v2/user:alice"},
+			wantStatusCode: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := &fakeLimiter{delay: tt.delay, err: tt.err}
+			middleware := tt.middleware
+			middleware.Limiter = limiter
+			middleware.Limits = limits
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req = mux.SetURLVars(req, map[string]string{"group": tt.group, "version": tt.version})
+			ctx := request.ContextWithSourceIP(req.Context(), "10.0.0.1")
+			if tt.claims != nil {
+				ctx = context.WithValue(ctx, corev2.ClaimsKey, tt.claims)
+			}
+			req = req.WithContext(ctx)
+
+			w := httptest.NewRecorder()
+			middleware.Then(testHandler()).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			assert.Equal(t, tt.wantKeys, limiter.keys)
+			assert.Equal(t, tt.wantRetryAfter, w.Header().Get("Retry-After"))
+		})
+	}
+}
+
+func TestRateLimitWithoutLimiter(t *testing.T) {
+	middleware := RateLimit{Limits: map[string]ratelimit.Limit{"graphql": {Rate: 1, Burst: 1}}, Group: "graphql"}
+	w := httptest.NewRecorder()
+	middleware.Then(testHandler()).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -2,7 +2,6 @@ package middlewares
 
 import (
 	"net/http"
-	"strings"
 
 	"github.com/sensu/sensu-go/backend/apid/request"
 	"github.com/sensu/sensu-go/backend/selector"
@@ -21,7 +20,7 @@ func (s Selectors) Then(next http.Handler) http.Handler {
 		query := r.URL.Query()
 		// Determine if we have a label selector
 		var labelSelector *selector.Selector
-		requirements := strings.Join(query["labelSelector"], " && ")
+		requirements := selector.Join(query["labelSelector"])
 		if requirements != "" {
 			var err error
 			labelSelector, err = selector.ParseLabelSelector(requirements)
@@ -34,7 +33,7 @@ func (s Selectors) Then(next http.Handler) http.Handler {
 
 		// Determine if we have a field selector
 		var fieldSelector *selector.Selector
-		requirements = strings.Join(query["fieldSelector"], " && ")
+		requirements = selector.Join(query["fieldSelector"])
 		if requirements != "" {
 			var err error
 			fieldSelector, err = selector.ParseFieldSelector(requirements)
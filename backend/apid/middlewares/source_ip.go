@@ -0,0 +1,24 @@
+package middlewares
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/sensu/sensu-go/backend/apid/request"
+)
+
+// SourceIP is a HTTP middleware that sets the IP address of the client into
+// the request context, for the audit log.
+type SourceIP struct{}
+
+// Then middleware
+func (SourceIP) Then(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			ip = r.RemoteAddr
+		}
+		ctx := request.ContextWithSourceIP(r.Context(), ip)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -27,3 +27,20 @@ func SelectorFromContext(ctx context.Context) *selector.Selector {
 	}
 	return val.(*selector.Selector)
 }
+
+// contextKeySourceIP is the context key that identifies the source IP of a
+// request.
+type contextKeySourceIP struct{}
+
+// ContextWithSourceIP returns a new context that contains the IP address a
+// request came from.
+func ContextWithSourceIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, contextKeySourceIP{}, ip)
+}
+
+// SourceIPFromContext returns the source IP from the context, or an empty
+// string if it is missing.
+func SourceIPFromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(contextKeySourceIP{}).(string)
+	return ip
+}
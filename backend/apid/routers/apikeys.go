@@ -2,11 +2,13 @@ package routers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
@@ -18,8 +20,10 @@ import (
 	"github.com/sensu/sensu-go/backend/apid/actions"
 	"github.com/sensu/sensu-go/backend/apid/handlers"
 	"github.com/sensu/sensu-go/backend/apid/request"
+	apikeys "github.com/sensu/sensu-go/backend/authentication/apikey"
 	"github.com/sensu/sensu-go/backend/authentication/bcrypt"
 	"github.com/sensu/sensu-go/backend/store"
+	"github.com/sensu/sensu-go/backend/store/patch"
 	storev2 "github.com/sensu/sensu-go/backend/store/v2"
 	"github.com/sensu/core/v3/types"
 )
@@ -27,12 +31,15 @@ import (
 // APIKeysRouter handles requests for /apikeys.
 type APIKeysRouter struct {
 	store storev2.Interface
+	usage store.APIKeyUsageStore
 }
 
 // NewAPIKeysRouter instantiates new router for controlling apikeys resources.
-func NewAPIKeysRouter(store storev2.Interface) *APIKeysRouter {
+// The last time the keys were used is retrieved from usage, if set.
+func NewAPIKeysRouter(store storev2.Interface, usage store.APIKeyUsageStore) *APIKeysRouter {
 	return &APIKeysRouter{
 		store: store,
+		usage: usage,
 	}
 }
 
@@ -46,19 +53,134 @@ func (r *APIKeysRouter) Mount(parent *mux.Router) {
 	handlers := handlers.NewHandlers[*corev2.APIKey](r.store)
 
 	routes.Del(handlers.DeleteResource)
-	routes.Get(handlers.GetResource)
-	routes.List(handlers.ListResources, corev3.APIKeyFields)
+	routes.Get(r.get(handlers.GetResource))
+	routes.List(r.list(handlers.ListResources), corev3.APIKeyFields)
 	parent.HandleFunc(routes.PathPrefix, r.create).Methods(http.MethodPost, http.MethodPut)
-	routes.Patch(handlers.PatchResource)
+	routes.Patch(r.patch(handlers.PatchResource))
+}
+
+// patch refuses the patches that change the immutable annotations of a key.
+func (r *APIKeysRouter) patch(fn actionHandlerFunc) actionHandlerFunc {
+	return func(req *http.Request) (handlers.HandlerResponse, error) {
+		var response handlers.HandlerResponse
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			return response, actions.NewError(
+				actions.InvalidArgument,
+				fmt.Errorf("could not read the request body: %s", err),
+			)
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		name, err := url.PathUnescape(mux.Vars(req)["id"])
+		if err != nil {
+			return response, err
+		}
+		before, err := storev2.Of[*corev2.APIKey](r.store).Get(req.Context(), storev2.ID{Name: name})
+		if err != nil {
+			if _, ok := err.(*store.ErrNotFound); ok {
+				return response, actions.NewError(actions.NotFound, err)
+			}
+			return response, actions.NewError(actions.InternalErr, err)
+		}
+		document, err := json.Marshal(before)
+		if err != nil {
+			return response, actions.NewError(actions.InternalErr, err)
+		}
+		patched, err := (&patch.Merge{MergePatch: body}).Patch(document)
+		if err != nil {
+			return response, actions.NewError(actions.InvalidArgument, err)
+		}
+		var after corev2.APIKey
+		if err := json.Unmarshal(patched, &after); err != nil {
+			return response, actions.NewError(actions.InvalidArgument, err)
+		}
+		if err := apikeys.ValidateUpdate(before, &after); err != nil {
+			return response, actions.NewError(actions.InvalidArgument, err)
+		}
+		return fn(req)
+	}
+}
+
+// get adds the last time the key was used to the retrieved key.
+func (r *APIKeysRouter) get(fn actionHandlerFunc) actionHandlerFunc {
+	return func(req *http.Request) (handlers.HandlerResponse, error) {
+		response, err := fn(req)
+		if err != nil {
+			return response, err
+		}
+		if key, ok := response.Resource.(*corev2.APIKey); ok {
+			r.addLastUsed(req.Context(), []corev3.Resource{key})
+		}
+		return response, nil
+	}
+}
+
+// list adds the last time the keys were used to the listed keys.
+func (r *APIKeysRouter) list(fn ListControllerFunc) ListControllerFunc {
+	return func(ctx context.Context, pred *store.SelectionPredicate) ([]corev3.Resource, error) {
+		keys, err := fn(ctx, pred)
+		if err != nil {
+			return keys, err
+		}
+		r.addLastUsed(ctx, keys)
+		return keys, nil
+	}
+}
+
+func (r *APIKeysRouter) addLastUsed(ctx context.Context, keys []corev3.Resource) {
+	if r.usage == nil || len(keys) == 0 {
+		return
+	}
+	uses, err := r.usage.GetAPIKeyUses(ctx)
+	if err != nil {
+		logger.WithError(err).Warn("couldn't get api key uses")
+		return
+	}
+	for _, resource := range keys {
+		key, ok := resource.(*corev2.APIKey)
+		if !ok {
+			continue
+		}
+		if lastUsed, ok := uses[key.Name]; ok {
+			apikeys.SetLastUsed(key, lastUsed)
+		}
+	}
 }
 
 func (r *APIKeysRouter) create(w http.ResponseWriter, req *http.Request) {
+	if handlers.IsDryRun(req) {
+		WriteError(w, handlers.UnsupportedDryRun())
+		return
+	}
+
 	apikey, err := request.Resource[*corev2.APIKey](req)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	// the last use of the key is not stored with it
+	delete(apikey.Annotations, apikeys.LastUsedAnnotation)
+	if err := apikeys.Validate(apikey); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// the restrictions of an existing key can't be changed
+	if req.Method == http.MethodPut && apikey.Name != "" {
+		before, err := storev2.Of[*corev2.APIKey](r.store).Get(req.Context(), storev2.ID{Name: apikey.Name})
+		if err == nil {
+			if err := apikeys.ValidateUpdate(before, apikey); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		} else if _, ok := err.(*store.ErrNotFound); !ok {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
 	// validate that the user exists
 	user := &corev2.User{Username: apikey.Username}
 	storeReq := storev2.NewResourceRequestFromResource(user)
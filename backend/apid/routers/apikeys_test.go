@@ -2,13 +2,17 @@ package routers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gorilla/mux"
 	corev2 "github.com/sensu/core/v2"
+	apikeys "github.com/sensu/sensu-go/backend/authentication/apikey"
 	"github.com/sensu/sensu-go/backend/store"
 	storev2 "github.com/sensu/sensu-go/backend/store/v2"
 	"github.com/sensu/sensu-go/testing/mockstore"
@@ -21,7 +25,7 @@ func TestAPIKeysRouter(t *testing.T) {
 	s := &mockstore.V2MockStore{}
 	cs := new(mockstore.ConfigStore)
 	s.On("GetConfigStore").Return(cs)
-	router := NewAPIKeysRouter(s)
+	router := NewAPIKeysRouter(s, nil)
 	parentRouter := mux.NewRouter().PathPrefix(corev2.URLPrefix).Subrouter()
 	router.Mount(parentRouter)
 
@@ -43,7 +47,7 @@ func TestPostAPIKey(t *testing.T) {
 	s.On("GetConfigStore").Return(cs)
 	cs.On("CreateIfNotExists", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	cs.On("Get", mock.Anything, mock.Anything).Return(mockstore.Wrapper[*corev2.User]{Value: corev2.FixtureUser("admin")}, nil)
-	router := NewAPIKeysRouter(s)
+	router := NewAPIKeysRouter(s, nil)
 	parentRouter := mux.NewRouter()
 	router.Mount(parentRouter)
 	server := httptest.NewServer(parentRouter)
@@ -78,7 +82,7 @@ func TestPostAPIKeyInvalidUser(t *testing.T) {
 	cs.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	userReq := storev2.NewResourceRequestFromResource(&user)
 	cs.On("Get", mock.Anything, userReq).Return(nil, &store.ErrNotFound{})
-	router := NewAPIKeysRouter(s)
+	router := NewAPIKeysRouter(s, nil)
 	parentRouter := mux.NewRouter()
 	router.Mount(parentRouter)
 	server := httptest.NewServer(parentRouter)
@@ -103,3 +107,147 @@ func TestPostAPIKeyInvalidUser(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
 }
+
+func TestPostAPIKeyInvalidScope(t *testing.T) {
+	s := &mockstore.V2MockStore{}
+	cs := new(mockstore.ConfigStore)
+	s.On("GetConfigStore").Return(cs)
+	router := NewAPIKeysRouter(s, nil)
+	parentRouter := mux.NewRouter()
+	router.Mount(parentRouter)
+	server := httptest.NewServer(parentRouter)
+	defer server.Close()
+
+	fixture := corev2.FixtureAPIKey("226f9e06-9d54-45c6-a9f6-4206bfa7ccf6", "admin")
+	fixture.Annotations = map[string]string{
+		apikeys.RulesAnnotation: `[{"verbs":["steal"],"resources":["*"]}]`,
+	}
+	payload, err := json.Marshal(types.WrapResource(fixture))
+	assert.NoError(t, err)
+	res, err := http.Post(server.URL+"/apikeys", "application/json", bytes.NewReader(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+type apiKeyUsageStore map[string]time.Time
+
+func (s apiKeyUsageStore) RecordAPIKeyUse(ctx context.Context, name string, t time.Time) error {
+	s[name] = t
+	return nil
+}
+
+func (s apiKeyUsageStore) GetAPIKeyUses(ctx context.Context) (map[string]time.Time, error) {
+	return s, nil
+}
+
+func (s apiKeyUsageStore) PruneAPIKeyUses(ctx context.Context, keep []string) (int64, error) {
+	return 0, nil
+}
+
+func TestGetAPIKeyLastUsed(t *testing.T) {
+	s := &mockstore.V2MockStore{}
+	cs := new(mockstore.ConfigStore)
+	s.On("GetConfigStore").Return(cs)
+	fixture := corev2.FixtureAPIKey("226f9e06-9d54-45c6-a9f6-4206bfa7ccf6", "admin")
+	cs.On("Get", mock.Anything, mock.Anything).Return(mockstore.Wrapper[*corev2.APIKey]{Value: fixture}, nil)
+	lastUsed := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	router := NewAPIKeysRouter(s, apiKeyUsageStore{fixture.Name: lastUsed})
+	parentRouter := mux.NewRouter()
+	router.Mount(parentRouter)
+	server := httptest.NewServer(parentRouter)
+	defer server.Close()
+
+	res, err := http.Get(server.URL + "/apikeys/" + fixture.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Contains(t, string(body), `"sensu.io/api_key/last_used":"2024-01-01T12:00:00Z"`)
+}
+
+func TestUpdateAPIKeyScope(t *testing.T) {
+	s := &mockstore.V2MockStore{}
+	cs := new(mockstore.ConfigStore)
+	s.On("GetConfigStore").Return(cs)
+	fixture := corev2.FixtureAPIKey("226f9e06-9d54-45c6-a9f6-4206bfa7ccf6", "admin")
+	fixture.Annotations = map[string]string{
+		apikeys.NamespacesAnnotation: "ci",
+		apikeys.ExpiresAtAnnotation:  "2024-01-01T00:00:00Z",
+	}
+	isAPIKey := mock.MatchedBy(func(req storev2.ResourceRequest) bool {
+		return req.Type == "APIKey"
+	})
+	cs.On("Get", mock.Anything, isAPIKey).Return(mockstore.Wrapper[*corev2.APIKey]{Value: fixture}, nil)
+	router := NewAPIKeysRouter(s, nil)
+	parentRouter := mux.NewRouter()
+	router.Mount(parentRouter)
+	server := httptest.NewServer(parentRouter)
+	defer server.Close()
+
+	// The namespaces of an existing key can't be widened
+	update := corev2.FixtureAPIKey(fixture.Name, "admin")
+	update.Annotations = map[string]string{
+		apikeys.NamespacesAnnotation: "ci,default",
+		apikeys.ExpiresAtAnnotation:  "2024-01-01T00:00:00Z",
+	}
+	payload, err := json.Marshal(types.WrapResource(update))
+	assert.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPut, server.URL+"/apikeys", bytes.NewReader(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+	// The expiration of a key can't be removed by a patch
+	patches := []string{
+		`{"metadata":{"annotations":{"sensu.io/api_key/expires_at":null}}}`,
+		`{"metadata":{"annotations":null}}`,
+		`{"metadata":null}`,
+	}
+	for _, body := range patches {
+		req, err = http.NewRequest(http.MethodPatch, server.URL+"/apikeys/"+fixture.Name, bytes.NewReader([]byte(body)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/merge-patch+json")
+		res, err = http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode, body)
+	}
+	cs.AssertNotCalled(t, "Patch", mock.Anything, mock.Anything, mock.Anything)
+	cs.AssertNotCalled(t, "CreateOrUpdate", mock.Anything, mock.Anything, mock.Anything)
+
+	// The description of a key can be changed
+	cs.On("Patch", mock.Anything, isAPIKey, mock.Anything).Return(nil).Once()
+	body := `{"metadata":{"annotations":{"sensu.io/api_key/description":"deploys"}}}`
+	req, err = http.NewRequest(http.MethodPatch, server.URL+"/apikeys/"+fixture.Name, bytes.NewReader([]byte(body)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	res, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+	cs.AssertExpectations(t)
+}
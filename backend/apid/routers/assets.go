@@ -36,4 +36,5 @@ func (r *AssetsRouter) Mount(parent *mux.Router) {
 	routes.Post(handlers.CreateResource)
 	routes.Put(handlers.CreateOrUpdateResource)
 	routes.Del(handlers.DeleteResource)
+	routes.Revisions(handlers)
 }
@@ -0,0 +1,72 @@
+package routers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/backend/apid/handlers"
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+// defaultAuditLimit is the number of audit log entries returned when no limit
+// is specified.
+const defaultAuditLimit = 100
+
+// AuditRouter handles requests for /entries of the audit log
+type AuditRouter struct {
+	store store.AuditStore
+}
+
+// NewAuditRouter instantiates new router for querying the audit log
+func NewAuditRouter(store store.AuditStore) *AuditRouter {
+	return &AuditRouter{
+		store: store,
+	}
+}
+
+// Mount the AuditRouter to a parent Router
+func (r *AuditRouter) Mount(parent *mux.Router) {
+	routes := ResourceRoute{
+		Router:     parent,
+		PathPrefix: "/{resource:entries}",
+	}
+	routes.Path("", r.list).Methods(http.MethodGet)
+}
+
+// list returns the entries of the audit log, most recent first, filtered by
+// the user, type, namespace, name, since and until query parameters.
+func (r *AuditRouter) list(req *http.Request) (handlers.HandlerResponse, error) {
+	params := req.URL.Query()
+	query := &store.AuditQuery{
+		User:      params.Get("user"),
+		Type:      params.Get("type"),
+		Namespace: params.Get("namespace"),
+		Name:      params.Get("name"),
+		Limit:     defaultAuditLimit,
+	}
+	var err error
+	if query.Since, err = parseHistoryTime(params.Get("since")); err != nil {
+		return handlers.HandlerResponse{}, actions.NewError(actions.InvalidArgument, fmt.Errorf("invalid since: %s", err))
+	}
+	if query.Until, err = parseHistoryTime(params.Get("until")); err != nil {
+		return handlers.HandlerResponse{}, actions.NewError(actions.InvalidArgument, fmt.Errorf("invalid until: %s", err))
+	}
+	if limit := params.Get("limit"); limit != "" {
+		query.Limit, err = strconv.Atoi(limit)
+		if err != nil || query.Limit < 0 {
+			return handlers.HandlerResponse{}, actions.NewError(actions.InvalidArgument, fmt.Errorf("invalid limit: %s", limit))
+		}
+	}
+	entries, err := r.store.ListAuditEntries(req.Context(), query)
+	if err != nil {
+		return handlers.HandlerResponse{}, actions.NewError(actions.InternalErr, err)
+	}
+	if entries == nil {
+		entries = []*store.AuditEntry{}
+	}
+	// Audit entries are not resources, they are written as a plain list
+	return handlers.HandlerResponse{Payload: entries}, nil
+}
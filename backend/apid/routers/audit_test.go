@@ -0,0 +1,108 @@
+package routers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAuditStore struct {
+	query   *store.AuditQuery
+	entries []*store.AuditEntry
+	err     error
+}
+
+func (s *fakeAuditStore) RecordAuditEntry(ctx context.Context, entry *store.AuditEntry) error {
+	return errors.New("unexpected call")
+}
+
+func (s *fakeAuditStore) ListAuditEntries(ctx context.Context, query *store.AuditQuery) ([]*store.AuditEntry, error) {
+	s.query = query
+	return s.entries, s.err
+}
+
+func (s *fakeAuditStore) PruneAuditEntries(ctx context.Context, before time.Time) (int64, error) {
+	return 0, errors.New("unexpected call")
+}
+
+func TestAuditRouter(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		store          *fakeAuditStore
+		wantQuery      *store.AuditQuery
+		wantStatusCode int
+	}{
+		{
+			name: "it returns 200 and the entries",
+			path: "/api/audit/v1/entries?user=admin&type=CheckConfig&namespace=default&name=cpu&since=1700000000&until=2023-11-15T00:00:00Z&limit=10",
+			store: &fakeAuditStore{
+				entries: []*store.AuditEntry{{ID: 1, User: "admin", Verb: "create"}},
+			},
+			wantQuery: &store.AuditQuery{
+				User:      "admin",
+				Type:      "CheckConfig",
+				Namespace: "default",
+				Name:      "cpu",
+				Since:     time.Unix(1700000000, 0),
+				Until:     time.Date(2023, 11, 15, 0, 0, 0, 0, time.UTC),
+				Limit:     10,
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "it applies the default limit",
+			path:           "/api/audit/v1/entries",
+			store:          &fakeAuditStore{},
+			wantQuery:      &store.AuditQuery{Limit: defaultAuditLimit},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "it returns 400 if the time range is not valid",
+			path:           "/api/audit/v1/entries?since=yesterday",
+			store:          &fakeAuditStore{},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "it returns 400 if the limit is not valid",
+			path:           "/api/audit/v1/entries?limit=-1",
+			store:          &fakeAuditStore{},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "it returns 500 if the store returns an error",
+			path:           "/api/audit/v1/entries",
+			store:          &fakeAuditStore{err: errors.New("error")},
+			wantQuery:      &store.AuditQuery{Limit: defaultAuditLimit},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := mux.NewRouter().PathPrefix("/api/{group:audit}/{version:v1}").Subrouter()
+			NewAuditRouter(tt.store).Mount(parent)
+
+			req, err := http.NewRequest(http.MethodGet, tt.path, nil)
+			require.NoError(t, err)
+			res := httptest.NewRecorder()
+			parent.ServeHTTP(res, req)
+
+			assert.Equal(t, tt.wantStatusCode, res.Code)
+			assert.Equal(t, tt.wantQuery, tt.store.query)
+			if res.Code == http.StatusOK {
+				var entries []*store.AuditEntry
+				require.NoError(t, json.Unmarshal(res.Body.Bytes(), &entries))
+				assert.Len(t, entries, len(tt.store.entries))
+			}
+		})
+	}
+}
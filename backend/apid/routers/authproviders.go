@@ -0,0 +1,44 @@
+package routers
+
+import (
+	"github.com/gorilla/mux"
+	authv2 "github.com/sensu/sensu-go/api/authentication/v2"
+	"github.com/sensu/sensu-go/backend/apid/handlers"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+// AuthProvidersRouter handles requests for /authproviders, where each type of
+// provider has its own path
+type AuthProvidersRouter struct {
+	store storev2.Interface
+}
+
+// NewAuthProvidersRouter instantiates new router for controlling
+// authentication provider resources
+func NewAuthProvidersRouter(store storev2.Interface) *AuthProvidersRouter {
+	return &AuthProvidersRouter{
+		store: store,
+	}
+}
+
+// Mount the AuthProvidersRouter to a parent Router
+func (r *AuthProvidersRouter) Mount(parent *mux.Router) {
+	mountAuthProviders[*authv2.LDAP](parent, r.store, "ldap", authv2.LDAPFields)
+	mountAuthProviders[*authv2.OIDC](parent, r.store, "oidc", authv2.OIDCFields)
+}
+
+func mountAuthProviders[R storev2.Resource[T], T any](parent *mux.Router, store storev2.Interface, typename string, fields FieldsFunc) {
+	routes := ResourceRoute{
+		Router:     parent,
+		PathPrefix: "/{resource:authproviders}/" + typename,
+	}
+
+	handlers := handlers.NewHandlers[R](store)
+
+	routes.Del(handlers.DeleteResource)
+	routes.Get(handlers.GetResource)
+	routes.List(handlers.ListResources, fields)
+	routes.Patch(handlers.PatchResource)
+	routes.Post(handlers.CreateResource)
+	routes.Put(handlers.CreateOrUpdateResource)
+}
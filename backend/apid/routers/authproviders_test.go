@@ -0,0 +1,39 @@
+package routers
+
+import (
+	"testing"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/core/v2"
+	authv2 "github.com/sensu/sensu-go/api/authentication/v2"
+	"github.com/sensu/sensu-go/testing/mockstore"
+)
+
+func TestAuthProvidersRouter(t *testing.T) {
+	s := &mockstore.V2MockStore{}
+	cs := new(mockstore.ConfigStore)
+	s.On("GetConfigStore").Return(cs)
+	router := NewAuthProvidersRouter(s)
+	parentRouter := mux.NewRouter().PathPrefix("/api/{group:authentication}/{version:v2}").Subrouter()
+	router.Mount(parentRouter)
+
+	ldapEmpty := &authv2.LDAP{Metadata: &corev2.ObjectMeta{}}
+	ldapFixture := authv2.FixtureLDAP("foo")
+	oidcEmpty := &authv2.OIDC{Metadata: &corev2.ObjectMeta{}}
+	oidcFixture := authv2.FixtureOIDC("foo")
+
+	tests := []routerTestCase{}
+	tests = append(tests, getTestCases[*authv2.LDAP](ldapFixture)...)
+	tests = append(tests, listTestCases[*authv2.LDAP](ldapEmpty)...)
+	tests = append(tests, createTestCases(ldapFixture)...)
+	tests = append(tests, updateTestCases(ldapFixture)...)
+	tests = append(tests, deleteTestCases(ldapFixture)...)
+	tests = append(tests, getTestCases[*authv2.OIDC](oidcFixture)...)
+	tests = append(tests, listTestCases[*authv2.OIDC](oidcEmpty)...)
+	tests = append(tests, createTestCases(oidcFixture)...)
+	tests = append(tests, updateTestCases(oidcFixture)...)
+	tests = append(tests, deleteTestCases(oidcFixture)...)
+	for _, tt := range tests {
+		run(t, tt, parentRouter, s)
+	}
+}
@@ -55,6 +55,7 @@ func (r *ChecksRouter) Mount(parent *mux.Router) {
 	routes.Patch(handlers.PatchResource)
 	routes.Post(handlers.CreateResource)
 	routes.Put(handlers.CreateOrUpdateResource)
+	routes.Revisions(handlers)
 
 	// Custom
 	routes.Path("{id}/hooks/{type}", r.addCheckHook).Methods(http.MethodPut)
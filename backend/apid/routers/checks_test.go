@@ -86,6 +86,7 @@ func TestChecksRouter(t *testing.T) {
 	tests = append(tests, createTestCases(fixture)...)
 	tests = append(tests, updateTestCases(fixture)...)
 	tests = append(tests, deleteTestCases(fixture)...)
+	tests = append(tests, revisionTestCases(fixture)...)
 	for _, tt := range tests {
 		run(t, tt, parentRouter, s)
 	}
@@ -35,4 +35,5 @@ func (r *ClusterRoleBindingsRouter) Mount(parent *mux.Router) {
 	routes.Patch(handlers.PatchResource)
 	routes.Post(handlers.CreateResource)
 	routes.Put(handlers.CreateOrUpdateResource)
+	routes.Revisions(handlers)
 }
@@ -10,6 +10,7 @@ import (
 	corev2 "github.com/sensu/core/v2"
 	corev3 "github.com/sensu/core/v3"
 	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/backend/apid/filters/fields"
 	"github.com/sensu/sensu-go/backend/apid/handlers"
 	"github.com/sensu/sensu-go/backend/apid/request"
 	"github.com/sensu/sensu-go/backend/store"
@@ -61,8 +62,8 @@ func (r *EntitiesRouter) Mount(parent *mux.Router) {
 
 	routes.Del(deleter.Delete)
 	routes.Get(r.find)
-	routes.List(r.controller.List, corev3.EntityFields)
-	routes.ListAllNamespaces(r.controller.List, "/{resource:entities}", corev3.EntityFields)
+	routes.List(r.controller.List, fields.EntityFields)
+	routes.ListAllNamespaces(r.controller.List, "/{resource:entities}", fields.EntityFields)
 	routes.Patch(ecHandlers.PatchResource)
 	routes.Post(r.create)
 	routes.Put(r.createOrReplace)
@@ -93,6 +94,9 @@ func (r *EntitiesRouter) create(req *http.Request) (handlers.HandlerResponse, er
 	if err != nil {
 		return response, err
 	}
+	if handlers.IsDryRun(req) {
+		return dryRunResponse(req, r.store, entity)
+	}
 	err = r.controller.Create(req.Context(), *entity)
 	return responseWrap(entity, err)
 }
@@ -107,6 +111,9 @@ func (r *EntitiesRouter) createOrReplace(req *http.Request) (handlers.HandlerRes
 	if entity.Labels[corev2.ManagedByLabel] == "sensu-agent" {
 		return response, actions.NewError(actions.AlreadyExistsErr, errors.New("entity is managed by its agent"))
 	}
+	if handlers.IsDryRun(req) {
+		return dryRunResponse(req, r.store, entity)
+	}
 
 	return responseWrap(entity, r.controller.CreateOrReplace(req.Context(), *entity))
 }
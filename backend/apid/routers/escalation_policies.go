@@ -0,0 +1,40 @@
+package routers
+
+import (
+	"github.com/gorilla/mux"
+	pipelinev1 "github.com/sensu/sensu-go/api/pipeline/v1"
+	"github.com/sensu/sensu-go/backend/apid/handlers"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+// EscalationPoliciesRouter handles requests for /escalation_policies
+type EscalationPoliciesRouter struct {
+	store storev2.Interface
+}
+
+// NewEscalationPoliciesRouter instantiates new router for controlling
+// escalation policy resources
+func NewEscalationPoliciesRouter(store storev2.Interface) *EscalationPoliciesRouter {
+	return &EscalationPoliciesRouter{
+		store: store,
+	}
+}
+
+// Mount the EscalationPoliciesRouter to a parent Router
+func (r *EscalationPoliciesRouter) Mount(parent *mux.Router) {
+	routes := ResourceRoute{
+		Router:     parent,
+		PathPrefix: "/namespaces/{namespace}/{resource:escalation_policies}",
+	}
+
+	handlers := handlers.NewHandlers[*pipelinev1.EscalationPolicy](r.store)
+
+	routes.Get(handlers.GetResource)
+	routes.List(handlers.ListResources, pipelinev1.EscalationPolicyFields)
+	routes.ListAllNamespaces(handlers.ListResources, "/{resource:escalation_policies}", pipelinev1.EscalationPolicyFields)
+	routes.Patch(handlers.PatchResource)
+	routes.Post(handlers.CreateResource)
+	routes.Put(handlers.CreateOrUpdateResource)
+	routes.Del(handlers.DeleteResource)
+	routes.Revisions(handlers)
+}
@@ -0,0 +1,70 @@
+package routers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	pipelinev1 "github.com/sensu/sensu-go/api/pipeline/v1"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEscalationPoliciesRouter(t *testing.T) {
+	s := &mockstore.V2MockStore{}
+	cs := new(mockstore.ConfigStore)
+	s.On("GetConfigStore").Return(cs)
+	router := NewEscalationPoliciesRouter(s)
+	parentRouter := mux.NewRouter().PathPrefix("/api/{group:pipeline}/{version:v1}").Subrouter()
+	router.Mount(parentRouter)
+	server := httptest.NewServer(parentRouter)
+	defer server.Close()
+
+	// The steps of a policy reach the store as they were sent
+	policy := pipelinev1.FixtureEscalationPolicy("oncall")
+	policy.Repeat = &pipelinev1.EscalationRepeat{Count: 2, Interval: 3600}
+	var stored *pipelinev1.EscalationPolicy
+	cs.On("CreateOrUpdate", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		req := args.Get(1).(storev2.ResourceRequest)
+		assert.Equal(t, "default", req.Namespace)
+		assert.Equal(t, "oncall", req.Name)
+		resource, err := args.Get(2).(storev2.Wrapper).Unwrap()
+		require.NoError(t, err)
+		stored = resource.(*pipelinev1.EscalationPolicy)
+	}).Return(nil).Once()
+	req, err := http.NewRequest(http.MethodPut, server.URL+policy.URIPath(), bytes.NewReader(marshalWrapped(policy)))
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.NotNil(t, stored)
+	assert.Equal(t, policy.Steps, stored.Steps)
+	assert.Equal(t, policy.Repeat, stored.Repeat)
+
+	// The policies of every namespace are listed under the pipeline/v1 group
+	cs.On("List", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		assert.Equal(t, "", args.Get(1).(storev2.ResourceRequest).Namespace)
+	}).Return(mockstore.WrapList[*pipelinev1.EscalationPolicy]{policy}, nil).Once()
+	resp, err = http.Get(server.URL + "/api/pipeline/v1/escalation_policies")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var policies []struct {
+		Type       string                      `json:"type"`
+		APIVersion string                      `json:"api_version"`
+		Spec       pipelinev1.EscalationPolicy `json:"spec"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&policies))
+	if assert.Len(t, policies, 1) {
+		assert.Equal(t, "EscalationPolicy", policies[0].Type)
+		assert.Equal(t, "pipeline/v1", policies[0].APIVersion)
+		assert.Equal(t, policy.Steps, policies[0].Spec.Steps)
+	}
+}
@@ -2,9 +2,12 @@ package routers
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/url"
 	"path"
+	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 	corev2 "github.com/sensu/core/v2"
@@ -27,6 +30,7 @@ type eventController interface {
 	CreateOrReplace(ctx context.Context, check *corev2.Event) error
 	Delete(ctx context.Context, entity, check string) error
 	Get(ctx context.Context, entity, check string) (*corev2.Event, error)
+	History(ctx context.Context, entity, check string, start, end time.Time) ([]*store.EventHistoryEntry, error)
 	List(ctx context.Context, pred *store.SelectionPredicate) ([]corev3.Resource, error)
 }
 
@@ -48,6 +52,7 @@ func (r *EventsRouter) Mount(parent *mux.Router) {
 	routes.List(r.controller.List, corev3.EventFields)
 	routes.ListAllNamespaces(r.controller.List, "/{resource:events}", corev3.EventFields)
 	routes.Path("{entity}/{check}", r.get).Methods(http.MethodGet)
+	routes.Path("{entity}/{check}/history", r.history).Methods(http.MethodGet)
 	routes.Path("{entity}/{check}", r.delete).Methods(http.MethodDelete)
 	routes.Path("{entity}/{check}", r.createOrReplace).Methods(http.MethodPost, http.MethodPut)
 
@@ -66,6 +71,43 @@ func (r *EventsRouter) get(req *http.Request) (handlers.HandlerResponse, error)
 	return response, err
 }
 
+func (r *EventsRouter) history(req *http.Request) (handlers.HandlerResponse, error) {
+	params := actions.QueryParams(mux.Vars(req))
+	entity := url.PathEscape(params["entity"])
+	check := url.PathEscape(params["check"])
+	query := req.URL.Query()
+	start, err := parseHistoryTime(query.Get("start"))
+	if err != nil {
+		return handlers.HandlerResponse{}, actions.NewError(actions.InvalidArgument, fmt.Errorf("invalid start: %s", err))
+	}
+	end, err := parseHistoryTime(query.Get("end"))
+	if err != nil {
+		return handlers.HandlerResponse{}, actions.NewError(actions.InvalidArgument, fmt.Errorf("invalid end: %s", err))
+	}
+	entries, err := r.controller.History(req.Context(), entity, check, start, end)
+	if err != nil {
+		return handlers.HandlerResponse{}, err
+	}
+	if entries == nil {
+		entries = []*store.EventHistoryEntry{}
+	}
+	// History entries are not resources, they are written as a plain list
+	return handlers.HandlerResponse{Payload: entries}, nil
+}
+
+// parseHistoryTime parses a bound of the time range of the event history,
+// expressed either in RFC 3339 format or in seconds since the epoch. An empty
+// string returns the zero time, which leaves the bound open.
+func parseHistoryTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
 func (r *EventsRouter) delete(req *http.Request) (handlers.HandlerResponse, error) {
 	params := actions.QueryParams(mux.Vars(req))
 	entity := url.PathEscape(params["entity"])
@@ -84,6 +126,9 @@ func (r *EventsRouter) create(req *http.Request) (handlers.HandlerResponse, erro
 	if err := validateEventPayload(event, vars); err != nil {
 		return response, err
 	}
+	if handlers.IsDryRun(req) {
+		return dryRunResponse(req, nil, event)
+	}
 
 	err = r.controller.CreateOrReplace(req.Context(), event)
 	return response, err
@@ -100,6 +145,9 @@ func (r *EventsRouter) createOrReplace(req *http.Request) (handlers.HandlerRespo
 	if err := validateEventPayload(event, vars); err != nil {
 		return response, err
 	}
+	if handlers.IsDryRun(req) {
+		return dryRunResponse(req, nil, event)
+	}
 
 	err = r.controller.CreateOrReplace(req.Context(), event)
 	return response, err
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gorilla/mux"
 	corev2 "github.com/sensu/core/v2"
@@ -33,6 +34,11 @@ func (m *mockEventController) Get(ctx context.Context, entity, check string) (*c
 	return args.Get(0).(*corev2.Event), args.Error(1)
 }
 
+func (m *mockEventController) History(ctx context.Context, entity, check string, start, end time.Time) ([]*store.EventHistoryEntry, error) {
+	args := m.Called(ctx, entity, check, start, end)
+	return args.Get(0).([]*store.EventHistoryEntry), args.Error(1)
+}
+
 func (m *mockEventController) List(ctx context.Context, pred *store.SelectionPredicate) ([]corev3.Resource, error) {
 	args := m.Called(ctx, pred)
 	return args.Get(0).([]corev3.Resource), args.Error(1)
@@ -89,6 +95,36 @@ func TestEventsRouter(t *testing.T) {
 			},
 			wantStatusCode: http.StatusOK,
 		},
+		{
+			name:   "it returns 200 and the event history",
+			method: http.MethodGet,
+			path:   fixture.URIPath() + "/history?start=1700000000&end=2023-11-15T00:00:00Z",
+			controllerFunc: func(c *mockEventController) {
+				start := time.Unix(1700000000, 0)
+				end := time.Date(2023, 11, 15, 0, 0, 0, 0, time.UTC)
+				c.On("History", mock.Anything, "foo", "check-cpu", start, end).
+					Return([]*store.EventHistoryEntry{{Entity: "foo", Check: "check-cpu", Status: 2}}, nil).
+					Once()
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "it returns 400 if the history time range is not valid",
+			method:         http.MethodGet,
+			path:           fixture.URIPath() + "/history?start=yesterday",
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "it returns 500 if the store encounters an error while getting the event history",
+			method: http.MethodGet,
+			path:   fixture.URIPath() + "/history",
+			controllerFunc: func(c *mockEventController) {
+				c.On("History", mock.Anything, "foo", "check-cpu", time.Time{}, time.Time{}).
+					Return([]*store.EventHistoryEntry(nil), actions.NewErrorf(actions.InternalErr)).
+					Once()
+			},
+			wantStatusCode: http.StatusInternalServerError,
+		},
 		{
 			name:   "it returns 500 if the store encounters an error while listing events",
 			method: http.MethodGet,
@@ -36,4 +36,5 @@ func (r *EventFiltersRouter) Mount(parent *mux.Router) {
 	routes.Patch(handlers.PatchResource)
 	routes.Post(handlers.CreateResource)
 	routes.Put(handlers.CreateOrUpdateResource)
+	routes.Revisions(handlers)
 }
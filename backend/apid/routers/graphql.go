@@ -21,17 +21,28 @@ var (
 
 type GraphQLService interface {
 	Do(context.Context, graphql.QueryParams) *graphql.Result
+	Subscribe(context.Context, graphql.QueryParams) chan *graphql.Result
 }
 
 // GraphQLRouter handles requests for /events
 type GraphQLRouter struct {
 	Service GraphQLService
 	Timeout time.Duration
+
+	// Authenticate authenticates the access token or API key given by the
+	// clients of the subscriptions when they initialize the connection.
+	Authenticate func(ctx context.Context, authorization string) (context.Context, error)
+
+	// AllowedOrigins are the origins, besides the origin of the API, from
+	// which browsers are allowed to open subscription connections, or "*"
+	// to allow any origin.
+	AllowedOrigins []string
 }
 
 // Mount the GraphQLRouter to a parent Router
 func (r *GraphQLRouter) Mount(parent *mux.Router) {
 	parent.HandleFunc("/graphql", r.query).Methods(http.MethodPost)
+	parent.HandleFunc("/graphql", r.subscribe).Methods(http.MethodGet)
 }
 
 func (r *GraphQLRouter) query(w http.ResponseWriter, req *http.Request) {
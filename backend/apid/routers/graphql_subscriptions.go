@@ -0,0 +1,312 @@
+package routers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/authentication/jwt"
+	"github.com/sensu/sensu-go/graphql"
+)
+
+// graphqlWSProtocol is the WebSocket subprotocol spoken by the subscription
+// endpoint, as specified by
+// https://github.com/enisdenjo/graphql-ws/blob/master/PROTOCOL.md
+const graphqlWSProtocol = "graphql-transport-ws"
+
+// Message types of the graphql-transport-ws protocol
+const (
+	graphqlWSConnectionInit = "connection_init"
+	graphqlWSConnectionAck  = "connection_ack"
+	graphqlWSPing           = "ping"
+	graphqlWSPong           = "pong"
+	graphqlWSSubscribe      = "subscribe"
+	graphqlWSNext           = "next"
+	graphqlWSError          = "error"
+	graphqlWSComplete       = "complete"
+)
+
+// Close codes of the graphql-transport-ws protocol
+const (
+	graphqlWSCloseBadRequest         = 4400
+	graphqlWSCloseUnauthorized       = 4401
+	graphqlWSCloseForbidden          = 4403
+	graphqlWSCloseInitTimeout        = 4408
+	graphqlWSCloseSubscriberConflict = 4409
+	graphqlWSCloseTooManyInits       = 4429
+)
+
+// graphqlWSInitTimeout is the time given to clients to initialize the
+// connection once it has been upgraded.
+var graphqlWSInitTimeout = 10 * time.Second
+
+type graphqlWSMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+type graphqlWSSubscribePayload struct {
+	OperationName string                 `json:"operationName"`
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// graphqlWSConn is a WebSocket connection serving GraphQL subscriptions.
+type graphqlWSConn struct {
+	conn         *websocket.Conn
+	service      GraphQLService
+	authenticate func(context.Context, string) (context.Context, error)
+	writeMu      sync.Mutex
+
+	mu         sync.Mutex
+	operations map[string]context.CancelFunc
+	wg         sync.WaitGroup
+}
+
+func (r *GraphQLRouter) subscribe(w http.ResponseWriter, req *http.Request) {
+	upgrader := websocket.Upgrader{
+		Subprotocols: []string{graphqlWSProtocol},
+		CheckOrigin:  r.checkOrigin,
+	}
+	conn, err := upgrader.Upgrade(w, req, nil)
+	if err != nil {
+		// The upgrader has already replied with an HTTP error
+		logger.WithError(err).Warn("couldn't upgrade graphql subscription connection")
+		return
+	}
+	defer conn.Close()
+	if conn.Subprotocol() != graphqlWSProtocol {
+		closeGraphQLWS(conn, websocket.CloseProtocolError, "unsupported subprotocol")
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.WithValue(req.Context(), corev2.NamespaceKey, ""))
+	defer cancel()
+
+	c := &graphqlWSConn{
+		conn:         conn,
+		service:      r.Service,
+		authenticate: r.Authenticate,
+		operations:   make(map[string]context.CancelFunc),
+	}
+	c.serve(ctx)
+	cancel()
+	c.wg.Wait()
+}
+
+// checkOrigin permits the upgrade requests of clients that aren't browsers,
+// which don't send an Origin header, and the requests of browsers from the
+// origin of the API or from one of the allowed origins. Other cross-origin
+// requests are refused, so that other sites can't open subscriptions with the
+// credentials of their visitors.
+func (r *GraphQLRouter) checkOrigin(req *http.Request) bool {
+	origin := req.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	if strings.EqualFold(u.Host, req.Host) {
+		return true
+	}
+	for _, allowed := range r.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// serve reads and handles the messages of the connection until it is closed.
+func (c *graphqlWSConn) serve(ctx context.Context) {
+	initTimer := time.AfterFunc(graphqlWSInitTimeout, func() {
+		c.close(graphqlWSCloseInitTimeout, "connection initialisation timeout")
+	})
+	defer initTimer.Stop()
+
+	var (
+		initialized bool
+		expiryTimer *time.Timer
+	)
+	defer func() {
+		if expiryTimer != nil {
+			expiryTimer.Stop()
+		}
+	}()
+	for {
+		var msg graphqlWSMessage
+		if err := c.conn.ReadJSON(&msg); err != nil {
+			if _, ok := err.(*json.SyntaxError); ok {
+				c.close(graphqlWSCloseBadRequest, "invalid message")
+			}
+			return
+		}
+
+		switch msg.Type {
+		case graphqlWSConnectionInit:
+			if initialized {
+				c.close(graphqlWSCloseTooManyInits, "too many initialisation requests")
+				return
+			}
+			initTimer.Stop()
+			authCtx, ok := c.authenticateInit(ctx, msg.Payload)
+			if !ok {
+				c.close(graphqlWSCloseForbidden, "forbidden")
+				return
+			}
+			ctx = authCtx
+			initialized = true
+			// Close the connection once the credentials expire, so that the
+			// client reconnects with fresh credentials
+			if claims := jwt.GetClaimsFromContext(ctx); claims != nil && claims.ExpiresAt > 0 {
+				expiryTimer = time.AfterFunc(time.Until(time.Unix(claims.ExpiresAt, 0)), func() {
+					c.close(graphqlWSCloseUnauthorized, "credentials expired")
+				})
+			}
+			if err := c.write(graphqlWSMessage{Type: graphqlWSConnectionAck}); err != nil {
+				return
+			}
+
+		case graphqlWSPing:
+			if err := c.write(graphqlWSMessage{Type: graphqlWSPong}); err != nil {
+				return
+			}
+
+		case graphqlWSPong:
+
+		case graphqlWSSubscribe:
+			if !initialized {
+				c.close(graphqlWSCloseUnauthorized, "unauthorized")
+				return
+			}
+			var payload graphqlWSSubscribePayload
+			if msg.ID == "" || json.Unmarshal(msg.Payload, &payload) != nil {
+				c.close(graphqlWSCloseBadRequest, "invalid subscribe message")
+				return
+			}
+			if !c.start(ctx, msg.ID, payload) {
+				c.close(graphqlWSCloseSubscriberConflict, fmt.Sprintf("subscriber for %s already exists", msg.ID))
+				return
+			}
+
+		case graphqlWSComplete:
+			c.stop(msg.ID)
+
+		default:
+			c.close(graphqlWSCloseBadRequest, fmt.Sprintf("unexpected message type %q", msg.Type))
+			return
+		}
+	}
+}
+
+// start executes the subscription identified by id, sending each of its
+// results to the client. It returns false if the id is already in use.
+func (c *graphqlWSConn) start(ctx context.Context, id string, payload graphqlWSSubscribePayload) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.operations[id]; ok {
+		return false
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	c.operations[id] = cancel
+
+	results := c.service.Subscribe(ctx, graphql.QueryParams{
+		OperationName: payload.OperationName,
+		Query:         payload.Query,
+		Variables:     payload.Variables,
+		IsAuthed:      jwt.GetClaimsFromContext(ctx) != nil,
+	})
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		defer c.stop(id)
+		var failed bool
+		// The results must be drained until the channel is closed
+		for result := range results {
+			if failed || ctx.Err() != nil {
+				continue
+			}
+			var msg graphqlWSMessage
+			if result.Data == nil && result.HasErrors() {
+				// The operation could not be executed
+				failed = true
+				data, _ := json.Marshal(result.Errors)
+				msg = graphqlWSMessage{ID: id, Type: graphqlWSError, Payload: data}
+			} else {
+				data, _ := json.Marshal(result)
+				msg = graphqlWSMessage{ID: id, Type: graphqlWSNext, Payload: data}
+			}
+			if err := c.write(msg); err != nil {
+				failed = true
+			}
+		}
+		if !failed && ctx.Err() == nil {
+			_ = c.write(graphqlWSMessage{ID: id, Type: graphqlWSComplete})
+		}
+	}()
+	return true
+}
+
+// stop cancels the subscription identified by id, if it is running.
+func (c *graphqlWSConn) stop(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cancel, ok := c.operations[id]; ok {
+		cancel()
+		delete(c.operations, id)
+	}
+}
+
+func (c *graphqlWSConn) write(msg graphqlWSMessage) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return c.conn.WriteJSON(msg)
+}
+
+func (c *graphqlWSConn) close(code int, reason string) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	closeGraphQLWS(c.conn, code, reason)
+}
+
+func closeGraphQLWS(conn *websocket.Conn, code int, reason string) {
+	msg := websocket.FormatCloseMessage(code, reason)
+	_ = conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(time.Second))
+	_ = conn.Close()
+}
+
+// authenticateInit returns the context of the connection with the claims of
+// the user. The user is either authenticated by the Authentication middleware,
+// from the headers of the upgrade request, or by the access token or API key
+// given in the "Authorization" field of the connection_init payload, since
+// browsers can't set headers on WebSocket requests.
+func (c *graphqlWSConn) authenticateInit(ctx context.Context, payload json.RawMessage) (context.Context, bool) {
+	if claims := jwt.GetClaimsFromContext(ctx); claims != nil {
+		return ctx, true
+	}
+	var params struct {
+		Authorization string `json:"Authorization"`
+	}
+	if len(payload) > 0 {
+		_ = json.Unmarshal(payload, &params)
+	}
+	if params.Authorization == "" || c.authenticate == nil {
+		return ctx, false
+	}
+	authCtx, err := c.authenticate(ctx, params.Authorization)
+	if err != nil || jwt.GetClaimsFromContext(authCtx) == nil {
+		return ctx, false
+	}
+	return authCtx, true
+}
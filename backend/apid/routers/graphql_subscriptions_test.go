@@ -0,0 +1,243 @@
+package routers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type subscriptionService struct {
+	results chan *graphql.Result
+	params  chan graphql.QueryParams
+}
+
+func (s *subscriptionService) Do(context.Context, graphql.QueryParams) *graphql.Result {
+	return &graphql.Result{}
+}
+
+func (s *subscriptionService) Subscribe(ctx context.Context, p graphql.QueryParams) chan *graphql.Result {
+	s.params <- p
+	out := make(chan *graphql.Result)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case result, ok := <-s.results:
+				if !ok {
+					return
+				}
+				out <- result
+			}
+		}
+	}()
+	return out
+}
+
+func newSubscriptionServer(t *testing.T, service GraphQLService, authenticated bool) *websocket.Conn {
+	t.Helper()
+	conn, _, err := dialSubscriptionServer(t, &GraphQLRouter{Service: service}, authenticated, nil)
+	require.NoError(t, err)
+	return conn
+}
+
+func dialSubscriptionServer(t *testing.T, router *GraphQLRouter, authenticated bool, header http.Header) (*websocket.Conn, *http.Response, error) {
+	t.Helper()
+	parent := mux.NewRouter()
+	router.Mount(parent)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if authenticated {
+			claims := corev2.FixtureClaims("legit", nil)
+			r = r.WithContext(context.WithValue(r.Context(), corev2.ClaimsKey, claims))
+		}
+		parent.ServeHTTP(w, r)
+	})
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	dialer := websocket.Dialer{Subprotocols: []string{graphqlWSProtocol}}
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/graphql"
+	conn, resp, err := dialer.Dial(url, header)
+	if err != nil {
+		return nil, resp, err
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	return conn, resp, nil
+}
+
+func readGraphQLWS(t *testing.T, conn *websocket.Conn) graphqlWSMessage {
+	t.Helper()
+	var msg graphqlWSMessage
+	require.NoError(t, conn.ReadJSON(&msg))
+	return msg
+}
+
+func TestGraphQLSubscription(t *testing.T) {
+	service := &subscriptionService{
+		results: make(chan *graphql.Result),
+		params:  make(chan graphql.QueryParams, 1),
+	}
+	conn := newSubscriptionServer(t, service, true)
+
+	require.NoError(t, conn.WriteJSON(graphqlWSMessage{Type: graphqlWSConnectionInit}))
+	assert.Equal(t, graphqlWSConnectionAck, readGraphQLWS(t, conn).Type)
+
+	require.NoError(t, conn.WriteJSON(graphqlWSMessage{Type: graphqlWSPing}))
+	assert.Equal(t, graphqlWSPong, readGraphQLWS(t, conn).Type)
+
+	payload, _ := json.Marshal(graphqlWSSubscribePayload{Query: "subscription { eventUpdated }"})
+	require.NoError(t, conn.WriteJSON(graphqlWSMessage{ID: "1", Type: graphqlWSSubscribe, Payload: payload}))
+	params := <-service.params
+	assert.Equal(t, "subscription { eventUpdated }", params.Query)
+	assert.True(t, params.IsAuthed)
+
+	service.results <- &graphql.Result{Data: map[string]interface{}{"eventUpdated": "foo"}}
+	msg := readGraphQLWS(t, conn)
+	assert.Equal(t, graphqlWSNext, msg.Type)
+	assert.Equal(t, "1", msg.ID)
+	assert.JSONEq(t, `{"data":{"eventUpdated":"foo"}}`, string(msg.Payload))
+
+	close(service.results)
+	msg = readGraphQLWS(t, conn)
+	assert.Equal(t, graphqlWSComplete, msg.Type)
+	assert.Equal(t, "1", msg.ID)
+}
+
+func TestGraphQLSubscriptionSubscriberConflict(t *testing.T) {
+	service := &subscriptionService{
+		results: make(chan *graphql.Result),
+		params:  make(chan graphql.QueryParams, 2),
+	}
+	conn := newSubscriptionServer(t, service, true)
+
+	require.NoError(t, conn.WriteJSON(graphqlWSMessage{Type: graphqlWSConnectionInit}))
+	assert.Equal(t, graphqlWSConnectionAck, readGraphQLWS(t, conn).Type)
+
+	payload, _ := json.Marshal(graphqlWSSubscribePayload{Query: "subscription { eventUpdated }"})
+	for i := 0; i < 2; i++ {
+		require.NoError(t, conn.WriteJSON(graphqlWSMessage{ID: "1", Type: graphqlWSSubscribe, Payload: payload}))
+	}
+	var msg graphqlWSMessage
+	err := conn.ReadJSON(&msg)
+	assert.True(t, websocket.IsCloseError(err, graphqlWSCloseSubscriberConflict), "unexpected error: %v", err)
+}
+
+func TestGraphQLSubscriptionUnauthenticated(t *testing.T) {
+	service := &subscriptionService{
+		results: make(chan *graphql.Result),
+		params:  make(chan graphql.QueryParams, 1),
+	}
+	conn := newSubscriptionServer(t, service, false)
+
+	require.NoError(t, conn.WriteJSON(graphqlWSMessage{Type: graphqlWSConnectionInit}))
+	var msg graphqlWSMessage
+	err := conn.ReadJSON(&msg)
+	assert.True(t, websocket.IsCloseError(err, graphqlWSCloseForbidden), "unexpected error: %v", err)
+}
+
+func TestGraphQLSubscriptionNotInitialized(t *testing.T) {
+	service := &subscriptionService{
+		results: make(chan *graphql.Result),
+		params:  make(chan graphql.QueryParams, 1),
+	}
+	conn := newSubscriptionServer(t, service, true)
+
+	payload, _ := json.Marshal(graphqlWSSubscribePayload{Query: "subscription { eventUpdated }"})
+	require.NoError(t, conn.WriteJSON(graphqlWSMessage{ID: "1", Type: graphqlWSSubscribe, Payload: payload}))
+	var msg graphqlWSMessage
+	err := conn.ReadJSON(&msg)
+	assert.True(t, websocket.IsCloseError(err, graphqlWSCloseUnauthorized), "unexpected error: %v", err)
+}
+
+func TestGraphQLSubscriptionOrigin(t *testing.T) {
+	service := &subscriptionService{
+		results: make(chan *graphql.Result),
+		params:  make(chan graphql.QueryParams, 1),
+	}
+	router := &GraphQLRouter{Service: service, AllowedOrigins: []string{"https://dashboard.example.com/"}}
+
+	header := http.Header{}
+	header.Set("Origin", "https://attacker.example.com")
+	_, resp, err := dialSubscriptionServer(t, router, true, header)
+	require.Error(t, err)
+	require.NotNil(t, resp)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	header.Set("Origin", "https://dashboard.example.com")
+	_, _, err = dialSubscriptionServer(t, router, true, header)
+	assert.NoError(t, err)
+
+	// requests without an Origin header don't come from browsers
+	_, _, err = dialSubscriptionServer(t, router, true, nil)
+	assert.NoError(t, err)
+}
+
+func TestGraphQLSubscriptionAuthenticateInit(t *testing.T) {
+	service := &subscriptionService{
+		results: make(chan *graphql.Result),
+		params:  make(chan graphql.QueryParams, 1),
+	}
+	router := &GraphQLRouter{
+		Service: service,
+		Authenticate: func(ctx context.Context, authorization string) (context.Context, error) {
+			if authorization != "Key legit" {
+				return ctx, errors.New("API key rejected")
+			}
+			return context.WithValue(ctx, corev2.ClaimsKey, corev2.FixtureClaims("legit", nil)), nil
+		},
+	}
+
+	conn, _, err := dialSubscriptionServer(t, router, false, nil)
+	require.NoError(t, err)
+	payload, _ := json.Marshal(map[string]string{"Authorization": "Key legit"})
+	require.NoError(t, conn.WriteJSON(graphqlWSMessage{Type: graphqlWSConnectionInit, Payload: payload}))
+	assert.Equal(t, graphqlWSConnectionAck, readGraphQLWS(t, conn).Type)
+
+	conn, _, err = dialSubscriptionServer(t, router, false, nil)
+	require.NoError(t, err)
+	payload, _ = json.Marshal(map[string]string{"Authorization": "Key bogus"})
+	require.NoError(t, conn.WriteJSON(graphqlWSMessage{Type: graphqlWSConnectionInit, Payload: payload}))
+	var msg graphqlWSMessage
+	err = conn.ReadJSON(&msg)
+	assert.True(t, websocket.IsCloseError(err, graphqlWSCloseForbidden), "unexpected error: %v", err)
+}
+
+func TestGraphQLSubscriptionCredentialsExpiry(t *testing.T) {
+	service := &subscriptionService{
+		results: make(chan *graphql.Result),
+		params:  make(chan graphql.QueryParams, 1),
+	}
+	router := &GraphQLRouter{
+		Service: service,
+		Authenticate: func(ctx context.Context, authorization string) (context.Context, error) {
+			claims := corev2.FixtureClaims("legit", nil)
+			claims.ExpiresAt = time.Now().Add(time.Second).Unix()
+			return context.WithValue(ctx, corev2.ClaimsKey, claims), nil
+		},
+	}
+
+	conn, _, err := dialSubscriptionServer(t, router, false, nil)
+	require.NoError(t, err)
+	payload, _ := json.Marshal(map[string]string{"Authorization": "Bearer token"})
+	require.NoError(t, conn.WriteJSON(graphqlWSMessage{Type: graphqlWSConnectionInit, Payload: payload}))
+	assert.Equal(t, graphqlWSConnectionAck, readGraphQLWS(t, conn).Type)
+
+	// the connection is closed once the credentials expire
+	var msg graphqlWSMessage
+	err = conn.ReadJSON(&msg)
+	assert.True(t, websocket.IsCloseError(err, graphqlWSCloseUnauthorized), "unexpected error: %v", err)
+}
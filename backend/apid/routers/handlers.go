@@ -34,4 +34,5 @@ func (r *HandlersRouter) Mount(parent *mux.Router) {
 	routes.Patch(handlers.PatchResource)
 	routes.Post(handlers.CreateResource)
 	routes.Put(handlers.CreateOrUpdateResource)
+	routes.Revisions(handlers)
 }
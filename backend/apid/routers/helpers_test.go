@@ -128,7 +128,7 @@ func getResourceSuccessTestCase[R storev2.Resource[T], T any](resource corev3.Re
 		storeFunc: func(s *mockstore.V2MockStore) {
 			cs := s.GetConfigStore().(*mockstore.ConfigStore)
 			cs.On("Get", mock.Anything, mock.Anything).
-				Return(mockstore.Wrapper[R]{resource.(R)}, nil).
+				Return(mockstore.Wrapper[R]{Value: resource.(R)}, nil).
 				Once()
 		},
 		wantStatusCode: http.StatusOK,
@@ -500,6 +500,75 @@ func deleteResourceSuccessTestCase(resource corev3.Resource) routerTestCase {
 	}
 }
 
+// Revisions
+func revisionTestCases(resource corev3.Resource) []routerTestCase {
+	revision := &storev2.Revision{
+		Revision:  1,
+		Operation: storev2.RevisionCreate,
+		Resource:  marshalRaw(resource),
+	}
+	return []routerTestCase{
+		{
+			name:   "it lists the revisions of a resource",
+			method: http.MethodGet,
+			path:   resource.URIPath() + "/history",
+			storeFunc: func(s *mockstore.V2MockStore) {
+				hs := new(mockstore.ConfigHistoryStore)
+				hs.On("ListRevisions", mock.Anything, mock.Anything).
+					Return([]*storev2.Revision{revision}, nil)
+				s.On("GetConfigHistoryStore").Return(hs).Once()
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:   "it returns 404 if a resource has no revisions",
+			method: http.MethodGet,
+			path:   resource.URIPath() + "/history",
+			storeFunc: func(s *mockstore.V2MockStore) {
+				hs := new(mockstore.ConfigHistoryStore)
+				hs.On("ListRevisions", mock.Anything, mock.Anything).
+					Return([]*storev2.Revision{}, nil)
+				s.On("GetConfigHistoryStore").Return(hs).Once()
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:   "it compares two revisions of a resource",
+			method: http.MethodGet,
+			path:   resource.URIPath() + "/history/diff?from=1&to=1",
+			storeFunc: func(s *mockstore.V2MockStore) {
+				hs := new(mockstore.ConfigHistoryStore)
+				hs.On("GetRevision", mock.Anything, mock.Anything, int64(1)).
+					Return(revision, nil)
+				s.On("GetConfigHistoryStore").Return(hs).Twice()
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "it returns 400 if the revision to roll back to is missing",
+			method:         http.MethodPut,
+			path:           resource.URIPath() + "/rollback",
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "it rolls back a resource",
+			method: http.MethodPut,
+			path:   resource.URIPath() + "/rollback?revision=1",
+			storeFunc: func(s *mockstore.V2MockStore) {
+				hs := new(mockstore.ConfigHistoryStore)
+				hs.On("GetRevision", mock.Anything, mock.Anything, int64(1)).
+					Return(revision, nil)
+				s.On("GetConfigHistoryStore").Return(hs).Once()
+				cs := s.GetConfigStore().(*mockstore.ConfigStore)
+				cs.On("CreateOrUpdate", mock.Anything, mock.Anything, mock.Anything).
+					Return(nil).
+					Once()
+			},
+			wantStatusCode: http.StatusOK,
+		},
+	}
+}
+
 func marshalWrapped(v corev3.Resource) []byte {
 	bytes, _ := json.Marshal(types.WrapResource(v))
 	return bytes
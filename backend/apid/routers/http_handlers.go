@@ -0,0 +1,40 @@
+package routers
+
+import (
+	"github.com/gorilla/mux"
+	pipelinev1 "github.com/sensu/sensu-go/api/pipeline/v1"
+	"github.com/sensu/sensu-go/backend/apid/handlers"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+// HTTPHandlersRouter handles requests for /http_handlers
+type HTTPHandlersRouter struct {
+	store storev2.Interface
+}
+
+// NewHTTPHandlersRouter instantiates new router for controlling HTTP handler
+// resources
+func NewHTTPHandlersRouter(store storev2.Interface) *HTTPHandlersRouter {
+	return &HTTPHandlersRouter{
+		store: store,
+	}
+}
+
+// Mount the HTTPHandlersRouter to a parent Router
+func (r *HTTPHandlersRouter) Mount(parent *mux.Router) {
+	routes := ResourceRoute{
+		Router:     parent,
+		PathPrefix: "/namespaces/{namespace}/{resource:http_handlers}",
+	}
+
+	handlers := handlers.NewHandlers[*pipelinev1.HTTPHandler](r.store)
+
+	routes.Get(handlers.GetResource)
+	routes.List(handlers.ListResources, pipelinev1.HTTPHandlerFields)
+	routes.ListAllNamespaces(handlers.ListResources, "/{resource:http_handlers}", pipelinev1.HTTPHandlerFields)
+	routes.Patch(handlers.PatchResource)
+	routes.Post(handlers.CreateResource)
+	routes.Put(handlers.CreateOrUpdateResource)
+	routes.Del(handlers.DeleteResource)
+	routes.Revisions(handlers)
+}
@@ -0,0 +1,32 @@
+package routers
+
+import (
+	"testing"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/core/v2"
+	pipelinev1 "github.com/sensu/sensu-go/api/pipeline/v1"
+	"github.com/sensu/sensu-go/testing/mockstore"
+)
+
+func TestHTTPHandlersRouter(t *testing.T) {
+	s := &mockstore.V2MockStore{}
+	cs := new(mockstore.ConfigStore)
+	s.On("GetConfigStore").Return(cs)
+	router := NewHTTPHandlersRouter(s)
+	parentRouter := mux.NewRouter().PathPrefix("/api/{group:pipeline}/{version:v1}").Subrouter()
+	router.Mount(parentRouter)
+
+	empty := &pipelinev1.HTTPHandler{Metadata: &corev2.ObjectMeta{}}
+	fixture := pipelinev1.FixtureHTTPHandler("foo")
+
+	tests := []routerTestCase{}
+	tests = append(tests, getTestCases[*pipelinev1.HTTPHandler](fixture)...)
+	tests = append(tests, listTestCases[*pipelinev1.HTTPHandler](empty)...)
+	tests = append(tests, createTestCases(fixture)...)
+	tests = append(tests, updateTestCases(fixture)...)
+	tests = append(tests, deleteTestCases(fixture)...)
+	for _, tt := range tests {
+		run(t, tt, parentRouter, s)
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"encoding/base64"
 	"net/http"
 	"net/url"
-	"strings"
 
 	"github.com/gorilla/mux"
 	corev2 "github.com/sensu/core/v2"
@@ -44,7 +43,7 @@ func WrapList(list ListControllerFunc, fieldsFunc FieldsFunc) http.HandlerFunc {
 
 		// Determine if we have a label selector
 		var labelSelector *selector.Selector
-		requirements := strings.Join(query["labelSelector"], " && ")
+		requirements := selector.Join(query["labelSelector"])
 		if requirements != "" {
 			labelSelector, err = selector.ParseLabelSelector(requirements)
 			if err != nil {
@@ -55,7 +54,7 @@ func WrapList(list ListControllerFunc, fieldsFunc FieldsFunc) http.HandlerFunc {
 
 		// Determine if we have a field selector
 		var fieldSelector *selector.Selector
-		requirements = strings.Join(query["fieldSelector"], " && ")
+		requirements = selector.Join(query["fieldSelector"])
 		if requirements != "" {
 			fieldSelector, err = selector.ParseFieldSelector(requirements)
 			if err != nil {
@@ -0,0 +1,40 @@
+package routers
+
+import (
+	"github.com/gorilla/mux"
+	maintenancev1 "github.com/sensu/sensu-go/api/maintenance/v1"
+	"github.com/sensu/sensu-go/backend/apid/handlers"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+// MaintenanceWindowsRouter handles requests for /maintenance_windows
+type MaintenanceWindowsRouter struct {
+	store storev2.Interface
+}
+
+// NewMaintenanceWindowsRouter instantiates new router for controlling
+// maintenance window resources
+func NewMaintenanceWindowsRouter(store storev2.Interface) *MaintenanceWindowsRouter {
+	return &MaintenanceWindowsRouter{
+		store: store,
+	}
+}
+
+// Mount the MaintenanceWindowsRouter to a parent Router
+func (r *MaintenanceWindowsRouter) Mount(parent *mux.Router) {
+	routes := ResourceRoute{
+		Router:     parent,
+		PathPrefix: "/namespaces/{namespace}/{resource:maintenance_windows}",
+	}
+
+	handlers := handlers.NewHandlers[*maintenancev1.MaintenanceWindow](r.store)
+
+	routes.Get(handlers.GetResource)
+	routes.List(handlers.ListResources, maintenancev1.MaintenanceWindowFields)
+	routes.ListAllNamespaces(handlers.ListResources, "/{resource:maintenance_windows}", maintenancev1.MaintenanceWindowFields)
+	routes.Patch(handlers.PatchResource)
+	routes.Post(handlers.CreateResource)
+	routes.Put(handlers.CreateOrUpdateResource)
+	routes.Del(handlers.DeleteResource)
+	routes.Revisions(handlers)
+}
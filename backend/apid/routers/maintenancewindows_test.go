@@ -0,0 +1,32 @@
+package routers
+
+import (
+	"testing"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/core/v2"
+	maintenancev1 "github.com/sensu/sensu-go/api/maintenance/v1"
+	"github.com/sensu/sensu-go/testing/mockstore"
+)
+
+func TestMaintenanceWindowsRouter(t *testing.T) {
+	s := &mockstore.V2MockStore{}
+	cs := new(mockstore.ConfigStore)
+	s.On("GetConfigStore").Return(cs)
+	router := NewMaintenanceWindowsRouter(s)
+	parentRouter := mux.NewRouter().PathPrefix("/api/{group:maintenance}/{version:v1}").Subrouter()
+	router.Mount(parentRouter)
+
+	empty := &maintenancev1.MaintenanceWindow{Metadata: &corev2.ObjectMeta{}}
+	fixture := maintenancev1.FixtureMaintenanceWindow("foo")
+
+	tests := []routerTestCase{}
+	tests = append(tests, getTestCases[*maintenancev1.MaintenanceWindow](fixture)...)
+	tests = append(tests, listTestCases[*maintenancev1.MaintenanceWindow](empty)...)
+	tests = append(tests, createTestCases(fixture)...)
+	tests = append(tests, updateTestCases(fixture)...)
+	tests = append(tests, deleteTestCases(fixture)...)
+	for _, tt := range tests {
+		run(t, tt, parentRouter, s)
+	}
+}
@@ -107,6 +107,9 @@ func (r *NamespacesRouter) create(req *http.Request) (handlers.HandlerResponse,
 	if err := ns.Validate(); err != nil {
 		return response, actions.NewError(actions.InvalidArgument, err)
 	}
+	if handlers.IsDryRun(req) {
+		return dryRunResponse(req, nil, &ns)
+	}
 	if err := r.client.CreateNamespace(ctx, &ns); err != nil {
 		switch err := err.(type) {
 		case *store.ErrAlreadyExists:
@@ -138,6 +141,9 @@ func (r *NamespacesRouter) update(req *http.Request) (handlers.HandlerResponse,
 	if err := ns.Validate(); err != nil {
 		return response, actions.NewError(actions.InvalidArgument, err)
 	}
+	if handlers.IsDryRun(req) {
+		return dryRunResponse(req, nil, &ns)
+	}
 	if err := r.client.UpdateNamespace(ctx, &ns); err != nil {
 		switch err := err.(type) {
 		case *store.ErrNotValid:
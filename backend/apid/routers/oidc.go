@@ -0,0 +1,121 @@
+package routers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/api"
+	"github.com/sensu/sensu-go/backend/authentication/jwt"
+)
+
+// oidcCallbackPath is the path of the endpoint OIDC issuers redirect users to
+// once they are authenticated.
+const oidcCallbackPath = "/auth/oidc/callback"
+
+type OIDCClient interface {
+	Authorize(ctx context.Context, provider, redirectURI, clientRedirect string) (string, error)
+	Callback(ctx context.Context, state, code string) (*api.OIDCLogin, error)
+	Token(ctx context.Context, code string) (*corev2.Tokens, error)
+}
+
+// OIDCRouter handles the authorization code flow of the OIDC authentication
+// providers
+type OIDCRouter struct {
+	client OIDCClient
+}
+
+// NewOIDCRouter instantiates new router.
+func NewOIDCRouter(client OIDCClient) *OIDCRouter {
+	return &OIDCRouter{client: client}
+}
+
+// Mount the OIDC routes on given mux.Router.
+func (o *OIDCRouter) Mount(r *mux.Router) {
+	r.HandleFunc("/auth/oidc/authorize", o.authorize).Methods(http.MethodGet)
+	r.HandleFunc(oidcCallbackPath, o.callback).Methods(http.MethodGet)
+	r.HandleFunc("/auth/oidc/token", o.token).Methods(http.MethodPost)
+}
+
+// authorize starts a login by redirecting the user to the issuer
+func (o *OIDCRouter) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	redirectURI := issuerURL(r) + oidcCallbackPath
+
+	authURL, err := o.client.Authorize(r.Context(), query.Get("provider"), redirectURI, query.Get("redirect"))
+	if err != nil {
+		switch {
+		case errors.Is(err, api.ErrOIDCProviderNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, api.ErrInvalidClientRedirect):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			logger.WithError(err).Error("could not start oidc login")
+			http.Error(w, http.StatusText(http.StatusBadGateway), http.StatusBadGateway)
+		}
+		return
+	}
+
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// callback completes a login once the user is redirected back by the issuer
+func (o *OIDCRouter) callback(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if reason := query.Get("error"); reason != "" {
+		logger.WithField("error", reason).WithField("description", query.Get("error_description")).
+			Error("the issuer denied the oidc login")
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	// Determine the URL that serves this request so it can be later used as the
+	// issuer URL
+	ctx := context.WithValue(r.Context(), jwt.IssuerURLKey, issuerURL(r))
+
+	login, err := o.client.Callback(ctx, query.Get("state"), query.Get("code"))
+	if err != nil {
+		if err == corev2.ErrUnauthorized {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		logger.WithError(err).Error("could not complete oidc login")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	if login.RedirectURL != "" {
+		http.Redirect(w, r, login.RedirectURL, http.StatusFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(login.Tokens); err != nil {
+		logger.WithError(err).Error("couldn't write response")
+	}
+}
+
+// token redeems the code given to a client at the end of a login
+func (o *OIDCRouter) token(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		Code string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.Code == "" {
+		http.Error(w, "invalid login code", http.StatusBadRequest)
+		return
+	}
+
+	tokens, err := o.client.Token(r.Context(), payload.Code)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(tokens); err != nil {
+		logger.WithError(err).Error("couldn't write response")
+	}
+}
@@ -0,0 +1,135 @@
+package routers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockOIDCClient struct {
+	mock.Mock
+}
+
+func (m *mockOIDCClient) Authorize(ctx context.Context, provider, redirectURI, clientRedirect string) (string, error) {
+	args := m.Called(ctx, provider, redirectURI, clientRedirect)
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockOIDCClient) Callback(ctx context.Context, state, code string) (*api.OIDCLogin, error) {
+	args := m.Called(ctx, state, code)
+	return args.Get(0).(*api.OIDCLogin), args.Error(1)
+}
+
+func (m *mockOIDCClient) Token(ctx context.Context, code string) (*corev2.Tokens, error) {
+	args := m.Called(ctx, code)
+	return args.Get(0).(*corev2.Tokens), args.Error(1)
+}
+
+func TestOIDCAuthorize(t *testing.T) {
+	client := new(mockOIDCClient)
+	client.On("Authorize", mock.Anything, "okta", "http://sensu.example.com:8080/auth/oidc/callback", "http://127.0.0.1:9999/").
+		Return("https://idp.example.com/authorize?state=abcd", nil)
+	router := NewOIDCRouter(client)
+
+	req, _ := http.NewRequest(http.MethodGet, "/auth/oidc/authorize?provider=okta&redirect=http://127.0.0.1:9999/", nil)
+	req.Host = "sensu.example.com:8080"
+
+	res := processRequest(router, req)
+	assert.Equal(t, http.StatusFound, res.Code)
+	assert.Equal(t, "https://idp.example.com/authorize?state=abcd", res.Header().Get("Location"))
+}
+
+func TestOIDCAuthorizeErrors(t *testing.T) {
+	tests := []struct {
+		err  error
+		code int
+	}{
+		{err: api.ErrOIDCProviderNotFound, code: http.StatusNotFound},
+		{err: api.ErrInvalidClientRedirect, code: http.StatusBadRequest},
+		{err: errors.New("discovery failed"), code: http.StatusBadGateway},
+	}
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			client := new(mockOIDCClient)
+			client.On("Authorize", mock.Anything, "", mock.Anything, "").Return("", tt.err)
+			router := NewOIDCRouter(client)
+
+			req, _ := http.NewRequest(http.MethodGet, "/auth/oidc/authorize", nil)
+			res := processRequest(router, req)
+			assert.Equal(t, tt.code, res.Code)
+		})
+	}
+}
+
+func TestOIDCCallback(t *testing.T) {
+	tokens := &corev2.Tokens{Access: "abcd", Refresh: "efgh"}
+	client := new(mockOIDCClient)
+	client.On("Callback", mock.Anything, "state", "code").Return(&api.OIDCLogin{Tokens: tokens}, nil)
+	router := NewOIDCRouter(client)
+
+	req, _ := http.NewRequest(http.MethodGet, "/auth/oidc/callback?state=state&code=code", nil)
+	res := processRequest(router, req)
+	assert.Equal(t, http.StatusOK, res.Code)
+
+	response := &corev2.Tokens{}
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), response))
+	assert.Equal(t, tokens, response)
+}
+
+func TestOIDCCallbackClientRedirect(t *testing.T) {
+	client := new(mockOIDCClient)
+	client.On("Callback", mock.Anything, "state", "code").
+		Return(&api.OIDCLogin{RedirectURL: "http://127.0.0.1:9999/?code=1234"}, nil)
+	router := NewOIDCRouter(client)
+
+	req, _ := http.NewRequest(http.MethodGet, "/auth/oidc/callback?state=state&code=code", nil)
+	res := processRequest(router, req)
+	assert.Equal(t, http.StatusFound, res.Code)
+	assert.Equal(t, "http://127.0.0.1:9999/?code=1234", res.Header().Get("Location"))
+}
+
+func TestOIDCCallbackErrors(t *testing.T) {
+	client := new(mockOIDCClient)
+	client.On("Callback", mock.Anything, "state", "code").Return((*api.OIDCLogin)(nil), corev2.ErrUnauthorized)
+	router := NewOIDCRouter(client)
+
+	req, _ := http.NewRequest(http.MethodGet, "/auth/oidc/callback?state=state&code=code", nil)
+	res := processRequest(router, req)
+	assert.Equal(t, http.StatusUnauthorized, res.Code)
+
+	req, _ = http.NewRequest(http.MethodGet, "/auth/oidc/callback?error=access_denied", nil)
+	res = processRequest(router, req)
+	assert.Equal(t, http.StatusUnauthorized, res.Code)
+	client.AssertNumberOfCalls(t, "Callback", 1)
+}
+
+func TestOIDCToken(t *testing.T) {
+	tokens := &corev2.Tokens{Access: "abcd", Refresh: "efgh"}
+	client := new(mockOIDCClient)
+	client.On("Token", mock.Anything, "1234").Return(tokens, nil)
+	client.On("Token", mock.Anything, "5678").Return((*corev2.Tokens)(nil), corev2.ErrUnauthorized)
+	router := NewOIDCRouter(client)
+
+	req, _ := http.NewRequest(http.MethodPost, "/auth/oidc/token", strings.NewReader(`{"code":"1234"}`))
+	res := processRequest(router, req)
+	assert.Equal(t, http.StatusOK, res.Code)
+	response := &corev2.Tokens{}
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), response))
+	assert.Equal(t, tokens, response)
+
+	req, _ = http.NewRequest(http.MethodPost, "/auth/oidc/token", strings.NewReader(`{"code":"5678"}`))
+	res = processRequest(router, req)
+	assert.Equal(t, http.StatusUnauthorized, res.Code)
+
+	req, _ = http.NewRequest(http.MethodPost, "/auth/oidc/token", strings.NewReader(`{}`))
+	res = processRequest(router, req)
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+}
@@ -12,7 +12,9 @@ import (
 	"github.com/sensu/core/v3/types"
 	"github.com/sensu/sensu-go/backend/apid/actions"
 	"github.com/sensu/sensu-go/backend/apid/handlers"
+	"github.com/sensu/sensu-go/backend/authorization/rbac"
 	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
 )
 
 type errorBody struct {
@@ -63,6 +65,10 @@ func RespondWith(w http.ResponseWriter, r *http.Request, response handlers.Handl
 		resources = wrapList
 	} else if response.GraphQL != nil {
 		resources = response.GraphQL
+	} else if response.DryRun != nil {
+		resources = response.DryRun
+	} else if response.Payload != nil {
+		resources = response.Payload
 	}
 
 	// Marshal
@@ -140,6 +146,8 @@ func HTTPStatusFromCode(code actions.ErrCode) int {
 		return http.StatusGatewayTimeout
 	case actions.Gone:
 		return http.StatusGone
+	case actions.ResourceExhausted:
+		return http.StatusTooManyRequests
 	}
 
 	logger.WithField("code", code).Error("unknown error code")
@@ -189,6 +197,15 @@ func listHandler(fn listHandlerFunc) http.HandlerFunc {
 	}
 }
 
+// dryRunResponse responds to the dry-run requests of the routers with custom
+// create and update handlers.
+func dryRunResponse(req *http.Request, s storev2.Interface, resource corev3.Resource) (handlers.HandlerResponse, error) {
+	var response handlers.HandlerResponse
+	result, err := handlers.DryRun(req.Context(), s, &rbac.Authorizer{Store: s}, resource)
+	response.DryRun = result
+	return response, err
+}
+
 type actionHandlerFunc func(r *http.Request) (handlers.HandlerResponse, error)
 
 type listHandlerFunc func(w http.ResponseWriter, req *http.Request) ([]corev3.Resource, error)
@@ -250,6 +267,22 @@ func (r *ResourceRoute) Del(fn actionHandlerFunc) *mux.Route {
 	return r.Path("{id}", fn).Methods(http.MethodDelete)
 }
 
+// revisionHandlers are the handlers of the revision history of a resource.
+type revisionHandlers interface {
+	ListRevisions(*http.Request) (handlers.HandlerResponse, error)
+	DiffRevisions(*http.Request) (handlers.HandlerResponse, error)
+	RollbackResource(*http.Request) (handlers.HandlerResponse, error)
+}
+
+// Revisions mounts the revision history of the resources, at GET
+// /checks/:id/history and GET /checks/:id/history/diff, and their rollback
+// at PUT /checks/:id/rollback, which is authorized as an update
+func (r *ResourceRoute) Revisions(h revisionHandlers) {
+	r.Path("{id}/history", h.ListRevisions).Methods(http.MethodGet)
+	r.Path("{id}/history/diff", h.DiffRevisions).Methods(http.MethodGet)
+	r.Path("{id}/rollback", h.RollbackResource).Methods(http.MethodPut)
+}
+
 // Path adds custom path
 func (r *ResourceRoute) Path(p string, fn actionHandlerFunc) *mux.Route {
 	fullPath := path.Join(r.PathPrefix, p)
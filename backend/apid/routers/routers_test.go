@@ -3,6 +3,7 @@ package routers
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -78,6 +79,28 @@ func TestRespondWith(t *testing.T) {
 	}
 }
 
+func TestRespondWithDryRun(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/?dryRun=true", nil)
+	RespondWith(w, r, handlers.HandlerResponse{
+		DryRun: &handlers.DryRunResult{
+			DryRun:    true,
+			Operation: handlers.DryRunCreate,
+			Warnings:  []handlers.DryRunWarning{{Field: "handlers[0]", Message: "Handler slack not found"}},
+		},
+	})
+	if got, want := w.Code, http.StatusOK; got != want {
+		t.Fatalf("bad status: got %d, want %d", got, want)
+	}
+	var result handlers.DryRunResult
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+	if !result.DryRun || len(result.Warnings) != 1 {
+		t.Errorf("bad dry run result: %+v", result)
+	}
+}
+
 func TestWriteError(t *testing.T) {
 	type args struct {
 		w   http.ResponseWriter
@@ -0,0 +1,75 @@
+package routers
+
+import (
+	"github.com/gorilla/mux"
+	secretsv1 "github.com/sensu/sensu-go/api/secrets/v1"
+	"github.com/sensu/sensu-go/backend/apid/handlers"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+// SecretsProvidersRouter handles requests for /providers, where each type of
+// provider has its own path
+type SecretsProvidersRouter struct {
+	store storev2.Interface
+}
+
+// NewSecretsProvidersRouter instantiates new router for controlling secrets
+// provider resources
+func NewSecretsProvidersRouter(store storev2.Interface) *SecretsProvidersRouter {
+	return &SecretsProvidersRouter{
+		store: store,
+	}
+}
+
+// Mount the SecretsProvidersRouter to a parent Router
+func (r *SecretsProvidersRouter) Mount(parent *mux.Router) {
+	mountSecretsProviders[*secretsv1.Env](parent, r.store, "env", secretsv1.EnvFields)
+	mountSecretsProviders[*secretsv1.VaultProvider](parent, r.store, "vault", secretsv1.VaultProviderFields)
+}
+
+func mountSecretsProviders[R storev2.Resource[T], T any](parent *mux.Router, store storev2.Interface, typename string, fields FieldsFunc) {
+	routes := ResourceRoute{
+		Router:     parent,
+		PathPrefix: "/{resource:providers}/" + typename,
+	}
+
+	handlers := handlers.NewHandlers[R](store)
+
+	routes.Del(handlers.DeleteResource)
+	routes.Get(handlers.GetResource)
+	routes.List(handlers.ListResources, fields)
+	routes.Patch(handlers.PatchResource)
+	routes.Post(handlers.CreateResource)
+	routes.Put(handlers.CreateOrUpdateResource)
+}
+
+// SecretsRouter handles requests for /secrets
+type SecretsRouter struct {
+	store storev2.Interface
+}
+
+// NewSecretsRouter instantiates new router for controlling secret resources
+func NewSecretsRouter(store storev2.Interface) *SecretsRouter {
+	return &SecretsRouter{
+		store: store,
+	}
+}
+
+// Mount the SecretsRouter to a parent Router
+func (r *SecretsRouter) Mount(parent *mux.Router) {
+	routes := ResourceRoute{
+		Router:     parent,
+		PathPrefix: "/namespaces/{namespace}/{resource:secrets}",
+	}
+
+	handlers := handlers.NewHandlers[*secretsv1.Secret](r.store)
+
+	routes.Get(handlers.GetResource)
+	routes.List(handlers.ListResources, secretsv1.SecretFields)
+	routes.ListAllNamespaces(handlers.ListResources, "/{resource:secrets}", secretsv1.SecretFields)
+	routes.Patch(handlers.PatchResource)
+	routes.Post(handlers.CreateResource)
+	routes.Put(handlers.CreateOrUpdateResource)
+	routes.Del(handlers.DeleteResource)
+	routes.Revisions(handlers)
+}
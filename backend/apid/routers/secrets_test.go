@@ -0,0 +1,61 @@
+package routers
+
+import (
+	"testing"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/core/v2"
+	secretsv1 "github.com/sensu/sensu-go/api/secrets/v1"
+	"github.com/sensu/sensu-go/testing/mockstore"
+)
+
+func TestSecretsProvidersRouter(t *testing.T) {
+	s := &mockstore.V2MockStore{}
+	cs := new(mockstore.ConfigStore)
+	s.On("GetConfigStore").Return(cs)
+	router := NewSecretsProvidersRouter(s)
+	parentRouter := mux.NewRouter().PathPrefix("/api/{group:secrets}/{version:v1}").Subrouter()
+	router.Mount(parentRouter)
+
+	envEmpty := &secretsv1.Env{Metadata: &corev2.ObjectMeta{}}
+	envFixture := secretsv1.FixtureEnv("foo")
+	vaultEmpty := &secretsv1.VaultProvider{Metadata: &corev2.ObjectMeta{}}
+	vaultFixture := secretsv1.FixtureVaultProvider("foo")
+
+	tests := []routerTestCase{}
+	tests = append(tests, getTestCases[*secretsv1.Env](envFixture)...)
+	tests = append(tests, listTestCases[*secretsv1.Env](envEmpty)...)
+	tests = append(tests, createTestCases(envFixture)...)
+	tests = append(tests, updateTestCases(envFixture)...)
+	tests = append(tests, deleteTestCases(envFixture)...)
+	tests = append(tests, getTestCases[*secretsv1.VaultProvider](vaultFixture)...)
+	tests = append(tests, listTestCases[*secretsv1.VaultProvider](vaultEmpty)...)
+	tests = append(tests, createTestCases(vaultFixture)...)
+	tests = append(tests, updateTestCases(vaultFixture)...)
+	tests = append(tests, deleteTestCases(vaultFixture)...)
+	for _, tt := range tests {
+		run(t, tt, parentRouter, s)
+	}
+}
+
+func TestSecretsRouter(t *testing.T) {
+	s := &mockstore.V2MockStore{}
+	cs := new(mockstore.ConfigStore)
+	s.On("GetConfigStore").Return(cs)
+	router := NewSecretsRouter(s)
+	parentRouter := mux.NewRouter().PathPrefix("/api/{group:secrets}/{version:v1}").Subrouter()
+	router.Mount(parentRouter)
+
+	empty := &secretsv1.Secret{Metadata: &corev2.ObjectMeta{}}
+	fixture := secretsv1.FixtureSecret("foo", "default")
+
+	tests := []routerTestCase{}
+	tests = append(tests, getTestCases[*secretsv1.Secret](fixture)...)
+	tests = append(tests, listTestCases[*secretsv1.Secret](empty)...)
+	tests = append(tests, createTestCases(fixture)...)
+	tests = append(tests, updateTestCases(fixture)...)
+	tests = append(tests, deleteTestCases(fixture)...)
+	for _, tt := range tests {
+		run(t, tt, parentRouter, s)
+	}
+}
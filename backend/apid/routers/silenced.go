@@ -79,6 +79,9 @@ func (r *SilencedRouter) create(req *http.Request) (handlers.HandlerResponse, er
 	if err := handlers.CheckMeta(entry, mux.Vars(req), "id"); err != nil {
 		return response, actions.NewError(actions.InvalidArgument, err)
 	}
+	if handlers.IsDryRun(req) {
+		return dryRunResponse(req, r.store, entry)
+	}
 
 	err = r.controller.Create(req.Context(), entry)
 	return response, err
@@ -94,6 +97,9 @@ func (r *SilencedRouter) createOrReplace(req *http.Request) (handlers.HandlerRes
 	if err := handlers.CheckMeta(entry, mux.Vars(req), "id"); err != nil {
 		return response, actions.NewError(actions.InvalidArgument, err)
 	}
+	if handlers.IsDryRun(req) {
+		return dryRunResponse(req, r.store, entry)
+	}
 
 	err = r.controller.CreateOrReplace(req.Context(), entry)
 	return response, err
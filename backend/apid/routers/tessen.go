@@ -46,6 +46,9 @@ func (r *TessenRouter) createOrUpdate(req *http.Request) (handlers.HandlerRespon
 	if err != nil {
 		return response, err
 	}
+	if handlers.IsDryRun(req) {
+		return response, handlers.UnsupportedDryRun()
+	}
 
 	err = r.controller.CreateOrUpdate(req.Context(), obj)
 	response.Resource = obj
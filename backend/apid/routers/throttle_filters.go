@@ -0,0 +1,40 @@
+package routers
+
+import (
+	"github.com/gorilla/mux"
+	pipelinev1 "github.com/sensu/sensu-go/api/pipeline/v1"
+	"github.com/sensu/sensu-go/backend/apid/handlers"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+// ThrottleFiltersRouter handles requests for /throttle_filters
+type ThrottleFiltersRouter struct {
+	store storev2.Interface
+}
+
+// NewThrottleFiltersRouter instantiates new router for controlling
+// throttle filter resources
+func NewThrottleFiltersRouter(store storev2.Interface) *ThrottleFiltersRouter {
+	return &ThrottleFiltersRouter{
+		store: store,
+	}
+}
+
+// Mount the ThrottleFiltersRouter to a parent Router
+func (r *ThrottleFiltersRouter) Mount(parent *mux.Router) {
+	routes := ResourceRoute{
+		Router:     parent,
+		PathPrefix: "/namespaces/{namespace}/{resource:throttle_filters}",
+	}
+
+	handlers := handlers.NewHandlers[*pipelinev1.ThrottleFilter](r.store)
+
+	routes.Get(handlers.GetResource)
+	routes.List(handlers.ListResources, pipelinev1.ThrottleFilterFields)
+	routes.ListAllNamespaces(handlers.ListResources, "/{resource:throttle_filters}", pipelinev1.ThrottleFilterFields)
+	routes.Patch(handlers.PatchResource)
+	routes.Post(handlers.CreateResource)
+	routes.Put(handlers.CreateOrUpdateResource)
+	routes.Del(handlers.DeleteResource)
+	routes.Revisions(handlers)
+}
@@ -0,0 +1,32 @@
+package routers
+
+import (
+	"testing"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/core/v2"
+	pipelinev1 "github.com/sensu/sensu-go/api/pipeline/v1"
+	"github.com/sensu/sensu-go/testing/mockstore"
+)
+
+func TestThrottleFiltersRouter(t *testing.T) {
+	s := &mockstore.V2MockStore{}
+	cs := new(mockstore.ConfigStore)
+	s.On("GetConfigStore").Return(cs)
+	router := NewThrottleFiltersRouter(s)
+	parentRouter := mux.NewRouter().PathPrefix("/api/{group:pipeline}/{version:v1}").Subrouter()
+	router.Mount(parentRouter)
+
+	empty := &pipelinev1.ThrottleFilter{Metadata: &corev2.ObjectMeta{}}
+	fixture := pipelinev1.FixtureThrottleFilter("foo")
+
+	tests := []routerTestCase{}
+	tests = append(tests, getTestCases[*pipelinev1.ThrottleFilter](fixture)...)
+	tests = append(tests, listTestCases[*pipelinev1.ThrottleFilter](empty)...)
+	tests = append(tests, createTestCases(fixture)...)
+	tests = append(tests, updateTestCases(fixture)...)
+	tests = append(tests, deleteTestCases(fixture)...)
+	for _, tt := range tests {
+		run(t, tt, parentRouter, s)
+	}
+}
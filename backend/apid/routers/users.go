@@ -88,6 +88,9 @@ func (r *UsersRouter) create(req *http.Request) (handlers.HandlerResponse, error
 	if err != nil {
 		return response, actions.NewError(actions.InvalidArgument, err)
 	}
+	if handlers.IsDryRun(req) {
+		return dryRunResponse(req, nil, user)
+	}
 
 	err = r.controller.Create(req.Context(), user)
 	return response, err
@@ -109,6 +112,9 @@ func (r *UsersRouter) createOrReplace(req *http.Request) (handlers.HandlerRespon
 				vars["id"],
 			))
 	}
+	if handlers.IsDryRun(req) {
+		return dryRunResponse(req, nil, user)
+	}
 
 	err = r.controller.CreateOrReplace(req.Context(), user)
 	return response, err
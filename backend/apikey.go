@@ -0,0 +1,63 @@
+package backend
+
+import (
+	"context"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/authentication/apikey"
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+// apiKeyPruneInterval is the interval at which the expired API keys are
+// deleted.
+const apiKeyPruneInterval = 10 * time.Minute
+
+// PruneAPIKeysLoop periodically deletes the expired API keys, and the last
+// uses of the API keys that no longer exist, until ctx is cancelled.
+func PruneAPIKeysLoop(ctx context.Context, s storev2.Interface, usage store.APIKeyUsageStore) {
+	ticker := time.NewTicker(apiKeyPruneInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := pruneAPIKeys(ctx, s, usage); err != nil {
+				logger.WithError(err).Error("error pruning expired api keys")
+			}
+		}
+	}
+}
+
+func pruneAPIKeys(ctx context.Context, s storev2.Interface, usage store.APIKeyUsageStore) error {
+	keyStore := storev2.Of[*corev2.APIKey](s)
+	keys, err := keyStore.List(ctx, storev2.ID{}, nil)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	names := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if !apikey.Expired(key, now) {
+			names = append(names, key.Name)
+			continue
+		}
+		if err := keyStore.Delete(ctx, storev2.ID{Name: key.Name}); err != nil {
+			// the key may have been deleted by another backend
+			if _, ok := err.(*store.ErrNotFound); !ok {
+				return err
+			}
+		}
+		logger.WithField("api_key", key.Name).Info("deleted expired api key")
+	}
+	pruned, err := usage.PruneAPIKeyUses(ctx, names)
+	if err != nil {
+		return err
+	}
+	if pruned > 0 {
+		logger.WithField("api_keys", pruned).Debug("pruned api key uses")
+	}
+	return nil
+}
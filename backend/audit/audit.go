@@ -0,0 +1,206 @@
+// Package audit records the changes made to the resources through the API, in
+// an audit log.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"reflect"
+	"sync"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	corev3 "github.com/sensu/core/v3"
+	"github.com/sensu/sensu-go/backend/apid/request"
+	"github.com/sensu/sensu-go/backend/authentication/jwt"
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+// The verbs of the audit entries.
+const (
+	VerbCreate = "create"
+	VerbUpdate = "update"
+	VerbPatch  = "patch"
+	VerbDelete = "delete"
+)
+
+// RedactTag is the struct tag of the fields of the resources that are never
+// recorded, e.g. `sensu:"redact"`.
+const RedactTag = "sensu"
+
+// redactedFields are the fields of the core resources that are never
+// recorded, by resource type. The fields of the other resources are redacted
+// with RedactTag.
+var redactedFields = map[string][]string{
+	"User":   {"password", "password_hash"},
+	"APIKey": {"hash"},
+}
+
+// redactedTypes caches whether the types have fields tagged with RedactTag.
+var redactedTypes sync.Map
+
+// Auditor records the audit entries as JSON lines to Writer, and to Store.
+// Either may be nil.
+type Auditor struct {
+	Writer io.Writer
+	Store  store.AuditStore
+
+	mu  sync.Mutex
+	now func() time.Time
+}
+
+// Record records an audit entry. The user and source IP of the entry are
+// taken from the context. Recording errors are logged, since the change was
+// already made.
+func (a *Auditor) Record(ctx context.Context, entry *store.AuditEntry) {
+	now := time.Now
+	if a.now != nil {
+		now = a.now
+	}
+	if entry.Time.IsZero() {
+		entry.Time = now()
+	}
+	if claims := jwt.GetClaimsFromContext(ctx); claims != nil {
+		entry.User = claims.StandardClaims.Subject
+		entry.Groups = claims.Groups
+		entry.APIKey = claims.APIKey
+	}
+	entry.SourceIP = request.SourceIPFromContext(ctx)
+
+	if a.Store != nil {
+		if err := a.Store.RecordAuditEntry(ctx, entry); err != nil {
+			logger.WithError(err).Error("error recording audit entry")
+		}
+	}
+	if a.Writer != nil {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			logger.WithError(err).Error("error recording audit entry")
+			return
+		}
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		if _, err := a.Writer.Write(append(line, '\n')); err != nil {
+			logger.WithError(err).Error("error writing audit entry")
+		}
+	}
+}
+
+// record records a change of the resource identified by the request, with
+// its state before and after the change. Either may be nil.
+func (a *Auditor) record(ctx context.Context, verb string, req storev2.ResourceRequest, before, after corev3.Resource) {
+	entry := &store.AuditEntry{
+		Verb:       verb,
+		APIVersion: req.APIVersion,
+		Type:       req.Type,
+		Namespace:  req.Namespace,
+		Name:       req.Name,
+	}
+	var err error
+	if entry.Before, err = marshal(req.Type, before); err != nil {
+		logger.WithError(err).Error("error recording audit entry")
+	}
+	if entry.After, err = marshal(req.Type, after); err != nil {
+		logger.WithError(err).Error("error recording audit entry")
+	}
+	if entry.Before != nil && entry.After != nil {
+		if entry.Diff, err = jsonpatch.CreateMergePatch(entry.Before, entry.After); err != nil {
+			logger.WithError(err).Error("error recording audit entry")
+		}
+	}
+	a.Record(ctx, entry)
+}
+
+// marshal returns the JSON representation of a resource, without its redacted
+// fields, or nil if it is nil.
+func marshal(typename string, resource corev3.Resource) (json.RawMessage, error) {
+	if resource == nil {
+		return nil, nil
+	}
+	if hasRedactedFields(reflect.TypeOf(resource)) {
+		b, err := json.Marshal(resource)
+		if err != nil {
+			return nil, err
+		}
+		redacted := reflect.New(reflect.TypeOf(resource).Elem())
+		if err := json.Unmarshal(b, redacted.Interface()); err != nil {
+			return nil, err
+		}
+		redact(redacted)
+		resource = redacted.Interface().(corev3.Resource)
+	}
+	b, err := json.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+	fields, ok := redactedFields[typename]
+	if !ok {
+		return b, nil
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	for _, field := range fields {
+		delete(m, field)
+	}
+	return json.Marshal(m)
+}
+
+// hasRedactedFields returns whether a type has fields tagged with RedactTag,
+// including in its nested structs.
+func hasRedactedFields(t reflect.Type) bool {
+	if redacted, ok := redactedTypes.Load(t); ok {
+		return redacted.(bool)
+	}
+	redacted := findRedactedFields(t, map[reflect.Type]bool{})
+	redactedTypes.Store(t, redacted)
+	return redacted
+}
+
+func findRedactedFields(t reflect.Type, seen map[reflect.Type]bool) bool {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return findRedactedFields(t.Elem(), seen)
+	case reflect.Struct:
+		if seen[t] {
+			return false
+		}
+		seen[t] = true
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.Tag.Get(RedactTag) == "redact" || findRedactedFields(field.Type, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// redact zeroes the fields tagged with RedactTag of a value.
+func redact(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			redact(v.Elem())
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			redact(v.Index(i))
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Field(i)
+			if !field.CanSet() {
+				continue
+			}
+			if v.Type().Field(i).Tag.Get(RedactTag) == "redact" {
+				field.Set(reflect.Zero(field.Type()))
+				continue
+			}
+			redact(field)
+		}
+	}
+}
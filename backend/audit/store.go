@@ -0,0 +1,302 @@
+package audit
+
+import (
+	"context"
+
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+	"github.com/sensu/sensu-go/backend/store/patch"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+// Store wraps a store so that the changes made to the configuration
+// resources, entities, namespaces and silences through it are recorded by an
+// Auditor. The states of the entities and the events are not audited.
+type Store struct {
+	storev2.Interface
+	auditor *Auditor
+}
+
+// NewStore creates a new Store that records the changes made through the given
+// store with the auditor.
+func NewStore(s storev2.Interface, auditor *Auditor) *Store {
+	return &Store{Interface: s, auditor: auditor}
+}
+
+// GetConfigStore returns the audited config store.
+func (s *Store) GetConfigStore() storev2.ConfigStore {
+	return &configStore{ConfigStore: s.Interface.GetConfigStore(), auditor: s.auditor}
+}
+
+// GetEntityConfigStore returns the audited entity config store.
+func (s *Store) GetEntityConfigStore() storev2.EntityConfigStore {
+	return &entityConfigStore{EntityConfigStore: s.Interface.GetEntityConfigStore(), auditor: s.auditor}
+}
+
+// GetNamespaceStore returns the audited namespace store.
+func (s *Store) GetNamespaceStore() storev2.NamespaceStore {
+	return &namespaceStore{NamespaceStore: s.Interface.GetNamespaceStore(), auditor: s.auditor}
+}
+
+// GetSilencesStore returns the audited silences store.
+func (s *Store) GetSilencesStore() storev2.SilencesStore {
+	return &silencesStore{SilencesStore: s.Interface.GetSilencesStore(), auditor: s.auditor}
+}
+
+// readContext returns a context for reading the state of a resource before
+// or after a change, without the preconditions of the change.
+func readContext(ctx context.Context) context.Context {
+	ctx = storev2.ContextWithIfMatch(ctx, nil)
+	return storev2.ContextWithIfNoneMatch(ctx, nil)
+}
+
+// verb returns the verb of a write, depending on whether the resource
+// existed before.
+func verb(before corev3.Resource) string {
+	if before == nil {
+		return VerbCreate
+	}
+	return VerbUpdate
+}
+
+type configStore struct {
+	storev2.ConfigStore
+	auditor *Auditor
+}
+
+func (s *configStore) get(ctx context.Context, req storev2.ResourceRequest) corev3.Resource {
+	wrapper, err := s.ConfigStore.Get(readContext(ctx), req)
+	if err != nil {
+		return nil
+	}
+	resource, err := wrapper.Unwrap()
+	if err != nil {
+		return nil
+	}
+	return resource
+}
+
+func unwrap(w storev2.Wrapper) corev3.Resource {
+	resource, err := w.Unwrap()
+	if err != nil {
+		logger.WithError(err).Error("error recording audit entry")
+		return nil
+	}
+	return resource
+}
+
+func (s *configStore) CreateOrUpdate(ctx context.Context, req storev2.ResourceRequest, w storev2.Wrapper) error {
+	before := s.get(ctx, req)
+	if err := s.ConfigStore.CreateOrUpdate(ctx, req, w); err != nil {
+		return err
+	}
+	s.auditor.record(ctx, verb(before), req, before, unwrap(w))
+	return nil
+}
+
+func (s *configStore) UpdateIfExists(ctx context.Context, req storev2.ResourceRequest, w storev2.Wrapper) error {
+	before := s.get(ctx, req)
+	if err := s.ConfigStore.UpdateIfExists(ctx, req, w); err != nil {
+		return err
+	}
+	s.auditor.record(ctx, VerbUpdate, req, before, unwrap(w))
+	return nil
+}
+
+func (s *configStore) CreateIfNotExists(ctx context.Context, req storev2.ResourceRequest, w storev2.Wrapper) error {
+	if err := s.ConfigStore.CreateIfNotExists(ctx, req, w); err != nil {
+		return err
+	}
+	s.auditor.record(ctx, VerbCreate, req, nil, unwrap(w))
+	return nil
+}
+
+func (s *configStore) Delete(ctx context.Context, req storev2.ResourceRequest) error {
+	before := s.get(ctx, req)
+	if err := s.ConfigStore.Delete(ctx, req); err != nil {
+		return err
+	}
+	s.auditor.record(ctx, VerbDelete, req, before, nil)
+	return nil
+}
+
+func (s *configStore) Patch(ctx context.Context, req storev2.ResourceRequest, patcher patch.Patcher) error {
+	before := s.get(ctx, req)
+	if err := s.ConfigStore.Patch(ctx, req, patcher); err != nil {
+		return err
+	}
+	s.auditor.record(ctx, VerbPatch, req, before, s.get(ctx, req))
+	return nil
+}
+
+type entityConfigStore struct {
+	storev2.EntityConfigStore
+	auditor *Auditor
+}
+
+func entityConfigRequest(namespace, name string) storev2.ResourceRequest {
+	return storev2.NewResourceRequestFromResource(&corev3.EntityConfig{
+		Metadata: &corev2.ObjectMeta{Namespace: namespace, Name: name},
+	})
+}
+
+func (s *entityConfigStore) get(ctx context.Context, namespace, name string) corev3.Resource {
+	config, err := s.EntityConfigStore.Get(readContext(ctx), namespace, name)
+	if err != nil || config == nil {
+		return nil
+	}
+	return config
+}
+
+func (s *entityConfigStore) CreateOrUpdate(ctx context.Context, config *corev3.EntityConfig) error {
+	meta := config.GetMetadata()
+	before := s.get(ctx, meta.Namespace, meta.Name)
+	if err := s.EntityConfigStore.CreateOrUpdate(ctx, config); err != nil {
+		return err
+	}
+	s.auditor.record(ctx, verb(before), entityConfigRequest(meta.Namespace, meta.Name), before, config)
+	return nil
+}
+
+func (s *entityConfigStore) UpdateIfExists(ctx context.Context, config *corev3.EntityConfig) error {
+	meta := config.GetMetadata()
+	before := s.get(ctx, meta.Namespace, meta.Name)
+	if err := s.EntityConfigStore.UpdateIfExists(ctx, config); err != nil {
+		return err
+	}
+	s.auditor.record(ctx, VerbUpdate, entityConfigRequest(meta.Namespace, meta.Name), before, config)
+	return nil
+}
+
+func (s *entityConfigStore) CreateIfNotExists(ctx context.Context, config *corev3.EntityConfig) error {
+	if err := s.EntityConfigStore.CreateIfNotExists(ctx, config); err != nil {
+		return err
+	}
+	meta := config.GetMetadata()
+	s.auditor.record(ctx, VerbCreate, entityConfigRequest(meta.Namespace, meta.Name), nil, config)
+	return nil
+}
+
+func (s *entityConfigStore) Delete(ctx context.Context, namespace, name string) error {
+	before := s.get(ctx, namespace, name)
+	if err := s.EntityConfigStore.Delete(ctx, namespace, name); err != nil {
+		return err
+	}
+	s.auditor.record(ctx, VerbDelete, entityConfigRequest(namespace, name), before, nil)
+	return nil
+}
+
+func (s *entityConfigStore) Patch(ctx context.Context, namespace, name string, patcher patch.Patcher) error {
+	before := s.get(ctx, namespace, name)
+	if err := s.EntityConfigStore.Patch(ctx, namespace, name, patcher); err != nil {
+		return err
+	}
+	s.auditor.record(ctx, VerbPatch, entityConfigRequest(namespace, name), before, s.get(ctx, namespace, name))
+	return nil
+}
+
+type namespaceStore struct {
+	storev2.NamespaceStore
+	auditor *Auditor
+}
+
+func namespaceRequest(name string) storev2.ResourceRequest {
+	return storev2.NewResourceRequestFromResource(&corev3.Namespace{
+		Metadata: &corev2.ObjectMeta{Name: name},
+	})
+}
+
+func (s *namespaceStore) get(ctx context.Context, name string) corev3.Resource {
+	namespace, err := s.NamespaceStore.Get(readContext(ctx), name)
+	if err != nil || namespace == nil {
+		return nil
+	}
+	return namespace
+}
+
+func (s *namespaceStore) CreateOrUpdate(ctx context.Context, namespace *corev3.Namespace) error {
+	name := namespace.GetMetadata().Name
+	before := s.get(ctx, name)
+	if err := s.NamespaceStore.CreateOrUpdate(ctx, namespace); err != nil {
+		return err
+	}
+	s.auditor.record(ctx, verb(before), namespaceRequest(name), before, namespace)
+	return nil
+}
+
+func (s *namespaceStore) UpdateIfExists(ctx context.Context, namespace *corev3.Namespace) error {
+	name := namespace.GetMetadata().Name
+	before := s.get(ctx, name)
+	if err := s.NamespaceStore.UpdateIfExists(ctx, namespace); err != nil {
+		return err
+	}
+	s.auditor.record(ctx, VerbUpdate, namespaceRequest(name), before, namespace)
+	return nil
+}
+
+func (s *namespaceStore) CreateIfNotExists(ctx context.Context, namespace *corev3.Namespace) error {
+	if err := s.NamespaceStore.CreateIfNotExists(ctx, namespace); err != nil {
+		return err
+	}
+	s.auditor.record(ctx, VerbCreate, namespaceRequest(namespace.GetMetadata().Name), nil, namespace)
+	return nil
+}
+
+func (s *namespaceStore) Delete(ctx context.Context, name string) error {
+	before := s.get(ctx, name)
+	if err := s.NamespaceStore.Delete(ctx, name); err != nil {
+		return err
+	}
+	s.auditor.record(ctx, VerbDelete, namespaceRequest(name), before, nil)
+	return nil
+}
+
+func (s *namespaceStore) Patch(ctx context.Context, name string, patcher patch.Patcher) error {
+	before := s.get(ctx, name)
+	if err := s.NamespaceStore.Patch(ctx, name, patcher); err != nil {
+		return err
+	}
+	s.auditor.record(ctx, VerbPatch, namespaceRequest(name), before, s.get(ctx, name))
+	return nil
+}
+
+type silencesStore struct {
+	storev2.SilencesStore
+	auditor *Auditor
+}
+
+func (s *silencesStore) get(ctx context.Context, namespace, name string) corev3.Resource {
+	silenced, err := s.SilencesStore.GetSilenceByName(readContext(ctx), namespace, name)
+	if err != nil || silenced == nil {
+		return nil
+	}
+	return silenced
+}
+
+func (s *silencesStore) UpdateSilence(ctx context.Context, silenced *corev2.Silenced) error {
+	before := s.get(ctx, silenced.Namespace, silenced.Name)
+	if err := s.SilencesStore.UpdateSilence(ctx, silenced); err != nil {
+		return err
+	}
+	s.auditor.record(ctx, verb(before), storev2.NewResourceRequestFromResource(silenced), before, silenced)
+	return nil
+}
+
+func (s *silencesStore) DeleteSilences(ctx context.Context, namespace string, names []string) error {
+	befores := make([]corev3.Resource, len(names))
+	for i, name := range names {
+		befores[i] = s.get(ctx, namespace, name)
+	}
+	if err := s.SilencesStore.DeleteSilences(ctx, namespace, names); err != nil {
+		return err
+	}
+	for i, name := range names {
+		if befores[i] == nil {
+			// The silence did not exist
+			continue
+		}
+		silenced := &corev2.Silenced{ObjectMeta: corev2.ObjectMeta{Namespace: namespace, Name: name}}
+		s.auditor.record(ctx, VerbDelete, storev2.NewResourceRequestFromResource(silenced), befores[i], nil)
+	}
+	return nil
+}
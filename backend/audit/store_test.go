@@ -0,0 +1,198 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	authv2 "github.com/sensu/sensu-go/api/authentication/v2"
+	secretsv1 "github.com/sensu/sensu-go/api/secrets/v1"
+	"github.com/sensu/sensu-go/backend/apid/request"
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	"github.com/sensu/sensu-go/backend/store/v2/wrap"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder is an AuditStore that keeps the entries in memory.
+type recorder struct {
+	entries []*store.AuditEntry
+}
+
+func (r *recorder) RecordAuditEntry(ctx context.Context, entry *store.AuditEntry) error {
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+func (r *recorder) ListAuditEntries(ctx context.Context, query *store.AuditQuery) ([]*store.AuditEntry, error) {
+	return r.entries, nil
+}
+
+func (r *recorder) PruneAuditEntries(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+func newTestStore(t *testing.T) (*Store, *mockstore.ConfigStore, *recorder, *bytes.Buffer) {
+	t.Helper()
+	s := &mockstore.V2MockStore{}
+	cs := new(mockstore.ConfigStore)
+	s.On("GetConfigStore").Return(cs)
+	rec := &recorder{}
+	var buf bytes.Buffer
+	now := time.Unix(1700000000, 0)
+	auditor := &Auditor{Writer: &buf, Store: rec, now: func() time.Time { return now }}
+	return NewStore(s, auditor), cs, rec, &buf
+}
+
+func testContext() context.Context {
+	claims := &corev2.Claims{Groups: []string{"cluster-admins"}, APIKey: true}
+	claims.StandardClaims.Subject = "admin"
+	ctx := context.WithValue(context.Background(), corev2.ClaimsKey, claims)
+	return request.ContextWithSourceIP(ctx, "10.0.0.1")
+}
+
+func TestStoreCreateOrUpdate(t *testing.T) {
+	s, cs, rec, buf := newTestStore(t)
+
+	before := corev2.FixtureCheckConfig("cpu")
+	after := corev2.FixtureCheckConfig("cpu")
+	after.Command = "check-cpu --warning 80"
+	req := storev2.NewResourceRequestFromResource(after)
+	w, err := wrap.Resource(before)
+	require.NoError(t, err)
+	cs.On("Get", mock.Anything, req).Return(w, nil)
+	cs.On("CreateOrUpdate", mock.Anything, req, mock.Anything).Return(nil)
+
+	w, err = wrap.Resource(after)
+	require.NoError(t, err)
+	require.NoError(t, s.GetConfigStore().CreateOrUpdate(testContext(), req, w))
+
+	require.Len(t, rec.entries, 1)
+	entry := rec.entries[0]
+	assert.Equal(t, VerbUpdate, entry.Verb)
+	assert.Equal(t, "admin", entry.User)
+	assert.Equal(t, []string{"cluster-admins"}, entry.Groups)
+	assert.True(t, entry.APIKey)
+	assert.Equal(t, "10.0.0.1", entry.SourceIP)
+	assert.Equal(t, time.Unix(1700000000, 0), entry.Time)
+	assert.Equal(t, "CheckConfig", entry.Type)
+	assert.Equal(t, "default", entry.Namespace)
+	assert.Equal(t, "cpu", entry.Name)
+	assert.JSONEq(t, `{"command": "check-cpu --warning 80"}`, string(entry.Diff))
+
+	// The entry is also written as a JSON line
+	var written store.AuditEntry
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &written))
+	assert.Equal(t, entry.Name, written.Name)
+	assert.Equal(t, byte('\n'), buf.Bytes()[buf.Len()-1])
+}
+
+func TestStoreCreate(t *testing.T) {
+	s, cs, rec, _ := newTestStore(t)
+
+	user := corev2.FixtureUser("alice")
+	user.Password = "P@ssw0rd!"
+	req := storev2.NewResourceRequestFromResource(user)
+	cs.On("Get", mock.Anything, req).Return(nil, &store.ErrNotFound{Key: "alice"})
+	cs.On("CreateOrUpdate", mock.Anything, req, mock.Anything).Return(nil)
+
+	w, err := wrap.Resource(user)
+	require.NoError(t, err)
+	require.NoError(t, s.GetConfigStore().CreateOrUpdate(testContext(), req, w))
+
+	require.Len(t, rec.entries, 1)
+	entry := rec.entries[0]
+	assert.Equal(t, VerbCreate, entry.Verb)
+	assert.Nil(t, entry.Before)
+	assert.Nil(t, entry.Diff)
+
+	// The password is redacted
+	var after map[string]interface{}
+	require.NoError(t, json.Unmarshal(entry.After, &after))
+	assert.Equal(t, "alice", after["username"])
+	assert.NotContains(t, after, "password")
+	assert.NotContains(t, after, "password_hash")
+}
+
+func TestStoreCreateVaultProvider(t *testing.T) {
+	s, cs, rec, buf := newTestStore(t)
+
+	provider := secretsv1.FixtureVaultProvider("vault")
+	req := storev2.NewResourceRequestFromResource(provider)
+	cs.On("Get", mock.Anything, req).Return(nil, &store.ErrNotFound{Key: "vault"})
+	cs.On("CreateOrUpdate", mock.Anything, req, mock.Anything).Return(nil)
+
+	w, err := wrap.Resource(provider)
+	require.NoError(t, err)
+	require.NoError(t, s.GetConfigStore().CreateOrUpdate(testContext(), req, w))
+
+	// The token is redacted, but the provider is not modified
+	require.Len(t, rec.entries, 1)
+	after := string(rec.entries[0].After)
+	assert.Contains(t, after, "https://vault.example.com:8200")
+	assert.NotContains(t, after, "VAULT_TOKEN")
+	assert.NotContains(t, buf.String(), "VAULT_TOKEN")
+	assert.Equal(t, "VAULT_TOKEN", provider.Client.Token)
+}
+
+func TestMarshalRedacted(t *testing.T) {
+	vault := secretsv1.FixtureVaultProvider("vault")
+	vault.Client.Token = ""
+	vault.Client.AppRole = &secretsv1.VaultAppRole{RoleID: "role", SecretID: "APPROLE_SECRET_ID"}
+	oidc := authv2.FixtureOIDC("oidc")
+	oidc.ClientSecret = "OIDC_CLIENT_SECRET"
+	ldap := authv2.FixtureLDAP("ldap")
+	ldap.Servers[0].Binding = &authv2.LDAPBinding{UserDN: "cn=sensu", Password: "LDAP_PASSWORD"}
+
+	b, err := marshal("VaultProvider", vault)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"role_id":"role"`)
+	assert.NotContains(t, string(b), "APPROLE_SECRET_ID")
+
+	b, err = marshal("OIDC", oidc)
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), "OIDC_CLIENT_SECRET")
+
+	b, err = marshal("LDAP", ldap)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "cn=sensu")
+	assert.NotContains(t, string(b), "LDAP_PASSWORD")
+}
+
+func TestStoreDelete(t *testing.T) {
+	s, cs, rec, _ := newTestStore(t)
+
+	check := corev2.FixtureCheckConfig("cpu")
+	req := storev2.NewResourceRequestFromResource(check)
+	w, err := wrap.Resource(check)
+	require.NoError(t, err)
+	cs.On("Get", mock.Anything, req).Return(w, nil)
+	cs.On("Delete", mock.Anything, req).Return(nil)
+
+	require.NoError(t, s.GetConfigStore().Delete(testContext(), req))
+	require.Len(t, rec.entries, 1)
+	assert.Equal(t, VerbDelete, rec.entries[0].Verb)
+	assert.NotNil(t, rec.entries[0].Before)
+	assert.Nil(t, rec.entries[0].After)
+}
+
+func TestStoreFailedChange(t *testing.T) {
+	s, cs, rec, buf := newTestStore(t)
+
+	check := corev2.FixtureCheckConfig("cpu")
+	req := storev2.NewResourceRequestFromResource(check)
+	cs.On("Get", mock.Anything, req).Return(nil, &store.ErrNotFound{Key: "cpu"})
+	cs.On("Delete", mock.Anything, req).Return(errors.New("error"))
+
+	// The changes that fail are not audited
+	require.Error(t, s.GetConfigStore().Delete(testContext(), req))
+	assert.Empty(t, rec.entries)
+	assert.Zero(t, buf.Len())
+}
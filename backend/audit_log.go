@@ -0,0 +1,76 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+	"time"
+
+	"github.com/sensu/sensu-go/backend/audit"
+	"github.com/sensu/sensu-go/backend/logging"
+	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+// auditLogPruneInterval is the interval at which expired audit log entries
+// are deleted.
+const auditLogPruneInterval = time.Hour
+
+// PruneAuditLogLoop periodically deletes the audit log entries that are older
+// than the given retention, until ctx is cancelled.
+func PruneAuditLogLoop(ctx context.Context, s store.AuditStore, retention time.Duration) {
+	ticker := time.NewTicker(auditLogPruneInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			pruned, err := s.PruneAuditEntries(ctx, time.Now().Add(-retention))
+			if err != nil {
+				logger.WithError(err).Error("error pruning audit log")
+				continue
+			}
+			if pruned > 0 {
+				logger.WithField("entries", pruned).Debug("pruned audit log")
+			}
+		}
+	}
+}
+
+// newAuditor returns the auditor of the changes made through the API, or nil
+// if the audit log is disabled. The audit log file is reopened on SIGHUP, so
+// that it can be rotated, and closed when ctx is done.
+func newAuditor(ctx context.Context, bus messaging.MessageBus, s store.AuditStore, config *Config) (*audit.Auditor, error) {
+	if config.AuditLogFile == "" && !config.AuditLogDatabase {
+		return nil, nil
+	}
+	auditor := &audit.Auditor{}
+	if config.AuditLogDatabase {
+		auditor.Store = s
+	}
+	if config.AuditLogFile == "" {
+		return auditor, nil
+	}
+
+	consumer := fmt.Sprintf("filelogger://%s", config.AuditLogFile)
+	sighup := make(messaging.ChanSubscriber, 1)
+	subscription, err := bus.Subscribe(messaging.SignalTopic(syscall.SIGHUP), consumer, sighup)
+	if err != nil {
+		return nil, fmt.Errorf("unable to subscribe to SIGHUP signal notifications: %s", err)
+	}
+	writer, err := logging.NewRotateWriter(config.AuditLogFile, sighup)
+	if err != nil {
+		_ = subscription.Cancel()
+		close(sighup)
+		return nil, fmt.Errorf("unable to open audit log file: %s", err)
+	}
+	go func() {
+		<-ctx.Done()
+		_ = subscription.Cancel()
+		close(sighup)
+		_ = writer.Close()
+	}()
+	auditor.Writer = writer
+	return auditor, nil
+}
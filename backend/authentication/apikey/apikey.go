@@ -0,0 +1,200 @@
+// Package apikey provides the optional attributes of the API keys, which are
+// stored in the annotations of the keys: their description, their expiration
+// and the scope that restricts their requests.
+package apikey
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/authorization"
+)
+
+const (
+	// DescriptionAnnotation is the annotation of the description of an API
+	// key.
+	DescriptionAnnotation = "sensu.io/api_key/description"
+
+	// ExpiresAtAnnotation is the annotation of the expiration of an API key,
+	// in the RFC 3339 format. The API key never expires without it.
+	ExpiresAtAnnotation = "sensu.io/api_key/expires_at"
+
+	// NamespacesAnnotation is the annotation of the comma-separated list of
+	// namespaces that the requests of an API key are restricted to.
+	NamespacesAnnotation = "sensu.io/api_key/namespaces"
+
+	// RulesAnnotation is the annotation of the RBAC rules, in JSON, that the
+	// requests of an API key are restricted to.
+	RulesAnnotation = "sensu.io/api_key/rules"
+
+	// LastUsedAnnotation is the annotation of the last time an API key was
+	// used, in the RFC 3339 format. It is added by the API when the key is
+	// retrieved, and is not stored.
+	LastUsedAnnotation = "sensu.io/api_key/last_used"
+)
+
+// ErrExpired is returned when an expired API key is used.
+var ErrExpired = errors.New("API key expired")
+
+// Description returns the description of the API key.
+func Description(key *corev2.APIKey) string {
+	return key.Annotations[DescriptionAnnotation]
+}
+
+// SetDescription sets the description of the API key.
+func SetDescription(key *corev2.APIKey, description string) {
+	setAnnotation(key, DescriptionAnnotation, description)
+}
+
+// ExpiresAt returns the expiration of the API key, or the zero time if the
+// key never expires.
+func ExpiresAt(key *corev2.APIKey) (time.Time, error) {
+	value, ok := key.Annotations[ExpiresAtAnnotation]
+	if !ok {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s annotation: %s", ExpiresAtAnnotation, err)
+	}
+	return t, nil
+}
+
+// SetExpiresAt sets the expiration of the API key. The key never expires if
+// t is the zero time.
+func SetExpiresAt(key *corev2.APIKey, t time.Time) {
+	if t.IsZero() {
+		delete(key.Annotations, ExpiresAtAnnotation)
+		return
+	}
+	setAnnotation(key, ExpiresAtAnnotation, t.UTC().Format(time.RFC3339))
+}
+
+// Expired returns whether the API key is expired at the given time. A key
+// with an invalid expiration is expired.
+func Expired(key *corev2.APIKey, now time.Time) bool {
+	expiresAt, err := ExpiresAt(key)
+	if err != nil {
+		return true
+	}
+	return !expiresAt.IsZero() && !now.Before(expiresAt)
+}
+
+// LastUsed returns the last time the API key was used, or the zero time if
+// it is unknown.
+func LastUsed(key *corev2.APIKey) time.Time {
+	t, _ := time.Parse(time.RFC3339, key.Annotations[LastUsedAnnotation])
+	return t
+}
+
+// SetLastUsed sets the last time the API key was used.
+func SetLastUsed(key *corev2.APIKey, t time.Time) {
+	setAnnotation(key, LastUsedAnnotation, t.UTC().Format(time.RFC3339))
+}
+
+// Scope returns the scope that the requests of the API key are restricted
+// to, or nil if they are not restricted.
+func Scope(key *corev2.APIKey) (*authorization.Scope, error) {
+	var scope authorization.Scope
+	if value := key.Annotations[NamespacesAnnotation]; value != "" {
+		for _, namespace := range strings.Split(value, ",") {
+			if namespace = strings.TrimSpace(namespace); namespace != "" {
+				scope.Namespaces = append(scope.Namespaces, namespace)
+			}
+		}
+	}
+	if value := key.Annotations[RulesAnnotation]; value != "" {
+		if err := json.Unmarshal([]byte(value), &scope.Rules); err != nil {
+			return nil, fmt.Errorf("invalid %s annotation: %s", RulesAnnotation, err)
+		}
+		if len(scope.Rules) == 0 {
+			return nil, fmt.Errorf("invalid %s annotation: no rules", RulesAnnotation)
+		}
+	}
+	if len(scope.Namespaces) == 0 && len(scope.Rules) == 0 {
+		return nil, nil
+	}
+	return &scope, nil
+}
+
+// SetScope restricts the requests of the API key to the given scope.
+func SetScope(key *corev2.APIKey, scope *authorization.Scope) error {
+	delete(key.Annotations, NamespacesAnnotation)
+	delete(key.Annotations, RulesAnnotation)
+	if scope == nil {
+		return nil
+	}
+	if len(scope.Namespaces) > 0 {
+		setAnnotation(key, NamespacesAnnotation, strings.Join(scope.Namespaces, ","))
+	}
+	if len(scope.Rules) > 0 {
+		rules, err := json.Marshal(scope.Rules)
+		if err != nil {
+			return err
+		}
+		setAnnotation(key, RulesAnnotation, string(rules))
+	}
+	return nil
+}
+
+// Validate returns an error if the attributes of the API key are invalid.
+func Validate(key *corev2.APIKey) error {
+	if _, err := ExpiresAt(key); err != nil {
+		return err
+	}
+	scope, err := Scope(key)
+	if err != nil {
+		return err
+	}
+	if scope == nil {
+		return nil
+	}
+	for _, rule := range scope.Rules {
+		if len(rule.Verbs) == 0 || len(rule.Resources) == 0 {
+			return fmt.Errorf("invalid %s annotation: rules must have verbs and resources", RulesAnnotation)
+		}
+	}
+	if len(scope.Rules) > 0 {
+		// the rules of the scope are validated like the rules of a role
+		role := &corev2.ClusterRole{
+			ObjectMeta: corev2.ObjectMeta{Name: "scope"},
+			Rules:      scope.Rules,
+		}
+		if err := role.Validate(); err != nil {
+			return fmt.Errorf("invalid %s annotation: %s", RulesAnnotation, err)
+		}
+	}
+	for _, namespace := range scope.Namespaces {
+		if err := corev2.ValidateName(namespace); err != nil {
+			return fmt.Errorf("invalid %s annotation: %s", NamespacesAnnotation, err)
+		}
+	}
+	return nil
+}
+
+// ImmutableAnnotations are the annotations of an API key that can't be changed
+// once the key is created, so that the restrictions of a key can't be lifted
+// by updating it.
+var ImmutableAnnotations = []string{ExpiresAtAnnotation, NamespacesAnnotation, RulesAnnotation}
+
+// ValidateUpdate returns an error if the update of an API key from before to
+// after changes one of its immutable annotations.
+func ValidateUpdate(before, after *corev2.APIKey) error {
+	for _, name := range ImmutableAnnotations {
+		if before.Annotations[name] != after.Annotations[name] {
+			return fmt.Errorf("the %s annotation of an API key can't be changed", name)
+		}
+	}
+	return nil
+}
+
+func setAnnotation(key *corev2.APIKey, name, value string) {
+	if key.Annotations == nil {
+		key.Annotations = make(map[string]string)
+	}
+	key.Annotations[name] = value
+}
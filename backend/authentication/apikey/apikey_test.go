@@ -0,0 +1,229 @@
+package apikey
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/authorization"
+)
+
+func TestExpired(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        bool
+	}{
+		{
+			name: "no expiration",
+			want: false,
+		},
+		{
+			name:        "not expired",
+			annotations: map[string]string{ExpiresAtAnnotation: "2024-01-01T13:00:00Z"},
+			want:        false,
+		},
+		{
+			name:        "expired",
+			annotations: map[string]string{ExpiresAtAnnotation: "2024-01-01T12:00:00Z"},
+			want:        true,
+		},
+		{
+			name:        "invalid expiration",
+			annotations: map[string]string{ExpiresAtAnnotation: "tomorrow"},
+			want:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := corev2.FixtureAPIKey("foo", "admin")
+			key.Annotations = tt.annotations
+			if got := Expired(key, now); got != tt.want {
+				t.Errorf("Expired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetExpiresAt(t *testing.T) {
+	key := corev2.FixtureAPIKey("foo", "admin")
+	expiresAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	SetExpiresAt(key, expiresAt)
+	got, err := ExpiresAt(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(expiresAt) {
+		t.Errorf("ExpiresAt() = %v, want %v", got, expiresAt)
+	}
+
+	SetExpiresAt(key, time.Time{})
+	if _, ok := key.Annotations[ExpiresAtAnnotation]; ok {
+		t.Error("expected no expiration")
+	}
+}
+
+func TestScope(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        *authorization.Scope
+		wantErr     bool
+	}{
+		{
+			name: "unrestricted",
+			want: nil,
+		},
+		{
+			name:        "namespaces",
+			annotations: map[string]string{NamespacesAnnotation: "ci, staging,"},
+			want:        &authorization.Scope{Namespaces: []string{"ci", "staging"}},
+		},
+		{
+			name:        "rules",
+			annotations: map[string]string{RulesAnnotation: `[{"verbs":["get","list"],"resources":["checks"]}]`},
+			want: &authorization.Scope{Rules: []corev2.Rule{
+				{Verbs: []string{"get", "list"}, Resources: []string{"checks"}},
+			}},
+		},
+		{
+			name:        "invalid rules",
+			annotations: map[string]string{RulesAnnotation: `{"verbs":["get"]}`},
+			wantErr:     true,
+		},
+		{
+			name:        "empty rules",
+			annotations: map[string]string{RulesAnnotation: `[]`},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := corev2.FixtureAPIKey("foo", "admin")
+			key.Annotations = tt.annotations
+			got, err := Scope(key)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Scope() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Scope() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetScope(t *testing.T) {
+	key := corev2.FixtureAPIKey("foo", "admin")
+	scope := &authorization.Scope{
+		Namespaces: []string{"ci"},
+		Rules: []corev2.Rule{
+			{Verbs: []string{"get", "list"}, Resources: []string{"*"}},
+		},
+	}
+	if err := SetScope(key, scope); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Scope(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, scope) {
+		t.Errorf("Scope() = %#v, want %#v", got, scope)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantErr     bool
+	}{
+		{
+			name: "no annotations",
+		},
+		{
+			name: "valid annotations",
+			annotations: map[string]string{
+				DescriptionAnnotation: "ci pipeline",
+				ExpiresAtAnnotation:   "2024-01-01T12:00:00Z",
+				NamespacesAnnotation:  "ci",
+				RulesAnnotation:       `[{"verbs":["get","list"],"resources":["*"]}]`,
+			},
+		},
+		{
+			name:        "invalid expiration",
+			annotations: map[string]string{ExpiresAtAnnotation: "tomorrow"},
+			wantErr:     true,
+		},
+		{
+			name:        "invalid namespace",
+			annotations: map[string]string{NamespacesAnnotation: "c i"},
+			wantErr:     true,
+		},
+		{
+			name:        "invalid verb",
+			annotations: map[string]string{RulesAnnotation: `[{"verbs":["steal"],"resources":["*"]}]`},
+			wantErr:     true,
+		},
+		{
+			name:        "no resources",
+			annotations: map[string]string{RulesAnnotation: `[{"verbs":["get"]}]`},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := corev2.FixtureAPIKey("foo", "admin")
+			key.Annotations = tt.annotations
+			if err := Validate(key); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateUpdate(t *testing.T) {
+	tests := []struct {
+		name    string
+		before  map[string]string
+		after   map[string]string
+		wantErr bool
+	}{
+		{
+			name:   "description changed",
+			before: map[string]string{DescriptionAnnotation: "ci", NamespacesAnnotation: "ci"},
+			after:  map[string]string{DescriptionAnnotation: "deploys", NamespacesAnnotation: "ci"},
+		},
+		{
+			name:    "expiration removed",
+			before:  map[string]string{ExpiresAtAnnotation: "2024-01-01T00:00:00Z"},
+			after:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "namespaces widened",
+			before:  map[string]string{NamespacesAnnotation: "ci"},
+			after:   map[string]string{NamespacesAnnotation: "ci,default"},
+			wantErr: true,
+		},
+		{
+			name:    "rules added",
+			before:  nil,
+			after:   map[string]string{RulesAnnotation: `[{"verbs":["get"],"resources":["*"]}]`},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := corev2.FixtureAPIKey("foo", "admin")
+			before.Annotations = tt.before
+			after := corev2.FixtureAPIKey("foo", "admin")
+			after.Annotations = tt.after
+			if err := ValidateUpdate(before, after); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateUpdate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -4,12 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/sirupsen/logrus"
 
 	corev2 "github.com/sensu/core/v2"
 	corev3 "github.com/sensu/core/v3"
+	"github.com/sensu/sensu-go/backend/authentication/providers/basic"
 )
 
 // Authenticator contains the list of authentication providers
@@ -18,16 +20,15 @@ type Authenticator struct {
 	providers map[string]corev3.AuthProvider
 }
 
-// Authenticate with the configured authentication providers
+// Authenticate with the configured authentication providers. The providers
+// are tried in a stable order, so the same provider always authenticates a
+// user present in several providers: the basic provider first, so local users
+// take precedence, then the other providers by name.
 func (a *Authenticator) Authenticate(ctx context.Context, username, password string) (*corev2.Claims, error) {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
 
-	// TODO(palourde): The Go runtime randomizes map iteration order so the
-	// providers resolution order might vary on each authentication, and
-	// consequently provoke weird behavior if the same username/password
-	// combinaison exists in multiple providers.
-	for _, provider := range a.providers {
+	for _, provider := range a.sortedProviders() {
 		claims, err := provider.Authenticate(ctx, username, password)
 		if err != nil || claims == nil {
 			logger.WithError(err).Debugf(
@@ -77,6 +78,22 @@ func (a *Authenticator) Refresh(ctx context.Context, claims *corev2.Claims) (*co
 	)
 }
 
+// sortedProviders returns the providers in the order they are tried. The mutex
+// must be held.
+func (a *Authenticator) sortedProviders() []corev3.AuthProvider {
+	providers := make([]corev3.AuthProvider, 0, len(a.providers))
+	for _, provider := range a.providers {
+		providers = append(providers, provider)
+	}
+	sort.Slice(providers, func(i, j int) bool {
+		if basicI, basicJ := providers[i].Type() == basic.Type, providers[j].Type() == basic.Type; basicI != basicJ {
+			return basicI
+		}
+		return providers[i].Name() < providers[j].Name()
+	})
+	return providers
+}
+
 // AddProvider adds a provided provider to the list of configured providers
 func (a *Authenticator) AddProvider(provider corev3.AuthProvider) {
 	a.mu.Lock()
@@ -0,0 +1,71 @@
+package authentication
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+	"github.com/sensu/sensu-go/backend/authentication/providers/basic"
+)
+
+// testProvider authenticates any user with its password.
+type testProvider struct {
+	corev3.AuthProvider
+	name     string
+	typ      string
+	password string
+}
+
+func (p *testProvider) Authenticate(ctx context.Context, username, password string) (*corev2.Claims, error) {
+	if password != p.password {
+		return nil, errors.New("wrong password")
+	}
+	return &corev2.Claims{Provider: corev2.AuthProviderClaims{ProviderID: p.name, UserID: username}}, nil
+}
+
+func (p *testProvider) Name() string {
+	return p.name
+}
+
+func (p *testProvider) Type() string {
+	return p.typ
+}
+
+func TestAuthenticateProvidersOrder(t *testing.T) {
+	a := &Authenticator{}
+	a.AddProvider(&testProvider{name: "zeta", typ: "ldap", password: "P@ssw0rd!"})
+	a.AddProvider(&testProvider{name: "alpha", typ: "ldap", password: "P@ssw0rd!"})
+	a.AddProvider(&testProvider{name: "default", typ: basic.Type, password: "P@ssw0rd!"})
+	a.AddProvider(&testProvider{name: "beta", typ: "ldap", password: "other"})
+
+	// The basic provider takes precedence
+	for i := 0; i < 20; i++ {
+		claims, err := a.Authenticate(context.Background(), "foo", "P@ssw0rd!")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := claims.Provider.ProviderID, "default"; got != want {
+			t.Fatalf("authenticated by %q, want %q", got, want)
+		}
+	}
+
+	// Then the providers are tried by name
+	if err := a.RemoveProvider("default"); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		claims, err := a.Authenticate(context.Background(), "foo", "P@ssw0rd!")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := claims.Provider.ProviderID, "alpha"; got != want {
+			t.Fatalf("authenticated by %q, want %q", got, want)
+		}
+	}
+
+	if _, err := a.Authenticate(context.Background(), "foo", "wrong"); err == nil {
+		t.Fatal("expected an error")
+	}
+}
@@ -0,0 +1,342 @@
+// Package ldap provides an LDAP and Active Directory authentication provider.
+// Users are looked up in the directory, authenticated by binding with their DN
+// and password, and the groups they are members of are mapped to RBAC groups.
+package ldap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	jwt "github.com/golang-jwt/jwt/v4"
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+	authv2 "github.com/sensu/sensu-go/api/authentication/v2"
+	sensujwt "github.com/sensu/sensu-go/backend/authentication/jwt"
+)
+
+// Type represents the type of the LDAP authentication provider
+const Type = "ldap"
+
+var (
+	// ErrEmptyUsernamePassword is the error returned by the provider when one
+	// tries to authenticate with empty username and password.
+	ErrEmptyUsernamePassword = errors.New("the username and the password must not be empty")
+
+	// ErrUserNotFound is the error returned by the provider when the user is
+	// not found in the directory.
+	ErrUserNotFound = errors.New("user not found")
+)
+
+var _ corev3.AuthProvider = new(Provider)
+
+// DefaultTimeout is the timeout of the operations made with a server when
+// none is configured.
+const DefaultTimeout = 10 * time.Second
+
+// unavailableError is returned when a server can't be used, and the next
+// server must be tried.
+type unavailableError struct {
+	err error
+}
+
+func (e *unavailableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *unavailableError) Unwrap() error {
+	return e.err
+}
+
+// Provider represents an LDAP authentication provider
+type Provider struct {
+	// Config is the configuration of the provider
+	Config *authv2.LDAP
+
+	// Timeout is the timeout of the operations made with a server. Defaults
+	// to DefaultTimeout.
+	Timeout time.Duration
+}
+
+// New returns a provider for the given configuration.
+func New(config *authv2.LDAP) *Provider {
+	return &Provider{Config: config}
+}
+
+// GetMetadata returns the provider metadata
+func (p *Provider) GetMetadata() *corev2.ObjectMeta {
+	return p.Config.GetMetadata()
+}
+
+// SetMetadata sets the provider metadata
+func (p *Provider) SetMetadata(meta *corev2.ObjectMeta) {
+	p.Config.SetMetadata(meta)
+}
+
+// StoreName returns the store name of the provider configuration
+func (p *Provider) StoreName() string {
+	return p.Config.StoreName()
+}
+
+// RBACName returns the RBAC name of the provider configuration
+func (p *Provider) RBACName() string {
+	return p.Config.RBACName()
+}
+
+// URIPath returns the API path of the provider configuration
+func (p *Provider) URIPath() string {
+	return p.Config.URIPath()
+}
+
+// Validate validates the provider configuration
+func (p *Provider) Validate() error {
+	return p.Config.Validate()
+}
+
+// Name returns the provider name
+func (p *Provider) Name() string {
+	if p.Config.Metadata == nil {
+		return ""
+	}
+	return p.Config.Metadata.Name
+}
+
+// Type returns the provider type
+func (p *Provider) Type() string {
+	return Type
+}
+
+// Authenticate a user, with the provided credentials, against the directory.
+// The servers are tried in order, until one of them can be reached.
+func (p *Provider) Authenticate(ctx context.Context, username, password string) (*corev2.Claims, error) {
+	if username == "" || password == "" {
+		return nil, ErrEmptyUsernamePassword
+	}
+	return p.withServer(ctx, func(conn *ldap.Conn, server *authv2.LDAPServer) (*corev2.Claims, error) {
+		user, err := p.findUser(conn, server, username)
+		if err != nil {
+			return nil, err
+		}
+		if err := conn.Bind(user.DN, password); err != nil {
+			if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+				return nil, fmt.Errorf("wrong password for user %s", username)
+			}
+			return nil, err
+		}
+		// Search the groups with the binding account, since users might not be
+		// allowed to
+		if err := p.bind(conn, server); err != nil {
+			return nil, err
+		}
+		groups, err := p.findGroups(conn, server, user.DN)
+		if err != nil {
+			return nil, err
+		}
+		return p.claims(username, groups)
+	})
+}
+
+// Refresh the claims of a user. The user is looked up again in the
+// directory, so users that were removed, or removed from groups, are
+// reflected in the new claims.
+func (p *Provider) Refresh(ctx context.Context, claims *corev2.Claims) (*corev2.Claims, error) {
+	username := claims.Provider.UserID
+	return p.withServer(ctx, func(conn *ldap.Conn, server *authv2.LDAPServer) (*corev2.Claims, error) {
+		user, err := p.findUser(conn, server, username)
+		if err != nil {
+			return nil, err
+		}
+		groups, err := p.findGroups(conn, server, user.DN)
+		if err != nil {
+			return nil, err
+		}
+		return p.claims(username, groups)
+	})
+}
+
+// withServer calls fn with a connection to the first server that can be
+// reached, bound with its binding account.
+func (p *Provider) withServer(ctx context.Context, fn func(*ldap.Conn, *authv2.LDAPServer) (*corev2.Claims, error)) (*corev2.Claims, error) {
+	var err error
+	for _, server := range p.Config.Servers {
+		var claims *corev2.Claims
+		claims, err = p.tryServer(ctx, server, fn)
+		if err == nil {
+			return claims, nil
+		}
+		var unavailable *unavailableError
+		if !errors.As(err, &unavailable) {
+			return nil, err
+		}
+		logger.WithError(err).WithField("server", address(server)).Warn("ldap server unavailable")
+	}
+	if err == nil {
+		return nil, errors.New("no ldap server configured")
+	}
+	return nil, fmt.Errorf("no ldap server available: %s", err)
+}
+
+func (p *Provider) tryServer(ctx context.Context, server *authv2.LDAPServer, fn func(*ldap.Conn, *authv2.LDAPServer) (*corev2.Claims, error)) (*corev2.Claims, error) {
+	conn, err := p.dial(server)
+	if err != nil {
+		return nil, &unavailableError{err: err}
+	}
+	defer conn.Close()
+
+	// The client has no context support, so the connection is closed to
+	// abort the operation in progress when ctx is cancelled
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	if err := p.bind(conn, server); err != nil {
+		return nil, &unavailableError{err: err}
+	}
+	return fn(conn, server)
+}
+
+// dial connects to a server, with the configured security.
+func (p *Provider) dial(server *authv2.LDAPServer) (*ldap.Conn, error) {
+	var tlsOptions = corev2.TLSOptions{
+		CertFile:           server.ClientCertFile,
+		KeyFile:            server.ClientKeyFile,
+		TrustedCAFile:      server.TrustedCAFile,
+		InsecureSkipVerify: server.InsecureSkipVerify,
+	}
+	tlsConfig, err := tlsOptions.ToClientTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig.ServerName = server.Host
+
+	timeout := p.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	scheme := "ldap"
+	opts := []ldap.DialOpt{ldap.DialWithDialer(&net.Dialer{Timeout: timeout})}
+	if server.GetSecurity() == authv2.LDAPSecurityTLS {
+		scheme = "ldaps"
+		opts = append(opts, ldap.DialWithTLSConfig(tlsConfig))
+	}
+	conn, err := ldap.DialURL(scheme+"://"+address(server), opts...)
+	if err != nil {
+		return nil, err
+	}
+	conn.SetTimeout(timeout)
+
+	if server.GetSecurity() == authv2.LDAPSecurityStartTLS {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+// bind binds the connection with the binding account of the server, if any.
+func (p *Provider) bind(conn *ldap.Conn, server *authv2.LDAPServer) error {
+	if server.Binding == nil {
+		return nil
+	}
+	if err := conn.Bind(server.Binding.UserDN, server.Binding.Password); err != nil {
+		return fmt.Errorf("could not bind as %s: %w", server.Binding.UserDN, err)
+	}
+	return nil
+}
+
+// findUser returns the entry of a user.
+func (p *Provider) findUser(conn *ldap.Conn, server *authv2.LDAPServer, username string) (*ldap.Entry, error) {
+	search := server.UserSearch
+	filter := fmt.Sprintf("(&(objectClass=%s)(%s=%s)%s)",
+		ldap.EscapeFilter(search.GetObjectClass()),
+		search.GetAttribute(),
+		ldap.EscapeFilter(username),
+		search.Filter,
+	)
+	result, err := conn.Search(&ldap.SearchRequest{
+		BaseDN:     search.BaseDN,
+		Scope:      ldap.ScopeWholeSubtree,
+		SizeLimit:  2,
+		Filter:     filter,
+		Attributes: []string{search.GetAttribute()},
+	})
+	var entries []*ldap.Entry
+	if result != nil {
+		entries = result.Entries
+	}
+	if ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) || len(entries) > 1 {
+		return nil, fmt.Errorf("multiple users match the username %s", username)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not search for user %s: %w", username, err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrUserNotFound, username)
+	}
+	return entries[0], nil
+}
+
+// findGroups returns the names of the groups of a user.
+func (p *Provider) findGroups(conn *ldap.Conn, server *authv2.LDAPServer, dn string) ([]string, error) {
+	search := server.GroupSearch
+	filter := fmt.Sprintf("(&(objectClass=%s)(%s=%s)%s)",
+		ldap.EscapeFilter(search.GetObjectClass()),
+		search.GetAttribute(),
+		ldap.EscapeFilter(dn),
+		search.Filter,
+	)
+	result, err := conn.Search(&ldap.SearchRequest{
+		BaseDN:     search.BaseDN,
+		Scope:      ldap.ScopeWholeSubtree,
+		Filter:     filter,
+		Attributes: []string{search.GetNameAttribute()},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not search for the groups of %s: %w", dn, err)
+	}
+
+	groups := make([]string, 0, len(result.Entries))
+	for _, entry := range result.Entries {
+		names := entry.GetEqualFoldAttributeValues(search.GetNameAttribute())
+		if len(names) == 0 {
+			logger.WithField("group", entry.DN).Debugf("group has no %s attribute", search.GetNameAttribute())
+			continue
+		}
+		groups = append(groups, p.Config.GroupsPrefix+names[0])
+	}
+	return groups, nil
+}
+
+func (p *Provider) claims(username string, groups []string) (*corev2.Claims, error) {
+	jti, err := sensujwt.GenJTI()
+	if err != nil {
+		return nil, err
+	}
+	return &corev2.Claims{
+		StandardClaims: jwt.StandardClaims{
+			Id:      jti,
+			Subject: p.Config.UsernamePrefix + username,
+		},
+		Groups: groups,
+		Provider: corev2.AuthProviderClaims{
+			ProviderID:   p.Name(),
+			ProviderType: Type,
+			UserID:       username,
+		},
+	}, nil
+}
+
+func address(server *authv2.LDAPServer) string {
+	return net.JoinHostPort(server.Host, strconv.Itoa(server.GetPort()))
+}
@@ -0,0 +1,231 @@
+package ldap
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev2 "github.com/sensu/core/v2"
+	authv2 "github.com/sensu/sensu-go/api/authentication/v2"
+	"github.com/sensu/sensu-go/testing/mockldap"
+)
+
+const (
+	bindDN   = "cn=binder,dc=acme,dc=org"
+	userDN   = "uid=jdoe,ou=users,dc=acme,dc=org"
+	password = "P@ssw0rd!"
+)
+
+func newTestServer(t *testing.T, ldaps bool) *mockldap.Server {
+	t.Helper()
+	newServer := mockldap.NewServer
+	if ldaps {
+		newServer = mockldap.NewTLSServer
+	}
+	server, err := newServer()
+	require.NoError(t, err)
+	t.Cleanup(server.Close)
+
+	server.AddEntry(bindDN, "binder", map[string][]string{"cn": {"binder"}})
+	server.AddEntry(userDN, password, map[string][]string{
+		"objectClass": {"person"},
+		"uid":         {"jdoe"},
+	})
+	server.AddEntry("uid=disabled,ou=users,dc=acme,dc=org", password, map[string][]string{
+		"objectClass": {"person"},
+		"uid":         {"disabled"},
+		"disabled":    {"TRUE"},
+	})
+	server.AddEntry("cn=ops,ou=groups,dc=acme,dc=org", "", map[string][]string{
+		"objectClass": {"groupOfNames"},
+		"cn":          {"ops"},
+		"member":      {userDN},
+	})
+	server.AddEntry("cn=dev,ou=groups,dc=acme,dc=org", "", map[string][]string{
+		"objectClass": {"groupOfNames"},
+		"cn":          {"dev"},
+		"member":      {"uid=other,ou=users,dc=acme,dc=org"},
+	})
+	return server
+}
+
+func serverConfig(t *testing.T, server *mockldap.Server, security string) *authv2.LDAPServer {
+	t.Helper()
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, server.CACertificate, 0600))
+	return &authv2.LDAPServer{
+		Host:          server.Host(),
+		Port:          server.Port(),
+		Security:      security,
+		TrustedCAFile: caFile,
+		Binding:       &authv2.LDAPBinding{UserDN: bindDN, Password: "binder"},
+		UserSearch: authv2.LDAPUserSearch{
+			BaseDN: "ou=users,dc=acme,dc=org",
+			Filter: "(!(disabled=TRUE))",
+		},
+		GroupSearch: authv2.LDAPGroupSearch{
+			BaseDN: "ou=groups,dc=acme,dc=org",
+		},
+	}
+}
+
+func newTestProvider(servers ...*authv2.LDAPServer) *Provider {
+	config := authv2.FixtureLDAP("corp")
+	config.Servers = servers
+	config.UsernamePrefix = "ldap:"
+	config.GroupsPrefix = "ldap:"
+	p := New(config)
+	p.Timeout = time.Second
+	return p
+}
+
+func TestProviderAuthenticate(t *testing.T) {
+	for _, security := range []string{authv2.LDAPSecurityTLS, authv2.LDAPSecurityStartTLS, authv2.LDAPSecurityInsecure} {
+		t.Run(security, func(t *testing.T) {
+			server := newTestServer(t, security == authv2.LDAPSecurityTLS)
+			p := newTestProvider(serverConfig(t, server, security))
+
+			claims, err := p.Authenticate(context.Background(), "jdoe", password)
+			require.NoError(t, err)
+			assert.Equal(t, "ldap:jdoe", claims.Subject)
+			assert.Equal(t, []string{"ldap:ops"}, claims.Groups)
+			assert.NotEmpty(t, claims.Id)
+			assert.Equal(t, corev2.AuthProviderClaims{
+				ProviderID:   "corp",
+				ProviderType: Type,
+				UserID:       "jdoe",
+			}, claims.Provider)
+		})
+	}
+}
+
+func TestProviderAuthenticateErrors(t *testing.T) {
+	server := newTestServer(t, true)
+	p := newTestProvider(serverConfig(t, server, authv2.LDAPSecurityTLS))
+	ctx := context.Background()
+
+	_, err := p.Authenticate(ctx, "jdoe", "")
+	assert.Equal(t, ErrEmptyUsernamePassword, err)
+
+	_, err = p.Authenticate(ctx, "jdoe", "wrong")
+	assert.EqualError(t, err, "wrong password for user jdoe")
+
+	_, err = p.Authenticate(ctx, "unknown", password)
+	assert.True(t, errors.Is(err, ErrUserNotFound), err)
+
+	// The user search filter excludes disabled users
+	_, err = p.Authenticate(ctx, "disabled", password)
+	assert.True(t, errors.Is(err, ErrUserNotFound), err)
+
+	// Usernames can't inject filters
+	_, err = p.Authenticate(ctx, "*", password)
+	assert.True(t, errors.Is(err, ErrUserNotFound), err)
+	_, err = p.Authenticate(ctx, "jdoe)(uid=*", password)
+	assert.True(t, errors.Is(err, ErrUserNotFound), err)
+}
+
+func TestProviderAuthenticateMultipleUsers(t *testing.T) {
+	server := newTestServer(t, true)
+	server.AddEntry("uid=jdoe,ou=contractors,ou=users,dc=acme,dc=org", password, map[string][]string{
+		"objectClass": {"person"},
+		"uid":         {"jdoe"},
+	})
+	p := newTestProvider(serverConfig(t, server, authv2.LDAPSecurityTLS))
+
+	_, err := p.Authenticate(context.Background(), "jdoe", password)
+	assert.EqualError(t, err, "multiple users match the username jdoe")
+}
+
+func TestProviderAuthenticateUntrustedServer(t *testing.T) {
+	server := newTestServer(t, true)
+	config := serverConfig(t, server, authv2.LDAPSecurityTLS)
+	config.TrustedCAFile = ""
+	p := newTestProvider(config)
+
+	_, err := p.Authenticate(context.Background(), "jdoe", password)
+	assert.Error(t, err)
+
+	config.InsecureSkipVerify = true
+	_, err = p.Authenticate(context.Background(), "jdoe", password)
+	assert.NoError(t, err)
+}
+
+func TestProviderFailover(t *testing.T) {
+	down := newTestServer(t, true)
+	downConfig := serverConfig(t, down, authv2.LDAPSecurityTLS)
+	down.Close()
+
+	// A server with a wrong binding password can't be used either
+	misconfigured := newTestServer(t, true)
+	misconfiguredConfig := serverConfig(t, misconfigured, authv2.LDAPSecurityTLS)
+	misconfiguredConfig.Binding.Password = "wrong"
+
+	server := newTestServer(t, true)
+	p := newTestProvider(downConfig, misconfiguredConfig, serverConfig(t, server, authv2.LDAPSecurityTLS))
+
+	claims, err := p.Authenticate(context.Background(), "jdoe", password)
+	require.NoError(t, err)
+	assert.Equal(t, "ldap:jdoe", claims.Subject)
+
+	// Authentication failures don't fail over to the next server
+	p = newTestProvider(serverConfig(t, server, authv2.LDAPSecurityTLS), serverConfig(t, misconfigured, authv2.LDAPSecurityTLS))
+	misconfigured.AddEntry(userDN, "other", map[string][]string{
+		"objectClass": {"person"},
+		"uid":         {"jdoe"},
+	})
+	_, err = p.Authenticate(context.Background(), "jdoe", "other")
+	assert.EqualError(t, err, "wrong password for user jdoe")
+
+	// All servers down
+	p = newTestProvider(downConfig)
+	_, err = p.Authenticate(context.Background(), "jdoe", password)
+	assert.Error(t, err)
+}
+
+func TestProviderNestedGroups(t *testing.T) {
+	server := newTestServer(t, true)
+	server.AddEntry("cn=sre,ou=groups,dc=acme,dc=org", "", map[string][]string{
+		"objectClass": {"groupOfNames"},
+		"cn":          {"sre"},
+		"member":      {"cn=ops,ou=groups,dc=acme,dc=org"},
+	})
+	config := serverConfig(t, server, authv2.LDAPSecurityTLS)
+	config.GroupSearch.Attribute = "member:" + mockldap.InChainRule + ":"
+	p := newTestProvider(config)
+
+	claims, err := p.Authenticate(context.Background(), "jdoe", password)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"ldap:ops", "ldap:sre"}, claims.Groups)
+}
+
+func TestProviderRefresh(t *testing.T) {
+	server := newTestServer(t, true)
+	p := newTestProvider(serverConfig(t, server, authv2.LDAPSecurityTLS))
+	ctx := context.Background()
+
+	claims, err := p.Authenticate(ctx, "jdoe", password)
+	require.NoError(t, err)
+
+	// The user is added to a group
+	server.AddEntry("cn=dev,ou=groups,dc=acme,dc=org", "", map[string][]string{
+		"objectClass": {"groupOfNames"},
+		"cn":          {"dev"},
+		"member":      {userDN},
+	})
+	refreshed, err := p.Refresh(ctx, claims)
+	require.NoError(t, err)
+	assert.Equal(t, "ldap:jdoe", refreshed.Subject)
+	assert.ElementsMatch(t, []string{"ldap:ops", "ldap:dev"}, refreshed.Groups)
+	assert.Equal(t, claims.Provider, refreshed.Provider)
+
+	// The user is removed from the directory
+	server.RemoveEntry(userDN)
+	_, err = p.Refresh(ctx, claims)
+	assert.True(t, errors.Is(err, ErrUserNotFound), err)
+}
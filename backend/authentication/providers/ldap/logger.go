@@ -0,0 +1,7 @@
+package ldap
+
+import "github.com/sirupsen/logrus"
+
+var logger = logrus.WithFields(logrus.Fields{
+	"component": "authentication/ldap",
+})
@@ -0,0 +1,7 @@
+package oidc
+
+import "github.com/sirupsen/logrus"
+
+var logger = logrus.WithFields(logrus.Fields{
+	"component": "authentication/oidc",
+})
@@ -0,0 +1,404 @@
+// Package oidc provides an OpenID Connect authentication provider. Users are
+// authenticated with the authorization code flow, and the claims of the ID
+// token issued to them are mapped to a Sensu username and RBAC groups.
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt/v4"
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+	authv2 "github.com/sensu/sensu-go/api/authentication/v2"
+	sensujwt "github.com/sensu/sensu-go/backend/authentication/jwt"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/sensu/sensu-go/backend/store/memory"
+)
+
+// Type represents the type of the OIDC authentication provider
+const Type = "oidc"
+
+// ErrPasswordUnsupported is the error returned by the provider when one tries
+// to authenticate with a username and a password.
+var ErrPasswordUnsupported = errors.New("the oidc provider does not support password authentication")
+
+var _ corev3.AuthProvider = new(Provider)
+
+// defaultTimeout is the timeout of the requests made to the issuer when no
+// HTTP client is configured.
+const defaultTimeout = 10 * time.Second
+
+// signingMethods are the algorithms accepted for ID token signatures.
+var signingMethods = []string{
+	jwt.SigningMethodRS256.Alg(),
+	jwt.SigningMethodRS384.Alg(),
+	jwt.SigningMethodRS512.Alg(),
+	jwt.SigningMethodPS256.Alg(),
+	jwt.SigningMethodPS384.Alg(),
+	jwt.SigningMethodPS512.Alg(),
+	jwt.SigningMethodES256.Alg(),
+	jwt.SigningMethodES384.Alg(),
+	jwt.SigningMethodES512.Alg(),
+}
+
+// Provider represents an OpenID Connect authentication provider
+type Provider struct {
+	// Config is the configuration of the provider
+	Config *authv2.OIDC
+
+	// HTTPClient is used for the requests made to the issuer. Defaults to a
+	// client with a 10 seconds timeout.
+	HTTPClient *http.Client
+
+	// Store keeps the sessions of the users logged in with the provider.
+	// Defaults to a store in memory, which is neither shared with the other
+	// backends nor kept across updates of the provider.
+	Store store.OIDCStateStore
+
+	mu            sync.Mutex
+	discovery     *discoveryDocument
+	keys          map[string]interface{}
+	keysFetchedAt time.Time
+	memoryStore   store.OIDCStateStore
+}
+
+// New returns a provider for the given configuration.
+func New(config *authv2.OIDC) *Provider {
+	return &Provider{Config: config}
+}
+
+// states returns the store of the sessions of the provider.
+func (p *Provider) states() store.OIDCStateStore {
+	if p.Store != nil {
+		return p.Store
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.memoryStore == nil {
+		p.memoryStore = memory.NewOIDCStateStore()
+	}
+	return p.memoryStore
+}
+
+// GetMetadata returns the provider metadata
+func (p *Provider) GetMetadata() *corev2.ObjectMeta {
+	return p.Config.GetMetadata()
+}
+
+// SetMetadata sets the provider metadata
+func (p *Provider) SetMetadata(meta *corev2.ObjectMeta) {
+	p.Config.SetMetadata(meta)
+}
+
+// StoreName returns the store name of the provider configuration
+func (p *Provider) StoreName() string {
+	return p.Config.StoreName()
+}
+
+// RBACName returns the RBAC name of the provider configuration
+func (p *Provider) RBACName() string {
+	return p.Config.RBACName()
+}
+
+// URIPath returns the API path of the provider configuration
+func (p *Provider) URIPath() string {
+	return p.Config.URIPath()
+}
+
+// Validate validates the provider configuration
+func (p *Provider) Validate() error {
+	return p.Config.Validate()
+}
+
+// Name returns the provider name
+func (p *Provider) Name() string {
+	return p.Config.Metadata.Name
+}
+
+// Type returns the provider type
+func (p *Provider) Type() string {
+	return Type
+}
+
+// Authenticate is not supported by the OIDC provider, users must log in with
+// the authorization code flow.
+func (p *Provider) Authenticate(ctx context.Context, username, password string) (*corev2.Claims, error) {
+	return nil, ErrPasswordUnsupported
+}
+
+// AuthCodeURL returns the URL of the issuer's authorization endpoint where the
+// user must be sent to log in. The state and nonce must be random values
+// bound to the login attempt.
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, redirectURI string) (string, error) {
+	doc, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(doc.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid authorization endpoint: %s", err)
+	}
+	query := u.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.Config.ClientID)
+	query.Set("redirect_uri", p.redirectURI(redirectURI))
+	query.Set("scope", strings.Join(p.scopes(), " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// Exchange exchanges an authorization code for an ID token, and returns the
+// claims of the user it identifies. The nonce must be the one given to
+// AuthCodeURL.
+func (p *Provider) Exchange(ctx context.Context, code, nonce, redirectURI string) (*corev2.Claims, error) {
+	tokens, err := p.requestToken(ctx, url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {p.redirectURI(redirectURI)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if tokens.IDToken == "" {
+		return nil, errors.New("the token response does not contain an id_token")
+	}
+	idToken, err := p.verify(ctx, tokens.IDToken)
+	if err != nil {
+		return nil, err
+	}
+	if got, _ := idToken["nonce"].(string); nonce == "" || got != nonce {
+		return nil, errors.New("invalid ID token nonce")
+	}
+
+	identity, err := p.identity(idToken)
+	if err != nil {
+		return nil, err
+	}
+	sessionID, err := sensujwt.GenJTI()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.putSession(ctx, sessionID, newSession(p.Name(), identity, tokens.RefreshToken)); err != nil {
+		return nil, err
+	}
+
+	return p.claims(identity, sessionID)
+}
+
+// Refresh the claims of a user. The session of the user is refreshed with the
+// issuer, so users that were disabled, or removed from groups, by the issuer
+// are reflected in the new claims.
+func (p *Provider) Refresh(ctx context.Context, claims *corev2.Claims) (*corev2.Claims, error) {
+	s, err := p.getSession(ctx, claims.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if s == nil {
+		return nil, errSessionExpired
+	}
+	if s.RefreshToken == "" {
+		p.deleteSession(ctx, claims.SessionID)
+		return nil, errors.New("the issuer did not return a refresh token, please log in again")
+	}
+
+	tokens, err := p.requestToken(ctx, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {s.RefreshToken},
+	})
+	if err != nil {
+		p.deleteSession(ctx, claims.SessionID)
+		return nil, err
+	}
+
+	identity := s.identity()
+	if tokens.IDToken != "" {
+		idToken, err := p.verify(ctx, tokens.IDToken)
+		if err != nil {
+			return nil, err
+		}
+		if identity, err = p.identity(idToken); err != nil {
+			return nil, err
+		}
+		if identity.userID != s.UserID {
+			return nil, errors.New("the refreshed ID token identifies a different user")
+		}
+	}
+	refreshToken := s.RefreshToken
+	if tokens.RefreshToken != "" {
+		// The issuer rotated the refresh token
+		refreshToken = tokens.RefreshToken
+	}
+	if err := p.putSession(ctx, claims.SessionID, newSession(p.Name(), identity, refreshToken)); err != nil {
+		return nil, err
+	}
+
+	return p.claims(identity, claims.SessionID)
+}
+
+// identity is a user identified by an ID token.
+type identity struct {
+	userID   string
+	username string
+	groups   []string
+}
+
+// identity maps the claims of an ID token to the identity of a user.
+func (p *Provider) identity(idToken jwt.MapClaims) (identity, error) {
+	userID, _ := idToken["sub"].(string)
+	if userID == "" {
+		return identity{}, errors.New("the ID token has no subject")
+	}
+
+	claim := p.Config.GetUsernameClaim()
+	username, _ := idToken[claim].(string)
+	if username == "" {
+		return identity{}, fmt.Errorf("the ID token has no %q claim", claim)
+	}
+
+	var groups []string
+	switch value := idToken[p.Config.GetGroupsClaim()].(type) {
+	case string:
+		groups = []string{p.Config.GroupsPrefix + value}
+	case []interface{}:
+		for _, group := range value {
+			if name, ok := group.(string); ok && name != "" {
+				groups = append(groups, p.Config.GroupsPrefix+name)
+			}
+		}
+	}
+
+	return identity{
+		userID:   userID,
+		username: p.Config.UsernamePrefix + username,
+		groups:   groups,
+	}, nil
+}
+
+func (p *Provider) claims(identity identity, sessionID string) (*corev2.Claims, error) {
+	jti, err := sensujwt.GenJTI()
+	if err != nil {
+		return nil, err
+	}
+	return &corev2.Claims{
+		StandardClaims: jwt.StandardClaims{
+			Id:      jti,
+			Subject: identity.username,
+		},
+		Groups:    identity.groups,
+		SessionID: sessionID,
+		Provider: corev2.AuthProviderClaims{
+			ProviderID:   p.Name(),
+			ProviderType: Type,
+			UserID:       identity.userID,
+		},
+	}, nil
+}
+
+// verify verifies the signature and the standard claims of an ID token, and
+// returns its claims.
+func (p *Provider) verify(ctx context.Context, token string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	parser := jwt.NewParser(jwt.WithValidMethods(signingMethods))
+	_, err := parser.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return p.key(ctx, kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid ID token: %s", err)
+	}
+
+	issuer, _ := claims["iss"].(string)
+	if !sameIssuer(issuer, p.Config.Server) {
+		return nil, fmt.Errorf("invalid ID token issuer %q", issuer)
+	}
+	if !claims.VerifyAudience(p.Config.ClientID, true) {
+		return nil, errors.New("the ID token was not issued for this client")
+	}
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return nil, errors.New("the ID token has expired")
+	}
+	return claims, nil
+}
+
+// tokenResponse is the response of the issuer's token endpoint.
+type tokenResponse struct {
+	IDToken          string `json:"id_token"`
+	RefreshToken     string `json:"refresh_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func (p *Provider) requestToken(ctx context.Context, form url.Values) (*tokenResponse, error) {
+	doc, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, doc.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(p.Config.ClientID), url.QueryEscape(p.Config.ClientSecret))
+
+	resp, err := p.httpClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't request a token from the issuer: %s", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read the token response: %s", err)
+	}
+
+	var tokens tokenResponse
+	if err := json.Unmarshal(body, &tokens); err != nil {
+		return nil, fmt.Errorf("invalid token response (%s): %s", resp.Status, err)
+	}
+	if tokens.Error != "" {
+		return nil, fmt.Errorf("the issuer returned an error: %s %s", tokens.Error, tokens.ErrorDescription)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("the issuer returned an error: %s", resp.Status)
+	}
+	return &tokens, nil
+}
+
+func (p *Provider) scopes() []string {
+	scopes := []string{"openid"}
+	if !p.Config.DisableOfflineAccess {
+		scopes = append(scopes, "offline_access")
+	}
+	for _, scope := range p.Config.AdditionalScopes {
+		if scope != "openid" && scope != "offline_access" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// redirectURI returns the configured redirect URI, or the given default.
+func (p *Provider) redirectURI(fallback string) string {
+	if p.Config.RedirectURI != "" {
+		return p.Config.RedirectURI
+	}
+	return fallback
+}
+
+func (p *Provider) httpClient() *http.Client {
+	if p.HTTPClient != nil {
+		return p.HTTPClient
+	}
+	return &http.Client{Timeout: defaultTimeout}
+}
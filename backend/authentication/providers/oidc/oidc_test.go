@@ -0,0 +1,210 @@
+package oidc
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authv2 "github.com/sensu/sensu-go/api/authentication/v2"
+	"github.com/sensu/sensu-go/backend/store/memory"
+	"github.com/sensu/sensu-go/testing/mockoidc"
+)
+
+const testRedirectURI = "http://127.0.0.1:8080/auth/oidc/callback"
+
+func newTestProvider(t *testing.T) (*Provider, *mockoidc.Issuer) {
+	t.Helper()
+	issuer, err := mockoidc.NewIssuer("sensu", "secret")
+	require.NoError(t, err)
+	t.Cleanup(issuer.Close)
+
+	config := authv2.FixtureOIDC("mock")
+	config.Server = issuer.URL
+	config.ClientID = "sensu"
+	config.ClientSecret = "secret"
+	config.UsernameClaim = "email"
+	config.UsernamePrefix = "oidc:"
+	config.GroupsPrefix = "oidc:"
+	return New(config), issuer
+}
+
+// authorize follows the authorization code flow up to the redirection to the
+// callback, and returns the authorization code.
+func authorize(t *testing.T, p *Provider, state, nonce string) string {
+	t.Helper()
+	authURL, err := p.AuthCodeURL(context.Background(), state, nonce, testRedirectURI)
+	require.NoError(t, err)
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := client.Get(authURL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	require.Equal(t, state, location.Query().Get("state"))
+	return location.Query().Get("code")
+}
+
+func TestProviderExchange(t *testing.T) {
+	p, issuer := newTestProvider(t)
+	issuer.SetClaims(map[string]interface{}{
+		"sub":    "1234",
+		"email":  "jane@example.com",
+		"groups": []string{"ops", "dev"},
+	})
+
+	code := authorize(t, p, "state", "nonce")
+	claims, err := p.Exchange(context.Background(), code, "nonce", testRedirectURI)
+	require.NoError(t, err)
+
+	assert.Equal(t, "oidc:jane@example.com", claims.Subject)
+	assert.Equal(t, []string{"oidc:ops", "oidc:dev"}, claims.Groups)
+	assert.Equal(t, "mock", claims.Provider.ProviderID)
+	assert.Equal(t, Type, claims.Provider.ProviderType)
+	assert.Equal(t, "1234", claims.Provider.UserID)
+	assert.NotEmpty(t, claims.SessionID)
+	assert.NotEmpty(t, claims.Id)
+}
+
+func TestProviderExchangeBadNonce(t *testing.T) {
+	p, issuer := newTestProvider(t)
+	issuer.SetClaims(map[string]interface{}{"email": "jane@example.com"})
+
+	code := authorize(t, p, "state", "nonce")
+	_, err := p.Exchange(context.Background(), code, "other", testRedirectURI)
+	assert.Error(t, err)
+}
+
+func TestProviderExchangeMissingUsernameClaim(t *testing.T) {
+	p, _ := newTestProvider(t)
+
+	code := authorize(t, p, "state", "nonce")
+	_, err := p.Exchange(context.Background(), code, "nonce", testRedirectURI)
+	assert.Error(t, err)
+}
+
+func TestProviderExchangeBadClientSecret(t *testing.T) {
+	p, issuer := newTestProvider(t)
+	issuer.SetClaims(map[string]interface{}{"email": "jane@example.com"})
+	p.Config.ClientSecret = "wrong"
+
+	code := authorize(t, p, "state", "nonce")
+	_, err := p.Exchange(context.Background(), code, "nonce", testRedirectURI)
+	assert.Error(t, err)
+}
+
+func TestProviderExchangeWrongIssuer(t *testing.T) {
+	p, issuer := newTestProvider(t)
+	issuer.SetClaims(map[string]interface{}{"email": "jane@example.com"})
+
+	// A token issued by another issuer must be rejected, even when it is
+	// signed with a trusted key.
+	_, err := p.AuthCodeURL(context.Background(), "state", "nonce", testRedirectURI)
+	require.NoError(t, err)
+	p.Config.Server = issuer.URL + "/other"
+	code := authorize(t, p, "state", "nonce")
+	_, err = p.Exchange(context.Background(), code, "nonce", testRedirectURI)
+	assert.Error(t, err)
+}
+
+func TestProviderRefresh(t *testing.T) {
+	p, issuer := newTestProvider(t)
+	issuer.SetClaims(map[string]interface{}{
+		"email":  "jane@example.com",
+		"groups": []string{"ops"},
+	})
+
+	code := authorize(t, p, "state", "nonce")
+	claims, err := p.Exchange(context.Background(), code, "nonce", testRedirectURI)
+	require.NoError(t, err)
+
+	// Group membership changes are reflected on refresh
+	issuer.SetClaims(map[string]interface{}{
+		"email":  "jane@example.com",
+		"groups": "admins",
+	})
+	refreshed, err := p.Refresh(context.Background(), claims)
+	require.NoError(t, err)
+	assert.Equal(t, "oidc:jane@example.com", refreshed.Subject)
+	assert.Equal(t, []string{"oidc:admins"}, refreshed.Groups)
+	assert.Equal(t, claims.SessionID, refreshed.SessionID)
+
+	// The rotated refresh token is used for the next refresh
+	_, err = p.Refresh(context.Background(), refreshed)
+	require.NoError(t, err)
+
+	// Sessions revoked by the issuer can't be refreshed
+	issuer.RevokeRefreshTokens()
+	_, err = p.Refresh(context.Background(), refreshed)
+	assert.Error(t, err)
+	_, err = p.Refresh(context.Background(), refreshed)
+	assert.Error(t, err)
+}
+
+func TestProviderRefreshWithoutOfflineAccess(t *testing.T) {
+	p, issuer := newTestProvider(t)
+	issuer.SetClaims(map[string]interface{}{"email": "jane@example.com"})
+	p.Config.DisableOfflineAccess = true
+
+	code := authorize(t, p, "state", "nonce")
+	claims, err := p.Exchange(context.Background(), code, "nonce", testRedirectURI)
+	require.NoError(t, err)
+
+	_, err = p.Refresh(context.Background(), claims)
+	assert.Error(t, err)
+}
+
+func TestProviderRefreshAfterUpdate(t *testing.T) {
+	p, issuer := newTestProvider(t)
+	issuer.SetClaims(map[string]interface{}{"email": "jane@example.com"})
+	p.Store = memory.NewOIDCStateStore()
+
+	code := authorize(t, p, "state", "nonce")
+	claims, err := p.Exchange(context.Background(), code, "nonce", testRedirectURI)
+	require.NoError(t, err)
+
+	// The sessions are kept by the store when the provider is recreated
+	config := *p.Config
+	updated := New(&config)
+	updated.Store = p.Store
+	_, err = updated.Refresh(context.Background(), claims)
+	require.NoError(t, err)
+
+	// but they can't be refreshed with another provider
+	config.Metadata.Name = "other"
+	other := New(&config)
+	other.Store = p.Store
+	_, err = other.Refresh(context.Background(), claims)
+	assert.Error(t, err)
+}
+
+func TestProviderRefreshUnknownSession(t *testing.T) {
+	p, _ := newTestProvider(t)
+	claims, err := p.claims(identity{userID: "1234", username: "jane"}, "unknown")
+	require.NoError(t, err)
+	_, err = p.Refresh(context.Background(), claims)
+	assert.Error(t, err)
+}
+
+func TestProviderAuthenticate(t *testing.T) {
+	p, _ := newTestProvider(t)
+	_, err := p.Authenticate(context.Background(), "jane", "P@ssw0rd!")
+	assert.Equal(t, ErrPasswordUnsupported, err)
+}
+
+func TestProviderScopes(t *testing.T) {
+	p, _ := newTestProvider(t)
+	p.Config.AdditionalScopes = []string{"email", "openid", "groups"}
+	assert.Equal(t, []string{"openid", "offline_access", "email", "groups"}, p.scopes())
+
+	p.Config.DisableOfflineAccess = true
+	assert.Equal(t, []string{"openid", "email", "groups"}, p.scopes())
+}
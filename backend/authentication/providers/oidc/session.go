@@ -0,0 +1,82 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+// sessionIdleTimeout is the time after which a session that hasn't been
+// refreshed is forgotten.
+var sessionIdleTimeout = 24 * time.Hour
+
+// session holds the identity of a user logged in with the provider, and the
+// refresh token issued to them, if any. Refresh tokens issued by the issuer
+// are never sent to clients: sessions are kept in the OIDC state store of the
+// provider, so that any backend of the cluster can refresh them, and that
+// they survive the updates of the provider.
+type session struct {
+	Provider     string   `json:"provider"`
+	UserID       string   `json:"user_id"`
+	Username     string   `json:"username"`
+	Groups       []string `json:"groups,omitempty"`
+	RefreshToken string   `json:"refresh_token,omitempty"`
+}
+
+func newSession(provider string, identity identity, refreshToken string) *session {
+	return &session{
+		Provider:     provider,
+		UserID:       identity.userID,
+		Username:     identity.username,
+		Groups:       identity.groups,
+		RefreshToken: refreshToken,
+	}
+}
+
+func (s *session) identity() identity {
+	return identity{userID: s.UserID, username: s.Username, groups: s.Groups}
+}
+
+// getSession returns the session id of the provider, or nil if it doesn't
+// exist or has expired.
+func (p *Provider) getSession(ctx context.Context, id string) (*session, error) {
+	value, err := p.states().GetOIDCState(ctx, store.OIDCSession, id)
+	if err != nil {
+		if _, ok := err.(*store.ErrNotFound); ok {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var s session
+	if err := json.Unmarshal(value, &s); err != nil {
+		return nil, fmt.Errorf("invalid session: %s", err)
+	}
+	if s.Provider != p.Name() {
+		// The session was opened with another provider
+		return nil, nil
+	}
+	return &s, nil
+}
+
+// putSession stores the session id, which expires after the idle timeout.
+func (p *Provider) putSession(ctx context.Context, id string, s *session) error {
+	value, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return p.states().PutOIDCState(ctx, store.OIDCSession, id, value, time.Now().Add(sessionIdleTimeout))
+}
+
+// deleteSession deletes the session id. Failures are logged, as the session
+// can't be refreshed anyway.
+func (p *Provider) deleteSession(ctx context.Context, id string) {
+	if err := p.states().DeleteOIDCState(ctx, store.OIDCSession, id); err != nil {
+		logger.WithError(err).Error("couldn't delete oidc session")
+	}
+}
+
+var errSessionExpired = errors.New("the session has expired, please log in again")
@@ -132,6 +132,13 @@ func (a *Authorizer) Authorize(ctx context.Context, attrs *authorization.Attribu
 		})
 	}
 
+	// The request must also be authorized by the scope of the client, if it
+	// is restricted, e.g. by the scope of its API key
+	if scope := authorization.ScopeFromContext(ctx); scope != nil && attrs != nil && !scope.Allows(attrs) {
+		logger.Debug("unauthorized request, outside of the scope of the client")
+		return false, nil
+	}
+
 	var (
 		authorized bool
 		visitErr   error
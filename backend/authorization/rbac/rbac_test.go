@@ -313,6 +313,102 @@ func TestAuthorize(t *testing.T) {
 	}
 }
 
+func TestAuthorizeWithScope(t *testing.T) {
+	ctx := store.NamespaceContext(context.Background(), "acme")
+	attrs := &authorization.Attributes{
+		Namespace:    "acme",
+		Verb:         "create",
+		Resource:     "checks",
+		ResourceName: "check-cpu",
+		User: corev2.User{
+			Username: "foo",
+		},
+	}
+
+	tests := []struct {
+		name  string
+		scope *authorization.Scope
+		want  bool
+	}{
+		{
+			name: "no scope",
+			want: true,
+		},
+		{
+			name:  "empty scope",
+			scope: &authorization.Scope{},
+			want:  true,
+		},
+		{
+			name:  "scope of the namespace",
+			scope: &authorization.Scope{Namespaces: []string{"ci", "acme"}},
+			want:  true,
+		},
+		{
+			name:  "scope of another namespace",
+			scope: &authorization.Scope{Namespaces: []string{"ci"}},
+			want:  false,
+		},
+		{
+			name: "scope rule allowing the request",
+			scope: &authorization.Scope{Rules: []corev2.Rule{
+				{Verbs: []string{"get", "list"}, Resources: []string{"*"}},
+				{Verbs: []string{"create"}, Resources: []string{"checks"}},
+			}},
+			want: true,
+		},
+		{
+			name: "scope rules not allowing the request",
+			scope: &authorization.Scope{
+				Namespaces: []string{"acme"},
+				Rules: []corev2.Rule{
+					{Verbs: []string{"get", "list"}, Resources: []string{"checks"}},
+				},
+			},
+			want: false,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &mockstore.V2MockStore{}
+			configStore := &mockstore.ConfigStore{}
+			s.On("GetConfigStore").Return(configStore)
+			configStore.On("List", mock.Anything, mock.Anything, mock.Anything).
+				Return(mockstore.WrapList[*corev2.ClusterRoleBinding]{{
+					RoleRef: corev2.RoleRef{
+						Type: "ClusterRole",
+						Name: "cluster-admin",
+					},
+					Subjects: []corev2.Subject{
+						{Type: corev2.UserType, Name: "foo"},
+					},
+				}}, nil)
+			configStore.On("Get", mock.Anything, mock.Anything).
+				Return(mockstore.Wrapper[*corev2.ClusterRole]{Value: &corev2.ClusterRole{Rules: []corev2.Rule{
+					{
+						Verbs:     []string{"*"},
+						Resources: []string{"*"},
+					},
+				}}}, nil)
+			a := &Authorizer{
+				Store: s,
+			}
+
+			ctx := ctx
+			if tc.scope != nil {
+				ctx = authorization.ContextWithScope(ctx, tc.scope)
+			}
+			got, err := a.Authorize(ctx, attrs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Errorf("Authorizer.Authorize() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
 func TestMatchesUser(t *testing.T) {
 	tests := []struct {
 		name     string
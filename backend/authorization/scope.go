@@ -0,0 +1,68 @@
+package authorization
+
+import (
+	"context"
+
+	v2 "github.com/sensu/core/v2"
+)
+
+type scopeKey struct{}
+
+// Scope restricts the requests of a client to a subset of the requests that
+// its user is authorized to make, e.g. for an API key. The requests must be
+// authorized by both the scope and the RBAC rules of the user.
+type Scope struct {
+	// Namespaces are the namespaces of the authorized requests. The requests
+	// of all namespaces, and the cluster-wide requests, are authorized when
+	// empty.
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Rules are the rules that authorize the requests. All requests are
+	// authorized when empty.
+	Rules []v2.Rule `json:"rules,omitempty"`
+}
+
+// Allows returns whether the scope allows a request with the given
+// attributes.
+func (s *Scope) Allows(attrs *Attributes) bool {
+	if len(s.Namespaces) > 0 && !s.allowsNamespace(attrs) {
+		return false
+	}
+	if len(s.Rules) == 0 {
+		return true
+	}
+	for _, rule := range s.Rules {
+		if rule.VerbMatches(attrs.Verb) && rule.ResourceMatches(attrs.Resource) && rule.ResourceNameMatches(attrs.ResourceName) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsNamespace returns whether the request is made in one of the
+// namespaces of the scope, or is a request of one of these namespaces.
+func (s *Scope) allowsNamespace(attrs *Attributes) bool {
+	namespace := attrs.Namespace
+	if attrs.Resource == (&v2.Namespace{}).RBACName() {
+		namespace = attrs.ResourceName
+	}
+	for _, n := range s.Namespaces {
+		if n == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// ContextWithScope returns a context that restricts the authorized requests
+// to the given scope.
+func ContextWithScope(ctx context.Context, scope *Scope) context.Context {
+	return context.WithValue(ctx, scopeKey{}, scope)
+}
+
+// ScopeFromContext returns the scope of the authorized requests, or nil if
+// they are not restricted.
+func ScopeFromContext(ctx context.Context) *Scope {
+	scope, _ := ctx.Value(scopeKey{}).(*Scope)
+	return scope
+}
@@ -0,0 +1,58 @@
+package backend
+
+import (
+	"context"
+
+	corev3 "github.com/sensu/core/v3"
+	authv2 "github.com/sensu/sensu-go/api/authentication/v2"
+	"github.com/sensu/sensu-go/backend/authentication"
+	"github.com/sensu/sensu-go/backend/authentication/providers/ldap"
+	"github.com/sensu/sensu-go/backend/authentication/providers/oidc"
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+// AuthProvidersLoop adds the LDAP and OIDC providers configured in the store
+// to the authenticator, and keeps them in sync with the store until ctx is
+// cancelled. The sessions of the OIDC providers are kept in oidcStates, so
+// they survive the updates of the providers.
+func AuthProvidersLoop(ctx context.Context, s storev2.Interface, auth *authentication.Authenticator, oidcStates store.OIDCStateStore) {
+	go watchProviders(ctx, s, authProviders(auth), newLDAPProvider)
+	watchProviders(ctx, s, authProviders(auth), newOIDCProvider(oidcStates))
+}
+
+func newLDAPProvider(config *authv2.LDAP) corev3.AuthProvider {
+	return ldap.New(config)
+}
+
+func newOIDCProvider(states store.OIDCStateStore) func(*authv2.OIDC) corev3.AuthProvider {
+	return func(config *authv2.OIDC) corev3.AuthProvider {
+		provider := oidc.New(config)
+		provider.Store = states
+		return provider
+	}
+}
+
+// authProviders is the kind of the providers of the authenticator.
+func authProviders(auth *authentication.Authenticator) providerKind[corev3.AuthProvider] {
+	return providerKind[corev3.AuthProvider]{
+		name:   "authentication",
+		set:    auth,
+		typeOf: authProviderType,
+	}
+}
+
+// authProviderType returns the type of an authentication provider, or of its
+// configuration.
+func authProviderType(v interface{}) string {
+	switch v := v.(type) {
+	case corev3.AuthProvider:
+		return v.Type()
+	case *authv2.LDAP:
+		return ldap.Type
+	case *authv2.OIDC:
+		return oidc.Type
+	default:
+		return ""
+	}
+}
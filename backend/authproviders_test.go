@@ -0,0 +1,84 @@
+package backend
+
+import (
+	"testing"
+
+	corev2 "github.com/sensu/core/v2"
+	authv2 "github.com/sensu/sensu-go/api/authentication/v2"
+	"github.com/sensu/sensu-go/backend/authentication"
+	"github.com/sensu/sensu-go/backend/authentication/providers/basic"
+	"github.com/sensu/sensu-go/backend/authentication/providers/ldap"
+	"github.com/sensu/sensu-go/backend/authentication/providers/oidc"
+	"github.com/sensu/sensu-go/backend/store/memory"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+func TestHandleAuthProviderEvent(t *testing.T) {
+	auth := &authentication.Authenticator{}
+	auth.AddProvider(&basic.Provider{ObjectMeta: corev2.ObjectMeta{Name: basic.Type}})
+	states := memory.NewOIDCStateStore()
+
+	config := authv2.FixtureOIDC("okta")
+	handleProviderEvent(authProviders(auth), storev2.GenericEvent[*authv2.OIDC]{Type: storev2.WatchCreate, Value: config}, newOIDCProvider(states))
+	provider, ok := auth.Providers()["okta"].(*oidc.Provider)
+	if !ok {
+		t.Fatal("the oidc provider was not added")
+	}
+	if provider.Config != config {
+		t.Error("the oidc provider has the wrong configuration")
+	}
+
+	updated := authv2.FixtureOIDC("okta")
+	updated.ClientID = "other"
+	handleProviderEvent(authProviders(auth), storev2.GenericEvent[*authv2.OIDC]{Type: storev2.WatchUpdate, Value: updated}, newOIDCProvider(states))
+	provider = auth.Providers()["okta"].(*oidc.Provider)
+	if got := provider.Config.ClientID; got != "other" {
+		t.Errorf("the oidc provider was not updated: client_id = %q", got)
+	}
+	if provider.Store != states {
+		t.Error("the updated oidc provider does not keep the sessions")
+	}
+
+	handleProviderEvent(authProviders(auth), storev2.GenericEvent[*authv2.OIDC]{
+		Type: storev2.WatchDelete,
+		Key:  corev2.ObjectMeta{Name: "okta"},
+	}, newOIDCProvider(states))
+	if _, ok := auth.Providers()["okta"]; ok {
+		t.Error("the oidc provider was not removed")
+	}
+	if _, ok := auth.Providers()[basic.Type]; !ok {
+		t.Error("the basic provider was removed")
+	}
+}
+
+func TestHandleAuthProviderEventTypes(t *testing.T) {
+	auth := &authentication.Authenticator{}
+	auth.AddProvider(&basic.Provider{ObjectMeta: corev2.ObjectMeta{Name: basic.Type}})
+	states := memory.NewOIDCStateStore()
+
+	handleProviderEvent(authProviders(auth), storev2.GenericEvent[*authv2.LDAP]{
+		Type:  storev2.WatchCreate,
+		Value: authv2.FixtureLDAP("corp"),
+	}, newLDAPProvider)
+	if _, ok := auth.Providers()["corp"].(*ldap.Provider); !ok {
+		t.Fatal("the ldap provider was not added")
+	}
+
+	// An OIDC provider with the same name replaces the LDAP provider
+	handleProviderEvent(authProviders(auth), storev2.GenericEvent[*authv2.OIDC]{
+		Type:  storev2.WatchCreate,
+		Value: authv2.FixtureOIDC("corp"),
+	}, newOIDCProvider(states))
+	if _, ok := auth.Providers()["corp"].(*oidc.Provider); !ok {
+		t.Fatal("the oidc provider was not added")
+	}
+
+	// Deleting the LDAP provider doesn't remove the OIDC provider
+	handleProviderEvent(authProviders(auth), storev2.GenericEvent[*authv2.LDAP]{
+		Type: storev2.WatchDelete,
+		Key:  corev2.ObjectMeta{Name: "corp"},
+	}, newLDAPProvider)
+	if _, ok := auth.Providers()["corp"].(*oidc.Provider); !ok {
+		t.Error("the oidc provider was removed")
+	}
+}
@@ -23,15 +23,18 @@ import (
 	"github.com/sensu/sensu-go/backend/apid/actions"
 	"github.com/sensu/sensu-go/backend/apid/graphql"
 	"github.com/sensu/sensu-go/backend/apid/routers"
+	"github.com/sensu/sensu-go/backend/audit"
 	"github.com/sensu/sensu-go/backend/authentication"
 	"github.com/sensu/sensu-go/backend/authentication/jwt"
 	"github.com/sensu/sensu-go/backend/authentication/providers/basic"
 	"github.com/sensu/sensu-go/backend/authorization/rbac"
 	"github.com/sensu/sensu-go/backend/daemon"
+	"github.com/sensu/sensu-go/backend/escalationd"
 	"github.com/sensu/sensu-go/backend/eventd"
 	"github.com/sensu/sensu-go/backend/keepalived"
 	"github.com/sensu/sensu-go/backend/licensing"
 	"github.com/sensu/sensu-go/backend/logging"
+	"github.com/sensu/sensu-go/backend/maintenanced"
 	"github.com/sensu/sensu-go/backend/messaging"
 	"github.com/sensu/sensu-go/backend/pipeline"
 	"github.com/sensu/sensu-go/backend/pipeline/filter"
@@ -41,6 +44,7 @@ import (
 	"github.com/sensu/sensu-go/backend/ringv2"
 	"github.com/sensu/sensu-go/backend/schedulerd"
 	"github.com/sensu/sensu-go/backend/secrets"
+	"github.com/sensu/sensu-go/backend/store"
 	"github.com/sensu/sensu-go/backend/store/postgres"
 	storev2 "github.com/sensu/sensu-go/backend/store/v2"
 	"github.com/sensu/sensu-go/backend/tessend"
@@ -163,10 +167,36 @@ func Initialize(ctx context.Context, pgdb postgres.DBI, config *Config) (*Backen
 	// Initialize a Backend struct
 	b := &Backend{Cfg: config}
 
+	// Initialize the postgres notification bus
+	pgDSN := b.Cfg.Store.PostgresStore.DSN
+	listener := pq.NewListener(pgDSN, time.Second, time.Minute, errorReporter)
+	pgBus := postgres.NewBus(ctx, listener)
+
 	// Initialize the bus
-	bus, err := messaging.NewWizardBus(messaging.WizardBusConfig{})
+	wizardBus, err := messaging.NewWizardBus(messaging.WizardBusConfig{})
 	if err != nil {
-		return nil, fmt.Errorf("error initializing %s: %s", bus.Name(), err)
+		return nil, fmt.Errorf("error initializing %s: %s", wizardBus.Name(), err)
+	}
+	var bus messaging.MessageBus = wizardBus
+	// localBus is only set when the check requests are bridged
+	var localBus messaging.MessageBus
+	if len(config.ClusterBusTopics) > 0 {
+		// Bridge the selected topics across the backends
+		topics, err := newClusterTopics(config.ClusterBusTopics)
+		if err != nil {
+			return nil, err
+		}
+		bus, err = messaging.NewClusterBus(wizardBus, messaging.ClusterBusConfig{
+			Transport:      postgres.NewClusterBusTransport(pgdb, pgBus),
+			Topics:         topics,
+			LocalConsumers: clusterBusLocalConsumers,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error initializing cluster bus: %s", err)
+		}
+		if bridgesCheckRequests(config.ClusterBusTopics) {
+			localBus = wizardBus
+		}
 	}
 	b.Bus = bus
 	b.Daemons = append(b.Daemons, bus)
@@ -206,6 +236,10 @@ func Initialize(ctx context.Context, pgdb postgres.DBI, config *Config) (*Backen
 	if limit == 0 {
 		limit = asset.DefaultAssetsRateLimit
 	}
+	assetManager.SignatureVerifier, err = asset.NewSignatureVerifier(config.AssetTrustedKeys, config.AssetSignaturePolicy)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing asset manager: %s", err)
+	}
 	assetGetter, err := assetManager.StartAssetManager(ctx, rate.NewLimiter(limit, b.Cfg.AssetsBurstLimit))
 	if err != nil {
 		return nil, fmt.Errorf("error initializing asset manager: %s", err)
@@ -217,8 +251,16 @@ func Initialize(ctx context.Context, pgdb postgres.DBI, config *Config) (*Backen
 		return nil, fmt.Errorf("error creating system namespace and backend entity: %s", err.Error())
 	}
 
-	// Initialize the secrets provider manager
+	// Initialize the secrets provider manager. Check secrets are only sent to
+	// agents authenticated with mutual TLS.
 	b.SecretsProviderManager = secrets.NewProviderManager(br)
+	b.SecretsProviderManager.Getter = &secrets.StoreGetter{Store: b.Store}
+	agentTLSOptions := config.AgentTLSOptions
+	if agentTLSOptions == nil {
+		agentTLSOptions = config.TLS
+	}
+	b.SecretsProviderManager.TLSenabled = agentTLSOptions.GetClientAuthType()
+	go SecretsProvidersLoop(ctx, b.Store, b.SecretsProviderManager)
 
 	auth := &rbac.Authorizer{Store: b.Store}
 
@@ -248,12 +290,20 @@ func Initialize(ctx context.Context, pgdb postgres.DBI, config *Config) (*Backen
 	hasMetricsFilterAdapter := &filter.HasMetricsAdapter{}
 	isIncidentFilterAdapter := &filter.IsIncidentAdapter{}
 	notSilencedFilterAdapter := &filter.NotSilencedAdapter{}
+	throttleStore := postgres.NewThrottleStore(pgdb)
+	throttleFilterAdapter := &filter.ThrottleAdapter{
+		Store:        b.Store,
+		Throttles:    throttleStore,
+		StoreTimeout: storeTimeout,
+	}
+	go PruneThrottleStatesLoop(ctx, throttleStore)
 
 	b.PipelineAdapterV1.FilterAdapters = []pipeline.FilterAdapter{
 		legacyFilterAdapter,
 		hasMetricsFilterAdapter,
 		isIncidentFilterAdapter,
 		notSilencedFilterAdapter,
+		throttleFilterAdapter,
 	}
 
 	// Initialize PipelineAdapterV1 mutator adapters
@@ -282,18 +332,73 @@ func Initialize(ctx context.Context, pgdb postgres.DBI, config *Config) (*Backen
 		Store:                  b.Store,
 		StoreTimeout:           storeTimeout,
 	}
+	httpHandlerAdapter := &handler.HTTPAdapter{
+		SecretsProviderManager: b.SecretsProviderManager,
+		Store:                  b.Store,
+		StoreTimeout:           storeTimeout,
+	}
+
+	escalationStore := postgres.NewEscalationStore(pgdb)
+	escalationHandlerAdapter := &handler.EscalationAdapter{
+		Store:        b.Store,
+		Incidents:    escalationStore,
+		StoreTimeout: storeTimeout,
+	}
 
 	b.PipelineAdapterV1.HandlerAdapters = []pipeline.HandlerAdapter{
 		legacyHandlerAdapter,
+		httpHandlerAdapter,
+		escalationHandlerAdapter,
 	}
 
 	pipelineDaemon.AddAdapter(&b.PipelineAdapterV1)
 	b.Daemons = append(b.Daemons, pipelineDaemon)
 
+	// Initialize escalationd, which notifies the handlers of the escalation
+	// policy steps
+	escalation, err := escalationd.New(escalationd.Config{
+		Store:     b.Store,
+		Incidents: escalationStore,
+		HandlerAdapters: []pipeline.HandlerAdapter{
+			legacyHandlerAdapter,
+			httpHandlerAdapter,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error initializing %s: %s", escalation.Name(), err)
+	}
+	b.Daemons = append(b.Daemons, escalation)
+
+	// Initialize maintenanced, which silences the maintenance windows
+	maintenance, err := maintenanced.New(maintenanced.Config{
+		Store: b.Store,
+		Executor: &postgres.SynchronizedExecutor{
+			DB:              pgdb,
+			CheckinInterval: 10 * time.Second,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error initializing %s: %s", maintenance.Name(), err)
+	}
+	b.Daemons = append(b.Daemons, maintenance)
+
 	pgOPC := postgres.NewOPC(pgdb)
 
 	go CheckInLoop(ctx, b.Cfg.Name, pgOPC)
 
+	// Prune the event history, if it is recorded and has a limited retention
+	if retention := config.Store.PostgresStore.EventHistoryRetention; retention > 0 {
+		if hs, ok := b.Store.GetEventStore().(store.EventHistoryStore); ok {
+			go PruneEventHistoryLoop(ctx, hs, retention)
+		}
+	}
+
+	// Prune the configuration history, if it is bounded
+	pgConfig := config.Store.PostgresStore
+	if pgConfig.ConfigHistoryMaxRevisions > 0 || pgConfig.ConfigHistoryRetention > 0 {
+		go PruneConfigHistoryLoop(ctx, b.Store.GetConfigHistoryStore(), pgConfig.ConfigHistoryMaxRevisions, pgConfig.ConfigHistoryRetention)
+	}
+
 	// Initialize eventd
 	event, err := eventd.New(
 		ctx,
@@ -330,6 +435,9 @@ func Initialize(ctx context.Context, pgdb postgres.DBI, config *Config) (*Backen
 			Bus:                    bus,
 			SecretsProviderManager: b.SecretsProviderManager,
 			Queue:                  workQueue,
+			BackendName:            b.Cfg.Name,
+			OperatorQueryer:        pgOPC,
+			LocalBus:               localBus,
 		})
 	if err != nil {
 		return nil, fmt.Errorf("error initializing %s: %s", scheduler.Name(), err)
@@ -370,6 +478,11 @@ func Initialize(ctx context.Context, pgdb postgres.DBI, config *Config) (*Backen
 	}
 	authenticator.AddProvider(provider)
 
+	// The OIDC sessions and logins are shared by the backends
+	oidcStateStore := postgres.NewOIDCStateStore(pgdb)
+	go AuthProvidersLoop(ctx, b.Store, authenticator, oidcStateStore)
+	go PruneOIDCStatesLoop(ctx, oidcStateStore)
+
 	var clusterVersion string
 
 	// Load the JWT key pair
@@ -380,24 +493,45 @@ func Initialize(ctx context.Context, pgdb postgres.DBI, config *Config) (*Backen
 	// Initialize the health router
 	b.HealthRouter = routers.NewHealthRouter(actions.HealthController{})
 
+	// The changes made through the API are audited, if the audit log is
+	// enabled
+	auditStore := postgres.NewAuditStore(pgdb)
+	apiStore := b.Store
+	auditor, err := newAuditor(ctx, bus, auditStore, config)
+	if err != nil {
+		return nil, err
+	}
+	if auditor != nil {
+		apiStore = audit.NewStore(b.Store, auditor)
+	}
+	if config.AuditLogDatabase && config.AuditLogRetention > 0 {
+		go PruneAuditLogLoop(ctx, auditStore, config.AuditLogRetention)
+	}
+
+	// The expired API keys are deleted, and the last uses of the API keys
+	// are recorded apart from the keys
+	apiKeyUsageStore := postgres.NewAPIKeyUsageStore(pgdb)
+	go PruneAPIKeysLoop(ctx, b.Store, apiKeyUsageStore)
+
 	// Initialize GraphQL service
 	b.GraphQLService, err = graphql.NewService(graphql.ServiceConfig{
-		AssetClient:       api.NewAssetClient(b.Store, auth),
-		CheckClient:       api.NewCheckClient(b.Store, actions.NewCheckController(b.Store, workQueue), auth),
-		EntityClient:      api.NewEntityClient(b.Store, auth),
-		EventClient:       api.NewEventClient(b.Store.GetEventStore(), auth, bus),
-		EventFilterClient: api.NewEventFilterClient(b.Store, auth),
-		HandlerClient:     api.NewHandlerClient(b.Store, auth),
-		HealthController:  actions.HealthController{},
-		MutatorClient:     api.NewMutatorClient(b.Store, auth),
-		SilencedClient:    api.NewSilencedClient(b.Store.GetSilencesStore(), auth),
-		NamespaceClient:   api.NewNamespaceClient(b.Store, auth),
-		HookClient:        api.NewHookConfigClient(b.Store, auth),
-		UserClient:        api.NewUserClient(b.Store, auth),
-		RBACClient:        api.NewRBACClient(b.Store, auth),
-		VersionController: actions.NewVersionController(clusterVersion),
-		MetricGatherer:    prometheus.DefaultGatherer,
-		GenericClient:     &api.GenericClient{Store: b.Store, Auth: auth},
+		AssetClient:        api.NewAssetClient(apiStore, auth),
+		CheckClient:        api.NewCheckClient(apiStore, actions.NewCheckController(apiStore, workQueue), auth),
+		EntityClient:       api.NewEntityClient(apiStore, auth),
+		EventClient:        api.NewEventClient(apiStore.GetEventStore(), auth, bus),
+		EventFilterClient:  api.NewEventFilterClient(apiStore, auth),
+		HandlerClient:      api.NewHandlerClient(apiStore, auth),
+		HealthController:   actions.HealthController{},
+		MutatorClient:      api.NewMutatorClient(apiStore, auth),
+		SilencedClient:     api.NewSilencedClient(apiStore.GetSilencesStore(), auth),
+		SubscriptionClient: api.NewSubscriptionClient(apiStore, bus, auth),
+		NamespaceClient:    api.NewNamespaceClient(apiStore, auth),
+		HookClient:         api.NewHookConfigClient(apiStore, auth),
+		UserClient:         api.NewUserClient(apiStore, auth),
+		RBACClient:         api.NewRBACClient(apiStore, auth),
+		VersionController:  actions.NewVersionController(clusterVersion),
+		MetricGatherer:     prometheus.DefaultGatherer,
+		GenericClient:      &api.GenericClient{Store: apiStore, Auth: auth},
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error initializing graphql.Service: %s", err)
@@ -405,17 +539,24 @@ func Initialize(ctx context.Context, pgdb postgres.DBI, config *Config) (*Backen
 
 	// Initialize apid
 	b.APIDConfig = apid.Config{
-		ListenAddress:  config.APIListenAddress,
-		RequestLimit:   config.APIRequestLimit,
-		WriteTimeout:   config.APIWriteTimeout,
-		URL:            config.APIURL,
-		Bus:            bus,
-		Store:          b.Store,
-		TLS:            config.TLS,
-		Authenticator:  authenticator,
-		ClusterVersion: clusterVersion,
-		GraphQLService: b.GraphQLService,
-		Queue:          workQueue,
+		ListenAddress:    config.APIListenAddress,
+		RequestLimit:     config.APIRequestLimit,
+		WriteTimeout:     config.APIWriteTimeout,
+		URL:              config.APIURL,
+		Bus:              bus,
+		Store:            apiStore,
+		TLS:              config.TLS,
+		Authenticator:    authenticator,
+		ClusterVersion:   clusterVersion,
+		GraphQLService:   b.GraphQLService,
+		Queue:            workQueue,
+		AuditStore:       auditStore,
+		APIKeyUsageStore: apiKeyUsageStore,
+		OIDCStateStore:   oidcStateStore,
+		RateLimiter:      newRateLimiter(ctx, postgres.NewRateLimitStore(pgdb), config),
+		RateLimits:       config.APIRateLimits,
+		IPRateLimits:     config.APIIPRateLimits,
+		AllowedOrigins:   config.APIAllowedOrigins,
 	}
 	newApi, err := apid.New(b.APIDConfig)
 	if err != nil {
@@ -424,10 +565,6 @@ func Initialize(ctx context.Context, pgdb postgres.DBI, config *Config) (*Backen
 	b.Daemons = append(b.Daemons, newApi)
 
 	// Initialize tessend
-	pgDSN := b.Cfg.Store.PostgresStore.DSN
-	listener := pq.NewListener(pgDSN, time.Second, time.Minute, errorReporter)
-	pgBus := postgres.NewBus(ctx, listener)
-
 	ringPool := ringv2.NewRingPool(func(path string) ringv2.Interface {
 		ring, err := postgres.NewRing(pgdb, pgBus, path)
 		if err != nil {
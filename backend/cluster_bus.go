@@ -0,0 +1,114 @@
+package backend
+
+import (
+	"encoding/json"
+	"fmt"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/messaging"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	"github.com/sensu/sensu-go/backend/store/v2/wrap"
+)
+
+// clusterBusTopics are the topics which can be bridged across the backends by
+// the cluster bus, by name.
+var clusterBusTopics = map[string]messaging.ClusterTopic{
+	"event": {
+		Topic:  messaging.TopicEvent,
+		Decode: messaging.JSONDecoder[*corev2.Event](),
+	},
+	"entity-config": {
+		Topic:  messaging.TopicEntityConfig,
+		Encode: encodeWatchEvent,
+		Decode: decodeWatchEvent,
+	},
+	"check": {
+		Topic:  messaging.TopicSubscriptions,
+		Decode: messaging.JSONDecoder[*corev2.CheckRequest](),
+	},
+	"agent-conn": {
+		Topic:  messaging.TopicAgentConnectionState,
+		Decode: messaging.JSONDecoder[messaging.AgentNotification](),
+	},
+}
+
+// clusterBusLocalConsumers are the consumers of the bridged topics which only
+// handle the messages published by their own backend. Pipelined handles the
+// events processed by its backend, so that each event is handled once.
+var clusterBusLocalConsumers = []string{"pipelined"}
+
+// newClusterTopics returns the cluster bus topics of the given names.
+func newClusterTopics(names []string) ([]messaging.ClusterTopic, error) {
+	topics := make([]messaging.ClusterTopic, 0, len(names))
+	for _, name := range names {
+		topic, ok := clusterBusTopics[name]
+		if !ok {
+			return nil, fmt.Errorf("invalid cluster bus topic %q", name)
+		}
+		topics = append(topics, topic)
+	}
+	return topics, nil
+}
+
+// bridgesCheckRequests returns true if the cluster bus topics of the given
+// names bridge the check requests, which schedulerd then publishes once
+// instead of sending them to every backend.
+func bridgesCheckRequests(names []string) bool {
+	for _, name := range names {
+		if name == "check" {
+			return true
+		}
+	}
+	return false
+}
+
+// watchEventMessage is the encoding of an entity config watch event, whose
+// value is always wrapped with a wrap.Wrapper.
+type watchEventMessage struct {
+	Type     storev2.WatchActionType
+	Key      storev2.ResourceRequest
+	Value    *wrap.Wrapper
+	Revision int64
+}
+
+func encodeWatchEvent(msg interface{}) ([]byte, error) {
+	event, ok := msg.(*storev2.WatchEvent)
+	if !ok || event == nil {
+		return nil, fmt.Errorf("unexpected entity config message: %T", msg)
+	}
+	message := watchEventMessage{
+		Type:     event.Type,
+		Key:      event.Key,
+		Revision: event.Revision,
+	}
+	if event.Value != nil {
+		if wrapper, ok := event.Value.(*wrap.Wrapper); ok {
+			message.Value = wrapper
+		} else {
+			resource, err := event.Value.Unwrap()
+			if err != nil {
+				return nil, err
+			}
+			if message.Value, err = wrap.Resource(resource); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return json.Marshal(message)
+}
+
+func decodeWatchEvent(b []byte) (interface{}, error) {
+	var message watchEventMessage
+	if err := json.Unmarshal(b, &message); err != nil {
+		return nil, err
+	}
+	event := &storev2.WatchEvent{
+		Type:     message.Type,
+		Key:      message.Key,
+		Revision: message.Revision,
+	}
+	if message.Value != nil {
+		event.Value = message.Value
+	}
+	return event, nil
+}
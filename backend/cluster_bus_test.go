@@ -0,0 +1,92 @@
+package backend
+
+import (
+	"encoding/json"
+	"testing"
+
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+	"github.com/sensu/sensu-go/backend/messaging"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClusterTopics(t *testing.T) {
+	topics, err := newClusterTopics([]string{"event", "agent-conn"})
+	require.NoError(t, err)
+	require.Len(t, topics, 2)
+	assert.Equal(t, messaging.TopicEvent, topics[0].Topic)
+	assert.Equal(t, messaging.TopicAgentConnectionState, topics[1].Topic)
+
+	_, err = newClusterTopics([]string{"keepalive"})
+	assert.Error(t, err)
+
+	// The check topic bridges the topics of every subscription
+	topics, err = newClusterTopics([]string{"event", "check"})
+	require.NoError(t, err)
+	require.Len(t, topics, 2)
+	assert.Equal(t, messaging.TopicSubscriptions, topics[1].Topic)
+	assert.True(t, bridgesCheckRequests([]string{"event", "check"}))
+	assert.False(t, bridgesCheckRequests([]string{"event", "agent-conn"}))
+}
+
+func TestClusterBusTopicsCodecs(t *testing.T) {
+	event := corev2.FixtureEvent("entity", "check")
+	b, err := json.Marshal(event)
+	require.NoError(t, err)
+	decoded, err := clusterBusTopics["event"].Decode(b)
+	require.NoError(t, err)
+	assert.Equal(t, event.Check.Name, decoded.(*corev2.Event).Check.Name)
+
+	notification := messaging.AgentNotification{Namespace: "default", Name: "entity", Connected: true}
+	b, err = json.Marshal(notification)
+	require.NoError(t, err)
+	decoded, err = clusterBusTopics["agent-conn"].Decode(b)
+	require.NoError(t, err)
+	assert.Equal(t, notification, decoded)
+
+	request := &corev2.CheckRequest{Config: corev2.FixtureCheckConfig("check"), Issued: 42}
+	b, err = json.Marshal(request)
+	require.NoError(t, err)
+	decoded, err = clusterBusTopics["check"].Decode(b)
+	require.NoError(t, err)
+	assert.Equal(t, request.Config.Name, decoded.(*corev2.CheckRequest).Config.Name)
+	assert.Equal(t, request.Issued, decoded.(*corev2.CheckRequest).Issued)
+}
+
+func TestClusterBusWatchEventCodec(t *testing.T) {
+	config := corev3.FixtureEntityConfig("entity")
+	config.Subscriptions = []string{"linux"}
+	wrapper, err := storev2.WrapResource(config)
+	require.NoError(t, err)
+	event := &storev2.WatchEvent{
+		Type:     storev2.WatchUpdate,
+		Key:      storev2.NewResourceRequestFromResource(config),
+		Value:    wrapper,
+		Revision: 42,
+	}
+
+	topic := clusterBusTopics["entity-config"]
+	b, err := topic.Encode(event)
+	require.NoError(t, err)
+	decoded, err := topic.Decode(b)
+	require.NoError(t, err)
+	watchEvent := decoded.(*storev2.WatchEvent)
+	assert.Equal(t, event.Type, watchEvent.Type)
+	assert.Equal(t, event.Key, watchEvent.Key)
+	assert.Equal(t, event.Revision, watchEvent.Revision)
+	got, err := storev2.ReadEventValue[*corev3.EntityConfig](*watchEvent)
+	require.NoError(t, err)
+	assert.Equal(t, config.Subscriptions, got.Subscriptions)
+
+	// Delete events have no value
+	b, err = topic.Encode(&storev2.WatchEvent{Type: storev2.WatchDelete, Key: event.Key})
+	require.NoError(t, err)
+	decoded, err = topic.Decode(b)
+	require.NoError(t, err)
+	assert.Nil(t, decoded.(*storev2.WatchEvent).Value)
+
+	_, err = topic.Encode("foo")
+	assert.Error(t, err)
+}
@@ -17,6 +17,7 @@ import (
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/sensu/sensu-go/backend/apid/middlewares"
+	"github.com/sensu/sensu-go/backend/ratelimit"
 	"github.com/sensu/sensu-go/backend/store/postgres"
 
 	corev2 "github.com/sensu/core/v2"
@@ -38,6 +39,8 @@ var DeprecateDashboardFlags = true
 var (
 	annotations               map[string]string
 	labels                    map[string]string
+	apiRateLimits             map[string]string
+	apiIPRateLimits           map[string]string
 	configFileDefaultLocation = filepath.Join(path.SystemConfigDir(), "backend.yml")
 )
 
@@ -48,12 +51,15 @@ const (
 	flagConfigFile            = "config-file"
 	flagAgentHost             = "agent-host"
 	flagAgentPort             = "agent-port"
+	flagAPIAllowedOrigins     = "api-allowed-origins"
 	flagAPIListenAddress      = "api-listen-address"
 	flagAPIRequestLimit       = "api-request-limit"
 	flagAPIURL                = "api-url"
 	flagAPIWriteTimeout       = "api-write-timeout"
 	flagAssetsRateLimit       = "assets-rate-limit"
 	flagAssetsBurstLimit      = "assets-burst-limit"
+	flagAssetSignaturePolicy  = "asset-signature-policy"
+	flagAssetTrustedKeys      = "asset-trusted-keys"
 	flagDashboardHost         = "dashboard-host"
 	flagDashboardPort         = "dashboard-port"
 	flagDashboardCertFile     = "dashboard-cert-file"
@@ -72,9 +78,12 @@ const (
 	flagName                  = "name"
 
 	// Postgres store
-	flagPGDSN                = "pg-dsn"                  // postgresql connection string
-	flagEventCacheWriteLimit = "event-cache-write-limit" // maximum number of tps that event cache will write
-	flagDisableEventCache    = "disable-event-cache"     // don't cache events, always write through to postgresql
+	flagPGDSN                     = "pg-dsn"                       // postgresql connection string
+	flagEventCacheWriteLimit      = "event-cache-write-limit"      // maximum number of tps that event cache will write
+	flagDisableEventCache         = "disable-event-cache"          // don't cache events, always write through to postgresql
+	flagEventHistoryRetention     = "event-history-retention"      // how long to keep event status history
+	flagConfigHistoryMaxRevisions = "config-history-max-revisions" // how many revisions to keep of each resource
+	flagConfigHistoryRetention    = "config-history-retention"     // how long to keep the revisions of resources
 
 	// Metric logging flags
 	flagDisablePlatformMetrics         = "disable-platform-metrics"
@@ -93,6 +102,27 @@ const (
 	// flagEventLogParallelEncoders used to indicate parallel encoders should be used for event logging
 	flagEventLogParallelEncoders = "event-log-parallel-encoders"
 
+	// flagAuditLogFile indicates the path to the audit log file
+	flagAuditLogFile = "audit-log-file"
+
+	// flagAuditLogDatabase indicates that the audit log is recorded in the database
+	flagAuditLogDatabase = "audit-log-database"
+
+	// flagAuditLogRetention indicates how long the audit log is kept in the database
+	flagAuditLogRetention = "audit-log-retention"
+
+	// flagClusterBusTopics indicates the topics bridged across the backends
+	flagClusterBusTopics = "cluster-bus-topics"
+
+	// flagAPIRateLimits indicates the rate limits of the API clients by route group
+	flagAPIRateLimits = "api-rate-limits"
+
+	// flagAPIRateLimitCluster indicates that the rate limits are shared by the backends
+	flagAPIRateLimitCluster = "api-rate-limit-cluster"
+
+	// flagAPIIPRateLimits indicates the rate limits of the API source IPs by route group
+	flagAPIIPRateLimits = "api-ip-rate-limits"
+
 	// Default values
 
 	// Start command usage template
@@ -161,12 +191,15 @@ func StartCommand(initialize InitializeFunc) *cobra.Command {
 				AgentHost:             viper.GetString(flagAgentHost),
 				AgentPort:             viper.GetInt(flagAgentPort),
 				AgentWriteTimeout:     viper.GetInt(backend.FlagAgentWriteTimeout),
+				APIAllowedOrigins:     viper.GetStringSlice(flagAPIAllowedOrigins),
 				APIListenAddress:      viper.GetString(flagAPIListenAddress),
 				APIRequestLimit:       viper.GetInt64(flagAPIRequestLimit),
 				APIURL:                viper.GetString(flagAPIURL),
 				APIWriteTimeout:       viper.GetDuration(flagAPIWriteTimeout),
 				AssetsRateLimit:       rate.Limit(viper.GetFloat64(flagAssetsRateLimit)),
 				AssetsBurstLimit:      viper.GetInt(flagAssetsBurstLimit),
+				AssetSignaturePolicy:  asset.SignaturePolicy(viper.GetString(flagAssetSignaturePolicy)),
+				AssetTrustedKeys:      viper.GetStringSlice(flagAssetTrustedKeys),
 				DashboardHost:         viper.GetString(flagDashboardHost),
 				DashboardPort:         viper.GetInt(flagDashboardPort),
 				DashboardTLSCertFile:  viper.GetString(flagDashboardCertFile),
@@ -185,12 +218,20 @@ func StartCommand(initialize InitializeFunc) *cobra.Command {
 				EventLogBufferWait:             viper.GetDuration(flagEventLogBufferWait),
 				EventLogFile:                   viper.GetString(flagEventLogFile),
 				EventLogParallelEncoders:       viper.GetBool(flagEventLogParallelEncoders),
+				AuditLogFile:                   viper.GetString(flagAuditLogFile),
+				AuditLogDatabase:               viper.GetBool(flagAuditLogDatabase),
+				AuditLogRetention:              viper.GetDuration(flagAuditLogRetention),
+				APIRateLimitCluster:            viper.GetBool(flagAPIRateLimitCluster),
+				ClusterBusTopics:               viper.GetStringSlice(flagClusterBusTopics),
 
 				Store: backend.StoreConfig{
 					PostgresStore: postgres.Config{
-						DSN:               viper.GetString(flagPGDSN),
-						MaxTPS:            viper.GetInt(flagEventCacheWriteLimit),
-						DisableEventCache: viper.GetBool(flagDisableEventCache),
+						DSN:                       viper.GetString(flagPGDSN),
+						MaxTPS:                    viper.GetInt(flagEventCacheWriteLimit),
+						DisableEventCache:         viper.GetBool(flagDisableEventCache),
+						EventHistoryRetention:     viper.GetDuration(flagEventHistoryRetention),
+						ConfigHistoryMaxRevisions: viper.GetInt(flagConfigHistoryMaxRevisions),
+						ConfigHistoryRetention:    viper.GetDuration(flagConfigHistoryRetention),
 					},
 				},
 			}
@@ -206,6 +247,23 @@ func StartCommand(initialize InitializeFunc) *cobra.Command {
 				cfg.Annotations = annotations
 			}
 
+			rateLimits := viper.GetStringMapString(flagAPIRateLimits)
+			if flag := cmd.Flags().Lookup(flagAPIRateLimits); flag != nil && flag.Changed {
+				rateLimits = apiRateLimits
+			}
+			cfg.APIRateLimits, err = ratelimit.ParseLimits(rateLimits)
+			if err != nil {
+				return fmt.Errorf("invalid --%s: %s", flagAPIRateLimits, err)
+			}
+			ipRateLimits := viper.GetStringMapString(flagAPIIPRateLimits)
+			if flag := cmd.Flags().Lookup(flagAPIIPRateLimits); flag != nil && flag.Changed {
+				ipRateLimits = apiIPRateLimits
+			}
+			cfg.APIIPRateLimits, err = ratelimit.ParseLimits(ipRateLimits)
+			if err != nil {
+				return fmt.Errorf("invalid --%s: %s", flagAPIIPRateLimits, err)
+			}
+
 			// Sensu APIs TLS config
 			certFile := viper.GetString(flagCertFile)
 			keyFile := viper.GetString(flagKeyFile)
@@ -318,12 +376,15 @@ func handleConfig(cmd *cobra.Command, arguments []string, server bool) error {
 		// Flag defaults
 		viper.SetDefault(flagAgentHost, "[::]")
 		viper.SetDefault(flagAgentPort, 8081)
+		viper.SetDefault(flagAPIAllowedOrigins, []string{})
 		viper.SetDefault(flagAPIListenAddress, "[::]:8080")
 		viper.SetDefault(flagAPIRequestLimit, middlewares.MaxBytesLimit)
 		viper.SetDefault(flagAPIURL, "http://localhost:8080")
 		viper.SetDefault(flagAPIWriteTimeout, "15s")
 		viper.SetDefault(flagAssetsRateLimit, asset.DefaultAssetsRateLimit)
 		viper.SetDefault(flagAssetsBurstLimit, asset.DefaultAssetsBurstLimit)
+		viper.SetDefault(flagAssetSignaturePolicy, string(asset.SignaturePolicyEnforce))
+		viper.SetDefault(flagAssetTrustedKeys, []string{})
 		viper.SetDefault(flagDashboardHost, "[::]")
 		viper.SetDefault(flagDashboardPort, 3000)
 		viper.SetDefault(flagDashboardCertFile, "")
@@ -349,8 +410,16 @@ func handleConfig(cmd *cobra.Command, arguments []string, server bool) error {
 		viper.SetDefault(flagEventLogBufferSize, 100000)
 		viper.SetDefault(flagEventLogFile, "")
 		viper.SetDefault(flagEventLogParallelEncoders, false)
+		viper.SetDefault(flagAuditLogFile, "")
+		viper.SetDefault(flagAuditLogDatabase, false)
+		viper.SetDefault(flagAuditLogRetention, 90*24*time.Hour)
+		viper.SetDefault(flagAPIRateLimitCluster, false)
+		viper.SetDefault(flagClusterBusTopics, []string{})
 		viper.SetDefault(flagEventCacheWriteLimit, 1000)
 		viper.SetDefault(flagDisableEventCache, false)
+		viper.SetDefault(flagEventHistoryRetention, 7*24*time.Hour)
+		viper.SetDefault(flagConfigHistoryMaxRevisions, 10)
+		viper.SetDefault(flagConfigHistoryRetention, time.Duration(0))
 
 		backendName, err := os.Hostname()
 		if err != nil {
@@ -414,17 +483,29 @@ func flagSet(server bool) *pflag.FlagSet {
 	flagSet.Bool(flagDisableEventCache, viper.GetBool(flagDisableEventCache), "disable caching events, write events directly to postgresql")
 	_ = flagSet.SetAnnotation(flagDisableEventCache, "categories", []string{"store"})
 
+	flagSet.Duration(flagEventHistoryRetention, viper.GetDuration(flagEventHistoryRetention), "how long to keep event status history, 0 keeps it forever")
+	_ = flagSet.SetAnnotation(flagEventHistoryRetention, "categories", []string{"store"})
+
+	flagSet.Int(flagConfigHistoryMaxRevisions, viper.GetInt(flagConfigHistoryMaxRevisions), "number of revisions to keep of each configuration resource, 0 keeps them all")
+	_ = flagSet.SetAnnotation(flagConfigHistoryMaxRevisions, "categories", []string{"store"})
+
+	flagSet.Duration(flagConfigHistoryRetention, viper.GetDuration(flagConfigHistoryRetention), "how long to keep the revisions of configuration resources, 0 keeps them regardless of age")
+	_ = flagSet.SetAnnotation(flagConfigHistoryRetention, "categories", []string{"store"})
+
 	if server {
 		// Main Flags
 		flagSet.String(flagName, viper.GetString(flagName), "backend name")
 		flagSet.String(flagAgentHost, viper.GetString(flagAgentHost), "agent listener host")
 		flagSet.Int(flagAgentPort, viper.GetInt(flagAgentPort), "agent listener port")
+		flagSet.StringSlice(flagAPIAllowedOrigins, viper.GetStringSlice(flagAPIAllowedOrigins), "comma-delimited list of the origins, besides the origin of the api, from which browsers can open GraphQL subscriptions, or * to allow any origin")
 		flagSet.String(flagAPIListenAddress, viper.GetString(flagAPIListenAddress), "address to listen on for api traffic")
 		flagSet.Int64(flagAPIRequestLimit, viper.GetInt64(flagAPIRequestLimit), "maximum API request body size, in bytes")
 		flagSet.String(flagAPIURL, viper.GetString(flagAPIURL), "url of the api to connect to")
 		flagSet.Duration(flagAPIWriteTimeout, viper.GetDuration(flagAPIWriteTimeout), "maximum duration before timing out writes of responses")
 		flagSet.Float64(flagAssetsRateLimit, viper.GetFloat64(flagAssetsRateLimit), "maximum number of assets fetched per second")
 		flagSet.Int(flagAssetsBurstLimit, viper.GetInt(flagAssetsBurstLimit), "asset fetch burst limit")
+		flagSet.String(flagAssetSignaturePolicy, viper.GetString(flagAssetSignaturePolicy), "policy of the assets whose signature can't be verified with the trusted keys, warn or enforce")
+		flagSet.StringSlice(flagAssetTrustedKeys, viper.GetStringSlice(flagAssetTrustedKeys), "comma-delimited list of the files of the public keys trusted to sign the assets, minisign or PEM encoded ECDSA keys. The signatures of the assets are verified when set")
 		flagSet.String(flagDashboardHost, viper.GetString(flagDashboardHost), "dashboard listener host")
 		flagSet.Int(flagDashboardPort, viper.GetInt(flagDashboardPort), "dashboard listener port")
 		flagSet.String(flagDashboardCertFile, viper.GetString(flagDashboardCertFile), "dashboard TLS certificate in PEM format")
@@ -469,6 +550,16 @@ func flagSet(server bool) *pflag.FlagSet {
 		// event back-pressure could stop the backend and its agent sessions from
 		// producing and processing new events and possibly lead to a crash.
 		_ = flagSet.String(flagEventLogBufferWait, "10ms", "full buffer wait time")
+
+		flagSet.String(flagAuditLogFile, viper.GetString(flagAuditLogFile), "path to the audit log file of the API changes, disabled when empty")
+		flagSet.Bool(flagAuditLogDatabase, viper.GetBool(flagAuditLogDatabase), "record the audit log of the API changes in postgresql")
+		flagSet.Duration(flagAuditLogRetention, viper.GetDuration(flagAuditLogRetention), "how long to keep the audit log in postgresql, 0 keeps it forever")
+
+		flagSet.StringToStringVar(&apiRateLimits, flagAPIRateLimits, nil, "rate limits of the requests of each API client (API key, user or source IP) by route group (e.g. core/v2, core/v3, graphql), as RATE[:BURST] in requests per second, e.g. core/v2=10:20,graphql=5")
+		flagSet.Bool(flagAPIRateLimitCluster, viper.GetBool(flagAPIRateLimitCluster), "share the API rate limits across the backends of the cluster, through postgresql")
+		flagSet.StringToStringVar(&apiIPRateLimits, flagAPIIPRateLimits, nil, "rate limits of the requests of each source IP, applied before authentication, by route group (e.g. auth, core/v2, graphql), as RATE[:BURST] in requests per second, e.g. auth=1:10")
+
+		flagSet.StringSlice(flagClusterBusTopics, viper.GetStringSlice(flagClusterBusTopics), "comma-delimited list of topics bridged across the backends of the cluster (event, entity-config, agent-conn, check), disabled when empty. The check topic publishes the scheduled check requests once on the cluster bus instead of queueing them for every backend, except the checks with secrets")
 	}
 
 	flagSet.SetOutput(ioutil.Discard)
@@ -4,7 +4,9 @@ import (
 	"time"
 
 	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/asset"
 	"github.com/sensu/sensu-go/backend/licensing"
+	"github.com/sensu/sensu-go/backend/ratelimit"
 	"github.com/sensu/sensu-go/backend/store/postgres"
 	"golang.org/x/time/rate"
 )
@@ -54,10 +56,11 @@ type Config struct {
 	AgentWriteTimeout int
 
 	// Apid Configuration
-	APIListenAddress string
-	APIRequestLimit  int64
-	APIURL           string
-	APIWriteTimeout  time.Duration
+	APIAllowedOrigins []string
+	APIListenAddress  string
+	APIRequestLimit   int64
+	APIURL            string
+	APIWriteTimeout   time.Duration
 
 	// AssetsRateLimit is the maximum number of assets per second that will be fetched.
 	AssetsRateLimit rate.Limit
@@ -65,6 +68,14 @@ type Config struct {
 	// AssetsBurstLimit is the maximum amount of burst allowed in a rate interval.
 	AssetsBurstLimit int
 
+	// AssetSignaturePolicy is the policy of the assets whose signature can't
+	// be verified with the AssetTrustedKeys.
+	AssetSignaturePolicy asset.SignaturePolicy
+
+	// AssetTrustedKeys are the files of the public keys trusted to sign the
+	// assets. The signatures of the assets are verified when set.
+	AssetTrustedKeys []string
+
 	// Dashboardd Configuration
 	DashboardHost         string
 	DashboardPort         int
@@ -96,5 +107,29 @@ type Config struct {
 	EventLogFile             string
 	EventLogParallelEncoders bool
 
+	// AuditLogFile is the path of the file the audit log is written to. The
+	// audit log is not written to a file when empty.
+	AuditLogFile string
+
+	// AuditLogDatabase enables the recording of the audit log in the
+	// database, and AuditLogRetention is how long it is kept there.
+	AuditLogDatabase  bool
+	AuditLogRetention time.Duration
+
+	// APIRateLimits are the rate limits of the requests of each API client by
+	// route group, e.g. core/v2. APIRateLimitCluster shares them across the
+	// backends of the cluster, through the database.
+	APIRateLimits       map[string]ratelimit.Limit
+	APIRateLimitCluster bool
+
+	// APIIPRateLimits are the rate limits of the requests of each source IP
+	// by route group, e.g. auth or core/v2, applied before the requests are
+	// authenticated.
+	APIIPRateLimits map[string]ratelimit.Limit
+
+	// ClusterBusTopics are the names of the topics bridged across the backends
+	// of the cluster. The cluster bus is disabled when empty.
+	ClusterBusTopics []string
+
 	Store StoreConfig
 }
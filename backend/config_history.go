@@ -0,0 +1,40 @@
+package backend
+
+import (
+	"context"
+	"time"
+
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+// configHistoryPruneInterval is the interval at which the revisions of the
+// configuration resources that exceed the limits are deleted.
+const configHistoryPruneInterval = 10 * time.Minute
+
+// PruneConfigHistoryLoop periodically deletes the revisions of the
+// configuration resources beyond the most recent maxRevisions, or older than
+// retention, until ctx is cancelled. The latest revision of a resource is
+// always kept.
+func PruneConfigHistoryLoop(ctx context.Context, hs storev2.ConfigHistoryStore, maxRevisions int, retention time.Duration) {
+	ticker := time.NewTicker(configHistoryPruneInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			var before time.Time
+			if retention > 0 {
+				before = time.Now().Add(-retention)
+			}
+			pruned, err := hs.PruneRevisions(ctx, maxRevisions, before)
+			if err != nil {
+				logger.WithError(err).Error("error pruning configuration history")
+				continue
+			}
+			if pruned > 0 {
+				logger.WithField("revisions", pruned).Debug("pruned configuration history")
+			}
+		}
+	}
+}
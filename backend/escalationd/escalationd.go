@@ -0,0 +1,356 @@
+// Package escalationd runs the steps of the escalation policies of the open
+// incidents.
+package escalationd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev2 "github.com/sensu/core/v2"
+	pipelinev1 "github.com/sensu/sensu-go/api/pipeline/v1"
+	"github.com/sensu/sensu-go/backend/pipeline"
+	"github.com/sensu/sensu-go/backend/silenced"
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	metricspkg "github.com/sensu/sensu-go/metrics"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// EscalationNotifications is the name of the prometheus counter vec used
+	// to count the handlers notified by the steps of escalation policies.
+	EscalationNotifications = "sensu_go_escalation_notifications"
+
+	// DefaultInterval is the default interval at which the due incidents are
+	// claimed.
+	DefaultInterval = 5 * time.Second
+
+	// DefaultRecheckInterval is the default interval at which the incidents
+	// which are silenced, or whose steps are exhausted, are evaluated again.
+	DefaultRecheckInterval = time.Minute
+
+	// DefaultLease is the default time a claimed incident is reserved for
+	// the backend which claimed it. It is evaluated again by any backend if
+	// it is not updated before the end of the lease.
+	DefaultLease = 5 * time.Minute
+
+	// DefaultBatchSize is the default number of incidents claimed at once.
+	DefaultBatchSize = 100
+)
+
+var (
+	notificationsCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: EscalationNotifications,
+			Help: "The total number of handlers notified by the steps of escalation policies",
+		},
+		[]string{metricspkg.StatusLabelName},
+	)
+)
+
+func init() {
+	_ = prometheus.Register(notificationsCounter)
+}
+
+// Config configures Escalationd.
+type Config struct {
+	// Store is used to get the escalation policies, events and silences.
+	Store storev2.Interface
+
+	// Incidents tracks the open incidents.
+	Incidents store.EscalationStore
+
+	// HandlerAdapters are the adapters of the handlers referenced by the
+	// steps of the escalation policies.
+	HandlerAdapters []pipeline.HandlerAdapter
+
+	// Interval is the interval at which the due incidents are claimed.
+	Interval time.Duration
+
+	// RecheckInterval is the interval at which the silenced and exhausted
+	// incidents are evaluated again.
+	RecheckInterval time.Duration
+
+	// Lease is the time a claimed incident is reserved for this backend.
+	Lease time.Duration
+
+	// BatchSize is the number of incidents claimed at once.
+	BatchSize int
+}
+
+// Escalationd evaluates the incidents of escalation policies once they are
+// due. An incident is closed when its event resolves or is deleted, and is
+// postponed while its event is silenced. Otherwise, the handlers of its next
+// step are notified with the current event, encoded to JSON.
+type Escalationd struct {
+	store           storev2.Interface
+	incidents       store.EscalationStore
+	adapters        []pipeline.HandlerAdapter
+	interval        time.Duration
+	recheckInterval time.Duration
+	lease           time.Duration
+	batchSize       int
+	now             func() time.Time
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	errChan chan error
+}
+
+// New creates a new Escalationd.
+func New(c Config) (*Escalationd, error) {
+	if c.Store == nil || c.Incidents == nil {
+		return nil, errors.New("escalationd requires a store and an incident store")
+	}
+	if c.Interval <= 0 {
+		c.Interval = DefaultInterval
+	}
+	if c.RecheckInterval <= 0 {
+		c.RecheckInterval = DefaultRecheckInterval
+	}
+	if c.Lease <= 0 {
+		c.Lease = DefaultLease
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = DefaultBatchSize
+	}
+	e := &Escalationd{
+		store:           c.Store,
+		incidents:       c.Incidents,
+		adapters:        c.HandlerAdapters,
+		interval:        c.Interval,
+		recheckInterval: c.RecheckInterval,
+		lease:           c.Lease,
+		batchSize:       c.BatchSize,
+		now:             time.Now,
+		errChan:         make(chan error, 1),
+	}
+	e.ctx, e.cancel = context.WithCancel(context.Background())
+	return e, nil
+}
+
+// Start starts claiming and evaluating the due incidents.
+func (e *Escalationd) Start() error {
+	e.wg.Add(1)
+	go e.run()
+	return nil
+}
+
+// Stop stops Escalationd.
+func (e *Escalationd) Stop() error {
+	e.cancel()
+	e.wg.Wait()
+	return nil
+}
+
+// Err returns a channel on which the errors of Escalationd are sent.
+func (e *Escalationd) Err() <-chan error {
+	return e.errChan
+}
+
+// Name returns the daemon name
+func (e *Escalationd) Name() string {
+	return "escalationd"
+}
+
+func (e *Escalationd) run() {
+	defer e.wg.Done()
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-e.ctx.Done():
+			return
+		case <-ticker.C:
+			e.evaluateDueIncidents(e.ctx)
+		}
+	}
+}
+
+// evaluateDueIncidents claims and evaluates the due incidents, until none are
+// left.
+func (e *Escalationd) evaluateDueIncidents(ctx context.Context) {
+	for ctx.Err() == nil {
+		incidents, err := e.incidents.ClaimDueIncidents(ctx, e.now(), e.batchSize, e.lease)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.WithError(err).Error("couldn't claim due incidents")
+			}
+			return
+		}
+		for _, incident := range incidents {
+			if err := e.evaluate(ctx, incident); err != nil {
+				logger.WithFields(incidentFields(incident)).WithError(err).
+					Error("couldn't evaluate incident, retrying at the end of its lease")
+			}
+		}
+		if len(incidents) < e.batchSize {
+			return
+		}
+	}
+}
+
+// evaluate evaluates an incident against the current state of its event and
+// of the silences, and runs the next step of its policy.
+func (e *Escalationd) evaluate(ctx context.Context, incident *store.EscalationIncident) error {
+	lager := logger.WithFields(incidentFields(incident))
+
+	pstore := storev2.Of[*pipelinev1.EscalationPolicy](e.store)
+	policy, err := pstore.Get(ctx, storev2.ID{Namespace: incident.Namespace, Name: incident.Policy})
+	if err != nil {
+		if _, ok := err.(*store.ErrNotFound); ok {
+			lager.Info("escalation policy not found, closing incident")
+			return e.incidents.CloseIncident(ctx, incident)
+		}
+		return err
+	}
+
+	nsctx := store.NamespaceContext(ctx, incident.Namespace)
+	event, err := e.store.GetEventStore().GetEventByEntityCheck(nsctx, incident.Entity, incident.Check)
+	if err != nil {
+		return err
+	}
+	if event == nil || !event.HasCheck() || event.Check.Status == 0 {
+		lager.Info("event resolved, closing incident")
+		return e.incidents.CloseIncident(ctx, incident)
+	}
+
+	now := e.now()
+	silences, err := e.store.GetSilencesStore().GetSilences(ctx, incident.Namespace)
+	if err != nil {
+		return err
+	}
+	if silencedBy := silenced.SilencedBy(event, activeSilences(silences, now)); len(silencedBy) > 0 {
+		lager.WithField("silenced", silencedBy).Debug("event silenced, postponing incident")
+		return e.postpone(ctx, lager, incident, now.Add(e.recheckInterval))
+	}
+
+	step := int(incident.Step)
+	repeats := incident.Repeats
+	if step >= len(policy.Steps) {
+		if !canRepeat(policy, repeats) {
+			// The steps are exhausted, the incident stays open until the
+			// event resolves
+			return e.postpone(ctx, lager, incident, now.Add(e.recheckInterval))
+		}
+		repeats++
+		step = 0
+	}
+
+	var dueAt time.Time
+	switch {
+	case step+1 < len(policy.Steps):
+		dueAt = now.Add(policy.StepDelay(step + 1))
+	case canRepeat(policy, repeats):
+		dueAt = now.Add(time.Duration(policy.Repeat.Interval) * time.Second)
+	default:
+		dueAt = now.Add(e.recheckInterval)
+	}
+
+	// The incident is advanced past the step before its handlers are
+	// notified, so that the step runs at most once, even if this backend
+	// fails in the meantime or another backend claimed the incident after
+	// its lease expired
+	advanced, err := e.incidents.AdvanceIncident(ctx, incident, uint32(step+1), repeats, dueAt)
+	if err != nil {
+		return err
+	}
+	if !advanced {
+		lager.Debug("incident advanced by another backend, skipping step")
+		return nil
+	}
+	incident.Step = uint32(step + 1)
+	incident.Repeats = repeats
+	incident.DueAt = dueAt
+
+	e.runStep(ctx, lager, policy.Steps[step], event)
+	return nil
+}
+
+// postpone postpones an incident to the given due time, at its current step,
+// unless another backend advanced it since it was claimed, so that a backend
+// whose lease expired never moves the incident back.
+func (e *Escalationd) postpone(ctx context.Context, lager *logrus.Entry, incident *store.EscalationIncident, dueAt time.Time) error {
+	postponed, err := e.incidents.AdvanceIncident(ctx, incident, incident.Step, incident.Repeats, dueAt)
+	if err != nil {
+		return err
+	}
+	if !postponed {
+		lager.Debug("incident advanced by another backend, not postponing it")
+		return nil
+	}
+	incident.DueAt = dueAt
+	return nil
+}
+
+// runStep notifies the handlers of a step. Handler errors are logged, and do
+// not prevent the escalation from progressing.
+func (e *Escalationd) runStep(ctx context.Context, lager *logrus.Entry, step *pipelinev1.EscalationStep, event *corev2.Event) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		lager.WithError(err).Error("couldn't encode event")
+		return
+	}
+	for _, ref := range step.Handlers {
+		hlager := lager.WithField("handler", ref.ResourceID())
+		adapter := e.handlerAdapter(ref)
+		if adapter == nil {
+			hlager.Error("no handler adapter found for escalation step handler")
+			notificationsCounter.WithLabelValues(metricspkg.StatusLabelError).Inc()
+			continue
+		}
+		if err := adapter.Handle(ctx, ref, event, data); err != nil {
+			hlager.WithError(err).Error("escalation step handler failed")
+			notificationsCounter.WithLabelValues(metricspkg.StatusLabelError).Inc()
+			continue
+		}
+		hlager.Info("notified escalation step handler")
+		notificationsCounter.WithLabelValues(metricspkg.StatusLabelSuccess).Inc()
+	}
+}
+
+func (e *Escalationd) handlerAdapter(ref *corev2.ResourceReference) pipeline.HandlerAdapter {
+	for _, adapter := range e.adapters {
+		if adapter.CanHandle(ref) {
+			return adapter
+		}
+	}
+	return nil
+}
+
+// canRepeat returns whether the steps of the policy can be repeated once more
+// for an incident whose steps were already repeated the given number of times.
+func canRepeat(policy *pipelinev1.EscalationPolicy, repeats uint32) bool {
+	if policy.Repeat == nil {
+		return false
+	}
+	return policy.Repeat.Count == 0 || repeats < policy.Repeat.Count
+}
+
+// activeSilences filters out the silences which have expired, but may not
+// have been deleted yet.
+func activeSilences(silences []*corev2.Silenced, now time.Time) []*corev2.Silenced {
+	active := make([]*corev2.Silenced, 0, len(silences))
+	for _, silence := range silences {
+		if silence.ExpireAt > 0 && time.Unix(silence.ExpireAt, 0).Before(now) {
+			continue
+		}
+		active = append(active, silence)
+	}
+	return active
+}
+
+func incidentFields(incident *store.EscalationIncident) logrus.Fields {
+	return logrus.Fields{
+		"namespace":         incident.Namespace,
+		"entity":            incident.Entity,
+		"check":             incident.Check,
+		"escalation_policy": incident.Policy,
+		"step":              incident.Step,
+	}
+}
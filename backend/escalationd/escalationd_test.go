@@ -0,0 +1,271 @@
+package escalationd
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	pipelinev1 "github.com/sensu/sensu-go/api/pipeline/v1"
+	"github.com/sensu/sensu-go/backend/pipeline"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingAdapter is a handler adapter recording the handlers it handles.
+type recordingAdapter struct {
+	handled []string
+	err     error
+
+	// onHandle is called before a handler is handled, if set
+	onHandle func()
+}
+
+func (r *recordingAdapter) Name() string { return "recording" }
+
+func (r *recordingAdapter) CanHandle(ref *corev2.ResourceReference) bool {
+	return ref.APIVersion == "core/v2" && ref.Type == "Handler"
+}
+
+func (r *recordingAdapter) Handle(ctx context.Context, ref *corev2.ResourceReference, event *corev2.Event, data []byte) error {
+	if r.onHandle != nil {
+		r.onHandle()
+	}
+	r.handled = append(r.handled, ref.Name)
+	return r.err
+}
+
+type fixture struct {
+	escalationd *Escalationd
+	adapter     *recordingAdapter
+	incidents   *mockstore.EscalationStore
+	updated     []store.EscalationIncident
+	closed      bool
+	now         time.Time
+}
+
+func newFixture(t *testing.T, policy *pipelinev1.EscalationPolicy, event *corev2.Event, silences []*corev2.Silenced) *fixture {
+	t.Helper()
+	f := &fixture{
+		adapter:   &recordingAdapter{},
+		incidents: new(mockstore.EscalationStore),
+		now:       time.Unix(1700000000, 0),
+	}
+
+	s := new(mockstore.V2MockStore)
+	cs := new(mockstore.ConfigStore)
+	st := new(mockstore.MockStore)
+	s.On("GetConfigStore").Return(cs)
+	s.On("GetEventStore").Return(st)
+	s.On("GetSilencesStore").Return(st)
+	if policy == nil {
+		cs.On("Get", mock.Anything, mock.Anything).Return(nil, &store.ErrNotFound{})
+	} else {
+		cs.On("Get", mock.Anything, mock.Anything).Return(mockstore.Wrapper[*pipelinev1.EscalationPolicy]{Value: policy}, nil)
+	}
+	st.On("GetEventByEntityCheck", mock.Anything, "entity1", "check1").Return(event, nil)
+	st.On("GetSilences", mock.Anything, "default").Return(silences, nil)
+
+	f.incidents.On("AdvanceIncident", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		incident := *args.Get(1).(*store.EscalationIncident)
+		incident.Step = args.Get(2).(uint32)
+		incident.Repeats = args.Get(3).(uint32)
+		incident.DueAt = args.Get(4).(time.Time)
+		f.updated = append(f.updated, incident)
+	}).Return(true, nil)
+	f.incidents.On("CloseIncident", mock.Anything, mock.Anything).Run(func(mock.Arguments) {
+		f.closed = true
+	}).Return(nil)
+
+	var err error
+	f.escalationd, err = New(Config{
+		Store:           s,
+		Incidents:       f.incidents,
+		HandlerAdapters: []pipeline.HandlerAdapter{f.adapter},
+	})
+	require.NoError(t, err)
+	f.escalationd.now = func() time.Time { return f.now }
+	return f
+}
+
+func failingEvent() *corev2.Event {
+	event := corev2.FixtureEvent("entity1", "check1")
+	event.Check.Status = 2
+	return event
+}
+
+func newIncident(step, repeats uint32) *store.EscalationIncident {
+	return &store.EscalationIncident{
+		Namespace: "default",
+		Entity:    "entity1",
+		Check:     "check1",
+		Policy:    "on-call",
+		Step:      step,
+		Repeats:   repeats,
+	}
+}
+
+func TestEvaluateRunsSteps(t *testing.T) {
+	policy := pipelinev1.FixtureEscalationPolicy("on-call")
+	f := newFixture(t, policy, failingEvent(), nil)
+	incident := newIncident(0, 0)
+
+	require.NoError(t, f.escalationd.evaluate(context.Background(), incident))
+	assert.Equal(t, []string{"primary"}, f.adapter.handled)
+	require.Len(t, f.updated, 1)
+	assert.Equal(t, uint32(1), f.updated[0].Step)
+	assert.Equal(t, f.now.Add(900*time.Second), f.updated[0].DueAt)
+
+	// The last step leaves the incident open until the event resolves
+	require.NoError(t, f.escalationd.evaluate(context.Background(), incident))
+	assert.Equal(t, []string{"primary", "secondary"}, f.adapter.handled)
+	require.Len(t, f.updated, 2)
+	assert.Equal(t, uint32(2), f.updated[1].Step)
+	assert.Equal(t, f.now.Add(DefaultRecheckInterval), f.updated[1].DueAt)
+
+	require.NoError(t, f.escalationd.evaluate(context.Background(), incident))
+	assert.Len(t, f.adapter.handled, 2)
+	assert.False(t, f.closed)
+}
+
+func TestEvaluateRepeatsSteps(t *testing.T) {
+	policy := pipelinev1.FixtureEscalationPolicy("on-call")
+	policy.Repeat = &pipelinev1.EscalationRepeat{Count: 1, Interval: 3600}
+	f := newFixture(t, policy, failingEvent(), nil)
+	incident := newIncident(1, 0)
+
+	require.NoError(t, f.escalationd.evaluate(context.Background(), incident))
+	assert.Equal(t, []string{"secondary"}, f.adapter.handled)
+	assert.Equal(t, f.now.Add(time.Hour), f.updated[0].DueAt)
+
+	require.NoError(t, f.escalationd.evaluate(context.Background(), incident))
+	assert.Equal(t, []string{"secondary", "primary"}, f.adapter.handled)
+	assert.Equal(t, uint32(1), f.updated[1].Repeats)
+	assert.Equal(t, uint32(1), f.updated[1].Step)
+
+	require.NoError(t, f.escalationd.evaluate(context.Background(), incident))
+	assert.Equal(t, []string{"secondary", "primary", "secondary"}, f.adapter.handled)
+	assert.Equal(t, f.now.Add(DefaultRecheckInterval), f.updated[2].DueAt)
+}
+
+func TestEvaluateClosesResolvedIncidents(t *testing.T) {
+	resolved := failingEvent()
+	resolved.Check.Status = 0
+	for name, event := range map[string]*corev2.Event{"resolved": resolved, "deleted": nil} {
+		t.Run(name, func(t *testing.T) {
+			f := newFixture(t, pipelinev1.FixtureEscalationPolicy("on-call"), event, nil)
+			require.NoError(t, f.escalationd.evaluate(context.Background(), newIncident(0, 0)))
+			assert.True(t, f.closed)
+			assert.Empty(t, f.adapter.handled)
+		})
+	}
+}
+
+func TestEvaluateClosesIncidentsOfDeletedPolicies(t *testing.T) {
+	f := newFixture(t, nil, failingEvent(), nil)
+	require.NoError(t, f.escalationd.evaluate(context.Background(), newIncident(0, 0)))
+	assert.True(t, f.closed)
+}
+
+func TestEvaluatePostponesSilencedIncidents(t *testing.T) {
+	event := failingEvent()
+	silence := corev2.FixtureSilenced("*:check1")
+	f := newFixture(t, pipelinev1.FixtureEscalationPolicy("on-call"), event, []*corev2.Silenced{silence})
+
+	require.NoError(t, f.escalationd.evaluate(context.Background(), newIncident(0, 0)))
+	assert.Empty(t, f.adapter.handled)
+	require.Len(t, f.updated, 1)
+	assert.Equal(t, uint32(0), f.updated[0].Step)
+	assert.Equal(t, f.now.Add(DefaultRecheckInterval), f.updated[0].DueAt)
+
+	// Expired silences are ignored
+	silence.ExpireAt = f.now.Add(-time.Minute).Unix()
+	require.NoError(t, f.escalationd.evaluate(context.Background(), newIncident(0, 0)))
+	assert.Equal(t, []string{"primary"}, f.adapter.handled)
+}
+
+func TestEvaluateHandlerErrors(t *testing.T) {
+	f := newFixture(t, pipelinev1.FixtureEscalationPolicy("on-call"), failingEvent(), nil)
+	f.adapter.err = errors.New("handler failed")
+
+	// The escalation progresses despite the handler errors
+	require.NoError(t, f.escalationd.evaluate(context.Background(), newIncident(0, 0)))
+	assert.Equal(t, []string{"primary"}, f.adapter.handled)
+	assert.Equal(t, uint32(1), f.updated[0].Step)
+}
+
+func TestEvaluateAdvancesBeforeRunningSteps(t *testing.T) {
+	f := newFixture(t, pipelinev1.FixtureEscalationPolicy("on-call"), failingEvent(), nil)
+	f.adapter.onHandle = func() {
+		// The step is already advanced when its handlers are notified
+		require.Len(t, f.updated, 1)
+		assert.Equal(t, uint32(1), f.updated[0].Step)
+	}
+	require.NoError(t, f.escalationd.evaluate(context.Background(), newIncident(0, 0)))
+	assert.Equal(t, []string{"primary"}, f.adapter.handled)
+}
+
+func TestEvaluateSkipsStepsAdvancedElsewhere(t *testing.T) {
+	f := newFixture(t, pipelinev1.FixtureEscalationPolicy("on-call"), failingEvent(), nil)
+	incidents := new(mockstore.EscalationStore)
+	incidents.On("AdvanceIncident", mock.Anything, mock.Anything, uint32(1), uint32(0), mock.Anything).
+		Return(false, nil)
+	f.escalationd.incidents = incidents
+
+	// Another backend claimed the incident once its lease expired, and ran
+	// the step
+	incident := newIncident(0, 0)
+	require.NoError(t, f.escalationd.evaluate(context.Background(), incident))
+	assert.Empty(t, f.adapter.handled)
+	assert.Equal(t, uint32(0), incident.Step)
+
+	incidents = new(mockstore.EscalationStore)
+	incidents.On("AdvanceIncident", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(false, errors.New("error"))
+	f.escalationd.incidents = incidents
+	assert.Error(t, f.escalationd.evaluate(context.Background(), incident))
+	assert.Empty(t, f.adapter.handled)
+}
+
+func TestEvaluateDueIncidents(t *testing.T) {
+	f := newFixture(t, pipelinev1.FixtureEscalationPolicy("on-call"), failingEvent(), nil)
+	f.escalationd.batchSize = 1
+	f.incidents.On("ClaimDueIncidents", mock.Anything, f.now, 1, DefaultLease).
+		Return([]*store.EscalationIncident{newIncident(0, 0)}, nil).Once()
+	f.incidents.On("ClaimDueIncidents", mock.Anything, f.now, 1, DefaultLease).
+		Return([]*store.EscalationIncident{}, nil).Once()
+
+	f.escalationd.evaluateDueIncidents(context.Background())
+	assert.Equal(t, []string{"primary"}, f.adapter.handled)
+	f.incidents.AssertNumberOfCalls(t, "ClaimDueIncidents", 2)
+}
+
+func TestEvaluatePostponesOnlyClaimedSteps(t *testing.T) {
+	silence := corev2.FixtureSilenced("*:check1")
+	f := newFixture(t, pipelinev1.FixtureEscalationPolicy("on-call"), failingEvent(), []*corev2.Silenced{silence})
+	incidents := new(mockstore.EscalationStore)
+	incidents.On("AdvanceIncident", mock.Anything, mock.Anything, uint32(0), uint32(0), mock.Anything).
+		Return(false, nil)
+	f.escalationd.incidents = incidents
+
+	// Another backend advanced the incident once its lease expired, the
+	// silenced incident is not moved back to its claimed step
+	incident := newIncident(0, 0)
+	require.NoError(t, f.escalationd.evaluate(context.Background(), incident))
+	assert.True(t, incident.DueAt.IsZero())
+	incidents.AssertNumberOfCalls(t, "AdvanceIncident", 1)
+
+	// Same for the incidents whose steps are exhausted
+	f = newFixture(t, pipelinev1.FixtureEscalationPolicy("on-call"), failingEvent(), nil)
+	f.escalationd.incidents = incidents
+	incident = newIncident(2, 0)
+	incidents.On("AdvanceIncident", mock.Anything, mock.Anything, uint32(2), uint32(0), mock.Anything).
+		Return(false, nil)
+	require.NoError(t, f.escalationd.evaluate(context.Background(), incident))
+	assert.True(t, incident.DueAt.IsZero())
+	assert.Empty(t, f.adapter.handled)
+}
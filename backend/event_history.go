@@ -0,0 +1,34 @@
+package backend
+
+import (
+	"context"
+	"time"
+
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+// eventHistoryPruneInterval is the interval at which expired event history
+// entries are deleted.
+const eventHistoryPruneInterval = 10 * time.Minute
+
+// PruneEventHistoryLoop periodically deletes the event history entries that
+// are older than the given retention, until ctx is cancelled.
+func PruneEventHistoryLoop(ctx context.Context, hs store.EventHistoryStore, retention time.Duration) {
+	ticker := time.NewTicker(eventHistoryPruneInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			pruned, err := hs.PruneEventHistory(ctx, time.Now().Add(-retention))
+			if err != nil {
+				logger.WithError(err).Error("error pruning event history")
+				continue
+			}
+			if pruned > 0 {
+				logger.WithField("entries", pruned).Debug("pruned event history")
+			}
+		}
+	}
+}
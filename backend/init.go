@@ -80,6 +80,10 @@ func InitializeStore(ctx context.Context, db *pgxpool.Pool, config *Config) (*Ba
 	if limit == 0 {
 		limit = asset.DefaultAssetsRateLimit
 	}
+	assetManager.SignatureVerifier, err = asset.NewSignatureVerifier(config.AssetTrustedKeys, config.AssetSignaturePolicy)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing asset manager: %s", err)
+	}
 	assetGetter, err := assetManager.StartAssetManager(ctx, rate.NewLimiter(limit, b.Cfg.AssetsBurstLimit))
 	if err != nil {
 		return nil, fmt.Errorf("error initializing asset manager: %s", err)
@@ -245,22 +249,23 @@ func InitializeStore(ctx context.Context, db *pgxpool.Pool, config *Config) (*Ba
 
 	// Initialize GraphQL service
 	b.GraphQLService, err = graphql.NewService(graphql.ServiceConfig{
-		AssetClient:       api.NewAssetClient(b.Store, auth),
-		CheckClient:       api.NewCheckClient(b.Store, actions.NewCheckController(b.Store, nil), auth),
-		EntityClient:      api.NewEntityClient(b.Store, auth),
-		EventClient:       api.NewEventClient(b.Store.GetEventStore(), auth, bus),
-		EventFilterClient: api.NewEventFilterClient(b.Store, auth),
-		HandlerClient:     api.NewHandlerClient(b.Store, auth),
-		HealthController:  actions.HealthController{},
-		MutatorClient:     api.NewMutatorClient(b.Store, auth),
-		SilencedClient:    api.NewSilencedClient(b.Store.GetSilencesStore(), auth),
-		NamespaceClient:   api.NewNamespaceClient(b.Store, auth),
-		HookClient:        api.NewHookConfigClient(b.Store, auth),
-		UserClient:        api.NewUserClient(b.Store, auth),
-		RBACClient:        api.NewRBACClient(b.Store, auth),
-		VersionController: actions.NewVersionController("no version"),
-		MetricGatherer:    prometheus.DefaultGatherer,
-		GenericClient:     &api.GenericClient{Store: b.Store, Auth: auth},
+		AssetClient:        api.NewAssetClient(b.Store, auth),
+		CheckClient:        api.NewCheckClient(b.Store, actions.NewCheckController(b.Store, nil), auth),
+		EntityClient:       api.NewEntityClient(b.Store, auth),
+		EventClient:        api.NewEventClient(b.Store.GetEventStore(), auth, bus),
+		EventFilterClient:  api.NewEventFilterClient(b.Store, auth),
+		HandlerClient:      api.NewHandlerClient(b.Store, auth),
+		HealthController:   actions.HealthController{},
+		MutatorClient:      api.NewMutatorClient(b.Store, auth),
+		SilencedClient:     api.NewSilencedClient(b.Store.GetSilencesStore(), auth),
+		SubscriptionClient: api.NewSubscriptionClient(b.Store, bus, auth),
+		NamespaceClient:    api.NewNamespaceClient(b.Store, auth),
+		HookClient:         api.NewHookConfigClient(b.Store, auth),
+		UserClient:         api.NewUserClient(b.Store, auth),
+		RBACClient:         api.NewRBACClient(b.Store, auth),
+		VersionController:  actions.NewVersionController("no version"),
+		MetricGatherer:     prometheus.DefaultGatherer,
+		GenericClient:      &api.GenericClient{Store: b.Store, Auth: auth},
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error initializing graphql.Service: %s", err)
@@ -278,6 +283,7 @@ func InitializeStore(ctx context.Context, db *pgxpool.Pool, config *Config) (*Ba
 		Authenticator:  authenticator,
 		ClusterVersion: "no version",
 		GraphQLService: b.GraphQLService,
+		AllowedOrigins: config.APIAllowedOrigins,
 	}
 	newApi, err := apid.New(b.APIDConfig)
 	if err != nil {
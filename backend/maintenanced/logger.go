@@ -0,0 +1,7 @@
+package maintenanced
+
+import "github.com/sirupsen/logrus"
+
+var logger = logrus.WithFields(logrus.Fields{
+	"component": "maintenanced",
+})
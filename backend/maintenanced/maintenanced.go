@@ -0,0 +1,405 @@
+// Package maintenanced materializes the occurrences of the maintenance
+// windows as silences.
+package maintenanced
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+	maintenancev1 "github.com/sensu/sensu-go/api/maintenance/v1"
+	"github.com/sensu/sensu-go/backend/selector"
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultInterval is the default interval at which the silences of the
+	// maintenance windows are reconciled.
+	DefaultInterval = time.Minute
+
+	// DefaultLookahead is the default time before the start of an occurrence
+	// of a maintenance window at which its silences are created.
+	DefaultLookahead = time.Hour
+
+	// entitySubscriptionPrefix prefixes the name of an entity to form its
+	// entity subscription.
+	entitySubscriptionPrefix = "entity:"
+)
+
+// Config configures Maintenanced.
+type Config struct {
+	// Store is used to get the maintenance windows, entities and checks, and
+	// to manage the silences.
+	Store storev2.Interface
+
+	// Interval is the interval at which the silences are reconciled.
+	Interval time.Duration
+
+	// Lookahead is the time before the start of an occurrence at which its
+	// silences are created.
+	Lookahead time.Duration
+
+	// Executor ensures that a single backend reconciles the silences at a
+	// time. The silences are reconciled by every backend when nil.
+	Executor store.SynchronizedExecutor
+}
+
+// Maintenanced reconciles the silences of the maintenance windows. A silence
+// is created for each subscription and check targeted by a window, ahead of
+// its next occurrence, which begins and expires with the occurrence. The
+// silences of the windows are labelled as managed by them, and are deleted
+// once they are no longer desired.
+//
+// Since a subscription and a check can only be silenced once, silences which
+// are not managed by a window are never overwritten.
+//
+// Only the backend holding the maintenance mutex reconciles the silences, the
+// other backends wait to take over if it loses the mutex.
+type Maintenanced struct {
+	store     storev2.Interface
+	interval  time.Duration
+	lookahead time.Duration
+	executor  store.SynchronizedExecutor
+	now       func() time.Time
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	errChan chan error
+}
+
+// New creates a new Maintenanced.
+func New(c Config) (*Maintenanced, error) {
+	if c.Store == nil {
+		return nil, errors.New("maintenanced requires a store")
+	}
+	if c.Interval <= 0 {
+		c.Interval = DefaultInterval
+	}
+	if c.Lookahead <= 0 {
+		c.Lookahead = DefaultLookahead
+	}
+	m := &Maintenanced{
+		store:     c.Store,
+		interval:  c.Interval,
+		lookahead: c.Lookahead,
+		executor:  c.Executor,
+		now:       time.Now,
+		errChan:   make(chan error, 1),
+	}
+	m.ctx, m.cancel = context.WithCancel(context.Background())
+	return m, nil
+}
+
+// Start starts reconciling the silences of the maintenance windows.
+func (m *Maintenanced) Start() error {
+	m.wg.Add(1)
+	go m.run()
+	return nil
+}
+
+// Stop stops Maintenanced.
+func (m *Maintenanced) Stop() error {
+	m.cancel()
+	m.wg.Wait()
+	return nil
+}
+
+// Err returns a channel on which the errors of Maintenanced are sent.
+func (m *Maintenanced) Err() <-chan error {
+	return m.errChan
+}
+
+// Name returns the daemon name
+func (m *Maintenanced) Name() string {
+	return "maintenanced"
+}
+
+func (m *Maintenanced) run() {
+	defer m.wg.Done()
+	if m.executor == nil {
+		_ = m.reconcileLoop(m.ctx)
+		return
+	}
+	for m.ctx.Err() == nil {
+		// Execute returns once the mutex is lost, or the context is cancelled
+		if err := m.executor.Execute(m.ctx, store.MutexMaintenance, m.reconcileLoop); err != nil && m.ctx.Err() == nil {
+			logger.WithError(err).Error("lost the maintenance mutex")
+		}
+	}
+}
+
+// reconcileLoop reconciles the silences at every interval, until the context
+// is cancelled.
+func (m *Maintenanced) reconcileLoop(ctx context.Context) error {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		if err := m.reconcile(ctx); err != nil && ctx.Err() == nil {
+			logger.WithError(err).Error("couldn't reconcile the silences of the maintenance windows")
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// reconcile creates or updates the desired silences of the maintenance
+// windows, and deletes their other silences.
+func (m *Maintenanced) reconcile(ctx context.Context) error {
+	now := m.now()
+	wstore := storev2.Of[*maintenancev1.MaintenanceWindow](m.store)
+	windows, err := wstore.List(ctx, storev2.ID{}, nil)
+	if err != nil {
+		return fmt.Errorf("couldn't list maintenance windows: %s", err)
+	}
+	sstore := m.store.GetSilencesStore()
+	silences, err := sstore.GetSilences(ctx, "")
+	if err != nil {
+		return fmt.Errorf("couldn't list silences: %s", err)
+	}
+
+	targets := newTargetCache(m.store)
+	desired := map[silenceID]*corev2.Silenced{}
+	for _, window := range windows {
+		lager := logger.WithFields(windowFields(window))
+		occurrence, err := window.NextOccurrence(now)
+		if err != nil {
+			lager.WithError(err).Error("invalid maintenance window")
+			continue
+		}
+		if occurrence == nil || occurrence.Start.After(now.Add(m.lookahead)) {
+			continue
+		}
+		windowSilences, err := m.windowSilences(ctx, targets, window, occurrence)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			lager.WithError(err).Error("couldn't resolve the targets of the maintenance window")
+			continue
+		}
+		for _, silence := range windowSilences {
+			id := silenceID{Namespace: silence.Namespace, Name: silence.Name}
+			// The earliest occurrence wins when windows overlap
+			if other, ok := desired[id]; !ok || silence.Begin < other.Begin {
+				desired[id] = silence
+			}
+		}
+	}
+
+	existing := make(map[silenceID]*corev2.Silenced, len(silences))
+	stale := map[string][]string{}
+	for _, silence := range silences {
+		id := silenceID{Namespace: silence.Namespace, Name: silence.Name}
+		existing[id] = silence
+		if _, ok := desired[id]; !ok && isManaged(silence) {
+			stale[silence.Namespace] = append(stale[silence.Namespace], silence.Name)
+		}
+	}
+
+	for id, silence := range desired {
+		current, ok := existing[id]
+		if ok && !isManaged(current) {
+			logger.WithFields(logrus.Fields{
+				"namespace":          id.Namespace,
+				"silence":            id.Name,
+				"maintenance_window": silence.Labels[maintenancev1.MaintenanceWindowLabel],
+			}).Warn("silence exists and is not managed by a maintenance window, skipping")
+			continue
+		}
+		if ok && sameSilence(current, silence) {
+			continue
+		}
+		if err := sstore.UpdateSilence(ctx, silence); err != nil {
+			return fmt.Errorf("couldn't update silence %s/%s: %s", id.Namespace, id.Name, err)
+		}
+		logger.WithFields(logrus.Fields{
+			"namespace":          id.Namespace,
+			"silence":            id.Name,
+			"maintenance_window": silence.Labels[maintenancev1.MaintenanceWindowLabel],
+			"begin":              time.Unix(silence.Begin, 0),
+			"expire_at":          time.Unix(silence.ExpireAt, 0),
+		}).Info("scheduled maintenance window silence")
+	}
+
+	for namespace, names := range stale {
+		if err := sstore.DeleteSilences(ctx, namespace, names); err != nil {
+			return fmt.Errorf("couldn't delete silences in namespace %s: %s", namespace, err)
+		}
+		logger.WithFields(logrus.Fields{
+			"namespace": namespace,
+			"silences":  names,
+		}).Info("deleted maintenance window silences")
+	}
+	return nil
+}
+
+// windowSilences returns the silences of an occurrence of a window.
+func (m *Maintenanced) windowSilences(ctx context.Context, targets *targetCache, window *maintenancev1.MaintenanceWindow, occurrence *maintenancev1.Occurrence) ([]*corev2.Silenced, error) {
+	namespace := window.Metadata.Namespace
+	subscriptions := append([]string{}, window.Subscriptions...)
+	if window.EntitySelector != "" {
+		sel, err := selector.ParseFieldSelector(window.EntitySelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid entity selector: %s", err)
+		}
+		entities, err := targets.entities(ctx, namespace)
+		if err != nil {
+			return nil, err
+		}
+		for _, entity := range entities {
+			if sel.Matches(entityFields(entity)) {
+				subscriptions = append(subscriptions, entitySubscriptionPrefix+entity.Metadata.Name)
+			}
+		}
+	} else if len(subscriptions) == 0 {
+		// The checks are silenced on all the entities
+		subscriptions = []string{""}
+	}
+
+	checks := append([]string{}, window.Checks...)
+	if window.CheckSelector != "" {
+		sel, err := selector.ParseFieldSelector(window.CheckSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid check selector: %s", err)
+		}
+		configs, err := targets.checks(ctx, namespace)
+		if err != nil {
+			return nil, err
+		}
+		for _, check := range configs {
+			if sel.Matches(corev2.CheckConfigFields(check)) {
+				checks = append(checks, check.Name)
+			}
+		}
+	} else if len(checks) == 0 {
+		// All the checks of the subscriptions are silenced
+		checks = []string{""}
+	}
+
+	silences := make([]*corev2.Silenced, 0, len(subscriptions)*len(checks))
+	for _, subscription := range dedup(subscriptions) {
+		for _, check := range dedup(checks) {
+			name, err := corev2.SilencedName(subscription, check)
+			if err != nil {
+				continue
+			}
+			meta := corev2.NewObjectMeta(name, namespace)
+			meta.Labels = map[string]string{
+				corev2.ManagedByLabel:                maintenancev1.MaintenanceWindowManager,
+				maintenancev1.MaintenanceWindowLabel: window.Metadata.Name,
+			}
+			silences = append(silences, &corev2.Silenced{
+				ObjectMeta:   meta,
+				Subscription: subscription,
+				Check:        check,
+				Reason:       window.Reason,
+				Begin:        occurrence.Start.Unix(),
+				ExpireAt:     occurrence.End.Unix(),
+			})
+		}
+	}
+	return silences, nil
+}
+
+type silenceID struct {
+	Namespace string
+	Name      string
+}
+
+// targetCache lists the entities and checks of a namespace once per
+// reconciliation.
+type targetCache struct {
+	store        storev2.Interface
+	entityLists  map[string][]*corev3.EntityConfig
+	checkConfigs map[string][]*corev2.CheckConfig
+}
+
+func newTargetCache(store storev2.Interface) *targetCache {
+	return &targetCache{
+		store:        store,
+		entityLists:  map[string][]*corev3.EntityConfig{},
+		checkConfigs: map[string][]*corev2.CheckConfig{},
+	}
+}
+
+func (c *targetCache) entities(ctx context.Context, namespace string) ([]*corev3.EntityConfig, error) {
+	if entities, ok := c.entityLists[namespace]; ok {
+		return entities, nil
+	}
+	entities, err := storev2.Of[*corev3.EntityConfig](c.store).List(ctx, storev2.ID{Namespace: namespace}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't list entities: %s", err)
+	}
+	c.entityLists[namespace] = entities
+	return entities, nil
+}
+
+func (c *targetCache) checks(ctx context.Context, namespace string) ([]*corev2.CheckConfig, error) {
+	if checks, ok := c.checkConfigs[namespace]; ok {
+		return checks, nil
+	}
+	checks, err := storev2.Of[*corev2.CheckConfig](c.store).List(ctx, storev2.ID{Namespace: namespace}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't list checks: %s", err)
+	}
+	c.checkConfigs[namespace] = checks
+	return checks, nil
+}
+
+// entityFields returns the fields of an entity config, as named by the entity
+// field selectors.
+func entityFields(entity *corev3.EntityConfig) map[string]string {
+	fields := map[string]string{
+		"entity.name":          entity.Metadata.Name,
+		"entity.namespace":     entity.Metadata.Namespace,
+		"entity.deregister":    strconv.FormatBool(entity.Deregister),
+		"entity.entity_class":  entity.EntityClass,
+		"entity.subscriptions": strings.Join(entity.Subscriptions, ","),
+	}
+	corev3.MergeMapWithPrefix(fields, entity.Metadata.Labels, "entity.labels.")
+	return fields
+}
+
+func dedup(values []string) []string {
+	seen := make(map[string]struct{}, len(values))
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if _, ok := seen[value]; ok {
+			continue
+		}
+		seen[value] = struct{}{}
+		result = append(result, value)
+	}
+	return result
+}
+
+// isManaged returns whether a silence is managed by a maintenance window.
+func isManaged(silence *corev2.Silenced) bool {
+	return silence.Labels[corev2.ManagedByLabel] == maintenancev1.MaintenanceWindowManager
+}
+
+func sameSilence(a, b *corev2.Silenced) bool {
+	return a.Begin == b.Begin &&
+		a.ExpireAt == b.ExpireAt &&
+		a.Reason == b.Reason &&
+		a.Labels[maintenancev1.MaintenanceWindowLabel] == b.Labels[maintenancev1.MaintenanceWindowLabel]
+}
+
+func windowFields(window *maintenancev1.MaintenanceWindow) logrus.Fields {
+	return logrus.Fields{
+		"namespace":          window.Metadata.Namespace,
+		"maintenance_window": window.Metadata.Name,
+	}
+}
@@ -0,0 +1,201 @@
+package maintenanced
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+	maintenancev1 "github.com/sensu/sensu-go/api/maintenance/v1"
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fixture struct {
+	maintenanced *Maintenanced
+	updated      map[string]*corev2.Silenced
+	deleted      []string
+}
+
+func newFixture(t *testing.T, now time.Time, windows []*maintenancev1.MaintenanceWindow, silences []*corev2.Silenced) *fixture {
+	t.Helper()
+	f := &fixture{updated: map[string]*corev2.Silenced{}}
+
+	s := new(mockstore.V2MockStore)
+	cs := new(mockstore.ConfigStore)
+	es := new(mockstore.EntityConfigStore)
+	st := new(mockstore.MockStore)
+	s.On("GetConfigStore").Return(cs)
+	s.On("GetEntityConfigStore").Return(es)
+	s.On("GetSilencesStore").Return(st)
+
+	isType := func(typename string) interface{} {
+		return mock.MatchedBy(func(req storev2.ResourceRequest) bool { return req.Type == typename })
+	}
+	cs.On("List", mock.Anything, isType("MaintenanceWindow"), mock.Anything).
+		Return(mockstore.WrapList[*maintenancev1.MaintenanceWindow](windows), nil)
+	web := corev2.FixtureCheckConfig("web")
+	web.Labels = map[string]string{"tier": "web"}
+	db := corev2.FixtureCheckConfig("db")
+	cs.On("List", mock.Anything, isType("CheckConfig"), mock.Anything).
+		Return(mockstore.WrapList[*corev2.CheckConfig]{web, db}, nil)
+
+	entity1 := corev3.FixtureEntityConfig("entity1")
+	entity1.Metadata.Labels = map[string]string{"role": "db"}
+	entity2 := corev3.FixtureEntityConfig("entity2")
+	es.On("List", mock.Anything, "default", mock.Anything).
+		Return([]*corev3.EntityConfig{entity1, entity2}, nil)
+
+	st.On("GetSilences", mock.Anything, "").Return(silences, nil)
+	st.On("UpdateSilence", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		silence := args.Get(1).(*corev2.Silenced)
+		f.updated[silence.Name] = silence
+	}).Return(nil)
+	st.On("DeleteSilences", mock.Anything, "default", mock.Anything).Run(func(args mock.Arguments) {
+		f.deleted = append(f.deleted, args.Get(2).([]string)...)
+	}).Return(nil)
+
+	var err error
+	f.maintenanced, err = New(Config{Store: s})
+	require.NoError(t, err)
+	f.maintenanced.now = func() time.Time { return now }
+	return f
+}
+
+func weeklyWindow(name string) *maintenancev1.MaintenanceWindow {
+	window := maintenancev1.FixtureMaintenanceWindow(name)
+	window.Cron = "0 2 * * SUN"
+	window.Duration = 7200
+	return window
+}
+
+func managedSilence(name, window string, begin, expireAt int64) *corev2.Silenced {
+	silence := corev2.FixtureSilenced(name)
+	silence.Labels = map[string]string{
+		corev2.ManagedByLabel:                maintenancev1.MaintenanceWindowManager,
+		maintenancev1.MaintenanceWindowLabel: window,
+	}
+	silence.Reason = "weekly maintenance"
+	silence.Begin = begin
+	silence.ExpireAt = expireAt
+	return silence
+}
+
+// sunday is the start of an occurrence of the weekly windows.
+var sunday = time.Date(2024, 3, 17, 2, 0, 0, 0, time.UTC)
+
+func TestReconcileCreatesUpcomingSilences(t *testing.T) {
+	window := weeklyWindow("patching")
+	window.Checks = []string{"disk"}
+	f := newFixture(t, sunday.Add(-30*time.Minute), []*maintenancev1.MaintenanceWindow{window}, nil)
+
+	require.NoError(t, f.maintenanced.reconcile(context.Background()))
+	require.Len(t, f.updated, 1)
+	silence := f.updated["linux:disk"]
+	require.NotNil(t, silence)
+	assert.Equal(t, "default", silence.Namespace)
+	assert.Equal(t, sunday.Unix(), silence.Begin)
+	assert.Equal(t, sunday.Add(2*time.Hour).Unix(), silence.ExpireAt)
+	assert.Equal(t, "weekly maintenance", silence.Reason)
+	assert.Equal(t, "patching", silence.Labels[maintenancev1.MaintenanceWindowLabel])
+	assert.Equal(t, maintenancev1.MaintenanceWindowManager, silence.Labels[corev2.ManagedByLabel])
+}
+
+func TestReconcileSkipsDistantOccurrences(t *testing.T) {
+	window := weeklyWindow("patching")
+	f := newFixture(t, sunday.Add(-2*time.Hour), []*maintenancev1.MaintenanceWindow{window}, nil)
+
+	require.NoError(t, f.maintenanced.reconcile(context.Background()))
+	assert.Empty(t, f.updated)
+}
+
+func TestReconcileSelectors(t *testing.T) {
+	window := weeklyWindow("patching")
+	window.Subscriptions = nil
+	window.EntitySelector = "entity.labels.role == db"
+	window.CheckSelector = "check.labels.tier == web"
+	f := newFixture(t, sunday.Add(time.Hour), []*maintenancev1.MaintenanceWindow{window}, nil)
+
+	require.NoError(t, f.maintenanced.reconcile(context.Background()))
+	require.Len(t, f.updated, 1)
+	assert.NotNil(t, f.updated["entity:entity1:web"])
+}
+
+func TestReconcileChecksOnly(t *testing.T) {
+	window := weeklyWindow("patching")
+	window.Subscriptions = nil
+	window.Checks = []string{"disk"}
+	f := newFixture(t, sunday, []*maintenancev1.MaintenanceWindow{window}, nil)
+
+	require.NoError(t, f.maintenanced.reconcile(context.Background()))
+	require.Len(t, f.updated, 1)
+	assert.NotNil(t, f.updated["*:disk"])
+}
+
+func TestReconcileLeavesCurrentSilences(t *testing.T) {
+	window := weeklyWindow("patching")
+	current := managedSilence("linux:*", "patching", sunday.Unix(), sunday.Add(2*time.Hour).Unix())
+	f := newFixture(t, sunday, []*maintenancev1.MaintenanceWindow{window}, []*corev2.Silenced{current})
+
+	require.NoError(t, f.maintenanced.reconcile(context.Background()))
+	assert.Empty(t, f.updated)
+	assert.Empty(t, f.deleted)
+}
+
+func TestReconcileDeletesStaleSilences(t *testing.T) {
+	stale := managedSilence("linux:*", "deleted", sunday.Unix(), sunday.Add(2*time.Hour).Unix())
+	manual := corev2.FixtureSilenced("manual:*")
+	f := newFixture(t, sunday, nil, []*corev2.Silenced{stale, manual})
+
+	require.NoError(t, f.maintenanced.reconcile(context.Background()))
+	assert.Equal(t, []string{"linux:*"}, f.deleted)
+}
+
+func TestReconcileDoesNotOverwriteManualSilences(t *testing.T) {
+	window := weeklyWindow("patching")
+	manual := corev2.FixtureSilenced("linux:*")
+	f := newFixture(t, sunday, []*maintenancev1.MaintenanceWindow{window}, []*corev2.Silenced{manual})
+
+	require.NoError(t, f.maintenanced.reconcile(context.Background()))
+	assert.Empty(t, f.updated)
+	assert.Empty(t, f.deleted)
+}
+
+// testExecutor is a SynchronizedExecutor which acquires the mutexes at once.
+type testExecutor struct {
+	once     sync.Once
+	mutex    store.Mutex
+	acquired chan struct{}
+}
+
+func (e *testExecutor) Execute(ctx context.Context, mutex store.Mutex, handler store.MutexHandler) error {
+	e.once.Do(func() {
+		e.mutex = mutex
+		close(e.acquired)
+	})
+	return handler(ctx)
+}
+
+func TestMaintenancedHoldsMutex(t *testing.T) {
+	window := weeklyWindow("patching")
+	window.Checks = []string{"disk"}
+	f := newFixture(t, sunday.Add(-30*time.Minute), []*maintenancev1.MaintenanceWindow{window}, nil)
+	executor := &testExecutor{acquired: make(chan struct{})}
+	f.maintenanced.executor = executor
+
+	require.NoError(t, f.maintenanced.Start())
+	select {
+	case <-executor.acquired:
+	case <-time.After(10 * time.Second):
+		t.Fatal("mutex not acquired")
+	}
+	require.NoError(t, f.maintenanced.Stop())
+	assert.Equal(t, store.MutexMaintenance, executor.mutex)
+	assert.Len(t, f.updated, 1)
+}
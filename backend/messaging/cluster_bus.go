@@ -0,0 +1,407 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	ClusterBusMessagesSent     = "sensu_go_cluster_bus_messages_sent"
+	ClusterBusMessagesReceived = "sensu_go_cluster_bus_messages_received"
+	ClusterBusMessagesDropped  = "sensu_go_cluster_bus_messages_dropped"
+	ClusterBusReasonLabelName  = "reason"
+
+	// remoteTopicPrefix prefixes the local topics on which the messages
+	// received from the other backends are published.
+	remoteTopicPrefix = "sensu:cluster:"
+
+	// DefaultClusterBusBufferSize is the default number of messages waiting to
+	// be sent to the other backends.
+	DefaultClusterBusBufferSize = 1000
+
+	// DefaultClusterBusMaxMessageSize is the default maximum size, in bytes,
+	// of the messages sent to the other backends.
+	DefaultClusterBusMaxMessageSize = 1024 * 1024
+)
+
+var (
+	clusterBusSentCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: ClusterBusMessagesSent,
+			Help: "The total number of messages sent to the other backends by the cluster bus",
+		},
+		[]string{WizardBusTopicLabelName},
+	)
+
+	clusterBusReceivedCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: ClusterBusMessagesReceived,
+			Help: "The total number of messages received from the other backends by the cluster bus",
+		},
+		[]string{WizardBusTopicLabelName},
+	)
+
+	clusterBusDroppedCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: ClusterBusMessagesDropped,
+			Help: "The total number of messages dropped by the cluster bus",
+		},
+		[]string{WizardBusTopicLabelName, ClusterBusReasonLabelName},
+	)
+)
+
+func init() {
+	_ = prometheus.Register(clusterBusSentCounter)
+	_ = prometheus.Register(clusterBusReceivedCounter)
+	_ = prometheus.Register(clusterBusDroppedCounter)
+}
+
+// A ClusterTransport carries the messages of a ClusterBus between the
+// backends of a cluster.
+type ClusterTransport interface {
+	// Send sends a message to every backend of the cluster, including this
+	// one.
+	Send(ctx context.Context, message []byte) error
+
+	// Receive returns the messages sent by every backend of the cluster,
+	// until the context is cancelled.
+	Receive(ctx context.Context) (<-chan []byte, error)
+}
+
+// A ClusterTopic is a topic bridged across the backends of a cluster, along
+// with every topic it prefixes, e.g. TopicSubscriptions bridges the topics of
+// every subscription.
+type ClusterTopic struct {
+	// Topic is the bridged topic
+	Topic string
+
+	// Encode encodes the messages published on the topic. The messages are
+	// encoded to JSON when nil.
+	Encode func(interface{}) ([]byte, error)
+
+	// Decode decodes the messages received from the other backends into the
+	// type of the messages published on the topic.
+	Decode func([]byte) (interface{}, error)
+}
+
+// JSONDecoder returns a ClusterTopic decoder decoding JSON messages to T.
+func JSONDecoder[T any]() func([]byte) (interface{}, error) {
+	return func(b []byte) (interface{}, error) {
+		var msg T
+		err := json.Unmarshal(b, &msg)
+		return msg, err
+	}
+}
+
+// ClusterBusConfig configures a ClusterBus.
+type ClusterBusConfig struct {
+	// Transport carries the messages between the backends
+	Transport ClusterTransport
+
+	// Topics are the topics bridged across the backends
+	Topics []ClusterTopic
+
+	// LocalConsumers are the consumers which only receive the messages
+	// published by this backend, e.g. the consumers of TopicEvent which would
+	// otherwise handle the same events on every backend.
+	LocalConsumers []string
+
+	// BufferSize is the number of messages waiting to be sent to the other
+	// backends, and of those received from them waiting to be published on
+	// the local bus. Messages are dropped when a buffer is full.
+	BufferSize int
+
+	// MaxMessageSize is the maximum size, in bytes, of an encoded message.
+	// Larger messages are only published locally.
+	MaxMessageSize int
+}
+
+// clusterMessage is a message exchanged between the backends.
+type clusterMessage struct {
+	// Origin identifies the cluster bus which sent the message, so that it
+	// doesn't publish its own messages twice.
+	Origin  string          `json:"origin"`
+	Topic   string          `json:"topic"`
+	Message json.RawMessage `json:"message"`
+}
+
+type outgoingMessage struct {
+	topic   *ClusterTopic
+	name    string
+	message interface{}
+}
+
+type incomingMessage struct {
+	name    string
+	message interface{}
+	lager   *logrus.Entry
+}
+
+// ClusterBus is a MessageBus bridging selected topics across the backends of
+// a cluster. Messages are published on the local bus, and those published on
+// the bridged topics are also sent to the other backends, which publish them
+// on their own local bus. Messages received from the other backends are never
+// sent back to the cluster.
+//
+// The messages received from the other backends are published on separate
+// local topics, which are subscribed along with the bridged topics, except for
+// the local consumers.
+type ClusterBus struct {
+	MessageBus
+
+	id             string
+	transport      ClusterTransport
+	topics         []ClusterTopic
+	localConsumers map[string]struct{}
+	maxMessageSize int
+	outgoing       chan outgoingMessage
+	incoming       chan incomingMessage
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	errchan chan error
+}
+
+// NewClusterBus creates a new ClusterBus bridging the topics of the local bus.
+func NewClusterBus(local MessageBus, cfg ClusterBusConfig) (*ClusterBus, error) {
+	if cfg.Transport == nil {
+		return nil, errors.New("the cluster bus requires a transport")
+	}
+	for _, topic := range cfg.Topics {
+		if topic.Decode == nil {
+			return nil, errors.New("no decoder for cluster bus topic " + topic.Topic)
+		}
+	}
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = DefaultClusterBusBufferSize
+	}
+	if cfg.MaxMessageSize <= 0 {
+		cfg.MaxMessageSize = DefaultClusterBusMaxMessageSize
+	}
+	bus := &ClusterBus{
+		MessageBus:     local,
+		id:             uuid.New().String(),
+		transport:      cfg.Transport,
+		topics:         cfg.Topics,
+		localConsumers: make(map[string]struct{}, len(cfg.LocalConsumers)),
+		maxMessageSize: cfg.MaxMessageSize,
+		outgoing:       make(chan outgoingMessage, cfg.BufferSize),
+		incoming:       make(chan incomingMessage, cfg.BufferSize),
+		errchan:        make(chan error, 1),
+	}
+	for _, consumer := range cfg.LocalConsumers {
+		bus.localConsumers[consumer] = struct{}{}
+	}
+	bus.ctx, bus.cancel = context.WithCancel(context.Background())
+	return bus, nil
+}
+
+// Start starts the local bus, and the exchange of messages with the other
+// backends.
+func (b *ClusterBus) Start() error {
+	if err := b.MessageBus.Start(); err != nil {
+		return err
+	}
+	messages, err := b.transport.Receive(b.ctx)
+	if err != nil {
+		return err
+	}
+	b.wg.Add(3)
+	go b.sendLoop()
+	go b.receiveLoop(messages)
+	go b.publishLoop()
+	return nil
+}
+
+// Stop stops the exchange of messages with the other backends, and the local
+// bus.
+func (b *ClusterBus) Stop() error {
+	b.cancel()
+	b.wg.Wait()
+	return b.MessageBus.Stop()
+}
+
+// Err returns a channel on which the errors of the bus are sent.
+func (b *ClusterBus) Err() <-chan error {
+	return b.errchan
+}
+
+// Name returns the daemon name
+func (b *ClusterBus) Name() string {
+	return "cluster_bus"
+}
+
+// clusterTopic returns the bridged topic of a topic, if any.
+func (b *ClusterBus) clusterTopic(topic string) *ClusterTopic {
+	for i := range b.topics {
+		t := &b.topics[i]
+		if topic == t.Topic || strings.HasPrefix(topic, t.Topic+":") {
+			return t
+		}
+	}
+	return nil
+}
+
+// Subscribe subscribes to a topic. The subscribers of a bridged topic also
+// receive the messages published by the other backends, unless they are local
+// consumers.
+func (b *ClusterBus) Subscribe(topic string, consumer string, sub Subscriber) (Subscription, error) {
+	local, err := b.MessageBus.Subscribe(topic, consumer, sub)
+	if err != nil {
+		return local, err
+	}
+	if _, ok := b.localConsumers[consumer]; ok || b.clusterTopic(topic) == nil {
+		return local, nil
+	}
+	remote, err := b.MessageBus.Subscribe(remoteTopicPrefix+topic, consumer, sub)
+	if err != nil {
+		_ = local.Cancel()
+		return remote, err
+	}
+	return Subscription{
+		id: local.id,
+		cancel: func(string) error {
+			localErr := local.Cancel()
+			if err := remote.Cancel(); err != nil {
+				return err
+			}
+			return localErr
+		},
+	}, nil
+}
+
+// Publish publishes a message on the local bus, and sends the messages of the
+// bridged topics to the other backends.
+func (b *ClusterBus) Publish(topic string, message interface{}) error {
+	if err := b.MessageBus.Publish(topic, message); err != nil {
+		return err
+	}
+	if t := b.clusterTopic(topic); t != nil {
+		select {
+		case b.outgoing <- outgoingMessage{topic: t, name: topic, message: message}:
+		default:
+			clusterBusDroppedCounter.WithLabelValues(t.Topic, "buffer_full").Inc()
+		}
+	}
+	return nil
+}
+
+func (b *ClusterBus) sendLoop() {
+	defer b.wg.Done()
+	for {
+		select {
+		case <-b.ctx.Done():
+			return
+		case msg := <-b.outgoing:
+			b.send(msg)
+		}
+	}
+}
+
+func (b *ClusterBus) send(msg outgoingMessage) {
+	lager := logger.WithField("topic", msg.name)
+	encode := msg.topic.Encode
+	if encode == nil {
+		encode = json.Marshal
+	}
+	encoded, err := encode(msg.message)
+	if err != nil {
+		lager.WithError(err).Error("error encoding cluster bus message")
+		clusterBusDroppedCounter.WithLabelValues(msg.topic.Topic, "invalid").Inc()
+		return
+	}
+	payload, err := json.Marshal(clusterMessage{Origin: b.id, Topic: msg.name, Message: encoded})
+	if err != nil {
+		lager.WithError(err).Error("error encoding cluster bus message")
+		clusterBusDroppedCounter.WithLabelValues(msg.topic.Topic, "invalid").Inc()
+		return
+	}
+	if len(payload) > b.maxMessageSize {
+		lager.WithField("size", len(payload)).Warn("cluster bus message too large, only published locally")
+		clusterBusDroppedCounter.WithLabelValues(msg.topic.Topic, "too_large").Inc()
+		return
+	}
+	if err := b.transport.Send(b.ctx, payload); err != nil {
+		if b.ctx.Err() == nil {
+			lager.WithError(err).Error("error sending cluster bus message")
+			clusterBusDroppedCounter.WithLabelValues(msg.topic.Topic, "send_error").Inc()
+		}
+		return
+	}
+	clusterBusSentCounter.WithLabelValues(msg.topic.Topic).Inc()
+}
+
+func (b *ClusterBus) receiveLoop(messages <-chan []byte) {
+	defer b.wg.Done()
+	for {
+		select {
+		case <-b.ctx.Done():
+			return
+		case payload, ok := <-messages:
+			if !ok {
+				if b.ctx.Err() == nil {
+					b.errchan <- errors.New("cluster bus transport closed")
+				}
+				return
+			}
+			b.receive(payload)
+		}
+	}
+}
+
+func (b *ClusterBus) receive(payload []byte) {
+	var msg clusterMessage
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		logger.WithError(err).Error("error decoding cluster bus message")
+		return
+	}
+	if msg.Origin == b.id {
+		// The message was published by this backend
+		return
+	}
+	lager := logger.WithFields(logrus.Fields{"topic": msg.Topic, "origin": msg.Origin})
+	t := b.clusterTopic(msg.Topic)
+	if t == nil {
+		lager.Debug("ignoring cluster bus message of a topic that is not bridged")
+		return
+	}
+	message, err := t.Decode(msg.Message)
+	if err != nil {
+		lager.WithError(err).Error("error decoding cluster bus message")
+		clusterBusDroppedCounter.WithLabelValues(t.Topic, "invalid").Inc()
+		return
+	}
+	clusterBusReceivedCounter.WithLabelValues(t.Topic).Inc()
+	// The local bus blocks on slow subscribers, which must not hold up the
+	// transport
+	select {
+	case b.incoming <- incomingMessage{name: msg.Topic, message: message, lager: lager}:
+	default:
+		lager.Warn("cluster bus receive buffer full, dropping message")
+		clusterBusDroppedCounter.WithLabelValues(t.Topic, "buffer_full").Inc()
+	}
+}
+
+// publishLoop publishes the messages received from the other backends on the
+// local bus.
+func (b *ClusterBus) publishLoop() {
+	defer b.wg.Done()
+	for {
+		select {
+		case <-b.ctx.Done():
+			return
+		case msg := <-b.incoming:
+			if err := b.MessageBus.Publish(remoteTopicPrefix+msg.name, msg.message); err != nil {
+				msg.lager.WithError(err).Error("error publishing cluster bus message")
+			}
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryHub is an in-memory ClusterTransport, sending every message to the
+// transports of the hub.
+type memoryHub struct {
+	mu        sync.Mutex
+	receivers []chan []byte
+	sent      int
+}
+
+func (h *memoryHub) Send(ctx context.Context, message []byte) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.sent++
+	for _, ch := range h.receivers {
+		ch <- message
+	}
+	return nil
+}
+
+func (h *memoryHub) Receive(ctx context.Context) (<-chan []byte, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	ch := make(chan []byte, 100)
+	h.receivers = append(h.receivers, ch)
+	return ch, nil
+}
+
+func (h *memoryHub) sentCount() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.sent
+}
+
+type testNotification struct {
+	Name string
+}
+
+func newTestClusterBus(t *testing.T, hub *memoryHub, cfg ClusterBusConfig) *ClusterBus {
+	t.Helper()
+	local, err := NewWizardBus(WizardBusConfig{})
+	require.NoError(t, err)
+	cfg.Transport = hub
+	if cfg.Topics == nil {
+		cfg.Topics = []ClusterTopic{
+			{Topic: TopicEvent, Decode: JSONDecoder[*testNotification]()},
+			{Topic: TopicSubscriptions, Decode: JSONDecoder[testNotification]()},
+		}
+	}
+	bus, err := NewClusterBus(local, cfg)
+	require.NoError(t, err)
+	require.NoError(t, bus.Start())
+	t.Cleanup(func() { _ = bus.Stop() })
+	return bus
+}
+
+func receive(t *testing.T, ch chan interface{}) interface{} {
+	t.Helper()
+	select {
+	case msg := <-ch:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message received")
+		return nil
+	}
+}
+
+func assertNoMessage(t *testing.T, ch chan interface{}) {
+	t.Helper()
+	select {
+	case msg := <-ch:
+		t.Fatalf("unexpected message: %v", msg)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestClusterBus(t *testing.T) {
+	hub := &memoryHub{}
+	a := newTestClusterBus(t, hub, ClusterBusConfig{LocalConsumers: []string{"pipelined"}})
+	b := newTestClusterBus(t, hub, ClusterBusConfig{LocalConsumers: []string{"pipelined"}})
+
+	aEvents := make(ChanSubscriber, 10)
+	_, err := a.Subscribe(TopicEvent, "graphql", aEvents)
+	require.NoError(t, err)
+	bEvents := make(ChanSubscriber, 10)
+	_, err = b.Subscribe(TopicEvent, "graphql", bEvents)
+	require.NoError(t, err)
+	bPipelined := make(ChanSubscriber, 10)
+	_, err = b.Subscribe(TopicEvent, "pipelined", bPipelined)
+	require.NoError(t, err)
+	bKeepalives := make(ChanSubscriber, 10)
+	_, err = b.Subscribe(TopicKeepalive, "keepalived", bKeepalives)
+	require.NoError(t, err)
+
+	// The messages of a are received once by its subscribers and by the
+	// subscribers of b, except for the local consumers
+	require.NoError(t, a.Publish(TopicEvent, &testNotification{Name: "a"}))
+	assert.Equal(t, &testNotification{Name: "a"}, receive(t, aEvents))
+	assert.Equal(t, &testNotification{Name: "a"}, receive(t, bEvents))
+	assertNoMessage(t, aEvents)
+	assertNoMessage(t, bPipelined)
+
+	// The topics which are not bridged stay local
+	require.NoError(t, a.Publish(TopicKeepalive, &testNotification{Name: "a"}))
+	assertNoMessage(t, bKeepalives)
+
+	// The messages received from the other backends are not sent back
+	require.NoError(t, b.Publish(TopicEvent, &testNotification{Name: "b"}))
+	assert.Equal(t, &testNotification{Name: "b"}, receive(t, bPipelined))
+	assert.Equal(t, &testNotification{Name: "b"}, receive(t, bEvents))
+	assert.Equal(t, &testNotification{Name: "b"}, receive(t, aEvents))
+	assertNoMessage(t, aEvents)
+	assertNoMessage(t, bEvents)
+	assert.Equal(t, 2, hub.sentCount())
+}
+
+func TestClusterBusTopicPrefix(t *testing.T) {
+	hub := &memoryHub{}
+	a := newTestClusterBus(t, hub, ClusterBusConfig{})
+	b := newTestClusterBus(t, hub, ClusterBusConfig{})
+
+	requests := make(ChanSubscriber, 10)
+	subscription, err := b.Subscribe(SubscriptionTopic("default", "linux"), "agent", requests)
+	require.NoError(t, err)
+
+	require.NoError(t, a.Publish(SubscriptionTopic("default", "linux"), testNotification{Name: "check"}))
+	assert.Equal(t, testNotification{Name: "check"}, receive(t, requests))
+
+	// Cancelling the subscription also cancels the subscription to the
+	// messages of the other backends
+	require.NoError(t, subscription.Cancel())
+	require.NoError(t, a.Publish(SubscriptionTopic("default", "linux"), testNotification{Name: "check"}))
+	assertNoMessage(t, requests)
+}
+
+func TestClusterBusMaxMessageSize(t *testing.T) {
+	hub := &memoryHub{}
+	a := newTestClusterBus(t, hub, ClusterBusConfig{MaxMessageSize: 200})
+	b := newTestClusterBus(t, hub, ClusterBusConfig{})
+
+	events := make(ChanSubscriber, 10)
+	_, err := b.Subscribe(TopicEvent, "graphql", events)
+	require.NoError(t, err)
+
+	require.NoError(t, a.Publish(TopicEvent, &testNotification{Name: string(make([]byte, 100))}))
+	assertNoMessage(t, events)
+	require.NoError(t, a.Publish(TopicEvent, &testNotification{Name: "a"}))
+	assert.Equal(t, &testNotification{Name: "a"}, receive(t, events))
+}
+
+type failingTransport struct{}
+
+func (failingTransport) Send(context.Context, []byte) error { return nil }
+
+func (failingTransport) Receive(context.Context) (<-chan []byte, error) {
+	return nil, errors.New("error")
+}
+
+func TestNewClusterBus(t *testing.T) {
+	local, err := NewWizardBus(WizardBusConfig{})
+	require.NoError(t, err)
+
+	_, err = NewClusterBus(local, ClusterBusConfig{})
+	assert.Error(t, err)
+
+	_, err = NewClusterBus(local, ClusterBusConfig{
+		Transport: failingTransport{},
+		Topics:    []ClusterTopic{{Topic: TopicEvent}},
+	})
+	assert.Error(t, err)
+
+	bus, err := NewClusterBus(local, ClusterBusConfig{Transport: failingTransport{}})
+	require.NoError(t, err)
+	assert.Error(t, bus.Start())
+}
+
+func TestClusterBusSlowSubscriber(t *testing.T) {
+	hub := &memoryHub{}
+	a := newTestClusterBus(t, hub, ClusterBusConfig{})
+	b := newTestClusterBus(t, hub, ClusterBusConfig{BufferSize: 1})
+
+	// A subscriber which doesn't read its messages doesn't hold up the
+	// transport, the messages are dropped instead
+	slow := make(ChanSubscriber)
+	_, err := b.Subscribe(TopicEvent, "graphql", slow)
+	require.NoError(t, err)
+	for i := 0; i < 200; i++ {
+		require.NoError(t, a.Publish(TopicEvent, &testNotification{Name: "a"}))
+	}
+	assert.Eventually(t, func() bool {
+		return hub.sentCount() == 200
+	}, 10*time.Second, 10*time.Millisecond)
+
+	go func() {
+		for range slow {
+		}
+	}()
+}
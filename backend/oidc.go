@@ -0,0 +1,34 @@
+package backend
+
+import (
+	"context"
+	"time"
+
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+// oidcStatePruneInterval is the interval at which the expired OIDC sessions
+// and logins are deleted.
+const oidcStatePruneInterval = 10 * time.Minute
+
+// PruneOIDCStatesLoop periodically deletes the expired OIDC sessions and
+// logins, until ctx is cancelled.
+func PruneOIDCStatesLoop(ctx context.Context, states store.OIDCStateStore) {
+	ticker := time.NewTicker(oidcStatePruneInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			pruned, err := states.PruneOIDCStates(ctx, time.Now())
+			if err != nil {
+				logger.WithError(err).Error("error pruning expired oidc states")
+				continue
+			}
+			if pruned > 0 {
+				logger.WithField("oidc_states", pruned).Debug("pruned expired oidc states")
+			}
+		}
+	}
+}
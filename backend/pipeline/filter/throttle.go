@@ -0,0 +1,91 @@
+package filter
+
+import (
+	"context"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	pipelinev1 "github.com/sensu/sensu-go/api/pipeline/v1"
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	utillogging "github.com/sensu/sensu-go/util/logging"
+)
+
+const (
+	// ThrottleAdapterName is the name of the filter adapter.
+	ThrottleAdapterName = "ThrottleAdapter"
+)
+
+// ThrottleAdapter is a filter adapter that supports the
+// pipeline/v1.ThrottleFilter type. The occurrences of the events are counted
+// in the throttle store, so that they are throttled consistently across the
+// backends.
+type ThrottleAdapter struct {
+	Store        storev2.Interface
+	Throttles    store.ThrottleStore
+	StoreTimeout time.Duration
+}
+
+// Name returns the name of the filter adapter.
+func (t *ThrottleAdapter) Name() string {
+	return ThrottleAdapterName
+}
+
+// CanFilter determines whether ThrottleAdapter can filter the resource being
+// referenced.
+func (t *ThrottleAdapter) CanFilter(ref *corev2.ResourceReference) bool {
+	return ref.APIVersion == pipelinev1.APIVersion && ref.Type == pipelinev1.ThrottleFilterType
+}
+
+// Filter will evaluate the event and determine whether or not to filter it.
+// Passing events are never filtered, and reset the occurrences of their key.
+func (t *ThrottleAdapter) Filter(ctx context.Context, ref *corev2.ResourceReference, event *corev2.Event) (bool, error) {
+	// Prepare log entry
+	fields := utillogging.EventFields(event, false)
+	fields["pipeline"] = corev2.ContextPipeline(ctx)
+	fields["pipeline_workflow"] = corev2.ContextPipelineWorkflow(ctx)
+	fields["filter"] = ref.Name
+
+	tctx, cancel := context.WithTimeout(ctx, t.StoreTimeout)
+	defer cancel()
+
+	fstore := storev2.Of[*pipelinev1.ThrottleFilter](t.Store)
+	filter, err := fstore.Get(tctx, storev2.ID{Namespace: event.Entity.Namespace, Name: ref.Name})
+	if err != nil {
+		logger.WithFields(fields).WithError(err).Warning(errCouldNotRetrieveFilter.Error())
+		return false, err
+	}
+
+	key := store.ThrottleKey{
+		Namespace: event.Entity.Namespace,
+		Filter:    filter.Metadata.Name,
+		Key:       filter.EventKey(event),
+	}
+	fields["throttle_key"] = key.Key
+
+	if event.HasCheck() && event.Check.Status == 0 {
+		if err := t.Throttles.ResetOccurrences(tctx, key); err != nil {
+			logger.WithFields(fields).WithError(err).Error("couldn't reset throttle occurrences")
+			return false, err
+		}
+		logger.WithFields(fields).Debug("allowing passing event")
+		return false, nil
+	}
+
+	limits := store.ThrottleLimits{
+		Occurrences: filter.Occurrences,
+		Interval:    filter.IntervalDuration(),
+	}
+	allowed, err := t.Throttles.RecordOccurrence(tctx, key, limits, time.Now())
+	if err != nil {
+		logger.WithFields(fields).WithError(err).Error("couldn't record throttle occurrence")
+		return false, err
+	}
+	if !allowed {
+		logger.WithFields(fields).Debug("denying throttled event")
+		return true, nil
+	}
+
+	logger.WithFields(fields).Debug("allowing event")
+	return false, nil
+}
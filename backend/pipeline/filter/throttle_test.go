@@ -0,0 +1,110 @@
+package filter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	pipelinev1 "github.com/sensu/sensu-go/api/pipeline/v1"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryThrottleStore is an in-memory ThrottleStore.
+type memoryThrottleStore struct {
+	states map[store.ThrottleKey]*store.ThrottleState
+}
+
+func (m *memoryThrottleStore) RecordOccurrence(ctx context.Context, key store.ThrottleKey, limits store.ThrottleLimits, now time.Time) (bool, error) {
+	state, ok := m.states[key]
+	if !ok {
+		state = &store.ThrottleState{}
+		m.states[key] = state
+	}
+	return state.Record(limits, now), nil
+}
+
+func (m *memoryThrottleStore) ResetOccurrences(ctx context.Context, key store.ThrottleKey) error {
+	delete(m.states, key)
+	return nil
+}
+
+func (m *memoryThrottleStore) PruneThrottleStates(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+func newThrottleAdapter(t *testing.T, filter *pipelinev1.ThrottleFilter, err error) *ThrottleAdapter {
+	t.Helper()
+	stor := new(mockstore.V2MockStore)
+	cs := new(mockstore.ConfigStore)
+	stor.On("GetConfigStore").Return(cs)
+	if filter == nil {
+		cs.On("Get", mock.Anything, mock.Anything).Return(nil, err)
+	} else {
+		cs.On("Get", mock.Anything, mock.Anything).Return(mockstore.Wrapper[*pipelinev1.ThrottleFilter]{Value: filter}, err)
+	}
+	return &ThrottleAdapter{
+		Store:        stor,
+		Throttles:    &memoryThrottleStore{states: map[store.ThrottleKey]*store.ThrottleState{}},
+		StoreTimeout: time.Second,
+	}
+}
+
+func throttleFilterRef(name string) *corev2.ResourceReference {
+	return &corev2.ResourceReference{
+		APIVersion: pipelinev1.APIVersion,
+		Type:       pipelinev1.ThrottleFilterType,
+		Name:       name,
+	}
+}
+
+func TestThrottleAdapter_CanFilter(t *testing.T) {
+	adapter := &ThrottleAdapter{}
+	assert.True(t, adapter.CanFilter(throttleFilterRef("throttle")))
+	assert.False(t, adapter.CanFilter(&corev2.ResourceReference{APIVersion: "core/v2", Type: "EventFilter", Name: "is_incident"}))
+}
+
+func TestThrottleAdapter_Filter(t *testing.T) {
+	filter := pipelinev1.FixtureThrottleFilter("throttle")
+	filter.Occurrences = 2
+	adapter := newThrottleAdapter(t, filter, nil)
+	ctx := context.Background()
+	ref := throttleFilterRef("throttle")
+
+	failing := corev2.FixtureEvent("entity1", "check1")
+	failing.Check.Status = 2
+	for i, want := range []bool{false, false, true, true} {
+		filtered, err := adapter.Filter(ctx, ref, failing)
+		require.NoError(t, err)
+		assert.Equal(t, want, filtered, "occurrence %d", i+1)
+	}
+
+	// The events of other keys are counted separately
+	other := corev2.FixtureEvent("entity2", "check1")
+	other.Check.Status = 2
+	filtered, err := adapter.Filter(ctx, ref, other)
+	require.NoError(t, err)
+	assert.False(t, filtered)
+
+	// A passing event is allowed, and resets the occurrences of its key
+	passing := corev2.FixtureEvent("entity1", "check1")
+	filtered, err = adapter.Filter(ctx, ref, passing)
+	require.NoError(t, err)
+	assert.False(t, filtered)
+
+	filtered, err = adapter.Filter(ctx, ref, failing)
+	require.NoError(t, err)
+	assert.False(t, filtered)
+}
+
+func TestThrottleAdapter_FilterStoreError(t *testing.T) {
+	adapter := newThrottleAdapter(t, nil, errors.New("store error"))
+	event := corev2.FixtureEvent("entity1", "check1")
+	_, err := adapter.Filter(context.Background(), throttleFilterRef("throttle"), event)
+	assert.Error(t, err)
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	pipelinev1 "github.com/sensu/sensu-go/api/pipeline/v1"
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	utillogging "github.com/sensu/sensu-go/util/logging"
+)
+
+const (
+	// EscalationAdapterName is the name of the escalation policy handler
+	// adapter.
+	EscalationAdapterName = "EscalationAdapter"
+)
+
+// EscalationAdapter is a handler adapter that supports the
+// pipeline/v1.EscalationPolicy type. It opens an incident when a failing event
+// is handled, and closes the incidents of the event when it resolves. The steps
+// of the policy are run by escalationd.
+type EscalationAdapter struct {
+	Store        storev2.Interface
+	Incidents    store.EscalationStore
+	StoreTimeout time.Duration
+}
+
+// Name returns the name of the handler adapter.
+func (e *EscalationAdapter) Name() string {
+	return EscalationAdapterName
+}
+
+// CanHandle determines whether EscalationAdapter can handle the resource
+// being referenced.
+func (e *EscalationAdapter) CanHandle(ref *corev2.ResourceReference) bool {
+	return ref.APIVersion == pipelinev1.APIVersion && ref.Type == pipelinev1.EscalationPolicyType
+}
+
+// Handle handles a Sensu event by opening or closing the incident of the
+// referenced escalation policy.
+func (e *EscalationAdapter) Handle(ctx context.Context, ref *corev2.ResourceReference, event *corev2.Event, mutatedData []byte) error {
+	// Prepare log entry
+	fields := utillogging.EventFields(event, false)
+	fields["pipeline"] = corev2.ContextPipeline(ctx)
+	fields["pipeline_workflow"] = corev2.ContextPipelineWorkflow(ctx)
+	fields["escalation_policy"] = ref.Name
+
+	if !event.HasCheck() {
+		logger.WithFields(fields).Warn("escalation policies only handle check events")
+		return nil
+	}
+
+	tctx, cancel := context.WithTimeout(ctx, e.StoreTimeout)
+	defer cancel()
+
+	if event.Check.Status == 0 {
+		if err := e.Incidents.CloseIncidents(tctx, event.Entity.Namespace, event.Entity.Name, event.Check.Name); err != nil {
+			return fmt.Errorf("failed to close incidents: %v", err)
+		}
+		return nil
+	}
+
+	pstore := storev2.Of[*pipelinev1.EscalationPolicy](e.Store)
+	policy, err := pstore.Get(tctx, storev2.ID{Namespace: event.Entity.Namespace, Name: ref.Name})
+	if err != nil {
+		if _, ok := err.(*store.ErrNotFound); ok {
+			logger.WithFields(fields).
+				Error("escalation policy not found, skipping handler execution")
+			return nil
+		}
+		return fmt.Errorf("failed to fetch escalation policy from store: %v", err)
+	}
+
+	incident := &store.EscalationIncident{
+		Namespace: event.Entity.Namespace,
+		Entity:    event.Entity.Name,
+		Check:     event.Check.Name,
+		Policy:    policy.Metadata.Name,
+		DueAt:     time.Now().Add(policy.StepDelay(0)),
+	}
+	opened, err := e.Incidents.OpenIncident(tctx, incident)
+	if err != nil {
+		return fmt.Errorf("failed to open incident: %v", err)
+	}
+	if opened {
+		logger.WithFields(fields).Info("opened escalation incident")
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	pipelinev1 "github.com/sensu/sensu-go/api/pipeline/v1"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newEscalationAdapter(t *testing.T, policy *pipelinev1.EscalationPolicy, err error) (*EscalationAdapter, *mockstore.EscalationStore) {
+	t.Helper()
+	stor := new(mockstore.V2MockStore)
+	cs := new(mockstore.ConfigStore)
+	stor.On("GetConfigStore").Return(cs)
+	if policy == nil {
+		cs.On("Get", mock.Anything, mock.Anything).Return(nil, err)
+	} else {
+		cs.On("Get", mock.Anything, mock.Anything).Return(mockstore.Wrapper[*pipelinev1.EscalationPolicy]{Value: policy}, err)
+	}
+	incidents := new(mockstore.EscalationStore)
+	return &EscalationAdapter{
+		Store:        stor,
+		Incidents:    incidents,
+		StoreTimeout: time.Second,
+	}, incidents
+}
+
+func escalationPolicyRef(name string) *corev2.ResourceReference {
+	return &corev2.ResourceReference{
+		APIVersion: pipelinev1.APIVersion,
+		Type:       pipelinev1.EscalationPolicyType,
+		Name:       name,
+	}
+}
+
+func TestEscalationAdapter_CanHandle(t *testing.T) {
+	adapter := &EscalationAdapter{}
+	assert.True(t, adapter.CanHandle(escalationPolicyRef("on-call")))
+	assert.False(t, adapter.CanHandle(httpHandlerRef("webhook")))
+	assert.False(t, adapter.CanHandle(&corev2.ResourceReference{APIVersion: "core/v2", Type: "Handler"}))
+}
+
+func TestEscalationAdapter_HandleOpensIncident(t *testing.T) {
+	policy := pipelinev1.FixtureEscalationPolicy("on-call")
+	policy.Steps[0].Delay = 60
+	adapter, incidents := newEscalationAdapter(t, policy, nil)
+	event := corev2.FixtureEvent("entity1", "check1")
+	event.Check.Status = 2
+
+	var opened *store.EscalationIncident
+	incidents.On("OpenIncident", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		opened = args.Get(1).(*store.EscalationIncident)
+	}).Return(true, nil)
+
+	before := time.Now()
+	require.NoError(t, adapter.Handle(context.Background(), escalationPolicyRef("on-call"), event, nil))
+	require.NotNil(t, opened)
+	assert.Equal(t, "default", opened.Namespace)
+	assert.Equal(t, "entity1", opened.Entity)
+	assert.Equal(t, "check1", opened.Check)
+	assert.Equal(t, "on-call", opened.Policy)
+	assert.Equal(t, uint32(0), opened.Step)
+	assert.False(t, opened.DueAt.Before(before.Add(time.Minute)))
+}
+
+func TestEscalationAdapter_HandleClosesIncidents(t *testing.T) {
+	adapter, incidents := newEscalationAdapter(t, nil, nil)
+	event := corev2.FixtureEvent("entity1", "check1")
+	event.Check.Status = 0
+
+	incidents.On("CloseIncidents", mock.Anything, "default", "entity1", "check1").Return(nil)
+	require.NoError(t, adapter.Handle(context.Background(), escalationPolicyRef("on-call"), event, nil))
+	incidents.AssertExpectations(t)
+}
+
+func TestEscalationAdapter_HandleNotFound(t *testing.T) {
+	adapter, incidents := newEscalationAdapter(t, nil, &store.ErrNotFound{})
+	event := corev2.FixtureEvent("entity1", "check1")
+	event.Check.Status = 1
+	require.NoError(t, adapter.Handle(context.Background(), escalationPolicyRef("on-call"), event, nil))
+	incidents.AssertNotCalled(t, "OpenIncident", mock.Anything, mock.Anything)
+}
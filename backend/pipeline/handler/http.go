@@ -0,0 +1,258 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	pipelinev1 "github.com/sensu/sensu-go/api/pipeline/v1"
+	"github.com/sensu/sensu-go/backend/secrets"
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	utillogging "github.com/sensu/sensu-go/util/logging"
+	"github.com/sensu/sensu-go/util/retry"
+)
+
+const (
+	// HTTPAdapterName is the name of the HTTP handler adapter.
+	HTTPAdapterName = "HTTPAdapter"
+
+	// maxResponseBodyLogSize is the maximum number of bytes of a response
+	// body that are logged when a request fails.
+	maxResponseBodyLogSize = 1024
+)
+
+// HTTPAdapter is a handler adapter that supports the pipeline/v1.HTTPHandler
+// type. Its execution latency is reported by the pipeline adapter, like any
+// other handler adapter.
+type HTTPAdapter struct {
+	SecretsProviderManager secrets.ProviderManagerer
+	Store                  storev2.Interface
+	StoreTimeout           time.Duration
+
+	// Transport is the base transport used for requests. When nil,
+	// http.DefaultTransport is used. TLS options of the handler are applied
+	// on a clone of it.
+	Transport *http.Transport
+}
+
+// httpTemplateData is the data that URL, header and body templates of an
+// HTTPHandler are evaluated with.
+type httpTemplateData struct {
+	Event   *corev2.Event
+	Data    string
+	Secrets map[string]string
+}
+
+// errRetryable wraps errors that should cause a request to be retried.
+type errRetryable struct {
+	err error
+}
+
+func (e errRetryable) Error() string {
+	return e.err.Error()
+}
+
+// Name returns the name of the handler adapter.
+func (h *HTTPAdapter) Name() string {
+	return HTTPAdapterName
+}
+
+// CanHandle determines whether HTTPAdapter can handle the resource being
+// referenced.
+func (h *HTTPAdapter) CanHandle(ref *corev2.ResourceReference) bool {
+	return ref.APIVersion == pipelinev1.APIVersion && ref.Type == pipelinev1.HTTPHandlerType
+}
+
+// Handle handles a Sensu event by sending the mutated data to the HTTP
+// endpoint configured in the referenced handler.
+func (h *HTTPAdapter) Handle(ctx context.Context, ref *corev2.ResourceReference, event *corev2.Event, mutatedData []byte) error {
+	// Prepare log entry
+	fields := utillogging.EventFields(event, false)
+	fields["pipeline"] = corev2.ContextPipeline(ctx)
+	fields["pipeline_workflow"] = corev2.ContextPipelineWorkflow(ctx)
+	fields["handler"] = ref.Name
+
+	tctx, cancel := context.WithTimeout(ctx, h.StoreTimeout)
+	hstore := storev2.Of[*pipelinev1.HTTPHandler](h.Store)
+	handler, err := hstore.Get(tctx, storev2.ID{Namespace: event.Entity.Namespace, Name: ref.Name})
+	cancel()
+	if err != nil {
+		if _, ok := err.(*store.ErrNotFound); ok {
+			logger.WithFields(fields).
+				Error("handler not found, skipping handler execution")
+			return nil
+		}
+		return fmt.Errorf("failed to fetch handler from store: %v", err)
+	}
+
+	ctx = context.WithValue(ctx, corev2.NamespaceKey, handler.Metadata.Namespace)
+
+	data := httpTemplateData{
+		Event:   event,
+		Data:    string(mutatedData),
+		Secrets: map[string]string{},
+	}
+	if h.SecretsProviderManager != nil && len(handler.Secrets) > 0 {
+		substituted, err := h.SecretsProviderManager.SubSecrets(ctx, handler.Secrets)
+		if err != nil {
+			logger.WithFields(fields).WithError(err).Error("failed to retrieve secrets for handler")
+			return err
+		}
+		for _, kv := range substituted {
+			if i := strings.Index(kv, "="); i > 0 {
+				data.Secrets[kv[:i]] = kv[i+1:]
+			}
+		}
+	}
+
+	client, err := h.client(handler)
+	if err != nil {
+		logger.WithFields(fields).WithError(err).Error("failed to configure http handler client")
+		return err
+	}
+
+	policy := handler.Retry
+	backoff := retry.ExponentialBackoff{
+		Ctx:              ctx,
+		MaxRetryAttempts: int(policy.Attempts()),
+	}
+	if policy != nil {
+		backoff.InitialDelayInterval = policy.InitialBackoffDuration()
+		backoff.MaxDelayInterval = policy.MaxBackoffDuration()
+	}
+
+	var lastErr error
+	err = backoff.Retry(func(attempt int) (bool, error) {
+		fields["attempt"] = attempt + 1
+		status, err := h.send(ctx, client, handler, data)
+		if err == nil {
+			fields["status"] = status
+			logger.WithFields(fields).Info("event http handler executed")
+			return true, nil
+		}
+		lastErr = err
+		if _, ok := err.(errRetryable); ok {
+			logger.WithFields(fields).WithError(err).Warn("event http handler failed, retrying")
+			return false, nil
+		}
+		return false, err
+	})
+	if err == retry.ErrMaxRetryAttempts {
+		err = lastErr
+	}
+	if err != nil {
+		logger.WithFields(fields).WithError(err).Error("failed to execute event http handler")
+		return err
+	}
+
+	return nil
+}
+
+// client returns an HTTP client configured with the timeout and TLS options
+// of the handler.
+func (h *HTTPAdapter) client(handler *pipelinev1.HTTPHandler) (*http.Client, error) {
+	timeout := handler.Timeout
+	if timeout == 0 {
+		timeout = pipelinev1.DefaultHTTPHandlerTimeout
+	}
+
+	base := h.Transport
+	if base == nil {
+		base = http.DefaultTransport.(*http.Transport)
+	}
+	transport := base.Clone()
+	if handler.TLS != nil {
+		var tlsConfig *tls.Config
+		var err error
+		if tlsConfig, err = handler.TLS.ToClientTLSConfig(); err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   time.Duration(timeout) * time.Second,
+	}, nil
+}
+
+// send makes a single request to the handler endpoint and returns the
+// response status code.
+func (h *HTTPAdapter) send(ctx context.Context, client *http.Client, handler *pipelinev1.HTTPHandler, data httpTemplateData) (int, error) {
+	url, err := render("url", handler.URL, data)
+	if err != nil {
+		return 0, err
+	}
+
+	body := []byte(data.Data)
+	if handler.Body != "" {
+		rendered, err := render("body", handler.Body, data)
+		if err != nil {
+			return 0, err
+		}
+		body = []byte(rendered)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, handler.HTTPMethod(), url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range handler.Headers {
+		rendered, err := render("header "+name, value, data)
+		if err != nil {
+			return 0, err
+		}
+		req.Header.Set(name, rendered)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, err
+		}
+		return 0, errRetryable{err: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		return resp.StatusCode, nil
+	}
+
+	respBody, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxResponseBodyLogSize))
+	err = fmt.Errorf("unexpected response status %d: %s", resp.StatusCode, bytes.TrimSpace(respBody))
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return resp.StatusCode, errRetryable{err: err}
+	}
+	return resp.StatusCode, err
+}
+
+// render evaluates a handler template with the provided data.
+func render(name, text string, data httpTemplateData) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	tmpl, err := template.New(name).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s template: %s", name, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("error evaluating %s template: %s", name, err)
+	}
+	if buf.Len() == 0 && name == "url" {
+		return "", errors.New("url template evaluated to an empty string")
+	}
+	return buf.String(), nil
+}
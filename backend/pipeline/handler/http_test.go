@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	pipelinev1 "github.com/sensu/sensu-go/api/pipeline/v1"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/sensu/sensu-go/testing/mocksecrets"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newHTTPAdapter(t *testing.T, handler *pipelinev1.HTTPHandler, err error) *HTTPAdapter {
+	t.Helper()
+	stor := new(mockstore.V2MockStore)
+	cs := new(mockstore.ConfigStore)
+	stor.On("GetConfigStore").Return(cs)
+	if handler == nil {
+		cs.On("Get", mock.Anything, mock.Anything).Return(nil, err)
+	} else {
+		cs.On("Get", mock.Anything, mock.Anything).Return(mockstore.Wrapper[*pipelinev1.HTTPHandler]{Value: handler}, err)
+	}
+	return &HTTPAdapter{
+		Store:        stor,
+		StoreTimeout: time.Second,
+	}
+}
+
+func httpHandlerRef(name string) *corev2.ResourceReference {
+	return &corev2.ResourceReference{
+		APIVersion: pipelinev1.APIVersion,
+		Type:       pipelinev1.HTTPHandlerType,
+		Name:       name,
+	}
+}
+
+func TestHTTPAdapter_CanHandle(t *testing.T) {
+	adapter := &HTTPAdapter{}
+	assert.True(t, adapter.CanHandle(httpHandlerRef("webhook")))
+	assert.False(t, adapter.CanHandle(&corev2.ResourceReference{APIVersion: "core/v2", Type: "Handler"}))
+	assert.False(t, adapter.CanHandle(&corev2.ResourceReference{APIVersion: pipelinev1.APIVersion, Type: "Handler"}))
+}
+
+func TestHTTPAdapter_Name(t *testing.T) {
+	if got, want := (&HTTPAdapter{}).Name(), "HTTPAdapter"; got != want {
+		t.Errorf("HTTPAdapter.Name() = %v, want %v", got, want)
+	}
+}
+
+func TestHTTPAdapter_HandleNotFound(t *testing.T) {
+	adapter := newHTTPAdapter(t, nil, &store.ErrNotFound{})
+	event := corev2.FixtureEvent("entity1", "check1")
+	require.NoError(t, adapter.Handle(context.Background(), httpHandlerRef("webhook"), event, nil))
+}
+
+func TestHTTPAdapter_HandleStoreError(t *testing.T) {
+	adapter := newHTTPAdapter(t, nil, errors.New("store error"))
+	event := corev2.FixtureEvent("entity1", "check1")
+	require.Error(t, adapter.Handle(context.Background(), httpHandlerRef("webhook"), event, nil))
+}
+
+func TestHTTPAdapter_Handle(t *testing.T) {
+	var (
+		gotMethod string
+		gotBody   string
+		gotHeader string
+		gotPath   string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotPath = r.URL.Path
+		gotHeader = r.Header.Get("Authorization")
+		b, _ := ioutil.ReadAll(r.Body)
+		gotBody = string(b)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	handler := pipelinev1.FixtureHTTPHandler("webhook")
+	handler.URL = server.URL + "/hooks/{{ .Event.Entity.Name }}"
+	handler.Method = "put"
+	handler.Headers = map[string]string{"Authorization": "Bearer {{ .Secrets.TOKEN }}"}
+	handler.Body = `{"check":"{{ .Event.Check.Name }}","data":{{ .Data }}}`
+	handler.Secrets = []*corev2.Secret{{Name: "TOKEN", Secret: "slack-token"}}
+
+	adapter := newHTTPAdapter(t, handler, nil)
+	manager := new(mocksecrets.ProviderManager)
+	manager.On("SubSecrets", mock.Anything, handler.Secrets).Return([]string{"TOKEN=s3cr3t"}, nil)
+	adapter.SecretsProviderManager = manager
+
+	event := corev2.FixtureEvent("entity1", "check1")
+	err := adapter.Handle(context.Background(), httpHandlerRef("webhook"), event, []byte(`{"a":1}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.MethodPut, gotMethod)
+	assert.Equal(t, "/hooks/entity1", gotPath)
+	assert.Equal(t, "Bearer s3cr3t", gotHeader)
+	assert.Equal(t, `{"check":"check1","data":{"a":1}}`, gotBody)
+}
+
+func TestHTTPAdapter_HandleDefaultBody(t *testing.T) {
+	var gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		gotBody = string(b)
+	}))
+	defer server.Close()
+
+	handler := pipelinev1.FixtureHTTPHandler("webhook")
+	handler.URL = server.URL
+
+	adapter := newHTTPAdapter(t, handler, nil)
+	event := corev2.FixtureEvent("entity1", "check1")
+	require.NoError(t, adapter.Handle(context.Background(), httpHandlerRef("webhook"), event, []byte("mutated")))
+	assert.Equal(t, "mutated", gotBody)
+}
+
+func TestHTTPAdapter_HandleSecretsError(t *testing.T) {
+	handler := pipelinev1.FixtureHTTPHandler("webhook")
+	handler.Secrets = []*corev2.Secret{{Name: "TOKEN", Secret: "slack-token"}}
+
+	adapter := newHTTPAdapter(t, handler, nil)
+	manager := new(mocksecrets.ProviderManager)
+	manager.On("SubSecrets", mock.Anything, mock.Anything).Return([]string{}, errors.New("secrets error"))
+	adapter.SecretsProviderManager = manager
+
+	event := corev2.FixtureEvent("entity1", "check1")
+	require.Error(t, adapter.Handle(context.Background(), httpHandlerRef("webhook"), event, nil))
+}
+
+func TestHTTPAdapter_HandleRetry(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		maxAttempts  uint32
+		wantErr      bool
+		wantRequests int32
+	}{
+		{
+			name:         "retries server errors until success",
+			statuses:     []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK},
+			maxAttempts:  3,
+			wantRequests: 3,
+		},
+		{
+			name:         "gives up after max attempts",
+			statuses:     []int{http.StatusInternalServerError, http.StatusInternalServerError},
+			maxAttempts:  2,
+			wantErr:      true,
+			wantRequests: 2,
+		},
+		{
+			name:         "does not retry client errors",
+			statuses:     []int{http.StatusBadRequest, http.StatusOK},
+			maxAttempts:  3,
+			wantErr:      true,
+			wantRequests: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				i := atomic.AddInt32(&requests, 1) - 1
+				w.WriteHeader(tt.statuses[i])
+			}))
+			defer server.Close()
+
+			handler := pipelinev1.FixtureHTTPHandler("webhook")
+			handler.URL = server.URL
+			handler.Retry = &pipelinev1.HTTPRetryPolicy{
+				MaxAttempts:    tt.maxAttempts,
+				InitialBackoff: "1ms",
+				MaxBackoff:     "5ms",
+			}
+
+			adapter := newHTTPAdapter(t, handler, nil)
+			event := corev2.FixtureEvent("entity1", "check1")
+			err := adapter.Handle(context.Background(), httpHandlerRef("webhook"), event, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("HTTPAdapter.Handle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.wantRequests, atomic.LoadInt32(&requests))
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package backend
+
+import (
+	"context"
+
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+// providerSet is a set of providers by name, such as the authentication
+// providers of an authenticator, or the secrets providers of a provider
+// manager.
+type providerSet[P any] interface {
+	AddProvider(P)
+	Providers() map[string]P
+	RemoveProvider(name string) error
+}
+
+// providerKind is a kind of providers kept in sync with their configurations
+// in the store.
+type providerKind[P any] struct {
+	// name names the providers in the logs, e.g. "secrets"
+	name string
+
+	// set is the set of the providers
+	set providerSet[P]
+
+	// typeOf returns the type of a provider, or of the configuration of a
+	// provider, so that providers of different types can share a name.
+	typeOf func(interface{}) string
+}
+
+// watchProviders adds the providers configured in the store to the set of
+// the kind, and keeps them in sync with the store until ctx is cancelled.
+func watchProviders[R storev2.Resource[T], T any, P any](ctx context.Context, s storev2.Interface, kind providerKind[P], newProvider func(R) P) {
+	pstore := storev2.Of[R](s)
+
+	// Start watching before listing, so no change is missed
+	watch := pstore.Watch(ctx, storev2.ID{})
+
+	providers, err := pstore.List(ctx, storev2.ID{}, nil)
+	if err != nil {
+		logger.WithError(err).Errorf("could not list the %s providers", kind.name)
+	}
+	for _, provider := range providers {
+		kind.set.AddProvider(newProvider(provider))
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case events, ok := <-watch:
+			if !ok {
+				return
+			}
+			for _, event := range events {
+				handleProviderEvent(kind, event, newProvider)
+			}
+		}
+	}
+}
+
+func handleProviderEvent[R storev2.Resource[T], T any, P any](kind providerKind[P], event storev2.GenericEvent[R], newProvider func(R) P) {
+	if event.Err != nil {
+		logger.WithError(event.Err).Errorf("error watching the %s providers", kind.name)
+		return
+	}
+	switch event.Type {
+	case storev2.WatchCreate, storev2.WatchUpdate:
+		if event.Value == nil || event.Value.GetMetadata() == nil {
+			return
+		}
+		name := event.Value.GetMetadata().Name
+		provider := newProvider(event.Value)
+		if existing, ok := kind.set.Providers()[name]; ok && kind.typeOf(existing) != kind.typeOf(provider) {
+			logger.WithField("provider", name).Warnf(
+				"%s provider replaces a %s provider with the same name", kind.name, kind.typeOf(existing),
+			)
+		}
+		kind.set.AddProvider(provider)
+		logger.WithField("provider", name).Infof("%s provider configured", kind.name)
+	case storev2.WatchDelete:
+		if existing, ok := kind.set.Providers()[event.Key.Name]; ok && kind.typeOf(existing) != kind.typeOf(R(new(T))) {
+			// The provider was replaced by one of another type with the same name
+			return
+		}
+		if err := kind.set.RemoveProvider(event.Key.Name); err != nil {
+			logger.WithError(err).Warnf("could not remove %s provider", kind.name)
+			return
+		}
+		logger.WithField("provider", event.Key.Name).Infof("%s provider removed", kind.name)
+	}
+}
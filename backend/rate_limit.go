@@ -0,0 +1,54 @@
+package backend
+
+import (
+	"context"
+	"time"
+
+	"github.com/sensu/sensu-go/backend/ratelimit"
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+// rateLimitPruneInterval is the interval at which the idle rate limit
+// buckets are deleted from the store.
+const rateLimitPruneInterval = 10 * time.Minute
+
+// rateLimitBucketIdleTime is how long a rate limit bucket is kept in the
+// store after it was last used. It is long enough to refill the buckets of
+// the limits of at least 1 request per minute.
+const rateLimitBucketIdleTime = time.Hour
+
+// PruneRateLimitLoop periodically deletes the rate limit buckets that were
+// not used recently, until ctx is cancelled.
+func PruneRateLimitLoop(ctx context.Context, s store.RateLimitStore) {
+	ticker := time.NewTicker(rateLimitPruneInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			pruned, err := s.PruneRateLimitBuckets(ctx, time.Now().Add(-rateLimitBucketIdleTime))
+			if err != nil {
+				logger.WithError(err).Error("error pruning rate limit buckets")
+				continue
+			}
+			if pruned > 0 {
+				logger.WithField("buckets", pruned).Debug("pruned rate limit buckets")
+			}
+		}
+	}
+}
+
+// newRateLimiter returns the limiter of the API rate limits, which keeps the
+// buckets in the store if they are shared by the backends, or nil if there
+// are no limits.
+func newRateLimiter(ctx context.Context, s store.RateLimitStore, config *Config) ratelimit.Limiter {
+	if len(config.APIRateLimits) == 0 && len(config.APIIPRateLimits) == 0 {
+		return nil
+	}
+	if config.APIRateLimitCluster {
+		go PruneRateLimitLoop(ctx, s)
+		return ratelimit.StoreLimiter{Store: s}
+	}
+	return ratelimit.NewLocalLimiter()
+}
@@ -0,0 +1,149 @@
+// Package ratelimit limits the rate of the requests of the API clients with
+// token buckets, kept either in memory or in the store.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sensu/sensu-go/backend/store"
+	"golang.org/x/time/rate"
+)
+
+// sweepInterval is the interval at which the idle buckets of a LocalLimiter
+// are deleted.
+const sweepInterval = time.Minute
+
+// Limit is the limit of a token bucket: Rate tokens are added per second, up
+// to Burst tokens.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// String returns the limit in the format read by ParseLimit.
+func (l Limit) String() string {
+	return fmt.Sprintf("%s:%d", strconv.FormatFloat(l.Rate, 'f', -1, 64), l.Burst)
+}
+
+// ParseLimit parses a limit in the RATE[:BURST] format, e.g. 10:20. The burst
+// defaults to the rate, rounded up.
+func ParseLimit(s string) (Limit, error) {
+	var limit Limit
+	rateValue, burstValue, hasBurst := strings.Cut(s, ":")
+	r, err := strconv.ParseFloat(rateValue, 64)
+	if err != nil || r <= 0 || math.IsInf(r, 0) {
+		return limit, fmt.Errorf("invalid rate limit %q: the rate must be a positive number", s)
+	}
+	limit.Rate = r
+	limit.Burst = int(math.Ceil(r))
+	if hasBurst {
+		burst, err := strconv.Atoi(burstValue)
+		if err != nil || burst <= 0 {
+			return limit, fmt.Errorf("invalid rate limit %q: the burst must be a positive integer", s)
+		}
+		limit.Burst = burst
+	}
+	return limit, nil
+}
+
+// ParseLimits parses the limits of the route groups, given as RATE[:BURST]
+// values keyed by route group, e.g. core/v2=10:20.
+func ParseLimits(values map[string]string) (map[string]Limit, error) {
+	limits := make(map[string]Limit, len(values))
+	for group, value := range values {
+		limit, err := ParseLimit(value)
+		if err != nil {
+			return nil, fmt.Errorf("route group %s: %s", group, err)
+		}
+		limits[group] = limit
+	}
+	return limits, nil
+}
+
+// Limiter takes tokens from the buckets of the clients.
+type Limiter interface {
+	// Take takes a token from the bucket of key, which is refilled according
+	// to limit. It returns zero if a token was taken, or the delay after
+	// which one will be.
+	Take(ctx context.Context, key string, limit Limit) (time.Duration, error)
+}
+
+type localBucket struct {
+	limiter  *rate.Limiter
+	lastUsed time.Time
+}
+
+// LocalLimiter keeps the token buckets in memory, so that the limits apply
+// to each backend separately.
+type LocalLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*localBucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewLocalLimiter returns a new LocalLimiter.
+func NewLocalLimiter() *LocalLimiter {
+	return &LocalLimiter{
+		buckets: make(map[string]*localBucket),
+		now:     time.Now,
+	}
+}
+
+// Take takes a token from the bucket of key.
+func (l *LocalLimiter) Take(_ context.Context, key string, limit Limit) (time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Sub(l.lastSweep) >= sweepInterval {
+		l.sweep(now)
+	}
+
+	bucket, ok := l.buckets[key]
+	if !ok || bucket.limiter.Limit() != rate.Limit(limit.Rate) || bucket.limiter.Burst() != limit.Burst {
+		bucket = &localBucket{limiter: rate.NewLimiter(rate.Limit(limit.Rate), limit.Burst)}
+		l.buckets[key] = bucket
+	}
+	bucket.lastUsed = now
+
+	reservation := bucket.limiter.ReserveN(now, 1)
+	if !reservation.OK() {
+		return time.Duration(math.MaxInt64), nil
+	}
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return delay, nil
+	}
+	return 0, nil
+}
+
+// sweep deletes the buckets that have been refilled since they were last
+// used, which are the same as new buckets.
+func (l *LocalLimiter) sweep(now time.Time) {
+	for key, bucket := range l.buckets {
+		limiter := bucket.limiter
+		refill := time.Duration(float64(limiter.Burst()) / float64(limiter.Limit()) * float64(time.Second))
+		if now.Sub(bucket.lastUsed) > refill {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+// StoreLimiter keeps the token buckets in a store, so that the limits are
+// shared by the backends of a cluster.
+type StoreLimiter struct {
+	Store store.RateLimitStore
+}
+
+// Take takes a token from the bucket of key.
+func (l StoreLimiter) Take(ctx context.Context, key string, limit Limit) (time.Duration, error) {
+	return l.Store.TakeRateLimitToken(ctx, key, limit.Rate, limit.Burst)
+}
@@ -0,0 +1,116 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Limit
+		wantErr bool
+	}{
+		{value: "10", want: Limit{Rate: 10, Burst: 10}},
+		{value: "10:20", want: Limit{Rate: 10, Burst: 20}},
+		{value: "0.5", want: Limit{Rate: 0.5, Burst: 1}},
+		{value: "0.5:3", want: Limit{Rate: 0.5, Burst: 3}},
+		{value: "", wantErr: true},
+		{value: "0", wantErr: true},
+		{value: "-1", wantErr: true},
+		{value: "ten", wantErr: true},
+		{value: "10:0", wantErr: true},
+		{value: "10:1.5", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseLimit(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseLimits(t *testing.T) {
+	limits, err := ParseLimits(map[string]string{"core/v2": "10:20", "graphql": "5"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Limit{
+		"core/v2": {Rate: 10, Burst: 20},
+		"graphql": {Rate: 5, Burst: 5},
+	}, limits)
+
+	_, err = ParseLimits(map[string]string{"core/v3": "fast"})
+	assert.Error(t, err)
+}
+
+func TestLimitString(t *testing.T) {
+	limit := Limit{Rate: 0.5, Burst: 3}
+	parsed, err := ParseLimit(limit.String())
+	require.NoError(t, err)
+	assert.Equal(t, limit, parsed)
+}
+
+func TestLocalLimiter(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	limiter := NewLocalLimiter()
+	limiter.now = func() time.Time { return now }
+	limit := Limit{Rate: 2, Burst: 3}
+	ctx := context.Background()
+
+	// The burst is available at once
+	for i := 0; i < 3; i++ {
+		delay, err := limiter.Take(ctx, "user:alice", limit)
+		require.NoError(t, err)
+		assert.Zero(t, delay)
+	}
+
+	// The bucket is empty, a token is added every 500ms
+	delay, err := limiter.Take(ctx, "user:alice", limit)
+	require.NoError(t, err)
+	assert.Equal(t, 500*time.Millisecond, delay)
+
+	// A rejected request does not consume a token
+	delay, err = limiter.Take(ctx, "user:alice", limit)
+	require.NoError(t, err)
+	assert.Equal(t, 500*time.Millisecond, delay)
+
+	// The buckets of the clients are independent
+	delay, err = limiter.Take(ctx, "user:bob", limit)
+	require.NoError(t, err)
+	assert.Zero(t, delay)
+
+	now = now.Add(500 * time.Millisecond)
+	delay, err = limiter.Take(ctx, "user:alice", limit)
+	require.NoError(t, err)
+	assert.Zero(t, delay)
+}
+
+func TestLocalLimiterSweep(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	limiter := NewLocalLimiter()
+	limiter.now = func() time.Time { return now }
+	limit := Limit{Rate: 1, Burst: 1}
+	ctx := context.Background()
+
+	_, err := limiter.Take(ctx, "ip:10.0.0.1", limit)
+	require.NoError(t, err)
+	now = now.Add(sweepInterval / 2)
+	_, err = limiter.Take(ctx, "ip:10.0.0.2", limit)
+	require.NoError(t, err)
+	assert.Len(t, limiter.buckets, 2)
+
+	// Both buckets were refilled after 1s, they are deleted by the next sweep
+	now = now.Add(sweepInterval / 2)
+	_, err = limiter.Take(ctx, "ip:10.0.0.3", limit)
+	require.NoError(t, err)
+	assert.Len(t, limiter.buckets, 1)
+	assert.Contains(t, limiter.buckets, "ip:10.0.0.3")
+}
@@ -10,21 +10,37 @@ import (
 )
 
 func NewAdhocScheduler(ctx context.Context, queue queue.Client, executor *CheckExecutor) *AdhocScheduler {
-	ctx, cancel := context.WithCancel(ctx)
 	executor.force = true
+	return newQueueScheduler(ctx, queue, adhocQueueName, "adhoc", executor)
+}
+
+// newScheduledRequestScheduler returns a scheduler executing the check
+// requests scheduled by the backends owning the checks, when the checks are
+// sharded between the backends.
+func newScheduledRequestScheduler(ctx context.Context, queue queue.Client, executor *CheckExecutor) *AdhocScheduler {
+	return newQueueScheduler(ctx, queue, scheduledQueueName, "scheduled", executor)
+}
+
+func newQueueScheduler(ctx context.Context, queue queue.Client, queueName, kind string, executor *CheckExecutor) *AdhocScheduler {
+	ctx, cancel := context.WithCancel(ctx)
 	return &AdhocScheduler{
-		queue:    queue,
-		executor: executor,
-		ctx:      ctx,
-		cancel:   cancel,
+		queue:     queue,
+		queueName: queueName,
+		kind:      kind,
+		executor:  executor,
+		ctx:       ctx,
+		cancel:    cancel,
 	}
 }
 
+// AdhocScheduler executes the checks received on a queue.
 type AdhocScheduler struct {
-	queue    queue.Client
-	executor *CheckExecutor
-	ctx      context.Context
-	cancel   context.CancelFunc
+	queue     queue.Client
+	queueName string
+	kind      string
+	executor  *CheckExecutor
+	ctx       context.Context
+	cancel    context.CancelFunc
 }
 
 func (a *AdhocScheduler) Start() {
@@ -35,22 +51,22 @@ func (a *AdhocScheduler) schedule() {
 	ctx := a.ctx
 	defer a.cancel()
 	for {
-		res, err := a.queue.Reserve(ctx, adhocQueueName)
+		res, err := a.queue.Reserve(ctx, a.queueName)
 		if err != nil {
 			if err == ctx.Err() {
 				return
 			}
-			logger.WithError(err).Error("unexpected error reserving adhoc check")
+			logger.WithError(err).Errorf("unexpected error reserving %s check", a.kind)
 			continue
 		}
 		item := res.Item()
 		var check corev2.CheckConfig
 		if err := json.Unmarshal(item.Value, &check); err != nil {
-			logger.WithError(err).WithField("value", string(item.Value)).Error("error unmarshaling adhoc check")
+			logger.WithError(err).WithField("value", string(item.Value)).Errorf("error unmarshaling %s check", a.kind)
 			if ackErr := res.Ack(ctx); ackErr != nil {
 				logger.WithError(ackErr).
 					WithField("item_id", item.ID).
-					Error("error acknowleding invalid adhoc check. potential poison record")
+					Errorf("error acknowleding invalid %s check. potential poison record", a.kind)
 			}
 			continue
 		}
@@ -59,14 +75,14 @@ func (a *AdhocScheduler) schedule() {
 			"check_namespace": check.Namespace,
 			"check_name":      check.Name,
 		}
-		logger.WithFields(logFields).Debug("attempting to schedule ad hoc check")
+		logger.WithFields(logFields).Debugf("attempting to schedule %s check", a.kind)
 
-		if err := a.executor.processCheck(ctx, &check); err != nil {
-			logger.WithError(err).WithFields(logFields).Error("error processing adhoc check request")
+		if err := a.executor.processCheck(corev2.SetContextFromResource(ctx, &check), &check); err != nil {
+			logger.WithError(err).WithFields(logFields).Errorf("error processing %s check request", a.kind)
 			if nackErr := res.Nack(ctx); nackErr != nil {
 				logger.WithError(nackErr).
 					WithFields(logFields).
-					Error("error returning unprocessed adhoc check to queue")
+					Errorf("error returning unprocessed %s check to queue", a.kind)
 			}
 			continue
 		}
@@ -74,9 +90,9 @@ func (a *AdhocScheduler) schedule() {
 		if err := res.Ack(ctx); err != nil {
 			logger.WithError(err).
 				WithFields(logFields).
-				Error("error acknowledging processed adhoc check. potential double delivery")
+				Errorf("error acknowledging processed %s check. potential double delivery", a.kind)
 		}
-		logger.WithFields(logFields).Debug("sucesfully scheduled ad hoc check")
+		logger.WithFields(logFields).Debugf("sucesfully scheduled %s check", a.kind)
 	}
 }
 
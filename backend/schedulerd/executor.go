@@ -2,6 +2,7 @@ package schedulerd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -11,6 +12,7 @@ import (
 	corev2 "github.com/sensu/core/v2"
 	corev3 "github.com/sensu/core/v3"
 	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/sensu/sensu-go/backend/queue"
 	"github.com/sensu/sensu-go/backend/secrets"
 	"github.com/sensu/sensu-go/backend/store"
 	storev2 "github.com/sensu/sensu-go/backend/store/v2"
@@ -24,6 +26,17 @@ type CheckExecutor struct {
 	entityCache            EntityCache
 	secretsProviderManager *secrets.ProviderManager
 	force                  bool
+
+	// dispatch is the queue on which the checks are sent to every backend,
+	// instead of being executed, when the checks are sharded between the
+	// backends.
+	dispatch queue.Client
+
+	// bridged is set when the bus bridges the check requests across the
+	// backends. The checks are then published on the bus by this backend
+	// only, rather than dispatched, except the checks with secrets, so that
+	// the resolved secrets never leave the backend.
+	bridged bool
 }
 
 // NewCheckExecutor creates a new check executor
@@ -34,9 +47,30 @@ func NewCheckExecutor(bus messaging.MessageBus, store storev2.Interface, cache E
 // ProcessCheck processes a check by publishing its proxy requests (if any)
 // and publishing the check itself
 func (c *CheckExecutor) processCheck(ctx context.Context, check *corev2.CheckConfig) error {
+	if c.dispatch != nil && !(c.bridged && len(check.Secrets) == 0) {
+		return c.dispatchCheck(ctx, check)
+	}
 	return processCheck(ctx, c, check)
 }
 
+// dispatchCheck sends a check to every backend, which execute it for their
+// agents. The clustered queue enqueues a copy of the request for each backend,
+// so every execution costs as many queue rows as there are backends.
+func (c *CheckExecutor) dispatchCheck(ctx context.Context, check *corev2.CheckConfig) error {
+	value, err := json.Marshal(check)
+	if err != nil {
+		return fmt.Errorf("error marshaling check: %s", err)
+	}
+	item := queue.Item{
+		Queue: scheduledQueueName,
+		Value: value,
+	}
+	if err := c.dispatch.Enqueue(ctx, item); err != nil {
+		return fmt.Errorf("error dispatching check request: %s", err)
+	}
+	return nil
+}
+
 func (c *CheckExecutor) getEntities(ctx context.Context) ([]EntityCacheValue, error) {
 	return c.entityCache.Get(store.NewNamespaceFromContext(ctx)), nil
 }
@@ -7,11 +7,14 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	corev2 "github.com/sensu/core/v2"
 	corev3 "github.com/sensu/core/v3"
 	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/sensu/sensu-go/backend/queue"
+	"github.com/sensu/sensu-go/testing/mockqueue"
 )
 
 func TestPublishProxyCheckRequest(t *testing.T) {
@@ -248,3 +251,19 @@ func TestCheckBuildRequestCron(t *testing.T) {
 
 	assert.NoError(scheduler.msgBus.Stop())
 }
+
+func TestBridgedExecutorDispatchesSecrets(t *testing.T) {
+	// The checks with secrets are still sent to every backend when the
+	// check requests are bridged, so that the resolved secrets never leave
+	// the backend
+	mockQ := &mockqueue.MockQueue{}
+	mockQ.On("Enqueue", mock.Anything, mock.Anything).Return(nil)
+	executor := &CheckExecutor{dispatch: mockQ, bridged: true}
+
+	check := corev2.FixtureCheckConfig("check")
+	check.Secrets = []*corev2.Secret{{Name: "token", Secret: "sensu-token"}}
+	require.NoError(t, executor.processCheck(context.Background(), check))
+	mockQ.AssertCalled(t, "Enqueue", mock.Anything, mock.MatchedBy(func(item queue.Item) bool {
+		return item.Queue == scheduledQueueName
+	}))
+}
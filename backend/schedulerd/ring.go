@@ -0,0 +1,100 @@
+package schedulerd
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+)
+
+// ringReplicas is the number of points of each backend on the hash ring. More
+// points spread the checks more evenly between the backends.
+const ringReplicas = 128
+
+// hashRing is a consistent hash ring of backends. A key is owned by the
+// backend of the first point of the ring following the hash of the key. When
+// a backend joins or leaves the ring, only the keys of the arcs it takes over
+// or releases change owners.
+type hashRing struct {
+	backends []string
+	points   []ringPoint
+}
+
+type ringPoint struct {
+	hash    uint64
+	backend string
+}
+
+func newHashRing(backends []string) *hashRing {
+	ring := &hashRing{
+		backends: make([]string, 0, len(backends)),
+		points:   make([]ringPoint, 0, len(backends)*ringReplicas),
+	}
+	seen := make(map[string]struct{}, len(backends))
+	for _, backend := range backends {
+		if _, ok := seen[backend]; ok {
+			continue
+		}
+		seen[backend] = struct{}{}
+		ring.backends = append(ring.backends, backend)
+		for i := 0; i < ringReplicas; i++ {
+			ring.points = append(ring.points, ringPoint{
+				hash:    ringHash(backend + "#" + strconv.Itoa(i)),
+				backend: backend,
+			})
+		}
+	}
+	sort.Strings(ring.backends)
+	sort.Slice(ring.points, func(i, j int) bool {
+		if ring.points[i].hash == ring.points[j].hash {
+			return ring.points[i].backend < ring.points[j].backend
+		}
+		return ring.points[i].hash < ring.points[j].hash
+	})
+	return ring
+}
+
+// owner returns the backend owning key, or an empty string if the ring is
+// empty.
+func (r *hashRing) owner(key string) string {
+	if r == nil || len(r.points) == 0 {
+		return ""
+	}
+	hash := ringHash(key)
+	i := sort.Search(len(r.points), func(i int) bool {
+		return r.points[i].hash >= hash
+	})
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.points[i].backend
+}
+
+// equal returns true if both rings have the same backends.
+func (r *hashRing) equal(other *hashRing) bool {
+	if r == nil || other == nil {
+		return r == other
+	}
+	if len(r.backends) != len(other.backends) {
+		return false
+	}
+	for i := range r.backends {
+		if r.backends[i] != other.backends[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ringHash hashes a key with FNV-1a, followed by the splitmix64 finalizer so
+// that similar keys are spread over the whole ring.
+func ringHash(key string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	x := h.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
@@ -0,0 +1,52 @@
+package schedulerd
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashRingOwner(t *testing.T) {
+	assert.Equal(t, "", newHashRing(nil).owner("default/check"))
+	assert.Equal(t, "a", newHashRing([]string{"a"}).owner("default/check"))
+
+	// The owners don't depend on the order of the backends
+	ring := newHashRing([]string{"a", "b", "c"})
+	other := newHashRing([]string{"c", "a", "b", "a"})
+	assert.True(t, ring.equal(other))
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("default/check%d", i)
+		assert.Equal(t, ring.owner(key), other.owner(key))
+	}
+}
+
+func TestHashRingBalance(t *testing.T) {
+	backends := []string{"backend-0", "backend-1", "backend-2", "backend-3"}
+	ring := newHashRing(backends)
+	counts := make(map[string]int)
+	const keys = 10000
+	for i := 0; i < keys; i++ {
+		counts[ring.owner(fmt.Sprintf("default/check%d", i))]++
+	}
+	for _, backend := range backends {
+		// Each backend owns roughly a quarter of the keys
+		assert.InDelta(t, keys/len(backends), counts[backend], keys/10, backend)
+	}
+}
+
+func TestHashRingRebalance(t *testing.T) {
+	before := newHashRing([]string{"a", "b", "c"})
+	after := newHashRing([]string{"a", "b", "c", "d"})
+	assert.False(t, before.equal(after))
+	moved := 0
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("default/check%d", i)
+		if owner := after.owner(key); owner != before.owner(key) {
+			// Keys only move to the backend joining the ring
+			assert.Equal(t, "d", owner)
+			moved++
+		}
+	}
+	assert.InDelta(t, 250, moved, 100)
+}
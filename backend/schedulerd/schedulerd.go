@@ -12,6 +12,7 @@ import (
 	"github.com/sensu/sensu-go/backend/messaging"
 	"github.com/sensu/sensu-go/backend/queue"
 	"github.com/sensu/sensu-go/backend/secrets"
+	"github.com/sensu/sensu-go/backend/store"
 	cachev2 "github.com/sensu/sensu-go/backend/store/cache/v2"
 	storev2 "github.com/sensu/sensu-go/backend/store/v2"
 	"github.com/sirupsen/logrus"
@@ -55,10 +56,16 @@ type Schedulerd struct {
 	entityCache            EntityCache
 	secretsProviderManager *secrets.ProviderManager
 	queue                  queue.Client
+	localBus               messaging.MessageBus
 
 	checks         namespacedChecks
 	schedulers     map[string]Scheduler
 	adhocScheduler *AdhocScheduler
+
+	// shard and scheduledScheduler are only set when the checks are sharded
+	// between the backends
+	shard              *shard
+	scheduledScheduler *AdhocScheduler
 }
 
 // Config configures Schedulerd.
@@ -68,6 +75,23 @@ type Config struct {
 	SecretsProviderManager *secrets.ProviderManager
 	RefreshInterval        time.Duration
 	Queue                  queue.Client
+
+	// BackendName and OperatorQueryer enable the sharding of the checks
+	// between the backends present in the operator registry, so that each
+	// check is scheduled by a single backend. The check requests are sent to
+	// every backend with Queue, which must then be a clustered queue. Note
+	// that a clustered queue writes one row per backend for every request, so
+	// a cluster of N backends writes N rows per scheduled check execution.
+	BackendName     string
+	OperatorQueryer store.OperatorQueryer
+
+	// LocalBus is the bus of this backend only, set when Bus bridges the
+	// check requests across the backends. The sharded checks are then
+	// published on Bus by the backend scheduling them instead of being sent
+	// to every backend with Queue, and the requests every backend receives
+	// from Queue are published on LocalBus, so that the agents receive each
+	// request once.
+	LocalBus messaging.MessageBus
 }
 
 // New creates a new Schedulerd.
@@ -79,6 +103,7 @@ func New(ctx context.Context, c Config) (*Schedulerd, error) {
 		errChan:                make(chan error, 1),
 		secretsProviderManager: c.SecretsProviderManager,
 		queue:                  c.Queue,
+		localBus:               c.LocalBus,
 
 		checks:     make(namespacedChecks),
 		schedulers: make(map[string]Scheduler),
@@ -86,6 +111,11 @@ func New(ctx context.Context, c Config) (*Schedulerd, error) {
 	if s.refreshInterval <= 0 {
 		s.refreshInterval = time.Second * 5
 	}
+	if c.Queue != nil && c.OperatorQueryer != nil && c.BackendName != "" {
+		// Give the other backends two refreshes to notice a change in the set
+		// of backends before taking over their checks
+		s.shard = newShard(c.BackendName, c.OperatorQueryer, 2*s.refreshInterval)
+	}
 	s.ctx, s.cancel = context.WithCancel(ctx)
 	cache, err := cachev2.New[*corev3.EntityConfig](s.ctx, c.Store, true)
 	if err != nil {
@@ -106,8 +136,12 @@ func (s *Schedulerd) Start() error {
 
 // start initializes schedulerd and begins polling for scheduling state changes
 func (s *Schedulerd) start() error {
-	s.adhocScheduler = NewAdhocScheduler(s.ctx, s.queue, s.makeExecutor())
+	s.adhocScheduler = NewAdhocScheduler(s.ctx, s.queue, s.makeQueueExecutor())
 	s.adhocScheduler.Start()
+	if s.shard != nil {
+		s.scheduledScheduler = newScheduledRequestScheduler(s.ctx, s.queue, s.makeQueueExecutor())
+		s.scheduledScheduler.Start()
+	}
 	if err := s.refresh(); err != nil {
 		return err
 	}
@@ -137,6 +171,12 @@ func (s *Schedulerd) refresh() error {
 	if err != nil {
 		return err
 	}
+	if s.shard != nil {
+		if err := s.shard.update(s.ctx); err != nil {
+			return fmt.Errorf("error updating the scheduling backends: %s", err)
+		}
+		next = s.shard.filter(next)
+	}
 	added, changed, removed := s.checks.Update(next)
 
 	checksAdded := make([]string, len(added))
@@ -215,9 +255,9 @@ func (s *Schedulerd) makeScheduler(check *corev2.CheckConfig) Scheduler {
 
 	switch GetSchedulerType(check) {
 	case IntervalType:
-		scheduler = NewIntervalScheduler(s.ctx, check, s.makeExecutor())
+		scheduler = NewIntervalScheduler(s.ctx, check, s.makeSchedulerExecutor())
 	case CronType:
-		scheduler = NewCronScheduler(s.ctx, check, s.makeExecutor())
+		scheduler = NewCronScheduler(s.ctx, check, s.makeSchedulerExecutor())
 	case RoundRobinIntervalType:
 		scheduler = NewNoopScheduler(RoundRobinIntervalType)
 		logger.WithFields(logrus.Fields{"namespace": check.Namespace, "check": check.Name}).
@@ -228,7 +268,7 @@ func (s *Schedulerd) makeScheduler(check *corev2.CheckConfig) Scheduler {
 		scheduler = NewNoopScheduler(RoundRobinCronType)
 	default:
 		logger.Error("bad scheduler type, falling back to interval scheduler")
-		scheduler = NewIntervalScheduler(s.ctx, check, s.makeExecutor())
+		scheduler = NewIntervalScheduler(s.ctx, check, s.makeSchedulerExecutor())
 	}
 	return scheduler
 }
@@ -237,11 +277,36 @@ func (s *Schedulerd) makeExecutor() *CheckExecutor {
 	return NewCheckExecutor(s.bus, s.store, s.entityCache, s.secretsProviderManager)
 }
 
+// makeSchedulerExecutor returns the executor of the check schedulers, which
+// sends the checks to every backend when the checks are sharded.
+func (s *Schedulerd) makeSchedulerExecutor() *CheckExecutor {
+	executor := s.makeExecutor()
+	if s.shard != nil {
+		executor.dispatch = s.queue
+		executor.bridged = s.localBus != nil
+	}
+	return executor
+}
+
+// makeQueueExecutor returns the executor of the requests received from the
+// queue, which every backend receives when the queue is clustered. They are
+// published on the local bus when the bus bridges the check requests.
+func (s *Schedulerd) makeQueueExecutor() *CheckExecutor {
+	executor := s.makeExecutor()
+	if s.localBus != nil {
+		executor.bus = s.localBus
+	}
+	return executor
+}
+
 // Stop the scheduler daemon.
 func (s *Schedulerd) Stop() error {
 	s.cancel()
 	close(s.errChan)
 	s.adhocScheduler.Stop()
+	if s.scheduledScheduler != nil {
+		s.scheduledScheduler.Stop()
+	}
 	return nil
 }
 
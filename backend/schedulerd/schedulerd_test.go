@@ -167,3 +167,150 @@ func stubStoreForCheck(check *corev2.CheckConfig) storev2.Interface {
 	stor.On("GetEntityConfigStore").Return(es)
 	return stor
 }
+
+func TestSchedulerdSharded(t *testing.T) {
+	intervalCheck := corev2.FixtureCheckConfig("interval")
+	intervalCheck.Subscriptions = append(intervalCheck.Subscriptions, "disco")
+	intervalCheck.Cron, intervalCheck.RoundRobin = "", false
+	intervalCheck.Interval = 1
+	intervalCheckB, _ := json.Marshal(intervalCheck)
+
+	stor := stubStoreForCheck(intervalCheck)
+
+	opc := &mockstore.OPC{}
+	opc.On("ListOperators", mock.Anything, mock.Anything).Return(backendOperators("backend"), nil)
+
+	// The owner of the check sends it to every backend, which then executes
+	// it for its agents
+	enqueued := make(chan queue.Item, 100)
+	received := make(chan time.Time)
+	never := make(chan time.Time)
+	mockQRes := &mockqueue.MockReservation{}
+	mockQRes.On("Item").Return(queue.Item{ID: "aaa", Queue: scheduledQueueName, Value: intervalCheckB})
+	mockQRes.On("Ack", mock.Anything).Return(nil)
+	mockQ := &mockqueue.MockQueue{}
+	mockQ.On("Reserve", mock.Anything, adhocQueueName).WaitUntil(never)
+	mockQ.On("Reserve", mock.Anything, scheduledQueueName).
+		WaitUntil(received).
+		Return(mockQRes, nil)
+	mockQ.On("Enqueue", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		select {
+		case enqueued <- args.Get(1).(queue.Item):
+		default:
+		}
+	})
+
+	bus, err := messaging.NewWizardBus(messaging.WizardBusConfig{})
+	require.NoError(t, err)
+	require.NoError(t, bus.Start())
+
+	discoC := make(chan interface{}, 10)
+	discoSub, err := bus.Subscribe(messaging.SubscriptionTopic("default", "disco"), "testing", testSubscriber{ch: discoC})
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, discoSub.Cancel())
+	}()
+
+	sched, err := New(context.Background(), Config{
+		Store:                  stor,
+		Bus:                    bus,
+		SecretsProviderManager: secrets.NewProviderManager(&mockEventReceiver{}),
+		Queue:                  mockQ,
+		BackendName:            "backend",
+		OperatorQueryer:        opc,
+	})
+	require.NoError(t, err)
+	require.NoError(t, sched.Start())
+	defer sched.Stop()
+	mockTime.Start()
+	defer mockTime.Stop()
+
+	item := <-enqueued
+	assert.Equal(t, scheduledQueueName, item.Queue)
+	assert.JSONEq(t, string(intervalCheckB), string(item.Value))
+
+	received <- time.Now()
+	raw := <-discoC
+	checkRequest, ok := raw.(*corev2.CheckRequest)
+	require.True(t, ok, "expected CheckRequest")
+	assert.Equal(t, intervalCheck.Name, checkRequest.Config.Name)
+	assert.Equal(t, intervalCheck.Subscriptions, checkRequest.Config.Subscriptions)
+}
+
+func TestSchedulerdShardedBridged(t *testing.T) {
+	intervalCheck := corev2.FixtureCheckConfig("interval")
+	intervalCheck.Subscriptions = append(intervalCheck.Subscriptions, "disco")
+	intervalCheck.Cron, intervalCheck.RoundRobin = "", false
+	intervalCheck.Interval = 1
+	intervalCheckB, _ := json.Marshal(intervalCheck)
+
+	stor := stubStoreForCheck(intervalCheck)
+
+	opc := &mockstore.OPC{}
+	opc.On("ListOperators", mock.Anything, mock.Anything).Return(backendOperators("backend"), nil)
+
+	// The owner of the check publishes it on the bridged bus instead of
+	// sending it to every backend, and the requests received from the queue
+	// are only published on the local bus
+	enqueued := make(chan queue.Item, 100)
+	received := make(chan time.Time)
+	never := make(chan time.Time)
+	mockQRes := &mockqueue.MockReservation{}
+	mockQRes.On("Item").Return(queue.Item{ID: "aaa", Queue: scheduledQueueName, Value: intervalCheckB})
+	mockQRes.On("Ack", mock.Anything).Return(nil)
+	mockQ := &mockqueue.MockQueue{}
+	mockQ.On("Reserve", mock.Anything, adhocQueueName).WaitUntil(never)
+	mockQ.On("Reserve", mock.Anything, scheduledQueueName).
+		WaitUntil(received).
+		Return(mockQRes, nil)
+	mockQ.On("Enqueue", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		enqueued <- args.Get(1).(queue.Item)
+	})
+
+	bus, err := messaging.NewWizardBus(messaging.WizardBusConfig{})
+	require.NoError(t, err)
+	require.NoError(t, bus.Start())
+	localBus, err := messaging.NewWizardBus(messaging.WizardBusConfig{})
+	require.NoError(t, err)
+	require.NoError(t, localBus.Start())
+
+	discoC := make(chan interface{}, 10)
+	discoSub, err := bus.Subscribe(messaging.SubscriptionTopic("default", "disco"), "testing", testSubscriber{ch: discoC})
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, discoSub.Cancel())
+	}()
+	localC := make(chan interface{}, 10)
+	localSub, err := localBus.Subscribe(messaging.SubscriptionTopic("default", "disco"), "testing", testSubscriber{ch: localC})
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, localSub.Cancel())
+	}()
+
+	sched, err := New(context.Background(), Config{
+		Store:                  stor,
+		Bus:                    bus,
+		SecretsProviderManager: secrets.NewProviderManager(&mockEventReceiver{}),
+		Queue:                  mockQ,
+		BackendName:            "backend",
+		OperatorQueryer:        opc,
+		LocalBus:               localBus,
+	})
+	require.NoError(t, err)
+	require.NoError(t, sched.Start())
+	defer sched.Stop()
+	mockTime.Start()
+	defer mockTime.Stop()
+
+	raw := <-discoC
+	checkRequest, ok := raw.(*corev2.CheckRequest)
+	require.True(t, ok, "expected CheckRequest")
+	assert.Equal(t, intervalCheck.Name, checkRequest.Config.Name)
+	assert.Empty(t, enqueued)
+
+	received <- time.Now()
+	raw = <-localC
+	checkRequest, ok = raw.(*corev2.CheckRequest)
+	require.True(t, ok, "expected CheckRequest")
+	assert.Equal(t, intervalCheck.Name, checkRequest.Config.Name)
+}
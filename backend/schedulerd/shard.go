@@ -0,0 +1,98 @@
+package schedulerd
+
+import (
+	"context"
+	"path"
+
+	time "github.com/echlebek/timeproxy"
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/sirupsen/logrus"
+)
+
+// scheduledQueueName is the name of the queue on which the check requests
+// scheduled by the owner of a check are sent to every backend.
+const scheduledQueueName = "scheduledRequest"
+
+// shard determines the checks scheduled by this backend. The checks are
+// partitioned between the backends present in the operator registry with a
+// consistent hash ring, so that each check is scheduled by a single backend.
+//
+// When the set of backends changes, the checks taken over by this backend are
+// only scheduled once the handoff period has elapsed, which gives the other
+// backends the time to notice the change and to stop scheduling them.
+type shard struct {
+	backendName string
+	opc         store.OperatorQueryer
+	handoff     time.Duration
+
+	ring         *hashRing
+	previous     *hashRing
+	handoffUntil time.Time
+}
+
+func newShard(backendName string, opc store.OperatorQueryer, handoff time.Duration) *shard {
+	return &shard{
+		backendName: backendName,
+		opc:         opc,
+		handoff:     handoff,
+	}
+}
+
+// update rebuilds the hash ring from the backends present in the operator
+// registry. This backend is always part of the ring.
+func (s *shard) update(ctx context.Context) error {
+	operators, err := s.opc.ListOperators(ctx, store.OperatorKey{Type: store.BackendOperator})
+	if err != nil {
+		return err
+	}
+	others := make([]string, 0, len(operators))
+	for _, op := range operators {
+		if op.Present && op.Name != s.backendName {
+			others = append(others, op.Name)
+		}
+	}
+	ring := newHashRing(append([]string{s.backendName}, others...))
+	if s.ring != nil && ring.equal(s.ring) {
+		return nil
+	}
+
+	previous := s.ring
+	if previous == nil {
+		// This backend is starting, so its checks were owned by the others
+		previous = newHashRing(others)
+	}
+	s.previous, s.ring = previous, ring
+	s.handoffUntil = time.Now().Add(s.handoff)
+	logger.WithFields(logrus.Fields{
+		"backends": ring.backends,
+		"previous": previous.backends,
+	}).Info("scheduling backends changed, rebalancing checks")
+	return nil
+}
+
+// owns returns true if this backend must schedule the check.
+func (s *shard) owns(check *corev2.CheckConfig) bool {
+	key := path.Join(check.Namespace, check.Name)
+	if s.ring.owner(key) != s.backendName {
+		return false
+	}
+	if time.Now().Before(s.handoffUntil) {
+		// The check is taken over from another backend, which may still be
+		// scheduling it
+		previous := s.previous.owner(key)
+		return previous == "" || previous == s.backendName
+	}
+	return true
+}
+
+// filter returns the checks owned by this backend.
+func (s *shard) filter(checks []*corev2.CheckConfig) []*corev2.CheckConfig {
+	owned := make([]*corev2.CheckConfig, 0, len(checks))
+	for _, check := range checks {
+		if s.owns(check) {
+			owned = append(owned, check)
+		}
+	}
+	return owned
+}
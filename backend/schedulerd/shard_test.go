@@ -0,0 +1,89 @@
+package schedulerd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	time "github.com/echlebek/timeproxy"
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func backendOperators(names ...string) []store.OperatorState {
+	operators := make([]store.OperatorState, 0, len(names))
+	for _, name := range names {
+		operators = append(operators, store.OperatorState{
+			Type:    store.BackendOperator,
+			Name:    name,
+			Present: true,
+		})
+	}
+	return operators
+}
+
+func fixtureChecks(n int) []*corev2.CheckConfig {
+	checks := make([]*corev2.CheckConfig, 0, n)
+	for i := 0; i < n; i++ {
+		checks = append(checks, corev2.FixtureCheckConfig(fmt.Sprintf("check%d", i)))
+	}
+	return checks
+}
+
+func TestShard(t *testing.T) {
+	opc := &mockstore.OPC{}
+	absent := store.OperatorState{Type: store.BackendOperator, Name: "c"}
+	opc.On("ListOperators", mock.Anything, store.OperatorKey{Type: store.BackendOperator}).
+		Return(append(backendOperators("a", "b"), absent), nil)
+
+	shard := newShard("a", opc, time.Minute)
+	require.NoError(t, shard.update(context.Background()))
+	assert.Equal(t, []string{"a", "b"}, shard.ring.backends)
+
+	// The checks of a are owned by b until the end of the handoff
+	checks := fixtureChecks(100)
+	assert.Empty(t, shard.filter(checks))
+
+	shard.handoffUntil = time.Now().Add(-time.Second)
+	owned := shard.filter(checks)
+	assert.NotEmpty(t, owned)
+	assert.Less(t, len(owned), len(checks))
+	for _, check := range checks {
+		assert.Equal(t, shard.ring.owner("default/"+check.Name) == "a", shard.owns(check), check.Name)
+	}
+}
+
+func TestShardHandoff(t *testing.T) {
+	opc := &mockstore.OPC{}
+	call := opc.On("ListOperators", mock.Anything, mock.Anything).Return(backendOperators("a"), nil)
+
+	// A single backend owns all the checks right away
+	shard := newShard("a", opc, time.Minute)
+	require.NoError(t, shard.update(context.Background()))
+	checks := fixtureChecks(100)
+	assert.Len(t, shard.filter(checks), len(checks))
+
+	// A backend joining takes over some of the checks, which are released
+	// right away
+	call.Return(backendOperators("a", "b"), nil)
+	require.NoError(t, shard.update(context.Background()))
+	owned := shard.filter(checks)
+	assert.Less(t, len(owned), len(checks))
+
+	// A backend leaving hands its checks over after the handoff period
+	call.Return(backendOperators("a"), nil)
+	require.NoError(t, shard.update(context.Background()))
+	assert.Equal(t, owned, shard.filter(checks))
+	shard.handoffUntil = time.Now().Add(-time.Second)
+	assert.Len(t, shard.filter(checks), len(checks))
+
+	// The ring is left as is when the operators can't be listed
+	call.Return([]store.OperatorState(nil), errors.New("error"))
+	assert.Error(t, shard.update(context.Background()))
+	assert.Len(t, shard.filter(checks), len(checks))
+}
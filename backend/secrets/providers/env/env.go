@@ -0,0 +1,69 @@
+// Package env provides a secrets provider that reads the secrets from the
+// environment variables of the backend.
+package env
+
+import (
+	"os"
+
+	corev2 "github.com/sensu/core/v2"
+	secretsv1 "github.com/sensu/sensu-go/api/secrets/v1"
+	"github.com/sensu/sensu-go/backend/secrets"
+)
+
+var _ secrets.Provider = new(Provider)
+
+// Provider is a secrets provider that reads the secrets from the environment
+// variables of the backend. The ID of a secret is the name of the variable.
+type Provider struct {
+	// Config is the configuration of the provider
+	Config *secretsv1.Env
+}
+
+// New returns a provider for the given configuration.
+func New(config *secretsv1.Env) *Provider {
+	return &Provider{Config: config}
+}
+
+// Get returns the value of the environment variable id.
+func (p *Provider) Get(id string) (string, error) {
+	value, ok := os.LookupEnv(id)
+	if !ok {
+		return "", secrets.ErrSecretNotFound(id)
+	}
+	return value, nil
+}
+
+// GetMetadata returns the provider metadata
+func (p *Provider) GetMetadata() *corev2.ObjectMeta {
+	return p.Config.GetMetadata()
+}
+
+// SetMetadata sets the provider metadata
+func (p *Provider) SetMetadata(meta *corev2.ObjectMeta) {
+	p.Config.SetMetadata(meta)
+}
+
+// GetTypeMeta returns the type metadata of the provider configuration
+func (p *Provider) GetTypeMeta() corev2.TypeMeta {
+	return p.Config.GetTypeMeta()
+}
+
+// StoreName returns the store name of the provider configuration
+func (p *Provider) StoreName() string {
+	return p.Config.StoreName()
+}
+
+// RBACName returns the RBAC name of the provider configuration
+func (p *Provider) RBACName() string {
+	return p.Config.RBACName()
+}
+
+// URIPath returns the API path of the provider configuration
+func (p *Provider) URIPath() string {
+	return p.Config.URIPath()
+}
+
+// Validate validates the provider configuration
+func (p *Provider) Validate() error {
+	return p.Config.Validate()
+}
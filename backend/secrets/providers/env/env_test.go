@@ -0,0 +1,28 @@
+package env
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	secretsv1 "github.com/sensu/sensu-go/api/secrets/v1"
+	"github.com/sensu/sensu-go/backend/secrets"
+)
+
+func TestProviderGet(t *testing.T) {
+	t.Setenv("SENSU_TEST_SECRET", "s3cr3t")
+	t.Setenv("SENSU_TEST_EMPTY", "")
+	p := New(secretsv1.FixtureEnv("env"))
+
+	value, err := p.Get("SENSU_TEST_SECRET")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cr3t", value)
+
+	value, err = p.Get("SENSU_TEST_EMPTY")
+	require.NoError(t, err)
+	assert.Equal(t, "", value)
+
+	_, err = p.Get("SENSU_TEST_UNSET")
+	assert.Equal(t, secrets.ErrSecretNotFound("SENSU_TEST_UNSET"), err)
+}
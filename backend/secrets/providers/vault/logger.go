@@ -0,0 +1,7 @@
+package vault
+
+import "github.com/sirupsen/logrus"
+
+var logger = logrus.WithFields(logrus.Fields{
+	"component": "secrets/vault",
+})
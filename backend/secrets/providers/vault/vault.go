@@ -0,0 +1,312 @@
+// Package vault provides a secrets provider that reads the secrets from the KV
+// secrets engine of HashiCorp Vault.
+package vault
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/vault/api"
+	corev2 "github.com/sensu/core/v2"
+	secretsv1 "github.com/sensu/sensu-go/api/secrets/v1"
+	"github.com/sensu/sensu-go/backend/secrets"
+)
+
+var _ secrets.Provider = new(Provider)
+
+var (
+	// errNotFound is returned when Vault has no secret at a path.
+	errNotFound = errors.New("secret not found in vault")
+
+	// errPermissionDenied is returned when the token is invalid or expired, or
+	// doesn't grant access to a secret.
+	errPermissionDenied = errors.New("permission denied by vault")
+)
+
+type cachedSecret struct {
+	data   map[string]interface{}
+	expiry time.Time
+}
+
+// Provider is a secrets provider that reads the secrets from Vault. The ID of a
+// secret is the path of the secret, including its mount, and the key of the
+// value to read, separated by a #, e.g. secret/database#password.
+//
+// Secrets are cached for the configured cache TTL, or the duration of their
+// lease if shorter. AppRole tokens are renewed by logging in again, shortly
+// before they expire or whenever Vault rejects them.
+type Provider struct {
+	// Config is the configuration of the provider
+	Config *secretsv1.VaultProvider
+
+	// client reads the secrets, with the token of the provider, and login
+	// logs in with AppRole, without a token.
+	client *api.Client
+	login  *api.Client
+	now    func() time.Time
+
+	mu          sync.Mutex
+	cache       map[string]cachedSecret
+	token       string
+	tokenExpiry time.Time
+}
+
+// New returns a provider for the given configuration. It returns an error if
+// the TLS configuration of the client can't be loaded.
+func New(config *secretsv1.VaultProvider) (*Provider, error) {
+	tlsOptions := config.Client.TLS
+	if tlsOptions == nil {
+		tlsOptions = &corev2.TLSOptions{}
+	}
+	tlsConfig, err := tlsOptions.ToClientTLSConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid tls configuration: %s", err)
+	}
+
+	client, err := newClient(config, tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+	login, err := newClient(config, tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+	if config.Client.AppRole == nil {
+		client.SetToken(config.Client.Token)
+	}
+
+	return &Provider{
+		Config: config,
+		client: client,
+		login:  login,
+		now:    time.Now,
+		cache:  make(map[string]cachedSecret),
+	}, nil
+}
+
+// newClient returns a Vault client for the given configuration. The client
+// ignores the VAULT_* environment variables, and doesn't retry requests, since
+// secrets are read while handling events.
+func newClient(config *secretsv1.VaultProvider, tlsConfig *tls.Config) (*api.Client, error) {
+	clientConfig := api.DefaultConfig()
+	if clientConfig.Error != nil {
+		return nil, clientConfig.Error
+	}
+	clientConfig.Address = strings.TrimRight(config.Client.Address, "/")
+	clientConfig.Timeout = time.Duration(config.Client.GetTimeout()) * time.Second
+	clientConfig.MaxRetries = 0
+	clientConfig.HttpClient.Transport.(*http.Transport).TLSClientConfig = tlsConfig
+
+	client, err := api.NewClient(clientConfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vault client configuration: %s", err)
+	}
+	client.ClearToken()
+	client.ClearNamespace()
+	return client, nil
+}
+
+// Get returns the value of the secret id.
+func (p *Provider) Get(id string) (string, error) {
+	i := strings.LastIndex(id, "#")
+	if i <= 0 || i == len(id)-1 {
+		return "", fmt.Errorf("invalid secret id %q: must be <path>#<key>", id)
+	}
+	path, key := strings.Trim(id[:i], "/"), id[i+1:]
+
+	data, err := p.read(path)
+	if err != nil {
+		if errors.Is(err, errNotFound) {
+			return "", secrets.ErrSecretNotFound(id)
+		}
+		return "", err
+	}
+	value, ok := data[key]
+	if !ok {
+		return "", secrets.ErrSecretNotFound(id)
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// read returns the data of the secret at path, from the cache if possible.
+func (p *Provider) read(path string) (map[string]interface{}, error) {
+	p.mu.Lock()
+	cached, ok := p.cache[path]
+	p.mu.Unlock()
+	if ok && p.now().Before(cached.expiry) {
+		return cached.data, nil
+	}
+
+	mount, rest, _ := strings.Cut(path, "/")
+	var secret *api.KVSecret
+	if err := p.withToken(func() error {
+		var err error
+		if p.Config.Client.GetVersion() == secretsv1.VaultKVv2 {
+			secret, err = p.client.KVv2(mount).Get(context.Background(), rest)
+		} else {
+			secret, err = p.client.KVv1(mount).Get(context.Background(), rest)
+		}
+		return p.error(err)
+	}); err != nil {
+		return nil, err
+	}
+	if secret.Data == nil {
+		// Deleted secrets of KV v2 engines have no data
+		return nil, errNotFound
+	}
+
+	ttl := time.Duration(p.Config.Client.GetCacheTTL()) * time.Second
+	if lease := time.Duration(secret.Raw.LeaseDuration) * time.Second; lease > 0 && lease < ttl {
+		ttl = lease
+	}
+	p.mu.Lock()
+	p.cache[path] = cachedSecret{data: secret.Data, expiry: p.now().Add(ttl)}
+	p.mu.Unlock()
+
+	return secret.Data, nil
+}
+
+// withToken calls fn once the client has a valid token. With AppRole, fn is
+// retried once with a new token if Vault rejects the current one.
+func (p *Provider) withToken(fn func() error) error {
+	if err := p.renewToken(false); err != nil {
+		return err
+	}
+	err := fn()
+	if errors.Is(err, errPermissionDenied) && p.Config.Client.AppRole != nil {
+		if err := p.renewToken(true); err != nil {
+			return err
+		}
+		err = fn()
+	}
+	return err
+}
+
+// renewToken logs in with AppRole, if the provider has no token yet, if the
+// token is about to expire, or if force is true.
+func (p *Provider) renewToken(force bool) error {
+	appRole := p.Config.Client.AppRole
+	if appRole == nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !force && p.token != "" && (p.tokenExpiry.IsZero() || p.now().Before(p.tokenExpiry)) {
+		return nil
+	}
+
+	path := "auth/" + strings.Trim(appRole.GetMountPath(), "/") + "/login"
+	secret, err := p.login.Logical().Write(path, map[string]interface{}{
+		"role_id":   appRole.RoleID,
+		"secret_id": appRole.SecretID,
+	})
+	if err := p.error(err); err != nil {
+		if _, ok := err.(secrets.ErrProviderNotAvailable); ok {
+			return err
+		}
+		return fmt.Errorf("could not log in with approle: %w", err)
+	}
+	if secret == nil || secret.Auth == nil || secret.Auth.ClientToken == "" {
+		return errors.New("could not log in with approle: no token issued")
+	}
+	logger.WithField("provider", p.name()).Debug("logged in to vault with approle")
+
+	p.token = secret.Auth.ClientToken
+	p.tokenExpiry = time.Time{}
+	if lease := time.Duration(secret.Auth.LeaseDuration) * time.Second; lease > 0 {
+		// Renew the token before it expires, so requests in flight don't fail
+		p.tokenExpiry = p.now().Add(lease * 9 / 10)
+	}
+	p.client.SetToken(p.token)
+	return nil
+}
+
+// error maps the errors of the Vault client to the errors of the provider.
+func (p *Provider) error(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, api.ErrSecretNotFound) {
+		return errNotFound
+	}
+	var respErr *api.ResponseError
+	if !errors.As(err, &respErr) {
+		return secrets.ErrProviderNotAvailable(fmt.Sprintf("%s: %s", p.name(), err))
+	}
+	switch {
+	case respErr.StatusCode == http.StatusNotFound:
+		return errNotFound
+	case respErr.StatusCode == http.StatusForbidden:
+		return errPermissionDenied
+	case respErr.StatusCode >= http.StatusInternalServerError:
+		return secrets.ErrProviderNotAvailable(fmt.Sprintf("%s: %s", p.name(), responseError(respErr)))
+	default:
+		return responseError(respErr)
+	}
+}
+
+// responseError returns the errors reported by Vault in a response.
+func responseError(err *api.ResponseError) error {
+	status := fmt.Sprintf("%d %s", err.StatusCode, http.StatusText(err.StatusCode))
+	if len(err.Errors) == 0 {
+		return fmt.Errorf("vault responded with %s", status)
+	}
+	return fmt.Errorf("vault responded with %s: %s", status, strings.Join(err.Errors, ", "))
+}
+
+func (p *Provider) name() string {
+	if p.Config.Metadata == nil {
+		return ""
+	}
+	return p.Config.Metadata.Name
+}
+
+// GetMetadata returns the provider metadata
+func (p *Provider) GetMetadata() *corev2.ObjectMeta {
+	return p.Config.GetMetadata()
+}
+
+// SetMetadata sets the provider metadata
+func (p *Provider) SetMetadata(meta *corev2.ObjectMeta) {
+	p.Config.SetMetadata(meta)
+}
+
+// GetTypeMeta returns the type metadata of the provider configuration
+func (p *Provider) GetTypeMeta() corev2.TypeMeta {
+	return p.Config.GetTypeMeta()
+}
+
+// StoreName returns the store name of the provider configuration
+func (p *Provider) StoreName() string {
+	return p.Config.StoreName()
+}
+
+// RBACName returns the RBAC name of the provider configuration
+func (p *Provider) RBACName() string {
+	return p.Config.RBACName()
+}
+
+// URIPath returns the API path of the provider configuration
+func (p *Provider) URIPath() string {
+	return p.Config.URIPath()
+}
+
+// Validate validates the provider configuration
+func (p *Provider) Validate() error {
+	return p.Config.Validate()
+}
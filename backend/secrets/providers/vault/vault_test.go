@@ -0,0 +1,230 @@
+package vault
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev2 "github.com/sensu/core/v2"
+	secretsv1 "github.com/sensu/sensu-go/api/secrets/v1"
+	"github.com/sensu/sensu-go/backend/secrets"
+	"github.com/sensu/sensu-go/testing/mockvault"
+)
+
+const rootToken = "root"
+
+func newTestServer(t *testing.T, tls bool) *mockvault.Server {
+	t.Helper()
+	newServer := mockvault.NewServer
+	if tls {
+		newServer = mockvault.NewTLSServer
+	}
+	server := newServer(rootToken)
+	t.Cleanup(server.Close)
+
+	server.Mount("secret", secretsv1.VaultKVv2)
+	server.Mount("kv", secretsv1.VaultKVv1)
+	data := map[string]interface{}{
+		"username": "sensu",
+		"password": "P@ssw0rd!",
+		"port":     5432,
+	}
+	server.Put("secret/database", data)
+	server.Put("kv/database", data)
+	return server
+}
+
+func newTestProvider(t *testing.T, server *mockvault.Server, mutate func(*secretsv1.VaultClient)) *Provider {
+	t.Helper()
+	config := secretsv1.FixtureVaultProvider("vault")
+	config.Client.Address = server.URL
+	config.Client.Token = rootToken
+	if mutate != nil {
+		mutate(config.Client)
+	}
+	require.NoError(t, config.Validate())
+	p, err := New(config)
+	require.NoError(t, err)
+	return p
+}
+
+func TestProviderGet(t *testing.T) {
+	for _, version := range []string{secretsv1.VaultKVv1, secretsv1.VaultKVv2} {
+		t.Run(version, func(t *testing.T) {
+			server := newTestServer(t, false)
+			p := newTestProvider(t, server, func(c *secretsv1.VaultClient) {
+				c.Version = version
+			})
+			mount := "secret"
+			if version == secretsv1.VaultKVv1 {
+				mount = "kv"
+			}
+
+			value, err := p.Get(mount + "/database#password")
+			require.NoError(t, err)
+			assert.Equal(t, "P@ssw0rd!", value)
+
+			// Values that are not strings are encoded as JSON
+			value, err = p.Get(mount + "/database#port")
+			require.NoError(t, err)
+			assert.Equal(t, "5432", value)
+
+			_, err = p.Get(mount + "/database#unknown")
+			assert.Equal(t, secrets.ErrSecretNotFound(mount+"/database#unknown"), err)
+
+			_, err = p.Get(mount + "/unknown#password")
+			assert.Equal(t, secrets.ErrSecretNotFound(mount+"/unknown#password"), err)
+		})
+	}
+}
+
+func TestProviderGetInvalidID(t *testing.T) {
+	server := newTestServer(t, false)
+	p := newTestProvider(t, server, nil)
+
+	for _, id := range []string{"secret/database", "#password", "secret/database#"} {
+		_, err := p.Get(id)
+		assert.Error(t, err, id)
+	}
+	assert.Equal(t, 0, server.Reads())
+}
+
+func TestProviderGetPermissionDenied(t *testing.T) {
+	server := newTestServer(t, false)
+	p := newTestProvider(t, server, func(c *secretsv1.VaultClient) {
+		c.Token = "invalid"
+	})
+
+	_, err := p.Get("secret/database#password")
+	assert.ErrorIs(t, err, errPermissionDenied)
+}
+
+func TestProviderGetUnavailable(t *testing.T) {
+	server := newTestServer(t, false)
+	p := newTestProvider(t, server, nil)
+	server.Close()
+
+	_, err := p.Get("secret/database#password")
+	assert.IsType(t, secrets.ErrProviderNotAvailable(""), err)
+}
+
+func TestProviderCache(t *testing.T) {
+	server := newTestServer(t, false)
+	p := newTestProvider(t, server, func(c *secretsv1.VaultClient) {
+		c.CacheTTL = 60
+	})
+	now := time.Now()
+	p.now = func() time.Time { return now }
+
+	_, err := p.Get("secret/database#username")
+	require.NoError(t, err)
+	_, err = p.Get("secret/database#password")
+	require.NoError(t, err)
+	assert.Equal(t, 1, server.Reads())
+
+	// Secrets are read again once the cache expires
+	server.Put("secret/database", map[string]interface{}{"password": "changed"})
+	now = now.Add(time.Minute)
+	value, err := p.Get("secret/database#password")
+	require.NoError(t, err)
+	assert.Equal(t, "changed", value)
+	assert.Equal(t, 2, server.Reads())
+}
+
+func TestProviderCacheLease(t *testing.T) {
+	server := newTestServer(t, false)
+	server.SetLeaseDuration(10 * time.Second)
+	p := newTestProvider(t, server, func(c *secretsv1.VaultClient) {
+		c.Version = secretsv1.VaultKVv1
+		c.CacheTTL = 60
+	})
+	now := time.Now()
+	p.now = func() time.Time { return now }
+
+	_, err := p.Get("kv/database#password")
+	require.NoError(t, err)
+
+	// Secrets are not cached beyond their lease
+	now = now.Add(10 * time.Second)
+	_, err = p.Get("kv/database#password")
+	require.NoError(t, err)
+	assert.Equal(t, 2, server.Reads())
+}
+
+func TestProviderAppRole(t *testing.T) {
+	server := newTestServer(t, false)
+	server.EnableAppRole("custom", "sensu", "s3cr3t", time.Hour)
+	p := newTestProvider(t, server, func(c *secretsv1.VaultClient) {
+		c.Token = ""
+		c.AppRole = &secretsv1.VaultAppRole{RoleID: "sensu", SecretID: "s3cr3t", MountPath: "custom"}
+		c.CacheTTL = 1
+	})
+	now := time.Now()
+	p.now = func() time.Time { return now }
+
+	value, err := p.Get("secret/database#password")
+	require.NoError(t, err)
+	assert.Equal(t, "P@ssw0rd!", value)
+	assert.Equal(t, 1, server.Logins())
+
+	// The token is reused while it is valid
+	now = now.Add(time.Minute)
+	_, err = p.Get("secret/database#password")
+	require.NoError(t, err)
+	assert.Equal(t, 1, server.Logins())
+
+	// The provider logs in again once Vault rejects its token
+	server.RevokeTokens()
+	now = now.Add(time.Minute)
+	_, err = p.Get("secret/database#password")
+	require.NoError(t, err)
+	assert.Equal(t, 2, server.Logins())
+
+	// The provider logs in again before its token expires
+	now = now.Add(55 * time.Minute)
+	_, err = p.Get("secret/database#password")
+	require.NoError(t, err)
+	assert.Equal(t, 3, server.Logins())
+	assert.Equal(t, 4, server.Reads())
+}
+
+func TestProviderAppRoleInvalidCredentials(t *testing.T) {
+	server := newTestServer(t, false)
+	server.EnableAppRole("approle", "sensu", "s3cr3t", time.Hour)
+	p := newTestProvider(t, server, func(c *secretsv1.VaultClient) {
+		c.Token = ""
+		c.AppRole = &secretsv1.VaultAppRole{RoleID: "sensu", SecretID: "wrong"}
+	})
+
+	_, err := p.Get("secret/database#password")
+	assert.ErrorContains(t, err, "invalid role or secret ID")
+}
+
+func TestProviderTLS(t *testing.T) {
+	server := newTestServer(t, true)
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, server.CACertificate, 0600))
+
+	// The server is not trusted by default
+	p := newTestProvider(t, server, nil)
+	_, err := p.Get("secret/database#password")
+	assert.Error(t, err)
+
+	p = newTestProvider(t, server, func(c *secretsv1.VaultClient) {
+		c.TLS = &corev2.TLSOptions{TrustedCAFile: caFile}
+	})
+	value, err := p.Get("secret/database#password")
+	require.NoError(t, err)
+	assert.Equal(t, "P@ssw0rd!", value)
+}
+
+func TestNewInvalidTLS(t *testing.T) {
+	config := secretsv1.FixtureVaultProvider("vault")
+	config.Client.TLS = &corev2.TLSOptions{TrustedCAFile: filepath.Join(t.TempDir(), "missing.pem")}
+	_, err := New(config)
+	assert.Error(t, err)
+}
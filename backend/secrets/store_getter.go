@@ -0,0 +1,32 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+
+	corev2 "github.com/sensu/core/v2"
+	secretsv1 "github.com/sensu/sensu-go/api/secrets/v1"
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+// StoreGetter is a Getter that looks up the secrets in the store. Secrets are
+// looked up in the namespace of the context.
+type StoreGetter struct {
+	Store storev2.Interface
+}
+
+// Get gets the name of the provider and secret ID associated with the Sensu
+// secret name.
+func (g *StoreGetter) Get(ctx context.Context, name string) (string, string, error) {
+	namespace := corev2.ContextNamespace(ctx)
+	secret, err := storev2.Of[*secretsv1.Secret](g.Store).Get(ctx, storev2.ID{Namespace: namespace, Name: name})
+	if err != nil {
+		var notFound *store.ErrNotFound
+		if errors.As(err, &notFound) {
+			return "", "", ErrSecretNotFound(name)
+		}
+		return "", "", err
+	}
+	return secret.Provider, secret.ID, nil
+}
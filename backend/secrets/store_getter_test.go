@@ -0,0 +1,45 @@
+package secrets
+
+import (
+	"context"
+	"testing"
+
+	corev2 "github.com/sensu/core/v2"
+	secretsv1 "github.com/sensu/sensu-go/api/secrets/v1"
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreGetter(t *testing.T) {
+	secret := secretsv1.FixtureSecret("db-password", "acme")
+	secret.ID = "secret/database#password"
+	secret.Provider = "vault"
+
+	s := new(mockstore.V2MockStore)
+	cs := new(mockstore.ConfigStore)
+	s.On("GetConfigStore").Return(cs)
+	cs.On("Get", mock.Anything, mock.MatchedBy(func(req storev2.ResourceRequest) bool {
+		return req.Namespace == "acme" && req.Name == "db-password"
+	})).Return(mockstore.Wrapper[*secretsv1.Secret]{Value: secret}, nil)
+	cs.On("Get", mock.Anything, mock.Anything).Return(nil, &store.ErrNotFound{Key: "unknown"})
+
+	getter := &StoreGetter{Store: s}
+	ctx := context.WithValue(context.Background(), corev2.NamespaceKey, "acme")
+
+	provider, id, err := getter.Get(ctx, "db-password")
+	require.NoError(t, err)
+	assert.Equal(t, "vault", provider)
+	assert.Equal(t, "secret/database#password", id)
+
+	_, _, err = getter.Get(ctx, "unknown")
+	assert.Equal(t, ErrSecretNotFound("unknown"), err)
+
+	// Secrets are namespaced
+	ctx = context.WithValue(context.Background(), corev2.NamespaceKey, "other")
+	_, _, err = getter.Get(ctx, "db-password")
+	assert.Equal(t, ErrSecretNotFound("db-password"), err)
+}
@@ -0,0 +1,61 @@
+package backend
+
+import (
+	"context"
+
+	corev2 "github.com/sensu/core/v2"
+	secretsv1 "github.com/sensu/sensu-go/api/secrets/v1"
+	"github.com/sensu/sensu-go/backend/secrets"
+	"github.com/sensu/sensu-go/backend/secrets/providers/env"
+	"github.com/sensu/sensu-go/backend/secrets/providers/vault"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+// SecretsProvidersLoop adds the secrets providers configured in the store to
+// the provider manager, and keeps them in sync with the store until ctx is
+// cancelled.
+func SecretsProvidersLoop(ctx context.Context, s storev2.Interface, manager *secrets.ProviderManager) {
+	go watchProviders(ctx, s, secretsProviders(manager), newEnvSecretsProvider)
+	watchProviders(ctx, s, secretsProviders(manager), newVaultSecretsProvider)
+}
+
+func newEnvSecretsProvider(config *secretsv1.Env) secrets.Provider {
+	return env.New(config)
+}
+
+// newVaultSecretsProvider returns a Vault provider, or a broken provider
+// reporting why the provider could not be created whenever a secret is read.
+func newVaultSecretsProvider(config *secretsv1.VaultProvider) secrets.Provider {
+	provider, err := vault.New(config)
+	if err != nil {
+		logger.WithError(err).WithField("provider", config.Metadata.Name).Error("could not create the vault secrets provider")
+		return &secrets.BrokenProvider{
+			TypeMeta: config.GetTypeMeta(),
+			Metadata: *config.Metadata,
+			Err:      err,
+		}
+	}
+	return provider
+}
+
+// secretsProviders is the kind of the providers of the provider manager.
+func secretsProviders(manager *secrets.ProviderManager) providerKind[secrets.Provider] {
+	return providerKind[secrets.Provider]{
+		name:   "secrets",
+		set:    manager,
+		typeOf: secretsProviderType,
+	}
+}
+
+// secretsProviderType returns the type of a secrets provider, or of its
+// configuration.
+func secretsProviderType(v interface{}) string {
+	switch v := v.(type) {
+	case *secrets.BrokenProvider:
+		return v.TypeMeta.Type
+	case interface{ GetTypeMeta() corev2.TypeMeta }:
+		return v.GetTypeMeta().Type
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,86 @@
+package backend
+
+import (
+	"testing"
+
+	corev2 "github.com/sensu/core/v2"
+	secretsv1 "github.com/sensu/sensu-go/api/secrets/v1"
+	"github.com/sensu/sensu-go/backend/secrets"
+	"github.com/sensu/sensu-go/backend/secrets/providers/env"
+	"github.com/sensu/sensu-go/backend/secrets/providers/vault"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+func TestHandleSecretsProviderEvent(t *testing.T) {
+	manager := secrets.NewProviderManager(nil)
+
+	config := secretsv1.FixtureVaultProvider("vault")
+	handleProviderEvent(secretsProviders(manager), storev2.GenericEvent[*secretsv1.VaultProvider]{Type: storev2.WatchCreate, Value: config}, newVaultSecretsProvider)
+	provider, ok := manager.Providers()["vault"].(*vault.Provider)
+	if !ok {
+		t.Fatal("the vault provider was not added")
+	}
+	if provider.Config != config {
+		t.Error("the vault provider has the wrong configuration")
+	}
+
+	updated := secretsv1.FixtureVaultProvider("vault")
+	updated.Client.Address = "https://other.example.com:8200"
+	handleProviderEvent(secretsProviders(manager), storev2.GenericEvent[*secretsv1.VaultProvider]{Type: storev2.WatchUpdate, Value: updated}, newVaultSecretsProvider)
+	if got := manager.Providers()["vault"].(*vault.Provider).Config.Client.Address; got != updated.Client.Address {
+		t.Errorf("the vault provider was not updated: address = %q", got)
+	}
+
+	handleProviderEvent(secretsProviders(manager), storev2.GenericEvent[*secretsv1.VaultProvider]{
+		Type: storev2.WatchDelete,
+		Key:  corev2.ObjectMeta{Name: "vault"},
+	}, newVaultSecretsProvider)
+	if _, ok := manager.Providers()["vault"]; ok {
+		t.Error("the vault provider was not removed")
+	}
+}
+
+func TestHandleSecretsProviderEventBroken(t *testing.T) {
+	manager := secrets.NewProviderManager(nil)
+
+	config := secretsv1.FixtureVaultProvider("vault")
+	config.Client.TLS = &corev2.TLSOptions{TrustedCAFile: "/nonexistent/ca.pem"}
+	handleProviderEvent(secretsProviders(manager), storev2.GenericEvent[*secretsv1.VaultProvider]{Type: storev2.WatchCreate, Value: config}, newVaultSecretsProvider)
+	provider, ok := manager.Providers()["vault"].(*secrets.BrokenProvider)
+	if !ok {
+		t.Fatal("a broken provider was not added")
+	}
+	if _, err := provider.Get("secret/database#password"); err == nil {
+		t.Error("expected the broken provider to return an error")
+	}
+}
+
+func TestHandleSecretsProviderEventTypes(t *testing.T) {
+	manager := secrets.NewProviderManager(nil)
+
+	handleProviderEvent(secretsProviders(manager), storev2.GenericEvent[*secretsv1.Env]{
+		Type:  storev2.WatchCreate,
+		Value: secretsv1.FixtureEnv("env"),
+	}, newEnvSecretsProvider)
+	if _, ok := manager.Providers()["env"].(*env.Provider); !ok {
+		t.Fatal("the env provider was not added")
+	}
+
+	// A Vault provider with the same name replaces the env provider
+	handleProviderEvent(secretsProviders(manager), storev2.GenericEvent[*secretsv1.VaultProvider]{
+		Type:  storev2.WatchCreate,
+		Value: secretsv1.FixtureVaultProvider("env"),
+	}, newVaultSecretsProvider)
+	if _, ok := manager.Providers()["env"].(*vault.Provider); !ok {
+		t.Fatal("the vault provider did not replace the env provider")
+	}
+
+	// Deleting the env provider keeps the Vault provider
+	handleProviderEvent(secretsProviders(manager), storev2.GenericEvent[*secretsv1.Env]{
+		Type: storev2.WatchDelete,
+		Key:  corev2.ObjectMeta{Name: "env"},
+	}, newEnvSecretsProvider)
+	if _, ok := manager.Providers()["env"].(*vault.Provider); !ok {
+		t.Error("the vault provider was removed")
+	}
+}
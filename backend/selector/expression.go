@@ -0,0 +1,79 @@
+package selector
+
+// Expression is a boolean expression that can be evaluated against a set of
+// fields or labels. Operations are the leaves of expressions, and can be
+// combined with the ||, && and ! operators, and grouped with parentheses.
+type Expression interface {
+	Matches(set map[string]string) bool
+}
+
+// AndExpression is the logical conjunction of its operands.
+type AndExpression struct {
+	Operands []Expression
+}
+
+// Matches returns true if all of the operands match the given set.
+func (e AndExpression) Matches(set map[string]string) bool {
+	for _, operand := range e.Operands {
+		if !operand.Matches(set) {
+			return false
+		}
+	}
+	return true
+}
+
+// OrExpression is the logical disjunction of its operands.
+type OrExpression struct {
+	Operands []Expression
+}
+
+// Matches returns true if any of the operands matches the given set.
+func (e OrExpression) Matches(set map[string]string) bool {
+	for _, operand := range e.Operands {
+		if operand.Matches(set) {
+			return true
+		}
+	}
+	return false
+}
+
+// NotExpression is the logical negation of its operand.
+type NotExpression struct {
+	Operand Expression
+}
+
+// Matches returns true if the operand does not match the given set.
+func (e NotExpression) Matches(set map[string]string) bool {
+	return !e.Operand.Matches(set)
+}
+
+// Matches returns true if the operation matches the given set.
+func (o Operation) Matches(set map[string]string) bool {
+	return matches(o, set)
+}
+
+// setOperationType returns a copy of expr where all operations have the
+// given operation type.
+func setOperationType(expr Expression, t OperationType) Expression {
+	switch e := expr.(type) {
+	case Operation:
+		e.OperationType = t
+		return e
+	case AndExpression:
+		operands := make([]Expression, len(e.Operands))
+		for i := range e.Operands {
+			operands[i] = setOperationType(e.Operands[i], t)
+		}
+		return AndExpression{Operands: operands}
+	case OrExpression:
+		operands := make([]Expression, len(e.Operands))
+		for i := range e.Operands {
+			operands[i] = setOperationType(e.Operands[i], t)
+		}
+		return OrExpression{Operands: operands}
+	case NotExpression:
+		return NotExpression{Operand: setOperationType(e.Operand, t)}
+	default:
+		return expr
+	}
+}
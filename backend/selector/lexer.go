@@ -60,8 +60,43 @@ const (
 
 	// matchesToken represents matches
 	matchesToken
+
+	// leftParenToken represents (
+	leftParenToken
+
+	// rightParenToken represents )
+	rightParenToken
+
+	// doublePipeToken represents ||
+	doublePipeToken
+
+	// notToken represents !
+	notToken
+
+	// greaterThanToken represents >
+	greaterThanToken
+
+	// greaterThanOrEqualToken represents >=
+	greaterThanOrEqualToken
+
+	// lessThanToken represents <
+	lessThanToken
+
+	// lessThanOrEqualToken represents <=
+	lessThanOrEqualToken
+
+	// numberToken represents an integer or decimal number, e.g. -1.5
+	numberToken
 )
 
+// orEqualTokens maps single character operators to their counterpart
+// followed by '='
+var orEqualTokens = map[TokenT]TokenT{
+	notToken:         notEqualToken,
+	greaterThanToken: greaterThanOrEqualToken,
+	lessThanToken:    lessThanOrEqualToken,
+}
+
 var reservedWords = map[string]Token{
 	"in":      Token{Type: inToken, Value: "in"},
 	"notin":   Token{Type: notInToken, Value: "notin"},
@@ -88,6 +123,15 @@ func identTail(r rune) bool {
 	return r == '_' || r == '.' || r == '/' || unicode.IsDigit(r) || unicode.IsLetter(r)
 }
 
+// isDelimiter returns true if r terminates an identifier or a number
+func isDelimiter(r rune) bool {
+	switch r {
+	case '[', ']', '(', ')', '!', '&', '|', ',', '"', '\'', '=', '<', '>':
+		return true
+	}
+	return false
+}
+
 // Tokenize returns the next token found in the input stream
 func (l *lexer) Tokenize() Token {
 	// Yep, it's a state machine. 1-rune lookahead.
@@ -106,7 +150,9 @@ func (l *lexer) Tokenize() Token {
 					return Token{Type: errorToken, Value: fmt.Sprintf("end of input while scanning identifier: %q", string(buf))}
 				}
 				return Token{Type: endOfStringToken}
-			case identifierToken:
+			case identifierToken, numberToken:
+			case greaterThanToken, lessThanToken, notToken:
+				return Token{Type: state, Value: string(buf)}
 			default:
 				return Token{Type: errorToken}
 			}
@@ -121,20 +167,38 @@ func (l *lexer) Tokenize() Token {
 				return Token{Type: leftSquareToken, Value: "["}
 			case ']':
 				return Token{Type: rightSquareToken, Value: "]"}
+			case '(':
+				return Token{Type: leftParenToken, Value: "("}
+			case ')':
+				return Token{Type: rightParenToken, Value: ")"}
 			case '=':
 				state = doubleEqualSignToken
 				buf = append(buf, r)
 			case '!':
-				state = notEqualToken
+				state = notToken
 				buf = append(buf, r)
 			case '&':
 				state = doubleAmpersandToken
 				buf = append(buf, r)
+			case '|':
+				state = doublePipeToken
+				buf = append(buf, r)
+			case '>':
+				state = greaterThanToken
+				buf = append(buf, r)
+			case '<':
+				state = lessThanToken
+				buf = append(buf, r)
 			case ',':
 				return Token{Type: commaToken, Value: ","}
 			case '"', '\'':
 				state = stringToken
 			default:
+				if len(buf) == 0 && (r == '-' || unicode.IsDigit(r)) {
+					state = numberToken
+					buf = append(buf, r)
+					continue
+				}
 				if !identStart(r) {
 					if len(buf) > 0 {
 						return Token{Type: errorToken, Value: fmt.Sprintf("invalid identifier: %q", string(append(buf, r)))}
@@ -144,7 +208,7 @@ func (l *lexer) Tokenize() Token {
 				state = identifierToken
 				buf = append(buf, r)
 			}
-		case doubleEqualSignToken, notEqualToken:
+		case doubleEqualSignToken:
 			switch r {
 			case '=':
 				return Token{Type: state, Value: string(append(buf, r))}
@@ -152,14 +216,41 @@ func (l *lexer) Tokenize() Token {
 				errmsg := fmt.Sprintf("at %d, looking for %q but got %q", l.position, "=", string(append(buf, r)))
 				return Token{Type: errorToken, Value: errmsg}
 			}
-		case doubleAmpersandToken:
+		case notToken, greaterThanToken, lessThanToken:
+			// These operators are either used on their own, or followed by
+			// '=', e.g. ! and !=
+			if r == '=' {
+				return Token{Type: orEqualTokens[state], Value: string(append(buf, r))}
+			}
+			_ = l.input.UnreadRune()
+			l.position -= size
+			return Token{Type: state, Value: string(buf)}
+		case doubleAmpersandToken, doublePipeToken:
 			switch r {
-			case '&':
+			case buf[0]:
 				return Token{Type: state, Value: string(append(buf, r))}
 			default:
-				errmsg := fmt.Sprintf("at %d, looking for %q but got %q", l.position, "&", string(append(buf, r)))
+				errmsg := fmt.Sprintf("at %d, looking for %q but got %q", l.position, string(buf[0]), string(append(buf, r)))
 				return Token{Type: errorToken, Value: errmsg}
 			}
+		case numberToken:
+			if unicode.IsSpace(r) || err == io.EOF || isDelimiter(r) {
+				if isDelimiter(r) {
+					_ = l.input.UnreadRune()
+					l.position -= size
+				}
+				if _, perr := ParseNumber(string(buf)); perr != nil {
+					return Token{Type: errorToken, Value: fmt.Sprintf("invalid number: %q", string(buf))}
+				}
+				return Token{Type: state, Value: string(buf)}
+			}
+			if unicode.IsDigit(r) || r == '.' {
+				buf = append(buf, r)
+				continue
+			}
+			// Identifiers can't start with a digit, report the rune that
+			// started this token.
+			return Token{Type: errorToken, Value: fmt.Sprintf("invalid rune: %q", string(buf[0]))}
 		case identifierToken:
 			if unicode.IsSpace(r) || err == io.EOF {
 				if buf[len(buf)-1] == '.' {
@@ -172,10 +263,16 @@ func (l *lexer) Tokenize() Token {
 				}
 				return Token{Type: state, Value: buf}
 			}
-			switch r {
-			case '[', ']', '!', '&', ',', '"', '\'':
+			if isDelimiter(r) {
 				_ = l.input.UnreadRune()
-				return Token{Type: state, Value: string(buf)}
+				l.position -= size
+				buf := string(buf)
+				if tok, ok := reservedWords[strings.ToLower(buf)]; ok {
+					return tok
+				}
+				return Token{Type: state, Value: buf}
+			}
+			switch r {
 			case '.':
 				state = start
 			}
@@ -207,6 +207,36 @@ func TestLexerTokenize(t *testing.T) {
 			input: "matches",
 			want:  Token{Type: matchesToken, Value: "matches"},
 		},
+		{
+			name:  "or operator",
+			input: "|| foo",
+			want:  Token{Type: doublePipeToken, Value: "||"},
+		},
+		{
+			name:  "not operator",
+			input: "!(foo",
+			want:  Token{Type: notToken, Value: "!"},
+		},
+		{
+			name:  "greater than or equal operator",
+			input: ">= 2",
+			want:  Token{Type: greaterThanOrEqualToken, Value: ">="},
+		},
+		{
+			name:  "less than operator",
+			input: "<2",
+			want:  Token{Type: lessThanToken, Value: "<"},
+		},
+		{
+			name:  "negative decimal number",
+			input: "-1.5)",
+			want:  Token{Type: numberToken, Value: "-1.5"},
+		},
+		{
+			name:  "invalid number",
+			input: "1.",
+			want:  Token{Type: errorToken, Value: `invalid number: "1."`},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -248,6 +278,36 @@ func TestArrayTokens(t *testing.T) {
 	}
 }
 
+func TestExpressionTokens(t *testing.T) {
+	input := "!(check.status>=2||has labels.region)"
+	lexer := newLexer(input)
+	var token Token
+	var tokens []Token
+	for token.Type != endOfStringToken {
+		token = lexer.Tokenize()
+		if token.Type == errorToken {
+			t.Fatal(token.Value)
+		}
+		tokens = append(tokens, token)
+	}
+	want := []Token{
+		{Type: notToken, Value: "!"},
+		{Type: leftParenToken, Value: "("},
+		{Type: identifierToken, Value: "check.status"},
+		{Type: greaterThanOrEqualToken, Value: ">="},
+		{Type: numberToken, Value: "2"},
+		{Type: doublePipeToken, Value: "||"},
+		{Type: identifierToken, Value: "has"},
+		{Type: identifierToken, Value: "labels.region"},
+		{Type: rightParenToken, Value: ")"},
+		{Type: endOfStringToken},
+	}
+
+	if got := tokens; !reflect.DeepEqual(got, want) {
+		t.Fatalf("lexer.Tokenize(): %v != %v", got, want)
+	}
+}
+
 func TestLexIdentifiers(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -1,6 +1,9 @@
 package selector
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 type Operator string
 
@@ -15,8 +18,22 @@ const (
 	NotInOperator Operator = "notin"
 	// MatchesOperator represents matches
 	MatchesOperator Operator = "matches"
+	// GreaterThanOperator represents >
+	GreaterThanOperator Operator = ">"
+	// GreaterThanOrEqualOperator represents >=
+	GreaterThanOrEqualOperator Operator = ">="
+	// LessThanOperator represents <
+	LessThanOperator Operator = "<"
+	// LessThanOrEqualOperator represents <=
+	LessThanOrEqualOperator Operator = "<="
+	// HasOperator represents has, which checks for the presence of a key
+	HasOperator Operator = "has"
 )
 
+// hasKeyword is the keyword of the has operator. It is not a reserved word,
+// so that "has" can still be used as a key or a value.
+const hasKeyword = "has"
+
 type OperationType int
 
 const (
@@ -46,14 +63,39 @@ func Parse(input string) (*Selector, error) {
 
 	parser.tokenize()
 
-	operations, err := parser.operations()
+	if parser.peek().Type == endOfStringToken {
+		return &Selector{}, nil
+	}
+
+	expr, err := parser.parseExpression()
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("could not parse the operations: %s", err)
+	}
+	if result := parser.read(); result.Type != endOfStringToken {
+		return nil, fmt.Errorf("unexpected token '%s', expected an operator or end of string", result.Value)
 	}
 
-	selector := &Selector{Operations: operations}
+	return newSelector(expr), nil
+}
 
-	return selector, nil
+// newSelector returns a selector for the given expression. The operations of
+// a top-level conjunction are stored in Operations, and any other expression
+// in Expressions, so that selectors which only use && keep their simple
+// representation.
+func newSelector(expr Expression) *Selector {
+	operands := []Expression{expr}
+	if and, ok := expr.(AndExpression); ok {
+		operands = and.Operands
+	}
+	selector := &Selector{}
+	for _, operand := range operands {
+		if operation, ok := operand.(Operation); ok {
+			selector.Operations = append(selector.Operations, operation)
+		} else {
+			selector.Expressions = append(selector.Expressions, operand)
+		}
+	}
+	return selector
 }
 
 // ParseFieldSelector parses the input and returns a field selector.
@@ -65,6 +107,9 @@ func ParseFieldSelector(input string) (*Selector, error) {
 	for i := range sel.Operations {
 		sel.Operations[i].OperationType = OperationTypeFieldSelector
 	}
+	for i := range sel.Expressions {
+		sel.Expressions[i] = setOperationType(sel.Expressions[i], OperationTypeFieldSelector)
+	}
 	return sel, nil
 }
 
@@ -77,6 +122,9 @@ func ParseLabelSelector(input string) (*Selector, error) {
 	for i := range sel.Operations {
 		sel.Operations[i].OperationType = OperationTypeLabelSelector
 	}
+	for i := range sel.Expressions {
+		sel.Expressions[i] = setOperationType(sel.Expressions[i], OperationTypeLabelSelector)
+	}
 	return sel, nil
 }
 
@@ -103,6 +151,14 @@ func (p *parser) parseOperator() (Operator, error) {
 		return NotInOperator, nil
 	case matchesToken:
 		return MatchesOperator, nil
+	case greaterThanToken:
+		return GreaterThanOperator, nil
+	case greaterThanOrEqualToken:
+		return GreaterThanOrEqualOperator, nil
+	case lessThanToken:
+		return LessThanOperator, nil
+	case lessThanOrEqualToken:
+		return LessThanOrEqualOperator, nil
 	default:
 		return "", fmt.Errorf("unexpected operator '%s' found", result.Value)
 	}
@@ -130,10 +186,19 @@ func (p *parser) parseOperation() (Operation, error) {
 		if err != nil {
 			return r, err
 		}
+	case GreaterThanOperator, GreaterThanOrEqualOperator, LessThanOperator, LessThanOrEqualOperator:
+		result := p.read()
+		if result.Type != numberToken && result.Type != stringToken {
+			return r, fmt.Errorf("unexpected token '%s': expected a number", result.Value)
+		}
+		if _, err := ParseNumber(result.Value); err != nil {
+			return r, fmt.Errorf("unexpected value '%s': expected a number", result.Value)
+		}
+		r.RValues = []string{result.Value}
 	default:
 		result := p.read()
 		switch result.Type {
-		case identifierToken, stringToken, boolToken, matchesToken:
+		case identifierToken, stringToken, boolToken, matchesToken, numberToken:
 			r.RValues = []string{result.Value}
 		default:
 			return r, fmt.Errorf("unexpected token '%s': expected an identifier or literal value", result.Value)
@@ -159,7 +224,7 @@ func (p *parser) parseValues() ([]string, error) {
 	for {
 		result = p.read()
 		switch result.Type {
-		case identifierToken, stringToken:
+		case identifierToken, stringToken, numberToken:
 			values = append(values, result.Value)
 		case commaToken:
 			continue
@@ -185,45 +250,103 @@ func (p *parser) read() Token {
 	return p.results[p.position-1]
 }
 
-// operations analyzes the results and determines the list of operations
-func (p *parser) operations() ([]Operation, error) {
-	var operations []Operation
+// parseExpression parses a disjunction of conjunctions, e.g.
+// a == b && c == d || e == f. && has precedence over ||.
+func (p *parser) parseExpression() (Expression, error) {
+	var operands []Expression
+	for {
+		operand, err := p.parseAndExpression()
+		if err != nil {
+			return nil, err
+		}
+		if or, ok := operand.(OrExpression); ok {
+			operands = append(operands, or.Operands...)
+		} else {
+			operands = append(operands, operand)
+		}
+		if p.peek().Type != doublePipeToken {
+			break
+		}
+		_ = p.read()
+	}
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+	return OrExpression{Operands: operands}, nil
+}
 
+// parseAndExpression parses a conjunction of unary expressions, e.g.
+// a == b && !(c == d)
+func (p *parser) parseAndExpression() (Expression, error) {
+	var operands []Expression
 	for {
-		result := p.peek()
-		switch result.Type {
-		case identifierToken, stringToken:
-			operation, err := p.parseOperation()
-			if err != nil {
-				return nil, fmt.Errorf("could not parse the operations: %s", err)
-			}
-			// We found a valid operation, append it to our list of operations
-			operations = append(operations, operation)
-		case doubleAmpersandToken:
-			// Move the position forward
-			_ = p.read()
+		operand, err := p.parseUnaryExpression()
+		if err != nil {
+			return nil, err
+		}
+		if and, ok := operand.(AndExpression); ok {
+			operands = append(operands, and.Operands...)
+		} else {
+			operands = append(operands, operand)
+		}
+		if p.peek().Type != doubleAmpersandToken {
+			break
+		}
+		_ = p.read()
+	}
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+	return AndExpression{Operands: operands}, nil
+}
 
-			// Make sure we already have a operation before accepting the '&&'
-			// operator
-			if len(operations) == 0 {
-				return operations, fmt.Errorf("unexpected '&&' operator found, expected a operation first")
-			}
-
-			// Make sure the next token is an identifier or a string, which are
-			// the 2 types of tokens that can start a new expression.
-			result = p.peek()
-			if result.Type == identifierToken || result.Type == stringToken {
-				continue
-			} else {
-				return nil, fmt.Errorf("unexpected token '%s', expected an identifier or string after '&&'", result.Value)
-			}
-		case endOfStringToken:
-			return operations, nil
-		default:
-			return nil, fmt.Errorf("unexpected token '%s', expected an identifier or end of string", result.Value)
+// parseUnaryExpression parses a negation, a parenthesized expression, a has
+// operation or a regular operation.
+func (p *parser) parseUnaryExpression() (Expression, error) {
+	result := p.peek()
+	switch result.Type {
+	case notToken:
+		_ = p.read()
+		operand, err := p.parseUnaryExpression()
+		if err != nil {
+			return nil, err
+		}
+		return NotExpression{Operand: operand}, nil
+	case leftParenToken:
+		_ = p.read()
+		expr, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		if result := p.read(); result.Type != rightParenToken {
+			return nil, fmt.Errorf("unexpected token '%s', expected ')'", result.Value)
 		}
+		return expr, nil
+	case identifierToken, stringToken:
+		if p.isHasOperation() {
+			_ = p.read()
+			key := p.read()
+			return Operation{LValue: key.Value, Operator: HasOperator}, nil
+		}
+		return p.parseOperation()
+	case endOfStringToken:
+		return nil, fmt.Errorf("unexpected end of string, expected an operation")
+	default:
+		return nil, fmt.Errorf("unexpected token '%s', expected an identifier, '!' or '('", result.Value)
+	}
+}
 
+// isHasOperation returns true if the next results are the has keyword
+// followed by a key, e.g. has labels.region
+func (p *parser) isHasOperation() bool {
+	if p.position+1 >= len(p.results) {
+		return false
+	}
+	keyword, key := p.results[p.position], p.results[p.position+1]
+	if keyword.Type != identifierToken || strings.ToLower(keyword.Value) != hasKeyword {
+		return false
 	}
+	return key.Type == identifierToken || key.Type == stringToken
 }
 
 // tokenize goes through the input string and produces a list of tokens stored
@@ -115,6 +115,93 @@ func TestParse(t *testing.T) {
 				{LValue: "my sub", Operator: InOperator, RValues: []string{"check.subscriptions"}},
 			}},
 		},
+		{
+			name:  "or expression",
+			input: "foo == bar || baz != qux",
+			want: &Selector{Expressions: []Expression{
+				OrExpression{Operands: []Expression{
+					Operation{LValue: "foo", Operator: DoubleEqualSignOperator, RValues: []string{"bar"}},
+					Operation{LValue: "baz", Operator: NotEqualOperator, RValues: []string{"qux"}},
+				}},
+			}},
+		},
+		{
+			name:  "and has precedence over or",
+			input: "a == b || c == d && e == f",
+			want: &Selector{Expressions: []Expression{
+				OrExpression{Operands: []Expression{
+					Operation{LValue: "a", Operator: DoubleEqualSignOperator, RValues: []string{"b"}},
+					AndExpression{Operands: []Expression{
+						Operation{LValue: "c", Operator: DoubleEqualSignOperator, RValues: []string{"d"}},
+						Operation{LValue: "e", Operator: DoubleEqualSignOperator, RValues: []string{"f"}},
+					}},
+				}},
+			}},
+		},
+		{
+			name:  "parentheses and negation",
+			input: "a == b && !(c == d || has e)",
+			want: &Selector{
+				Operations: []Operation{
+					{LValue: "a", Operator: DoubleEqualSignOperator, RValues: []string{"b"}},
+				},
+				Expressions: []Expression{
+					NotExpression{Operand: OrExpression{Operands: []Expression{
+						Operation{LValue: "c", Operator: DoubleEqualSignOperator, RValues: []string{"d"}},
+						Operation{LValue: "e", Operator: HasOperator},
+					}}},
+				},
+			},
+		},
+		{
+			name:  "parenthesized conjunction is flattened",
+			input: "(a == b && c == d) && has e",
+			want: &Selector{Operations: []Operation{
+				{LValue: "a", Operator: DoubleEqualSignOperator, RValues: []string{"b"}},
+				{LValue: "c", Operator: DoubleEqualSignOperator, RValues: []string{"d"}},
+				{LValue: "e", Operator: HasOperator},
+			}},
+		},
+		{
+			name:  "numeric comparison",
+			input: "check.status >= 2 && check.occurrences < 10.5",
+			want: &Selector{Operations: []Operation{
+				{LValue: "check.status", Operator: GreaterThanOrEqualOperator, RValues: []string{"2"}},
+				{LValue: "check.occurrences", Operator: LessThanOperator, RValues: []string{"10.5"}},
+			}},
+		},
+		{
+			name:  "has as a key",
+			input: "has == true",
+			want: &Selector{Operations: []Operation{
+				{LValue: "has", Operator: DoubleEqualSignOperator, RValues: []string{"true"}},
+			}},
+		},
+		{
+			name:    "numeric comparison with a non-numeric value",
+			input:   "check.status > foo",
+			wantErr: true,
+		},
+		{
+			name:    "numeric comparison with an exponent",
+			input:   `check.status > "1e5"`,
+			wantErr: true,
+		},
+		{
+			name:    "unbalanced parentheses",
+			input:   "(a == b || c == d",
+			wantErr: true,
+		},
+		{
+			name:    "unexpected closing parenthesis",
+			input:   "a == b)",
+			wantErr: true,
+		},
+		{
+			name:    "missing operation after '||'",
+			input:   "a == b ||",
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -130,6 +217,22 @@ func TestParse(t *testing.T) {
 	}
 }
 
+func TestParseLabelSelectorExpressions(t *testing.T) {
+	sel, err := ParseLabelSelector("region == us || !has tier")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Expression{
+		OrExpression{Operands: []Expression{
+			Operation{LValue: "region", Operator: DoubleEqualSignOperator, RValues: []string{"us"}, OperationType: OperationTypeLabelSelector},
+			NotExpression{Operand: Operation{LValue: "tier", Operator: HasOperator, OperationType: OperationTypeLabelSelector}},
+		}},
+	}
+	if !reflect.DeepEqual(sel.Expressions, want) {
+		t.Errorf("ParseLabelSelector() = %v, want %v", sel.Expressions, want)
+	}
+}
+
 func TestParserParseValues(t *testing.T) {
 	tests := []struct {
 		name    string
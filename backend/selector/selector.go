@@ -1,17 +1,46 @@
 package selector
 
 import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 )
 
+// NumberPattern is the grammar of the numbers compared by the numeric
+// operators: an optional minus sign, digits and an optional decimal part.
+// The stores that evaluate the selectors must use the same grammar, so that
+// a selector matches the same resources wherever it is evaluated.
+const NumberPattern = `^-?[0-9]+([.][0-9]+)?$`
+
+var numberRegexp = regexp.MustCompile(NumberPattern)
+
+// ParseNumber parses a number of the NumberPattern grammar.
+func ParseNumber(s string) (float64, error) {
+	if !numberRegexp.MatchString(s) {
+		return 0, fmt.Errorf("invalid number: %q", s)
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil && !errors.Is(err, strconv.ErrRange) {
+		return 0, err
+	}
+	return f, nil
+}
+
 // Selector represents a field or label selector that declares one or more
 // operations.
 type Selector struct {
 	Operations []Operation
+
+	// Expressions are the expressions of the selector that can't be
+	// represented as a single operation, e.g. disjunctions and negations.
+	// They are evaluated in conjunction with Operations.
+	Expressions []Expression
 }
 
 // Matches returns the logical intersection of the evaluations of each of the
-// operations in s.
+// operations and expressions in s.
 func (s *Selector) Matches(set map[string]string) bool {
 	for i := range s.Operations {
 		if matches := matches(s.Operations[i], set); !matches {
@@ -19,6 +48,12 @@ func (s *Selector) Matches(set map[string]string) bool {
 		}
 	}
 
+	for i := range s.Expressions {
+		if !s.Expressions[i].Matches(set) {
+			return false
+		}
+	}
+
 	return true
 }
 
@@ -65,6 +100,14 @@ func matches(r Operation, set map[string]string) bool {
 		//  Make sure the set's value for the operation's l-value matches
 		//  the operation r-values
 		return matchesValue(set[r.LValue], r.RValues)
+	case GreaterThanOperator, GreaterThanOrEqualOperator, LessThanOperator, LessThanOrEqualOperator:
+		// Make sure the r-value set has the specified l-value
+		if !hasKey(set, r.LValue) || len(r.RValues) != 1 {
+			return false
+		}
+		return compareNumbers(set[r.LValue], r.Operator, r.RValues[0])
+	case HasOperator:
+		return hasKey(set, r.LValue)
 	default:
 		return false
 	}
@@ -98,6 +141,31 @@ func matchesValue(value string, values []string) bool {
 	return false
 }
 
+// compareNumbers compares the set value with the operation value, both parsed
+// as numbers. Values that are not numbers never match.
+func compareNumbers(value string, operator Operator, operand string) bool {
+	lhs, err := ParseNumber(value)
+	if err != nil {
+		return false
+	}
+	rhs, err := ParseNumber(operand)
+	if err != nil {
+		return false
+	}
+	switch operator {
+	case GreaterThanOperator:
+		return lhs > rhs
+	case GreaterThanOrEqualOperator:
+		return lhs >= rhs
+	case LessThanOperator:
+		return lhs < rhs
+	case LessThanOrEqualOperator:
+		return lhs <= rhs
+	default:
+		return false
+	}
+}
+
 // hasKeysInValues determines if the values contains an actual key of the set
 func hasKeysInValues(set map[string]string, values []string) bool {
 	// We only support a single value in the array
@@ -129,6 +197,24 @@ func Merge(selectors ...*Selector) *Selector {
 			continue
 		}
 		selector.Operations = append(selector.Operations, s.Operations...)
+		selector.Expressions = append(selector.Expressions, s.Expressions...)
 	}
 	return &selector
 }
+
+// Join joins many selector requirements into a single requirement that is
+// the conjunction of all of them. Each requirement is parenthesized so that
+// the || operator it may contain does not bind to the other requirements.
+func Join(requirements []string) string {
+	if len(requirements) == 1 {
+		return requirements[0]
+	}
+	parenthesized := make([]string, 0, len(requirements))
+	for _, requirement := range requirements {
+		if strings.TrimSpace(requirement) == "" {
+			continue
+		}
+		parenthesized = append(parenthesized, "("+requirement+")")
+	}
+	return strings.Join(parenthesized, " && ")
+}
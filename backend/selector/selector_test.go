@@ -162,6 +162,72 @@ func TestSelector_Matches(t *testing.T) {
 			set:   nil,
 			want:  false,
 		},
+		{
+			name:  "or matches",
+			input: "object.name == bar || object.name == foo",
+			set:   map[string]string{"object.name": "foo"},
+			want:  true,
+		},
+		{
+			name:  "or doesn't match",
+			input: "object.name == bar || object.name == baz",
+			set:   map[string]string{"object.name": "foo"},
+			want:  false,
+		},
+		{
+			name:  "not matches",
+			input: "!(object.name == bar)",
+			set:   map[string]string{"object.name": "foo"},
+			want:  true,
+		},
+		{
+			name:  "operations and expressions are intersected",
+			input: "object.name == foo && (object.status > 1 || object.publish == true)",
+			set:   map[string]string{"object.name": "foo", "object.status": "0", "object.publish": "false"},
+			want:  false,
+		},
+		{
+			name:  "greater than matches",
+			input: "object.status > 1",
+			set:   map[string]string{"object.status": "2"},
+			want:  true,
+		},
+		{
+			name:  "numeric comparison is not lexicographic",
+			input: "object.status < 10",
+			set:   map[string]string{"object.status": "9"},
+			want:  true,
+		},
+		{
+			name:  "less than or equal with a decimal",
+			input: "object.ratio <= -0.5",
+			set:   map[string]string{"object.ratio": "-0.5"},
+			want:  true,
+		},
+		{
+			name:  "numeric comparison with a non-numeric value",
+			input: "object.status >= 0",
+			set:   map[string]string{"object.status": "ok"},
+			want:  false,
+		},
+		{
+			name:  "numeric comparison with a missing key",
+			input: "object.status >= 0",
+			set:   nil,
+			want:  false,
+		},
+		{
+			name:  "has matches an empty value",
+			input: "has object.region",
+			set:   map[string]string{"object.region": ""},
+			want:  true,
+		},
+		{
+			name:  "not has",
+			input: "!has object.region",
+			set:   map[string]string{"object.name": "foo"},
+			want:  true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -176,3 +242,34 @@ func TestSelector_Matches(t *testing.T) {
 		})
 	}
 }
+
+func TestJoin(t *testing.T) {
+	tests := []struct {
+		name         string
+		requirements []string
+		want         string
+	}{
+		{
+			name:         "no requirement",
+			requirements: nil,
+			want:         "",
+		},
+		{
+			name:         "single requirement",
+			requirements: []string{"a == b || c == d"},
+			want:         "a == b || c == d",
+		},
+		{
+			name:         "many requirements",
+			requirements: []string{"a == b || c == d", "e == f"},
+			want:         "(a == b || c == d) && (e == f)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Join(tt.requirements); got != tt.want {
+				t.Errorf("Join() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
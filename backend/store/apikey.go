@@ -0,0 +1,21 @@
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// APIKeyUsageStore keeps the last time the API keys were used, apart from
+// the keys themselves so that using a key does not update its resource.
+type APIKeyUsageStore interface {
+	// RecordAPIKeyUse records that the API key name was used at t. The
+	// record may be skipped if the key was used recently.
+	RecordAPIKeyUse(ctx context.Context, name string, t time.Time) error
+
+	// GetAPIKeyUses returns the last time each API key was used, by name.
+	GetAPIKeyUses(ctx context.Context) (map[string]time.Time, error)
+
+	// PruneAPIKeyUses deletes the records of the API keys that are not in
+	// keep, and returns how many were deleted.
+	PruneAPIKeyUses(ctx context.Context, keep []string) (int64, error)
+}
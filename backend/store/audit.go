@@ -0,0 +1,92 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// AuditEntry records a change made to a resource through the API.
+type AuditEntry struct {
+	// ID is the sequence number of the entry, set by the AuditStore.
+	ID int64 `json:"id,omitempty"`
+
+	// Time is the time of the change.
+	Time time.Time `json:"time"`
+
+	// User is the name of the user who made the change.
+	User string `json:"user"`
+
+	// Groups are the groups of the user.
+	Groups []string `json:"groups,omitempty"`
+
+	// APIKey is true if the user was authenticated with an API key.
+	APIKey bool `json:"api_key,omitempty"`
+
+	// SourceIP is the IP address the request came from.
+	SourceIP string `json:"source_ip,omitempty"`
+
+	// Verb is either create, update, patch or delete.
+	Verb string `json:"verb"`
+
+	// APIVersion is the API version of the resource.
+	APIVersion string `json:"api_version"`
+
+	// Type is the type of the resource.
+	Type string `json:"type"`
+
+	// Namespace is the namespace of the resource, if any.
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name is the name of the resource.
+	Name string `json:"name"`
+
+	// Before is the resource before the change, if it existed.
+	Before json.RawMessage `json:"before,omitempty"`
+
+	// After is the resource after the change, unless it was deleted.
+	After json.RawMessage `json:"after,omitempty"`
+
+	// Diff is the JSON merge patch from Before to After, when the resource was
+	// updated or patched.
+	Diff json.RawMessage `json:"diff,omitempty"`
+}
+
+// AuditQuery selects audit entries. The zero value of each field matches
+// every entry.
+type AuditQuery struct {
+	// User selects the entries of a user.
+	User string
+
+	// Type selects the entries of a resource type.
+	Type string
+
+	// Namespace selects the entries of a namespace.
+	Namespace string
+
+	// Name selects the entries of the resources with this name.
+	Name string
+
+	// Since selects the entries recorded at or after this time.
+	Since time.Time
+
+	// Until selects the entries recorded before this time.
+	Until time.Time
+
+	// Limit is the maximum number of entries returned.
+	Limit int
+}
+
+// AuditStore provides methods for recording and querying the audit log.
+type AuditStore interface {
+	// RecordAuditEntry records an entry in the audit log.
+	RecordAuditEntry(ctx context.Context, entry *AuditEntry) error
+
+	// ListAuditEntries returns the entries selected by the query, the most
+	// recent first.
+	ListAuditEntries(ctx context.Context, query *AuditQuery) ([]*AuditEntry, error)
+
+	// PruneAuditEntries deletes the entries recorded before the given time,
+	// and returns the number of entries deleted.
+	PruneAuditEntries(ctx context.Context, before time.Time) (int64, error)
+}
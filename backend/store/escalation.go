@@ -0,0 +1,62 @@
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// EscalationIncident is an open incident of an escalation policy, for the
+// event of an entity and a check.
+type EscalationIncident struct {
+	// Namespace is the namespace of the event and of the policy.
+	Namespace string `json:"namespace"`
+
+	// Entity is the name of the entity of the event.
+	Entity string `json:"entity"`
+
+	// Check is the name of the check of the event.
+	Check string `json:"check"`
+
+	// Policy is the name of the escalation policy.
+	Policy string `json:"policy"`
+
+	// Step is the index of the next step of the policy to run.
+	Step uint32 `json:"step"`
+
+	// Repeats is the number of times the steps of the policy were repeated.
+	Repeats uint32 `json:"repeats"`
+
+	// DueAt is the time the incident is due to be evaluated again.
+	DueAt time.Time `json:"due_at"`
+
+	// OpenedAt is the time the incident was opened.
+	OpenedAt time.Time `json:"opened_at"`
+}
+
+// EscalationStore provides methods for tracking the incidents of escalation
+// policies. The incidents are durable timers: they are claimed by a single
+// backend once they are due.
+type EscalationStore interface {
+	// OpenIncident opens an incident, unless an incident of the same policy
+	// is already open for the event. It returns whether the incident was
+	// opened.
+	OpenIncident(ctx context.Context, incident *EscalationIncident) (bool, error)
+
+	// CloseIncidents closes the incidents of every policy for the event of
+	// the given entity and check.
+	CloseIncidents(ctx context.Context, namespace, entity, check string) error
+
+	// ClaimDueIncidents claims at most limit incidents, in all namespaces,
+	// that are due at the given time. The claimed incidents are not due again
+	// before the lease expires, unless they are advanced.
+	ClaimDueIncidents(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*EscalationIncident, error)
+
+	// AdvanceIncident moves a claimed incident to the given step, repeats
+	// and due time, unless its step or repeats changed since it was claimed.
+	// It returns whether the incident was advanced, so that a step is only
+	// run by the backend which advanced the incident past it.
+	AdvanceIncident(ctx context.Context, incident *EscalationIncident, step, repeats uint32, dueAt time.Time) (bool, error)
+
+	// CloseIncident closes an incident.
+	CloseIncident(ctx context.Context, incident *EscalationIncident) error
+}
@@ -0,0 +1,48 @@
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// EventHistoryEntry is a status change of an event, as recorded in the event
+// history.
+type EventHistoryEntry struct {
+	// Entity is the name of the entity of the event.
+	Entity string `json:"entity"`
+
+	// Check is the name of the check of the event.
+	Check string `json:"check"`
+
+	// Status is the check status the event changed to.
+	Status uint32 `json:"status"`
+
+	// State is the check state of the event, e.g. passing or failing.
+	State string `json:"state"`
+
+	// Output is the check output of the event.
+	Output string `json:"output"`
+
+	// Executed is the time the check was executed, in seconds since the
+	// epoch.
+	Executed int64 `json:"executed"`
+
+	// Timestamp is the time of the event, in seconds since the epoch.
+	Timestamp int64 `json:"timestamp"`
+}
+
+// EventHistoryStore provides methods for querying and pruning the status
+// history of events. It is implemented by event stores that record history,
+// and can be discovered with a type assertion on an EventStore.
+type EventHistoryStore interface {
+	// GetEventHistory returns the status changes of the event identified by
+	// the given entity and check, within the namespace stored in ctx, that
+	// occurred between start and end, ordered from the oldest to the most
+	// recent. A zero start or end time leaves that side of the range open.
+	GetEventHistory(ctx context.Context, entity, check string, start, end time.Time) ([]*EventHistoryEntry, error)
+
+	// PruneEventHistory deletes the history entries, in all namespaces, that
+	// occurred before the given time. It returns the number of deleted
+	// entries.
+	PruneEventHistory(ctx context.Context, before time.Time) (int64, error)
+}
@@ -432,3 +432,19 @@ func (e *EventStore) GetKeepaliveGaugesByNamespace(ctx context.Context) (map[str
 	}
 	return getter.GetKeepaliveGaugesByNamespace(ctx)
 }
+
+func (e *EventStore) GetEventHistory(ctx context.Context, entity, check string, start, end time.Time) ([]*store.EventHistoryEntry, error) {
+	history, ok := e.backingStore.(store.EventHistoryStore)
+	if !ok {
+		return nil, errors.New("event history not supported")
+	}
+	return history.GetEventHistory(ctx, entity, check, start, end)
+}
+
+func (e *EventStore) PruneEventHistory(ctx context.Context, before time.Time) (int64, error) {
+	history, ok := e.backingStore.(store.EventHistoryStore)
+	if !ok {
+		return 0, errors.New("event history not supported")
+	}
+	return history.PruneEventHistory(ctx, before)
+}
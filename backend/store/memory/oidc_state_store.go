@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+var _ store.OIDCStateStore = &OIDCStateStore{}
+
+type oidcState struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// OIDCStateStore keeps the state of the OIDC logins in memory. It is only
+// suitable for a single backend, and for testing.
+type OIDCStateStore struct {
+	mu     sync.Mutex
+	states map[[2]string]oidcState
+}
+
+// NewOIDCStateStore creates a new OIDCStateStore.
+func NewOIDCStateStore() *OIDCStateStore {
+	return &OIDCStateStore{states: make(map[[2]string]oidcState)}
+}
+
+// PutOIDCState creates or updates a state.
+func (s *OIDCStateStore) PutOIDCState(ctx context.Context, kind, id string, value []byte, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states[[2]string{kind, id}] = oidcState{value: value, expiresAt: expiresAt}
+	return nil
+}
+
+// GetOIDCState returns a state.
+func (s *OIDCStateStore) GetOIDCState(ctx context.Context, kind, id string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.get(kind, id)
+}
+
+// TakeOIDCState deletes a state and returns it.
+func (s *OIDCStateStore) TakeOIDCState(ctx context.Context, kind, id string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, err := s.get(kind, id)
+	delete(s.states, [2]string{kind, id})
+	return value, err
+}
+
+// DeleteOIDCState deletes a state.
+func (s *OIDCStateStore) DeleteOIDCState(ctx context.Context, kind, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.states, [2]string{kind, id})
+	return nil
+}
+
+// PruneOIDCStates deletes the states that expired before t.
+func (s *OIDCStateStore) PruneOIDCStates(ctx context.Context, t time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var pruned int64
+	for key, state := range s.states {
+		if state.expiresAt.Before(t) {
+			delete(s.states, key)
+			pruned++
+		}
+	}
+	return pruned, nil
+}
+
+func (s *OIDCStateStore) get(kind, id string) ([]byte, error) {
+	state, ok := s.states[[2]string{kind, id}]
+	if !ok || !time.Now().Before(state.expiresAt) {
+		return nil, &store.ErrNotFound{Key: kind + "/" + id}
+	}
+	return state.value, nil
+}
@@ -0,0 +1,45 @@
+package store
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// OIDCSession is the kind of the states that are the sessions of the
+	// users logged in with an OIDC provider.
+	OIDCSession = "session"
+
+	// OIDCLogin is the kind of the states that are the logins in progress
+	// with an OIDC provider, by state parameter.
+	OIDCLogin = "login"
+
+	// OIDCCode is the kind of the states that are the tokens of the completed
+	// logins, until clients redeem them with their login code.
+	OIDCCode = "code"
+)
+
+// OIDCStateStore keeps the state of the logins with the OIDC authentication
+// providers, so that it is shared by the backends of a cluster and survives
+// their restarts and the updates of the providers.
+type OIDCStateStore interface {
+	// PutOIDCState creates or updates the state id of the given kind, which
+	// expires at expiresAt.
+	PutOIDCState(ctx context.Context, kind, id string, value []byte, expiresAt time.Time) error
+
+	// GetOIDCState returns the state id of the given kind, or ErrNotFound if
+	// it doesn't exist or has expired.
+	GetOIDCState(ctx context.Context, kind, id string) ([]byte, error)
+
+	// TakeOIDCState deletes the state id of the given kind and returns it, or
+	// ErrNotFound if it doesn't exist or has expired, so that the state is
+	// only used once.
+	TakeOIDCState(ctx context.Context, kind, id string) ([]byte, error)
+
+	// DeleteOIDCState deletes the state id of the given kind.
+	DeleteOIDCState(ctx context.Context, kind, id string) error
+
+	// PruneOIDCStates deletes the states that expired before t, and returns
+	// how many were deleted.
+	PruneOIDCStates(ctx context.Context, t time.Time) (int64, error)
+}
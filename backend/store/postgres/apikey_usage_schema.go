@@ -0,0 +1,26 @@
+package postgres
+
+// Migration 36
+const apiKeyUsageSchema = `
+CREATE TABLE IF NOT EXISTS api_key_uses (
+	name		text PRIMARY KEY,
+	last_used	timestamptz NOT NULL
+);
+`
+
+// apiKeyRecordUse records the use of an API key, unless it was already
+// recorded less than a minute before, so that a busy key is not written by
+// every request.
+const apiKeyRecordUse = `
+INSERT INTO api_key_uses AS uses (name, last_used) VALUES ($1, $2)
+ON CONFLICT (name) DO UPDATE SET last_used = $2
+WHERE uses.last_used < $2::timestamptz - INTERVAL '1 minute';
+`
+
+const apiKeyGetUses = `
+SELECT name, last_used FROM api_key_uses;
+`
+
+const apiKeyPruneUses = `
+DELETE FROM api_key_uses WHERE NOT (name = ANY($1));
+`
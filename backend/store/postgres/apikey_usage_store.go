@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+var _ store.APIKeyUsageStore = &APIKeyUsageStore{}
+
+// apiKeyUseInterval is the minimum interval between two records of the use
+// of an API key.
+const apiKeyUseInterval = time.Minute
+
+// APIKeyUsageStore keeps the last time the API keys were used in the
+// api_key_uses table.
+type APIKeyUsageStore struct {
+	db DBI
+
+	// recorded is the last time the use of each API key was recorded by this
+	// store, so that the uses of a busy key don't reach the database on every
+	// request.
+	recorded   map[string]time.Time
+	recordedMu sync.Mutex
+}
+
+// NewAPIKeyUsageStore creates a new APIKeyUsageStore.
+func NewAPIKeyUsageStore(db DBI) *APIKeyUsageStore {
+	return &APIKeyUsageStore{db: db, recorded: make(map[string]time.Time)}
+}
+
+// RecordAPIKeyUse records that the API key name was used at t, unless its use
+// was recorded less than a minute before.
+func (s *APIKeyUsageStore) RecordAPIKeyUse(ctx context.Context, name string, t time.Time) error {
+	s.recordedMu.Lock()
+	if last, ok := s.recorded[name]; ok && t.Sub(last) < apiKeyUseInterval {
+		s.recordedMu.Unlock()
+		return nil
+	}
+	s.recorded[name] = t
+	s.recordedMu.Unlock()
+	if _, err := s.db.Exec(ctx, apiKeyRecordUse, name, t); err != nil {
+		s.recordedMu.Lock()
+		delete(s.recorded, name)
+		s.recordedMu.Unlock()
+		return &store.ErrInternal{Message: fmt.Sprintf("couldn't record api key use: %s", err)}
+	}
+	return nil
+}
+
+// GetAPIKeyUses returns the last time each API key was used.
+func (s *APIKeyUsageStore) GetAPIKeyUses(ctx context.Context) (map[string]time.Time, error) {
+	rows, err := s.db.Query(ctx, apiKeyGetUses)
+	if err != nil {
+		return nil, &store.ErrInternal{Message: fmt.Sprintf("couldn't get api key uses: %s", err)}
+	}
+	defer rows.Close()
+	uses := make(map[string]time.Time)
+	for rows.Next() {
+		var (
+			name     string
+			lastUsed time.Time
+		)
+		if err := rows.Scan(&name, &lastUsed); err != nil {
+			return nil, &store.ErrInternal{Message: fmt.Sprintf("couldn't get api key uses: %s", err)}
+		}
+		uses[name] = lastUsed
+	}
+	if err := rows.Err(); err != nil {
+		return nil, &store.ErrInternal{Message: fmt.Sprintf("couldn't get api key uses: %s", err)}
+	}
+	return uses, nil
+}
+
+// PruneAPIKeyUses deletes the records of the API keys that are not in keep.
+func (s *APIKeyUsageStore) PruneAPIKeyUses(ctx context.Context, keep []string) (int64, error) {
+	if keep == nil {
+		keep = []string{}
+	}
+	tag, err := s.db.Exec(ctx, apiKeyPruneUses, keep)
+	if err != nil {
+		return 0, &store.ErrInternal{Message: fmt.Sprintf("couldn't prune api key uses: %s", err)}
+	}
+	kept := make(map[string]bool, len(keep))
+	for _, name := range keep {
+		kept[name] = true
+	}
+	s.recordedMu.Lock()
+	for name := range s.recorded {
+		if !kept[name] {
+			delete(s.recorded, name)
+		}
+	}
+	s.recordedMu.Unlock()
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestAPIKeyUsageStore(t *testing.T) {
+	withPostgres(t, func(ctx context.Context, db *pgxpool.Pool, dsn string) {
+		s := NewAPIKeyUsageStore(db)
+
+		now := time.Now().Truncate(time.Second)
+		if err := s.RecordAPIKeyUse(ctx, "ci", now); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.RecordAPIKeyUse(ctx, "deploy", now); err != nil {
+			t.Fatal(err)
+		}
+
+		// A use less than a minute later is not recorded
+		if err := s.RecordAPIKeyUse(ctx, "ci", now.Add(30*time.Second)); err != nil {
+			t.Fatal(err)
+		}
+		uses, err := s.GetAPIKeyUses(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := uses["ci"]; !got.Equal(now) {
+			t.Fatalf("expected last use at %s, got %s", now, got)
+		}
+
+		// A later use is
+		if err := s.RecordAPIKeyUse(ctx, "ci", now.Add(2*time.Minute)); err != nil {
+			t.Fatal(err)
+		}
+		uses, err = s.GetAPIKeyUses(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := uses["ci"]; !got.Equal(now.Add(2 * time.Minute)) {
+			t.Fatalf("expected last use at %s, got %s", now.Add(2*time.Minute), got)
+		}
+
+		pruned, err := s.PruneAPIKeyUses(ctx, []string{"ci"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pruned != 1 {
+			t.Fatalf("expected 1 use pruned, got %d", pruned)
+		}
+		uses, err = s.GetAPIKeyUses(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := uses["deploy"]; ok || len(uses) != 1 {
+			t.Fatalf("expected only the uses of ci, got %v", uses)
+		}
+	})
+}
+
+// execCounter is a DBI that counts the statements executed.
+type execCounter struct {
+	DBI
+	execs int
+}
+
+func (c *execCounter) Exec(context.Context, string, ...any) (pgconn.CommandTag, error) {
+	c.execs++
+	return pgconn.CommandTag{}, nil
+}
+
+func TestAPIKeyUsageStoreThrottle(t *testing.T) {
+	db := &execCounter{}
+	s := NewAPIKeyUsageStore(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	// The uses of a key within a minute only reach the database once
+	for i := 0; i < 10; i++ {
+		if err := s.RecordAPIKeyUse(ctx, "ci", now.Add(time.Duration(i)*time.Second)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.RecordAPIKeyUse(ctx, "deploy", now); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := db.execs, 2; got != want {
+		t.Fatalf("bad statements: got %d, want %d", got, want)
+	}
+
+	if err := s.RecordAPIKeyUse(ctx, "ci", now.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := db.execs, 3; got != want {
+		t.Fatalf("bad statements: got %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,48 @@
+package postgres
+
+// Migration 33
+const auditLogSchema = `
+CREATE TABLE IF NOT EXISTS audit_log (
+	id			bigint PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+	time		timestamptz NOT NULL DEFAULT NOW(),
+	username	text NOT NULL,
+	groups		text[] NOT NULL DEFAULT '{}',
+	api_key		boolean NOT NULL DEFAULT false,
+	source_ip	text NOT NULL DEFAULT '',
+	verb		text NOT NULL,
+	api_version	text NOT NULL,
+	type		text NOT NULL,
+	namespace	text NOT NULL DEFAULT '',
+	name		text NOT NULL,
+	before		jsonb,
+	after		jsonb,
+	diff		jsonb
+);
+CREATE INDEX ON audit_log ( time );
+CREATE INDEX ON audit_log ( username, time );
+CREATE INDEX ON audit_log ( type, namespace, name, time );
+`
+
+const auditRecordEntry = `
+INSERT INTO audit_log (time, username, groups, api_key, source_ip, verb, api_version, type, namespace, name, before, after, diff)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	RETURNING id;
+`
+
+const auditListEntries = `
+SELECT id, time, username, groups, api_key, source_ip, verb, api_version, type, namespace, name, before, after, diff
+FROM audit_log
+WHERE
+	($1 = '' OR username = $1) AND
+	($2 = '' OR type = $2) AND
+	($3 = '' OR namespace = $3) AND
+	($4 = '' OR name = $4) AND
+	($5::timestamptz IS NULL OR time >= $5) AND
+	($6::timestamptz IS NULL OR time < $6)
+ORDER BY id DESC
+LIMIT $7;
+`
+
+const auditPruneEntries = `
+DELETE FROM audit_log WHERE time < $1;
+`
@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+var _ store.AuditStore = &AuditStore{}
+
+// AuditStore records the audit log in the audit_log table.
+type AuditStore struct {
+	db DBI
+}
+
+// NewAuditStore creates a new AuditStore.
+func NewAuditStore(db DBI) *AuditStore {
+	return &AuditStore{db: db}
+}
+
+// RecordAuditEntry records an entry in the audit log, and sets its ID.
+func (s *AuditStore) RecordAuditEntry(ctx context.Context, entry *store.AuditEntry) error {
+	groups := entry.Groups
+	if groups == nil {
+		groups = []string{}
+	}
+	row := s.db.QueryRow(ctx, auditRecordEntry,
+		entry.Time,
+		entry.User,
+		groups,
+		entry.APIKey,
+		entry.SourceIP,
+		entry.Verb,
+		entry.APIVersion,
+		entry.Type,
+		entry.Namespace,
+		entry.Name,
+		nullJSON(entry.Before),
+		nullJSON(entry.After),
+		nullJSON(entry.Diff),
+	)
+	if err := row.Scan(&entry.ID); err != nil {
+		return &store.ErrInternal{Message: fmt.Sprintf("couldn't record audit entry: %s", err)}
+	}
+	return nil
+}
+
+// ListAuditEntries returns the entries selected by the query, the most recent
+// first.
+func (s *AuditStore) ListAuditEntries(ctx context.Context, query *store.AuditQuery) ([]*store.AuditEntry, error) {
+	limit := sql.NullInt64{Int64: int64(query.Limit), Valid: query.Limit > 0}
+	rows, err := s.db.Query(ctx, auditListEntries,
+		query.User,
+		query.Type,
+		query.Namespace,
+		query.Name,
+		sql.NullTime{Time: query.Since, Valid: !query.Since.IsZero()},
+		sql.NullTime{Time: query.Until, Valid: !query.Until.IsZero()},
+		limit,
+	)
+	if err != nil {
+		return nil, &store.ErrInternal{Message: fmt.Sprintf("couldn't list audit entries: %s", err)}
+	}
+	defer rows.Close()
+	entries := []*store.AuditEntry{}
+	for rows.Next() {
+		var entry store.AuditEntry
+		var before, after, diff []byte
+		err := rows.Scan(
+			&entry.ID,
+			&entry.Time,
+			&entry.User,
+			&entry.Groups,
+			&entry.APIKey,
+			&entry.SourceIP,
+			&entry.Verb,
+			&entry.APIVersion,
+			&entry.Type,
+			&entry.Namespace,
+			&entry.Name,
+			&before,
+			&after,
+			&diff,
+		)
+		if err != nil {
+			return nil, &store.ErrInternal{Message: fmt.Sprintf("error reading audit entries: %s", err)}
+		}
+		entry.Before, entry.After, entry.Diff = before, after, diff
+		entries = append(entries, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, &store.ErrInternal{Message: fmt.Sprintf("error reading audit entries: %s", err)}
+	}
+	return entries, nil
+}
+
+// nullJSON returns nil for an empty JSON document, so that it is stored as
+// NULL.
+func nullJSON(b []byte) interface{} {
+	if len(b) == 0 {
+		return nil
+	}
+	return string(b)
+}
+
+// PruneAuditEntries deletes the entries recorded before the given time.
+func (s *AuditStore) PruneAuditEntries(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := s.db.Exec(ctx, auditPruneEntries, before)
+	if err != nil {
+		return 0, &store.ErrInternal{Message: fmt.Sprintf("couldn't prune audit entries: %s", err)}
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+func TestAuditStore(t *testing.T) {
+	withPostgres(t, func(ctx context.Context, db *pgxpool.Pool, dsn string) {
+		s := NewAuditStore(db)
+		now := time.Now().Truncate(time.Second)
+		entries := []*store.AuditEntry{
+			{
+				Time:       now.Add(-time.Hour),
+				User:       "admin",
+				Groups:     []string{"cluster-admins"},
+				SourceIP:   "127.0.0.1",
+				Verb:       "create",
+				APIVersion: "core/v2",
+				Type:       "CheckConfig",
+				Namespace:  "default",
+				Name:       "cpu",
+				After:      json.RawMessage(`{"command":"check-cpu"}`),
+			},
+			{
+				Time:       now,
+				User:       "ci",
+				APIKey:     true,
+				Verb:       "update",
+				APIVersion: "core/v2",
+				Type:       "CheckConfig",
+				Namespace:  "default",
+				Name:       "cpu",
+				Before:     json.RawMessage(`{"command":"check-cpu"}`),
+				After:      json.RawMessage(`{"command":"check-cpu -w 80"}`),
+				Diff:       json.RawMessage(`{"command":"check-cpu -w 80"}`),
+			},
+		}
+		for _, entry := range entries {
+			if err := s.RecordAuditEntry(ctx, entry); err != nil {
+				t.Fatal(err)
+			}
+			if entry.ID == 0 {
+				t.Fatal("entry ID not set")
+			}
+		}
+
+		// The most recent entries come first
+		got, err := s.ListAuditEntries(ctx, &store.AuditQuery{Type: "CheckConfig", Name: "cpu"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 2 {
+			t.Fatalf("expected 2 entries, got %d", len(got))
+		}
+		if got[0].User != "ci" || !got[0].APIKey || got[0].Verb != "update" {
+			t.Errorf("bad entry: %+v", got[0])
+		}
+		if got[1].Before != nil || got[1].Diff != nil {
+			t.Errorf("bad entry: %+v", got[1])
+		}
+
+		got, err = s.ListAuditEntries(ctx, &store.AuditQuery{User: "admin"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 1 || got[0].Groups[0] != "cluster-admins" {
+			t.Errorf("bad entries: %+v", got)
+		}
+
+		got, err = s.ListAuditEntries(ctx, &store.AuditQuery{Since: now.Add(-time.Minute)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 1 || got[0].User != "ci" {
+			t.Errorf("bad entries: %+v", got)
+		}
+
+		got, err = s.ListAuditEntries(ctx, &store.AuditQuery{Until: now.Add(-time.Minute), Limit: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 1 || got[0].User != "admin" {
+			t.Errorf("bad entries: %+v", got)
+		}
+
+		pruned, err := s.PruneAuditEntries(ctx, now.Add(-time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pruned != 1 {
+			t.Errorf("expected 1 pruned entry, got %d", pruned)
+		}
+	})
+}
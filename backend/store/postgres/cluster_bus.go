@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	// clusterBusChannel is the notification channel of the cluster bus.
+	clusterBusChannel = "sensu_cluster_bus"
+
+	// maxNotifyPayload is the maximum size of the messages sent in the
+	// payload of a notification. Postgres limits the payloads to 8000 bytes.
+	maxNotifyPayload = 7900
+
+	// clusterBusRetention is how long the messages too large for a
+	// notification payload are kept for the backends to read them.
+	clusterBusRetention = time.Minute
+
+	// The payloads of the notifications are prefixed with the kind of
+	// payload: a message, or the ID of a message too large for a payload
+	clusterBusInlinePrefix = "m"
+	clusterBusRefPrefix    = "r"
+)
+
+// ClusterBusTransport carries the messages of a cluster bus between the
+// backends with postgres asynchronous notifications. The messages too large
+// for the payload of a notification are written to the cluster_bus_messages
+// table, and the notification only carries their ID.
+type ClusterBusTransport struct {
+	db  DBI
+	bus *Bus
+}
+
+// NewClusterBusTransport creates a new ClusterBusTransport, receiving the
+// notifications from bus.
+func NewClusterBusTransport(db DBI, bus *Bus) *ClusterBusTransport {
+	return &ClusterBusTransport{db: db, bus: bus}
+}
+
+// Send sends a message to every backend.
+func (t *ClusterBusTransport) Send(ctx context.Context, message []byte) error {
+	channel := ListenChannelName("", clusterBusChannel)
+	if len(message) <= maxNotifyPayload {
+		_, err := t.db.Exec(ctx, clusterBusNotify, channel, clusterBusInlinePrefix+string(message))
+		return err
+	}
+	var id int64
+	if err := t.db.QueryRow(ctx, clusterBusInsertMessage, message).Scan(&id); err != nil {
+		return fmt.Errorf("couldn't write cluster bus message: %w", err)
+	}
+	_, err := t.db.Exec(ctx, clusterBusNotify, channel, clusterBusRefPrefix+strconv.FormatInt(id, 10))
+	return err
+}
+
+// Receive returns the messages sent by every backend, until the context is
+// cancelled. The messages older than the retention are pruned from the
+// cluster_bus_messages table in the meantime.
+func (t *ClusterBusTransport) Receive(ctx context.Context) (<-chan []byte, error) {
+	notifications, err := t.bus.Subscribe(ctx, "", clusterBusChannel)
+	if err != nil {
+		return nil, err
+	}
+	go t.pruneLoop(ctx)
+	messages := make(chan []byte, 100)
+	go func() {
+		defer close(messages)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case notification := <-notifications:
+				message, err := t.readMessage(ctx, notification)
+				if err != nil {
+					logger.WithError(err).Error("couldn't read cluster bus message")
+					continue
+				}
+				select {
+				case messages <- message:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return messages, nil
+}
+
+// pruneLoop deletes the messages older than the retention, until the context
+// is cancelled.
+func (t *ClusterBusTransport) pruneLoop(ctx context.Context) {
+	ticker := time.NewTicker(clusterBusRetention)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := t.prune(ctx); err != nil && ctx.Err() == nil {
+				logger.WithError(err).Warn("couldn't prune cluster bus messages")
+			}
+		}
+	}
+}
+
+// prune deletes the messages older than the retention.
+func (t *ClusterBusTransport) prune(ctx context.Context) error {
+	retention := fmt.Sprintf("%d milliseconds", clusterBusRetention.Milliseconds())
+	_, err := t.db.Exec(ctx, clusterBusPruneMessages, retention)
+	return err
+}
+
+func (t *ClusterBusTransport) readMessage(ctx context.Context, notification *pq.Notification) ([]byte, error) {
+	payload := notification.Extra
+	if len(payload) == 0 {
+		return nil, fmt.Errorf("empty cluster bus notification")
+	}
+	switch prefix, value := payload[:1], payload[1:]; prefix {
+	case clusterBusInlinePrefix:
+		return []byte(value), nil
+	case clusterBusRefPrefix:
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cluster bus message ID: %s", err)
+		}
+		var message []byte
+		if err := t.db.QueryRow(ctx, clusterBusGetMessage, id).Scan(&message); err != nil {
+			return nil, fmt.Errorf("couldn't read cluster bus message %d: %w", id, err)
+		}
+		return message, nil
+	default:
+		return nil, fmt.Errorf("invalid cluster bus notification %q", payload)
+	}
+}
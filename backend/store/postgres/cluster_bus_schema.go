@@ -0,0 +1,23 @@
+package postgres
+
+// Migration 30
+const clusterBusSchema = `
+CREATE TABLE IF NOT EXISTS cluster_bus_messages (
+	id			bigint PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+	created_at	timestamptz NOT NULL DEFAULT NOW(),
+	message		bytea NOT NULL
+);
+CREATE INDEX ON cluster_bus_messages ( created_at );
+`
+
+const clusterBusNotify = `SELECT pg_notify($1::text, $2::text);`
+
+const clusterBusInsertMessage = `
+INSERT INTO cluster_bus_messages (message)
+	VALUES ($1)
+	RETURNING id;
+`
+
+const clusterBusGetMessage = `SELECT message FROM cluster_bus_messages WHERE id = $1;`
+
+const clusterBusPruneMessages = `DELETE FROM cluster_bus_messages WHERE created_at < NOW() - $1::interval;`
@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterBusTransport(t *testing.T) {
+	withPostgres(t, func(ctx context.Context, db *pgxpool.Pool, dsn string) {
+		listener := pq.NewListener(dsn, time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+		t.Cleanup(func() {
+			_ = listener.UnlistenAll()
+			_ = listener.Close()
+		})
+		transport := NewClusterBusTransport(db, NewBus(ctx, listener))
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		messages, err := transport.Receive(ctx)
+		require.NoError(t, err)
+
+		small := []byte(`{"topic":"sensu:event"}`)
+		large := bytes.Repeat([]byte("a"), 3*maxNotifyPayload)
+		require.NoError(t, transport.Send(ctx, small))
+		require.NoError(t, transport.Send(ctx, large))
+
+		for _, want := range [][]byte{small, large} {
+			select {
+			case got := <-messages:
+				assert.Equal(t, want, got)
+			case <-time.After(10 * time.Second):
+				t.Fatal("no message received")
+			}
+		}
+
+		// Only the large message was written to the table
+		var count int
+		require.NoError(t, db.QueryRow(ctx, "SELECT count(*) FROM cluster_bus_messages").Scan(&count))
+		assert.Equal(t, 1, count)
+
+		// The messages are only pruned once they are older than the retention
+		require.NoError(t, transport.prune(ctx))
+		require.NoError(t, db.QueryRow(ctx, "SELECT count(*) FROM cluster_bus_messages").Scan(&count))
+		assert.Equal(t, 1, count)
+		_, err = db.Exec(ctx, "UPDATE cluster_bus_messages SET created_at = now() - interval '1 hour'")
+		require.NoError(t, err)
+		require.NoError(t, transport.prune(ctx))
+		require.NoError(t, db.QueryRow(ctx, "SELECT count(*) FROM cluster_bus_messages").Scan(&count))
+		assert.Equal(t, 0, count)
+
+		cancel()
+		select {
+		case _, ok := <-messages:
+			assert.False(t, ok)
+		case <-time.After(10 * time.Second):
+			t.Fatal("messages not closed")
+		}
+	})
+}
+
+func TestClusterBusTransportReadMessage(t *testing.T) {
+	transport := NewClusterBusTransport(nil, nil)
+	message, err := transport.readMessage(context.Background(), &pq.Notification{Extra: `m{"topic":"sensu:event"}`})
+	require.NoError(t, err)
+	assert.Equal(t, []byte(`{"topic":"sensu:event"}`), message)
+
+	for _, payload := range []string{"", "x{}", "rfoo"} {
+		_, err := transport.readMessage(context.Background(), &pq.Notification{Extra: payload})
+		assert.Error(t, err, payload)
+	}
+}
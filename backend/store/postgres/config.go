@@ -1,7 +1,15 @@
 package postgres
 
+import "time"
+
 type Config struct {
-	DSN               string
-	MaxTPS            int
-	DisableEventCache bool
+	DSN                   string
+	MaxTPS                int
+	DisableEventCache     bool
+	EventHistoryRetention time.Duration
+
+	// ConfigHistoryMaxRevisions and ConfigHistoryRetention bound the
+	// revisions kept of each configuration resource, 0 disables a bound.
+	ConfigHistoryMaxRevisions int
+	ConfigHistoryRetention    time.Duration
 }
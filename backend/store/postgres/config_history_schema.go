@@ -0,0 +1,86 @@
+package postgres
+
+// Migration 34
+//
+// The revisions are recorded by a trigger, so that every write of the
+// configuration table is recorded, whatever the query. The live resources
+// start with a first revision.
+const configHistorySchema = `
+CREATE TABLE IF NOT EXISTS configuration_history (
+	id			bigint PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+	api_version	text NOT NULL,
+	api_type	text NOT NULL,
+	namespace	text NOT NULL,
+	name		text NOT NULL,
+	revision	bigint NOT NULL,
+	operation	text NOT NULL,
+	resource	jsonb NOT NULL,
+	time		timestamptz NOT NULL DEFAULT NOW(),
+	CONSTRAINT configuration_history_unique UNIQUE (api_version, api_type, namespace, name, revision)
+);
+CREATE INDEX ON configuration_history ( time );
+
+CREATE OR REPLACE FUNCTION record_configuration_history()
+RETURNS TRIGGER AS $$
+DECLARE
+	op text;
+	res jsonb;
+BEGIN
+	IF TG_OP = 'INSERT' THEN
+		op := 'create';
+		res := NEW.resource;
+	ELSIF isfinite(NEW.deleted_at) AND NOT isfinite(OLD.deleted_at) THEN
+		op := 'delete';
+		res := OLD.resource;
+	ELSIF NOT isfinite(NEW.deleted_at) AND NEW.resource IS DISTINCT FROM OLD.resource THEN
+		op := 'update';
+		res := NEW.resource;
+	ELSE
+		RETURN NULL;
+	END IF;
+	INSERT INTO configuration_history (api_version, api_type, namespace, name, revision, operation, resource)
+		SELECT NEW.api_version, NEW.api_type, NEW.namespace, NEW.name, COALESCE(MAX(revision), 0) + 1, op, res
+		FROM configuration_history
+		WHERE api_version = NEW.api_version AND api_type = NEW.api_type AND namespace = NEW.namespace AND name = NEW.name;
+	RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER configuration_history AFTER INSERT OR UPDATE
+	ON configuration FOR EACH ROW EXECUTE PROCEDURE
+	record_configuration_history();
+
+INSERT INTO configuration_history (api_version, api_type, namespace, name, revision, operation, resource, time)
+	SELECT api_version, api_type, namespace, name, 1, 'create', resource, updated_at
+	FROM configuration
+	WHERE NOT isfinite(deleted_at);
+`
+
+const configHistoryListRevisions = `
+SELECT revision, operation, time, resource
+FROM configuration_history
+WHERE api_version = $1 AND api_type = $2 AND namespace = $3 AND name = $4
+ORDER BY revision DESC;
+`
+
+const configHistoryGetRevision = `
+SELECT revision, operation, time, resource
+FROM configuration_history
+WHERE api_version = $1 AND api_type = $2 AND namespace = $3 AND name = $4 AND revision = $5;
+`
+
+// The most recent revision of a resource is only pruned once the resource is
+// deleted.
+const configHistoryPruneRevisions = `
+WITH ranked AS (
+	SELECT id, operation, time, row_number() OVER (
+		PARTITION BY api_version, api_type, namespace, name ORDER BY revision DESC
+	) AS rank
+	FROM configuration_history
+)
+DELETE FROM configuration_history
+USING ranked
+WHERE configuration_history.id = ranked.id
+	AND (ranked.rank > 1 OR ranked.operation = 'delete')
+	AND (($1::integer > 0 AND ranked.rank > $1::integer) OR ($2::timestamptz IS NOT NULL AND ranked.time < $2));
+`
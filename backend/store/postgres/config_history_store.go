@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+var _ storev2.ConfigHistoryStore = &ConfigHistoryStore{}
+
+// ConfigHistoryStore provides access to the configuration_history table, in
+// which the writes of the configuration table are recorded.
+type ConfigHistoryStore struct {
+	db DBI
+}
+
+// NewConfigHistoryStore creates a new ConfigHistoryStore.
+func NewConfigHistoryStore(db DBI) *ConfigHistoryStore {
+	return &ConfigHistoryStore{db: db}
+}
+
+// ListRevisions returns the revisions of a resource, most recent first.
+func (s *ConfigHistoryStore) ListRevisions(ctx context.Context, req storev2.ResourceRequest) ([]*storev2.Revision, error) {
+	if err := req.Validate(); err != nil {
+		return nil, &store.ErrNotValid{Err: err}
+	}
+	rows, err := s.db.Query(ctx, configHistoryListRevisions, req.APIVersion, req.Type, req.Namespace, req.Name)
+	if err != nil {
+		return nil, &store.ErrInternal{Message: fmt.Sprintf("couldn't list revisions: %s", err)}
+	}
+	defer rows.Close()
+	revisions := []*storev2.Revision{}
+	for rows.Next() {
+		var revision storev2.Revision
+		var resource []byte
+		if err := rows.Scan(&revision.Revision, &revision.Operation, &revision.Time, &resource); err != nil {
+			return nil, &store.ErrInternal{Message: fmt.Sprintf("error reading revisions: %s", err)}
+		}
+		revision.Resource = resource
+		revisions = append(revisions, &revision)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, &store.ErrInternal{Message: fmt.Sprintf("error reading revisions: %s", err)}
+	}
+	return revisions, nil
+}
+
+// GetRevision returns a revision of a resource.
+func (s *ConfigHistoryStore) GetRevision(ctx context.Context, req storev2.ResourceRequest, number int64) (*storev2.Revision, error) {
+	if err := req.Validate(); err != nil {
+		return nil, &store.ErrNotValid{Err: err}
+	}
+	row := s.db.QueryRow(ctx, configHistoryGetRevision, req.APIVersion, req.Type, req.Namespace, req.Name, number)
+	var revision storev2.Revision
+	var resource []byte
+	if err := row.Scan(&revision.Revision, &revision.Operation, &revision.Time, &resource); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, &store.ErrNotFound{Key: fmt.Sprintf("%s.%s/%s/%s@%d", req.APIVersion, req.Type, req.Namespace, req.Name, number)}
+		}
+		return nil, &store.ErrInternal{Message: fmt.Sprintf("couldn't get revision: %s", err)}
+	}
+	revision.Resource = resource
+	return &revision, nil
+}
+
+// PruneRevisions deletes the revisions beyond maxRevisions per resource, and
+// the revisions recorded before the given time.
+func (s *ConfigHistoryStore) PruneRevisions(ctx context.Context, maxRevisions int, before time.Time) (int64, error) {
+	if maxRevisions <= 0 && before.IsZero() {
+		return 0, nil
+	}
+	tag, err := s.db.Exec(ctx, configHistoryPruneRevisions, maxRevisions, sql.NullTime{Time: before, Valid: !before.IsZero()})
+	if err != nil {
+		return 0, &store.ErrInternal{Message: fmt.Sprintf("couldn't prune revisions: %s", err)}
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+func TestConfigHistoryStore(t *testing.T) {
+	withPostgres(t, func(ctx context.Context, db *pgxpool.Pool, dsn string) {
+		cs := NewConfigStore(db)
+		hs := NewConfigHistoryStore(db)
+
+		asset := corev2.FixtureAsset(assetName)
+		req := storev2.NewResourceRequestFromV2Resource(asset)
+		for _, url := range []string{"https://a", "https://b", "https://c"} {
+			asset.URL = url
+			if err := createOrUpdateAsset(ctx, cs, asset); err != nil {
+				t.Fatal(err)
+			}
+		}
+		// Writing the same resource again doesn't record a revision
+		if err := createOrUpdateAsset(ctx, cs, asset); err != nil {
+			t.Fatal(err)
+		}
+		if err := cs.Delete(ctx, req); err != nil {
+			t.Fatal(err)
+		}
+
+		revisions, err := hs.ListRevisions(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wantOperations := []string{storev2.RevisionDelete, storev2.RevisionUpdate, storev2.RevisionUpdate, storev2.RevisionCreate}
+		if got, want := len(revisions), len(wantOperations); got != want {
+			t.Fatalf("bad number of revisions: got %d, want %d", got, want)
+		}
+		for i, revision := range revisions {
+			if got, want := revision.Revision, int64(len(revisions)-i); got != want {
+				t.Errorf("bad revision number: got %d, want %d", got, want)
+			}
+			if got, want := revision.Operation, wantOperations[i]; got != want {
+				t.Errorf("bad operation of revision %d: got %s, want %s", revision.Revision, got, want)
+			}
+		}
+
+		revision, err := hs.GetRevision(ctx, req, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got corev2.Asset
+		if err := json.Unmarshal(revision.Resource, &got); err != nil {
+			t.Fatal(err)
+		}
+		if got.URL != "https://b" {
+			t.Errorf("bad revision 2: %s", got.URL)
+		}
+
+		if _, err := hs.GetRevision(ctx, req, 10); err == nil {
+			t.Error("expected non-nil error")
+		} else if _, ok := err.(*store.ErrNotFound); !ok {
+			t.Errorf("wanted ErrNotFound, but got %T (%s)", err, err)
+		}
+
+		pruned, err := hs.PruneRevisions(ctx, 2, time.Time{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pruned != 2 {
+			t.Errorf("expected 2 pruned revisions, got %d", pruned)
+		}
+
+		// The deleted resources are pruned entirely by age
+		pruned, err = hs.PruneRevisions(ctx, 0, time.Now().Add(time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pruned != 2 {
+			t.Errorf("expected 2 pruned revisions, got %d", pruned)
+		}
+	})
+}
@@ -0,0 +1,17 @@
+WITH ns AS (
+	SELECT id
+	FROM namespaces
+	WHERE name = $1
+	LIMIT 1
+), last AS (
+	SELECT event_history.status
+	FROM event_history, ns
+	WHERE event_history.namespace = ns.id AND
+	      event_history.entity_name = $2 AND
+	      event_history.check_name = $3
+	ORDER BY event_history.timestamp DESC, event_history.id DESC
+	LIMIT 1
+)
+INSERT INTO event_history ( namespace, entity_name, check_name, status, state, output, executed, timestamp )
+SELECT ns.id, $2, $3, $4, $5, $6, $7, $8 FROM ns
+WHERE NOT EXISTS ( SELECT 1 FROM last WHERE last.status = $4 );
@@ -0,0 +1,54 @@
+package postgres
+
+// Migration 31
+const escalationIncidentsSchema = `
+CREATE TABLE IF NOT EXISTS escalation_incidents (
+	id			bigint PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+	namespace	text NOT NULL,
+	entity_name	text NOT NULL,
+	check_name	text NOT NULL,
+	policy		text NOT NULL,
+	step		bigint NOT NULL DEFAULT 0,
+	repeats		bigint NOT NULL DEFAULT 0,
+	due_at		timestamptz NOT NULL,
+	opened_at	timestamptz NOT NULL DEFAULT NOW(),
+	UNIQUE ( namespace, entity_name, check_name, policy )
+);
+CREATE INDEX ON escalation_incidents ( due_at );
+`
+
+const escalationOpenIncident = `
+INSERT INTO escalation_incidents (namespace, entity_name, check_name, policy, step, repeats, due_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	ON CONFLICT DO NOTHING;
+`
+
+const escalationCloseIncidents = `
+DELETE FROM escalation_incidents
+WHERE namespace = $1 AND entity_name = $2 AND check_name = $3;
+`
+
+const escalationCloseIncident = `
+DELETE FROM escalation_incidents
+WHERE namespace = $1 AND entity_name = $2 AND check_name = $3 AND policy = $4;
+`
+
+const escalationClaimDueIncidents = `
+UPDATE escalation_incidents
+SET due_at = $3
+WHERE id IN (
+	SELECT id FROM escalation_incidents
+	WHERE due_at <= $1
+	ORDER BY due_at
+	LIMIT $2
+	FOR UPDATE SKIP LOCKED
+)
+RETURNING namespace, entity_name, check_name, policy, step, repeats, opened_at;
+`
+
+const escalationAdvanceIncident = `
+UPDATE escalation_incidents
+SET step = $7, repeats = $8, due_at = $9
+WHERE namespace = $1 AND entity_name = $2 AND check_name = $3 AND policy = $4
+	AND step = $5 AND repeats = $6;
+`
@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+var _ store.EscalationStore = &EscalationStore{}
+
+// EscalationStore tracks the incidents of escalation policies in the
+// escalation_incidents table. Incidents are claimed with row locks, so that
+// each due incident is evaluated by a single backend.
+type EscalationStore struct {
+	db DBI
+}
+
+// NewEscalationStore creates a new EscalationStore.
+func NewEscalationStore(db DBI) *EscalationStore {
+	return &EscalationStore{db: db}
+}
+
+// OpenIncident opens an incident, unless it is already open.
+func (s *EscalationStore) OpenIncident(ctx context.Context, incident *store.EscalationIncident) (bool, error) {
+	tag, err := s.db.Exec(ctx, escalationOpenIncident,
+		incident.Namespace,
+		incident.Entity,
+		incident.Check,
+		incident.Policy,
+		int64(incident.Step),
+		int64(incident.Repeats),
+		incident.DueAt,
+	)
+	if err != nil {
+		return false, &store.ErrInternal{Message: fmt.Sprintf("couldn't open incident: %s", err)}
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// CloseIncidents closes the incidents of every policy for an event.
+func (s *EscalationStore) CloseIncidents(ctx context.Context, namespace, entity, check string) error {
+	if _, err := s.db.Exec(ctx, escalationCloseIncidents, namespace, entity, check); err != nil {
+		return &store.ErrInternal{Message: fmt.Sprintf("couldn't close incidents: %s", err)}
+	}
+	return nil
+}
+
+// ClaimDueIncidents claims the incidents due at the given time, pushing their
+// due time to the end of the lease.
+func (s *EscalationStore) ClaimDueIncidents(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*store.EscalationIncident, error) {
+	leaseEnd := now.Add(lease)
+	rows, err := s.db.Query(ctx, escalationClaimDueIncidents, now, limit, leaseEnd)
+	if err != nil {
+		return nil, &store.ErrInternal{Message: fmt.Sprintf("couldn't claim incidents: %s", err)}
+	}
+	defer rows.Close()
+	var incidents []*store.EscalationIncident
+	for rows.Next() {
+		var incident store.EscalationIncident
+		var step, repeats int64
+		if err := rows.Scan(&incident.Namespace, &incident.Entity, &incident.Check, &incident.Policy, &step, &repeats, &incident.OpenedAt); err != nil {
+			return nil, &store.ErrInternal{Message: fmt.Sprintf("error reading incidents: %s", err)}
+		}
+		incident.Step = uint32(step)
+		incident.Repeats = uint32(repeats)
+		incident.DueAt = leaseEnd
+		incidents = append(incidents, &incident)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, &store.ErrInternal{Message: fmt.Sprintf("error reading incidents: %s", err)}
+	}
+	return incidents, nil
+}
+
+// AdvanceIncident moves an incident to the given step, repeats and due time,
+// unless another backend advanced it since it was claimed.
+func (s *EscalationStore) AdvanceIncident(ctx context.Context, incident *store.EscalationIncident, step, repeats uint32, dueAt time.Time) (bool, error) {
+	tag, err := s.db.Exec(ctx, escalationAdvanceIncident,
+		incident.Namespace,
+		incident.Entity,
+		incident.Check,
+		incident.Policy,
+		int64(incident.Step),
+		int64(incident.Repeats),
+		int64(step),
+		int64(repeats),
+		dueAt,
+	)
+	if err != nil {
+		return false, &store.ErrInternal{Message: fmt.Sprintf("couldn't advance incident: %s", err)}
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// CloseIncident closes an incident.
+func (s *EscalationStore) CloseIncident(ctx context.Context, incident *store.EscalationIncident) error {
+	_, err := s.db.Exec(ctx, escalationCloseIncident, incident.Namespace, incident.Entity, incident.Check, incident.Policy)
+	if err != nil {
+		return &store.ErrInternal{Message: fmt.Sprintf("couldn't close incident: %s", err)}
+	}
+	return nil
+}
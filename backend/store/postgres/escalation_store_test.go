@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+func TestEscalationStore(t *testing.T) {
+	withPostgres(t, func(ctx context.Context, db *pgxpool.Pool, dsn string) {
+		s := NewEscalationStore(db)
+		now := time.Now().Truncate(time.Second)
+		incident := &store.EscalationIncident{
+			Namespace: "default",
+			Entity:    "entity1",
+			Check:     "check1",
+			Policy:    "on-call",
+			DueAt:     now,
+		}
+		opened, err := s.OpenIncident(ctx, incident)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !opened {
+			t.Fatal("incident not opened")
+		}
+		if opened, err := s.OpenIncident(ctx, incident); err != nil {
+			t.Fatal(err)
+		} else if opened {
+			t.Fatal("incident opened twice")
+		}
+
+		// The incident is not due yet
+		incidents, err := s.ClaimDueIncidents(ctx, now.Add(-time.Second), 10, time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(incidents) != 0 {
+			t.Fatalf("got %d incidents, want 0", len(incidents))
+		}
+
+		incidents, err = s.ClaimDueIncidents(ctx, now, 10, time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(incidents) != 1 {
+			t.Fatalf("got %d incidents, want 1", len(incidents))
+		}
+		if got := incidents[0]; got.Policy != "on-call" || got.Step != 0 {
+			t.Fatalf("unexpected incident: %+v", got)
+		}
+
+		// The claimed incident is not due again before the end of its lease
+		incidents, err = s.ClaimDueIncidents(ctx, now.Add(30*time.Second), 10, time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(incidents) != 0 {
+			t.Fatalf("got %d incidents, want 0", len(incidents))
+		}
+
+		// The incident is only advanced once from the claimed step
+		if advanced, err := s.AdvanceIncident(ctx, incident, 1, 0, now.Add(10*time.Second)); err != nil {
+			t.Fatal(err)
+		} else if !advanced {
+			t.Fatal("incident not advanced")
+		}
+		if advanced, err := s.AdvanceIncident(ctx, incident, 1, 0, now.Add(10*time.Second)); err != nil {
+			t.Fatal(err)
+		} else if advanced {
+			t.Fatal("incident advanced twice")
+		}
+		incidents, err = s.ClaimDueIncidents(ctx, now.Add(10*time.Second), 10, time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(incidents) != 1 || incidents[0].Step != 1 {
+			t.Fatalf("unexpected incidents: %v", incidents)
+		}
+
+		if err := s.CloseIncidents(ctx, "default", "entity1", "check1"); err != nil {
+			t.Fatal(err)
+		}
+		incidents, err = s.ClaimDueIncidents(ctx, now.Add(time.Hour), 10, time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(incidents) != 0 {
+			t.Fatalf("got %d incidents, want 0", len(incidents))
+		}
+	})
+}
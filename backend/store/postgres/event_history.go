@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+var _ store.EventHistoryStore = &EventStore{}
+
+// recordEventHistory appends the event to the event history, unless the
+// status of the event is the same as the status of the last recorded entry.
+func (e *EventStore) recordEventHistory(ctx context.Context, event *corev2.Event) error {
+	_, err := e.db.Exec(ctx, createEventHistory,
+		event.Entity.Namespace,
+		event.Entity.Name,
+		event.Check.Name,
+		int64(event.Check.Status),
+		event.Check.State,
+		event.Check.Output,
+		event.Check.Executed,
+		event.Timestamp,
+	)
+	if err != nil {
+		return &store.ErrInternal{Message: fmt.Sprintf("couldn't record event history: %s", err)}
+	}
+	return nil
+}
+
+// GetEventHistory returns the status changes of an event between start and
+// end.
+func (e *EventStore) GetEventHistory(ctx context.Context, entity, check string, start, end time.Time) ([]*store.EventHistoryEntry, error) {
+	ns, err := getNamespace(ctx)
+	if err != nil {
+		// Warning: do not wrap this error
+		return nil, err
+	}
+	if entity == "" || check == "" {
+		return nil, &store.ErrNotValid{Err: errors.New("must specify entity and check name")}
+	}
+	if !start.IsZero() && !end.IsZero() && end.Before(start) {
+		return nil, &store.ErrNotValid{Err: errors.New("end of the time range is before its start")}
+	}
+	rows, err := e.db.Query(ctx, getEventHistory, ns, entity, check, unixOrNull(start), unixOrNull(end))
+	if err != nil {
+		return nil, &store.ErrInternal{Message: fmt.Sprintf("couldn't get event history: %s", err)}
+	}
+	defer rows.Close()
+	entries := []*store.EventHistoryEntry{}
+	for rows.Next() {
+		var entry store.EventHistoryEntry
+		var status int64
+		if err := rows.Scan(&entry.Entity, &entry.Check, &status, &entry.State, &entry.Output, &entry.Executed, &entry.Timestamp); err != nil {
+			return nil, &store.ErrNotValid{Err: fmt.Errorf("error reading event history: %s", err)}
+		}
+		entry.Status = uint32(status)
+		entries = append(entries, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, &store.ErrInternal{Message: fmt.Sprintf("error reading event history: %s", err)}
+	}
+	return entries, nil
+}
+
+// PruneEventHistory deletes the history entries that occurred before the
+// given time.
+func (e *EventStore) PruneEventHistory(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := e.db.Exec(ctx, pruneEventHistory, before.Unix())
+	if err != nil {
+		return 0, &store.ErrInternal{Message: fmt.Sprintf("couldn't prune event history: %s", err)}
+	}
+	return tag.RowsAffected(), nil
+}
+
+func unixOrNull(t time.Time) sql.NullInt64 {
+	if t.IsZero() {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: t.Unix(), Valid: true}
+}
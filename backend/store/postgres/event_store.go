@@ -14,6 +14,7 @@ import (
 	corev2 "github.com/sensu/core/v2"
 	"github.com/sensu/sensu-go/backend/store"
 	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	"github.com/sirupsen/logrus"
 )
 
 type SilenceStoreI interface {
@@ -395,9 +396,37 @@ func (e *EventStore) UpdateEvent(ctx context.Context, event *corev2.Event) (uEve
 		return nil, nil, &store.ErrInternal{Message: err.Error()}
 	}
 
+	// Record status changes in the event history. When the previous event is
+	// unknown, the history query compares the status with the last recorded
+	// entry instead. The event is already persisted at this point, so a
+	// failure to record its history must not fail the update.
+	if prevEvent == nil || prevEvent.Check == nil || prevEvent.Check.Status != event.Check.Status {
+		if err := e.recordEventHistory(ctx, historyEvent(persistEvent, event)); err != nil {
+			logger.WithError(err).WithFields(logrus.Fields{
+				"namespace": event.Entity.Namespace,
+				"entity":    event.Entity.Name,
+				"check":     event.Check.Name,
+			}).Error("couldn't record event history")
+		}
+	}
+
 	return event, prevEvent, nil
 }
 
+// historyEvent returns the event to record in the event history: the
+// persisted event, which has a truncated output, with the check state of the
+// updated event.
+func historyEvent(persistEvent, event *corev2.Event) *corev2.Event {
+	if persistEvent == event {
+		return event
+	}
+	e := *persistEvent
+	check := *persistEvent.Check
+	check.State = event.Check.State
+	e.Check = &check
+	return &e
+}
+
 func updateOccurrences(check *corev2.Check) {
 	if check == nil {
 		return
@@ -619,6 +619,51 @@ func TestEventStoreHistory(t *testing.T) {
 	})
 }
 
+func TestEventStoreStatusHistory(t *testing.T) {
+	testWithPostgresEventStore(t, func(s store.EventStore, sv2 storev2.Interface) {
+		hs, ok := s.(store.EventHistoryStore)
+		if !ok {
+			t.Fatal("event store does not record history")
+		}
+		event := corev2.FixtureEvent("foo", "bar")
+		ctx := store.NamespaceContext(context.Background(), "default")
+		for i, status := range []uint32{0, 0, 2, 2, 0} {
+			event.Check.Status = status
+			event.Check.Executed = int64(100 + i)
+			event.Timestamp = int64(100 + i)
+			if _, _, err := s.UpdateEvent(ctx, event); err != nil {
+				t.Fatal(err)
+			}
+		}
+		entries, err := hs.GetEventHistory(ctx, "foo", "bar", time.Time{}, time.Time{})
+		require.NoError(t, err)
+		var statuses []uint32
+		for _, entry := range entries {
+			statuses = append(statuses, entry.Status)
+		}
+		if got, want := statuses, []uint32{0, 2, 0}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("bad statuses: got %v, want %v", got, want)
+		}
+
+		entries, err = hs.GetEventHistory(ctx, "foo", "bar", time.Unix(101, 0), time.Unix(103, 0))
+		require.NoError(t, err)
+		if got, want := len(entries), 1; got != want {
+			t.Fatalf("bad number of entries: got %d, want %d", got, want)
+		}
+
+		pruned, err := hs.PruneEventHistory(ctx, time.Unix(104, 0))
+		require.NoError(t, err)
+		if got, want := pruned, int64(2); got != want {
+			t.Fatalf("bad number of pruned entries: got %d, want %d", got, want)
+		}
+		entries, err = hs.GetEventHistory(ctx, "foo", "bar", time.Time{}, time.Time{})
+		require.NoError(t, err)
+		if got, want := len(entries), 1; got != want {
+			t.Fatalf("bad number of entries: got %d, want %d", got, want)
+		}
+	})
+}
+
 func TestEventStoreSelectors(t *testing.T) {
 	pgURL := os.Getenv("PG_URL")
 	if pgURL == "" {
@@ -0,0 +1,14 @@
+WITH ns AS (
+	SELECT id
+	FROM namespaces
+	WHERE name = $1
+	LIMIT 1
+)
+SELECT entity_name, check_name, status, state, output, executed, timestamp
+FROM   event_history, ns
+WHERE  event_history.namespace = ns.id AND
+       event_history.entity_name = $2 AND
+       event_history.check_name = $3 AND
+       ( $4::bigint IS NULL OR event_history.timestamp >= $4 ) AND
+       ( $5::bigint IS NULL OR event_history.timestamp <= $5 )
+ORDER BY event_history.timestamp ASC, event_history.id ASC;
@@ -176,6 +176,51 @@ var Migrations = []migration.Migrator{
 		_, err := tx.Exec(context.Background(), "UPDATE configuration SET etag = digest(resource::text, 'sha1')")
 		return err
 	},
+	// Migration 29
+	func(tx migration.LimitedTx) error {
+		_, err := tx.Exec(context.Background(), addEventHistoryTable)
+		return err
+	},
+	// Migration 30
+	func(tx migration.LimitedTx) error {
+		_, err := tx.Exec(context.Background(), clusterBusSchema)
+		return err
+	},
+	// Migration 31
+	func(tx migration.LimitedTx) error {
+		_, err := tx.Exec(context.Background(), escalationIncidentsSchema)
+		return err
+	},
+	// Migration 32
+	func(tx migration.LimitedTx) error {
+		_, err := tx.Exec(context.Background(), throttleStatesSchema)
+		return err
+	},
+	// Migration 33
+	func(tx migration.LimitedTx) error {
+		_, err := tx.Exec(context.Background(), auditLogSchema)
+		return err
+	},
+	// Migration 34
+	func(tx migration.LimitedTx) error {
+		_, err := tx.Exec(context.Background(), configHistorySchema)
+		return err
+	},
+	// Migration 35
+	func(tx migration.LimitedTx) error {
+		_, err := tx.Exec(context.Background(), rateLimitSchema)
+		return err
+	},
+	// Migration 36
+	func(tx migration.LimitedTx) error {
+		_, err := tx.Exec(context.Background(), apiKeyUsageSchema)
+		return err
+	},
+	// Migration 37
+	func(tx migration.LimitedTx) error {
+		_, err := tx.Exec(context.Background(), oidcStateSchema)
+		return err
+	},
 }
 
 type eventRecord struct {
@@ -415,3 +415,20 @@ CREATE TABLE events (
 const addConfigurationFields = `
 ALTER TABLE configuration
 ADD COLUMN fields JSONB NOT NULL DEFAULT '{}'::jsonb;`
+
+// Migration 29
+const addEventHistoryTable = `
+CREATE TABLE IF NOT EXISTS event_history (
+	id          bigserial PRIMARY KEY,
+	namespace   bigint    REFERENCES namespaces (id) ON DELETE CASCADE,
+	entity_name text      NOT NULL,
+	check_name  text      NOT NULL,
+	status      bigint    NOT NULL,
+	state       text      NOT NULL,
+	output      text      NOT NULL,
+	executed    bigint    NOT NULL,
+	timestamp   bigint    NOT NULL
+);
+CREATE INDEX ON event_history ( namespace, entity_name, check_name, timestamp );
+CREATE INDEX ON event_history ( timestamp );
+`
@@ -0,0 +1,36 @@
+package postgres
+
+// Migration 37
+const oidcStateSchema = `
+CREATE TABLE IF NOT EXISTS oidc_states (
+	kind		text NOT NULL,
+	id		text NOT NULL,
+	value		bytea NOT NULL,
+	expires_at	timestamptz NOT NULL,
+	PRIMARY KEY (kind, id)
+);
+
+CREATE INDEX IF NOT EXISTS oidc_states_expires_at_idx ON oidc_states ( expires_at );
+`
+
+const oidcPutState = `
+INSERT INTO oidc_states (kind, id, value, expires_at) VALUES ($1, $2, $3, $4)
+ON CONFLICT (kind, id) DO UPDATE SET value = $3, expires_at = $4;
+`
+
+const oidcGetState = `
+SELECT value FROM oidc_states WHERE kind = $1 AND id = $2 AND expires_at > now();
+`
+
+const oidcTakeState = `
+DELETE FROM oidc_states WHERE kind = $1 AND id = $2
+RETURNING value, expires_at > now();
+`
+
+const oidcDeleteState = `
+DELETE FROM oidc_states WHERE kind = $1 AND id = $2;
+`
+
+const oidcPruneStates = `
+DELETE FROM oidc_states WHERE expires_at < $1;
+`
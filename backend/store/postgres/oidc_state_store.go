@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+var _ store.OIDCStateStore = &OIDCStateStore{}
+
+// OIDCStateStore keeps the state of the OIDC logins in the oidc_states table.
+type OIDCStateStore struct {
+	db DBI
+}
+
+// NewOIDCStateStore creates a new OIDCStateStore.
+func NewOIDCStateStore(db DBI) *OIDCStateStore {
+	return &OIDCStateStore{db: db}
+}
+
+// PutOIDCState creates or updates the state id of the given kind.
+func (s *OIDCStateStore) PutOIDCState(ctx context.Context, kind, id string, value []byte, expiresAt time.Time) error {
+	if _, err := s.db.Exec(ctx, oidcPutState, kind, id, value, expiresAt); err != nil {
+		return &store.ErrInternal{Message: fmt.Sprintf("couldn't put oidc state: %s", err)}
+	}
+	return nil
+}
+
+// GetOIDCState returns the state id of the given kind.
+func (s *OIDCStateStore) GetOIDCState(ctx context.Context, kind, id string) ([]byte, error) {
+	var value []byte
+	if err := s.db.QueryRow(ctx, oidcGetState, kind, id).Scan(&value); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, &store.ErrNotFound{Key: kind + "/" + id}
+		}
+		return nil, &store.ErrInternal{Message: fmt.Sprintf("couldn't get oidc state: %s", err)}
+	}
+	return value, nil
+}
+
+// TakeOIDCState deletes the state id of the given kind and returns it.
+func (s *OIDCStateStore) TakeOIDCState(ctx context.Context, kind, id string) ([]byte, error) {
+	var value []byte
+	var valid bool
+	if err := s.db.QueryRow(ctx, oidcTakeState, kind, id).Scan(&value, &valid); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, &store.ErrNotFound{Key: kind + "/" + id}
+		}
+		return nil, &store.ErrInternal{Message: fmt.Sprintf("couldn't take oidc state: %s", err)}
+	}
+	if !valid {
+		return nil, &store.ErrNotFound{Key: kind + "/" + id}
+	}
+	return value, nil
+}
+
+// DeleteOIDCState deletes the state id of the given kind.
+func (s *OIDCStateStore) DeleteOIDCState(ctx context.Context, kind, id string) error {
+	if _, err := s.db.Exec(ctx, oidcDeleteState, kind, id); err != nil {
+		return &store.ErrInternal{Message: fmt.Sprintf("couldn't delete oidc state: %s", err)}
+	}
+	return nil
+}
+
+// PruneOIDCStates deletes the states that expired before t.
+func (s *OIDCStateStore) PruneOIDCStates(ctx context.Context, t time.Time) (int64, error) {
+	tag, err := s.db.Exec(ctx, oidcPruneStates, t)
+	if err != nil {
+		return 0, &store.ErrInternal{Message: fmt.Sprintf("couldn't prune oidc states: %s", err)}
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+func TestOIDCStateStore(t *testing.T) {
+	withPostgres(t, func(ctx context.Context, db *pgxpool.Pool, dsn string) {
+		s := NewOIDCStateStore(db)
+
+		now := time.Now()
+		if err := s.PutOIDCState(ctx, store.OIDCLogin, "a", []byte("login"), now.Add(time.Minute)); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.PutOIDCState(ctx, store.OIDCLogin, "b", []byte("expired"), now.Add(-time.Minute)); err != nil {
+			t.Fatal(err)
+		}
+
+		value, err := s.GetOIDCState(ctx, store.OIDCLogin, "a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := string(value), "login"; got != want {
+			t.Fatalf("bad state: got %q, want %q", got, want)
+		}
+		var notFound *store.ErrNotFound
+		if _, err := s.GetOIDCState(ctx, store.OIDCLogin, "b"); !errors.As(err, &notFound) {
+			t.Fatalf("expected not found for an expired state, got %v", err)
+		}
+		if _, err := s.GetOIDCState(ctx, store.OIDCSession, "a"); !errors.As(err, &notFound) {
+			t.Fatalf("expected not found for another kind, got %v", err)
+		}
+
+		// A state can only be taken once
+		if _, err := s.TakeOIDCState(ctx, store.OIDCLogin, "a"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.TakeOIDCState(ctx, store.OIDCLogin, "a"); !errors.As(err, &notFound) {
+			t.Fatalf("expected not found for a taken state, got %v", err)
+		}
+
+		pruned, err := s.PruneOIDCStates(ctx, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pruned != 1 {
+			t.Fatalf("expected 1 pruned state, got %d", pruned)
+		}
+	})
+}
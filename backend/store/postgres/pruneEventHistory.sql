@@ -0,0 +1,2 @@
+DELETE FROM event_history
+WHERE timestamp < $1;
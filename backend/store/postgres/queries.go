@@ -16,3 +16,12 @@ var getKeepaliveCountsByNamespaceQuery string
 
 //go:embed getEventCountsByNamespaceQuery.sql
 var getEventCountsByNamespaceQuery string
+
+//go:embed createEventHistory.sql
+var createEventHistory string
+
+//go:embed getEventHistory.sql
+var getEventHistory string
+
+//go:embed pruneEventHistory.sql
+var pruneEventHistory string
@@ -0,0 +1,35 @@
+package postgres
+
+// Migration 35
+//
+// The buckets are unlogged, losing them in a crash only refills them.
+const rateLimitSchema = `
+CREATE UNLOGGED TABLE IF NOT EXISTS rate_limit_buckets (
+	key			text PRIMARY KEY,
+	tokens		double precision NOT NULL,
+	allowed		boolean NOT NULL,
+	updated_at	timestamptz NOT NULL DEFAULT NOW()
+);
+CREATE INDEX ON rate_limit_buckets ( updated_at );
+`
+
+// rateLimitTakeToken refills the bucket of $1 with $2 tokens per second since
+// it was last updated, up to $3 tokens, and takes a token if there is one. A
+// new bucket starts full.
+const rateLimitTakeToken = `
+INSERT INTO rate_limit_buckets AS bucket (key, tokens, allowed, updated_at)
+	VALUES ($1, $3::double precision - 1, true, NOW())
+ON CONFLICT (key) DO UPDATE SET
+	tokens = CASE
+		WHEN LEAST($3, bucket.tokens + EXTRACT(EPOCH FROM NOW() - bucket.updated_at)::double precision * $2::double precision) >= 1
+		THEN LEAST($3, bucket.tokens + EXTRACT(EPOCH FROM NOW() - bucket.updated_at)::double precision * $2::double precision) - 1
+		ELSE LEAST($3, bucket.tokens + EXTRACT(EPOCH FROM NOW() - bucket.updated_at)::double precision * $2::double precision)
+	END,
+	allowed = LEAST($3, bucket.tokens + EXTRACT(EPOCH FROM NOW() - bucket.updated_at)::double precision * $2::double precision) >= 1,
+	updated_at = NOW()
+RETURNING allowed, tokens;
+`
+
+const rateLimitPruneBuckets = `
+DELETE FROM rate_limit_buckets WHERE updated_at < $1;
+`
@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+var _ store.RateLimitStore = &RateLimitStore{}
+
+// RateLimitStore keeps the token buckets of the rate limits in the
+// rate_limit_buckets table, so that they are shared by the backends.
+type RateLimitStore struct {
+	db DBI
+}
+
+// NewRateLimitStore creates a new RateLimitStore.
+func NewRateLimitStore(db DBI) *RateLimitStore {
+	return &RateLimitStore{db: db}
+}
+
+// TakeRateLimitToken takes a token from the bucket of key.
+func (s *RateLimitStore) TakeRateLimitToken(ctx context.Context, key string, rate float64, burst int) (time.Duration, error) {
+	var (
+		allowed bool
+		tokens  float64
+	)
+	row := s.db.QueryRow(ctx, rateLimitTakeToken, key, rate, float64(burst))
+	if err := row.Scan(&allowed, &tokens); err != nil {
+		return 0, &store.ErrInternal{Message: fmt.Sprintf("couldn't take rate limit token: %s", err)}
+	}
+	if allowed {
+		return 0, nil
+	}
+	return time.Duration((1 - tokens) / rate * float64(time.Second)), nil
+}
+
+// PruneRateLimitBuckets deletes the buckets that were not used since before.
+func (s *RateLimitStore) PruneRateLimitBuckets(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := s.db.Exec(ctx, rateLimitPruneBuckets, before)
+	if err != nil {
+		return 0, &store.ErrInternal{Message: fmt.Sprintf("couldn't prune rate limit buckets: %s", err)}
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,52 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestRateLimitStore(t *testing.T) {
+	withPostgres(t, func(ctx context.Context, db *pgxpool.Pool, dsn string) {
+		s := NewRateLimitStore(db)
+
+		// A new bucket is full
+		for i := 0; i < 3; i++ {
+			delay, err := s.TakeRateLimitToken(ctx, "core/v2/user:alice", 0.01, 3)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if delay != 0 {
+				t.Fatalf("token %d: expected no delay, got %s", i, delay)
+			}
+		}
+
+		// The bucket is empty, a token is added every 100s
+		delay, err := s.TakeRateLimitToken(ctx, "core/v2/user:alice", 0.01, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if delay <= 90*time.Second || delay > 100*time.Second {
+			t.Fatalf("expected a delay of about 100s, got %s", delay)
+		}
+
+		// The buckets of the clients are independent
+		delay, err = s.TakeRateLimitToken(ctx, "core/v2/user:bob", 0.01, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if delay != 0 {
+			t.Fatalf("expected no delay, got %s", delay)
+		}
+
+		pruned, err := s.PruneRateLimitBuckets(ctx, time.Now().Add(time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pruned != 2 {
+			t.Fatalf("expected 2 buckets pruned, got %d", pruned)
+		}
+	})
+}
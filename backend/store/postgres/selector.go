@@ -94,6 +94,17 @@ func (s *SelectorSQLBuilder) GetSelectorCond(ctr *argCounter) (string, []interfa
 			cond, vr := s.matchOperator(ctr, op)
 			conds = append(conds, cond)
 			vars = append(vars, vr...)
+		case selector.GreaterThanOperator, selector.GreaterThanOrEqualOperator, selector.LessThanOperator, selector.LessThanOrEqualOperator:
+			cond, vr, err := s.numericOperatorMatch(ctr, op)
+			if err != nil {
+				return "", nil, err
+			}
+			conds = append(conds, cond)
+			vars = append(vars, vr...)
+		case selector.HasOperator:
+			cond, vr := s.hasOperatorMatch(ctr, op)
+			conds = append(conds, cond)
+			vars = append(vars, vr...)
 		default:
 			return "", nil, fmt.Errorf("unsupported operator: %s", op.Operator)
 		}
@@ -103,6 +114,15 @@ func (s *SelectorSQLBuilder) GetSelectorCond(ctr *argCounter) (string, []interfa
 	conds = append(conds, cnds...)
 	vars = append(vars, vrs...)
 
+	for _, expr := range s.selector.Expressions {
+		cond, vr, err := s.expressionCond(ctr, expr)
+		if err != nil {
+			return "", nil, err
+		}
+		conds = append(conds, cond)
+		vars = append(vars, vr...)
+	}
+
 	return strings.Join(conds, " AND "), vars, nil
 }
 
@@ -203,6 +223,151 @@ func (s *SelectorSQLBuilder) matchOperator(ctr *argCounter, op selector.Operatio
 	return query, []interface{}{op.LValue, op.RValues[0]}
 }
 
+// expressionCond translates a selector expression, e.g. a disjunction or a
+// negation, into a SQL condition. Operations are wrapped with COALESCE so
+// that a missing key evaluates to false rather than NULL, which would
+// otherwise make negations of missing keys unsatisfiable.
+func (s *SelectorSQLBuilder) expressionCond(ctr *argCounter, expr selector.Expression) (string, []interface{}, error) {
+	switch e := expr.(type) {
+	case selector.Operation:
+		cond, vars, err := s.operationCond(ctr, e)
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("COALESCE((%s), false)", cond), vars, nil
+	case selector.AndExpression:
+		return s.expressionConds(ctr, e.Operands, " AND ")
+	case selector.OrExpression:
+		return s.expressionConds(ctr, e.Operands, " OR ")
+	case selector.NotExpression:
+		cond, vars, err := s.expressionCond(ctr, e.Operand)
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("NOT %s", cond), vars, nil
+	default:
+		return "", nil, fmt.Errorf("unsupported expression: %T", expr)
+	}
+}
+
+func (s *SelectorSQLBuilder) expressionConds(ctr *argCounter, operands []selector.Expression, sep string) (string, []interface{}, error) {
+	conds := make([]string, 0, len(operands))
+	vars := make([]interface{}, 0, len(operands))
+	for _, operand := range operands {
+		cond, vr, err := s.expressionCond(ctr, operand)
+		if err != nil {
+			return "", nil, err
+		}
+		conds = append(conds, cond)
+		vars = append(vars, vr...)
+	}
+	return fmt.Sprintf("(%s)", strings.Join(conds, sep)), vars, nil
+}
+
+// operationCond translates a single operation of an expression into a SQL
+// condition.
+func (s *SelectorSQLBuilder) operationCond(ctr *argCounter, op selector.Operation) (string, []interface{}, error) {
+	switch op.Operator {
+	case selector.DoubleEqualSignOperator, selector.NotEqualOperator, selector.MatchesOperator:
+		if len(op.RValues) != 1 {
+			return "", nil, fmt.Errorf("invalid operator: %v", op)
+		}
+	}
+	switch op.Operator {
+	case selector.InOperator:
+		cond, vars := s.inOperatorMatch(ctr, op)
+		return cond, vars, nil
+	case selector.NotInOperator:
+		cond, vars := s.notInOperatorMatch(ctr, op)
+		return cond, vars, nil
+	case selector.DoubleEqualSignOperator, selector.NotEqualOperator:
+		if op.OperationType == selector.OperationTypeLabelSelector {
+			if op.Operator == selector.NotEqualOperator {
+				cond, vars := s.notInOperatorMatch(ctr, op)
+				return cond, vars, nil
+			}
+			cond, vars := s.inOperatorMatch(ctr, op)
+			return cond, vars, nil
+		}
+		values := map[string]string{op.LValue: op.RValues[0]}
+		if op.Operator == selector.NotEqualOperator {
+			conds, vars := s.formatSelectorConds(ctr, nil, values)
+			return conds[0], vars, nil
+		}
+		conds, vars := s.formatSelectorConds(ctr, values, nil)
+		return conds[0], vars, nil
+	case selector.MatchesOperator:
+		cond, vars := s.matchOperator(ctr, op)
+		return cond, vars, nil
+	case selector.GreaterThanOperator, selector.GreaterThanOrEqualOperator, selector.LessThanOperator, selector.LessThanOrEqualOperator:
+		return s.numericOperatorMatch(ctr, op)
+	case selector.HasOperator:
+		cond, vars := s.hasOperatorMatch(ctr, op)
+		return cond, vars, nil
+	default:
+		return "", nil, fmt.Errorf("unsupported operator: %s", op.Operator)
+	}
+}
+
+// numericOperatorMatch compares a field or a label with a number. Values that
+// are not numbers never match, like with the selector API.
+func (s *SelectorSQLBuilder) numericOperatorMatch(ctr *argCounter, op selector.Operation) (string, []interface{}, error) {
+	if len(op.RValues) != 1 {
+		return "", nil, fmt.Errorf("invalid operator: %v", op)
+	}
+	if _, err := selector.ParseNumber(op.RValues[0]); err != nil {
+		return "", nil, fmt.Errorf("invalid number: %s", op.RValues[0])
+	}
+	queryFragment := "(CASE WHEN %[1]s ~ %[2]s THEN (%[1]s)::numeric %[3]s $%[4]d::numeric ELSE false END)"
+	key, isLabel := s.labelKey(op)
+	if !isLabel {
+		keyArg := ctr.Next()
+		valueArg := ctr.Next()
+		value := fmt.Sprintf("%s#>>$%d", s.selectorColumn, keyArg)
+		query := fmt.Sprintf(queryFragment, value, pq.QuoteLiteral(selector.NumberPattern), op.Operator, valueArg)
+		return query, []interface{}{s.matchLValue(op.LValue), op.RValues[0]}, nil
+	}
+	keyArg := ctr.Next()
+	valueArg := ctr.Next()
+	fragments := make([]string, 0, len(s.labelPrefixes))
+	for _, prefix := range s.labelPrefixes {
+		value := fmt.Sprintf("%s->>(%s || $%d)", s.labelColumn, pq.QuoteLiteral(prefix), keyArg)
+		fragments = append(fragments, fmt.Sprintf(queryFragment, value, pq.QuoteLiteral(selector.NumberPattern), op.Operator, valueArg))
+	}
+	query := fmt.Sprintf("(%s)", strings.Join(fragments, " OR "))
+	return query, []interface{}{key, op.RValues[0]}, nil
+}
+
+// hasOperatorMatch checks for the presence of a field or a label.
+func (s *SelectorSQLBuilder) hasOperatorMatch(ctr *argCounter, op selector.Operation) (string, []interface{}) {
+	key, isLabel := s.labelKey(op)
+	if !isLabel {
+		query := fmt.Sprintf("%s#>>$%d IS NOT NULL", s.selectorColumn, ctr.Next())
+		return query, []interface{}{s.matchLValue(op.LValue)}
+	}
+	keyArg := ctr.Next()
+	fragments := make([]string, 0, len(s.labelPrefixes))
+	for _, prefix := range s.labelPrefixes {
+		fragments = append(fragments, fmt.Sprintf("%s ? (%s || $%d)", s.labelColumn, pq.QuoteLiteral(prefix), keyArg))
+	}
+	return fmt.Sprintf("(%s)", strings.Join(fragments, " OR ")), []interface{}{key}
+}
+
+// labelKey returns the key of the label an operation applies to, relative to
+// the label prefixes, or false if the operation applies to a field.
+func (s *SelectorSQLBuilder) labelKey(op selector.Operation) (string, bool) {
+	if s.validFieldKey(&op) {
+		return "", false
+	}
+	if !strings.HasPrefix(op.LValue, "labels.") && op.OperationType != selector.OperationTypeLabelSelector {
+		return "", false
+	}
+	if !strings.HasPrefix(op.LValue, "labels.") && s.includeLabelCaption {
+		return fmt.Sprintf("labels.%s", op.LValue), true
+	}
+	return op.LValue, true
+}
+
 func (s *SelectorSQLBuilder) formatSelectorConds(ctr *argCounter, inclusions, exclusions map[string]string) ([]string, []interface{}) {
 	conds := make([]string, 0, 2)
 	vars := make([]interface{}, 0, 2)
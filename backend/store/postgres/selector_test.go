@@ -1,8 +1,13 @@
 package postgres
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
 	"testing"
 
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/sensu/sensu-go/backend/selector"
 )
 
@@ -47,3 +52,133 @@ func TestGetSelectorCond(t *testing.T) {
 	}
 
 }
+
+func TestGetSelectorCondExpressions(t *testing.T) {
+	builder := &SelectorSQLBuilder{
+		selectorColumn:  "testSelectorCol",
+		nestedSelectors: true,
+		labelColumn:     "testLabelCol",
+		labelPrefixes:   []string{""},
+	}
+
+	testCases := []struct {
+		input         string
+		labels        bool
+		expectedQuery string
+		expectedArgs  []interface{}
+	}{
+		{
+			input:         "foo == bar || zip != zap",
+			expectedQuery: "(COALESCE((testSelectorCol @> $1), false) OR COALESCE((NOT testSelectorCol @> $2), false))",
+			expectedArgs:  []interface{}{[]byte(`{"foo":"bar"}`), []byte(`{"zip":"zap"}`)},
+		},
+		{
+			input:         "foo == bar && !(has zip)",
+			expectedQuery: "testSelectorCol @> $1 AND NOT COALESCE((testSelectorCol#>>$2 IS NOT NULL), false)",
+			expectedArgs:  []interface{}{[]byte(`{"foo":"bar"}`), "{zip}"},
+		},
+		{
+			input:         "check.status >= 2",
+			expectedQuery: `(CASE WHEN testSelectorCol#>>$1 ~ '^-?[0-9]+([.][0-9]+)?$' THEN (testSelectorCol#>>$1)::numeric >= $2::numeric ELSE false END)`,
+			expectedArgs:  []interface{}{"{check,status}", "2"},
+		},
+		{
+			input:         "region == us || tier < 3",
+			labels:        true,
+			expectedQuery: `(COALESCE(((testLabelCol ? ('' || $1) AND testLabelCol->>('' || $1) ~ $2)), false) OR COALESCE((((CASE WHEN testLabelCol->>('' || $3) ~ '^-?[0-9]+([.][0-9]+)?$' THEN (testLabelCol->>('' || $3))::numeric < $4::numeric ELSE false END))), false))`,
+			expectedArgs:  []interface{}{"region", "(((^|,)us($|,)))", "tier", "3"},
+		},
+		{
+			input:         "has region",
+			labels:        true,
+			expectedQuery: "(testLabelCol ? ('' || $1))",
+			expectedArgs:  []interface{}{"region"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			parse := selector.ParseFieldSelector
+			if tc.labels {
+				parse = selector.ParseLabelSelector
+			}
+			selector, err := parse(tc.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			builder.selector = selector
+			actualQuery, actualArgs, err := builder.GetSelectorCond(&argCounter{0})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actualQuery != tc.expectedQuery {
+				t.Errorf("expected %s, got %s", tc.expectedQuery, actualQuery)
+			}
+			if !reflect.DeepEqual(actualArgs, tc.expectedArgs) {
+				t.Errorf("expected args %v, got %v", tc.expectedArgs, actualArgs)
+			}
+		})
+	}
+}
+
+// numericSelectorCases are evaluated both by the selector API and by
+// postgres, which must agree on the values that are numbers.
+var numericSelectorCases = []struct {
+	value string
+	want  bool
+}{
+	{value: "2", want: true},
+	{value: "10.0", want: true},
+	{value: "1", want: false},
+	{value: "-3", want: false},
+	{value: "1e5", want: false},
+	{value: "+5", want: false},
+	{value: " 5", want: false},
+	{value: "5.", want: false},
+	{value: "0x10", want: false},
+	{value: "Inf", want: false},
+	{value: "NaN", want: false},
+	{value: "five", want: false},
+}
+
+func TestNumericSelectorGrammar(t *testing.T) {
+	sel, err := selector.ParseFieldSelector("value > 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range numericSelectorCases {
+		t.Run(tc.value, func(t *testing.T) {
+			if got := sel.Matches(map[string]string{"value": tc.value}); got != tc.want {
+				t.Errorf("selector API: expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+
+	withPostgres(t, func(ctx context.Context, db *pgxpool.Pool, dsn string) {
+		builder := &SelectorSQLBuilder{
+			selectorColumn:  "doc",
+			nestedSelectors: true,
+			selector:        sel,
+		}
+		cond, args, err := builder.GetSelectorCond(&argCounter{0})
+		if err != nil {
+			t.Fatal(err)
+		}
+		query := fmt.Sprintf("SELECT %s FROM (SELECT $%d::jsonb AS doc) AS docs", cond, len(args)+1)
+		for _, tc := range numericSelectorCases {
+			t.Run(tc.value, func(t *testing.T) {
+				doc, err := json.Marshal(map[string]string{"value": tc.value})
+				if err != nil {
+					t.Fatal(err)
+				}
+				var got bool
+				if err := db.QueryRow(ctx, query, append(args, string(doc))...).Scan(&got); err != nil {
+					t.Fatal(err)
+				}
+				if got != tc.want {
+					t.Errorf("postgres: expected %v, got %v", tc.want, got)
+				}
+			})
+		}
+	})
+}
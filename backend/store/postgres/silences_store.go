@@ -68,6 +68,16 @@ func readSilence(sf scanFunc) (*corev2.Silenced, error) {
 	if err != nil {
 		return nil, err
 	}
+	if len(labels) > 0 {
+		if err := json.Unmarshal(labels, &result.ObjectMeta.Labels); err != nil {
+			return nil, err
+		}
+	}
+	if len(annotations) > 0 {
+		if err := json.Unmarshal(annotations, &result.ObjectMeta.Annotations); err != nil {
+			return nil, err
+		}
+	}
 	return &result, nil
 }
 
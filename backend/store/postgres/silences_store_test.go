@@ -268,3 +268,37 @@ func TestSilenceStoreGetSilencesByName(t *testing.T) {
 		}
 	})
 }
+
+func TestReadSilenceLabels(t *testing.T) {
+	scan := func(labels, annotations string) scanFunc {
+		return func(dest ...interface{}) error {
+			*dest[0].(*string) = "default"
+			*dest[1].(*string) = "linux:disk"
+			*dest[2].(*[]byte) = []byte(labels)
+			*dest[3].(*[]byte) = []byte(annotations)
+			return nil
+		}
+	}
+	silence, err := readSilence(scan(`{"sensu.io/managed_by":"maintenance"}`, `{"note":"patching"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := silence.Labels, map[string]string{"sensu.io/managed_by": "maintenance"}; !cmp.Equal(got, want) {
+		t.Errorf("bad labels: %v", cmp.Diff(got, want))
+	}
+	if got, want := silence.Annotations, map[string]string{"note": "patching"}; !cmp.Equal(got, want) {
+		t.Errorf("bad annotations: %v", cmp.Diff(got, want))
+	}
+
+	silence, err = readSilence(scan("", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if silence.Labels != nil || silence.Annotations != nil {
+		t.Errorf("unexpected labels or annotations: %v %v", silence.Labels, silence.Annotations)
+	}
+
+	if _, err := readSilence(scan("{", "")); err == nil {
+		t.Error("expected non-nil error")
+	}
+}
@@ -117,6 +117,10 @@ func (s *Store) GetSilencesStore() storev2.SilencesStore {
 	return &SilenceStore{db: s.db}
 }
 
+func (s *Store) GetConfigHistoryStore() storev2.ConfigHistoryStore {
+	return NewConfigHistoryStore(s.db)
+}
+
 const pgUniqueViolationCode = "23505"
 
 type DBI interface {
@@ -786,7 +790,7 @@ func storeKey(req storev2.ResourceRequest) string {
 func getSelectorSQL(ctx context.Context, apiVersion, typeName string, nargs int) (string, []interface{}, error) {
 	ctxSelector := storev2.SelectorFromContext(ctx, corev2.TypeMeta{APIVersion: apiVersion, Type: typeName})
 
-	if ctxSelector != nil && len(ctxSelector.Operations) > 0 {
+	if ctxSelector != nil && (len(ctxSelector.Operations) > 0 || len(ctxSelector.Expressions) > 0) {
 		argCounter := argCounter{value: nargs}
 		builder := NewConfigSelectorSQLBuilder(ctxSelector)
 		return builder.GetSelectorCond(&argCounter)
@@ -0,0 +1,50 @@
+package postgres
+
+// Migration 32
+const throttleStatesSchema = `
+CREATE TABLE IF NOT EXISTS throttle_states (
+	namespace		text NOT NULL,
+	filter			text NOT NULL,
+	key				text NOT NULL,
+	occurrences		bigint NOT NULL DEFAULT 0,
+	last_allowed_at	timestamptz,
+	updated_at		timestamptz NOT NULL DEFAULT NOW(),
+	PRIMARY KEY ( namespace, filter, key )
+);
+CREATE INDEX ON throttle_states ( updated_at );
+`
+
+const throttleInsertState = `
+INSERT INTO throttle_states (namespace, filter, key)
+	VALUES ($1, $2, $3)
+	ON CONFLICT DO NOTHING;
+`
+
+const throttleGetStateForUpdate = `
+SELECT occurrences, last_allowed_at FROM throttle_states
+WHERE namespace = $1 AND filter = $2 AND key = $3
+FOR UPDATE;
+`
+
+const throttleUpdateState = `
+UPDATE throttle_states
+SET occurrences = $4, last_allowed_at = $5, updated_at = NOW()
+WHERE namespace = $1 AND filter = $2 AND key = $3;
+`
+
+const throttleStateExists = `
+SELECT EXISTS (
+	SELECT 1 FROM throttle_states
+	WHERE namespace = $1 AND filter = $2 AND key = $3
+);
+`
+
+const throttleDeleteState = `
+DELETE FROM throttle_states
+WHERE namespace = $1 AND filter = $2 AND key = $3;
+`
+
+const throttlePruneStates = `
+DELETE FROM throttle_states
+WHERE updated_at < $1;
+`
@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+var _ store.ThrottleStore = &ThrottleStore{}
+
+// ThrottleStore counts the occurrences of the keys of throttle filters in the
+// throttle_states table. The state of a key is locked while an occurrence is
+// recorded, so that the limits are enforced across the backends.
+type ThrottleStore struct {
+	db DBI
+}
+
+// NewThrottleStore creates a new ThrottleStore.
+func NewThrottleStore(db DBI) *ThrottleStore {
+	return &ThrottleStore{db: db}
+}
+
+// RecordOccurrence records an occurrence of the key, and returns whether it
+// is allowed by the limits.
+func (s *ThrottleStore) RecordOccurrence(ctx context.Context, key store.ThrottleKey, limits store.ThrottleLimits, now time.Time) (allowed bool, err error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return false, &store.ErrInternal{Message: fmt.Sprintf("couldn't record occurrence: %s", err)}
+	}
+	defer func() {
+		if err == nil {
+			if err = tx.Commit(ctx); err != nil {
+				err = &store.ErrInternal{Message: fmt.Sprintf("couldn't record occurrence: %s", err)}
+			}
+			return
+		}
+		if rollbackErr := tx.Rollback(context.Background()); rollbackErr != nil {
+			logger.WithError(rollbackErr).Error("error rolling back throttle transaction")
+		}
+	}()
+
+	if _, err = tx.Exec(ctx, throttleInsertState, key.Namespace, key.Filter, key.Key); err != nil {
+		return false, &store.ErrInternal{Message: fmt.Sprintf("couldn't record occurrence: %s", err)}
+	}
+	var occurrences int64
+	var lastAllowed sql.NullTime
+	if err = tx.QueryRow(ctx, throttleGetStateForUpdate, key.Namespace, key.Filter, key.Key).Scan(&occurrences, &lastAllowed); err != nil {
+		return false, &store.ErrInternal{Message: fmt.Sprintf("couldn't read throttle state: %s", err)}
+	}
+	state := store.ThrottleState{
+		Occurrences: uint32(occurrences),
+		LastAllowed: lastAllowed.Time,
+	}
+	allowed = state.Record(limits, now)
+	lastAllowed = sql.NullTime{Time: state.LastAllowed, Valid: !state.LastAllowed.IsZero()}
+	if _, err = tx.Exec(ctx, throttleUpdateState, key.Namespace, key.Filter, key.Key, int64(state.Occurrences), lastAllowed); err != nil {
+		return false, &store.ErrInternal{Message: fmt.Sprintf("couldn't update throttle state: %s", err)}
+	}
+	return allowed, nil
+}
+
+// ResetOccurrences resets the occurrences of the key. Most passing events
+// have no occurrences to reset, so the state is only deleted if it exists.
+func (s *ThrottleStore) ResetOccurrences(ctx context.Context, key store.ThrottleKey) error {
+	var exists bool
+	if err := s.db.QueryRow(ctx, throttleStateExists, key.Namespace, key.Filter, key.Key).Scan(&exists); err != nil {
+		return &store.ErrInternal{Message: fmt.Sprintf("couldn't read throttle state: %s", err)}
+	}
+	if !exists {
+		return nil
+	}
+	if _, err := s.db.Exec(ctx, throttleDeleteState, key.Namespace, key.Filter, key.Key); err != nil {
+		return &store.ErrInternal{Message: fmt.Sprintf("couldn't reset occurrences: %s", err)}
+	}
+	return nil
+}
+
+// PruneThrottleStates deletes the states of the keys without occurrences
+// since the given time.
+func (s *ThrottleStore) PruneThrottleStates(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := s.db.Exec(ctx, throttlePruneStates, before)
+	if err != nil {
+		return 0, &store.ErrInternal{Message: fmt.Sprintf("couldn't prune throttle states: %s", err)}
+	}
+	return tag.RowsAffected(), nil
+}
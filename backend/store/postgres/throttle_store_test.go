@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+func TestThrottleStore(t *testing.T) {
+	withPostgres(t, func(ctx context.Context, db *pgxpool.Pool, dsn string) {
+		s := NewThrottleStore(db)
+		key := store.ThrottleKey{Namespace: "default", Filter: "throttle", Key: `"entity1","check1"`}
+		limits := store.ThrottleLimits{Occurrences: 1, Interval: time.Minute}
+		now := time.Now()
+
+		for i, want := range []bool{true, false} {
+			allowed, err := s.RecordOccurrence(ctx, key, limits, now.Add(time.Duration(i)*time.Second))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if allowed != want {
+				t.Fatalf("occurrence %d: allowed = %v, want %v", i+1, allowed, want)
+			}
+		}
+
+		// The state of the other keys is independent
+		other := key
+		other.Key = `"entity2","check1"`
+		if allowed, err := s.RecordOccurrence(ctx, other, limits, now); err != nil {
+			t.Fatal(err)
+		} else if !allowed {
+			t.Fatal("occurrence of another key denied")
+		}
+
+		if allowed, err := s.RecordOccurrence(ctx, key, limits, now.Add(time.Minute)); err != nil {
+			t.Fatal(err)
+		} else if !allowed {
+			t.Fatal("occurrence after the interval denied")
+		}
+
+		if err := s.ResetOccurrences(ctx, key); err != nil {
+			t.Fatal(err)
+		}
+		if allowed, err := s.RecordOccurrence(ctx, key, limits, now.Add(time.Minute+time.Second)); err != nil {
+			t.Fatal(err)
+		} else if !allowed {
+			t.Fatal("occurrence after a reset denied")
+		}
+
+		// Resetting a key without occurrences is a no-op
+		if err := s.ResetOccurrences(ctx, store.ThrottleKey{Namespace: "default", Filter: "throttle", Key: "none"}); err != nil {
+			t.Fatal(err)
+		}
+
+		// The states updated before the given time are pruned
+		if pruned, err := s.PruneThrottleStates(ctx, time.Now().Add(-time.Hour)); err != nil {
+			t.Fatal(err)
+		} else if pruned != 0 {
+			t.Fatalf("pruned %d states, want 0", pruned)
+		}
+		if pruned, err := s.PruneThrottleStates(ctx, time.Now().Add(time.Hour)); err != nil {
+			t.Fatal(err)
+		} else if pruned != 2 {
+			t.Fatalf("pruned %d states, want 2", pruned)
+		}
+	})
+}
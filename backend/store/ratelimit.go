@@ -0,0 +1,19 @@
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimitStore keeps token buckets, so that rate limits are shared by the
+// backends of a cluster.
+type RateLimitStore interface {
+	// TakeRateLimitToken takes a token from the bucket of key, which is
+	// refilled with rate tokens per second up to burst tokens. It returns
+	// zero if a token was taken, or the delay after which one will be.
+	TakeRateLimitToken(ctx context.Context, key string, rate float64, burst int) (time.Duration, error)
+
+	// PruneRateLimitBuckets deletes the buckets that were not used since
+	// before, and returns how many were deleted.
+	PruneRateLimitBuckets(ctx context.Context, before time.Time) (int64, error)
+}
@@ -21,6 +21,8 @@ const (
 const (
 	// mutex for tessend telemetry
 	MutexTelemetry Mutex = iota ^ BitmaskMutexOSS
+	// mutex for the maintenance window silences of maintenanced
+	MutexMaintenance
 )
 
 // MutexHandler should listen for context cancellation. If a mutex is lost,
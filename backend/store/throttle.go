@@ -0,0 +1,66 @@
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// ThrottleKey identifies the occurrences counted by a throttle filter.
+type ThrottleKey struct {
+	// Namespace is the namespace of the filter and of the events.
+	Namespace string
+
+	// Filter is the name of the throttle filter.
+	Filter string
+
+	// Key is the key of the events, as returned by the filter.
+	Key string
+}
+
+// ThrottleLimits are the limits enforced by a throttle filter.
+type ThrottleLimits struct {
+	// Occurrences is the number of first occurrences that are allowed.
+	Occurrences uint32
+
+	// Interval is the minimum time between two allowed occurrences, once the
+	// first occurrences were allowed. Zero denies the other occurrences.
+	Interval time.Duration
+}
+
+// ThrottleState is the state of the occurrences of a throttle key.
+type ThrottleState struct {
+	// Occurrences is the number of occurrences since the last reset.
+	Occurrences uint32
+
+	// LastAllowed is the time of the last allowed occurrence.
+	LastAllowed time.Time
+}
+
+// Record records an occurrence at the given time, and returns whether it is
+// allowed by the limits.
+func (s *ThrottleState) Record(limits ThrottleLimits, now time.Time) bool {
+	s.Occurrences++
+	allowed := s.Occurrences <= limits.Occurrences
+	if !allowed && limits.Interval > 0 {
+		allowed = s.LastAllowed.IsZero() || !now.Before(s.LastAllowed.Add(limits.Interval))
+	}
+	if allowed {
+		s.LastAllowed = now
+	}
+	return allowed
+}
+
+// ThrottleStore provides methods for counting the occurrences of the keys of
+// throttle filters, consistently across the backends.
+type ThrottleStore interface {
+	// RecordOccurrence records an occurrence of the key at the given time,
+	// and returns whether it is allowed by the limits.
+	RecordOccurrence(ctx context.Context, key ThrottleKey, limits ThrottleLimits, now time.Time) (bool, error)
+
+	// ResetOccurrences resets the occurrences of the key.
+	ResetOccurrences(ctx context.Context, key ThrottleKey) error
+
+	// PruneThrottleStates deletes the states of the keys without occurrences
+	// since the given time, and returns the number of deleted states.
+	PruneThrottleStates(ctx context.Context, before time.Time) (int64, error)
+}
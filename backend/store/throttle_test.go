@@ -0,0 +1,59 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestThrottleStateRecord(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	limits := ThrottleLimits{Occurrences: 2, Interval: 10 * time.Minute}
+	tests := []struct {
+		offset time.Duration
+		want   bool
+	}{
+		{0, true},
+		{time.Minute, true},
+		{2 * time.Minute, false},
+		{10 * time.Minute, false},
+		{11 * time.Minute, true},
+		{15 * time.Minute, false},
+		{21 * time.Minute, true},
+	}
+	var state ThrottleState
+	for i, tt := range tests {
+		if got := state.Record(limits, start.Add(tt.offset)); got != tt.want {
+			t.Errorf("occurrence %d at %s: allowed = %v, want %v", i+1, tt.offset, got, tt.want)
+		}
+	}
+	if got, want := state.Occurrences, uint32(len(tests)); got != want {
+		t.Errorf("occurrences = %d, want %d", got, want)
+	}
+}
+
+func TestThrottleStateRecordWithoutInterval(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	var state ThrottleState
+	limits := ThrottleLimits{Occurrences: 1}
+	if !state.Record(limits, now) {
+		t.Error("first occurrence denied")
+	}
+	if state.Record(limits, now.Add(24*time.Hour)) {
+		t.Error("second occurrence allowed")
+	}
+}
+
+func TestThrottleStateRecordWithoutOccurrences(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	var state ThrottleState
+	limits := ThrottleLimits{Interval: time.Minute}
+	if !state.Record(limits, now) {
+		t.Error("first occurrence denied")
+	}
+	if state.Record(limits, now.Add(time.Second)) {
+		t.Error("occurrence within the interval allowed")
+	}
+	if !state.Record(limits, now.Add(time.Minute)) {
+		t.Error("occurrence after the interval denied")
+	}
+}
@@ -0,0 +1,64 @@
+package v2
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+const (
+	// RevisionCreate is the operation of the revision of a resource that
+	// didn't exist.
+	RevisionCreate = "create"
+
+	// RevisionUpdate is the operation of the revision of an existing
+	// resource.
+	RevisionUpdate = "update"
+
+	// RevisionDelete is the operation of the revision that records the
+	// deletion of a resource. Its resource is the last state of the deleted
+	// resource.
+	RevisionDelete = "delete"
+)
+
+// Revision is a version of a configuration resource, kept by the
+// configuration history.
+type Revision struct {
+	// Revision is the number of the revision, which increases with every
+	// write of the resource.
+	Revision int64 `json:"revision"`
+
+	// Operation is the write that produced the revision, either
+	// RevisionCreate, RevisionUpdate or RevisionDelete.
+	Operation string `json:"operation"`
+
+	// Time is the time of the write.
+	Time time.Time `json:"time"`
+
+	// Resource is the JSON representation of the resource.
+	Resource json.RawMessage `json:"resource"`
+}
+
+// ConfigHistoryStore provides access to the prior versions of the
+// configuration resources, which are recorded with every write of the
+// ConfigStore.
+type ConfigHistoryStore interface {
+	// ListRevisions returns the revisions of a resource, most recent first.
+	ListRevisions(context.Context, ResourceRequest) ([]*Revision, error)
+
+	// GetRevision returns a revision of a resource. It returns a
+	// store.ErrNotFound error if the revision doesn't exist.
+	GetRevision(context.Context, ResourceRequest, int64) (*Revision, error)
+
+	// PruneRevisions deletes the revisions beyond the given number of
+	// revisions per resource, and the revisions recorded before the given
+	// time. A zero number or time disables the corresponding bound. The most
+	// recent revision of a resource is never deleted. It returns the number
+	// of revisions deleted.
+	PruneRevisions(ctx context.Context, maxRevisions int, before time.Time) (int64, error)
+}
+
+// ConfigHistoryStoreGetter gets you a ConfigHistoryStore.
+type ConfigHistoryStoreGetter interface {
+	GetConfigHistoryStore() ConfigHistoryStore
+}
@@ -18,6 +18,7 @@ type Interface interface {
 	EventStoreGetter
 	EntityStoreGetter
 	SilencesStoreGetter
+	ConfigHistoryStoreGetter
 }
 
 // Wrapper is an abstraction of a store wrapper.
@@ -0,0 +1,42 @@
+package backend
+
+import (
+	"context"
+	"time"
+
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+const (
+	// throttleStatePruneInterval is the interval at which the idle throttle
+	// states are deleted.
+	throttleStatePruneInterval = time.Hour
+
+	// throttleStateRetention is how long the state of a throttle key is kept
+	// after its last occurrence. The failing events of a key keep updating
+	// its state, so only the keys whose entity or check stopped reporting are
+	// pruned.
+	throttleStateRetention = 24 * time.Hour
+)
+
+// PruneThrottleStatesLoop periodically deletes the throttle states without
+// occurrences during the retention, until ctx is cancelled.
+func PruneThrottleStatesLoop(ctx context.Context, throttles store.ThrottleStore) {
+	ticker := time.NewTicker(throttleStatePruneInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			pruned, err := throttles.PruneThrottleStates(ctx, time.Now().Add(-throttleStateRetention))
+			if err != nil {
+				logger.WithError(err).Error("error pruning throttle states")
+				continue
+			}
+			if pruned > 0 {
+				logger.WithField("throttle_states", pruned).Debug("pruned throttle states")
+			}
+		}
+	}
+}
@@ -0,0 +1,4 @@
+package client
+
+// AuditEntriesPath is the api path for the entries of the audit log.
+var AuditEntriesPath = CreateBasePath("audit", "v1", "entries")
@@ -34,6 +34,33 @@ func (client *RestClient) CreateAccessToken(url, userid, password string) (*core
 	return tokens, err
 }
 
+// CreateAccessTokenWithOIDCCode returns a new access token given the code
+// received at the end of an OIDC login
+func (client *RestClient) CreateAccessTokenWithOIDCCode(url, code string) (*corev2.Tokens, error) {
+	// Make sure any existing auth token doesn't get injected instead
+	client.ClearAuthToken()
+	defer client.Reset()
+
+	res, err := client.R().
+		SetHeader("Content-Type", "application/json").
+		SetBody(map[string]string{"code": code}).
+		Post(url + "/auth/oidc/token")
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode() >= 400 {
+		return nil, errors.New(string(res.Body()))
+	}
+
+	tokens := &corev2.Tokens{}
+	if err = json.Unmarshal(res.Body(), tokens); err != nil {
+		return nil, fmt.Errorf("could not unmarshal response from server: %s", err)
+	}
+
+	return tokens, nil
+}
+
 // TestCreds checks if the provided User credentials are valid
 func (client *RestClient) TestCreds(userid, password string) error {
 	client.ClearAuthToken()
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -54,6 +55,33 @@ func TestCreateAccessTokenForbidden(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestCreateAccessTokenWithOIDCCode(t *testing.T) {
+	testHandler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, http.MethodPost)
+		assert.Equal(t, "/auth/oidc/token", r.URL.Path)
+		body, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{"code": "1234"}`, string(body))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token": "foo", "expires_at": 123456789, "refresh_token": "bar"}`))
+	}
+	server := httptest.NewServer(http.HandlerFunc(testHandler))
+	defer server.Close()
+
+	mockConfig := &config.MockConfig{}
+	restyInst := resty.New()
+	client := &RestClient{resty: restyInst, config: mockConfig}
+
+	mockConfig.On("APIUrl").Return("")
+	mockConfig.On("Tokens").Return(&corev2.Tokens{})
+	mockConfig.On("APIKey").Return("")
+
+	tokens, err := client.CreateAccessTokenWithOIDCCode(server.URL, "1234")
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", tokens.Access)
+	assert.Equal(t, "bar", tokens.Refresh)
+}
+
 func TestRefreshAccessToken(t *testing.T) {
 	testHandler := func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, r.Method, http.MethodPost)
@@ -10,6 +10,7 @@ import (
 	corev2 "github.com/sensu/core/v2"
 	corev3 "github.com/sensu/core/v3"
 	"github.com/sensu/core/v3/types"
+	"github.com/sensu/sensu-go/util/compat"
 )
 
 // Delete sends a DELETE request to the given path
@@ -165,6 +166,52 @@ func (client *RestClient) Put(path string, obj interface{}) error {
 	return nil
 }
 
+// DryRunResult is the response of a dry run, in which the API validates a
+// resource and checks its references without persisting it.
+type DryRunResult struct {
+	// Operation is the operation a put would perform, either create or
+	// update.
+	Operation string `json:"operation"`
+
+	// Warnings are the problems found in the resource, such as the missing
+	// references.
+	Warnings []DryRunWarning `json:"warnings"`
+
+	// Resource is the resource as the API would persist it, if returned by
+	// the API.
+	Resource *types.Wrapper `json:"resource"`
+}
+
+// DryRunWarning is a problem found by a dry run, in the given field of the
+// resource.
+type DryRunWarning struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// DryRunResource puts a resource according to its URIPath in dry-run mode,
+// so that it is validated by the API without being persisted.
+func (client *RestClient) DryRunResource(r types.Wrapper) (*DryRunResult, error) {
+	path := compat.URIPath(r.Value)
+	bytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.R().SetQueryParam("dryRun", "true").SetBody(bytes).Put(path)
+	if err != nil {
+		return nil, fmt.Errorf("PUT %q: %s", path, err)
+	}
+	if res.StatusCode() >= 400 {
+		return nil, UnmarshalError(res)
+	}
+	var result DryRunResult
+	if err := json.Unmarshal(res.Body(), &result); err != nil {
+		return nil, fmt.Errorf("PUT %q: %s", path, err)
+	}
+	return &result, nil
+}
+
 // PutResource ...
 func (client *RestClient) PutResource(r types.Wrapper) error {
 	var path string
@@ -72,11 +72,15 @@ type GenericClient interface {
 
 	// PutResource puts a resource according to its URIPath.
 	PutResource(types.Wrapper) error
+	// DryRunResource puts a resource according to its URIPath in dry-run
+	// mode, so that it is validated by the API without being persisted.
+	DryRunResource(types.Wrapper) (*DryRunResult, error)
 }
 
 // AuthenticationAPIClient client methods for authenticating
 type AuthenticationAPIClient interface {
 	CreateAccessToken(url string, userid string, secret string) (*corev2.Tokens, error)
+	CreateAccessTokenWithOIDCCode(url string, code string) (*corev2.Tokens, error)
 	TestCreds(userid string, secret string) error
 	Logout(token string) error
 	RefreshAccessToken(tokens *corev2.Tokens) (*corev2.Tokens, error)
@@ -0,0 +1,4 @@
+package client
+
+// MaintenanceWindowsPath is the api path for maintenance windows.
+var MaintenanceWindowsPath = createNSBasePath("maintenance", "v1", "maintenance_windows")
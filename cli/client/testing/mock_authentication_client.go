@@ -10,6 +10,12 @@ func (c *MockClient) CreateAccessToken(url, u, p string) (*corev2.Tokens, error)
 	return args.Get(0).(*corev2.Tokens), args.Error(1)
 }
 
+// CreateAccessTokenWithOIDCCode for use with mock lib
+func (c *MockClient) CreateAccessTokenWithOIDCCode(url, code string) (*corev2.Tokens, error) {
+	args := c.Called(url, code)
+	return args.Get(0).(*corev2.Tokens), args.Error(1)
+}
+
 // TestCreds for use with mock lib
 func (c *MockClient) TestCreds(u, p string) error {
 	args := c.Called(u, p)
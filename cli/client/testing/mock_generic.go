@@ -50,3 +50,10 @@ func (c *MockClient) PutResource(r types.Wrapper) error {
 	args := c.Called(r)
 	return args.Error(0)
 }
+
+// DryRunResource ...
+func (c *MockClient) DryRunResource(r types.Wrapper) (*client.DryRunResult, error) {
+	args := c.Called(r)
+	result, _ := args.Get(0).(*client.DryRunResult)
+	return result, args.Error(1)
+}
@@ -105,7 +105,10 @@ func getEntity() (*corev2.Entity, error) {
 	}, nil
 }
 
-func NewCommandManager(cli *cli.SensuCli) (*CommandManager, error) {
+// NewCommandManager returns a manager of the command plugins of sensuctl. The
+// signatures of the assets of the plugins are verified by signatures, if not
+// nil.
+func NewCommandManager(cli *cli.SensuCli, signatures *asset.SignatureVerifier) (*CommandManager, error) {
 	m := CommandManager{
 		cli:          cli,
 		bonsaiClient: bonsai.New(bonsai.Config{}),
@@ -127,6 +130,7 @@ func NewCommandManager(cli *cli.SensuCli) (*CommandManager, error) {
 		trustedCAFile = cli.Config.TrustedCAFile()
 	}
 	m.assetManager = asset.NewManager(cacheDir, trustedCAFile, entity, &wg)
+	m.assetManager.SignatureVerifier = signatures
 	m.assetGetter, err = m.assetManager.StartAssetManager(ctx, nil)
 	if err != nil {
 		return nil, err
@@ -212,11 +216,19 @@ func (m *CommandManager) InstallCommandFromBonsai(alias, bonsaiAssetName string)
 	return m.installCommand(alias, asset)
 }
 
-func (m *CommandManager) InstallCommandFromURL(alias, archiveURL, checksum string) error {
+// InstallCommandFromURL installs a command plugin from the archive at the
+// given URL. The signature of the archive is fetched from signatureURL, if
+// set, when signatures are verified.
+func (m *CommandManager) InstallCommandFromURL(alias, archiveURL, checksum, signatureURL string) error {
 	meta := corev2.ObjectMeta{
 		Name:      alias,
 		Namespace: sensuctlAssetNamespace,
 	}
+	if signatureURL != "" {
+		meta.Annotations = map[string]string{
+			asset.SignatureURLAnnotation: signatureURL,
+		}
+	}
 	asset := corev2.Asset{
 		Builds: []*corev2.AssetBuild{
 			{
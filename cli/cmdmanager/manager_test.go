@@ -512,6 +512,7 @@ func TestCommandManager_InstallCommandFromURL(t *testing.T) {
 		alias                 string
 		archiveURL            string
 		checksum              string
+		signatureURL          string
 		wantErr               bool
 		errMatch              string
 		expectedCommandPlugin *CommandPlugin
@@ -543,10 +544,33 @@ func TestCommandManager_InstallCommandFromURL(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:         "valid asset with a signature",
+			alias:        "signedasset",
+			checksum:     checksum,
+			archiveURL:   "https://fake",
+			signatureURL: "https://fake/signature",
+			expectedCommandPlugin: &CommandPlugin{
+				Alias: "signedasset",
+				Asset: corev2.Asset{
+					Builds: []*corev2.AssetBuild{
+						{
+							URL:    "https://fake",
+							Sha512: checksum,
+						},
+					},
+					ObjectMeta: corev2.ObjectMeta{
+						Name:        "signedasset",
+						Namespace:   "sensuctl",
+						Annotations: map[string]string{asset.SignatureURLAnnotation: "https://fake/signature"},
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := m.InstallCommandFromURL(tt.alias, tt.archiveURL, tt.checksum)
+			err := m.InstallCommandFromURL(tt.alias, tt.archiveURL, tt.checksum, tt.signatureURL)
 
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("CommandManager.InstallCommandFromURL() error = %v, wantErr %v", err, tt.wantErr)
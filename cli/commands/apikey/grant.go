@@ -3,12 +3,23 @@ package apikey
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	corev2 "github.com/sensu/core/v2"
+	apikeys "github.com/sensu/sensu-go/backend/authentication/apikey"
+	"github.com/sensu/sensu-go/backend/authorization"
 	"github.com/sensu/sensu-go/cli"
 	"github.com/spf13/cobra"
 )
 
+const (
+	flagDescription = "description"
+	flagExpires     = "expires"
+	flagNamespace   = "namespace"
+	flagVerbs       = "verbs"
+	flagResources   = "resources"
+)
+
 // GrantCommand adds a command that creates apikeys.
 func GrantCommand(cli *cli.SensuCli) *cobra.Command {
 	cmd := &cobra.Command{
@@ -24,6 +35,9 @@ func GrantCommand(cli *cli.SensuCli) *cobra.Command {
 			apikey := &corev2.APIKey{
 				Username: args[0],
 			}
+			if err := setAttributes(cmd, apikey, time.Now()); err != nil {
+				return err
+			}
 
 			response, err := cli.Client.PostAPIKey(apikey.URIPath(), apikey)
 			if err != nil {
@@ -33,9 +47,73 @@ func GrantCommand(cli *cli.SensuCli) *cobra.Command {
 			fmt.Fprintf(cmd.OutOrStdout(), "Granted a new API key. Save this key as it will not be retrievable later!\n")
 			fmt.Fprintf(cmd.OutOrStdout(), "Name: %s\n", response.Name)
 			fmt.Fprintf(cmd.OutOrStdout(), "Key:  %s\n", response.Key)
+			if expiresAt, _ := apikeys.ExpiresAt(apikey); !expiresAt.IsZero() {
+				fmt.Fprintf(cmd.OutOrStdout(), "Expires: %s\n", expiresAt.Local().Format(time.RFC3339))
+			}
 			return nil
 		},
 	}
 
+	cmd.Flags().String(flagDescription, "", "description of the api-key")
+	cmd.Flags().Duration(flagExpires, 0, "duration after which the api-key expires, e.g. 720h (never by default)")
+	cmd.Flags().StringSlice(flagNamespace, nil, "comma separated list of the namespaces that the api-key is restricted to")
+	cmd.Flags().StringSlice(flagVerbs, nil, "comma separated list of the verbs that the api-key is restricted to")
+	cmd.Flags().StringSlice(flagResources, nil, "comma separated list of the resources that the api-key is restricted to")
+
 	return cmd
 }
+
+// setAttributes sets the description, the expiration and the scope of the
+// api-key from the flags. The api-key is restricted to the intersection of
+// the scope and the permissions of its user.
+func setAttributes(cmd *cobra.Command, apikey *corev2.APIKey, now time.Time) error {
+	description, err := cmd.Flags().GetString(flagDescription)
+	if err != nil {
+		return err
+	}
+	if description != "" {
+		apikeys.SetDescription(apikey, description)
+	}
+
+	expires, err := cmd.Flags().GetDuration(flagExpires)
+	if err != nil {
+		return err
+	}
+	if expires < 0 {
+		return errors.New("the expiration of the api-key must be positive")
+	}
+	if expires > 0 {
+		apikeys.SetExpiresAt(apikey, now.Add(expires))
+	}
+
+	var scope authorization.Scope
+	if scope.Namespaces, err = cmd.Flags().GetStringSlice(flagNamespace); err != nil {
+		return err
+	}
+	verbs, err := cmd.Flags().GetStringSlice(flagVerbs)
+	if err != nil {
+		return err
+	}
+	resources, err := cmd.Flags().GetStringSlice(flagResources)
+	if err != nil {
+		return err
+	}
+	if len(verbs) > 0 || len(resources) > 0 {
+		rule := corev2.Rule{
+			Verbs:     verbs,
+			Resources: resources,
+		}
+		if len(rule.Verbs) == 0 {
+			rule.Verbs = []string{corev2.VerbAll}
+		}
+		if len(rule.Resources) == 0 {
+			rule.Resources = []string{corev2.ResourceAll}
+		}
+		scope.Rules = []corev2.Rule{rule}
+	}
+	if err := apikeys.SetScope(apikey, &scope); err != nil {
+		return err
+	}
+
+	return apikeys.Validate(apikey)
+}
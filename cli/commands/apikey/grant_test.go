@@ -3,8 +3,11 @@ package apikey
 import (
 	"errors"
 	"testing"
+	"time"
 
 	corev2 "github.com/sensu/core/v2"
+	apikeys "github.com/sensu/sensu-go/backend/authentication/apikey"
+	"github.com/sensu/sensu-go/backend/authorization"
 	client "github.com/sensu/sensu-go/cli/client/testing"
 	test "github.com/sensu/sensu-go/cli/commands/testing"
 	"github.com/stretchr/testify/assert"
@@ -65,3 +68,51 @@ func TestGrantCommandServerError(t *testing.T) {
 	assert.Error(err)
 	assert.Equal("err", err.Error())
 }
+
+func TestGrantCommandWithScope(t *testing.T) {
+	assert := assert.New(t)
+
+	cli := test.NewMockCLI()
+	client := cli.Client.(*client.MockClient)
+	var apikey *corev2.APIKey
+	client.On("PostAPIKey", mock.Anything, mock.Anything).Return(corev2.APIKeyResponse{Name: "mykey", Key: "keystuff"}, nil).Run(
+		func(args mock.Arguments) {
+			apikey = args[1].(*corev2.APIKey)
+		},
+	)
+
+	cmd := GrantCommand(cli)
+	require.NoError(t, cmd.Flags().Set("expires", "720h"))
+	require.NoError(t, cmd.Flags().Set("namespace", "ci"))
+	require.NoError(t, cmd.Flags().Set("verbs", "get,list"))
+	require.NoError(t, cmd.Flags().Set("description", "ci pipeline"))
+	out, err := test.RunCmd(cmd, []string{"user1"})
+
+	require.NoError(t, err)
+	assert.Regexp("Key:  keystuff", out)
+	assert.Regexp("Expires: ", out)
+	require.NotNil(t, apikey)
+	assert.Equal("ci pipeline", apikeys.Description(apikey))
+	expiresAt, err := apikeys.ExpiresAt(apikey)
+	require.NoError(t, err)
+	assert.WithinDuration(time.Now().Add(720*time.Hour), expiresAt, time.Minute)
+	scope, err := apikeys.Scope(apikey)
+	require.NoError(t, err)
+	assert.Equal(&authorization.Scope{
+		Namespaces: []string{"ci"},
+		Rules: []corev2.Rule{
+			{Verbs: []string{"get", "list"}, Resources: []string{corev2.ResourceAll}},
+		},
+	}, scope)
+}
+
+func TestGrantCommandInvalidScope(t *testing.T) {
+	assert := assert.New(t)
+
+	cli := test.NewMockCLI()
+	cmd := GrantCommand(cli)
+	require.NoError(t, cmd.Flags().Set("verbs", "steal"))
+	_, err := test.RunCmd(cmd, []string{"user1"})
+
+	assert.Error(err)
+}
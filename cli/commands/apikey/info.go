@@ -4,9 +4,11 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 	"time"
 
 	corev2 "github.com/sensu/core/v2"
+	apikeys "github.com/sensu/sensu-go/backend/authentication/apikey"
 	"github.com/sensu/sensu-go/cli"
 	"github.com/sensu/sensu-go/cli/commands/helpers"
 	"github.com/sensu/sensu-go/cli/elements/list"
@@ -66,8 +68,37 @@ func printToList(v interface{}, writer io.Writer) error {
 				Label: "Created At",
 				Value: time.Unix(r.CreatedAt, 0).String(),
 			},
+			{
+				Label: "Description",
+				Value: apikeys.Description(r),
+			},
+			{
+				Label: "Expires",
+				Value: expiresAt(r),
+			},
+			{
+				Label: "Last Used",
+				Value: lastUsed(r),
+			},
 		},
 	}
 
+	// the scope of the api-key, if it is restricted
+	if scope, err := apikeys.Scope(r); err != nil {
+		cfg.Rows = append(cfg.Rows, &list.Row{Label: "Scope", Value: "Invalid"})
+	} else if scope != nil {
+		namespaces := "All"
+		if len(scope.Namespaces) > 0 {
+			namespaces = strings.Join(scope.Namespaces, ", ")
+		}
+		cfg.Rows = append(cfg.Rows, &list.Row{Label: "Namespaces", Value: namespaces})
+		for _, rule := range scope.Rules {
+			cfg.Rows = append(cfg.Rows, &list.Row{
+				Label: "Rule",
+				Value: fmt.Sprintf("verbs: %s, resources: %s", strings.Join(rule.Verbs, ", "), strings.Join(rule.Resources, ", ")),
+			})
+		}
+	}
+
 	return list.Print(writer, cfg)
 }
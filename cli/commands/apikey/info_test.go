@@ -5,9 +5,11 @@ import (
 	"testing"
 
 	corev2 "github.com/sensu/core/v2"
+	apikeys "github.com/sensu/sensu-go/backend/authentication/apikey"
 	client "github.com/sensu/sensu-go/cli/client/testing"
 	test "github.com/sensu/sensu-go/cli/commands/testing"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -98,3 +100,36 @@ func TestInfoCommandRunEClosureWithErr(t *testing.T) {
 	assert.Equal("err", err.Error())
 	assert.Empty(out)
 }
+
+func TestInfoCommandRunEClosureWithScope(t *testing.T) {
+	assert := assert.New(t)
+
+	cli := test.NewCLI()
+	client := cli.Client.(*client.MockClient)
+	apikey := &corev2.APIKey{
+		ObjectMeta: corev2.ObjectMeta{
+			Name: "my-api-key",
+		},
+	}
+	client.On("Get", apikey.URIPath(), apikey).Return(nil).Run(func(args mock.Arguments) {
+		apikey := args[1].(*corev2.APIKey)
+		apikey.Annotations = map[string]string{
+			apikeys.DescriptionAnnotation: "ci pipeline",
+			apikeys.ExpiresAtAnnotation:   "2000-01-01T00:00:00Z",
+			apikeys.NamespacesAnnotation:  "ci",
+			apikeys.RulesAnnotation:       `[{"verbs":["get","list"],"resources":["*"]}]`,
+		}
+	})
+
+	cmd := InfoCommand(cli)
+	require.NoError(t, cmd.Flags().Set("format", "tabular"))
+
+	out, err := test.RunCmd(cmd, []string{"my-api-key"})
+	require.NoError(t, err)
+
+	assert.Contains(out, "ci pipeline")
+	assert.Contains(out, "(expired)")
+	assert.Contains(out, "Last Used")
+	assert.Contains(out, "Namespaces")
+	assert.Contains(out, "verbs: get, list, resources: *")
+}
@@ -2,11 +2,14 @@ package apikey
 
 import (
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"time"
 
 	corev2 "github.com/sensu/core/v2"
 	corev3 "github.com/sensu/core/v3"
+	apikeys "github.com/sensu/sensu-go/backend/authentication/apikey"
 	"github.com/sensu/sensu-go/cli"
 	"github.com/sensu/sensu-go/cli/commands/helpers"
 	"github.com/sensu/sensu-go/cli/commands/timeutil"
@@ -91,7 +94,51 @@ func printToTable(results interface{}, writer io.Writer) {
 				return timeutil.HumanTimestamp(apikey.CreatedAt)
 			},
 		},
+		{
+			Title: "Expires",
+			CellTransformer: func(data interface{}) string {
+				apikey, ok := data.(corev2.APIKey)
+				if !ok {
+					return cli.TypeError
+				}
+				return expiresAt(&apikey)
+			},
+		},
+		{
+			Title: "Last Used",
+			CellTransformer: func(data interface{}) string {
+				apikey, ok := data.(corev2.APIKey)
+				if !ok {
+					return cli.TypeError
+				}
+				return lastUsed(&apikey)
+			},
+		},
 	})
 
 	table.Render(writer, results)
 }
+
+// expiresAt returns the expiration of the api-key in a readable format.
+func expiresAt(apikey *corev2.APIKey) string {
+	t, err := apikeys.ExpiresAt(apikey)
+	if err != nil {
+		return "Invalid"
+	}
+	if t.IsZero() {
+		return "Never"
+	}
+	if apikeys.Expired(apikey, time.Now()) {
+		return fmt.Sprintf("%s (expired)", timeutil.HumanTimestamp(t.Unix()))
+	}
+	return timeutil.HumanTimestamp(t.Unix())
+}
+
+// lastUsed returns the last time the api-key was used in a readable format.
+func lastUsed(apikey *corev2.APIKey) string {
+	t := apikeys.LastUsed(apikey)
+	if t.IsZero() {
+		return "Never"
+	}
+	return timeutil.HumanTimestamp(t.Unix())
+}
@@ -0,0 +1,100 @@
+package apply
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/sensu/sensu-go/cli"
+	"github.com/sensu/sensu-go/cli/resource"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagDryRun    = "dry-run"
+	flagManagedBy = "managed-by"
+	flagPrune     = "prune"
+)
+
+var description = `sensuctl apply
+
+Apply resources from files, directories or URLs (path, file://, http[s]://),
+or STDIN otherwise. The differences between the resources and their
+definitions are printed as unified diffs before being applied.
+
+With --prune, the resources labeled as managed by --managed-by that are missing
+from the definitions are deleted, within the namespaces of the definitions and
+the current namespace.
+
+With --dry-run, the differences are only printed, and the command fails if
+there are any, e.g. to detect drift in CI:
+$ sensuctl apply -r -f monitoring/ --prune --dry-run
+`
+
+// Command applies generic Sensu resources.
+func Command(cli *cli.SensuCli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "apply [-r] [[-f URL] ... ] [--prune] [--dry-run]",
+		Short:        "Reconcile resources with their definitions from file or URL, or STDIN otherwise",
+		Long:         description,
+		SilenceUsage: true,
+		RunE:         execute(cli),
+	}
+
+	_ = cmd.Flags().StringSliceP("file", "f", nil, "Files, directories, or URLs to apply resources from")
+	_ = cmd.Flags().BoolP("recursive", "r", false, "Follow subdirectories")
+	_ = cmd.Flags().Bool(flagPrune, false, "Delete the managed resources that are missing from the definitions")
+	_ = cmd.Flags().Bool(flagDryRun, false, "Only print the differences, and fail if there are any")
+	_ = cmd.Flags().String(flagManagedBy, "sensuctl", "Value of the sensu.io/managed_by label of the applied and pruned resources")
+
+	return cmd
+}
+
+func execute(cli *cli.SensuCli) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) > 0 {
+			_ = cmd.Help()
+			return errors.New("invalid argument(s) received")
+		}
+		t := &http.Transport{}
+		t.RegisterProtocol("file", http.NewFileTransport(http.Dir("/")))
+		client := &http.Client{Transport: t}
+		inputs, err := cmd.Flags().GetStringSlice("file")
+		if err != nil {
+			return err
+		}
+		recurse, err := cmd.Flags().GetBool("recursive")
+		if err != nil {
+			return err
+		}
+		managedBy, err := cmd.Flags().GetString(flagManagedBy)
+		if err != nil {
+			return err
+		}
+		if managedBy == "" {
+			return fmt.Errorf("--%s must not be empty", flagManagedBy)
+		}
+
+		applier := resource.NewApplier(managedBy, cmd.OutOrStdout())
+		if applier.Prune, err = cmd.Flags().GetBool(flagPrune); err != nil {
+			return err
+		}
+		if applier.DryRun, err = cmd.Flags().GetBool(flagDryRun); err != nil {
+			return err
+		}
+		applier.Namespaces = []string{cli.Config.Namespace()}
+
+		if len(inputs) == 0 {
+			err = resource.ProcessStdin(cli, client, applier)
+		} else {
+			err = resource.Process(cli, client, inputs, recurse, applier)
+		}
+		if err != nil {
+			return err
+		}
+		if applier.DryRun && applier.Changes > 0 {
+			return fmt.Errorf("%d resource(s) differ from their definitions", applier.Changes)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,67 @@
+package apply
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/cli/client"
+	mockclient "github.com/sensu/sensu-go/cli/client/testing"
+	cmdtesting "github.com/sensu/sensu-go/cli/commands/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const checkDefinition = `
+type: CheckConfig
+api_version: core/v2
+spec:
+  metadata:
+    name: cpu
+  command: check-cpu
+  interval: 60
+  subscriptions:
+  - linux
+`
+
+func writeDefinition(t *testing.T) string {
+	t.Helper()
+	fp := filepath.Join(t.TempDir(), "check.yaml")
+	require.NoError(t, os.WriteFile(fp, []byte(checkDefinition), 0600))
+	return fp
+}
+
+func TestApplyCommand(t *testing.T) {
+	cli := cmdtesting.NewMockCLI()
+	mc := cli.Client.(*mockclient.MockClient)
+	mc.On("Get", "/api/core/v2/namespaces/default/checks/cpu", mock.Anything).
+		Return(client.APIError{Code: uint32(actions.NotFound)})
+	mc.On("PutResource", mock.Anything).Return(nil)
+
+	cmd := Command(cli)
+	require.NoError(t, cmd.Flags().Set("file", writeDefinition(t)))
+	out, err := cmdtesting.RunCmd(cmd, nil)
+	require.NoError(t, err)
+	assert.Contains(t, out, "+  command: check-cpu")
+	assert.Contains(t, out, "core/v2.CheckConfig default/cpu created")
+	mc.AssertCalled(t, "PutResource", mock.Anything)
+}
+
+func TestApplyCommandDryRunDrift(t *testing.T) {
+	cli := cmdtesting.NewMockCLI()
+	mc := cli.Client.(*mockclient.MockClient)
+	mc.On("Get", "/api/core/v2/namespaces/default/checks/cpu", mock.Anything).
+		Return(client.APIError{Code: uint32(actions.NotFound)})
+	mc.On("DryRunResource", mock.Anything).Return(&client.DryRunResult{Operation: "create"}, nil)
+
+	cmd := Command(cli)
+	require.NoError(t, cmd.Flags().Set("file", writeDefinition(t)))
+	require.NoError(t, cmd.Flags().Set("dry-run", "true"))
+	out, err := cmdtesting.RunCmd(cmd, nil)
+	assert.Error(t, err)
+	assert.Contains(t, out, "core/v2.CheckConfig default/cpu created (dry run)")
+	mc.AssertNotCalled(t, "PutResource", mock.Anything)
+	mc.AssertCalled(t, "DryRunResource", mock.Anything)
+}
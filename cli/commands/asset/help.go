@@ -1,8 +1,10 @@
 package asset
 
 import (
+	corev2 "github.com/sensu/core/v2"
 	"github.com/sensu/sensu-go/cli"
 	"github.com/sensu/sensu-go/cli/commands/helpers"
+	"github.com/sensu/sensu-go/cli/commands/revision"
 	"github.com/spf13/cobra"
 )
 
@@ -23,6 +25,11 @@ func HelpCommand(cli *cli.SensuCli) *cobra.Command {
 		DeleteCommand(cli),
 		AddCommand(cli),
 		OutdatedCommand(cli),
+
+		// Revision commands
+		revision.HistoryCommand(cli, &corev2.Asset{}),
+		revision.RollbackCommand(cli, &corev2.Asset{}),
 	)
+
 	return cmd
 }
@@ -0,0 +1,27 @@
+package audit
+
+import (
+	"time"
+
+	"github.com/sensu/sensu-go/cli"
+	"github.com/sensu/sensu-go/cli/commands/helpers"
+	"github.com/spf13/cobra"
+)
+
+const (
+	timeFormat = time.RFC3339
+)
+
+// HelpCommand defines new audit command
+func HelpCommand(cli *cli.SensuCli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Query the audit log of the API changes",
+		RunE:  helpers.DefaultSubCommandRunE,
+	}
+
+	// Add sub-commands
+	cmd.AddCommand(ListCommand(cli))
+
+	return cmd
+}
@@ -0,0 +1,227 @@
+package audit
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/cli"
+	"github.com/sensu/sensu-go/cli/client"
+	"github.com/sensu/sensu-go/cli/commands/flags"
+	"github.com/sensu/sensu-go/cli/commands/helpers"
+	"github.com/sensu/sensu-go/cli/elements/globals"
+	"github.com/sensu/sensu-go/cli/elements/table"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagUser  = "user"
+	flagType  = "type"
+	flagName  = "name"
+	flagSince = "since"
+	flagUntil = "until"
+	flagLimit = "limit"
+
+	defaultLimit = 100
+)
+
+// auditEntry is an entry of the audit log, as returned by the API.
+type auditEntry struct {
+	ID         int64       `json:"id" yaml:"id"`
+	Time       time.Time   `json:"time" yaml:"time"`
+	User       string      `json:"user" yaml:"user"`
+	Groups     []string    `json:"groups,omitempty" yaml:"groups,omitempty"`
+	APIKey     bool        `json:"api_key,omitempty" yaml:"api_key,omitempty"`
+	SourceIP   string      `json:"source_ip,omitempty" yaml:"source_ip,omitempty"`
+	Verb       string      `json:"verb" yaml:"verb"`
+	APIVersion string      `json:"api_version" yaml:"api_version"`
+	Type       string      `json:"type" yaml:"type"`
+	Namespace  string      `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Name       string      `json:"name" yaml:"name"`
+	Before     interface{} `json:"before,omitempty" yaml:"before,omitempty"`
+	After      interface{} `json:"after,omitempty" yaml:"after,omitempty"`
+	Diff       interface{} `json:"diff,omitempty" yaml:"diff,omitempty"`
+}
+
+// ListCommand defines new command to list the entries of the audit log
+func ListCommand(cli *cli.SensuCli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "list",
+		Short:        "list the changes made through the API, most recent first",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				_ = cmd.Help()
+				return errors.New("invalid argument(s) received")
+			}
+			query, err := queryFromFlags(cmd, cli)
+			if err != nil {
+				return err
+			}
+
+			entries := []auditEntry{}
+			path := client.AuditEntriesPath() + "?" + query.Encode()
+			if err := cli.Client.Get(path, &entries); err != nil {
+				return err
+			}
+
+			return helpers.Print(cmd, cli.Config.Format(), printToTable, nil, entries)
+		},
+	}
+
+	helpers.AddFormatFlag(cmd.Flags())
+	helpers.AddAllNamespace(cmd.Flags())
+
+	_ = cmd.Flags().String(flagUser, "", "list the changes made by this user")
+	_ = cmd.Flags().String(flagType, "", "list the changes of the resources of this type, e.g. CheckConfig")
+	_ = cmd.Flags().String(flagName, "", "list the changes of the resources with this name")
+	_ = cmd.Flags().String(flagSince, "", "list the changes made since this time, in RFC 3339 format or as a duration ago, e.g. 24h")
+	_ = cmd.Flags().String(flagUntil, "", "list the changes made until this time, in RFC 3339 format or as a duration ago, e.g. 1h")
+	_ = cmd.Flags().Int(flagLimit, defaultLimit, "maximum number of changes listed, 0 for no limit")
+
+	return cmd
+}
+
+// queryFromFlags returns the query parameters of the API request. The
+// entries are filtered by the namespace of the CLI, unless all namespaces
+// are requested.
+func queryFromFlags(cmd *cobra.Command, cli *cli.SensuCli) (url.Values, error) {
+	query := url.Values{}
+	namespace := cli.Config.Namespace()
+	if ok, _ := cmd.Flags().GetBool(flags.AllNamespaces); ok {
+		namespace = corev2.NamespaceTypeAll
+	}
+	if namespace != "" {
+		query.Set("namespace", namespace)
+	}
+	for _, flag := range []string{flagUser, flagType, flagName} {
+		if value, _ := cmd.Flags().GetString(flag); value != "" {
+			query.Set(flag, value)
+		}
+	}
+	for _, flag := range []string{flagSince, flagUntil} {
+		value, _ := cmd.Flags().GetString(flag)
+		if value == "" {
+			continue
+		}
+		t, err := parseTime(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --%s: %s", flag, err)
+		}
+		query.Set(flag, t.Format(time.RFC3339))
+	}
+	limit, err := cmd.Flags().GetInt(flagLimit)
+	if err != nil {
+		return nil, err
+	}
+	if limit < 0 {
+		return nil, fmt.Errorf("--%s must not be negative", flagLimit)
+	}
+	query.Set(flagLimit, strconv.Itoa(limit))
+	return query, nil
+}
+
+// now returns the current time, and is replaced in tests.
+var now = time.Now
+
+// parseTime parses a time in RFC 3339 format, or a duration before now.
+func parseTime(value string) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return now().Add(-d), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+func printToTable(results interface{}, writer io.Writer) {
+	table := table.New([]*table.Column{
+		{
+			Title:       "Time",
+			ColumnStyle: table.PrimaryTextStyle,
+			CellTransformer: func(data interface{}) string {
+				entry, ok := data.(auditEntry)
+				if !ok {
+					return cli.TypeError
+				}
+				return entry.Time.Format(timeFormat)
+			},
+		},
+		{
+			Title: "User",
+			CellTransformer: func(data interface{}) string {
+				entry, ok := data.(auditEntry)
+				if !ok {
+					return cli.TypeError
+				}
+				return entry.User
+			},
+		},
+		{
+			Title: "API Key",
+			CellTransformer: func(data interface{}) string {
+				entry, ok := data.(auditEntry)
+				if !ok {
+					return cli.TypeError
+				}
+				return globals.BooleanStyleP(entry.APIKey)
+			},
+		},
+		{
+			Title: "Source IP",
+			CellTransformer: func(data interface{}) string {
+				entry, ok := data.(auditEntry)
+				if !ok {
+					return cli.TypeError
+				}
+				return entry.SourceIP
+			},
+		},
+		{
+			Title: "Verb",
+			CellTransformer: func(data interface{}) string {
+				entry, ok := data.(auditEntry)
+				if !ok {
+					return cli.TypeError
+				}
+				return entry.Verb
+			},
+		},
+		{
+			Title: "Type",
+			CellTransformer: func(data interface{}) string {
+				entry, ok := data.(auditEntry)
+				if !ok {
+					return cli.TypeError
+				}
+				return entry.APIVersion + "." + entry.Type
+			},
+		},
+		{
+			Title: "Name",
+			CellTransformer: func(data interface{}) string {
+				entry, ok := data.(auditEntry)
+				if !ok {
+					return cli.TypeError
+				}
+				return entry.Name
+			},
+		},
+		{
+			Title:       "Namespace",
+			ColumnStyle: table.PrimaryTextStyle,
+			CellTransformer: func(data interface{}) string {
+				entry, ok := data.(auditEntry)
+				if !ok {
+					return cli.TypeError
+				}
+				return entry.Namespace
+			},
+		},
+	})
+
+	table.Render(writer, results)
+}
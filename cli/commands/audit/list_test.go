@@ -0,0 +1,106 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sensu/sensu-go/cli"
+	client "github.com/sensu/sensu-go/cli/client/testing"
+	"github.com/sensu/sensu-go/cli/commands/flags"
+	test "github.com/sensu/sensu-go/cli/commands/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newConfiguredCLI() *cli.SensuCli {
+	cli := test.NewMockCLI()
+	config := cli.Config.(*client.MockConfig)
+	config.On("Format").Return("json")
+	return cli
+}
+
+func mockEntries(cli *cli.SensuCli, path string, err error) {
+	client := cli.Client.(*client.MockClient)
+	client.On("Get", path, mock.Anything).Return(err).Run(
+		func(args mock.Arguments) {
+			if err != nil {
+				return
+			}
+			entries := args[1].(*[]auditEntry)
+			*entries = []auditEntry{
+				{
+					ID:         1,
+					Time:       time.Date(2024, 3, 16, 12, 0, 0, 0, time.UTC),
+					User:       "admin",
+					SourceIP:   "10.0.0.1",
+					Verb:       "update",
+					APIVersion: "core/v2",
+					Type:       "CheckConfig",
+					Namespace:  "default",
+					Name:       "cpu",
+				},
+			}
+		},
+	)
+}
+
+func TestListCommand(t *testing.T) {
+	now = func() time.Time { return time.Date(2024, 3, 16, 12, 0, 0, 0, time.UTC) }
+	defer func() { now = time.Now }()
+
+	cli := newConfiguredCLI()
+	mockEntries(cli, "/api/audit/v1/entries?limit=10&name=cpu&namespace=default&since=2024-03-15T12%3A00%3A00Z&user=admin", nil)
+
+	cmd := ListCommand(cli)
+	require.NoError(t, cmd.Flags().Set(flags.Format, "json"))
+	require.NoError(t, cmd.Flags().Set(flagUser, "admin"))
+	require.NoError(t, cmd.Flags().Set(flagName, "cpu"))
+	require.NoError(t, cmd.Flags().Set(flagSince, "24h"))
+	require.NoError(t, cmd.Flags().Set(flagLimit, "10"))
+	out, err := test.RunCmd(cmd, []string{})
+	require.NoError(t, err)
+
+	var entries []auditEntry
+	require.NoError(t, json.Unmarshal([]byte(out), &entries))
+	require.Len(t, entries, 1)
+	assert.Equal(t, "cpu", entries[0].Name)
+}
+
+func TestListCommandWithTable(t *testing.T) {
+	cli := newConfiguredCLI()
+	mockEntries(cli, "/api/audit/v1/entries?limit=100", nil)
+
+	cmd := ListCommand(cli)
+	require.NoError(t, cmd.Flags().Set(flags.Format, "none"))
+	require.NoError(t, cmd.Flags().Set(flags.AllNamespaces, "true"))
+	out, err := test.RunCmd(cmd, []string{})
+	require.NoError(t, err)
+
+	assert.Contains(t, out, "Source IP") // Heading
+	assert.Contains(t, out, "2024-03-16T12:00:00Z")
+	assert.Contains(t, out, "core/v2.CheckConfig")
+}
+
+func TestListCommandWithErr(t *testing.T) {
+	cli := newConfiguredCLI()
+	mockEntries(cli, "/api/audit/v1/entries?limit=100&namespace=default", errors.New("fun-msg"))
+
+	cmd := ListCommand(cli)
+	out, err := test.RunCmd(cmd, []string{})
+
+	assert.Empty(t, out)
+	require.Error(t, err)
+	assert.Equal(t, "fun-msg", err.Error())
+}
+
+func TestListCommandInvalidTime(t *testing.T) {
+	cli := newConfiguredCLI()
+
+	cmd := ListCommand(cli)
+	require.NoError(t, cmd.Flags().Set(flagUntil, "yesterday"))
+	_, err := test.RunCmd(cmd, []string{})
+	assert.Error(t, err)
+}
@@ -1,9 +1,11 @@
 package check
 
 import (
+	corev2 "github.com/sensu/core/v2"
 	"github.com/sensu/sensu-go/cli"
 	"github.com/sensu/sensu-go/cli/commands/check/subcommands"
 	"github.com/sensu/sensu-go/cli/commands/helpers"
+	"github.com/sensu/sensu-go/cli/commands/revision"
 	"github.com/spf13/cobra"
 )
 
@@ -71,6 +73,10 @@ func HelpCommand(cli *cli.SensuCli) *cobra.Command {
 		subcommands.SetOutputMetricHandlersCommand(cli),
 		subcommands.SetOutputMetricFormatCommand(cli),
 		subcommands.SetRoundRobinCommand(cli),
+
+		// Revision commands
+		revision.HistoryCommand(cli, &corev2.CheckConfig{}),
+		revision.RollbackCommand(cli, &corev2.CheckConfig{}),
 	)
 
 	return cmd
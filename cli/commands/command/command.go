@@ -1,11 +1,18 @@
 package command
 
 import (
+	"github.com/sensu/sensu-go/asset"
 	"github.com/sensu/sensu-go/cli"
+	"github.com/sensu/sensu-go/cli/cmdmanager"
 	"github.com/sensu/sensu-go/cli/commands/helpers"
 	"github.com/spf13/cobra"
 )
 
+const (
+	flagAssetSignaturePolicy = "asset-signature-policy"
+	flagAssetTrustedKeys     = "asset-trusted-keys"
+)
+
 // HelpCommand defines new "command" command
 func HelpCommand(cli *cli.SensuCli) *cobra.Command {
 	cmd := &cobra.Command{
@@ -14,6 +21,9 @@ func HelpCommand(cli *cli.SensuCli) *cobra.Command {
 		RunE:  helpers.DefaultSubCommandRunE,
 	}
 
+	cmd.PersistentFlags().String(flagAssetSignaturePolicy, string(asset.SignaturePolicyEnforce), "policy of the commands whose signature can't be verified with the trusted keys, warn or enforce")
+	cmd.PersistentFlags().StringSlice(flagAssetTrustedKeys, nil, "comma separated list of the files of the public keys trusted to sign the commands, minisign or PEM encoded ECDSA keys. The signatures of the commands are verified when set")
+
 	// Add sub-commands
 	cmd.AddCommand(ExecCommand(cli))
 	cmd.AddCommand(InstallCommand(cli))
@@ -22,3 +32,22 @@ func HelpCommand(cli *cli.SensuCli) *cobra.Command {
 
 	return cmd
 }
+
+// newCommandManager returns the manager of the command plugins, that verifies
+// the signatures of the commands with the trusted keys of the flags or of the
+// SENSU_ASSET_TRUSTED_KEYS environment variable.
+func newCommandManager(cli *cli.SensuCli, cmd *cobra.Command) (*cmdmanager.CommandManager, error) {
+	v, err := helpers.InitViper(cmd.Flags())
+	if err != nil {
+		return nil, err
+	}
+	policy := asset.SignaturePolicy(v.GetString(flagAssetSignaturePolicy))
+	if policy == "" {
+		policy = asset.SignaturePolicyEnforce
+	}
+	signatures, err := asset.NewSignatureVerifier(v.GetStringSlice(flagAssetTrustedKeys), policy)
+	if err != nil {
+		return nil, err
+	}
+	return cmdmanager.NewCommandManager(cli, signatures)
+}
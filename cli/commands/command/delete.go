@@ -5,7 +5,6 @@ import (
 	"fmt"
 
 	"github.com/sensu/sensu-go/cli"
-	"github.com/sensu/sensu-go/cli/cmdmanager"
 	"github.com/sensu/sensu-go/cli/commands/helpers"
 	"github.com/spf13/cobra"
 )
@@ -31,7 +30,7 @@ func DeleteCommand(cli *cli.SensuCli) *cobra.Command {
 				}
 			}
 
-			manager, err := cmdmanager.NewCommandManager(cli)
+			manager, err := newCommandManager(cli, cmd)
 			if err != nil {
 				return err
 			}
@@ -7,7 +7,6 @@ import (
 	"strconv"
 
 	"github.com/sensu/sensu-go/cli"
-	"github.com/sensu/sensu-go/cli/cmdmanager"
 	"github.com/spf13/cobra"
 )
 
@@ -30,7 +29,7 @@ func execCommandExecute(cli *cli.SensuCli) func(cmd *cobra.Command, args []strin
 			return errors.New("invalid argument(s) received")
 		}
 
-		manager, err := cmdmanager.NewCommandManager(cli)
+		manager, err := newCommandManager(cli, cmd)
 		if err != nil {
 			return err
 		}
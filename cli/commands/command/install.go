@@ -4,12 +4,12 @@ import (
 	"errors"
 
 	"github.com/sensu/sensu-go/cli"
-	"github.com/sensu/sensu-go/cli/cmdmanager"
 	"github.com/spf13/cobra"
 )
 
 var assetURL string
 var assetChecksum string
+var assetSignatureURL string
 
 // InstallCommand adds command that allows a user to install a command plugin
 // via Bonsai or a URL.
@@ -22,6 +22,7 @@ func InstallCommand(cli *cli.SensuCli) *cobra.Command {
 
 	cmd.Flags().StringVarP(&assetURL, "url", "", "", "specifies a URL to fetch a sensuctl command archive from")
 	cmd.Flags().StringVarP(&assetChecksum, "checksum", "", "", "specifies the checksum (SHA512) of the command archive from the --url flag")
+	cmd.Flags().StringVarP(&assetSignatureURL, "signature-url", "", "", "specifies a URL to fetch the signature of the command archive from, the --url flag with a .sig suffix by default")
 
 	return cmd
 }
@@ -50,7 +51,7 @@ func installCommandExecute(cli *cli.SensuCli) func(cmd *cobra.Command, args []st
 
 		alias := args[0]
 
-		manager, err := cmdmanager.NewCommandManager(cli)
+		manager, err := newCommandManager(cli, cmd)
 		if err != nil {
 			return err
 		}
@@ -60,7 +61,7 @@ func installCommandExecute(cli *cli.SensuCli) func(cmd *cobra.Command, args []st
 				return err
 			}
 		} else {
-			if err = manager.InstallCommandFromURL(alias, assetURL, assetChecksum); err != nil {
+			if err = manager.InstallCommandFromURL(alias, assetURL, assetChecksum, assetSignatureURL); err != nil {
 				return err
 			}
 		}
@@ -39,7 +39,7 @@ func listCommandExecute(cli *cli.SensuCli) func(cmd *cobra.Command, args []strin
 			return errors.New("invalid argument(s) received")
 		}
 
-		manager, err := cmdmanager.NewCommandManager(cli)
+		manager, err := newCommandManager(cli, cmd)
 		if err != nil {
 			return err
 		}
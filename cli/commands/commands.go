@@ -3,7 +3,9 @@ package commands
 import (
 	"github.com/sensu/sensu-go/cli"
 	"github.com/sensu/sensu-go/cli/commands/apikey"
+	"github.com/sensu/sensu-go/cli/commands/apply"
 	"github.com/sensu/sensu-go/cli/commands/asset"
+	"github.com/sensu/sensu-go/cli/commands/audit"
 	"github.com/sensu/sensu-go/cli/commands/check"
 	"github.com/sensu/sensu-go/cli/commands/clusterrole"
 	"github.com/sensu/sensu-go/cli/commands/clusterrolebinding"
@@ -23,6 +25,7 @@ import (
 	"github.com/sensu/sensu-go/cli/commands/handler"
 	"github.com/sensu/sensu-go/cli/commands/hook"
 	"github.com/sensu/sensu-go/cli/commands/logout"
+	"github.com/sensu/sensu-go/cli/commands/maintenance"
 	"github.com/sensu/sensu-go/cli/commands/mutator"
 	"github.com/sensu/sensu-go/cli/commands/namespace"
 	"github.com/sensu/sensu-go/cli/commands/pipeline"
@@ -45,6 +48,7 @@ func AddCommands(rootCmd *cobra.Command, cli *cli.SensuCli) {
 		// Management Commands
 		asset.HelpCommand(cli),
 		apikey.HelpCommand(cli),
+		audit.HelpCommand(cli),
 		check.HelpCommand(cli),
 		config.HelpCommand(cli),
 		clusterrole.HelpCommand(cli),
@@ -55,12 +59,14 @@ func AddCommands(rootCmd *cobra.Command, cli *cli.SensuCli) {
 		filter.HelpCommand(cli),
 		handler.HelpCommand(cli),
 		hook.HelpCommand(cli),
+		maintenance.HelpCommand(cli),
 		mutator.HelpCommand(cli),
 		namespace.HelpCommand(cli),
 		role.HelpCommand(cli),
 		rolebinding.HelpCommand(cli),
 		user.HelpCommand(cli),
 		silenced.HelpCommand(cli),
+		apply.Command(cli),
 		create.CreateCommand(cli),
 		delete.DeleteCommand(cli),
 		edit.Command(cli),
@@ -19,6 +19,8 @@ const (
 	FlagInsecureSkipTlsVerify = "insecure-skip-tls-verify"
 	FlagNamespace             = "namespace"
 	FlagNonInteractive        = "non-interactive"
+	FlagOIDC                  = "oidc"
+	FlagOIDCProvider          = "oidc-provider"
 	FlagPassword              = "password"
 	FlagTimeout               = "timeout"
 	FlagTrustedCaFile         = "trusted-ca-file"
@@ -30,6 +32,8 @@ type Answers struct {
 	URL                   string `survey:"url"`
 	Username              string `survey:"username"`
 	Password              string
+	OIDC                  bool
+	OIDCProvider          string
 	Format                string `survey:"format"`
 	Namespace             string `survey:"namespace"`
 	InsecureSkipTLSVerify bool
@@ -51,7 +55,7 @@ func Command(cli *cli.SensuCli) *cobra.Command {
 			}
 
 			nonInteractive := v.GetBool(FlagNonInteractive)
-			if nonInteractive {
+			if nonInteractive && !v.GetBool(FlagOIDC) {
 				username := v.GetString(FlagUsername)
 				password := v.GetString(FlagPassword)
 				if username == "" || password == "" {
@@ -75,7 +79,10 @@ func Command(cli *cli.SensuCli) *cobra.Command {
 
 			nonInteractive := v.GetBool(FlagNonInteractive)
 
-			answers := &Answers{}
+			answers := &Answers{
+				OIDC:         v.GetBool(FlagOIDC),
+				OIDCProvider: v.GetString(FlagOIDCProvider),
+			}
 			if nonInteractive {
 				answers.WithFlags(v)
 			} else {
@@ -90,7 +97,12 @@ func Command(cli *cli.SensuCli) *cobra.Command {
 				return err
 			}
 
-			if err := Authenticate(cli, answers); err != nil {
+			if answers.OIDC {
+				err = AuthenticateOIDC(cli, answers, cmd.OutOrStderr())
+			} else {
+				err = Authenticate(cli, answers)
+			}
+			if err != nil {
 				_, _ = fmt.Fprintln(cmd.OutOrStderr())
 				return err
 			}
@@ -114,19 +126,20 @@ func AddFlags(cli *cli.SensuCli, cmd *cobra.Command) {
 	_ = cmd.Flags().StringP(FlagUrl, "", cli.Config.APIUrl(), "the sensu backend url")
 	_ = cmd.Flags().StringP(FlagUsername, "", "", "username")
 	_ = cmd.Flags().StringP(FlagPassword, "", "", "password")
+	_ = cmd.Flags().BoolP(FlagOIDC, "", false, "log in with an OIDC provider, using a web browser")
+	_ = cmd.Flags().StringP(FlagOIDCProvider, "", "", "name of the OIDC provider to log in with, when several are configured")
 	_ = cmd.Flags().StringP(FlagFormat, "", cli.Config.Format(), "preferred output format")
 	_ = cmd.Flags().StringP(FlagNamespace, "", cli.Config.Namespace(), "namespace")
 	_ = cmd.Flags().DurationP(FlagTimeout, "", cli.Config.Timeout(), "timeout when communicating with backend url")
 }
 
 func (answers *Answers) AdministerQuestionnaire(c config.Config) error {
-	qs := []*survey.Question{
-		AskForURL(c),
-		AskForUsername(),
-		AskForPassword(),
-		AskForNamespace(c),
-		AskForDefaultFormat(c),
+	qs := []*survey.Question{AskForURL(c)}
+	// Users logging in with OIDC authenticate with their web browser
+	if !answers.OIDC {
+		qs = append(qs, AskForUsername(), AskForPassword())
 	}
+	qs = append(qs, AskForNamespace(c), AskForDefaultFormat(c))
 
 	return survey.Ask(qs, answers)
 }
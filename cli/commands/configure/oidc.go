@@ -0,0 +1,104 @@
+package configure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/sensu/sensu-go/cli"
+)
+
+// oidcLoginTimeout is the time given to the user to log in with their browser
+var oidcLoginTimeout = 5 * time.Minute
+
+// openBrowser opens the given URL with the default web browser
+var openBrowser = func(u string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("open", u).Start()
+	case "windows":
+		return exec.Command("rundll32", "url.dll,FileProtocolHandler", u).Start()
+	default:
+		return exec.Command("xdg-open", u).Start()
+	}
+}
+
+// AuthenticateOIDC logs the user in with an OIDC provider, using their web
+// browser. The backend redirects the browser to a local listener once the
+// user is authenticated, along with a code that is exchanged for the tokens.
+func AuthenticateOIDC(cli *cli.SensuCli, answers *Answers, out io.Writer) error {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return fmt.Errorf("unable to listen for the login callback: %s", err)
+	}
+	defer listener.Close()
+
+	codes := make(chan string, 1)
+	server := &http.Server{
+		ReadHeaderTimeout: 10 * time.Second,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/callback" {
+				http.NotFound(w, r)
+				return
+			}
+			code := r.URL.Query().Get("code")
+			if code == "" {
+				http.Error(w, "missing login code", http.StatusBadRequest)
+				return
+			}
+			select {
+			case codes <- code:
+				_, _ = fmt.Fprintln(w, "Login successful, you can close this window and return to sensuctl.")
+			default:
+				http.Error(w, "login already completed", http.StatusConflict)
+			}
+		}),
+	}
+	go func() { _ = server.Serve(listener) }()
+	defer server.Close()
+
+	query := url.Values{}
+	if answers.OIDCProvider != "" {
+		query.Set("provider", answers.OIDCProvider)
+	}
+	query.Set("redirect", fmt.Sprintf("http://%s/callback", listener.Addr()))
+	loginURL := strings.TrimSuffix(answers.URL, "/") + "/auth/oidc/authorize?" + query.Encode()
+
+	_, _ = fmt.Fprintf(out, "Opening the following URL in your browser to log in:\n\n  %s\n\n", loginURL)
+	if err := openBrowser(loginURL); err != nil {
+		_, _ = fmt.Fprintln(out, "Unable to open a browser, please open the URL manually.")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), oidcLoginTimeout)
+	defer cancel()
+
+	var code string
+	select {
+	case code = <-codes:
+	case <-ctx.Done():
+		return errors.New("timed out waiting for the login to complete")
+	}
+
+	tokens, err := cli.Client.CreateAccessTokenWithOIDCCode(answers.URL, code)
+	if err != nil {
+		return fmt.Errorf("unable to authenticate with error: %s", err)
+	}
+
+	// Write new credentials to disk
+	if err = cli.Config.SaveTokens(tokens); err != nil {
+		return fmt.Errorf(
+			"unable to write new configuration file with error: %s",
+			err,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package configure
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	v2 "github.com/sensu/core/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	client "github.com/sensu/sensu-go/cli/client/testing"
+	"github.com/sensu/sensu-go/cli/commands/root"
+	test "github.com/sensu/sensu-go/cli/commands/testing"
+)
+
+// completeOIDCLogin simulates a browser completing the login, by following
+// the redirect given to the backend with the login code.
+func completeOIDCLogin(t *testing.T, loginURL string) {
+	t.Helper()
+	u, err := url.Parse(loginURL)
+	require.NoError(t, err)
+	assert.Equal(t, "/auth/oidc/authorize", u.Path)
+	assert.Equal(t, "okta", u.Query().Get("provider"))
+
+	redirect := u.Query().Get("redirect")
+	resp, err := http.Get(redirect + "?code=1234")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestCommandRunEClosureWithOIDC(t *testing.T) {
+	defer func(open func(string) error) { openBrowser = open }(openBrowser)
+	openBrowser = func(u string) error {
+		go completeOIDCLogin(t, u)
+		return nil
+	}
+
+	cli := test.NewCLI()
+	mockClient := cli.Client.(*client.MockClient)
+	mockConfig := cli.Config.(*client.MockConfig)
+	tokens := &v2.Tokens{Access: "foo", Refresh: "bar"}
+	mockConfig.On("APIUrl").Return("http://127.0.0.1:8080")
+	mockConfig.On("SaveAPIUrl", mock.Anything).Return(nil)
+	mockClient.On("CreateAccessTokenWithOIDCCode", "http://127.0.0.1:8080", "1234").Return(tokens, nil)
+	mockConfig.On("SaveTokens", tokens).Return(nil)
+	mockConfig.On("SaveFormat", mock.Anything).Return(nil)
+	mockConfig.On("SaveNamespace", mock.Anything).Return(nil)
+	mockConfig.On("SaveInsecureSkipTLSVerify", mock.Anything).Return(nil)
+	mockConfig.On("SaveTrustedCAFile", mock.Anything).Return(nil)
+	mockConfig.On("SaveTimeout", mock.Anything).Return(nil)
+	mockConfig.On("Timeout").Return(time.Second * 15)
+
+	rootCmd := root.Command()
+	cmd := Command(cli)
+	require.NoError(t, cmd.Flags().Set("non-interactive", "true"))
+	require.NoError(t, cmd.Flags().Set("oidc", "true"))
+	require.NoError(t, cmd.Flags().Set("oidc-provider", "okta"))
+	require.NoError(t, cmd.Flags().Set("url", "http://127.0.0.1:8080"))
+	rootCmd.AddCommand(cmd)
+
+	buf := new(bytes.Buffer)
+	rootCmd.SetOutput(buf)
+	rootCmd.SetArgs([]string{"configure"})
+	_, err := rootCmd.ExecuteC()
+	require.NoError(t, err)
+
+	assert.Contains(t, buf.String(), "http://127.0.0.1:8080/auth/oidc/authorize?")
+	mockConfig.AssertCalled(t, "SaveTokens", tokens)
+	mockClient.AssertNotCalled(t, "CreateAccessToken", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthenticateOIDCTimeout(t *testing.T) {
+	defer func(open func(string) error) { openBrowser = open }(openBrowser)
+	defer func(timeout time.Duration) { oidcLoginTimeout = timeout }(oidcLoginTimeout)
+	openBrowser = func(string) error { return errors.New("no browser") }
+	oidcLoginTimeout = 10 * time.Millisecond
+
+	cli := test.NewMockCLI()
+	buf := new(bytes.Buffer)
+	err := AuthenticateOIDC(cli, &Answers{URL: "http://127.0.0.1:8080"}, buf)
+	assert.Error(t, err)
+	assert.Contains(t, buf.String(), "please open the URL manually")
+}
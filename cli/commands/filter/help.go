@@ -1,8 +1,10 @@
 package filter
 
 import (
+	corev2 "github.com/sensu/core/v2"
 	"github.com/sensu/sensu-go/cli"
 	"github.com/sensu/sensu-go/cli/commands/helpers"
+	"github.com/sensu/sensu-go/cli/commands/revision"
 	"github.com/spf13/cobra"
 )
 
@@ -26,6 +28,10 @@ func HelpCommand(cli *cli.SensuCli) *cobra.Command {
 		// properly.
 		//subcommands.RemoveWhenCommand(cli),
 		//subcommands.SetWhenCommand(cli),
+
+		// Revision commands
+		revision.HistoryCommand(cli, &corev2.EventFilter{}),
+		revision.RollbackCommand(cli, &corev2.EventFilter{}),
 	)
 
 	return cmd
@@ -0,0 +1,35 @@
+package maintenance
+
+import (
+	"time"
+
+	maintenancev1 "github.com/sensu/sensu-go/api/maintenance/v1"
+	"github.com/sensu/sensu-go/cli"
+	"github.com/sensu/sensu-go/cli/commands/helpers"
+	"github.com/sensu/sensu-go/cli/commands/revision"
+	"github.com/spf13/cobra"
+)
+
+const (
+	timeFormat = time.RFC3339
+)
+
+// now returns the current time, and is replaced in tests.
+var now = time.Now
+
+// HelpCommand defines new maintenance command
+func HelpCommand(cli *cli.SensuCli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "maintenance",
+		Short: "Manage maintenance windows",
+		RunE:  helpers.DefaultSubCommandRunE,
+	}
+
+	// Add sub-commands
+	cmd.AddCommand(ListCommand(cli))
+	cmd.AddCommand(UpcomingCommand(cli))
+	cmd.AddCommand(revision.HistoryCommand(cli, &maintenancev1.MaintenanceWindow{}))
+	cmd.AddCommand(revision.RollbackCommand(cli, &maintenancev1.MaintenanceWindow{}))
+
+	return cmd
+}
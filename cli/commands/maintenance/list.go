@@ -0,0 +1,168 @@
+package maintenance
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+	maintenancev1 "github.com/sensu/sensu-go/api/maintenance/v1"
+	"github.com/sensu/sensu-go/cli"
+	"github.com/sensu/sensu-go/cli/client"
+	"github.com/sensu/sensu-go/cli/commands/flags"
+	"github.com/sensu/sensu-go/cli/commands/helpers"
+	"github.com/sensu/sensu-go/cli/elements/table"
+
+	"github.com/spf13/cobra"
+)
+
+// ListCommand defines new list maintenance windows command
+func ListCommand(cli *cli.SensuCli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "list",
+		Short:        "list maintenance windows",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				_ = cmd.Help()
+				return errors.New("invalid argument(s) received")
+			}
+
+			opts, err := helpers.ListOptionsFromFlags(cmd.Flags())
+			if err != nil {
+				return err
+			}
+
+			var header http.Header
+			results, err := listWindows(cmd, cli, &opts, &header)
+			if err != nil {
+				return err
+			}
+
+			// Print the results based on the user preferences
+			resources := []corev3.Resource{}
+			for i := range results {
+				resources = append(resources, &results[i])
+			}
+			return helpers.PrintList(cmd, cli.Config.Format(), printToTable, resources, results, header)
+		},
+	}
+
+	helpers.AddFormatFlag(cmd.Flags())
+	helpers.AddAllNamespace(cmd.Flags())
+	helpers.AddFieldSelectorFlag(cmd.Flags())
+	helpers.AddLabelSelectorFlag(cmd.Flags())
+	helpers.AddChunkSizeFlag(cmd.Flags())
+
+	return cmd
+}
+
+// listWindows fetches the maintenance windows of the configured namespace, or
+// of all the namespaces.
+func listWindows(cmd *cobra.Command, cli *cli.SensuCli, opts *client.ListOptions, header *http.Header) ([]maintenancev1.MaintenanceWindow, error) {
+	namespace := cli.Config.Namespace()
+	if ok, _ := cmd.Flags().GetBool(flags.AllNamespaces); ok {
+		namespace = corev2.NamespaceTypeAll
+	}
+	results := []maintenancev1.MaintenanceWindow{}
+	err := cli.Client.List(client.MaintenanceWindowsPath(namespace), &results, opts, header)
+	return results, err
+}
+
+func printToTable(results interface{}, writer io.Writer) {
+	current := now()
+	table := table.New([]*table.Column{
+		{
+			Title:       "Name",
+			ColumnStyle: table.PrimaryTextStyle,
+			CellTransformer: func(data interface{}) string {
+				window, ok := data.(maintenancev1.MaintenanceWindow)
+				if !ok {
+					return cli.TypeError
+				}
+				return window.Metadata.Name
+			},
+		},
+		{
+			Title: "Schedule",
+			CellTransformer: func(data interface{}) string {
+				window, ok := data.(maintenancev1.MaintenanceWindow)
+				if !ok {
+					return cli.TypeError
+				}
+				if window.RRule != "" {
+					return window.RRule
+				}
+				return window.Cron
+			},
+		},
+		{
+			Title: "Duration",
+			CellTransformer: func(data interface{}) string {
+				window, ok := data.(maintenancev1.MaintenanceWindow)
+				if !ok {
+					return cli.TypeError
+				}
+				return window.DurationTime().String()
+			},
+		},
+		{
+			Title: "Timezone",
+			CellTransformer: func(data interface{}) string {
+				window, ok := data.(maintenancev1.MaintenanceWindow)
+				if !ok {
+					return cli.TypeError
+				}
+				if window.Timezone == "" {
+					return time.UTC.String()
+				}
+				return window.Timezone
+			},
+		},
+		{
+			Title: "Next Occurrence",
+			CellTransformer: func(data interface{}) string {
+				window, ok := data.(maintenancev1.MaintenanceWindow)
+				if !ok {
+					return cli.TypeError
+				}
+				occurrence, err := window.NextOccurrence(current)
+				if err != nil {
+					return "invalid"
+				}
+				if occurrence == nil {
+					return "none"
+				}
+				if occurrence.Active(current) {
+					return "active until " + occurrence.End.Format(timeFormat)
+				}
+				return occurrence.Start.Format(timeFormat)
+			},
+		},
+		{
+			Title: "Reason",
+			CellTransformer: func(data interface{}) string {
+				window, ok := data.(maintenancev1.MaintenanceWindow)
+				if !ok {
+					return cli.TypeError
+				}
+				return window.Reason
+			},
+		},
+		{
+			Title:       "Namespace",
+			ColumnStyle: table.PrimaryTextStyle,
+			CellTransformer: func(data interface{}) string {
+				window, ok := data.(maintenancev1.MaintenanceWindow)
+				if !ok {
+					return cli.TypeError
+				}
+				return window.Metadata.Namespace
+			},
+		},
+	})
+
+	table.Render(writer, results)
+}
@@ -0,0 +1,81 @@
+package maintenance
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	maintenancev1 "github.com/sensu/sensu-go/api/maintenance/v1"
+	"github.com/sensu/sensu-go/cli"
+	client "github.com/sensu/sensu-go/cli/client/testing"
+	"github.com/sensu/sensu-go/cli/commands/flags"
+	test "github.com/sensu/sensu-go/cli/commands/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListCommandRunEClosureWithTable(t *testing.T) {
+	now = func() time.Time { return time.Date(2024, 3, 16, 12, 0, 0, 0, time.UTC) }
+	defer func() { now = time.Now }()
+
+	cli := newConfiguredCLI()
+	mockWindows(cli, nil)
+
+	cmd := ListCommand(cli)
+	require.NoError(t, cmd.Flags().Set(flags.Format, "none"))
+	out, err := test.RunCmd(cmd, []string{})
+	require.NoError(t, err)
+
+	assert.Contains(t, out, "Next Occurrence") // Heading
+	assert.Contains(t, out, "patching")
+	assert.Contains(t, out, "2024-03-17T02:00:00Z")
+}
+
+func TestListCommandRunEClosureWithJSON(t *testing.T) {
+	cli := newConfiguredCLI()
+	mockWindows(cli, nil)
+
+	cmd := ListCommand(cli)
+	require.NoError(t, cmd.Flags().Set(flags.Format, "json"))
+	out, err := test.RunCmd(cmd, []string{})
+	require.NoError(t, err)
+
+	assert.Contains(t, out, "patching")
+	assert.Contains(t, out, "MaintenanceWindow")
+}
+
+func TestListCommandRunEClosureWithErr(t *testing.T) {
+	cli := newConfiguredCLI()
+	mockWindows(cli, errors.New("fun-msg"))
+
+	cmd := ListCommand(cli)
+	out, err := test.RunCmd(cmd, []string{})
+
+	assert.Empty(t, out)
+	require.Error(t, err)
+	assert.Equal(t, "fun-msg", err.Error())
+}
+
+func newConfiguredCLI() *cli.SensuCli {
+	cli := test.NewMockCLI()
+	config := cli.Config.(*client.MockConfig)
+	config.On("Format").Return("json")
+	return cli
+}
+
+func mockWindows(cli *cli.SensuCli, err error) {
+	client := cli.Client.(*client.MockClient)
+	resources := []maintenancev1.MaintenanceWindow{}
+	client.On("List", mock.Anything, &resources, mock.Anything, mock.Anything).Return(err).Run(
+		func(args mock.Arguments) {
+			if err != nil {
+				return
+			}
+			resources := args[1].(*[]maintenancev1.MaintenanceWindow)
+			*resources = []maintenancev1.MaintenanceWindow{
+				*maintenancev1.FixtureMaintenanceWindow("patching"),
+			}
+		},
+	)
+}
@@ -0,0 +1,171 @@
+package maintenance
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/sensu/sensu-go/cli"
+	"github.com/sensu/sensu-go/cli/commands/helpers"
+	"github.com/sensu/sensu-go/cli/elements/globals"
+	"github.com/sensu/sensu-go/cli/elements/table"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagWithin = "within"
+
+	defaultWithin = 7 * 24 * time.Hour
+)
+
+// upcomingOccurrence is an occurrence of a maintenance window, as printed by
+// the upcoming command.
+type upcomingOccurrence struct {
+	Namespace string    `json:"namespace" yaml:"namespace"`
+	Window    string    `json:"maintenance_window" yaml:"maintenance_window"`
+	Start     time.Time `json:"start" yaml:"start"`
+	End       time.Time `json:"end" yaml:"end"`
+	Active    bool      `json:"active" yaml:"active"`
+	Reason    string    `json:"reason,omitempty" yaml:"reason,omitempty"`
+}
+
+// UpcomingCommand defines new command to list the upcoming occurrences of
+// the maintenance windows
+func UpcomingCommand(cli *cli.SensuCli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "upcoming",
+		Short:        "list the active and upcoming occurrences of maintenance windows",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				_ = cmd.Help()
+				return errors.New("invalid argument(s) received")
+			}
+			within, err := cmd.Flags().GetDuration(flagWithin)
+			if err != nil {
+				return err
+			}
+			if within <= 0 {
+				return fmt.Errorf("--%s must be greater than zero", flagWithin)
+			}
+
+			opts, err := helpers.ListOptionsFromFlags(cmd.Flags())
+			if err != nil {
+				return err
+			}
+
+			var header http.Header
+			windows, err := listWindows(cmd, cli, &opts, &header)
+			if err != nil {
+				return err
+			}
+
+			current := now()
+			occurrences := []upcomingOccurrence{}
+			for _, window := range windows {
+				windowOccurrences, err := window.Occurrences(current, current.Add(within))
+				if err != nil {
+					return fmt.Errorf("invalid maintenance window %s: %s", window.Metadata.Name, err)
+				}
+				for _, occurrence := range windowOccurrences {
+					occurrences = append(occurrences, upcomingOccurrence{
+						Namespace: window.Metadata.Namespace,
+						Window:    window.Metadata.Name,
+						Start:     occurrence.Start,
+						End:       occurrence.End,
+						Active:    occurrence.Active(current),
+						Reason:    window.Reason,
+					})
+				}
+			}
+			sort.SliceStable(occurrences, func(i, j int) bool {
+				return occurrences[i].Start.Before(occurrences[j].Start)
+			})
+
+			return helpers.Print(cmd, cli.Config.Format(), printUpcomingToTable, nil, occurrences)
+		},
+	}
+
+	helpers.AddFormatFlag(cmd.Flags())
+	helpers.AddAllNamespace(cmd.Flags())
+	helpers.AddFieldSelectorFlag(cmd.Flags())
+	helpers.AddLabelSelectorFlag(cmd.Flags())
+	helpers.AddChunkSizeFlag(cmd.Flags())
+
+	_ = cmd.Flags().Duration(flagWithin, defaultWithin, "list the occurrences that start within this duration")
+
+	return cmd
+}
+
+func printUpcomingToTable(results interface{}, writer io.Writer) {
+	table := table.New([]*table.Column{
+		{
+			Title:       "Maintenance Window",
+			ColumnStyle: table.PrimaryTextStyle,
+			CellTransformer: func(data interface{}) string {
+				occurrence, ok := data.(upcomingOccurrence)
+				if !ok {
+					return cli.TypeError
+				}
+				return occurrence.Window
+			},
+		},
+		{
+			Title: "Start",
+			CellTransformer: func(data interface{}) string {
+				occurrence, ok := data.(upcomingOccurrence)
+				if !ok {
+					return cli.TypeError
+				}
+				return occurrence.Start.Format(timeFormat)
+			},
+		},
+		{
+			Title: "End",
+			CellTransformer: func(data interface{}) string {
+				occurrence, ok := data.(upcomingOccurrence)
+				if !ok {
+					return cli.TypeError
+				}
+				return occurrence.End.Format(timeFormat)
+			},
+		},
+		{
+			Title: "Active",
+			CellTransformer: func(data interface{}) string {
+				occurrence, ok := data.(upcomingOccurrence)
+				if !ok {
+					return cli.TypeError
+				}
+				return globals.BooleanStyleP(occurrence.Active)
+			},
+		},
+		{
+			Title: "Reason",
+			CellTransformer: func(data interface{}) string {
+				occurrence, ok := data.(upcomingOccurrence)
+				if !ok {
+					return cli.TypeError
+				}
+				return occurrence.Reason
+			},
+		},
+		{
+			Title:       "Namespace",
+			ColumnStyle: table.PrimaryTextStyle,
+			CellTransformer: func(data interface{}) string {
+				occurrence, ok := data.(upcomingOccurrence)
+				if !ok {
+					return cli.TypeError
+				}
+				return occurrence.Namespace
+			},
+		},
+	})
+
+	table.Render(writer, results)
+}
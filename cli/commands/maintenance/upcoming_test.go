@@ -0,0 +1,59 @@
+package maintenance
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/sensu/sensu-go/cli/commands/flags"
+	test "github.com/sensu/sensu-go/cli/commands/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpcomingCommand(t *testing.T) {
+	now = func() time.Time { return time.Date(2024, 3, 16, 12, 0, 0, 0, time.UTC) }
+	defer func() { now = time.Now }()
+
+	cli := newConfiguredCLI()
+	mockWindows(cli, nil)
+
+	cmd := UpcomingCommand(cli)
+	require.NoError(t, cmd.Flags().Set(flags.Format, "json"))
+	require.NoError(t, cmd.Flags().Set(flagWithin, "336h"))
+	out, err := test.RunCmd(cmd, []string{})
+	require.NoError(t, err)
+
+	var occurrences []upcomingOccurrence
+	require.NoError(t, json.Unmarshal([]byte(out), &occurrences))
+	require.Len(t, occurrences, 2)
+	assert.Equal(t, "patching", occurrences[0].Window)
+	assert.Equal(t, time.Date(2024, 3, 17, 2, 0, 0, 0, time.UTC), occurrences[0].Start.UTC())
+	assert.Equal(t, time.Date(2024, 3, 24, 4, 0, 0, 0, time.UTC), occurrences[1].End.UTC())
+}
+
+func TestUpcomingCommandWithTable(t *testing.T) {
+	now = func() time.Time { return time.Date(2024, 3, 17, 3, 0, 0, 0, time.UTC) }
+	defer func() { now = time.Now }()
+
+	cli := newConfiguredCLI()
+	mockWindows(cli, nil)
+
+	cmd := UpcomingCommand(cli)
+	require.NoError(t, cmd.Flags().Set(flags.Format, "none"))
+	out, err := test.RunCmd(cmd, []string{})
+	require.NoError(t, err)
+
+	assert.Contains(t, out, "Maintenance Window") // Heading
+	assert.Contains(t, out, "2024-03-17T02:00:00Z")
+	assert.Contains(t, out, "2024-03-24T02:00:00Z")
+}
+
+func TestUpcomingCommandInvalidWithin(t *testing.T) {
+	cli := newConfiguredCLI()
+
+	cmd := UpcomingCommand(cli)
+	require.NoError(t, cmd.Flags().Set(flagWithin, "0s"))
+	_, err := test.RunCmd(cmd, []string{})
+	assert.Error(t, err)
+}
@@ -1,8 +1,10 @@
 package mutator
 
 import (
+	corev2 "github.com/sensu/core/v2"
 	"github.com/sensu/sensu-go/cli"
 	"github.com/sensu/sensu-go/cli/commands/helpers"
+	"github.com/sensu/sensu-go/cli/commands/revision"
 	"github.com/spf13/cobra"
 )
 
@@ -21,6 +23,10 @@ func HelpCommand(cli *cli.SensuCli) *cobra.Command {
 		InfoCommand(cli),
 		ListCommand(cli),
 		UpdateCommand(cli),
+
+		// Revision commands
+		revision.HistoryCommand(cli, &corev2.Mutator{}),
+		revision.RollbackCommand(cli, &corev2.Mutator{}),
 	)
 
 	return cmd
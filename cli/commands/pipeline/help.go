@@ -1,8 +1,10 @@
 package pipeline
 
 import (
+	corev2 "github.com/sensu/core/v2"
 	"github.com/sensu/sensu-go/cli"
 	"github.com/sensu/sensu-go/cli/commands/helpers"
+	"github.com/sensu/sensu-go/cli/commands/revision"
 	"github.com/spf13/cobra"
 )
 
@@ -18,6 +20,8 @@ func HelpCommand(cli *cli.SensuCli) *cobra.Command {
 	cmd.AddCommand(ListCommand(cli))
 	cmd.AddCommand(InfoCommand(cli))
 	cmd.AddCommand(DeleteCommand(cli))
+	cmd.AddCommand(revision.HistoryCommand(cli, &corev2.Pipeline{}))
+	cmd.AddCommand(revision.RollbackCommand(cli, &corev2.Pipeline{}))
 
 	return cmd
 }
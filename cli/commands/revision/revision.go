@@ -0,0 +1,219 @@
+// Package revision provides the commands that list the revisions of a
+// resource, compare them and roll the resource back to one of them. They are
+// added to the commands of every resource type whose history is kept.
+package revision
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/pmezard/go-difflib/difflib"
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+	"github.com/sensu/sensu-go/cli"
+	"github.com/sensu/sensu-go/cli/commands/helpers"
+	"github.com/sensu/sensu-go/cli/elements/table"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagDiff     = "diff"
+	flagTo       = "to"
+	flagRevision = "revision"
+
+	timeFormat = time.RFC3339
+)
+
+// revision is a revision of a resource, as returned by the API.
+type revision struct {
+	Revision  int64       `json:"revision" yaml:"revision"`
+	Operation string      `json:"operation" yaml:"operation"`
+	Time      time.Time   `json:"time" yaml:"time"`
+	Resource  interface{} `json:"resource,omitempty" yaml:"resource,omitempty"`
+}
+
+// revisionDiff is the comparison of two revisions, as returned by the API.
+type revisionDiff struct {
+	From revision `json:"from"`
+	To   revision `json:"to"`
+}
+
+// resourcePath returns the API path of the named resource of the type of
+// kind, in the namespace of the CLI.
+func resourcePath(cli *cli.SensuCli, kind corev3.Resource, name string) string {
+	resource := reflect.New(reflect.TypeOf(kind).Elem()).Interface().(corev3.Resource)
+	resource.SetMetadata(&corev2.ObjectMeta{
+		Namespace: cli.Config.Namespace(),
+		Name:      name,
+	})
+	return resource.URIPath()
+}
+
+// HistoryCommand defines a new command to list the revisions of a resource
+// of the type of kind, or to compare two of them.
+func HistoryCommand(cli *cli.SensuCli, kind corev3.Resource) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "history [NAME]",
+		Short:        "list the revisions of a resource, most recent first, or compare two of them",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				_ = cmd.Help()
+				return errors.New("invalid argument(s) received")
+			}
+			path := resourcePath(cli, kind, args[0]) + "/history"
+
+			from, _ := cmd.Flags().GetInt64(flagDiff)
+			to, _ := cmd.Flags().GetInt64(flagTo)
+			if from > 0 {
+				return printDiff(cmd, cli, path, args[0], from, to)
+			}
+			if to > 0 {
+				return fmt.Errorf("--%s requires --%s", flagTo, flagDiff)
+			}
+
+			revisions := []revision{}
+			if err := cli.Client.Get(path, &revisions); err != nil {
+				return err
+			}
+			return helpers.Print(cmd, cli.Config.Format(), printToTable, nil, revisions)
+		},
+	}
+
+	helpers.AddFormatFlag(cmd.Flags())
+	_ = cmd.Flags().Int64(flagDiff, 0, "compare this revision with the latest revision, or with the revision given by --to")
+	_ = cmd.Flags().Int64(flagTo, 0, "revision compared by --diff, the latest revision by default")
+
+	return cmd
+}
+
+// printDiff prints the unified diff of two revisions of a resource.
+func printDiff(cmd *cobra.Command, cli *cli.SensuCli, path, name string, from, to int64) error {
+	query := url.Values{}
+	query.Set("from", strconv.FormatInt(from, 10))
+	if to > 0 {
+		query.Set("to", strconv.FormatInt(to, 10))
+	}
+	var diff revisionDiff
+	if err := cli.Client.Get(path+"/diff?"+query.Encode(), &diff); err != nil {
+		return err
+	}
+
+	a, err := toYAML(diff.From)
+	if err != nil {
+		return err
+	}
+	b, err := toYAML(diff.To)
+	if err != nil {
+		return err
+	}
+	out, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        a,
+		B:        b,
+		FromFile: fmt.Sprintf("%s (revision %d)", name, diff.From.Revision),
+		ToFile:   fmt.Sprintf("%s (revision %d)", name, diff.To.Revision),
+		Context:  3,
+	})
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		out = fmt.Sprintf("revisions %d and %d are identical\n", diff.From.Revision, diff.To.Revision)
+	}
+	_, err = fmt.Fprint(cmd.OutOrStdout(), out)
+	return err
+}
+
+// toYAML returns the lines of the YAML representation of the resource of a
+// revision.
+func toYAML(r revision) ([]string, error) {
+	b, err := json.Marshal(r.Resource)
+	if err != nil {
+		return nil, err
+	}
+	b, err = yaml.JSONToYAML(b)
+	if err != nil {
+		return nil, err
+	}
+	return difflib.SplitLines(strings.TrimSpace(string(b)) + "\n"), nil
+}
+
+// RollbackCommand defines a new command to restore a resource of the type of
+// kind as it was at a revision.
+func RollbackCommand(cli *cli.SensuCli, kind corev3.Resource) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "rollback [NAME]",
+		Short:        "restore a resource as it was at a revision",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				_ = cmd.Help()
+				return errors.New("invalid argument(s) received")
+			}
+			number, _ := cmd.Flags().GetInt64(flagRevision)
+			if number <= 0 {
+				return fmt.Errorf("must provide a --%s", flagRevision)
+			}
+
+			query := url.Values{}
+			query.Set("revision", strconv.FormatInt(number, 10))
+			path := resourcePath(cli, kind, args[0]) + "/rollback?" + query.Encode()
+			if err := cli.Client.Put(path, nil); err != nil {
+				return err
+			}
+
+			_, err := fmt.Fprintf(cmd.OutOrStdout(), "Rolled back to revision %d\n", number)
+			return err
+		},
+	}
+
+	_ = cmd.Flags().Int64(flagRevision, 0, "revision to roll back to, as listed by the history command")
+
+	return cmd
+}
+
+func printToTable(results interface{}, writer io.Writer) {
+	table := table.New([]*table.Column{
+		{
+			Title:       "Revision",
+			ColumnStyle: table.PrimaryTextStyle,
+			CellTransformer: func(data interface{}) string {
+				r, ok := data.(revision)
+				if !ok {
+					return cli.TypeError
+				}
+				return strconv.FormatInt(r.Revision, 10)
+			},
+		},
+		{
+			Title: "Time",
+			CellTransformer: func(data interface{}) string {
+				r, ok := data.(revision)
+				if !ok {
+					return cli.TypeError
+				}
+				return r.Time.Format(timeFormat)
+			},
+		},
+		{
+			Title: "Operation",
+			CellTransformer: func(data interface{}) string {
+				r, ok := data.(revision)
+				if !ok {
+					return cli.TypeError
+				}
+				return r.Operation
+			},
+		},
+	})
+
+	table.Render(writer, results)
+}
@@ -0,0 +1,143 @@
+package revision
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/cli"
+	client "github.com/sensu/sensu-go/cli/client/testing"
+	"github.com/sensu/sensu-go/cli/commands/flags"
+	test "github.com/sensu/sensu-go/cli/commands/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newConfiguredCLI() *cli.SensuCli {
+	cli := test.NewMockCLI()
+	config := cli.Config.(*client.MockConfig)
+	config.On("Format").Return("json")
+	return cli
+}
+
+func fixtureRevision(number int64, operation, command string) revision {
+	return revision{
+		Revision:  number,
+		Operation: operation,
+		Time:      time.Date(2024, 3, 16, 12, int(number), 0, 0, time.UTC),
+		Resource: map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "cpu", "namespace": "default"},
+			"command":  command,
+		},
+	}
+}
+
+func TestHistoryCommand(t *testing.T) {
+	cli := newConfiguredCLI()
+	client := cli.Client.(*client.MockClient)
+	client.On("Get", "/api/core/v2/namespaces/default/checks/cpu/history", mock.Anything).Return(nil).Run(
+		func(args mock.Arguments) {
+			revisions := args[1].(*[]revision)
+			*revisions = []revision{
+				fixtureRevision(2, "update", "check-cpu --warning 90"),
+				fixtureRevision(1, "create", "check-cpu"),
+			}
+		},
+	)
+
+	cmd := HistoryCommand(cli, &corev2.CheckConfig{})
+	require.NoError(t, cmd.Flags().Set(flags.Format, "json"))
+	out, err := test.RunCmd(cmd, []string{"cpu"})
+	require.NoError(t, err)
+
+	var revisions []revision
+	require.NoError(t, json.Unmarshal([]byte(out), &revisions))
+	require.Len(t, revisions, 2)
+	assert.Equal(t, int64(2), revisions[0].Revision)
+}
+
+func TestHistoryCommandWithTable(t *testing.T) {
+	cli := newConfiguredCLI()
+	client := cli.Client.(*client.MockClient)
+	client.On("Get", "/api/core/v2/namespaces/default/checks/cpu/history", mock.Anything).Return(nil).Run(
+		func(args mock.Arguments) {
+			revisions := args[1].(*[]revision)
+			*revisions = []revision{fixtureRevision(1, "create", "check-cpu")}
+		},
+	)
+
+	cmd := HistoryCommand(cli, &corev2.CheckConfig{})
+	require.NoError(t, cmd.Flags().Set(flags.Format, "none"))
+	out, err := test.RunCmd(cmd, []string{"cpu"})
+	require.NoError(t, err)
+	assert.Contains(t, out, "Revision")
+	assert.Contains(t, out, "create")
+}
+
+func TestHistoryCommandDiff(t *testing.T) {
+	cli := newConfiguredCLI()
+	client := cli.Client.(*client.MockClient)
+	client.On("Get", "/api/core/v2/namespaces/default/checks/cpu/history/diff?from=1&to=2", mock.Anything).Return(nil).Run(
+		func(args mock.Arguments) {
+			diff := args[1].(*revisionDiff)
+			diff.From = fixtureRevision(1, "create", "check-cpu")
+			diff.To = fixtureRevision(2, "update", "check-cpu --warning 90")
+		},
+	)
+
+	cmd := HistoryCommand(cli, &corev2.CheckConfig{})
+	require.NoError(t, cmd.Flags().Set(flagDiff, "1"))
+	require.NoError(t, cmd.Flags().Set(flagTo, "2"))
+	out, err := test.RunCmd(cmd, []string{"cpu"})
+	require.NoError(t, err)
+	assert.Contains(t, out, "--- cpu (revision 1)")
+	assert.Contains(t, out, "+++ cpu (revision 2)")
+	assert.Contains(t, out, "-command: check-cpu\n")
+	assert.Contains(t, out, "+command: check-cpu --warning 90\n")
+}
+
+func TestHistoryCommandErrors(t *testing.T) {
+	cli := newConfiguredCLI()
+	client := cli.Client.(*client.MockClient)
+	client.On("Get", "/api/core/v2/namespaces/default/checks/missing/history", mock.Anything).Return(errors.New("not found"))
+
+	_, err := test.RunCmd(HistoryCommand(cli, &corev2.CheckConfig{}), []string{})
+	assert.Error(t, err)
+
+	_, err = test.RunCmd(HistoryCommand(cli, &corev2.CheckConfig{}), []string{"missing"})
+	assert.Error(t, err)
+
+	cmd := HistoryCommand(cli, &corev2.CheckConfig{})
+	require.NoError(t, cmd.Flags().Set(flagTo, "2"))
+	_, err = test.RunCmd(cmd, []string{"cpu"})
+	assert.Error(t, err)
+}
+
+func TestRollbackCommand(t *testing.T) {
+	cli := newConfiguredCLI()
+	client := cli.Client.(*client.MockClient)
+	client.On("Put", "/api/core/v2/namespaces/default/checks/cpu/rollback?revision=1", nil).Return(nil)
+
+	cmd := RollbackCommand(cli, &corev2.CheckConfig{})
+	require.NoError(t, cmd.Flags().Set(flagRevision, "1"))
+	out, err := test.RunCmd(cmd, []string{"cpu"})
+	require.NoError(t, err)
+	assert.Equal(t, "Rolled back to revision 1\n", out)
+}
+
+func TestRollbackCommandErrors(t *testing.T) {
+	cli := newConfiguredCLI()
+	client := cli.Client.(*client.MockClient)
+	client.On("Put", "/api/core/v2/namespaces/default/checks/cpu/rollback?revision=3", nil).Return(errors.New("revision 3 is a deletion"))
+
+	_, err := test.RunCmd(RollbackCommand(cli, &corev2.CheckConfig{}), []string{"cpu"})
+	assert.Error(t, err)
+
+	cmd := RollbackCommand(cli, &corev2.CheckConfig{})
+	require.NoError(t, cmd.Flags().Set(flagRevision, "3"))
+	_, err = test.RunCmd(cmd, []string{"cpu"})
+	assert.Error(t, err)
+}
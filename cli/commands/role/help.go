@@ -1,8 +1,10 @@
 package role
 
 import (
+	corev2 "github.com/sensu/core/v2"
 	"github.com/sensu/sensu-go/cli"
 	"github.com/sensu/sensu-go/cli/commands/helpers"
+	"github.com/sensu/sensu-go/cli/commands/revision"
 	"github.com/spf13/cobra"
 )
 
@@ -20,6 +22,10 @@ func HelpCommand(cli *cli.SensuCli) *cobra.Command {
 		DeleteCommand(cli),
 		ListCommand(cli),
 		InfoCommand(cli),
+
+		// Revision commands
+		revision.HistoryCommand(cli, &corev2.Role{}),
+		revision.RollbackCommand(cli, &corev2.Role{}),
 	)
 
 	return cmd
@@ -0,0 +1,327 @@
+package resource
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pmezard/go-difflib/difflib"
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+	"github.com/sensu/core/v3/types"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/cli/client"
+	"github.com/sensu/sensu-go/util/compat"
+)
+
+// etagAnnotation is the annotation of the live resources that contains their
+// etag, which is ignored when comparing them with their definitions.
+const etagAnnotation = "sensu.io/etag"
+
+// pruneChunkSize is the number of resources fetched per request when listing
+// the resources to prune.
+const pruneChunkSize = 100
+
+// Applier is a Processor that reconciles the resources of the API with their
+// definitions. It prints a unified diff of every resource that differs from
+// its definition, and puts it unless DryRun is set. With DryRun, the resources
+// that differ are put in dry-run mode instead, so that the API validates them,
+// tells whether they would be created or updated, and warns about their
+// missing references.
+//
+// With Prune, the resources of the API that are managed by ManagedBy but
+// missing from the definitions are deleted, within the namespaces of the
+// definitions and the given Namespaces.
+type Applier struct {
+	// ManagedBy is the value of the corev2.ManagedByLabel label applied to
+	// the resources, and of the resources pruned.
+	ManagedBy string
+
+	// Prune enables the deletion of the managed resources that are missing
+	// from the definitions.
+	Prune bool
+
+	// DryRun only prints the changes, without applying them.
+	DryRun bool
+
+	// Namespaces are the namespaces pruned in addition to the namespaces of
+	// the definitions.
+	Namespaces []string
+
+	// Types are the types of the resources pruned. Defaults to All, except
+	// events.
+	Types []corev3.Resource
+
+	// Out is where the diffs and changes are printed.
+	Out io.Writer
+
+	// Changes is the number of resources that were created, updated or
+	// deleted, or that would be with DryRun.
+	Changes int
+}
+
+// NewApplier instantiates a new Applier Processor.
+func NewApplier(managedBy string, out io.Writer) *Applier {
+	return &Applier{
+		ManagedBy: managedBy,
+		Out:       out,
+	}
+}
+
+// Process applies the resources to the API.
+func (a *Applier) Process(client client.GenericClient, resources []*types.Wrapper) error {
+	labeler := &ManagedByLabelPutter{Label: a.ManagedBy}
+	defined := make(map[string]bool, len(resources))
+	namespaces := append([]string{}, a.Namespaces...)
+	for i, resource := range resources {
+		labeler.label(resource)
+		path := compat.URIPath(resource.Value)
+		defined[path] = true
+		if namespace := compat.GetObjectMeta(resource.Value).Namespace; namespace != "" {
+			namespaces = append(namespaces, namespace)
+		}
+
+		live, err := getLive(client, path)
+		if err != nil {
+			return fmt.Errorf("error getting resource #%d: %s", i, err)
+		}
+		name := describe(resource)
+		if a.DryRun {
+			changed, err := a.dryRun(client, name, live, resource)
+			if err != nil {
+				return fmt.Errorf("error validating resource #%d: %s", i, err)
+			}
+			if changed {
+				a.Changes++
+			}
+			continue
+		}
+		changed, err := a.diff(name, live, resource)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			continue
+		}
+		a.Changes++
+		if live == nil {
+			a.printChange(name, "created")
+		} else {
+			a.printChange(name, "configured")
+		}
+		if err := client.PutResource(*resource); err != nil {
+			return fmt.Errorf("error putting resource #%d: %s", i, err)
+		}
+	}
+
+	if a.Prune {
+		return a.prune(client, defined, namespaces)
+	}
+	return nil
+}
+
+// dryRun puts a resource in dry-run mode, and prints the diff of the live
+// resource and of the resource as the API would persist it, along with the
+// change the API would make and its warnings. The fields the API normalizes,
+// e.g. its default values, are then not reported as changes. It returns false
+// if the resource would not change.
+func (a *Applier) dryRun(client client.GenericClient, name string, live, resource *types.Wrapper) (bool, error) {
+	result, err := client.DryRunResource(*resource)
+	if err != nil {
+		return false, err
+	}
+	normalized := resource
+	if result.Resource != nil {
+		normalized = result.Resource
+	}
+	changed, err := a.diff(name, live, normalized)
+	if err != nil || !changed {
+		return false, err
+	}
+	if result.Operation == "update" {
+		a.printChange(name, "configured")
+	} else {
+		a.printChange(name, "created")
+	}
+	for _, warning := range result.Warnings {
+		_, _ = fmt.Fprintf(a.Out, "%s warning: %s: %s\n", name, warning.Field, warning.Message)
+	}
+	return true, nil
+}
+
+// getLive returns the resource of the API at the given path, or nil if it
+// doesn't exist.
+func getLive(client client.GenericClient, path string) (*types.Wrapper, error) {
+	var live types.Wrapper
+	if err := client.Get(path, &live); err != nil {
+		if isIgnoredError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if live.Value == nil {
+		return nil, nil
+	}
+	return &live, nil
+}
+
+// isIgnoredError returns whether an API error means that the resource, or
+// its type, doesn't exist for the user.
+func isIgnoredError(err error) bool {
+	if err, ok := err.(client.APIError); ok {
+		switch actions.ErrCode(err.Code) {
+		case actions.PaymentRequired, actions.NotFound, actions.PermissionDenied:
+			return true
+		}
+	}
+	return false
+}
+
+// prune deletes the managed resources of the API that are not defined.
+func (a *Applier) prune(apiClient client.GenericClient, defined map[string]bool, namespaces []string) error {
+	resourceTypes := a.Types
+	if resourceTypes == nil {
+		for _, resource := range All {
+			if _, ok := resource.(*corev2.Event); !ok {
+				resourceTypes = append(resourceTypes, resource)
+			}
+		}
+	}
+
+	// Delete the namespaced resources first, in the reverse order of their
+	// types, so that namespaces are deleted last
+	listed := make(map[string]bool)
+	for i := len(resourceTypes) - 1; i >= 0; i-- {
+		for _, namespace := range namespaces {
+			resource := reflect.New(reflect.TypeOf(resourceTypes[i]).Elem()).Interface().(corev3.Resource)
+			resource.SetMetadata(&corev2.ObjectMeta{Namespace: namespace})
+			listPath := resource.URIPath()
+			if listed[listPath] {
+				continue
+			}
+			listed[listPath] = true
+
+			var wrappers []*types.Wrapper
+			if err := apiClient.List(listPath, &wrappers, &client.ListOptions{ChunkSize: pruneChunkSize}, nil); err != nil {
+				if isIgnoredError(err) {
+					continue
+				}
+				return fmt.Errorf("error listing resources to prune: %s", err)
+			}
+			for _, wrapper := range wrappers {
+				if compat.GetObjectMeta(wrapper.Value).Labels[corev2.ManagedByLabel] != a.ManagedBy {
+					continue
+				}
+				path := compat.URIPath(wrapper.Value)
+				if defined[path] {
+					continue
+				}
+				name := describe(wrapper)
+				if _, err := a.diff(name, wrapper, nil); err != nil {
+					return err
+				}
+				a.Changes++
+				a.printChange(name, "deleted")
+				if a.DryRun {
+					continue
+				}
+				if err := apiClient.Delete(path); err != nil {
+					return fmt.Errorf("error deleting resource (%s): %s", path, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// describe returns the fully-qualified type, namespace and name of a resource.
+func describe(resource *types.Wrapper) string {
+	wrapped := types.WrapResource(resource.Value)
+	meta := compat.GetObjectMeta(resource.Value)
+	if meta.Namespace == "" {
+		return fmt.Sprintf("%s.%s %s", wrapped.APIVersion, wrapped.Type, meta.Name)
+	}
+	return fmt.Sprintf("%s.%s %s/%s", wrapped.APIVersion, wrapped.Type, meta.Namespace, meta.Name)
+}
+
+// diff prints the unified diff of a live resource and its definition, either
+// of which may be nil. It returns false if they don't differ.
+func (a *Applier) diff(name string, live, defined *types.Wrapper) (bool, error) {
+	from, err := normalizedYAML(live)
+	if err != nil {
+		return false, fmt.Errorf("error comparing resource (%s): %s", name, err)
+	}
+	to, err := normalizedYAML(defined)
+	if err != nil {
+		return false, fmt.Errorf("error comparing resource (%s): %s", name, err)
+	}
+	if from == to {
+		return false, nil
+	}
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(from),
+		B:        splitLines(to),
+		FromFile: name + " (live)",
+		ToFile:   name + " (definition)",
+		Context:  3,
+	})
+	if err != nil {
+		return false, fmt.Errorf("error comparing resource (%s): %s", name, err)
+	}
+	_, _ = fmt.Fprint(a.Out, diff)
+	return true, nil
+}
+
+// splitLines splits a string into lines, keeping their line breaks, so that a
+// missing resource has no lines at all.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return difflib.SplitLines(s)
+}
+
+func (a *Applier) printChange(name, change string) {
+	if a.DryRun {
+		change += " (dry run)"
+	}
+	_, _ = fmt.Fprintf(a.Out, "%s %s\n", name, change)
+}
+
+// normalizedYAML returns the YAML representation of a resource, without the
+// fields that are set by the API, or an empty string if it is nil.
+func normalizedYAML(resource *types.Wrapper) (string, error) {
+	if resource == nil {
+		return "", nil
+	}
+
+	// Work on a copy of the resource
+	b, err := json.Marshal(resource)
+	if err != nil {
+		return "", err
+	}
+	var wrapper types.Wrapper
+	if err := json.Unmarshal(b, &wrapper); err != nil {
+		return "", err
+	}
+
+	meta := compat.GetObjectMeta(wrapper.Value)
+	meta.CreatedBy = ""
+	delete(meta.Annotations, etagAnnotation)
+	if len(meta.Labels) == 0 {
+		meta.Labels = nil
+	}
+	if len(meta.Annotations) == 0 {
+		meta.Annotations = nil
+	}
+	compat.SetObjectMeta(wrapper.Value, meta)
+
+	b, err = yaml.Marshal(wrapper)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)) + "\n", nil
+}
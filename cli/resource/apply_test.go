@@ -0,0 +1,250 @@
+package resource
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+	"github.com/sensu/core/v3/types"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/cli/client"
+	"github.com/sensu/sensu-go/util/compat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClient is a GenericClient that keeps the resources in memory, by path.
+type fakeClient struct {
+	client.GenericClient
+	resources map[string]types.Wrapper
+	puts      []string
+	deletes   []string
+	warnings  []client.DryRunWarning
+
+	// normalize normalizes the resources of the dry runs, as the API does
+	normalize func(corev3.Resource)
+}
+
+func newFakeClient(resources ...corev3.Resource) *fakeClient {
+	c := &fakeClient{resources: map[string]types.Wrapper{}}
+	for _, resource := range resources {
+		c.resources[resource.URIPath()] = types.WrapResource(resource)
+	}
+	return c
+}
+
+func (c *fakeClient) roundTrip(in, out interface{}) error {
+	b, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, out)
+}
+
+func (c *fakeClient) Get(path string, obj interface{}) error {
+	resource, ok := c.resources[path]
+	if !ok {
+		return client.APIError{Code: uint32(actions.NotFound), Message: "not found"}
+	}
+	return c.roundTrip(resource, obj)
+}
+
+func (c *fakeClient) List(path string, objs interface{}, options *client.ListOptions, header *http.Header) error {
+	var list []types.Wrapper
+	for p, resource := range c.resources {
+		if strings.HasPrefix(p, path+"/") {
+			list = append(list, resource)
+		}
+	}
+	return c.roundTrip(list, objs)
+}
+
+func (c *fakeClient) PutResource(w types.Wrapper) error {
+	path := compat.URIPath(w.Value)
+	c.puts = append(c.puts, path)
+	c.resources[path] = w
+	return nil
+}
+
+func (c *fakeClient) DryRunResource(w types.Wrapper) (*client.DryRunResult, error) {
+	if err := w.Value.(corev3.Resource).Validate(); err != nil {
+		return nil, client.APIError{Code: uint32(actions.InvalidArgument), Message: err.Error()}
+	}
+	var normalized types.Wrapper
+	if err := c.roundTrip(w, &normalized); err != nil {
+		return nil, err
+	}
+	if c.normalize != nil {
+		c.normalize(normalized.Value.(corev3.Resource))
+	}
+	result := &client.DryRunResult{Operation: "create", Warnings: c.warnings, Resource: &normalized}
+	if _, ok := c.resources[compat.URIPath(w.Value)]; ok {
+		result.Operation = "update"
+	}
+	return result, nil
+}
+
+func (c *fakeClient) Delete(path string) error {
+	c.deletes = append(c.deletes, path)
+	delete(c.resources, path)
+	return nil
+}
+
+func (c *fakeClient) PostWithResponse(path string, obj interface{}) (*resty.Response, error) {
+	panic("unexpected call")
+}
+
+func managedCheck(name, command string) *corev2.CheckConfig {
+	check := corev2.FixtureCheckConfig(name)
+	check.Command = command
+	check.Labels = map[string]string{corev2.ManagedByLabel: "sensuctl"}
+	check.CreatedBy = "admin"
+	check.Annotations = map[string]string{etagAnnotation: "abc"}
+	return check
+}
+
+func wrap(resources ...corev3.Resource) []*types.Wrapper {
+	var wrappers []*types.Wrapper
+	for _, resource := range resources {
+		w := types.WrapResource(resource)
+		wrappers = append(wrappers, &w)
+	}
+	return wrappers
+}
+
+func TestApplierUnchanged(t *testing.T) {
+	c := newFakeClient(managedCheck("cpu", "check-cpu"))
+	var out bytes.Buffer
+	applier := NewApplier("sensuctl", &out)
+
+	require.NoError(t, applier.Process(c, wrap(managedCheck("cpu", "check-cpu"))))
+	assert.Equal(t, 0, applier.Changes)
+	assert.Empty(t, c.puts)
+	assert.Empty(t, out.String())
+}
+
+func TestApplierDiff(t *testing.T) {
+	c := newFakeClient(managedCheck("cpu", "check-cpu"))
+	var out bytes.Buffer
+	applier := NewApplier("sensuctl", &out)
+
+	check := corev2.FixtureCheckConfig("cpu")
+	check.Command = "check-cpu --warning 80"
+	memory := corev2.FixtureCheckConfig("memory")
+	require.NoError(t, applier.Process(c, wrap(check, memory)))
+	assert.Equal(t, 2, applier.Changes)
+	assert.Equal(t, []string{check.URIPath(), memory.URIPath()}, c.puts)
+
+	output := out.String()
+	assert.Contains(t, output, "--- core/v2.CheckConfig default/cpu (live)")
+	assert.Contains(t, output, "+++ core/v2.CheckConfig default/cpu (definition)")
+	assert.Contains(t, output, "-  command: check-cpu\n")
+	assert.Contains(t, output, "+  command: check-cpu --warning 80\n")
+	assert.Contains(t, output, "core/v2.CheckConfig default/cpu configured\n")
+	assert.Contains(t, output, "core/v2.CheckConfig default/memory created\n")
+	assert.Contains(t, output, "@@ -0,0 +1,")
+
+	// The applied resources are managed by sensuctl
+	applied := c.resources[memory.URIPath()].Value.(*corev2.CheckConfig)
+	assert.Equal(t, "sensuctl", applied.Labels[corev2.ManagedByLabel])
+}
+
+func TestApplierPrune(t *testing.T) {
+	unmanaged := corev2.FixtureCheckConfig("unmanaged")
+	c := newFakeClient(
+		managedCheck("cpu", "check-cpu"),
+		managedCheck("stale", "check-stale"),
+		unmanaged,
+	)
+	var out bytes.Buffer
+	applier := NewApplier("sensuctl", &out)
+	applier.Prune = true
+	applier.Types = []corev3.Resource{&corev2.CheckConfig{}}
+
+	require.NoError(t, applier.Process(c, wrap(managedCheck("cpu", "check-cpu"))))
+	assert.Equal(t, 1, applier.Changes)
+	assert.Equal(t, []string{managedCheck("stale", "").URIPath()}, c.deletes)
+	assert.Contains(t, out.String(), "core/v2.CheckConfig default/stale deleted\n")
+	assert.Contains(t, c.resources, unmanaged.URIPath())
+}
+
+func TestApplierDryRun(t *testing.T) {
+	c := newFakeClient(managedCheck("stale", "check-stale"))
+	var out bytes.Buffer
+	applier := NewApplier("sensuctl", &out)
+	applier.Prune = true
+	applier.DryRun = true
+	applier.Types = []corev3.Resource{&corev2.CheckConfig{}}
+
+	require.NoError(t, applier.Process(c, wrap(corev2.FixtureCheckConfig("cpu"))))
+	assert.Equal(t, 2, applier.Changes)
+	assert.Empty(t, c.puts)
+	assert.Empty(t, c.deletes)
+	assert.Contains(t, out.String(), "core/v2.CheckConfig default/cpu created (dry run)\n")
+	assert.Contains(t, out.String(), "core/v2.CheckConfig default/stale deleted (dry run)\n")
+}
+
+func TestApplierDryRunNormalized(t *testing.T) {
+	// The API sets the default timeout of the checks
+	live := managedCheck("cpu", "check-cpu")
+	live.Timeout = 30
+	c := newFakeClient(live)
+	c.normalize = func(resource corev3.Resource) {
+		if check := resource.(*corev2.CheckConfig); check.Timeout == 0 {
+			check.Timeout = 30
+		}
+	}
+	var out bytes.Buffer
+	applier := NewApplier("sensuctl", &out)
+	applier.DryRun = true
+
+	require.NoError(t, applier.Process(c, wrap(managedCheck("cpu", "check-cpu"))))
+	assert.Equal(t, 0, applier.Changes)
+	assert.Empty(t, out.String())
+
+	// The changes are diffed against the normalized resource
+	require.NoError(t, applier.Process(c, wrap(managedCheck("cpu", "check-cpu --warning 80"))))
+	assert.Equal(t, 1, applier.Changes)
+	assert.Contains(t, out.String(), "+  command: check-cpu --warning 80\n")
+	assert.NotContains(t, out.String(), "timeout")
+	assert.Contains(t, out.String(), "core/v2.CheckConfig default/cpu configured (dry run)\n")
+	assert.Empty(t, c.puts)
+}
+
+func TestApplierDryRunValidation(t *testing.T) {
+	// The API tells apart the resources the user can't read
+	c := newFakeClient(corev2.FixtureCheckConfig("hidden"))
+	c.warnings = []client.DryRunWarning{{Field: "handlers[0]", Message: "Handler slack not found"}}
+	var out bytes.Buffer
+	applier := NewApplier("sensuctl", &out)
+	applier.DryRun = true
+
+	hidden := corev2.FixtureCheckConfig("hidden")
+	hidden.Command = "check-hidden"
+	require.NoError(t, applier.Process(hiddenClient{c}, wrap(hidden)))
+	assert.Equal(t, 1, applier.Changes)
+	assert.Empty(t, c.puts)
+	assert.Contains(t, out.String(), "core/v2.CheckConfig default/hidden configured (dry run)\n")
+	assert.Contains(t, out.String(), "core/v2.CheckConfig default/hidden warning: handlers[0]: Handler slack not found\n")
+
+	// The resources rejected by the API fail the dry run
+	invalid := corev2.FixtureCheckConfig("invalid")
+	invalid.Cron = "not a cron"
+	err := applier.Process(c, wrap(invalid))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "error validating resource #0")
+}
+
+// hiddenClient is a client of a user who can't read the resources.
+type hiddenClient struct {
+	*fakeClient
+}
+
+func (c hiddenClient) Get(path string, obj interface{}) error {
+	return client.APIError{Code: uint32(actions.PermissionDenied), Message: "permission denied"}
+}
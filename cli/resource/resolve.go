@@ -8,8 +8,12 @@ import (
 
 	corev2 "github.com/sensu/core/v2"
 	corev3 "github.com/sensu/core/v3"
-	apitools "github.com/sensu/sensu-api-tools"
 	"github.com/sensu/core/v3/types"
+	apitools "github.com/sensu/sensu-api-tools"
+	authv2 "github.com/sensu/sensu-go/api/authentication/v2"
+	maintenancev1 "github.com/sensu/sensu-go/api/maintenance/v1"
+	pipelinev1 "github.com/sensu/sensu-go/api/pipeline/v1"
+	secretsv1 "github.com/sensu/sensu-go/api/secrets/v1"
 )
 
 var (
@@ -21,6 +25,10 @@ var (
 		&corev2.User{},
 		&corev2.APIKey{},
 		&corev2.TessenConfig{},
+		&authv2.LDAP{Metadata: &corev2.ObjectMeta{}},
+		&authv2.OIDC{Metadata: &corev2.ObjectMeta{}},
+		&secretsv1.Env{Metadata: &corev2.ObjectMeta{}},
+		&secretsv1.VaultProvider{Metadata: &corev2.ObjectMeta{}},
 		&corev2.Asset{},
 		&corev2.CheckConfig{},
 		&corev2.Entity{},
@@ -33,6 +41,11 @@ var (
 		&corev2.Role{},
 		&corev2.RoleBinding{},
 		&corev2.Silenced{},
+		&pipelinev1.HTTPHandler{Metadata: &corev2.ObjectMeta{}},
+		&pipelinev1.EscalationPolicy{Metadata: &corev2.ObjectMeta{}},
+		&pipelinev1.ThrottleFilter{Metadata: &corev2.ObjectMeta{}},
+		&maintenancev1.MaintenanceWindow{Metadata: &corev2.ObjectMeta{}},
+		&secretsv1.Secret{Metadata: &corev2.ObjectMeta{}},
 	}
 
 	// synonyms provides user-friendly resource synonyms like checks, entities
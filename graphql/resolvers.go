@@ -131,6 +131,55 @@ type UnionTypeResolver interface {
 	ResolveType(interface{}, ResolveTypeParams) *Type
 }
 
+//
+// FieldSubscriber is implemented by the resolvers of subscription root types.
+// For each field of the type, it returns a function producing the stream of
+// payloads of the subscription. Each payload received from the channel is
+// then used as the source of the field's resolver.
+//
+// == Example input SDL
+//
+//   type Subscription {
+//     "Receives each new message."
+//     messageAdded: Message!
+//   }
+//
+// == Example implementation
+//
+//   // SubscriptionResolver implements FieldSubscriber
+//   type SubscriptionResolver struct {
+//     bus MessageBus
+//   }
+//
+//   func (r *SubscriptionResolver) SubscribeFields() map[string]graphql.SubscribeFn {
+//     return map[string]graphql.SubscribeFn{
+//       "messageAdded": r.subscribeMessageAdded,
+//     }
+//   }
+//
+//   // MessageAdded resolves the payload received from the channel.
+//   func (r *SubscriptionResolver) MessageAdded(p graphql.ResolveParams) (interface{}, error) {
+//     return p.Source, nil
+//   }
+//
+type FieldSubscriber interface {
+	SubscribeFields() map[string]SubscribeFn
+}
+
+// SubscribeFn returns a channel of subscription payloads. The channel must be
+// closed, or the context of the params cancelled, to end the subscription.
+type SubscribeFn func(p ResolveParams) (chan interface{}, error)
+
+func newSubscribeFn(fn SubscribeFn) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		ch, err := fn(p)
+		if err != nil {
+			return nil, err
+		}
+		return ch, nil
+	}
+}
+
 // DefaultResolver uses reflection to attempt to resolve the result of a given
 // field.
 //
@@ -6,6 +6,7 @@ import (
 
 	"github.com/graphql-go/graphql"
 	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
 	"github.com/graphql-go/graphql/language/parser"
 	"github.com/graphql-go/graphql/language/source"
 )
@@ -83,6 +84,13 @@ func (service *Service) RegisterObject(t ObjectDesc, impl interface{}) {
 		for fieldName, handler := range t.FieldHandlers {
 			fields[fieldName].Resolve = handler(impl)
 		}
+		if subscriber, ok := impl.(FieldSubscriber); ok {
+			for fieldName, fn := range subscriber.SubscribeFields() {
+				if field, ok := fields[fieldName]; ok {
+					field.Subscribe = newSubscribeFn(fn)
+				}
+			}
+		}
 
 		cfg.IsTypeOf = nil
 		if typeResolver, ok := impl.(isTypeOfResolver); ok {
@@ -169,6 +177,44 @@ type QueryParams struct {
 
 // Do executes given query.
 func (service *Service) Do(ctx context.Context, p QueryParams) *Result {
+	params, AST, result := service.prepare(ctx, p)
+	if result != nil {
+		return result
+	}
+
+	// execute query
+	return service.Executor(graphql.ExecuteParams{
+		Schema:  params.Schema,
+		AST:     AST,
+		Args:    p.Variables,
+		Context: params.Context,
+	})
+}
+
+// Subscribe executes given subscription. A result is sent on the returned
+// channel for each payload of the subscription, until ctx is cancelled or the
+// subscription ends, at which point the channel is closed. The channel must be
+// drained by the caller.
+func (service *Service) Subscribe(ctx context.Context, p QueryParams) chan *Result {
+	params, AST, result := service.prepare(ctx, p)
+	if result != nil {
+		ch := make(chan *Result, 1)
+		ch <- result
+		close(ch)
+		return ch
+	}
+	return graphql.ExecuteSubscription(graphql.ExecuteParams{
+		Schema:        params.Schema,
+		AST:           AST,
+		OperationName: p.OperationName,
+		Args:          p.Variables,
+		Context:       params.Context,
+	})
+}
+
+// prepare parses and validates the given query. If the query is invalid, the
+// returned result holds the errors.
+func (service *Service) prepare(ctx context.Context, p QueryParams) (graphql.Params, *ast.Document, *Result) {
 	schema := service.schema
 	params := graphql.Params{
 		Context:        ctx,
@@ -191,14 +237,14 @@ func (service *Service) Do(ctx context.Context, p QueryParams) *Result {
 	AST, err := parser.Parse(parser.ParseParams{Source: source})
 	parseFinishFn(err)
 	if err != nil {
-		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+		return params, nil, &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
 	}
 
 	// run mandatory (un-skippable) validators
 	rules := MandatoryValidators()
 	validationResult := graphql.ValidateDocument(&schema, AST, rules)
 	if !validationResult.IsValid {
-		return &graphql.Result{Errors: validationResult.Errors}
+		return params, nil, &graphql.Result{Errors: validationResult.Errors}
 	}
 
 	// run built-in validators e.g. schema type validation
@@ -207,17 +253,11 @@ func (service *Service) Do(ctx context.Context, p QueryParams) *Result {
 		validationResult := graphql.ValidateDocument(&schema, AST, nil)
 		validationFinishFn(validationResult.Errors)
 		if !validationResult.IsValid {
-			return &graphql.Result{Errors: validationResult.Errors}
+			return params, nil, &graphql.Result{Errors: validationResult.Errors}
 		}
 	}
 
-	// execute query
-	return service.Executor(graphql.ExecuteParams{
-		Schema:  schema,
-		AST:     AST,
-		Args:    p.Variables,
-		Context: params.Context,
-	})
+	return params, AST, nil
 }
 
 type typeRegister struct {
@@ -0,0 +1,9 @@
+package process
+
+// NewGetter returns the Getter of the local processes selected by filter,
+// with their command lines if cmdline is true.
+func NewGetter(filter Filter, cmdline bool) Lister {
+	getter := NewProcGetter(DefaultProcRoot, filter)
+	getter.Cmdline = cmdline
+	return getter
+}
@@ -0,0 +1,27 @@
+package process
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewGetter(t *testing.T) {
+	getter := NewGetter(Filter{}, true)
+	processes, err := getter.Get(context.Background())
+	require.NoError(t, err)
+	assert.NotEmpty(t, processes)
+
+	var found bool
+	for _, info := range getter.Processes() {
+		if int(info.PID) == os.Getpid() {
+			found = true
+			assert.Equal(t, os.Getppid(), int(info.PPID))
+			assert.Equal(t, os.Args, info.Cmdline)
+		}
+	}
+	assert.True(t, found)
+}
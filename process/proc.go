@@ -0,0 +1,288 @@
+package process
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+)
+
+// DefaultProcRoot is the mount point of the proc filesystem.
+const DefaultProcRoot = "/proc"
+
+// clockTicks is the number of clock ticks per second used by the kernel to
+// report the CPU times of the processes (USER_HZ), which is 100 on all the
+// supported architectures.
+const clockTicks = 100
+
+// procStates maps the state codes of /proc/[pid]/stat to their names.
+var procStates = map[string]string{
+	"R": "running",
+	"S": "sleeping",
+	"D": "disk-sleep",
+	"Z": "zombie",
+	"T": "stopped",
+	"t": "tracing-stop",
+	"X": "dead",
+	"I": "idle",
+	"P": "parked",
+	"W": "waking",
+}
+
+// cpuSample is the CPU time used by a process at a given time.
+type cpuSample struct {
+	ticks uint64
+	at    time.Time
+}
+
+// ProcGetter gets the local processes from the proc filesystem of Linux. The
+// entity processes only contain the distinct names of the processes selected
+// by the filter, in order to keep the keepalives small, while their details
+// are available with Processes.
+type ProcGetter struct {
+	// Root is the mount point of the proc filesystem
+	Root string
+
+	// Filter selects the processes
+	Filter Filter
+
+	// Cmdline reports the command lines of the processes
+	Cmdline bool
+
+	now func() time.Time
+
+	mu        sync.Mutex
+	samples   map[int32]cpuSample
+	processes []*Info
+}
+
+// NewProcGetter returns a ProcGetter reading the processes from the proc
+// filesystem mounted at root.
+func NewProcGetter(root string, filter Filter) *ProcGetter {
+	return &ProcGetter{
+		Root:    root,
+		Filter:  filter,
+		now:     time.Now,
+		samples: make(map[int32]cpuSample),
+	}
+}
+
+// Get gets the processes selected by the filter.
+func (g *ProcGetter) Get(ctx context.Context) ([]*corev2.Process, error) {
+	infos, err := g.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	g.mu.Lock()
+	g.processes = infos
+	g.mu.Unlock()
+
+	seen := make(map[string]struct{}, len(infos))
+	processes := make([]*corev2.Process, 0, len(infos))
+	for _, info := range infos {
+		if _, ok := seen[info.Name]; ok {
+			continue
+		}
+		seen[info.Name] = struct{}{}
+		processes = append(processes, &corev2.Process{Name: info.Name})
+	}
+	sort.Slice(processes, func(i, j int) bool {
+		return processes[i].Name < processes[j].Name
+	})
+	return processes, nil
+}
+
+// Processes returns the processes found by the last call to Get, ordered by
+// PID.
+func (g *ProcGetter) Processes() []*Info {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.processes
+}
+
+func (g *ProcGetter) list(ctx context.Context) ([]*Info, error) {
+	bootTime, err := g.bootTime()
+	if err != nil {
+		return nil, err
+	}
+	memTotal, err := g.memTotal()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(g.Root)
+	if err != nil {
+		return nil, err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	now := g.now()
+	samples := make(map[int32]cpuSample, len(g.samples))
+	infos := make([]*Info, 0, len(entries))
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		pid, err := strconv.ParseInt(entry.Name(), 10, 32)
+		if err != nil || !entry.IsDir() {
+			continue
+		}
+		info, ticks, err := g.readProcess(int32(pid), bootTime)
+		if err != nil {
+			// The process exited while the processes were being read
+			continue
+		}
+		if !g.Filter.Match(info.Name) {
+			continue
+		}
+		previous, ok := g.samples[info.PID]
+		if !ok || previous.ticks > ticks {
+			previous = cpuSample{at: info.Created}
+		}
+		if elapsed := now.Sub(previous.at).Seconds(); elapsed > 0 {
+			info.CPUPercent = float64(ticks-previous.ticks) / clockTicks / elapsed * 100
+		}
+		if memTotal > 0 {
+			info.MemoryPercent = float64(info.RSS) / float64(memTotal) * 100
+		}
+		samples[info.PID] = cpuSample{ticks: ticks, at: now}
+		infos = append(infos, info)
+	}
+	g.samples = samples
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].PID < infos[j].PID
+	})
+	return infos, nil
+}
+
+// readProcess reads the details of a process, along with the CPU time it used,
+// in clock ticks.
+func (g *ProcGetter) readProcess(pid int32, bootTime time.Time) (*Info, uint64, error) {
+	dir := filepath.Join(g.Root, strconv.Itoa(int(pid)))
+	stat, err := os.ReadFile(filepath.Join(dir, "stat"))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// The name of the executable is enclosed in parentheses, and may itself
+	// contain parentheses and spaces
+	start := bytes.IndexByte(stat, '(')
+	end := bytes.LastIndexByte(stat, ')')
+	if start < 0 || end < start {
+		return nil, 0, fmt.Errorf("malformed stat for process %d", pid)
+	}
+	// The fields following the name, starting with the state (3rd field)
+	fields := strings.Fields(string(stat[end+1:]))
+	if len(fields) < 22 {
+		return nil, 0, fmt.Errorf("malformed stat for process %d", pid)
+	}
+	ppid, err := strconv.ParseInt(fields[1], 10, 32)
+	if err != nil {
+		return nil, 0, err
+	}
+	utime, err := strconv.ParseUint(fields[11], 10, 64)
+	if err != nil {
+		return nil, 0, err
+	}
+	stime, err := strconv.ParseUint(fields[12], 10, 64)
+	if err != nil {
+		return nil, 0, err
+	}
+	startTime, err := strconv.ParseUint(fields[19], 10, 64)
+	if err != nil {
+		return nil, 0, err
+	}
+	rss, err := strconv.ParseInt(fields[21], 10, 64)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	state, ok := procStates[fields[0]]
+	if !ok {
+		state = fields[0]
+	}
+	info := &Info{
+		PID:     pid,
+		PPID:    int32(ppid),
+		Name:    string(stat[start+1 : end]),
+		State:   state,
+		Created: bootTime.Add(time.Duration(startTime) * time.Second / clockTicks),
+	}
+	if rss > 0 {
+		info.RSS = uint64(rss) * uint64(os.Getpagesize())
+	}
+
+	if g.Cmdline {
+		// The command line is empty for kernel threads and zombies
+		cmdline, err := os.ReadFile(filepath.Join(dir, "cmdline"))
+		if err != nil {
+			return nil, 0, err
+		}
+		cmdline = bytes.TrimRight(cmdline, "\x00")
+		if len(cmdline) > 0 {
+			info.Cmdline = strings.Split(string(cmdline), "\x00")
+		}
+	}
+
+	return info, utime + stime, nil
+}
+
+// bootTime reads the boot time of the system from /proc/stat.
+func (g *ProcGetter) bootTime() (time.Time, error) {
+	value, err := g.readField("stat", "btime")
+	if err != nil {
+		return time.Time{}, err
+	}
+	btime, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid boot time: %s", err)
+	}
+	return time.Unix(btime, 0), nil
+}
+
+// memTotal reads the total memory of the system, in bytes, from
+// /proc/meminfo.
+func (g *ProcGetter) memTotal() (uint64, error) {
+	value, err := g.readField("meminfo", "MemTotal:")
+	if err != nil {
+		return 0, err
+	}
+	kb, err := strconv.ParseUint(strings.TrimSuffix(value, " kB"), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid total memory: %s", err)
+	}
+	return kb * 1024, nil
+}
+
+// readField returns the value of the line of a file of the proc filesystem
+// starting with key.
+func (g *ProcGetter) readField(name, key string) (string, error) {
+	f, err := os.Open(filepath.Join(g.Root, name))
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) > 1 && fields[0] == key {
+			return strings.Join(fields[1:], " "), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", errors.New(key + " not found in " + filepath.Join(g.Root, name))
+}
@@ -0,0 +1,145 @@
+package process
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeProcFile(t *testing.T, root, name, content string) {
+	t.Helper()
+	path := filepath.Join(root, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+// writeProcess writes the stat and cmdline files of a process, which used
+// ticks clock ticks of CPU time and started start clock ticks after boot.
+func writeProcess(t *testing.T, root, pid, ppid, name, state string, ticks, start, rss int, cmdline string) {
+	t.Helper()
+	stat := pid + " (" + name + ") " + state + " " + ppid + " 1 1 0 -1 4194560 100 0 0 0 " +
+		strconv.Itoa(ticks) + " 0 0 0 20 0 1 0 " + strconv.Itoa(start) + " 1000000 " + strconv.Itoa(rss) + " 18446744073709551615\n"
+	writeProcFile(t, root, filepath.Join(pid, "stat"), stat)
+	writeProcFile(t, root, filepath.Join(pid, "cmdline"), cmdline)
+}
+
+func fixtureProcRoot(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	writeProcFile(t, root, "stat", "cpu  1 2 3 4\nbtime 1700000000\nprocesses 42\n")
+	writeProcFile(t, root, "meminfo", "MemTotal:        4000 kB\nMemFree:         1000 kB\n")
+	writeProcess(t, root, "1", "0", "systemd", "S", 200, 100, 1, "/sbin/init\x00splash\x00")
+	writeProcess(t, root, "2", "0", "kthreadd", "I", 0, 100, 0, "")
+	writeProcess(t, root, "100", "1", "nginx", "S", 50, 1000, 2, "nginx: master process\x00")
+	writeProcess(t, root, "101", "100", "nginx", "R", 50, 1000, 2, "nginx: worker process\x00")
+	writeProcess(t, root, "200", "1", "tmux: server (1)", "S", 0, 1000, 0, "tmux\x00")
+	writeProcFile(t, root, "self", "")
+	return root
+}
+
+func TestProcGetter(t *testing.T) {
+	root := fixtureProcRoot(t)
+	getter := NewProcGetter(root, Filter{})
+	boot := time.Unix(1700000000, 0)
+	now := boot.Add(20 * time.Second)
+	getter.now = func() time.Time { return now }
+
+	processes, err := getter.Get(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []*corev2.Process{
+		{Name: "kthreadd"},
+		{Name: "nginx"},
+		{Name: "systemd"},
+		{Name: "tmux: server (1)"},
+	}, processes)
+
+	infos := getter.Processes()
+	require.Len(t, infos, 5)
+	master := infos[2]
+	assert.Equal(t, int32(100), master.PID)
+	assert.Equal(t, int32(1), master.PPID)
+	assert.Equal(t, "nginx", master.Name)
+	assert.Equal(t, "sleeping", master.State)
+	// the command lines are only reported when enabled
+	assert.Nil(t, master.Cmdline)
+	assert.Equal(t, boot.Add(10*time.Second), master.Created)
+	// 0.5s of CPU time in the 10s since the process started
+	assert.InDelta(t, 5, master.CPUPercent, 0.001)
+	assert.Equal(t, uint64(2*os.Getpagesize()), master.RSS)
+	assert.InDelta(t, float64(2*os.Getpagesize())/(4000*1024)*100, master.MemoryPercent, 0.001)
+
+	assert.Equal(t, "idle", infos[1].State)
+	assert.Equal(t, "running", infos[3].State)
+
+	// The CPU usage is computed since the previous refresh
+	now = now.Add(10 * time.Second)
+	writeProcess(t, root, "100", "1", "nginx", "S", 150, 1000, 2, "nginx: master process\x00")
+	_, err = getter.Get(context.Background())
+	require.NoError(t, err)
+	infos = getter.Processes()
+	assert.InDelta(t, 10, infos[2].CPUPercent, 0.001)
+	assert.InDelta(t, 0, infos[3].CPUPercent, 0.001)
+
+	// Exited processes are not reported
+	require.NoError(t, os.RemoveAll(filepath.Join(root, "101")))
+	require.NoError(t, os.Remove(filepath.Join(root, "200", "stat")))
+	_, err = getter.Get(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, getter.Processes(), 3)
+}
+
+func TestProcGetterCmdline(t *testing.T) {
+	root := fixtureProcRoot(t)
+	getter := NewProcGetter(root, Filter{})
+	getter.Cmdline = true
+
+	_, err := getter.Get(context.Background())
+	require.NoError(t, err)
+	infos := getter.Processes()
+	require.Len(t, infos, 5)
+	assert.Equal(t, []string{"/sbin/init", "splash"}, infos[0].Cmdline)
+	assert.Nil(t, infos[1].Cmdline)
+	assert.Equal(t, []string{"nginx: master process"}, infos[2].Cmdline)
+
+	// Exited processes are not reported
+	require.NoError(t, os.Remove(filepath.Join(root, "200", "cmdline")))
+	_, err = getter.Get(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, getter.Processes(), 4)
+}
+
+func TestProcGetterFilter(t *testing.T) {
+	root := fixtureProcRoot(t)
+	getter := NewProcGetter(root, Filter{Allow: []string{"nginx", "k*", "tmux*"}, Deny: []string{"kthread*"}})
+
+	processes, err := getter.Get(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []*corev2.Process{{Name: "nginx"}, {Name: "tmux: server (1)"}}, processes)
+	assert.Len(t, getter.Processes(), 3)
+}
+
+func TestProcGetterErrors(t *testing.T) {
+	getter := NewProcGetter(filepath.Join(t.TempDir(), "missing"), Filter{})
+	_, err := getter.Get(context.Background())
+	assert.Error(t, err)
+
+	root := fixtureProcRoot(t)
+	writeProcFile(t, root, "stat", "cpu  1 2 3 4\n")
+	getter = NewProcGetter(root, Filter{})
+	_, err = getter.Get(context.Background())
+	assert.Error(t, err)
+
+	root = fixtureProcRoot(t)
+	getter = NewProcGetter(root, Filter{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = getter.Get(ctx)
+	assert.Error(t, err)
+}
@@ -2,6 +2,9 @@ package process
 
 import (
 	"context"
+	"fmt"
+	"path"
+	"time"
 
 	corev2 "github.com/sensu/core/v2"
 )
@@ -11,6 +14,15 @@ type Getter interface {
 	Get(context.Context) ([]*corev2.Process, error)
 }
 
+// A Lister is a Getter that also provides the details of the processes found
+// by the last call to Get.
+type Lister interface {
+	Getter
+
+	// Processes returns the processes found by the last call to Get.
+	Processes() []*Info
+}
+
 // A NoopProcessGetter is responsible for refreshing process info of an agent.
 type NoopProcessGetter struct{}
 
@@ -18,3 +30,78 @@ type NoopProcessGetter struct{}
 func (NoopProcessGetter) Get(ctx context.Context) ([]*corev2.Process, error) {
 	return ([]*corev2.Process)(nil), nil
 }
+
+// Processes returns no processes.
+func (NoopProcessGetter) Processes() []*Info {
+	return nil
+}
+
+// Info contains the details of a local process.
+type Info struct {
+	// PID is the process ID
+	PID int32 `json:"pid"`
+
+	// PPID is the process ID of the parent process
+	PPID int32 `json:"ppid"`
+
+	// Name is the name of the executable of the process
+	Name string `json:"name"`
+
+	// Cmdline is the command line of the process. It is only reported when
+	// enabled, since command lines often contain credentials.
+	Cmdline []string `json:"cmdline,omitempty"`
+
+	// State is the state of the process, e.g. running or sleeping
+	State string `json:"state"`
+
+	// CPUPercent is the percentage of a CPU used by the process since the
+	// previous refresh, or since it started on the first refresh
+	CPUPercent float64 `json:"cpu_percent"`
+
+	// RSS is the resident set size of the process, in bytes
+	RSS uint64 `json:"rss"`
+
+	// MemoryPercent is the percentage of the total memory of the system
+	// resident in memory for the process
+	MemoryPercent float64 `json:"memory_percent"`
+
+	// Created is the time at which the process started
+	Created time.Time `json:"created"`
+}
+
+// Filter selects the processes by name. Both lists contain glob patterns, as
+// understood by path.Match. A process is selected if its name matches one of
+// the allowed patterns, or if there are none, and doesn't match any of the
+// denied patterns.
+type Filter struct {
+	Allow []string
+	Deny  []string
+}
+
+// Match returns true if the process name is selected by the filter.
+func (f Filter) Match(name string) bool {
+	for _, pattern := range f.Deny {
+		if ok, _ := path.Match(pattern, name); ok {
+			return false
+		}
+	}
+	if len(f.Allow) == 0 {
+		return true
+	}
+	for _, pattern := range f.Allow {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate returns an error if one of the patterns of the filter is malformed.
+func (f Filter) Validate() error {
+	for _, pattern := range append(append([]string{}, f.Allow...), f.Deny...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid process pattern %q: %s", pattern, err)
+		}
+	}
+	return nil
+}
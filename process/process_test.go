@@ -0,0 +1,30 @@
+package process
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterMatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Filter
+		want   bool
+	}{
+		{name: "nginx", filter: Filter{}, want: true},
+		{name: "nginx", filter: Filter{Allow: []string{"ngin?"}}, want: true},
+		{name: "nginx", filter: Filter{Allow: []string{"postgres"}}, want: false},
+		{name: "nginx", filter: Filter{Deny: []string{"*"}}, want: false},
+		{name: "nginx", filter: Filter{Allow: []string{"nginx"}, Deny: []string{"n*"}}, want: false},
+		{name: "kworker/0:1", filter: Filter{Deny: []string{"kworker/*"}}, want: false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tt.filter.Match(tt.name), "%s %+v", tt.name, tt.filter)
+	}
+}
+
+func TestFilterValidate(t *testing.T) {
+	assert.NoError(t, Filter{Allow: []string{"nginx", "post*"}, Deny: []string{"k?"}}.Validate())
+	assert.Error(t, Filter{Deny: []string{"[a"}}.Validate())
+}
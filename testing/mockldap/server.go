@@ -0,0 +1,490 @@
+// Package mockldap provides a local LDAP directory server for testing the LDAP
+// authentication provider. The server holds its entries in memory, and
+// supports simple binds, searches, StartTLS and LDAPS. Messages are decoded
+// with the BER package, and the protocol constants, of the go-ldap client.
+package mockldap
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+)
+
+// InChainRule is the matching rule used by Active Directory to match the
+// members of nested groups.
+const InChainRule = "1.2.840.113556.1.4.1941"
+
+// startTLSOID is the name of the StartTLS extended operation.
+const startTLSOID = "1.3.6.1.4.1.1466.20037"
+
+type entry struct {
+	dn         string
+	password   string
+	attributes map[string][]string
+}
+
+// Server is a mock LDAP server.
+type Server struct {
+	// Addr is the address the server listens on.
+	Addr string
+
+	// CACertificate is the PEM encoded certificate used by the server for TLS,
+	// which clients must trust.
+	CACertificate []byte
+
+	listener  net.Listener
+	tlsConfig *tls.Config
+	wg        sync.WaitGroup
+
+	mu      sync.Mutex
+	entries map[string]*entry
+	conns   map[net.Conn]struct{}
+	binds   int
+}
+
+// NewServer starts a server accepting plain connections, which can be
+// upgraded with StartTLS. The server must be closed once done.
+func NewServer() (*Server, error) {
+	return newServer(false)
+}
+
+// NewTLSServer starts a server accepting TLS connections (LDAPS). The server
+// must be closed once done.
+func NewTLSServer() (*Server, error) {
+	return newServer(true)
+}
+
+func newServer(ldaps bool) (*Server, error) {
+	cert, certPEM, err := certificate()
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{
+		CACertificate: certPEM,
+		tlsConfig:     &tls.Config{Certificates: []tls.Certificate{cert}},
+		entries:       make(map[string]*entry),
+		conns:         make(map[net.Conn]struct{}),
+	}
+	if ldaps {
+		s.listener, err = tls.Listen("tcp", "127.0.0.1:0", s.tlsConfig)
+	} else {
+		s.listener, err = net.Listen("tcp", "127.0.0.1:0")
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.Addr = s.listener.Addr().String()
+
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Host returns the host the server listens on.
+func (s *Server) Host() string {
+	host, _, _ := net.SplitHostPort(s.Addr)
+	return host
+}
+
+// Port returns the port the server listens on.
+func (s *Server) Port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+// AddEntry adds an entry to the directory, or replaces it. Entries with a
+// password can be used to bind.
+func (s *Server) AddEntry(dn, password string, attributes map[string][]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[strings.ToLower(dn)] = &entry{dn: dn, password: password, attributes: attributes}
+}
+
+// RemoveEntry removes an entry from the directory.
+func (s *Server) RemoveEntry(dn string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, strings.ToLower(dn))
+}
+
+// Binds returns the number of successful binds.
+func (s *Server) Binds() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.binds
+}
+
+// Close stops the server and closes its connections.
+func (s *Server) Close() {
+	_ = s.listener.Close()
+	s.mu.Lock()
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handle(conn)
+		}()
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		_ = conn.Close()
+	}()
+
+	bound := false
+	for {
+		message, err := ber.ReadPacket(conn)
+		if err != nil || len(message.Children) < 2 {
+			return
+		}
+		id, ok := message.Children[0].Value.(int64)
+		if !ok {
+			return
+		}
+		op := message.Children[1]
+		respond := func(ops ...*ber.Packet) bool {
+			for _, op := range ops {
+				response := ber.NewSequence("LDAP Response")
+				response.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, "Message ID"))
+				response.AppendChild(op)
+				if _, err := conn.Write(response.Bytes()); err != nil {
+					return false
+				}
+			}
+			return true
+		}
+
+		switch op.Tag {
+		case ldap.ApplicationBindRequest:
+			code := s.bind(op)
+			bound = code == ldap.LDAPResultSuccess && len(op.Children) == 3 && op.Children[2].Data.Len() > 0
+			if !respond(result(ldap.ApplicationBindResponse, code, "")) {
+				return
+			}
+		case ldap.ApplicationSearchRequest:
+			if !bound {
+				if !respond(result(ldap.ApplicationSearchResultDone, ldap.LDAPResultOperationsError, "bind required")) {
+					return
+				}
+				continue
+			}
+			if !respond(s.search(op)...) {
+				return
+			}
+		case ldap.ApplicationExtendedRequest:
+			if len(op.Children) == 0 || op.Children[0].Data.String() != startTLSOID {
+				if !respond(result(ldap.ApplicationExtendedResponse, ldap.LDAPResultProtocolError, "unsupported operation")) {
+					return
+				}
+				continue
+			}
+			if !respond(result(ldap.ApplicationExtendedResponse, ldap.LDAPResultSuccess, "")) {
+				return
+			}
+			tlsConn := tls.Server(conn, s.tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			s.mu.Lock()
+			delete(s.conns, conn)
+			s.conns[tlsConn] = struct{}{}
+			s.mu.Unlock()
+			conn = tlsConn
+		case ldap.ApplicationUnbindRequest:
+			return
+		default:
+			return
+		}
+	}
+}
+
+func (s *Server) bind(op *ber.Packet) uint16 {
+	if len(op.Children) != 3 {
+		return ldap.LDAPResultProtocolError
+	}
+	dn, password := op.Children[1].Data.String(), op.Children[2].Data.String()
+	if dn == "" && password == "" {
+		// Anonymous bind
+		return ldap.LDAPResultSuccess
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[strings.ToLower(dn)]
+	if !ok || password == "" || e.password != password {
+		return ldap.LDAPResultInvalidCredentials
+	}
+	s.binds++
+	return ldap.LDAPResultSuccess
+}
+
+func (s *Server) search(op *ber.Packet) []*ber.Packet {
+	if len(op.Children) != 8 {
+		return []*ber.Packet{result(ldap.ApplicationSearchResultDone, ldap.LDAPResultProtocolError, "malformed search")}
+	}
+	base := strings.ToLower(op.Children[0].Data.String())
+	scope, _ := op.Children[1].Value.(int64)
+	sizeLimit, _ := op.Children[3].Value.(int64)
+	filter := op.Children[6]
+	var attributes []string
+	for _, attribute := range op.Children[7].Children {
+		attributes = append(attributes, attribute.Data.String())
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var responses []*ber.Packet
+	for key, e := range s.entries {
+		if !inScope(key, base, scope) || !s.match(e, filter) {
+			continue
+		}
+		if sizeLimit > 0 && int64(len(responses)) == sizeLimit {
+			return append(responses, result(ldap.ApplicationSearchResultDone, ldap.LDAPResultSizeLimitExceeded, ""))
+		}
+		responses = append(responses, searchEntry(e, attributes))
+	}
+	return append(responses, result(ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess, ""))
+}
+
+func inScope(dn, base string, scope int64) bool {
+	switch scope {
+	case ldap.ScopeBaseObject:
+		return dn == base
+	case ldap.ScopeSingleLevel:
+		i := strings.IndexByte(dn, ',')
+		return i >= 0 && dn[i+1:] == base
+	default:
+		return base == "" || dn == base || strings.HasSuffix(dn, ","+base)
+	}
+}
+
+// match evaluates a filter, as encoded by ldap.CompileFilter, against an
+// entry. The mutex must be held.
+func (s *Server) match(e *entry, f *ber.Packet) bool {
+	switch f.Tag {
+	case ldap.FilterAnd:
+		for _, child := range f.Children {
+			if !s.match(e, child) {
+				return false
+			}
+		}
+		return true
+	case ldap.FilterOr:
+		for _, child := range f.Children {
+			if s.match(e, child) {
+				return true
+			}
+		}
+		return false
+	case ldap.FilterNot:
+		return len(f.Children) == 1 && !s.match(e, f.Children[0])
+	case ldap.FilterPresent:
+		return len(values(e, f.Data.String())) > 0
+	case ldap.FilterSubstrings:
+		if len(f.Children) != 2 {
+			return false
+		}
+		for _, value := range values(e, f.Children[0].Data.String()) {
+			if matchSubstrings(strings.ToLower(value), f.Children[1]) {
+				return true
+			}
+		}
+		return false
+	case ldap.FilterExtensibleMatch:
+		var rule, attribute, value string
+		for _, child := range f.Children {
+			switch child.Tag {
+			case ldap.MatchingRuleAssertionMatchingRule:
+				rule = child.Data.String()
+			case ldap.MatchingRuleAssertionType:
+				attribute = child.Data.String()
+			case ldap.MatchingRuleAssertionMatchValue:
+				value = child.Data.String()
+			}
+		}
+		if rule == InChainRule {
+			return s.inChain(e, attribute, value, map[string]bool{})
+		}
+		return matchValues(e, ldap.FilterEqualityMatch, attribute, value)
+	}
+
+	if len(f.Children) != 2 {
+		return false
+	}
+	return matchValues(e, f.Tag, f.Children[0].Data.String(), f.Children[1].Data.String())
+}
+
+// matchValues compares the values of an attribute of an entry with a value.
+func matchValues(e *entry, op ber.Tag, attribute, value string) bool {
+	value = strings.ToLower(value)
+	for _, v := range values(e, attribute) {
+		v = strings.ToLower(v)
+		switch op {
+		case ldap.FilterEqualityMatch, ldap.FilterApproxMatch:
+			if v == value {
+				return true
+			}
+		case ldap.FilterGreaterOrEqual:
+			if v >= value {
+				return true
+			}
+		case ldap.FilterLessOrEqual:
+			if v <= value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// inChain returns true if an entry, or any of the entries it references
+// recursively with the attribute, has the value. The mutex must be held.
+func (s *Server) inChain(e *entry, attribute, value string, visited map[string]bool) bool {
+	key := strings.ToLower(e.dn)
+	if visited[key] {
+		return false
+	}
+	visited[key] = true
+	for _, v := range values(e, attribute) {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+		if nested, ok := s.entries[strings.ToLower(v)]; ok && s.inChain(nested, attribute, value, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchSubstrings(value string, substrings *ber.Packet) bool {
+	for _, substring := range substrings.Children {
+		component := strings.ToLower(substring.Data.String())
+		switch substring.Tag {
+		case ldap.FilterSubstringsInitial:
+			if !strings.HasPrefix(value, component) {
+				return false
+			}
+			value = value[len(component):]
+		case ldap.FilterSubstringsAny:
+			i := strings.Index(value, component)
+			if i < 0 {
+				return false
+			}
+			value = value[i+len(component):]
+		case ldap.FilterSubstringsFinal:
+			if !strings.HasSuffix(value, component) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func values(e *entry, attribute string) []string {
+	for name, values := range e.attributes {
+		if strings.EqualFold(name, attribute) {
+			return values
+		}
+	}
+	return nil
+}
+
+func searchEntry(e *entry, attributes []string) *ber.Packet {
+	entry := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+	entry.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, e.dn, "DN"))
+	list := ber.NewSequence("Attributes")
+	for name, vals := range e.attributes {
+		if !selected(name, attributes) {
+			continue
+		}
+		attribute := ber.NewSequence("Attribute")
+		attribute.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "Type"))
+		set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+		for _, value := range vals {
+			set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, "Value"))
+		}
+		attribute.AppendChild(set)
+		list.AppendChild(attribute)
+	}
+	entry.AppendChild(list)
+	return entry
+}
+
+func selected(name string, attributes []string) bool {
+	if len(attributes) == 0 {
+		return true
+	}
+	for _, attribute := range attributes {
+		if attribute == "*" || strings.EqualFold(attribute, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func result(tag ber.Tag, code uint16, message string) *ber.Packet {
+	result := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Result")
+	result.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), "Result Code"))
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, message, "Diagnostic Message"))
+	return result
+}
+
+// certificate generates the self-signed certificate of the server.
+func certificate() (tls.Certificate, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "mockldap"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+	cert := tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+	return cert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), nil
+}
@@ -0,0 +1,235 @@
+// Package mockoidc provides a local OpenID Connect issuer for testing the OIDC
+// authentication provider. The issuer authenticates any authorization request
+// without user interaction, and issues ID tokens containing its configured
+// claims.
+package mockoidc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt/v4"
+)
+
+const keyID = "mockoidc"
+
+type authorization struct {
+	nonce       string
+	redirectURI string
+	offline     bool
+}
+
+// Issuer is a mock OpenID Connect issuer.
+type Issuer struct {
+	*httptest.Server
+
+	// ClientID and ClientSecret are the credentials of the only client of the
+	// issuer.
+	ClientID     string
+	ClientSecret string
+
+	key *rsa.PrivateKey
+
+	mu            sync.Mutex
+	claims        map[string]interface{}
+	codes         map[string]authorization
+	refreshTokens map[string]bool
+}
+
+// NewIssuer starts a new issuer. The issuer must be closed once done.
+func NewIssuer(clientID, clientSecret string) (*Issuer, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	i := &Issuer{
+		ClientID:      clientID,
+		ClientSecret:  clientSecret,
+		key:           key,
+		claims:        map[string]interface{}{"sub": "user"},
+		codes:         make(map[string]authorization),
+		refreshTokens: make(map[string]bool),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", i.discovery)
+	mux.HandleFunc("/keys", i.keys)
+	mux.HandleFunc("/authorize", i.authorize)
+	mux.HandleFunc("/token", i.token)
+	i.Server = httptest.NewServer(mux)
+	return i, nil
+}
+
+// SetClaims sets the claims of the ID tokens issued from now on. The sub
+// claim defaults to "user".
+func (i *Issuer) SetClaims(claims map[string]interface{}) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.claims = map[string]interface{}{"sub": "user"}
+	for k, v := range claims {
+		i.claims[k] = v
+	}
+}
+
+// RevokeRefreshTokens revokes all the refresh tokens issued so far.
+func (i *Issuer) RevokeRefreshTokens() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.refreshTokens = make(map[string]bool)
+}
+
+func (i *Issuer) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                i.URL,
+		"authorization_endpoint":                i.URL + "/authorize",
+		"token_endpoint":                        i.URL + "/token",
+		"jwks_uri":                              i.URL + "/keys",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func (i *Issuer) keys(w http.ResponseWriter, r *http.Request) {
+	encode := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": keyID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   encode(i.key.N.Bytes()),
+			"e":   encode(big.NewInt(int64(i.key.E)).Bytes()),
+		}},
+	})
+}
+
+// authorize authenticates the user without interaction and redirects them
+// back to the client with an authorization code.
+func (i *Issuer) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("client_id") != i.ClientID || query.Get("response_type") != "code" {
+		http.Error(w, "invalid authorization request", http.StatusBadRequest)
+		return
+	}
+	redirectURI, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil || !redirectURI.IsAbs() {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+	scopes := strings.Fields(query.Get("scope"))
+	auth := authorization{nonce: query.Get("nonce"), redirectURI: redirectURI.String()}
+	for _, scope := range scopes {
+		if scope == "offline_access" {
+			auth.offline = true
+		}
+	}
+
+	code := randomString()
+	i.mu.Lock()
+	i.codes[code] = auth
+	i.mu.Unlock()
+
+	values := redirectURI.Query()
+	values.Set("code", code)
+	values.Set("state", query.Get("state"))
+	redirectURI.RawQuery = values.Encode()
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+func (i *Issuer) token(w http.ResponseWriter, r *http.Request) {
+	clientID, clientSecret, _ := r.BasicAuth()
+	clientID, _ = url.QueryUnescape(clientID)
+	clientSecret, _ = url.QueryUnescape(clientSecret)
+	if clientID != i.ClientID || clientSecret != i.ClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	var nonce string
+	var offline bool
+	switch r.PostForm.Get("grant_type") {
+	case "authorization_code":
+		code := r.PostForm.Get("code")
+		auth, ok := i.codes[code]
+		delete(i.codes, code)
+		if !ok || auth.redirectURI != r.PostForm.Get("redirect_uri") {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+			return
+		}
+		nonce = auth.nonce
+		offline = auth.offline
+	case "refresh_token":
+		token := r.PostForm.Get("refresh_token")
+		if !i.refreshTokens[token] {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+			return
+		}
+		delete(i.refreshTokens, token)
+		offline = true
+	default:
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+		return
+	}
+
+	claims := jwt.MapClaims{
+		"iss": i.URL,
+		"aud": i.ClientID,
+		"iat": time.Now().Unix(),
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+	for k, v := range i.claims {
+		claims[k] = v
+	}
+	if nonce != "" {
+		claims["nonce"] = nonce
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = keyID
+	idToken, err := token.SignedString(i.key)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	response := map[string]interface{}{
+		"access_token": randomString(),
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     idToken,
+	}
+	if offline {
+		refreshToken := randomString()
+		i.refreshTokens[refreshToken] = true
+		response["refresh_token"] = refreshToken
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func randomString() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
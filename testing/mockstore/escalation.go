@@ -0,0 +1,36 @@
+package mockstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/stretchr/testify/mock"
+)
+
+type EscalationStore struct {
+	mock.Mock
+}
+
+func (e *EscalationStore) OpenIncident(ctx context.Context, incident *store.EscalationIncident) (bool, error) {
+	args := e.Called(ctx, incident)
+	return args.Bool(0), args.Error(1)
+}
+
+func (e *EscalationStore) CloseIncidents(ctx context.Context, namespace, entity, check string) error {
+	return e.Called(ctx, namespace, entity, check).Error(0)
+}
+
+func (e *EscalationStore) ClaimDueIncidents(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*store.EscalationIncident, error) {
+	args := e.Called(ctx, now, limit, lease)
+	return args.Get(0).([]*store.EscalationIncident), args.Error(1)
+}
+
+func (e *EscalationStore) AdvanceIncident(ctx context.Context, incident *store.EscalationIncident, step, repeats uint32, dueAt time.Time) (bool, error) {
+	args := e.Called(ctx, incident, step, repeats, dueAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (e *EscalationStore) CloseIncident(ctx context.Context, incident *store.EscalationIncident) error {
+	return e.Called(ctx, incident).Error(0)
+}
@@ -2,6 +2,7 @@ package mockstore
 
 import (
 	"context"
+	"time"
 
 	corev2 "github.com/sensu/core/v2"
 	"github.com/sensu/sensu-go/backend/store"
@@ -45,3 +46,15 @@ func (s *MockStore) CountEvents(ctx context.Context, pred *store.SelectionPredic
 func (s *MockStore) EventStoreSupportsFiltering(ctx context.Context) bool {
 	return s.Called(ctx).Get(0).(bool)
 }
+
+// GetEventHistory ...
+func (s *MockStore) GetEventHistory(ctx context.Context, entityName, checkID string, start, end time.Time) ([]*store.EventHistoryEntry, error) {
+	args := s.Called(ctx, entityName, checkID, start, end)
+	return args.Get(0).([]*store.EventHistoryEntry), args.Error(1)
+}
+
+// PruneEventHistory ...
+func (s *MockStore) PruneEventHistory(ctx context.Context, before time.Time) (int64, error) {
+	args := s.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
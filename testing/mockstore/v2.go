@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"time"
 
 	corev2 "github.com/sensu/core/v2"
 	corev3 "github.com/sensu/core/v3"
@@ -45,6 +46,10 @@ func (v *V2MockStore) GetSilencesStore() storev2.SilencesStore {
 	return v.Called().Get(0).(storev2.SilencesStore)
 }
 
+func (v *V2MockStore) GetConfigHistoryStore() storev2.ConfigHistoryStore {
+	return v.Called().Get(0).(storev2.ConfigHistoryStore)
+}
+
 type ConfigStore struct {
 	mock.Mock
 }
@@ -338,3 +343,24 @@ func (s *SilencesStore) GetSilencesByName(ctx context.Context, namespace string,
 func (s *SilencesStore) DeleteSilences(ctx context.Context, namespace string, names []string) error {
 	return s.Called(ctx, namespace, names).Error(0)
 }
+
+type ConfigHistoryStore struct {
+	mock.Mock
+}
+
+func (h *ConfigHistoryStore) ListRevisions(ctx context.Context, req storev2.ResourceRequest) ([]*storev2.Revision, error) {
+	args := h.Called(ctx, req)
+	revisions, _ := args.Get(0).([]*storev2.Revision)
+	return revisions, args.Error(1)
+}
+
+func (h *ConfigHistoryStore) GetRevision(ctx context.Context, req storev2.ResourceRequest, revision int64) (*storev2.Revision, error) {
+	args := h.Called(ctx, req, revision)
+	result, _ := args.Get(0).(*storev2.Revision)
+	return result, args.Error(1)
+}
+
+func (h *ConfigHistoryStore) PruneRevisions(ctx context.Context, maxRevisions int, before time.Time) (int64, error) {
+	args := h.Called(ctx, maxRevisions, before)
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -0,0 +1,244 @@
+// Package mockvault provides a local stand-in for HashiCorp Vault, for testing
+// the Vault secrets provider. The server holds its secrets in memory, and
+// supports token and AppRole authentication and the KV secrets engines.
+package mockvault
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+type appRole struct {
+	mount    string
+	roleID   string
+	secretID string
+	ttl      time.Duration
+}
+
+// Server is a mock Vault server.
+type Server struct {
+	*httptest.Server
+
+	// Token is the root token of the server, which never expires.
+	Token string
+
+	// CACertificate is the PEM encoded certificate of a TLS server, which
+	// clients must trust.
+	CACertificate []byte
+
+	mu            sync.Mutex
+	mounts        map[string]string
+	secrets       map[string]map[string]interface{}
+	leaseDuration time.Duration
+	appRole       *appRole
+	tokens        map[string]time.Time
+	reads         int
+	logins        int
+}
+
+// NewServer starts a plain HTTP server accepting the given root token. The
+// server must be closed once done.
+func NewServer(token string) *Server {
+	s := newServer(token)
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// NewTLSServer starts an HTTPS server accepting the given root token. The
+// server must be closed once done.
+func NewTLSServer(token string) *Server {
+	s := newServer(token)
+	s.Server = httptest.NewTLSServer(s)
+	s.CACertificate = pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: s.Certificate().Raw,
+	})
+	return s
+}
+
+func newServer(token string) *Server {
+	return &Server{
+		Token:   token,
+		mounts:  make(map[string]string),
+		secrets: make(map[string]map[string]interface{}),
+		tokens:  make(map[string]time.Time),
+	}
+}
+
+// Mount mounts a KV secrets engine of the given version, v1 or v2, at path.
+func (s *Server) Mount(path, version string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mounts[strings.Trim(path, "/")] = version
+}
+
+// Put writes a secret at path, which includes the mount of its secrets
+// engine.
+func (s *Server) Put(path string, data map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.secrets[strings.Trim(path, "/")] = data
+}
+
+// SetLeaseDuration sets the lease duration returned with the secrets of KV v1
+// engines.
+func (s *Server) SetLeaseDuration(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.leaseDuration = d
+}
+
+// EnableAppRole enables the AppRole auth method at mount, with a single role.
+// The tokens issued by the method expire after ttl.
+func (s *Server) EnableAppRole(mount, roleID, secretID string, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.appRole = &appRole{mount: mount, roleID: roleID, secretID: secretID, ttl: ttl}
+}
+
+// RevokeTokens revokes all the tokens issued so far by the AppRole auth
+// method.
+func (s *Server) RevokeTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens = make(map[string]time.Time)
+}
+
+// Reads returns the number of secrets read so far.
+func (s *Server) Reads() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.reads
+}
+
+// Logins returns the number of successful AppRole logins so far.
+func (s *Server) Logins() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.logins
+}
+
+// ServeHTTP implements the parts of the Vault HTTP API used by the provider.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/v1/"), "/")
+	if s.appRole != nil && path == "auth/"+s.appRole.mount+"/login" {
+		if r.Method != http.MethodPost && r.Method != http.MethodPut {
+			writeErrors(w, http.StatusMethodNotAllowed, "unsupported operation")
+			return
+		}
+		s.login(w, r)
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeErrors(w, http.StatusMethodNotAllowed, "unsupported operation")
+		return
+	}
+	if !s.authorized(r.Header.Get("X-Vault-Token")) {
+		writeErrors(w, http.StatusForbidden, "permission denied")
+		return
+	}
+	s.read(w, path)
+}
+
+func (s *Server) login(w http.ResponseWriter, r *http.Request) {
+	var credentials struct {
+		RoleID   string `json:"role_id"`
+		SecretID string `json:"secret_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&credentials); err != nil {
+		writeErrors(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if credentials.RoleID != s.appRole.roleID || credentials.SecretID != s.appRole.secretID {
+		writeErrors(w, http.StatusBadRequest, "invalid role or secret ID")
+		return
+	}
+	token := randomToken()
+	s.tokens[token] = time.Now().Add(s.appRole.ttl)
+	s.logins++
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"auth": map[string]interface{}{
+			"client_token":   token,
+			"lease_duration": int(s.appRole.ttl.Seconds()),
+			"renewable":      true,
+		},
+	})
+}
+
+func (s *Server) authorized(token string) bool {
+	if token == "" {
+		return false
+	}
+	if token == s.Token {
+		return true
+	}
+	expiry, ok := s.tokens[token]
+	return ok && time.Now().Before(expiry)
+}
+
+func (s *Server) read(w http.ResponseWriter, path string) {
+	mount, rest, _ := strings.Cut(path, "/")
+	version, ok := s.mounts[mount]
+	if !ok {
+		writeErrors(w, http.StatusNotFound, "no handler for route")
+		return
+	}
+	if version == "v2" {
+		if !strings.HasPrefix(rest, "data/") {
+			writeErrors(w, http.StatusNotFound, "no handler for route")
+			return
+		}
+		rest = strings.TrimPrefix(rest, "data/")
+	}
+	data, ok := s.secrets[mount+"/"+rest]
+	if !ok {
+		writeErrors(w, http.StatusNotFound)
+		return
+	}
+	s.reads++
+	if version == "v2" {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"lease_duration": 0,
+			"data": map[string]interface{}{
+				"data":     data,
+				"metadata": map[string]interface{}{"version": 1},
+			},
+		})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"lease_duration": int(s.leaseDuration.Seconds()),
+		"data":           data,
+	})
+}
+
+func randomToken() string {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return "s." + hex.EncodeToString(b)
+}
+
+func writeErrors(w http.ResponseWriter, status int, errors ...string) {
+	if errors == nil {
+		errors = []string{}
+	}
+	writeJSON(w, status, map[string]interface{}{"errors": errors})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
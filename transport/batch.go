@@ -0,0 +1,76 @@
+package transport
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// MessageTypeBatch is the message type of the batches of messages, sent to
+// peers with the batch capability.
+const MessageTypeBatch = "batch"
+
+var errInvalidBatch = errors.New("invalid batch message")
+
+// NewBatchMessage returns a message that packs the given messages. The send
+// callbacks of the messages are executed after the batch is sent.
+func NewBatchMessage(msgs []*Message) *Message {
+	return &Message{
+		Type:    MessageTypeBatch,
+		Payload: EncodeBatch(msgs),
+		SendCallback: func(err error) {
+			for _, msg := range msgs {
+				if msg.SendCallback != nil {
+					msg.SendCallback(err)
+				}
+			}
+		},
+	}
+}
+
+// EncodeBatch encodes messages into the payload of a batch message. Each
+// message is encoded as the length of its type as a varint, its type, the
+// length of its payload as a varint and its payload.
+func EncodeBatch(msgs []*Message) []byte {
+	size := 0
+	for _, msg := range msgs {
+		size += 2*binary.MaxVarintLen64 + len(msg.Type) + len(msg.Payload)
+	}
+	buf := make([]byte, 0, size)
+	var n [binary.MaxVarintLen64]byte
+	for _, msg := range msgs {
+		buf = append(buf, n[:binary.PutUvarint(n[:], uint64(len(msg.Type)))]...)
+		buf = append(buf, msg.Type...)
+		buf = append(buf, n[:binary.PutUvarint(n[:], uint64(len(msg.Payload)))]...)
+		buf = append(buf, msg.Payload...)
+	}
+	return buf
+}
+
+// DecodeBatch decodes the messages of the payload of a batch message.
+func DecodeBatch(payload []byte) ([]*Message, error) {
+	var msgs []*Message
+	for len(payload) > 0 {
+		msgType, rest, err := decodeBatchField(payload)
+		if err != nil {
+			return nil, err
+		}
+		msgPayload, rest, err := decodeBatchField(rest)
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, NewMessage(string(msgType), msgPayload))
+		payload = rest
+	}
+	return msgs, nil
+}
+
+// decodeBatchField decodes a field prefixed with its length, and returns it
+// along with the rest of the payload.
+func decodeBatchField(payload []byte) ([]byte, []byte, error) {
+	length, n := binary.Uvarint(payload)
+	if n <= 0 || length > uint64(len(payload)-n) {
+		return nil, nil, errInvalidBatch
+	}
+	end := n + int(length)
+	return payload[n:end:end], payload[end:], nil
+}
@@ -0,0 +1,51 @@
+package transport
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeDecodeBatch(t *testing.T) {
+	msgs := []*Message{
+		NewMessage(MessageTypeEvent, []byte("event 1")),
+		NewMessage(MessageTypeKeepalive, nil),
+		NewMessage(MessageTypeEvent, make([]byte, 1000)),
+	}
+
+	decoded, err := DecodeBatch(EncodeBatch(msgs))
+	require.NoError(t, err)
+	require.Len(t, decoded, len(msgs))
+	for i := range msgs {
+		assert.Equal(t, msgs[i].Type, decoded[i].Type)
+		assert.Equal(t, len(msgs[i].Payload), len(decoded[i].Payload))
+		assert.Equal(t, string(msgs[i].Payload), string(decoded[i].Payload))
+	}
+}
+
+func TestDecodeInvalidBatch(t *testing.T) {
+	payload := EncodeBatch([]*Message{NewMessage(MessageTypeEvent, []byte("event"))})
+	_, err := DecodeBatch(payload[:len(payload)-1])
+	assert.Error(t, err)
+
+	_, err = DecodeBatch([]byte{0xff})
+	assert.Error(t, err)
+}
+
+func TestBatchMessageSendCallback(t *testing.T) {
+	var calls int
+	callback := func(err error) {
+		calls++
+		assert.EqualError(t, err, "send failed")
+	}
+	batch := NewBatchMessage([]*Message{
+		{Type: MessageTypeEvent, SendCallback: callback},
+		{Type: MessageTypeEvent},
+		{Type: MessageTypeEvent, SendCallback: callback},
+	})
+	assert.Equal(t, MessageTypeBatch, batch.Type)
+	batch.SendCallback(errors.New("send failed"))
+	assert.Equal(t, 2, calls)
+}
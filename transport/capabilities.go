@@ -0,0 +1,99 @@
+package transport
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+const (
+	// HeaderKeyCapabilities is the HTTP request and response header specifying
+	// the optional capabilities of the protocol supported by the peer, as a
+	// comma-delimited list. The capabilities used by a connection are the
+	// capabilities supported by both the agent and the backend, so the agents
+	// and the backends that don't support them keep using the basic protocol.
+	HeaderKeyCapabilities = "Sensu-Capabilities"
+
+	// CapabilityBatch is the capability of receiving batch messages.
+	CapabilityBatch = "batch"
+
+	// CapabilityDeflate is the capability of compressing the messages with
+	// the permessage-deflate websocket extension.
+	CapabilityDeflate = "deflate"
+
+	// CapabilityZstd is the capability of compressing the messages with zstd.
+	CapabilityZstd = "zstd"
+)
+
+// SupportedCapabilities are the capabilities supported by this version of the
+// transport.
+var SupportedCapabilities = Capabilities{
+	CapabilityBatch:   true,
+	CapabilityDeflate: true,
+	CapabilityZstd:    true,
+}
+
+// Capabilities is a set of optional capabilities of the protocol.
+type Capabilities map[string]bool
+
+// NewCapabilities returns the set of the given capabilities.
+func NewCapabilities(capabilities ...string) Capabilities {
+	c := make(Capabilities, len(capabilities))
+	for _, capability := range capabilities {
+		c[capability] = true
+	}
+	return c
+}
+
+// ParseCapabilities parses the capabilities of the capabilities header.
+func ParseCapabilities(header http.Header) Capabilities {
+	c := Capabilities{}
+	for _, value := range header.Values(HeaderKeyCapabilities) {
+		for _, capability := range strings.Split(value, ",") {
+			if capability = strings.ToLower(strings.TrimSpace(capability)); capability != "" {
+				c[capability] = true
+			}
+		}
+	}
+	return c
+}
+
+// NegotiateCapabilities returns the capabilities of the request header that
+// are supported, and sets them in the capabilities header of the response
+// header.
+func NegotiateCapabilities(requestHeader, responseHeader http.Header) Capabilities {
+	capabilities := ParseCapabilities(requestHeader).Intersect(SupportedCapabilities)
+	if len(capabilities) > 0 {
+		responseHeader.Set(HeaderKeyCapabilities, capabilities.String())
+	}
+	return capabilities
+}
+
+// Has returns true if the set contains the capability.
+func (c Capabilities) Has(capability string) bool {
+	return c[capability]
+}
+
+// Intersect returns the capabilities that are in both sets.
+func (c Capabilities) Intersect(other Capabilities) Capabilities {
+	result := Capabilities{}
+	for capability := range c {
+		if other.Has(capability) {
+			result[capability] = true
+		}
+	}
+	return result
+}
+
+// String returns the capabilities as a sorted, comma-delimited list, the
+// value of the capabilities header.
+func (c Capabilities) String() string {
+	capabilities := make([]string, 0, len(c))
+	for capability, ok := range c {
+		if ok {
+			capabilities = append(capabilities, capability)
+		}
+	}
+	sort.Strings(capabilities)
+	return strings.Join(capabilities, ",")
+}
@@ -0,0 +1,112 @@
+package transport
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCapabilities(t *testing.T) {
+	header := http.Header{}
+	header.Add(HeaderKeyCapabilities, "batch, ZSTD,")
+	header.Add(HeaderKeyCapabilities, "unknown")
+	capabilities := ParseCapabilities(header)
+	assert.Equal(t, NewCapabilities(CapabilityBatch, CapabilityZstd, "unknown"), capabilities)
+	assert.Equal(t, "batch,zstd", capabilities.Intersect(SupportedCapabilities).String())
+	assert.Empty(t, ParseCapabilities(http.Header{}))
+}
+
+func TestNegotiateCapabilities(t *testing.T) {
+	requestHeader := http.Header{}
+	requestHeader.Set(HeaderKeyCapabilities, "zstd,unknown")
+	responseHeader := http.Header{}
+	assert.Equal(t, NewCapabilities(CapabilityZstd), NegotiateCapabilities(requestHeader, responseHeader))
+	assert.Equal(t, "zstd", responseHeader.Get(HeaderKeyCapabilities))
+
+	// peers that don't request capabilities get no capabilities header
+	responseHeader = http.Header{}
+	assert.Empty(t, NegotiateCapabilities(http.Header{}, responseHeader))
+	assert.Empty(t, responseHeader.Get(HeaderKeyCapabilities))
+}
+
+func TestTransportCompression(t *testing.T) {
+	payload := bytes.Repeat([]byte("compressible "), 1000)
+	for _, capability := range []string{"", CapabilityDeflate, CapabilityZstd} {
+		t.Run(capability, func(t *testing.T) {
+			server := NewServer()
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				transport, err := server.Serve(w, r)
+				require.NoError(t, err)
+				msg, err := transport.Receive()
+				require.NoError(t, err)
+				// echo the message back to the client
+				require.NoError(t, transport.Send(msg))
+			}))
+			defer ts.Close()
+
+			header := http.Header{}
+			if capability != "" {
+				header.Set(HeaderKeyCapabilities, capability)
+			}
+			clientTransport, respHeader, err := Connect(strings.Replace(ts.URL, "http", "ws", 1), nil, header, 5)
+			require.NoError(t, err)
+			assert.Equal(t, capability, respHeader.Get(HeaderKeyCapabilities))
+			assert.Equal(t, capability != "", clientTransport.(*WebSocketTransport).Capabilities.Has(capability))
+
+			require.NoError(t, clientTransport.Send(NewMessage(MessageTypeEvent, payload)))
+			msg, err := clientTransport.Receive()
+			require.NoError(t, err)
+			assert.Equal(t, MessageTypeEvent, msg.Type)
+			assert.Equal(t, payload, msg.Payload)
+		})
+	}
+}
+
+func TestZstdCompression(t *testing.T) {
+	small := Encode(MessageTypeEvent, []byte("small"))
+	assert.Equal(t, small, compressZstd(small))
+
+	large := Encode(MessageTypeEvent, bytes.Repeat([]byte("large "), 1000))
+	compressed := compressZstd(large)
+	assert.Less(t, len(compressed), len(large))
+
+	decompressed, err := decompressZstd(compressed)
+	require.NoError(t, err)
+	assert.Equal(t, large, decompressed)
+
+	decompressed, err = decompressZstd(small)
+	require.NoError(t, err)
+	assert.Equal(t, small, decompressed)
+
+	_, err = decompressZstd(append(append([]byte{}, zstdMagic...), "garbage"...))
+	assert.Error(t, err)
+}
+
+func TestDeflateReadLimit(t *testing.T) {
+	server := NewServer()
+	received := make(chan error, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		transport, err := server.Serve(w, r)
+		require.NoError(t, err)
+		transport.(*WebSocketTransport).readLimit = 1024
+		_, err = transport.Receive()
+		received <- err
+	}))
+	defer ts.Close()
+
+	header := http.Header{}
+	header.Set(HeaderKeyCapabilities, CapabilityDeflate)
+	clientTransport, _, err := Connect(strings.Replace(ts.URL, "http", "ws", 1), nil, header, 5)
+	require.NoError(t, err)
+
+	// the message is much smaller than the limit once compressed
+	require.NoError(t, clientTransport.Send(NewMessage(MessageTypeEvent, bytes.Repeat([]byte("a"), 4096))))
+	err = <-received
+	assert.IsType(t, ConnectionError{}, err)
+	assert.Contains(t, err.Error(), "maximum size")
+}
@@ -30,6 +30,7 @@ func connect(wsServerURL string, tlsOpts *v2.TLSOptions, requestHeader http.Head
 	dialer := websocket.Dialer{
 		HandshakeTimeout:	time.Second * time.Duration(handshakeTimeout),
 		Proxy:			http.ProxyFromEnvironment,
+		EnableCompression:	ParseCapabilities(requestHeader).Has(CapabilityDeflate),
 	}
 
 	if tlsOpts != nil {
@@ -63,12 +64,15 @@ func connect(wsServerURL string, tlsOpts *v2.TLSOptions, requestHeader http.Head
 
 // Connect causes the transport Client to connect to a given websocket server.
 // Transport is a thin wrapper around a websocket connection that makes the
-// connection safe for concurrent use by multiple goroutines.
+// connection safe for concurrent use by multiple goroutines. The transport
+// uses the capabilities of the capabilities header of the request that the
+// server supports.
 func Connect(wsServerURL string, tlsOpts *v2.TLSOptions, requestHeader http.Header, handshakeTimeout int) (Transport, http.Header, error) {
 	conn, resp, err := connect(wsServerURL, tlsOpts, requestHeader, handshakeTimeout)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	return NewTransport(conn), resp, nil
+	capabilities := ParseCapabilities(requestHeader).Intersect(ParseCapabilities(resp))
+	return NewTransportWithCapabilities(conn, capabilities), resp, nil
 }
@@ -0,0 +1,49 @@
+package transport
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	// zstdCompressionThreshold is the minimum size of the messages compressed
+	// with zstd. Smaller messages are sent uncompressed.
+	zstdCompressionThreshold = 256
+
+	// maxDecompressedSize is the maximum size of a message decompressed with
+	// zstd or permessage-deflate.
+	maxDecompressedSize = 64 << 20
+)
+
+// zstdMagic is the magic number at the start of the zstd frames. It can't be
+// mistaken for the start of an uncompressed message, which starts with the
+// message type.
+var zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+var (
+	zstdEncoder, _ = zstd.NewWriter(nil)
+	zstdDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderMaxMemory(maxDecompressedSize))
+)
+
+// compressZstd compresses the message, unless it is too small to benefit from
+// compression.
+func compressZstd(msg []byte) []byte {
+	if len(msg) < zstdCompressionThreshold {
+		return msg
+	}
+	return zstdEncoder.EncodeAll(msg, make([]byte, 0, len(msg)/2))
+}
+
+// decompressZstd decompresses the message if it was compressed.
+func decompressZstd(msg []byte) ([]byte, error) {
+	if !bytes.HasPrefix(msg, zstdMagic) {
+		return msg, nil
+	}
+	decompressed, err := zstdDecoder.DecodeAll(msg, nil)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't decompress message: %s", err)
+	}
+	return decompressed, nil
+}
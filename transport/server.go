@@ -14,17 +14,20 @@ type Server struct {
 // NewServer is used to initialize a new Server and return a pointer to it.
 func NewServer() *Server {
 	return &Server{
-		upgrader: &websocket.Upgrader{},
+		upgrader: &websocket.Upgrader{EnableCompression: true},
 	}
 }
 
 // Serve is used to initialize a websocket connection and returns an pointer to
-// a Transport used to communicate with that client.
+// a Transport used to communicate with that client, with the capabilities
+// requested by the client that are supported.
 func (s *Server) Serve(w http.ResponseWriter, r *http.Request) (Transport, error) {
-	conn, err := s.upgrader.Upgrade(w, r, nil)
+	responseHeader := make(http.Header)
+	capabilities := NegotiateCapabilities(r.Header, responseHeader)
+	conn, err := s.upgrader.Upgrade(w, r, responseHeader)
 	if err != nil {
 		return nil, err
 	}
 
-	return NewTransport(conn), err
+	return NewTransportWithCapabilities(conn, capabilities), err
 }
@@ -5,6 +5,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -126,9 +128,17 @@ type Transport interface {
 // WebSocket.
 type WebSocketTransport struct {
 	Connection *websocket.Conn
-	closed     atomic.Value
-	readMu     sync.Mutex
-	writeMu    sync.Mutex
+
+	// Capabilities are the capabilities negotiated with the peer.
+	Capabilities Capabilities
+
+	closed  atomic.Value
+	readMu  sync.Mutex
+	writeMu sync.Mutex
+
+	// readLimit is the maximum size of the messages inflated with
+	// permessage-deflate, maxDecompressedSize if zero.
+	readLimit int64
 }
 
 // NewTransport creates an initialized Transport and return its pointer.
@@ -138,6 +148,15 @@ func NewTransport(conn *websocket.Conn) Transport {
 	}
 }
 
+// NewTransportWithCapabilities creates an initialized Transport that uses the
+// capabilities negotiated with the peer, and return its pointer.
+func NewTransportWithCapabilities(conn *websocket.Conn, capabilities Capabilities) Transport {
+	return &WebSocketTransport{
+		Connection:   conn,
+		Capabilities: capabilities,
+	}
+}
+
 // NewMessage creates a new Message.
 func NewMessage(msgType string, payload []byte) *Message {
 	return &Message{
@@ -234,7 +253,7 @@ func (t *WebSocketTransport) Receive() (*Message, error) {
 		return nil, ClosedError{"the websocket connection is no longer open"}
 	}
 
-	_, p, err := t.Connection.ReadMessage()
+	p, err := t.read()
 	if err != nil {
 		if websocket.IsCloseError(err, websocket.CloseGoingAway) {
 			t.closed.Store(true)
@@ -243,6 +262,12 @@ func (t *WebSocketTransport) Receive() (*Message, error) {
 		return nil, ConnectionError{err.Error()}
 	}
 
+	if t.Capabilities.Has(CapabilityZstd) {
+		if p, err = decompressZstd(p); err != nil {
+			return nil, err
+		}
+	}
+
 	msgType, payload, err := Decode(p)
 	if err != nil {
 		return nil, err
@@ -252,6 +277,32 @@ func (t *WebSocketTransport) Receive() (*Message, error) {
 	return msg, nil
 }
 
+// read reads the next message of the websocket connection. The size of the
+// messages inflated with permessage-deflate is limited, since a small
+// compressed message can be inflated to an arbitrary size.
+func (t *WebSocketTransport) read() ([]byte, error) {
+	if !t.Capabilities.Has(CapabilityDeflate) {
+		_, p, err := t.Connection.ReadMessage()
+		return p, err
+	}
+	limit := t.readLimit
+	if limit <= 0 {
+		limit = maxDecompressedSize
+	}
+	_, r, err := t.Connection.NextReader()
+	if err != nil {
+		return nil, err
+	}
+	p, err := ioutil.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(p)) > limit {
+		return nil, fmt.Errorf("message exceeds the maximum size of %d bytes once decompressed", limit)
+	}
+	return p, nil
+}
+
 // Send a message over the websocket connection. If the connection has been
 // closed, returns a ClosedError. Returns a ConnectionError if the websocket
 // connection returns an error while sending, but the connection is still open.
@@ -269,6 +320,9 @@ func (t *WebSocketTransport) Send(m *Message) (err error) {
 	}()
 
 	msg := Encode(m.Type, m.Payload)
+	if t.Capabilities.Has(CapabilityZstd) {
+		msg = compressZstd(msg)
+	}
 	if err := t.Connection.WriteMessage(websocket.BinaryMessage, msg); err != nil {
 		// If we get _any_ error, let's just considered the connection closed,
 		// because it's _really_ hard to figure out what errors from the